@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// ActivityHandler 运动记录处理器
+type ActivityHandler struct {
+	activityService service.ActivityService
+}
+
+// NewActivityHandler 创建运动记录处理器
+func NewActivityHandler(activityService service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+// CreateActivity 手动记录活动
+// @Summary 手动记录活动
+// @Description 记录一次散步、跑步等活动,需要宠物编辑权限;配速由距离和时长计算,热量按最近一次体重估算
+// @Tags 运动记录
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.SaveActivityRequest true "活动信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/activities [post]
+func (h *ActivityHandler) CreateActivity(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveActivityRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "记录活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	activity, err := h.activityService.CreateActivity(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    activity,
+	})
+}
+
+// ImportActivity 导入轨迹
+// @Summary 导入轨迹
+// @Description multipart上传GPX或GeoJSON轨迹文件(不超过5MB)创建活动,距离和配速由服务端计算;轨迹不含时间时需填写开始时间和时长
+// @Tags 运动记录
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param file formData file true "轨迹文件"
+// @Param type formData string false "类型,默认walk"
+// @Param title formData string false "标题"
+// @Param notes formData string false "备注"
+// @Param started_at formData string false "开始时间RFC3339"
+// @Param duration_sec formData int false "时长(秒)"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/activities/import [post]
+func (h *ActivityHandler) ImportActivity(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ImportActivityRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "导入轨迹参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"code":    400,
+			"message": "请选择要导入的轨迹文件",
+		})
+		return
+	}
+
+	activity, err := h.activityService.ImportActivity(ctx, middleware.GetUserID(c), petID, &req, header)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "导入成功",
+		"data":    activity,
+	})
+}
+
+// ListActivities 活动列表
+// @Summary 活动列表
+// @Description 宠物的活动记录,按开始时间倒序
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param type query string false "类型"
+// @Param from query string false "开始时间RFC3339"
+// @Param to query string false "结束时间RFC3339"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/activities [get]
+func (h *ActivityHandler) ListActivities(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListActivityRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取活动列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	activities, total, err := h.activityService.ListActivities(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      activities,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetActivity 活动详情
+// @Summary 活动详情
+// @Description 获取活动详情,宠物成员可见
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/activities/{id} [get]
+func (h *ActivityHandler) GetActivity(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	activity, err := h.activityService.GetActivity(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    activity,
+	})
+}
+
+// UpdateActivity 修改活动
+// @Summary 修改活动
+// @Description 修改活动,需要宠物编辑权限;导入轨迹的活动距离以轨迹为准,不可修改
+// @Tags 运动记录
+// @Accept json
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param request body model.SaveActivityRequest true "活动信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/activities/{id} [put]
+func (h *ActivityHandler) UpdateActivity(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveActivityRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	activity, err := h.activityService.UpdateActivity(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    activity,
+	})
+}
+
+// DeleteActivity 删除活动
+// @Summary 删除活动
+// @Description 删除活动及其轨迹,需要宠物编辑权限
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/activities/{id} [delete]
+func (h *ActivityHandler) DeleteActivity(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	if err := h.activityService.DeleteActivity(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// GetTrack 活动轨迹
+// @Summary 活动轨迹
+// @Description 以GeoJSON Feature(MultiLineString)返回导入的轨迹,包含bbox和各点时间
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/activities/{id}/track [get]
+func (h *ActivityHandler) GetTrack(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	feature, err := h.activityService.GetTrack(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    feature,
+	})
+}
+
+// Stats 活动统计
+// @Summary 活动统计
+// @Description 按周(周一起)或按月汇总次数、时长、距离、热量和平均配速,默认最近12个周期,无活动的周期补零
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param period query string false "周期:week,month"
+// @Param type query string false "类型"
+// @Param from query string false "开始时间RFC3339"
+// @Param to query string false "结束时间RFC3339"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/activities/stats [get]
+func (h *ActivityHandler) Stats(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ActivityStatsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取活动统计参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	stats, err := h.activityService.Stats(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    stats,
+	})
+}
+
+// SaveGoal 设置运动目标
+// @Summary 设置运动目标
+// @Description 设置每周或每月的距离(米)、时长(秒)或次数目标,同一周期和指标已有目标时覆盖
+// @Tags 运动记录
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.SaveActivityGoalRequest true "目标"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/activity-goals [put]
+func (h *ActivityHandler) SaveGoal(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveActivityGoalRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "设置运动目标参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	goal, err := h.activityService.SaveGoal(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "保存成功",
+		"data":    goal,
+	})
+}
+
+// ListGoals 运动目标
+// @Summary 运动目标
+// @Description 宠物的运动目标及本周或本月的完成进度
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/activity-goals [get]
+func (h *ActivityHandler) ListGoals(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	goals, err := h.activityService.ListGoals(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    goals,
+	})
+}
+
+// DeleteGoal 删除运动目标
+// @Summary 删除运动目标
+// @Description 删除运动目标,需要宠物编辑权限
+// @Tags 运动记录
+// @Produce json
+// @Param id path int true "目标ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/activity-goals/{id} [delete]
+func (h *ActivityHandler) DeleteGoal(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "目标ID")
+	if !ok {
+		return
+	}
+
+	if err := h.activityService.DeleteGoal(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
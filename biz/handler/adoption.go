@@ -0,0 +1,434 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// AdoptionHandler 领养处理器
+type AdoptionHandler struct {
+	adoptionService service.AdoptionService
+}
+
+// NewAdoptionHandler 创建领养处理器
+func NewAdoptionHandler(adoptionService service.AdoptionService) *AdoptionHandler {
+	return &AdoptionHandler{adoptionService: adoptionService}
+}
+
+// CreateShelter 创建救助机构
+// @Summary 创建救助机构
+// @Description 创建救助机构,创建者成为机构管理员
+// @Tags 领养
+// @Accept json
+// @Produce json
+// @Param request body model.CreateShelterRequest true "创建救助机构请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shelters [post]
+func (h *AdoptionHandler) CreateShelter(ctx context.Context, c *app.RequestContext) {
+	var req model.CreateShelterRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建救助机构参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	shelter, err := h.adoptionService.CreateShelter(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    shelter,
+	})
+}
+
+// UpdateShelter 更新救助机构
+// @Summary 更新救助机构
+// @Description 机构管理员更新机构信息
+// @Tags 领养
+// @Accept json
+// @Produce json
+// @Param id path int true "机构ID"
+// @Param request body model.UpdateShelterRequest true "更新救助机构请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shelters/{id} [put]
+func (h *AdoptionHandler) UpdateShelter(ctx context.Context, c *app.RequestContext) {
+	shelterID, ok := parseIDParam(c, "id", "机构ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateShelterRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新救助机构参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	shelter, err := h.adoptionService.UpdateShelter(ctx, middleware.GetUserID(c), shelterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    shelter,
+	})
+}
+
+// GetShelter 获取救助机构详情
+// @Summary 获取救助机构详情
+// @Description 根据ID获取救助机构详情
+// @Tags 领养
+// @Produce json
+// @Param id path int true "机构ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shelters/{id} [get]
+func (h *AdoptionHandler) GetShelter(ctx context.Context, c *app.RequestContext) {
+	shelterID, ok := parseIDParam(c, "id", "机构ID")
+	if !ok {
+		return
+	}
+
+	shelter, err := h.adoptionService.GetShelter(ctx, shelterID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    shelter,
+	})
+}
+
+// CreateListing 发布领养信息
+// @Summary 发布领养信息
+// @Description 机构管理员发布领养信息并创建宠物档案
+// @Tags 领养
+// @Accept json
+// @Produce json
+// @Param id path int true "机构ID"
+// @Param request body model.CreateListingRequest true "发布领养信息请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shelters/{id}/listings [post]
+func (h *AdoptionHandler) CreateListing(ctx context.Context, c *app.RequestContext) {
+	shelterID, ok := parseIDParam(c, "id", "机构ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateListingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发布领养信息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	listing, err := h.adoptionService.CreateListing(ctx, middleware.GetUserID(c), shelterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "发布成功",
+		"data":    listing,
+	})
+}
+
+// UpdateListing 更新领养信息
+// @Summary 更新领养信息
+// @Description 机构管理员更新、关闭或重新开放领养信息;已有通过审核的申请(pending)或已领养时不可修改状态
+// @Tags 领养
+// @Accept json
+// @Produce json
+// @Param id path int true "领养信息ID"
+// @Param request body model.UpdateListingRequest true "更新领养信息请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/adoptions/{id} [put]
+func (h *AdoptionHandler) UpdateListing(ctx context.Context, c *app.RequestContext) {
+	listingID, ok := parseIDParam(c, "id", "领养信息ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateListingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新领养信息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	listing, err := h.adoptionService.UpdateListing(ctx, middleware.GetUserID(c), listingID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    listing,
+	})
+}
+
+// GetListing 获取领养信息详情
+// @Summary 获取领养信息详情
+// @Description 根据ID获取领养信息详情
+// @Tags 领养
+// @Produce json
+// @Param id path int true "领养信息ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/adoptions/{id} [get]
+func (h *AdoptionHandler) GetListing(ctx context.Context, c *app.RequestContext) {
+	listingID, ok := parseIDParam(c, "id", "领养信息ID")
+	if !ok {
+		return
+	}
+
+	listing, err := h.adoptionService.GetListing(ctx, listingID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    listing,
+	})
+}
+
+// SearchListings 检索领养信息
+// @Summary 检索领养信息
+// @Description 按物种、年龄、体型、城市检索可领养宠物
+// @Tags 领养
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param species query string false "物种"
+// @Param size query string false "体型"
+// @Param city query string false "城市"
+// @Param min_age_months query int false "最小月龄"
+// @Param max_age_months query int false "最大月龄"
+// @Param shelter_id query int false "机构ID"
+// @Param keyword query string false "关键词"
+// @Success 200 {object} utils.H
+// @Router /api/v1/adoptions [get]
+func (h *AdoptionHandler) SearchListings(ctx context.Context, c *app.RequestContext) {
+	var req model.ListListingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "检索领养信息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	listings, total, err := h.adoptionService.SearchListings(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      listings,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// SubmitApplication 提交领养申请
+// @Summary 提交领养申请
+// @Description 登录用户填写问卷提交领养申请
+// @Tags 领养
+// @Accept json
+// @Produce json
+// @Param id path int true "领养信息ID"
+// @Param request body model.SubmitApplicationRequest true "领养申请"
+// @Success 200 {object} utils.H
+// @Router /api/v1/adoptions/{id}/applications [post]
+func (h *AdoptionHandler) SubmitApplication(ctx context.Context, c *app.RequestContext) {
+	listingID, ok := parseIDParam(c, "id", "领养信息ID")
+	if !ok {
+		return
+	}
+
+	var req model.SubmitApplicationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "提交领养申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	application, err := h.adoptionService.SubmitApplication(ctx, middleware.GetUserID(c), listingID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "提交成功",
+		"data":    application,
+	})
+}
+
+// GetApplication 获取领养申请详情
+// @Summary 获取领养申请详情
+// @Description 申请人或机构管理员查看领养申请
+// @Tags 领养
+// @Produce json
+// @Param id path int true "申请ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/adoption-applications/{id} [get]
+func (h *AdoptionHandler) GetApplication(ctx context.Context, c *app.RequestContext) {
+	applicationID, ok := parseIDParam(c, "id", "申请ID")
+	if !ok {
+		return
+	}
+
+	application, err := h.adoptionService.GetApplication(ctx, middleware.GetUserID(c), applicationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    application,
+	})
+}
+
+// ListMyApplications 获取我的领养申请
+// @Summary 获取我的领养申请
+// @Description 获取当前用户提交的领养申请
+// @Tags 领养
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/adoption-applications [get]
+func (h *AdoptionHandler) ListMyApplications(ctx context.Context, c *app.RequestContext) {
+	var req model.ListApplicationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取领养申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	applications, total, err := h.adoptionService.ListMyApplications(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      applications,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListShelterApplications 获取机构收到的领养申请
+// @Summary 获取机构收到的领养申请
+// @Description 机构管理员查看收到的领养申请
+// @Tags 领养
+// @Produce json
+// @Param id path int true "机构ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shelters/{id}/applications [get]
+func (h *AdoptionHandler) ListShelterApplications(ctx context.Context, c *app.RequestContext) {
+	shelterID, ok := parseIDParam(c, "id", "机构ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListApplicationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取领养申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	applications, total, err := h.adoptionService.ListShelterApplications(ctx, middleware.GetUserID(c), shelterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      applications,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ReviewApplication 审核领养申请
+// @Summary 审核领养申请
+// @Description 机构管理员推进领养申请状态,完成时宠物档案转移给领养人
+// @Tags 领养
+// @Accept json
+// @Produce json
+// @Param id path int true "申请ID"
+// @Param request body model.ReviewApplicationRequest true "审核请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/adoption-applications/{id}/status [put]
+func (h *AdoptionHandler) ReviewApplication(ctx context.Context, c *app.RequestContext) {
+	applicationID, ok := parseIDParam(c, "id", "申请ID")
+	if !ok {
+		return
+	}
+
+	var req model.ReviewApplicationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "审核领养申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	application, err := h.adoptionService.ReviewApplication(ctx, middleware.GetUserID(c), applicationID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "操作成功",
+		"data":    application,
+	})
+}
@@ -0,0 +1,478 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// AppointmentHandler 诊所服务与门诊预约处理器
+type AppointmentHandler struct {
+	appointmentService service.AppointmentService
+}
+
+// NewAppointmentHandler 创建诊所服务与门诊预约处理器
+func NewAppointmentHandler(appointmentService service.AppointmentService) *AppointmentHandler {
+	return &AppointmentHandler{appointmentService: appointmentService}
+}
+
+// CreateService 创建诊所服务
+// @Summary 创建诊所服务
+// @Description 诊所管理员或前台创建可预约的服务,按宠物体型(small/medium/large)分别设置时长和价格,默认上架
+// @Tags 门诊预约
+// @Accept json
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param request body model.SaveClinicServiceRequest true "服务信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/services [post]
+func (h *AppointmentHandler) CreateService(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveClinicServiceRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建诊所服务参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	item, err := h.appointmentService.CreateService(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    item,
+	})
+}
+
+// UpdateService 修改诊所服务
+// @Summary 修改诊所服务
+// @Description 修改服务信息,prices整体覆盖;已有预约按下单时的价格快照,不受影响
+// @Tags 门诊预约
+// @Accept json
+// @Produce json
+// @Param id path int true "服务ID"
+// @Param request body model.SaveClinicServiceRequest true "服务信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinic-services/{id} [put]
+func (h *AppointmentHandler) UpdateService(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "服务ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveClinicServiceRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改诊所服务参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	item, err := h.appointmentService.UpdateService(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    item,
+	})
+}
+
+// DeleteService 删除诊所服务
+// @Summary 删除诊所服务
+// @Description 删除服务,已有预约不受影响
+// @Tags 门诊预约
+// @Produce json
+// @Param id path int true "服务ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinic-services/{id} [delete]
+func (h *AppointmentHandler) DeleteService(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "服务ID")
+	if !ok {
+		return
+	}
+
+	if err := h.appointmentService.DeleteService(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// ListServices 诊所服务目录
+// @Summary 诊所服务目录
+// @Description 公开接口,诊所上架的服务及各体型的时长和价格
+// @Tags 门诊预约
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param category query string false "类别:grooming,bath,nail_trim,dental,checkup,other"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/services [get]
+func (h *AppointmentHandler) ListServices(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListClinicServiceRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取诊所服务参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	items, err := h.appointmentService.ListServices(ctx, clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    items,
+	})
+}
+
+// ListAllServices 诊所全部服务
+// @Summary 诊所全部服务
+// @Description 诊所人员查看全部服务,含已下架的服务
+// @Tags 门诊预约
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param category query string false "类别"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/services/all [get]
+func (h *AppointmentHandler) ListAllServices(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListClinicServiceRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取诊所服务参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	items, err := h.appointmentService.ListAllServices(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    items,
+	})
+}
+
+// Quote 预约报价
+// @Summary 预约报价
+// @Description 按宠物体型汇总所选服务的时长与价格;宠物未登记体型时需传pet_size;传入start_at时同时返回该时段是否可约
+// @Tags 门诊预约
+// @Accept json
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param request body model.AppointmentQuoteRequest true "报价请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/appointments/quote [post]
+func (h *AppointmentHandler) Quote(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.AppointmentQuoteRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "预约报价参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	quote, err := h.appointmentService.Quote(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    quote,
+	})
+}
+
+// Availability 可约时段
+// @Summary 可约时段
+// @Description 按所选服务的总时长返回某天可约的开始时间,已排除约满、营业时间外和提前量不足的时段
+// @Tags 门诊预约
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param date query string true "日期YYYY-MM-DD"
+// @Param pet_id query int true "宠物ID"
+// @Param pet_size query string false "体型,宠物未登记体型时必填"
+// @Param service_ids query []int true "服务ID,可重复传入" collectionFormat(multi)
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/availability [get]
+func (h *AppointmentHandler) Availability(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.AppointmentAvailabilityRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取可约时段参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	availability, err := h.appointmentService.Availability(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    availability,
+	})
+}
+
+// Book 预约
+// @Summary 预约
+// @Description 预约诊所服务,需要宠物编辑权限;quoted_price须与当前报价一致,否则需重新报价;预约提交后等待诊所确认
+// @Tags 门诊预约
+// @Accept json
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param request body model.CreateAppointmentRequest true "预约信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/appointments [post]
+func (h *AppointmentHandler) Book(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateAppointmentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	appointment, err := h.appointmentService.Book(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "预约申请已提交",
+		"data":    appointment,
+	})
+}
+
+// GetAppointment 预约详情
+// @Summary 预约详情
+// @Description 预约人和诊所人员可查看
+// @Tags 门诊预约
+// @Produce json
+// @Param id path int true "预约ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/appointments/{id} [get]
+func (h *AppointmentHandler) GetAppointment(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	appointment, err := h.appointmentService.Get(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    appointment,
+	})
+}
+
+// ListMyAppointments 我的预约
+// @Summary 我的预约
+// @Description 当前用户的门诊预约,按开始时间倒序
+// @Tags 门诊预约
+// @Produce json
+// @Param status query string false "状态"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/appointments [get]
+func (h *AppointmentHandler) ListMyAppointments(ctx context.Context, c *app.RequestContext) {
+	var req model.ListAppointmentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取预约列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	appointments, total, err := h.appointmentService.ListMine(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      appointments,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// CancelAppointment 取消预约
+// @Summary 取消预约
+// @Description 预约人在预约开始前取消待确认或已确认的预约
+// @Tags 门诊预约
+// @Accept json
+// @Produce json
+// @Param id path int true "预约ID"
+// @Param request body model.CancelAppointmentRequest false "取消原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/appointments/{id}/cancel [put]
+func (h *AppointmentHandler) CancelAppointment(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	var req model.CancelAppointmentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "取消预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	appointment, err := h.appointmentService.Cancel(ctx, middleware.GetUserID(c), id, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消",
+		"data":    appointment,
+	})
+}
+
+// ListClinicAppointments 诊所预约列表
+// @Summary 诊所预约列表
+// @Description 诊所人员查看诊所的预约,可按状态和日期筛选
+// @Tags 门诊预约
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param status query string false "状态"
+// @Param date query string false "日期YYYY-MM-DD"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/appointments [get]
+func (h *AppointmentHandler) ListClinicAppointments(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListAppointmentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取诊所预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	appointments, total, err := h.appointmentService.ListClinic(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      appointments,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// UpdateAppointmentStatus 变更预约状态
+// @Summary 变更预约状态
+// @Description 诊所人员确认、拒绝或取消预约,预约开始后可标记完成或爽约;完成时记录接诊兽医(vet_id,不填且操作人为兽医时取操作人),完成后预约人获得积分并可评价诊所和接诊兽医
+// @Tags 门诊预约
+// @Accept json
+// @Produce json
+// @Param id path int true "预约ID"
+// @Param request body model.UpdateAppointmentStatusRequest true "目标状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/appointments/{id}/status [put]
+func (h *AppointmentHandler) UpdateAppointmentStatus(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateAppointmentStatusRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "变更预约状态参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	appointment, err := h.appointmentService.UpdateStatus(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    appointment,
+	})
+}
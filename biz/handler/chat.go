@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+	"pet-service/pkg/websocket"
+)
+
+// ChatHandler 主人与诊所会话处理器
+type ChatHandler struct {
+	chatService service.ChatService
+}
+
+// NewChatHandler 创建会话处理器
+func NewChatHandler(chatService service.ChatService) *ChatHandler {
+	return &ChatHandler{chatService: chatService}
+}
+
+// Connect 建立实时连接
+// @Summary 建立实时连接
+// @Description WebSocket连接,使用与其他接口相同的JWT认证,浏览器无法设置请求头时可通过token查询参数传递。
+// @Description 服务端推送事件:message新消息,read已读回执,ack指令结果,error指令错误,pong心跳响应。
+// @Description 客户端指令:{"type":"send","conversation_id":1,"content":"..."}、{"type":"read","conversation_id":1,"message_id":10}、{"type":"ping"},可携带request_id用于匹配ack
+// @Tags 诊所咨询
+// @Param token query string false "JWT token"
+// @Success 101
+// @Router /api/v1/ws/chat [get]
+func (h *ChatHandler) Connect(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	err := websocket.Upgrade(c, func(conn *websocket.Conn) {
+		h.chatService.Serve(ctx, userID, conn)
+	})
+	if err != nil {
+		logger.Warn(ctx, "WebSocket握手失败", logger.ErrorField(err))
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"code":    400,
+			"message": "需要WebSocket握手请求",
+		})
+	}
+}
+
+// StartConversation 向诊所发起咨询
+// @Summary 向诊所发起咨询
+// @Description 主人向诊所发送第一条消息,与该诊所已有会话时沿用原会话
+// @Tags 诊所咨询
+// @Accept json
+// @Produce json
+// @Param request body model.StartConversationRequest true "诊所、宠物与消息内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/conversations [post]
+func (h *ChatHandler) StartConversation(ctx context.Context, c *app.RequestContext) {
+	var req model.StartConversationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发起咨询参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	conversation, err := h.chatService.StartConversation(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "发送成功",
+		"data":    conversation,
+	})
+}
+
+// ListMyConversations 我的咨询会话
+// @Summary 我的咨询会话
+// @Description 作为主人的会话列表,按最后消息时间倒序,owner_unread为未读数
+// @Tags 诊所咨询
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/conversations [get]
+func (h *ChatHandler) ListMyConversations(ctx context.Context, c *app.RequestContext) {
+	var req model.ListConversationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取会话列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	conversations, total, err := h.chatService.ListMyConversations(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      conversations,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListClinicConversations 诊所咨询会话
+// @Summary 诊所咨询会话
+// @Description 诊所人员查看本诊所收到的会话,clinic_unread为诊所未读数
+// @Tags 诊所咨询
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/conversations [get]
+func (h *ChatHandler) ListClinicConversations(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListConversationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取诊所会话参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	conversations, total, err := h.chatService.ListClinicConversations(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      conversations,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetConversation 会话详情
+// @Summary 会话详情
+// @Description 获取会话及双方已读位置
+// @Tags 诊所咨询
+// @Produce json
+// @Param id path int true "会话ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/conversations/{id} [get]
+func (h *ChatHandler) GetConversation(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "会话ID")
+	if !ok {
+		return
+	}
+
+	conversation, err := h.chatService.GetConversation(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    conversation,
+	})
+}
+
+// ListMessages 历史消息
+// @Summary 历史消息
+// @Description 按消息ID从新到旧翻页,下一页传入返回的next_before_id
+// @Tags 诊所咨询
+// @Produce json
+// @Param id path int true "会话ID"
+// @Param before_id query int false "获取该消息ID之前的消息"
+// @Param limit query int false "每页数量,默认20"
+// @Success 200 {object} utils.H
+// @Router /api/v1/conversations/{id}/messages [get]
+func (h *ChatHandler) ListMessages(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "会话ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListChatMessageRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取历史消息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.chatService.ListMessages(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// SendMessage 发送消息
+// @Summary 发送消息
+// @Description 主人或诊所人员在会话中发送文字或图片消息,在线的对方通过WebSocket实时收到
+// @Tags 诊所咨询
+// @Accept json
+// @Produce json
+// @Param id path int true "会话ID"
+// @Param request body model.SendChatMessageRequest true "消息内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/conversations/{id}/messages [post]
+func (h *ChatHandler) SendMessage(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "会话ID")
+	if !ok {
+		return
+	}
+
+	var req model.SendChatMessageRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发送消息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	message, err := h.chatService.SendMessage(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "发送成功",
+		"data":    message,
+	})
+}
+
+// MarkRead 标记已读
+// @Summary 标记已读
+// @Description 将当前用户所在一方的已读位置推进到指定消息,并向对方推送已读回执
+// @Tags 诊所咨询
+// @Accept json
+// @Produce json
+// @Param id path int true "会话ID"
+// @Param request body model.MarkReadRequest false "已读到的消息ID,为空时标记到最新消息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/conversations/{id}/read [put]
+func (h *ChatHandler) MarkRead(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "会话ID")
+	if !ok {
+		return
+	}
+
+	var req model.MarkReadRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "标记已读参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.chatService.MarkRead(ctx, middleware.GetUserID(c), id, req.MessageID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已读",
+	})
+}
+
+// GetUnread 未读消息数
+// @Summary 未读消息数
+// @Description 分别统计作为主人和作为诊所人员的未读消息数
+// @Tags 诊所咨询
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/conversations/unread [get]
+func (h *ChatHandler) GetUnread(ctx context.Context, c *app.RequestContext) {
+	unread, err := h.chatService.GetUnread(ctx, middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    unread,
+	})
+}
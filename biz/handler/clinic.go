@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// ClinicHandler 诊所处理器
+type ClinicHandler struct {
+	clinicService service.ClinicService
+}
+
+// NewClinicHandler 创建诊所处理器
+func NewClinicHandler(clinicService service.ClinicService) *ClinicHandler {
+	return &ClinicHandler{clinicService: clinicService}
+}
+
+// ListClinics 诊所列表
+// @Summary 诊所列表
+// @Description 按城市或关键字检索营业中的诊所
+// @Tags 诊所
+// @Produce json
+// @Param city query string false "城市"
+// @Param keyword query string false "诊所名称或地址"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics [get]
+func (h *ClinicHandler) ListClinics(ctx context.Context, c *app.RequestContext) {
+	var req model.ListClinicRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取诊所列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	clinics, total, err := h.clinicService.ListClinics(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      clinics,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetClinic 诊所详情
+// @Summary 诊所详情
+// @Description 获取诊所信息
+// @Tags 诊所
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id} [get]
+func (h *ClinicHandler) GetClinic(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	clinic, err := h.clinicService.GetClinic(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    clinic,
+	})
+}
+
+// CreateClinic 创建诊所
+// @Summary 创建诊所
+// @Description 平台管理员入驻诊所并指定诊所管理员
+// @Tags 诊所管理
+// @Accept json
+// @Produce json
+// @Param request body model.SaveClinicRequest true "诊所信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/clinics [post]
+func (h *ClinicHandler) CreateClinic(ctx context.Context, c *app.RequestContext) {
+	var req model.SaveClinicRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建诊所参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	clinic, err := h.clinicService.CreateClinic(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    clinic,
+	})
+}
+
+// UpdateClinic 更新诊所
+// @Summary 更新诊所
+// @Description 诊所管理员修改诊所信息或停业
+// @Tags 诊所
+// @Accept json
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param request body model.SaveClinicRequest true "诊所信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id} [put]
+func (h *ClinicHandler) UpdateClinic(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveClinicRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新诊所参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	clinic, err := h.clinicService.UpdateClinic(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    clinic,
+	})
+}
+
+// ListStaff 诊所人员列表
+// @Summary 诊所人员列表
+// @Description 诊所人员查看本诊所的全部人员
+// @Tags 诊所
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/staff [get]
+func (h *ClinicHandler) ListStaff(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	staff, err := h.clinicService.ListStaff(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    staff,
+	})
+}
+
+// SaveStaff 添加或修改诊所人员
+// @Summary 添加或修改诊所人员
+// @Description 诊所管理员添加人员或修改人员角色,诊所至少保留一名管理员
+// @Tags 诊所
+// @Accept json
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param request body model.SaveClinicStaffRequest true "人员与角色"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/staff [put]
+func (h *ClinicHandler) SaveStaff(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveClinicStaffRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "保存诊所人员参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	staff, err := h.clinicService.SaveStaff(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "保存成功",
+		"data":    staff,
+	})
+}
+
+// RemoveStaff 移除诊所人员
+// @Summary 移除诊所人员
+// @Description 诊所管理员移除人员
+// @Tags 诊所
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param uid path int true "用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/staff/{uid} [delete]
+func (h *ClinicHandler) RemoveStaff(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+	staffUserID, ok := parseIDParam(c, "uid", "用户ID")
+	if !ok {
+		return
+	}
+
+	if err := h.clinicService.RemoveStaff(ctx, middleware.GetUserID(c), id, staffUserID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "移除成功",
+	})
+}
+
+// ListMyClinics 我任职的诊所
+// @Summary 我任职的诊所
+// @Description 获取当前用户任职的诊所及角色
+// @Tags 诊所
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/clinics [get]
+func (h *ClinicHandler) ListMyClinics(ctx context.Context, c *app.RequestContext) {
+	staff, err := h.clinicService.ListMyClinics(ctx, middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    staff,
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/service"
+)
+
+// parseIDParam 解析路径中的ID参数,解析失败时直接写入400响应
+func parseIDParam(c *app.RequestContext, name, label string) (uint, bool) {
+	idStr := c.Param(name)
+	if idStr == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"code":    400,
+			"message": label + "不能为空",
+		})
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"code":    400,
+			"message": label + "格式错误",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondBindError 请求参数绑定失败响应
+func respondBindError(c *app.RequestContext, err error) {
+	c.JSON(consts.StatusBadRequest, utils.H{
+		"code":    400,
+		"message": "参数错误",
+		"error":   err.Error(),
+	})
+}
+
+// respondError 根据业务错误类型返回对应的状态码
+func respondError(c *app.RequestContext, err error) {
+	status := consts.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		status = consts.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		status = consts.StatusNotFound
+	case errors.Is(err, service.ErrInvalidParam):
+		status = consts.StatusBadRequest
+	}
+	c.JSON(status, utils.H{
+		"code":    status,
+		"message": err.Error(),
+	})
+}
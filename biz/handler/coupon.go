@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// CouponHandler 优惠券与积分处理器
+type CouponHandler struct {
+	couponService service.CouponService
+	pointsService service.PointsService
+}
+
+// NewCouponHandler 创建优惠券与积分处理器
+func NewCouponHandler(couponService service.CouponService, pointsService service.PointsService) *CouponHandler {
+	return &CouponHandler{
+		couponService: couponService,
+		pointsService: pointsService,
+	}
+}
+
+// CheckCoupon 试算优惠券
+// @Summary 试算优惠券
+// @Description 下单前校验券码对订单类型和金额是否可用,返回优惠金额或不可用原因
+// @Tags 优惠券与积分
+// @Accept json
+// @Produce json
+// @Param request body model.CheckCouponRequest true "券码与订单金额(分)"
+// @Success 200 {object} utils.H
+// @Router /api/v1/coupons/check [post]
+func (h *CouponHandler) CheckCoupon(ctx context.Context, c *app.RequestContext) {
+	var req model.CheckCouponRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "试算优惠券参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	quote, err := h.couponService.CheckCoupon(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    quote,
+	})
+}
+
+// GetMyPoints 我的积分
+// @Summary 我的积分
+// @Description 获取积分余额和变动流水,下单时1积分抵扣1分钱,最多抵扣订单金额的50%
+// @Tags 优惠券与积分
+// @Produce json
+// @Param type query string false "流水类型:earn_order,earn_appointment,redeem,return,revoke"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/points [get]
+func (h *CouponHandler) GetMyPoints(ctx context.Context, c *app.RequestContext) {
+	var req model.ListPointsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取积分参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	summary, err := h.pointsService.GetMyPoints(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    summary,
+	})
+}
+
+// ListCoupons 优惠券列表
+// @Summary 优惠券列表
+// @Description 管理端检索优惠券
+// @Tags 优惠券管理
+// @Produce json
+// @Param status query string false "状态:active,disabled"
+// @Param keyword query string false "券码或名称"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/coupons [get]
+func (h *CouponHandler) ListCoupons(ctx context.Context, c *app.RequestContext) {
+	var req model.ListCouponRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取优惠券列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	coupons, total, err := h.couponService.ListCoupons(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      coupons,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetCoupon 优惠券详情
+// @Summary 优惠券详情
+// @Description 获取优惠券及已使用次数
+// @Tags 优惠券管理
+// @Produce json
+// @Param id path int true "优惠券ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/coupons/{id} [get]
+func (h *CouponHandler) GetCoupon(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "优惠券ID")
+	if !ok {
+		return
+	}
+
+	coupon, err := h.couponService.GetCoupon(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    coupon,
+	})
+}
+
+// CreateCoupon 创建优惠券
+// @Summary 创建优惠券
+// @Description 创建折扣券或立减券,可设置最低消费、有效期、适用订单类型、总次数和每人次数
+// @Tags 优惠券管理
+// @Accept json
+// @Produce json
+// @Param request body model.SaveCouponRequest true "优惠券"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/coupons [post]
+func (h *CouponHandler) CreateCoupon(ctx context.Context, c *app.RequestContext) {
+	var req model.SaveCouponRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建优惠券参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	coupon, err := h.couponService.CreateCoupon(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    coupon,
+	})
+}
+
+// UpdateCoupon 更新优惠券
+// @Summary 更新优惠券
+// @Description 修改优惠券规则或停用,已使用次数不受影响
+// @Tags 优惠券管理
+// @Accept json
+// @Produce json
+// @Param id path int true "优惠券ID"
+// @Param request body model.SaveCouponRequest true "优惠券"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/coupons/{id} [put]
+func (h *CouponHandler) UpdateCoupon(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "优惠券ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveCouponRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新优惠券参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	coupon, err := h.couponService.UpdateCoupon(ctx, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    coupon,
+	})
+}
@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// DietHandler 饮食计划处理器
+type DietHandler struct {
+	dietService service.DietService
+}
+
+// NewDietHandler 创建饮食计划处理器
+func NewDietHandler(dietService service.DietService) *DietHandler {
+	return &DietHandler{dietService: dietService}
+}
+
+// CreatePlan 创建饮食计划
+// @Summary 创建饮食计划
+// @Description 创建饮食计划,需要宠物编辑权限;进行中的上一个计划自动结束于新计划开始的前一天,返回按最近体重计算的RER/MER
+// @Tags 饮食计划
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.SaveDietPlanRequest true "饮食计划"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/diet-plans [post]
+func (h *DietHandler) CreatePlan(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveDietPlanRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建饮食计划参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plan, err := h.dietService.CreatePlan(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    plan,
+	})
+}
+
+// ListPlans 饮食计划列表
+// @Summary 饮食计划列表
+// @Description 宠物的饮食计划,按开始日期倒序
+// @Tags 饮食计划
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/diet-plans [get]
+func (h *DietHandler) ListPlans(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListDietPlanRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取饮食计划参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plans, total, err := h.dietService.ListPlans(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      plans,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetPlan 饮食计划详情
+// @Summary 饮食计划详情
+// @Description 获取饮食计划及每日能量需求
+// @Tags 饮食计划
+// @Produce json
+// @Param id path int true "饮食计划ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/diet-plans/{id} [get]
+func (h *DietHandler) GetPlan(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "饮食计划ID")
+	if !ok {
+		return
+	}
+
+	plan, err := h.dietService.GetPlan(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    plan,
+	})
+}
+
+// UpdatePlan 修改饮食计划
+// @Summary 修改饮食计划
+// @Description 修改饮食计划,需要宠物编辑权限;开始日期不可修改
+// @Tags 饮食计划
+// @Accept json
+// @Produce json
+// @Param id path int true "饮食计划ID"
+// @Param request body model.SaveDietPlanRequest true "饮食计划"
+// @Success 200 {object} utils.H
+// @Router /api/v1/diet-plans/{id} [put]
+func (h *DietHandler) UpdatePlan(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "饮食计划ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveDietPlanRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改饮食计划参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plan, err := h.dietService.UpdatePlan(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    plan,
+	})
+}
+
+// DeletePlan 删除饮食计划
+// @Summary 删除饮食计划
+// @Description 删除饮食计划,已有的喂食记录保留
+// @Tags 饮食计划
+// @Produce json
+// @Param id path int true "饮食计划ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/diet-plans/{id} [delete]
+func (h *DietHandler) DeletePlan(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "饮食计划ID")
+	if !ok {
+		return
+	}
+
+	if err := h.dietService.DeletePlan(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// CreateLog 记录喂食
+// @Summary 记录喂食
+// @Description 记录一次喂食,自动关联当天生效的饮食计划;未填写热量时按计划食物的代谢能计算
+// @Tags 饮食计划
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.CreateFeedingLogRequest true "喂食记录"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/feeding-logs [post]
+func (h *DietHandler) CreateLog(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateFeedingLogRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "记录喂食参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	log, err := h.dietService.CreateLog(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    log,
+	})
+}
+
+// ListLogs 喂食记录列表
+// @Summary 喂食记录列表
+// @Description 宠物的喂食记录,按喂食时间倒序
+// @Tags 饮食计划
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param from query string false "开始日期YYYY-MM-DD"
+// @Param to query string false "结束日期YYYY-MM-DD(包含)"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/feeding-logs [get]
+func (h *DietHandler) ListLogs(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListFeedingLogRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取喂食记录参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	logs, total, err := h.dietService.ListLogs(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      logs,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// DeleteLog 删除喂食记录
+// @Summary 删除喂食记录
+// @Description 删除喂食记录,需要宠物编辑权限
+// @Tags 饮食计划
+// @Produce json
+// @Param id path int true "喂食记录ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/feeding-logs/{id} [delete]
+func (h *DietHandler) DeleteLog(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "喂食记录ID")
+	if !ok {
+		return
+	}
+
+	if err := h.dietService.DeleteLog(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// DailySummary 每日饮食汇总
+// @Summary 每日饮食汇总
+// @Description 汇总某天的喂食热量和份量,与当天饮食计划的目标热量对比;摄入在目标±10%以内为on_track
+// @Tags 饮食计划
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param date query string false "日期YYYY-MM-DD,默认今天"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/diet/summary [get]
+func (h *DietHandler) DailySummary(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.DietSummaryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取饮食汇总参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	summary, err := h.dietService.DailySummary(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    summary,
+	})
+}
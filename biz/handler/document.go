@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/middleware"
+)
+
+// DocumentHandler PDF文档处理器
+type DocumentHandler struct {
+	documentService service.DocumentService
+}
+
+// NewDocumentHandler 创建PDF文档处理器
+func NewDocumentHandler(documentService service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{documentService: documentService}
+}
+
+// VaccinationCertificate 下载疫苗接种证明
+// @Summary 下载疫苗接种证明
+// @Description 根据疫苗接种类型的就诊记录生成PDF证明,文档底部印有验证码和核验二维码;内容未变化时重复下载验证码不变
+// @Tags PDF文档
+// @Produce application/pdf
+// @Param id path int true "宠物ID"
+// @Success 200 {file} file
+// @Router /api/v1/pets/{id}/vaccination-certificate [get]
+func (h *DocumentHandler) VaccinationCertificate(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	data, doc, err := h.documentService.VaccinationCertificate(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("vaccination-certificate-%d.pdf", petID), data, doc)
+}
+
+// OrderInvoice 下载订单发票
+// @Summary 下载订单发票
+// @Description 生成已支付订单的PDF发票,包含明细、优惠与退款金额,底部印有验证码和核验二维码
+// @Tags PDF文档
+// @Produce application/pdf
+// @Param id path int true "订单ID"
+// @Success 200 {file} file
+// @Router /api/v1/orders/{id}/invoice [get]
+func (h *DocumentHandler) OrderInvoice(ctx context.Context, c *app.RequestContext) {
+	orderID, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	data, doc, err := h.documentService.OrderInvoice(ctx, middleware.GetUserID(c), orderID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("invoice-%d.pdf", orderID), data, doc)
+}
+
+// AppointmentInvoice 下载门诊预约发票
+// @Summary 下载门诊预约发票
+// @Description 生成已完成门诊预约的PDF发票,明细为预约时的服务快照,预约人和诊所人员均可下载
+// @Tags PDF文档
+// @Produce application/pdf
+// @Param id path int true "预约ID"
+// @Success 200 {file} file
+// @Router /api/v1/appointments/{id}/invoice [get]
+func (h *DocumentHandler) AppointmentInvoice(ctx context.Context, c *app.RequestContext) {
+	appointmentID, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	data, doc, err := h.documentService.AppointmentInvoice(ctx, middleware.GetUserID(c), appointmentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("appointment-invoice-%d.pdf", appointmentID), data, doc)
+}
+
+// DonationReceipt 下载捐款收据
+// @Summary 下载捐款收据
+// @Description 生成已到账捐款的PDF收据,仅捐款人本人可下载;核验结果不包含捐款人信息
+// @Tags PDF文档
+// @Produce application/pdf
+// @Param id path int true "捐款ID"
+// @Success 200 {file} file
+// @Router /api/v1/donations/{id}/receipt [get]
+func (h *DocumentHandler) DonationReceipt(ctx context.Context, c *app.RequestContext) {
+	donationID, ok := parseIDParam(c, "id", "捐款ID")
+	if !ok {
+		return
+	}
+
+	data, doc, err := h.documentService.DonationReceipt(ctx, middleware.GetUserID(c), donationID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	writePDF(c, fmt.Sprintf("donation-receipt-%d.pdf", donationID), data, doc)
+}
+
+// Verify 核验文档
+// @Summary 核验文档
+// @Description 公开接口,根据PDF上的验证码(不区分大小写,可含分隔符)核验文档真伪并返回签发时的内容摘要;superseded表示之后签发过内容不同的新文档
+// @Tags PDF文档
+// @Produce json
+// @Param code path string true "验证码"
+// @Success 200 {object} utils.H
+// @Router /api/v1/documents/verify/{code} [get]
+func (h *DocumentHandler) Verify(ctx context.Context, c *app.RequestContext) {
+	result, err := h.documentService.Verify(ctx, c.Param("code"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	message := "文档真实有效"
+	if !result.Valid {
+		message = "未找到该验证码对应的文档"
+	}
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": message,
+		"data":    result,
+	})
+}
+
+// writePDF 以附件形式返回PDF,并通过响应头返回验证码
+func writePDF(c *app.RequestContext, filename string, data []byte, doc *model.IssuedDocument) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("X-Verification-Code", doc.Code)
+	c.Data(consts.StatusOK, "application/pdf", data)
+}
@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// DonationHandler 募捐活动与捐款处理器
+type DonationHandler struct {
+	donationService service.DonationService
+}
+
+// NewDonationHandler 创建募捐活动与捐款处理器
+func NewDonationHandler(donationService service.DonationService) *DonationHandler {
+	return &DonationHandler{donationService: donationService}
+}
+
+// CreateCampaign 创建募捐活动
+// @Summary 创建募捐活动
+// @Description 救助机构管理员发起募捐活动,金额单位为分,截止时间最长一年
+// @Tags 募捐
+// @Accept json
+// @Produce json
+// @Param id path int true "救助机构ID"
+// @Param request body model.SaveCampaignRequest true "活动信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shelters/{id}/campaigns [post]
+func (h *DonationHandler) CreateCampaign(ctx context.Context, c *app.RequestContext) {
+	shelterID, ok := parseIDParam(c, "id", "救助机构ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveCampaignRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建募捐活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	campaign, err := h.donationService.CreateCampaign(ctx, middleware.GetUserID(c), shelterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    campaign,
+	})
+}
+
+// UpdateCampaign 修改募捐活动
+// @Summary 修改募捐活动
+// @Description 修改进行中的募捐活动,已筹金额不受影响
+// @Tags 募捐
+// @Accept json
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param request body model.SaveCampaignRequest true "活动信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donation-campaigns/{id} [put]
+func (h *DonationHandler) UpdateCampaign(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveCampaignRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改募捐活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	campaign, err := h.donationService.UpdateCampaign(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    campaign,
+	})
+}
+
+// CloseCampaign 结束募捐活动
+// @Summary 结束募捐活动
+// @Description 机构管理员提前结束活动,结束后不再接受新的捐款
+// @Tags 募捐
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donation-campaigns/{id}/close [put]
+func (h *DonationHandler) CloseCampaign(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	campaign, err := h.donationService.CloseCampaign(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    campaign,
+	})
+}
+
+// GetCampaign 募捐活动详情
+// @Summary 募捐活动详情
+// @Description 公开接口,返回实时的已筹金额、捐款笔数和进度百分比
+// @Tags 募捐
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donation-campaigns/{id} [get]
+func (h *DonationHandler) GetCampaign(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	campaign, err := h.donationService.GetCampaign(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    campaign,
+	})
+}
+
+// ListCampaigns 募捐活动列表
+// @Summary 募捐活动列表
+// @Description 公开接口,默认返回进行中的活动,按截止时间排序;ended=true返回已结束的活动
+// @Tags 募捐
+// @Produce json
+// @Param shelter_id query int false "救助机构ID"
+// @Param ended query bool false "是否查看已结束的活动"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donation-campaigns [get]
+func (h *DonationHandler) ListCampaigns(ctx context.Context, c *app.RequestContext) {
+	var req model.ListCampaignRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取募捐活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	campaigns, total, err := h.donationService.ListCampaigns(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      campaigns,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListDonors 捐款动态
+// @Summary 捐款动态
+// @Description 公开接口,活动已到账的捐款,按到账时间倒序;匿名捐款显示为匿名爱心人士
+// @Tags 募捐
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donation-campaigns/{id}/donors [get]
+func (h *DonationHandler) ListDonors(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListDonationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取捐款动态参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	donors, total, err := h.donationService.ListDonors(ctx, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      donors,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// Donate 捐款
+// @Summary 捐款
+// @Description 创建捐款及待支付订单,使用返回的订单ID调用支付接口;到账后计入活动总额,anonymous=true时对外不展示捐款人
+// @Tags 募捐
+// @Accept json
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param request body model.CreateDonationRequest true "捐款信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donation-campaigns/{id}/donations [post]
+func (h *DonationHandler) Donate(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateDonationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "捐款参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	checkout, err := h.donationService.Donate(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    checkout,
+	})
+}
+
+// GetDonation 捐款详情
+// @Summary 捐款详情
+// @Description 获取本人的捐款
+// @Tags 募捐
+// @Produce json
+// @Param id path int true "捐款ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/donations/{id} [get]
+func (h *DonationHandler) GetDonation(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "捐款ID")
+	if !ok {
+		return
+	}
+
+	donation, err := h.donationService.GetMyDonation(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    donation,
+	})
+}
+
+// ListMyDonations 我的捐款
+// @Summary 我的捐款
+// @Description 当前用户的捐款记录,含待支付的捐款
+// @Tags 募捐
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/donations [get]
+func (h *DonationHandler) ListMyDonations(ctx context.Context, c *app.RequestContext) {
+	var req model.ListDonationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取捐款记录参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	donations, total, err := h.donationService.ListMyDonations(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      donations,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ExportDonations 导出捐款
+// @Summary 导出捐款
+// @Description 机构管理员导出已到账的捐款(CSV,UTF-8带BOM),可按活动和到账日期筛选;匿名捐款不导出捐款人名称
+// @Tags 募捐
+// @Produce text/csv
+// @Param id path int true "救助机构ID"
+// @Param campaign_id query int false "活动ID"
+// @Param from query string false "开始日期YYYY-MM-DD"
+// @Param to query string false "结束日期YYYY-MM-DD(包含)"
+// @Success 200 {file} file
+// @Router /api/v1/shelters/{id}/donations/export [get]
+func (h *DonationHandler) ExportDonations(ctx context.Context, c *app.RequestContext) {
+	shelterID, ok := parseIDParam(c, "id", "救助机构ID")
+	if !ok {
+		return
+	}
+
+	var req model.ExportDonationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "导出捐款参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	data, err := h.donationService.ExportDonations(ctx, middleware.GetUserID(c), shelterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="donations-%d.csv"`, shelterID))
+	c.Data(consts.StatusOK, "text/csv; charset=utf-8", data)
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// EventHandler 社区活动处理器
+type EventHandler struct {
+	eventService service.EventService
+}
+
+// NewEventHandler 创建社区活动处理器
+func NewEventHandler(eventService service.EventService) *EventHandler {
+	return &EventHandler{eventService: eventService}
+}
+
+// ListEvents 活动列表
+// @Summary 活动列表
+// @Description 检索报名中且未结束的活动,按开始时间升序;species筛选允许携带该物种的活动
+// @Tags 社区活动
+// @Produce json
+// @Param city query string false "城市"
+// @Param species query string false "物种"
+// @Param keyword query string false "标题或地点"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events [get]
+func (h *EventHandler) ListEvents(ctx context.Context, c *app.RequestContext) {
+	var req model.ListEventRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取活动列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	events, total, err := h.eventService.ListEvents(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      events,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetEvent 活动详情
+// @Summary 活动详情
+// @Description 获取活动信息、名额与报名人数
+// @Tags 社区活动
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id} [get]
+func (h *EventHandler) GetEvent(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	event, err := h.eventService.GetEvent(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    event,
+	})
+}
+
+// ExportICS 导出日历
+// @Summary 导出日历
+// @Description 下载活动的iCalendar(.ics)文件,可导入手机或邮箱日历,包含开始前提醒
+// @Tags 社区活动
+// @Produce text/calendar
+// @Param id path int true "活动ID"
+// @Success 200 {file} file
+// @Router /api/v1/events/{id}/ics [get]
+func (h *EventHandler) ExportICS(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	data, err := h.eventService.ExportICS(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="event-%d.ics"`, id))
+	c.Data(consts.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+// CreateEvent 发布活动
+// @Summary 发布活动
+// @Description 发布社区活动,当前用户为组织者;allowed_species为空表示不限物种,remind_before_min默认120
+// @Tags 社区活动
+// @Accept json
+// @Produce json
+// @Param request body model.SaveEventRequest true "活动信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events [post]
+func (h *EventHandler) CreateEvent(ctx context.Context, c *app.RequestContext) {
+	var req model.SaveEventRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发布活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	event, err := h.eventService.CreateEvent(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "发布成功",
+		"data":    event,
+	})
+}
+
+// UpdateEvent 修改活动
+// @Summary 修改活动
+// @Description 组织者在活动开始前修改活动,名额不能少于已报名人数,名额增加时候补用户按顺序递补;时间或地点变化时通知报名者
+// @Tags 社区活动
+// @Accept json
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param request body model.SaveEventRequest true "活动信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id} [put]
+func (h *EventHandler) UpdateEvent(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveEventRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	event, err := h.eventService.UpdateEvent(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    event,
+	})
+}
+
+// CancelEvent 取消活动
+// @Summary 取消活动
+// @Description 组织者取消活动,已报名和候补中的用户会收到通知
+// @Tags 社区活动
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id}/cancel [put]
+func (h *EventHandler) CancelEvent(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	event, err := h.eventService.CancelEvent(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消",
+		"data":    event,
+	})
+}
+
+// RSVP 报名活动
+// @Summary 报名活动
+// @Description 名额未满时直接报名成功,否则进入候补并返回排队位置;重复提交只更新携带的宠物和备注
+// @Tags 社区活动
+// @Accept json
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param request body model.RSVPRequest false "携带的宠物与备注"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id}/rsvp [put]
+func (h *EventHandler) RSVP(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.RSVPRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "报名活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	rsvp, err := h.eventService.RSVP(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	message := "报名成功"
+	if rsvp.Status == model.RSVPStatusWaitlisted {
+		message = "名额已满,已加入候补"
+	}
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": message,
+		"data":    rsvp,
+	})
+}
+
+// CancelRSVP 取消报名
+// @Summary 取消报名
+// @Description 取消报名或退出候补,空出的名额由候补第一位自动递补
+// @Tags 社区活动
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id}/rsvp [delete]
+func (h *EventHandler) CancelRSVP(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	if err := h.eventService.CancelRSVP(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消报名",
+	})
+}
+
+// GetMyRSVP 我的报名状态
+// @Summary 我的报名状态
+// @Description 获取当前用户对活动的报名状态,候补中时返回waitlist_position
+// @Tags 社区活动
+// @Produce json
+// @Param id path int true "活动ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id}/rsvp [get]
+func (h *EventHandler) GetMyRSVP(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	rsvp, err := h.eventService.GetMyRSVP(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    rsvp,
+	})
+}
+
+// ListRSVPs 报名名单
+// @Summary 报名名单
+// @Description 组织者按报名顺序查看报名和候补名单
+// @Tags 社区活动
+// @Produce json
+// @Param id path int true "活动ID"
+// @Param status query string false "状态:going,waitlisted,cancelled"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/events/{id}/rsvps [get]
+func (h *EventHandler) ListRSVPs(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "活动ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListRSVPRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取报名名单参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	rsvps, total, err := h.eventService.ListRSVPs(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      rsvps,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListOrganizedEvents 我组织的活动
+// @Summary 我组织的活动
+// @Description 当前用户组织的全部活动,按开始时间倒序
+// @Tags 社区活动
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/events [get]
+func (h *EventHandler) ListOrganizedEvents(ctx context.Context, c *app.RequestContext) {
+	var req model.ListMyEventsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取我组织的活动参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	events, total, err := h.eventService.ListOrganizedEvents(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      events,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListMyRSVPs 我的报名
+// @Summary 我的报名
+// @Description 当前用户已报名或候补中的活动
+// @Tags 社区活动
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/event-rsvps [get]
+func (h *EventHandler) ListMyRSVPs(ctx context.Context, c *app.RequestContext) {
+	var req model.ListMyEventsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取我的报名参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	rsvps, total, err := h.eventService.ListMyRSVPs(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      rsvps,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// FileHandler 文件处理器
+type FileHandler struct {
+	fileService service.FileService
+}
+
+// NewFileHandler 创建文件处理器
+func NewFileHandler(fileService service.FileService) *FileHandler {
+	return &FileHandler{fileService: fileService}
+}
+
+// Upload 上传文件
+// @Summary 上传文件
+// @Description multipart上传头像、宠物照片或附件,图片自动生成缩略图
+// @Tags 文件
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "文件"
+// @Param purpose formData string true "用途:avatar,pet_photo,attachment"
+// @Success 200 {object} utils.H
+// @Router /api/v1/files [post]
+func (h *FileHandler) Upload(ctx context.Context, c *app.RequestContext) {
+	var req model.UploadFileRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "上传文件参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"code":    400,
+			"message": "请选择要上传的文件",
+		})
+		return
+	}
+
+	file, err := h.fileService.Upload(ctx, middleware.GetUserID(c), req.Purpose, header)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "上传成功",
+		"data":    file,
+	})
+}
+
+// GetFile 获取文件信息
+// @Summary 获取文件信息
+// @Description 获取文件信息及新的限时下载链接
+// @Tags 文件
+// @Produce json
+// @Param id path int true "文件ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/files/{id} [get]
+func (h *FileHandler) GetFile(ctx context.Context, c *app.RequestContext) {
+	fileID, ok := parseIDParam(c, "id", "文件ID")
+	if !ok {
+		return
+	}
+
+	file, err := h.fileService.GetFile(ctx, middleware.GetUserID(c), fileID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    file,
+	})
+}
+
+// DeleteFile 删除文件
+// @Summary 删除文件
+// @Description 删除自己上传的文件
+// @Tags 文件
+// @Produce json
+// @Param id path int true "文件ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/files/{id} [delete]
+func (h *FileHandler) DeleteFile(ctx context.Context, c *app.RequestContext) {
+	fileID, ok := parseIDParam(c, "id", "文件ID")
+	if !ok {
+		return
+	}
+
+	if err := h.fileService.DeleteFile(ctx, middleware.GetUserID(c), fileID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// Download 下载文件
+// @Summary 下载文件
+// @Description 通过带签名的限时链接下载文件或缩略图
+// @Tags 文件
+// @Produce octet-stream
+// @Param id path int true "文件ID"
+// @Param variant query string false "版本:original,thumb"
+// @Param expires query int true "过期时间戳"
+// @Param signature query string true "签名"
+// @Router /api/v1/files/{id}/download [get]
+func (h *FileHandler) Download(ctx context.Context, c *app.RequestContext) {
+	fileID, ok := parseIDParam(c, "id", "文件ID")
+	if !ok {
+		return
+	}
+
+	var req model.DownloadFileRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	r, contentType, err := h.fileService.Open(ctx, fileID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.SetContentType(contentType)
+	c.SetBodyStream(r, -1)
+}
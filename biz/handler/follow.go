@@ -0,0 +1,411 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// FollowHandler 关注与拉黑处理器
+type FollowHandler struct {
+	followService service.FollowService
+}
+
+// NewFollowHandler 创建关注与拉黑处理器
+func NewFollowHandler(followService service.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+// FollowUser 关注用户
+// @Summary 关注用户
+// @Description 重复关注不重复计数,与对方互相拉黑时不能关注
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/follow [put]
+func (h *FollowHandler) FollowUser(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	relation, err := h.followService.FollowUser(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "关注成功",
+		"data":    relation,
+	})
+}
+
+// UnfollowUser 取消关注用户
+// @Summary 取消关注用户
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/follow [delete]
+func (h *FollowHandler) UnfollowUser(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	relation, err := h.followService.UnfollowUser(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消关注",
+		"data":    relation,
+	})
+}
+
+// GetRelation 用户关系
+// @Summary 用户关系
+// @Description 获取目标用户的粉丝数、关注数,以及当前用户与其是否关注、互关、拉黑
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/relation [get]
+func (h *FollowHandler) GetRelation(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	relation, err := h.followService.GetRelation(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    relation,
+	})
+}
+
+// ListFollowers 粉丝列表
+// @Summary 粉丝列表
+// @Description 按关注时间倒序,使用next_cursor翻页
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/followers [get]
+func (h *FollowHandler) ListFollowers(ctx context.Context, c *app.RequestContext) {
+	h.listUserFollows(ctx, c, h.followService.ListFollowers)
+}
+
+// ListFollowing 关注列表
+// @Summary 关注列表
+// @Description 按关注时间倒序,使用next_cursor翻页
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/following [get]
+func (h *FollowHandler) ListFollowing(ctx context.Context, c *app.RequestContext) {
+	h.listUserFollows(ctx, c, h.followService.ListFollowing)
+}
+
+func (h *FollowHandler) listUserFollows(ctx context.Context, c *app.RequestContext,
+	list func(ctx context.Context, viewerID, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error)) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	var req model.CursorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取关注列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := list(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// ListMutuals 互相关注
+// @Summary 互相关注
+// @Description 与当前用户互相关注的用户,使用next_cursor翻页
+// @Tags 关注
+// @Produce json
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/mutuals [get]
+func (h *FollowHandler) ListMutuals(ctx context.Context, c *app.RequestContext) {
+	var req model.CursorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取互相关注列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.followService.ListMutuals(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// FollowPet 关注宠物
+// @Summary 关注宠物
+// @Description 关注后时间线会包含标记了该宠物的动态
+// @Tags 关注
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/follow [put]
+func (h *FollowHandler) FollowPet(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	stats, err := h.followService.FollowPet(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "关注成功",
+		"data":    stats,
+	})
+}
+
+// UnfollowPet 取消关注宠物
+// @Summary 取消关注宠物
+// @Tags 关注
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/follow [delete]
+func (h *FollowHandler) UnfollowPet(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	stats, err := h.followService.UnfollowPet(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消关注",
+		"data":    stats,
+	})
+}
+
+// GetPetFollowStats 宠物关注数
+// @Summary 宠物关注数
+// @Tags 关注
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/follow [get]
+func (h *FollowHandler) GetPetFollowStats(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	stats, err := h.followService.GetPetStats(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    stats,
+	})
+}
+
+// ListPetFollowers 宠物的粉丝
+// @Summary 宠物的粉丝
+// @Description 按关注时间倒序,使用next_cursor翻页
+// @Tags 关注
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/followers [get]
+func (h *FollowHandler) ListPetFollowers(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.CursorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取宠物粉丝参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.followService.ListPetFollowers(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// ListFollowedPets 我关注的宠物
+// @Summary 我关注的宠物
+// @Tags 关注
+// @Produce json
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/following-pets [get]
+func (h *FollowHandler) ListFollowedPets(ctx context.Context, c *app.RequestContext) {
+	var req model.CursorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取关注的宠物参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.followService.ListFollowedPets(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// BlockUser 拉黑用户
+// @Summary 拉黑用户
+// @Description 拉黑后双方互相看不到对方的动态和评论,并解除双方的关注关系
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/block [put]
+func (h *FollowHandler) BlockUser(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	if err := h.followService.BlockUser(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已拉黑",
+	})
+}
+
+// UnblockUser 取消拉黑
+// @Summary 取消拉黑
+// @Description 取消拉黑不会恢复之前的关注关系
+// @Tags 关注
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/users/{id}/block [delete]
+func (h *FollowHandler) UnblockUser(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	if err := h.followService.UnblockUser(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消拉黑",
+	})
+}
+
+// ListBlocks 我的黑名单
+// @Summary 我的黑名单
+// @Tags 关注
+// @Produce json
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/blocks [get]
+func (h *FollowHandler) ListBlocks(ctx context.Context, c *app.RequestContext) {
+	var req model.CursorRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取黑名单参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.followService.ListBlocks(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
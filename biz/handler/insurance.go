@@ -0,0 +1,411 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// InsuranceHandler 宠物保险处理器
+type InsuranceHandler struct {
+	insuranceService service.InsuranceService
+}
+
+// NewInsuranceHandler 创建宠物保险处理器
+func NewInsuranceHandler(insuranceService service.InsuranceService) *InsuranceHandler {
+	return &InsuranceHandler{insuranceService: insuranceService}
+}
+
+// CreatePolicy 添加保单
+// @Summary 添加保单
+// @Description 为宠物添加保险保单,需要宠物编辑权限;金额单位为分,日期格式YYYY-MM-DD
+// @Tags 宠物保险
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.SaveInsurancePolicyRequest true "保单信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/insurance-policies [post]
+func (h *InsuranceHandler) CreatePolicy(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveInsurancePolicyRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "添加保单参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	policy, err := h.insuranceService.CreatePolicy(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    policy,
+	})
+}
+
+// ListPolicies 保单列表
+// @Summary 保单列表
+// @Description 宠物的全部保单,active表示今天是否在有效期内
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/insurance-policies [get]
+func (h *InsuranceHandler) ListPolicies(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	policies, err := h.insuranceService.ListPolicies(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    policies,
+	})
+}
+
+// GetPolicy 保单详情
+// @Summary 保单详情
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "保单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-policies/{id} [get]
+func (h *InsuranceHandler) GetPolicy(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "保单ID")
+	if !ok {
+		return
+	}
+
+	policy, err := h.insuranceService.GetPolicy(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    policy,
+	})
+}
+
+// UpdatePolicy 修改保单
+// @Summary 修改保单
+// @Tags 宠物保险
+// @Accept json
+// @Produce json
+// @Param id path int true "保单ID"
+// @Param request body model.SaveInsurancePolicyRequest true "保单信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-policies/{id} [put]
+func (h *InsuranceHandler) UpdatePolicy(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "保单ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveInsurancePolicyRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改保单参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	policy, err := h.insuranceService.UpdatePolicy(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    policy,
+	})
+}
+
+// DeletePolicy 删除保单
+// @Summary 删除保单
+// @Description 删除保单,保单下存在理赔记录时不允许删除
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "保单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-policies/{id} [delete]
+func (h *InsuranceHandler) DeletePolicy(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "保单ID")
+	if !ok {
+		return
+	}
+
+	if err := h.insuranceService.DeletePolicy(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// CreateClaim 创建理赔
+// @Summary 创建理赔
+// @Description 在保单下创建理赔草稿,可关联就诊记录,未填写的治疗日期和发票号取自就诊记录;治疗日期须在保单有效期内
+// @Tags 宠物保险
+// @Accept json
+// @Produce json
+// @Param id path int true "保单ID"
+// @Param request body model.SaveInsuranceClaimRequest true "理赔信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-policies/{id}/claims [post]
+func (h *InsuranceHandler) CreateClaim(ctx context.Context, c *app.RequestContext) {
+	policyID, ok := parseIDParam(c, "id", "保单ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveInsuranceClaimRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建理赔参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	claim, err := h.insuranceService.CreateClaim(ctx, middleware.GetUserID(c), policyID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    claim,
+	})
+}
+
+// ListClaims 理赔列表
+// @Summary 理赔列表
+// @Description 宠物的理赔记录,按治疗日期倒序
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param policy_id query int false "保单ID"
+// @Param status query string false "状态"
+// @Param year query int false "治疗年份"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/insurance-claims [get]
+func (h *InsuranceHandler) ListClaims(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListInsuranceClaimRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取理赔列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	claims, total, err := h.insuranceService.ListClaims(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      claims,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetClaim 理赔详情
+// @Summary 理赔详情
+// @Description 获取理赔、附件下载链接及状态变更历史
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "理赔ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-claims/{id} [get]
+func (h *InsuranceHandler) GetClaim(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "理赔ID")
+	if !ok {
+		return
+	}
+
+	claim, err := h.insuranceService.GetClaim(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    claim,
+	})
+}
+
+// UpdateClaim 修改理赔
+// @Summary 修改理赔
+// @Description 仅草稿状态的理赔可以修改
+// @Tags 宠物保险
+// @Accept json
+// @Produce json
+// @Param id path int true "理赔ID"
+// @Param request body model.SaveInsuranceClaimRequest true "理赔信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-claims/{id} [put]
+func (h *InsuranceHandler) UpdateClaim(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "理赔ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveInsuranceClaimRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改理赔参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	claim, err := h.insuranceService.UpdateClaim(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    claim,
+	})
+}
+
+// DeleteClaim 删除理赔
+// @Summary 删除理赔
+// @Description 仅草稿状态的理赔可以删除,已提交的理赔请撤回
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "理赔ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-claims/{id} [delete]
+func (h *InsuranceHandler) DeleteClaim(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "理赔ID")
+	if !ok {
+		return
+	}
+
+	if err := h.insuranceService.DeleteClaim(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// UpdateClaimStatus 变更理赔状态
+// @Summary 变更理赔状态
+// @Description 状态流转:draft→submitted→in_review→approved/denied,approved→paid,draft/submitted可撤回(withdrawn);核准时amount为核准金额,默认等于申请金额;赔付时amount为实际赔付金额,默认等于核准金额;拒赔须填写note
+// @Tags 宠物保险
+// @Accept json
+// @Produce json
+// @Param id path int true "理赔ID"
+// @Param request body model.UpdateClaimStatusRequest true "目标状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/insurance-claims/{id}/status [put]
+func (h *InsuranceHandler) UpdateClaimStatus(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "理赔ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateClaimStatusRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "变更理赔状态参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	claim, err := h.insuranceService.UpdateClaimStatus(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    claim,
+	})
+}
+
+// GetSummary 理赔汇总
+// @Summary 理赔汇总
+// @Description 按治疗年份汇总申请、核准、已赔付(reimbursed)与待赔付(outstanding)金额,并细分到保单;草稿和已撤回的理赔不计入
+// @Tags 宠物保险
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param year query int false "年份,为空时汇总所有年份"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/insurance/summary [get]
+func (h *InsuranceHandler) GetSummary(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.InsuranceSummaryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取理赔汇总参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	summary, err := h.insuranceService.GetSummary(ctx, middleware.GetUserID(c), petID, req.Year)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    summary,
+	})
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// LostFoundHandler 走失/招领处理器
+type LostFoundHandler struct {
+	lostFoundService service.LostFoundService
+}
+
+// NewLostFoundHandler 创建走失/招领处理器
+func NewLostFoundHandler(lostFoundService service.LostFoundService) *LostFoundHandler {
+	return &LostFoundHandler{lostFoundService: lostFoundService}
+}
+
+// CreateReport 发布走失/招领信息
+// @Summary 发布走失/招领信息
+// @Description 发布走失或招领信息,返回附近的疑似匹配
+// @Tags 走失招领
+// @Accept json
+// @Produce json
+// @Param request body model.CreateReportRequest true "走失/招领信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/lost-found [post]
+func (h *LostFoundHandler) CreateReport(ctx context.Context, c *app.RequestContext) {
+	var req model.CreateReportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发布走失/招领信息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	report, matches, err := h.lostFoundService.CreateReport(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "发布成功",
+		"data": utils.H{
+			"report":  report,
+			"matches": matches,
+		},
+	})
+}
+
+// GetReport 获取走失/招领信息详情
+// @Summary 获取走失/招领信息详情
+// @Description 根据ID获取走失/招领信息
+// @Tags 走失招领
+// @Produce json
+// @Param id path int true "信息ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/lost-found/{id} [get]
+func (h *LostFoundHandler) GetReport(ctx context.Context, c *app.RequestContext) {
+	reportID, ok := parseIDParam(c, "id", "信息ID")
+	if !ok {
+		return
+	}
+
+	report, err := h.lostFoundService.GetReport(ctx, reportID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    report,
+	})
+}
+
+// ResolveReport 标记走失/招领信息已解决
+// @Summary 标记已解决
+// @Description 发布人标记走失/招领信息已解决
+// @Tags 走失招领
+// @Produce json
+// @Param id path int true "信息ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/lost-found/{id}/resolve [put]
+func (h *LostFoundHandler) ResolveReport(ctx context.Context, c *app.RequestContext) {
+	reportID, ok := parseIDParam(c, "id", "信息ID")
+	if !ok {
+		return
+	}
+
+	if err := h.lostFoundService.ResolveReport(ctx, middleware.GetUserID(c), reportID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "操作成功",
+	})
+}
+
+// ListMyReports 获取我发布的走失/招领信息
+// @Summary 获取我发布的走失/招领信息
+// @Description 获取当前用户发布的走失/招领信息
+// @Tags 走失招领
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/lost-found [get]
+func (h *LostFoundHandler) ListMyReports(ctx context.Context, c *app.RequestContext) {
+	var req model.ListReportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取走失/招领信息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	reports, total, err := h.lostFoundService.ListMyReports(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      reports,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// SearchNearby 查询附近的走失/招领信息
+// @Summary 查询附近的走失/招领信息
+// @Description 查询某点半径范围内的走失/招领信息
+// @Tags 走失招领
+// @Produce json
+// @Param lat query number true "纬度"
+// @Param lng query number true "经度"
+// @Param radius_km query number false "半径(km),默认5"
+// @Param type query string false "类型:lost,found"
+// @Param species query string false "物种"
+// @Param limit query int false "返回数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/lost-found/nearby [get]
+func (h *LostFoundHandler) SearchNearby(ctx context.Context, c *app.RequestContext) {
+	var req model.NearbyReportRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "查询附近走失/招领信息参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	reports, err := h.lostFoundService.SearchNearby(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    reports,
+	})
+}
+
+// SuggestMatches 获取疑似匹配
+// @Summary 获取疑似匹配
+// @Description 按物种、毛色和距离推荐走失与招领信息的疑似匹配
+// @Tags 走失招领
+// @Produce json
+// @Param id path int true "信息ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/lost-found/{id}/matches [get]
+func (h *LostFoundHandler) SuggestMatches(ctx context.Context, c *app.RequestContext) {
+	reportID, ok := parseIDParam(c, "id", "信息ID")
+	if !ok {
+		return
+	}
+
+	matches, err := h.lostFoundService.SuggestMatches(ctx, reportID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    matches,
+	})
+}
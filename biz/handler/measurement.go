@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// MeasurementHandler 健康指标处理器
+type MeasurementHandler struct {
+	measurementService service.MeasurementService
+}
+
+// NewMeasurementHandler 创建健康指标处理器
+func NewMeasurementHandler(measurementService service.MeasurementService) *MeasurementHandler {
+	return &MeasurementHandler{measurementService: measurementService}
+}
+
+// RecordMeasurements 批量记录测量数据
+// @Summary 批量记录测量数据
+// @Description 批量记录体重、体温等健康指标,包含体重时返回体重提醒
+// @Tags 健康指标
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.CreateMeasurementsRequest true "测量数据"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/measurements [post]
+func (h *MeasurementHandler) RecordMeasurements(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateMeasurementsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "记录测量数据参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	items, alerts, err := h.measurementService.Record(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "记录成功",
+		"data": utils.H{
+			"items":  items,
+			"alerts": alerts,
+		},
+	})
+}
+
+// QueryMeasurements 查询测量数据
+// @Summary 查询测量数据
+// @Description 按时间区间查询健康指标,interval为day/week时返回降采样后的均值、最值
+// @Tags 健康指标
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param metric query string true "指标:weight,body_condition,temperature,heart_rate,respiratory_rate"
+// @Param from query string false "开始时间(RFC3339),默认90天前"
+// @Param to query string false "结束时间(RFC3339),默认当前"
+// @Param interval query string false "粒度:raw,day,week"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/measurements [get]
+func (h *MeasurementHandler) QueryMeasurements(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.QueryMeasurementsRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "查询测量数据参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	series, err := h.measurementService.Query(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    series,
+	})
+}
+
+// DeleteMeasurement 删除测量数据
+// @Summary 删除测量数据
+// @Description 删除一条录入错误的测量数据
+// @Tags 健康指标
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param mid path int true "测量数据ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/measurements/{mid} [delete]
+func (h *MeasurementHandler) DeleteMeasurement(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+	measurementID, ok := parseIDParam(c, "mid", "测量数据ID")
+	if !ok {
+		return
+	}
+
+	if err := h.measurementService.Delete(ctx, middleware.GetUserID(c), petID, measurementID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// GetAlerts 获取体重提醒
+// @Summary 获取体重提醒
+// @Description 检查窗口期内体重变化是否超过阈值,以及成年宠物体重是否偏离品种标准
+// @Tags 健康指标
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param window_days query int false "窗口天数,默认取配置"
+// @Param threshold_pct query number false "变化百分比阈值,默认取配置"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/measurements/alerts [get]
+func (h *MeasurementHandler) GetAlerts(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.MeasurementAlertRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	alerts, err := h.measurementService.CheckAlerts(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    alerts,
+	})
+}
+
+// ListBreeds 查询品种目录
+// @Summary 查询品种目录
+// @Description 查询品种及成年体重标准
+// @Tags 健康指标
+// @Produce json
+// @Param species query string false "物种"
+// @Param keyword query string false "名称关键字"
+// @Success 200 {object} utils.H
+// @Router /api/v1/breeds [get]
+func (h *MeasurementHandler) ListBreeds(ctx context.Context, c *app.RequestContext) {
+	var req model.ListBreedRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	breeds, err := h.measurementService.ListBreeds(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    breeds,
+	})
+}
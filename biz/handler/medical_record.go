@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// MedicalRecordHandler 就诊记录处理器
+type MedicalRecordHandler struct {
+	recordService service.MedicalRecordService
+}
+
+// NewMedicalRecordHandler 创建就诊记录处理器
+func NewMedicalRecordHandler(recordService service.MedicalRecordService) *MedicalRecordHandler {
+	return &MedicalRecordHandler{recordService: recordService}
+}
+
+// CreateRecord 新增就诊记录
+// @Summary 新增就诊记录
+// @Description 为宠物记录一次就诊,需要宠物编辑权限;费用单位为分,附件须为当前用户上传的文件
+// @Tags 就诊记录
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.SaveMedicalRecordRequest true "就诊信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/medical-records [post]
+func (h *MedicalRecordHandler) CreateRecord(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveMedicalRecordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "新增就诊记录参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	record, err := h.recordService.CreateRecord(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    record,
+	})
+}
+
+// ListRecords 就诊记录列表
+// @Summary 就诊记录列表
+// @Description 宠物的就诊记录,按就诊日期倒序
+// @Tags 就诊记录
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param type query string false "类型"
+// @Param from query string false "开始日期YYYY-MM-DD"
+// @Param to query string false "结束日期YYYY-MM-DD"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/medical-records [get]
+func (h *MedicalRecordHandler) ListRecords(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListMedicalRecordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取就诊记录参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	records, total, err := h.recordService.ListRecords(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      records,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetRecord 就诊记录详情
+// @Summary 就诊记录详情
+// @Description 获取就诊记录及附件下载链接
+// @Tags 就诊记录
+// @Produce json
+// @Param id path int true "就诊记录ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medical-records/{id} [get]
+func (h *MedicalRecordHandler) GetRecord(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "就诊记录ID")
+	if !ok {
+		return
+	}
+
+	record, err := h.recordService.GetRecord(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    record,
+	})
+}
+
+// UpdateRecord 修改就诊记录
+// @Summary 修改就诊记录
+// @Description 修改就诊记录,需要宠物编辑权限
+// @Tags 就诊记录
+// @Accept json
+// @Produce json
+// @Param id path int true "就诊记录ID"
+// @Param request body model.SaveMedicalRecordRequest true "就诊信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medical-records/{id} [put]
+func (h *MedicalRecordHandler) UpdateRecord(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "就诊记录ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveMedicalRecordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改就诊记录参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	record, err := h.recordService.UpdateRecord(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    record,
+	})
+}
+
+// DeleteRecord 删除就诊记录
+// @Summary 删除就诊记录
+// @Description 删除就诊记录,需要宠物编辑权限
+// @Tags 就诊记录
+// @Produce json
+// @Param id path int true "就诊记录ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medical-records/{id} [delete]
+func (h *MedicalRecordHandler) DeleteRecord(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "就诊记录ID")
+	if !ok {
+		return
+	}
+
+	if err := h.recordService.DeleteRecord(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
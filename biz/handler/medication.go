@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// MedicationHandler 用药计划处理器
+type MedicationHandler struct {
+	medicationService service.MedicationService
+}
+
+// NewMedicationHandler 创建用药计划处理器
+func NewMedicationHandler(medicationService service.MedicationService) *MedicationHandler {
+	return &MedicationHandler{medicationService: medicationService}
+}
+
+// CreatePlan 创建用药计划
+// @Summary 创建用药计划
+// @Description 按固定间隔或cron规则创建用药计划,并生成未来一周的给药记录
+// @Tags 用药管理
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.CreateMedicationPlanRequest true "用药计划"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/medications [post]
+func (h *MedicationHandler) CreatePlan(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateMedicationPlanRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建用药计划参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plan, err := h.medicationService.CreatePlan(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    plan,
+	})
+}
+
+// ListPlans 获取宠物的用药计划
+// @Summary 用药计划列表
+// @Description 获取宠物的用药计划,可按状态筛选
+// @Tags 用药管理
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param status query string false "状态:active,paused,ended"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/medications [get]
+func (h *MedicationHandler) ListPlans(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListMedicationPlanRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "查询用药计划参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plans, err := h.medicationService.ListPlans(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    plans,
+	})
+}
+
+// GetPlan 获取用药计划详情
+// @Summary 用药计划详情
+// @Description 获取用药计划详情
+// @Tags 用药管理
+// @Produce json
+// @Param id path int true "用药计划ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medications/{id} [get]
+func (h *MedicationHandler) GetPlan(ctx context.Context, c *app.RequestContext) {
+	planID, ok := parseIDParam(c, "id", "用药计划ID")
+	if !ok {
+		return
+	}
+
+	plan, err := h.medicationService.GetPlan(ctx, middleware.GetUserID(c), planID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    plan,
+	})
+}
+
+// UpdatePlan 修改用药计划
+// @Summary 修改用药计划
+// @Description 修改药品、剂量或频率,尚未执行的后续给药记录按新规则重新生成
+// @Tags 用药管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用药计划ID"
+// @Param request body model.CreateMedicationPlanRequest true "用药计划"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medications/{id} [put]
+func (h *MedicationHandler) UpdatePlan(ctx context.Context, c *app.RequestContext) {
+	planID, ok := parseIDParam(c, "id", "用药计划ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateMedicationPlanRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改用药计划参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plan, err := h.medicationService.UpdatePlan(ctx, middleware.GetUserID(c), planID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    plan,
+	})
+}
+
+// UpdatePlanStatus 暂停、恢复或结束用药计划
+// @Summary 变更用药计划状态
+// @Description 暂停或结束时删除尚未执行的给药记录,恢复时从当前时间重新生成
+// @Tags 用药管理
+// @Accept json
+// @Produce json
+// @Param id path int true "用药计划ID"
+// @Param request body model.UpdateMedicationStatusRequest true "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medications/{id}/status [put]
+func (h *MedicationHandler) UpdatePlanStatus(ctx context.Context, c *app.RequestContext) {
+	planID, ok := parseIDParam(c, "id", "用药计划ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateMedicationStatusRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "变更用药计划状态参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	plan, err := h.medicationService.UpdatePlanStatus(ctx, middleware.GetUserID(c), planID, req.Status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    plan,
+	})
+}
+
+// ListDoses 查询给药记录
+// @Summary 给药记录
+// @Description 按时间区间查询宠物的给药记录,默认返回今天起一周内的记录
+// @Tags 用药管理
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param from query string false "开始时间(RFC3339)"
+// @Param to query string false "结束时间(RFC3339)"
+// @Param status query string false "状态:scheduled,given,skipped,missed"
+// @Param plan_id query int false "用药计划ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/medication-doses [get]
+func (h *MedicationHandler) ListDoses(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListDoseRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "查询给药记录参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	doses, err := h.medicationService.ListDoses(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    doses,
+	})
+}
+
+// RecordDose 记录给药结果
+// @Summary 记录给药结果
+// @Description 将给药记录标记为已给药或已跳过,漏服的记录可以补记
+// @Tags 用药管理
+// @Accept json
+// @Produce json
+// @Param id path int true "给药记录ID"
+// @Param request body model.RecordDoseRequest true "给药结果"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medication-doses/{id} [put]
+func (h *MedicationHandler) RecordDose(ctx context.Context, c *app.RequestContext) {
+	doseID, ok := parseIDParam(c, "id", "给药记录ID")
+	if !ok {
+		return
+	}
+
+	var req model.RecordDoseRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "记录给药结果参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	dose, err := h.medicationService.RecordDose(ctx, middleware.GetUserID(c), doseID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "记录成功",
+		"data":    dose,
+	})
+}
+
+// Adherence 用药依从性统计
+// @Summary 用药依从性统计
+// @Description 统计区间内已给药、跳过、漏服次数及依从率,按计划及按天/周汇总
+// @Tags 用药管理
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param from query string false "开始时间(RFC3339),默认30天前"
+// @Param to query string false "结束时间(RFC3339),默认当前"
+// @Param interval query string false "汇总粒度:day,week"
+// @Param plan_id query int false "用药计划ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/medications/adherence [get]
+func (h *MedicationHandler) Adherence(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.AdherenceRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "查询用药依从性参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	report, err := h.medicationService.Adherence(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    report,
+	})
+}
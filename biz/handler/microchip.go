@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// MicrochipHandler 芯片登记查询处理器
+type MicrochipHandler struct {
+	chipService service.MicrochipService
+}
+
+// NewMicrochipHandler 创建芯片登记查询处理器
+func NewMicrochipHandler(chipService service.MicrochipService) *MicrochipHandler {
+	return &MicrochipHandler{chipService: chipService}
+}
+
+// Lookup 查询芯片号
+// @Summary 查询芯片号
+// @Description 扫描流浪宠物芯片后查询,返回宠物外观信息和脱敏的主人联系方式,接口限流且记录审计
+// @Tags 芯片
+// @Produce json
+// @Param code path string true "15位芯片号"
+// @Success 200 {object} utils.H
+// @Router /api/v1/microchips/{code} [get]
+func (h *MicrochipHandler) Lookup(ctx context.Context, c *app.RequestContext) {
+	result, err := h.chipService.Lookup(ctx, c.Param("code"), lookupClient(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    result,
+	})
+}
+
+// SendMessage 给宠物主人留言
+// @Summary 给宠物主人留言
+// @Description 拾获人通过芯片号给主人留言,由平台转达,不暴露主人信息
+// @Tags 芯片
+// @Accept json
+// @Produce json
+// @Param code path string true "15位芯片号"
+// @Param request body model.SendFoundMessageRequest true "留言"
+// @Success 200 {object} utils.H
+// @Router /api/v1/microchips/{code}/messages [post]
+func (h *MicrochipHandler) SendMessage(ctx context.Context, c *app.RequestContext) {
+	var req model.SendFoundMessageRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "拾获留言参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.chipService.SendMessage(ctx, c.Param("code"), lookupClient(c), &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "留言已转达给宠物主人",
+	})
+}
+
+// ListLookups 获取芯片查询记录
+// @Summary 获取芯片查询记录
+// @Description 宠物成员查看芯片被查询的审计记录
+// @Tags 芯片
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/microchip-lookups [get]
+func (h *MicrochipHandler) ListLookups(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListChipRecordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	lookups, total, err := h.chipService.ListLookups(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      lookups,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListMessages 获取拾获留言
+// @Summary 获取拾获留言
+// @Description 宠物成员查看拾获人的留言,查看后标记为已读
+// @Tags 芯片
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/found-messages [get]
+func (h *MicrochipHandler) ListMessages(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListChipRecordRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	messages, total, err := h.chipService.ListMessages(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      messages,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// lookupClient 提取查询方信息用于审计
+func lookupClient(c *app.RequestContext) *model.LookupClient {
+	return &model.LookupClient{
+		UserID:    middleware.GetUserID(c),
+		ClientIP:  c.ClientIP(),
+		UserAgent: string(c.UserAgent()),
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+	"pet-service/pkg/sse"
+)
+
+// notificationHeartbeat 事件流心跳间隔,用于保活并及时发现已断开的连接
+const notificationHeartbeat = 25 * time.Second
+
+// NotificationHandler 消息通知处理器
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler 创建消息通知处理器
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// ListNotifications 我的通知
+// @Summary 我的通知
+// @Description 按时间倒序获取通知,unread=true时只返回未读通知,返回结果附带未读总数
+// @Tags 消息通知
+// @Produce json
+// @Param unread query bool false "只看未读"
+// @Param type query string false "通知类型"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/notifications [get]
+func (h *NotificationHandler) ListNotifications(ctx context.Context, c *app.RequestContext) {
+	var req model.ListNotificationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取通知列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.notificationService.List(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// MarkRead 标记通知已读
+// @Summary 标记通知已读
+// @Description 标记单条通知为已读
+// @Tags 消息通知
+// @Produce json
+// @Param id path int true "通知ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "通知ID")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已读",
+	})
+}
+
+// MarkAllRead 全部标记已读
+// @Summary 全部标记已读
+// @Description 标记当前用户的全部未读通知为已读,可按类型限定
+// @Tags 消息通知
+// @Accept json
+// @Produce json
+// @Param request body model.MarkAllNotificationsReadRequest false "通知类型"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/notifications/read-all [put]
+func (h *NotificationHandler) MarkAllRead(ctx context.Context, c *app.RequestContext) {
+	var req model.MarkAllNotificationsReadRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "全部已读参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	count, err := h.notificationService.MarkAllRead(ctx, middleware.GetUserID(c), req.Type)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已读",
+		"data":    utils.H{"count": count},
+	})
+}
+
+// GetPreferences 通知偏好
+// @Summary 通知偏好
+// @Description 获取每种通知类型开启的投递渠道,inbox为站内信,push为外部推送
+// @Tags 消息通知
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(ctx context.Context, c *app.RequestContext) {
+	preferences, err := h.notificationService.GetPreferences(ctx, middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    preferences,
+	})
+}
+
+// UpdatePreferences 更新通知偏好
+// @Summary 更新通知偏好
+// @Description 设置通知类型的投递渠道,channels为空数组表示不接收该类通知,未提交的类型保持不变
+// @Tags 消息通知
+// @Accept json
+// @Produce json
+// @Param request body model.UpdateNotificationPreferencesRequest true "各类型的渠道设置"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(ctx context.Context, c *app.RequestContext) {
+	var req model.UpdateNotificationPreferencesRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新通知偏好参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "保存成功",
+		"data":    preferences,
+	})
+}
+
+// Stream 实时通知
+// @Summary 实时通知
+// @Description Server-Sent Events事件流,新通知以notification事件推送,事件id为通知ID。
+// @Description 断线重连时浏览器自动携带Last-Event-ID请求头,服务端补发该ID之后的通知;EventSource无法设置请求头,可通过token查询参数认证
+// @Tags 消息通知
+// @Produce text/event-stream
+// @Param token query string false "JWT token"
+// @Param Last-Event-ID header string false "最后收到的通知ID"
+// @Success 200
+// @Router /api/v1/me/notifications/stream [get]
+func (h *NotificationHandler) Stream(ctx context.Context, c *app.RequestContext) {
+	userID := middleware.GetUserID(c)
+	lastID, _ := strconv.ParseUint(string(c.GetHeader("Last-Event-ID")), 10, 64)
+
+	subscription, err := h.notificationService.Subscribe(ctx, userID, uint(lastID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	defer subscription.Close()
+
+	stream := sse.NewStream(c)
+	if err := stream.Retry(3000); err != nil {
+		return
+	}
+
+	// 补发与实时推送可能重叠,只发送ID更大的通知
+	sentID := uint(lastID)
+	send := func(n *model.Notification) error {
+		if n.ID <= sentID {
+			return nil
+		}
+		sentID = n.ID
+		return stream.JSON(n.ID, "notification", n)
+	}
+	for _, n := range subscription.Backlog {
+		if err := send(n); err != nil {
+			return
+		}
+	}
+
+	logger.Info(ctx, "通知事件流已建立", logger.Int("user_id", int(userID)))
+	ticker := time.NewTicker(notificationHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-subscription.C:
+			err = send(n)
+		case <-ticker.C:
+			err = stream.Comment("ping")
+		}
+		if err != nil {
+			logger.Info(ctx, "通知事件流已断开", logger.Int("user_id", int(userID)), logger.String("reason", err.Error()))
+			return
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+	"pet-service/pkg/payment"
+)
+
+// OrderHandler 订单与支付处理器
+type OrderHandler struct {
+	orderService service.OrderService
+}
+
+// NewOrderHandler 创建订单与支付处理器
+func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+// CreateOrder 创建订单
+// @Summary 创建订单
+// @Description 由商城结算的库存预占或已接受的寄养预约创建待支付订单,超时未支付自动取消
+// @Tags 订单
+// @Accept json
+// @Produce json
+// @Param request body model.CreateOrderRequest true "订单来源"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders [post]
+func (h *OrderHandler) CreateOrder(ctx context.Context, c *app.RequestContext) {
+	var req model.CreateOrderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建订单参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.orderService.CreateOrder(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "下单成功",
+		"data":    order,
+	})
+}
+
+// ListMyOrders 我的订单
+// @Summary 我的订单
+// @Description 获取当前用户的订单
+// @Tags 订单
+// @Produce json
+// @Param status query string false "状态:pending,paid,fulfilled,refunded,cancelled"
+// @Param type query string false "类型:shop,sitter_booking"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/orders [get]
+func (h *OrderHandler) ListMyOrders(ctx context.Context, c *app.RequestContext) {
+	var req model.ListOrderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取订单列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	orders, total, err := h.orderService.ListMyOrders(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      orders,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetOrder 订单详情
+// @Summary 订单详情
+// @Description 获取本人订单详情,包含明细和退款记录
+// @Tags 订单
+// @Produce json
+// @Param id path int true "订单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders/{id} [get]
+func (h *OrderHandler) GetOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.GetOrder(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    order,
+	})
+}
+
+// PayOrder 发起支付
+// @Summary 发起支付
+// @Description 为待支付订单创建网关交易,返回支付参数
+// @Tags 订单
+// @Produce json
+// @Param id path int true "订单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders/{id}/pay [post]
+func (h *OrderHandler) PayOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	prepay, err := h.orderService.Pay(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    prepay,
+	})
+}
+
+// CancelOrder 取消订单
+// @Summary 取消订单
+// @Description 取消待支付订单,商城订单归还库存
+// @Tags 订单
+// @Accept json
+// @Produce json
+// @Param id path int true "订单ID"
+// @Param request body model.CancelOrderRequest false "取消原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders/{id}/cancel [put]
+func (h *OrderHandler) CancelOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	var req model.CancelOrderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "取消订单参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(ctx, middleware.GetUserID(c), id, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消",
+		"data":    order,
+	})
+}
+
+// ConfirmOrder 确认完成
+// @Summary 确认完成
+// @Description 用户确认收货或服务完成
+// @Tags 订单
+// @Produce json
+// @Param id path int true "订单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders/{id}/confirm [put]
+func (h *OrderHandler) ConfirmOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.ConfirmOrder(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已确认",
+		"data":    order,
+	})
+}
+
+// RequestRefund 申请退款
+// @Summary 申请退款
+// @Description 已支付未完成的订单可自助全额退款
+// @Tags 订单
+// @Accept json
+// @Produce json
+// @Param id path int true "订单ID"
+// @Param request body model.CancelOrderRequest false "退款原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders/{id}/refund [post]
+func (h *OrderHandler) RequestRefund(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	var req model.CancelOrderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "申请退款参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.orderService.RequestRefund(ctx, middleware.GetUserID(c), id, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "退款成功",
+		"data":    order,
+	})
+}
+
+// SimulatePayment 模拟支付
+// @Summary 模拟支付
+// @Description 使用本地模拟网关生成签名回调完成支付,仅PAYMENT_DRIVER=fake且PAYMENT_ALLOW_SIMULATE=true时注册,用于开发测试
+// @Tags 订单
+// @Produce json
+// @Param id path int true "订单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/orders/{id}/simulate-payment [post]
+func (h *OrderHandler) SimulatePayment(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.SimulatePayment(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "支付成功",
+		"data":    order,
+	})
+}
+
+// PaymentCallback 支付网关回调
+// @Summary 支付网关回调
+// @Description 网关异步通知支付/退款结果,签名放在X-Pay-Signature请求头,同一事件重复通知只处理一次
+// @Tags 订单
+// @Accept json
+// @Produce json
+// @Param gateway path string true "网关名称"
+// @Success 200 {object} utils.H
+// @Router /api/v1/payments/{gateway}/callback [post]
+func (h *OrderHandler) PaymentCallback(ctx context.Context, c *app.RequestContext) {
+	gateway := c.Param("gateway")
+	signature := string(c.GetHeader("X-Pay-Signature"))
+
+	err := h.orderService.HandleCallback(ctx, gateway, c.Request.Body(), signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			c.JSON(consts.StatusBadRequest, utils.H{
+				"code":    400,
+				"message": err.Error(),
+			})
+			return
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "success",
+	})
+}
+
+// AdminListOrders 管理端订单列表
+// @Summary 管理端订单列表
+// @Description 检索全部订单
+// @Tags 订单管理
+// @Produce json
+// @Param status query string false "状态"
+// @Param type query string false "类型"
+// @Param user_id query int false "用户ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/orders [get]
+func (h *OrderHandler) AdminListOrders(ctx context.Context, c *app.RequestContext) {
+	var req model.ListOrderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取订单列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	orders, total, err := h.orderService.AdminListOrders(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      orders,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// AdminGetOrder 管理端订单详情
+// @Summary 管理端订单详情
+// @Description 获取任意订单详情
+// @Tags 订单管理
+// @Produce json
+// @Param id path int true "订单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/orders/{id} [get]
+func (h *OrderHandler) AdminGetOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.AdminGetOrder(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    order,
+	})
+}
+
+// FulfillOrder 完成订单
+// @Summary 完成订单
+// @Description 已支付订单发货/服务完成
+// @Tags 订单管理
+// @Produce json
+// @Param id path int true "订单ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/orders/{id}/fulfill [put]
+func (h *OrderHandler) FulfillOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.FulfillOrder(ctx, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "操作成功",
+		"data":    order,
+	})
+}
+
+// RefundOrder 订单退款
+// @Summary 订单退款
+// @Description 对已支付或已完成的订单退款,支持部分退款,全额退款后订单变为已退款
+// @Tags 订单管理
+// @Accept json
+// @Produce json
+// @Param id path int true "订单ID"
+// @Param request body model.RefundOrderRequest true "退款金额与原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/orders/{id}/refunds [post]
+func (h *OrderHandler) RefundOrder(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "订单ID")
+	if !ok {
+		return
+	}
+
+	var req model.RefundOrderRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "订单退款参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.orderService.RefundOrder(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "退款成功",
+		"data":    order,
+	})
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// PetHandler 宠物处理器
+type PetHandler struct {
+	petService service.PetService
+}
+
+// NewPetHandler 创建宠物处理器
+func NewPetHandler(petService service.PetService) *PetHandler {
+	return &PetHandler{petService: petService}
+}
+
+// CreatePet 创建宠物
+// @Summary 创建宠物
+// @Description 为当前用户创建宠物档案
+// @Tags 宠物
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePetRequest true "创建宠物请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets [post]
+func (h *PetHandler) CreatePet(ctx context.Context, c *app.RequestContext) {
+	var req model.CreatePetRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建宠物参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	pet, err := h.petService.CreatePet(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    pet,
+	})
+}
+
+// UpdatePet 更新宠物
+// @Summary 更新宠物
+// @Description 更新宠物档案
+// @Tags 宠物
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.UpdatePetRequest true "更新宠物请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id} [put]
+func (h *PetHandler) UpdatePet(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdatePetRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新宠物参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	pet, err := h.petService.UpdatePet(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    pet,
+	})
+}
+
+// DeletePet 删除宠物
+// @Summary 删除宠物
+// @Description 删除宠物档案
+// @Tags 宠物
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id} [delete]
+func (h *PetHandler) DeletePet(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	if err := h.petService.DeletePet(ctx, middleware.GetUserID(c), petID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// GetPet 获取宠物详情
+// @Summary 获取宠物详情
+// @Description 根据ID获取宠物详情
+// @Tags 宠物
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id} [get]
+func (h *PetHandler) GetPet(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	pet, err := h.petService.GetPet(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    pet,
+	})
+}
+
+// ListMyPets 获取我的宠物列表
+// @Summary 获取我的宠物列表
+// @Description 获取当前用户的宠物列表
+// @Tags 宠物
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param species query string false "物种"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets [get]
+func (h *PetHandler) ListMyPets(ctx context.Context, c *app.RequestContext) {
+	var req model.ListPetRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取宠物列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	pets, total, err := h.petService.ListMyPets(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      pets,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// PetProfileHandler 宠物公开主页处理器
+type PetProfileHandler struct {
+	profileService service.PetProfileService
+}
+
+// NewPetProfileHandler 创建宠物公开主页处理器
+func NewPetProfileHandler(profileService service.PetProfileService) *PetProfileHandler {
+	return &PetProfileHandler{profileService: profileService}
+}
+
+// GetSettings 获取公开主页设置
+// @Summary 获取公开主页设置
+// @Description 获取宠物公开主页的开关、展示字段和访问链接
+// @Tags 公开主页
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/profile [get]
+func (h *PetProfileHandler) GetSettings(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	profile, err := h.profileService.GetSettings(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    profile,
+	})
+}
+
+// UpdateSettings 更新公开主页设置
+// @Summary 更新公开主页设置
+// @Description 开启/关闭公开主页,设置名字、照片、医疗提示、主人联系方式是否公开
+// @Tags 公开主页
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.UpdatePetProfileRequest true "主页设置"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/profile [put]
+func (h *PetProfileHandler) UpdateSettings(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdatePetProfileRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新公开主页参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	profile, err := h.profileService.UpdateSettings(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    profile,
+	})
+}
+
+// RegenerateSlug 重新生成主页链接
+// @Summary 重新生成主页链接
+// @Description 重新生成slug,旧链接和已打印的二维码失效
+// @Tags 公开主页
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/profile/slug [post]
+func (h *PetProfileHandler) RegenerateSlug(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	profile, err := h.profileService.RegenerateSlug(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "操作成功",
+		"data":    profile,
+	})
+}
+
+// QRCode 生成主页二维码
+// @Summary 生成主页二维码
+// @Description 生成指向公开主页的二维码,用于打印项圈吊牌
+// @Tags 公开主页
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path int true "宠物ID"
+// @Param format query string false "格式:png,svg"
+// @Param size query int false "尺寸(像素),64-2048,默认256"
+// @Router /api/v1/pets/{id}/profile/qrcode [get]
+func (h *PetProfileHandler) QRCode(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.QRCodeRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	data, contentType, err := h.profileService.QRCode(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(consts.StatusOK, contentType, data)
+}
+
+// GetPublic 查看宠物公开主页
+// @Summary 查看宠物公开主页
+// @Description 扫描吊牌二维码访问,只返回主人允许公开的信息
+// @Tags 公开主页
+// @Produce json
+// @Param slug path string true "主页标识"
+// @Success 200 {object} utils.H
+// @Router /p/{slug} [get]
+func (h *PetProfileHandler) GetPublic(ctx context.Context, c *app.RequestContext) {
+	profile, err := h.profileService.GetPublic(ctx, c.Param("slug"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    profile,
+	})
+}
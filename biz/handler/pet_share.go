@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// PetShareHandler 宠物共享处理器
+type PetShareHandler struct {
+	shareService service.PetShareService
+}
+
+// NewPetShareHandler 创建宠物共享处理器
+func NewPetShareHandler(shareService service.PetShareService) *PetShareHandler {
+	return &PetShareHandler{shareService: shareService}
+}
+
+// Invite 邀请共享宠物
+// @Summary 邀请共享宠物
+// @Description 通过用户名或邮箱邀请家庭成员,授予viewer/editor/owner角色
+// @Tags 宠物共享
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.CreateInvitationRequest true "邀请信息"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/invitations [post]
+func (h *PetShareHandler) Invite(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateInvitationRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "邀请共享宠物参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	invitation, err := h.shareService.Invite(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "邀请已发送",
+		"data":    invitation,
+	})
+}
+
+// ListPetInvitations 获取宠物发出的邀请
+// @Summary 获取宠物发出的邀请
+// @Description 宠物owner查看已发出的共享邀请
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/invitations [get]
+func (h *PetShareHandler) ListPetInvitations(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListShareRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	invitations, err := h.shareService.ListPetInvitations(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    invitations,
+	})
+}
+
+// ListMyInvitations 获取我收到的邀请
+// @Summary 获取我收到的邀请
+// @Description 获取当前用户收到的宠物共享邀请
+// @Tags 宠物共享
+// @Produce json
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/pet-invitations [get]
+func (h *PetShareHandler) ListMyInvitations(ctx context.Context, c *app.RequestContext) {
+	var req model.ListShareRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	invitations, err := h.shareService.ListMyInvitations(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    invitations,
+	})
+}
+
+// AcceptInvitation 接受邀请
+// @Summary 接受邀请
+// @Description 被邀请人接受宠物共享邀请
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "邀请ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pet-invitations/{id}/accept [put]
+func (h *PetShareHandler) AcceptInvitation(ctx context.Context, c *app.RequestContext) {
+	h.handleInvitation(ctx, c, h.shareService.AcceptInvitation)
+}
+
+// DeclineInvitation 拒绝邀请
+// @Summary 拒绝邀请
+// @Description 被邀请人拒绝宠物共享邀请
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "邀请ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pet-invitations/{id}/decline [put]
+func (h *PetShareHandler) DeclineInvitation(ctx context.Context, c *app.RequestContext) {
+	h.handleInvitation(ctx, c, h.shareService.DeclineInvitation)
+}
+
+// CancelInvitation 撤回邀请
+// @Summary 撤回邀请
+// @Description 邀请人或宠物owner撤回待处理的邀请
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "邀请ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pet-invitations/{id} [delete]
+func (h *PetShareHandler) CancelInvitation(ctx context.Context, c *app.RequestContext) {
+	h.handleInvitation(ctx, c, h.shareService.CancelInvitation)
+}
+
+// handleInvitation 处理邀请的通用流程
+func (h *PetShareHandler) handleInvitation(ctx context.Context, c *app.RequestContext, action func(ctx context.Context, userID, invitationID uint) error) {
+	invitationID, ok := parseIDParam(c, "id", "邀请ID")
+	if !ok {
+		return
+	}
+
+	if err := action(ctx, middleware.GetUserID(c), invitationID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "操作成功",
+	})
+}
+
+// ListMembers 获取宠物共享成员
+// @Summary 获取宠物共享成员
+// @Description 获取宠物的共享成员及角色
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/members [get]
+func (h *PetShareHandler) ListMembers(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	members, err := h.shareService.ListMembers(ctx, middleware.GetUserID(c), petID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    members,
+	})
+}
+
+// UpdateMemberRole 修改成员角色
+// @Summary 修改成员角色
+// @Description 宠物owner修改共享成员的角色
+// @Tags 宠物共享
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param uid path int true "成员用户ID"
+// @Param request body model.UpdateMemberRoleRequest true "角色"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/members/{uid} [put]
+func (h *PetShareHandler) UpdateMemberRole(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+	memberUserID, ok := parseIDParam(c, "uid", "成员用户ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateMemberRoleRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.shareService.UpdateMemberRole(ctx, middleware.GetUserID(c), petID, memberUserID, &req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "修改成功",
+	})
+}
+
+// RemoveMember 移除成员
+// @Summary 移除成员
+// @Description 宠物owner移除共享成员,成员也可移除自己以退出共享
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param uid path int true "成员用户ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/members/{uid} [delete]
+func (h *PetShareHandler) RemoveMember(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+	memberUserID, ok := parseIDParam(c, "uid", "成员用户ID")
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.RemoveMember(ctx, middleware.GetUserID(c), petID, memberUserID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "移除成功",
+	})
+}
+
+// RequestTransfer 发起主人转让
+// @Summary 发起主人转让
+// @Description 主人指定接收方发起转让,或owner角色的共同主人申请成为主人,需双方确认
+// @Tags 宠物共享
+// @Accept json
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param request body model.CreateTransferRequest true "接收方"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/transfers [post]
+func (h *PetShareHandler) RequestTransfer(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateTransferRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发起主人转让参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	transfer, err := h.shareService.RequestTransfer(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "转让已发起,等待对方确认",
+		"data":    transfer,
+	})
+}
+
+// ConfirmTransfer 确认主人转让
+// @Summary 确认主人转让
+// @Description 转让双方确认,双方均确认后宠物主人变更
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "转让ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pet-transfers/{id}/confirm [put]
+func (h *PetShareHandler) ConfirmTransfer(ctx context.Context, c *app.RequestContext) {
+	transferID, ok := parseIDParam(c, "id", "转让ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := h.shareService.ConfirmTransfer(ctx, middleware.GetUserID(c), transferID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "确认成功",
+		"data":    transfer,
+	})
+}
+
+// DeclineTransfer 拒绝或撤回主人转让
+// @Summary 拒绝或撤回主人转让
+// @Description 转让任一方可拒绝或撤回进行中的转让
+// @Tags 宠物共享
+// @Produce json
+// @Param id path int true "转让ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pet-transfers/{id}/decline [put]
+func (h *PetShareHandler) DeclineTransfer(ctx context.Context, c *app.RequestContext) {
+	transferID, ok := parseIDParam(c, "id", "转让ID")
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.DeclineTransfer(ctx, middleware.GetUserID(c), transferID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "操作成功",
+	})
+}
+
+// ListMyTransfers 获取我参与的主人转让
+// @Summary 获取我参与的主人转让
+// @Description 获取当前用户作为转出方或接收方的转让
+// @Tags 宠物共享
+// @Produce json
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/pet-transfers [get]
+func (h *PetShareHandler) ListMyTransfers(ctx context.Context, c *app.RequestContext) {
+	var req model.ListShareRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	transfers, err := h.shareService.ListMyTransfers(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    transfers,
+	})
+}
@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// PostHandler 社区动态处理器
+type PostHandler struct {
+	postService service.PostService
+}
+
+// NewPostHandler 创建社区动态处理器
+func NewPostHandler(postService service.PostService) *PostHandler {
+	return &PostHandler{postService: postService}
+}
+
+// CreatePost 发布动态
+// @Summary 发布动态
+// @Description 发布文字和照片,可标记自己有编辑权限的宠物,照片需先通过文件上传接口上传
+// @Tags 社区
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePostRequest true "动态内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts [post]
+func (h *PostHandler) CreatePost(ctx context.Context, c *app.RequestContext) {
+	var req model.CreatePostRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发布动态参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	post, err := h.postService.CreatePost(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "发布成功",
+		"data":    post,
+	})
+}
+
+// ListPosts 动态广场
+// @Summary 动态广场
+// @Description 按发布时间倒序浏览动态,不含互相拉黑的用户的动态,使用上一页返回的next_cursor翻页
+// @Tags 社区
+// @Produce json
+// @Param user_id query int false "发布人用户ID"
+// @Param pet_id query int false "标记的宠物ID"
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts [get]
+func (h *PostHandler) ListPosts(ctx context.Context, c *app.RequestContext) {
+	var req model.ListPostRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取动态列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.postService.ListPosts(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// Timeline 我的时间线
+// @Summary 我的时间线
+// @Description 自己、关注用户以及标记了关注宠物的动态,按发布时间倒序,使用next_cursor翻页
+// @Tags 社区
+// @Produce json
+// @Param cursor query int false "游标"
+// @Param limit query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/timeline [get]
+func (h *PostHandler) Timeline(ctx context.Context, c *app.RequestContext) {
+	var req model.ListPostRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取时间线参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	page, err := h.postService.Timeline(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    page,
+	})
+}
+
+// GetPost 动态详情
+// @Summary 动态详情
+// @Tags 社区
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts/{id} [get]
+func (h *PostHandler) GetPost(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "动态ID")
+	if !ok {
+		return
+	}
+
+	post, err := h.postService.GetPost(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    post,
+	})
+}
+
+// DeletePost 删除动态
+// @Summary 删除动态
+// @Tags 社区
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts/{id} [delete]
+func (h *PostHandler) DeletePost(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "动态ID")
+	if !ok {
+		return
+	}
+
+	if err := h.postService.DeletePost(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// LikePost 点赞
+// @Summary 点赞
+// @Description 重复点赞不会重复计数
+// @Tags 社区
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts/{id}/like [put]
+func (h *PostHandler) LikePost(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "动态ID")
+	if !ok {
+		return
+	}
+
+	result, err := h.postService.LikePost(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "点赞成功",
+		"data":    result,
+	})
+}
+
+// UnlikePost 取消点赞
+// @Summary 取消点赞
+// @Description 未点赞时直接返回成功
+// @Tags 社区
+// @Produce json
+// @Param id path int true "动态ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts/{id}/like [delete]
+func (h *PostHandler) UnlikePost(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "动态ID")
+	if !ok {
+		return
+	}
+
+	result, err := h.postService.UnlikePost(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消点赞",
+		"data":    result,
+	})
+}
+
+// CreateComment 发表评论
+// @Summary 发表评论
+// @Description parent_id为评论ID时表示回复,回复只有一层,回复某条回复时挂在同一条一级评论下
+// @Tags 社区
+// @Accept json
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param request body model.CreateCommentRequest true "评论内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts/{id}/comments [post]
+func (h *PostHandler) CreateComment(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "动态ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发表评论参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	comment, err := h.postService.CreateComment(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "评论成功",
+		"data":    comment,
+	})
+}
+
+// ListComments 评论列表
+// @Summary 评论列表
+// @Description 一级评论按时间正序,每条附带最早的3条回复,不含互相拉黑的用户的评论
+// @Tags 社区
+// @Produce json
+// @Param id path int true "动态ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/posts/{id}/comments [get]
+func (h *PostHandler) ListComments(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "动态ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListCommentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取评论列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	comments, total, err := h.postService.ListComments(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      comments,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListReplies 评论回复列表
+// @Summary 评论回复列表
+// @Tags 社区
+// @Produce json
+// @Param id path int true "一级评论ID"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/comments/{id}/replies [get]
+func (h *PostHandler) ListReplies(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "评论ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListCommentRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取回复列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	replies, total, err := h.postService.ListReplies(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      replies,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 评论人或动态发布人可删除,删除一级评论会同时删除其回复
+// @Tags 社区
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/comments/{id} [delete]
+func (h *PostHandler) DeleteComment(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "评论ID")
+	if !ok {
+		return
+	}
+
+	if err := h.postService.DeleteComment(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
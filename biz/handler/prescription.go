@@ -0,0 +1,518 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// PrescriptionHandler 处方与配药处理器
+type PrescriptionHandler struct {
+	prescriptionService service.PrescriptionService
+}
+
+// NewPrescriptionHandler 创建处方与配药处理器
+func NewPrescriptionHandler(prescriptionService service.PrescriptionService) *PrescriptionHandler {
+	return &PrescriptionHandler{prescriptionService: prescriptionService}
+}
+
+// CreatePrescription 开具处方
+// @Summary 开具处方
+// @Description 诊所执业兽医为就诊记录开具处方草稿,兽医需能查看该宠物(由主人共享);签发前可修改
+// @Tags 处方
+// @Accept json
+// @Produce json
+// @Param id path int true "就诊记录ID"
+// @Param request body model.SavePrescriptionRequest true "处方内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/medical-records/{id}/prescriptions [post]
+func (h *PrescriptionHandler) CreatePrescription(ctx context.Context, c *app.RequestContext) {
+	recordID, ok := parseIDParam(c, "id", "就诊记录ID")
+	if !ok {
+		return
+	}
+
+	var req model.SavePrescriptionRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "开具处方参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	prescription, err := h.prescriptionService.CreatePrescription(ctx, middleware.GetUserID(c), recordID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    prescription,
+	})
+}
+
+// UpdatePrescription 修改处方草稿
+// @Summary 修改处方草稿
+// @Description 开方兽医修改未签发的处方,clinic_id不可修改
+// @Tags 处方
+// @Accept json
+// @Produce json
+// @Param id path int true "处方ID"
+// @Param request body model.SavePrescriptionRequest true "处方内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id} [put]
+func (h *PrescriptionHandler) UpdatePrescription(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	var req model.SavePrescriptionRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改处方参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	prescription, err := h.prescriptionService.UpdatePrescription(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    prescription,
+	})
+}
+
+// DeletePrescription 删除处方草稿
+// @Summary 删除处方草稿
+// @Description 开方兽医删除未签发的处方,已签发的处方只能作废
+// @Tags 处方
+// @Produce json
+// @Param id path int true "处方ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id} [delete]
+func (h *PrescriptionHandler) DeletePrescription(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	if err := h.prescriptionService.DeletePrescription(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
+
+// SignPrescription 签发处方
+// @Summary 签发处方
+// @Description 开方兽医签发处方,签发后内容不可修改,并通知宠物主人
+// @Tags 处方
+// @Produce json
+// @Param id path int true "处方ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id}/sign [put]
+func (h *PrescriptionHandler) SignPrescription(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	prescription, err := h.prescriptionService.SignPrescription(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "签发成功",
+		"data":    prescription,
+	})
+}
+
+// RevokePrescription 作废处方
+// @Summary 作废处方
+// @Description 诊所执业兽医作废已签发的处方,待处理的配药申请自动取消
+// @Tags 处方
+// @Accept json
+// @Produce json
+// @Param id path int true "处方ID"
+// @Param request body model.RevokePrescriptionRequest true "作废原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id}/revoke [put]
+func (h *PrescriptionHandler) RevokePrescription(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	var req model.RevokePrescriptionRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "作废处方参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	prescription, err := h.prescriptionService.RevokePrescription(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已作废",
+		"data":    prescription,
+	})
+}
+
+// GetPrescription 处方详情
+// @Summary 处方详情
+// @Description 宠物成员可查看已签发或作废的处方,诊所人员可查看本诊所的全部处方
+// @Tags 处方
+// @Produce json
+// @Param id path int true "处方ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id} [get]
+func (h *PrescriptionHandler) GetPrescription(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	prescription, err := h.prescriptionService.GetPrescription(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    prescription,
+	})
+}
+
+// ListPetPrescriptions 宠物的处方
+// @Summary 宠物的处方
+// @Description 宠物已签发或作废的处方,可按就诊记录和状态筛选
+// @Tags 处方
+// @Produce json
+// @Param id path int true "宠物ID"
+// @Param medical_record_id query int false "就诊记录ID"
+// @Param status query string false "状态:signed,revoked"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/pets/{id}/prescriptions [get]
+func (h *PrescriptionHandler) ListPetPrescriptions(ctx context.Context, c *app.RequestContext) {
+	petID, ok := parseIDParam(c, "id", "宠物ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListPrescriptionRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取处方列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	prescriptions, total, err := h.prescriptionService.ListPetPrescriptions(ctx, middleware.GetUserID(c), petID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      prescriptions,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ListClinicPrescriptions 诊所开具的处方
+// @Summary 诊所开具的处方
+// @Description 诊所人员查看本诊所开具的处方,含草稿
+// @Tags 处方
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param medical_record_id query int false "就诊记录ID"
+// @Param status query string false "状态:draft,signed,revoked"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/prescriptions [get]
+func (h *PrescriptionHandler) ListClinicPrescriptions(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListPrescriptionRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取处方列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	prescriptions, total, err := h.prescriptionService.ListClinicPrescriptions(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      prescriptions,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// RequestRefill 申请配药
+// @Summary 申请配药
+// @Description 宠物主人或编辑者为已签发的处方申请配药,可选择开方诊所药房(clinic)或商城药房(shop);同一处方同时只能有一个待处理的申请
+// @Tags 处方
+// @Accept json
+// @Produce json
+// @Param id path int true "处方ID"
+// @Param request body model.CreateRefillRequest true "配药渠道"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id}/refills [post]
+func (h *PrescriptionHandler) RequestRefill(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateRefillRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "申请配药参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	refill, err := h.prescriptionService.RequestRefill(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "配药申请已提交",
+		"data":    refill,
+	})
+}
+
+// ListRefills 处方的配药记录
+// @Summary 处方的配药记录
+// @Description 处方的全部配药申请及处理结果
+// @Tags 处方
+// @Produce json
+// @Param id path int true "处方ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescriptions/{id}/refills [get]
+func (h *PrescriptionHandler) ListRefills(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "处方ID")
+	if !ok {
+		return
+	}
+
+	refills, err := h.prescriptionService.ListRefills(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    refills,
+	})
+}
+
+// CancelRefill 取消配药申请
+// @Summary 取消配药申请
+// @Description 宠物主人或编辑者取消待处理的配药申请
+// @Tags 处方
+// @Produce json
+// @Param id path int true "配药申请ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescription-refills/{id}/cancel [put]
+func (h *PrescriptionHandler) CancelRefill(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "配药申请ID")
+	if !ok {
+		return
+	}
+
+	refill, err := h.prescriptionService.CancelRefill(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消",
+		"data":    refill,
+	})
+}
+
+// ListClinicRefills 诊所药房的配药申请
+// @Summary 诊所药房的配药申请
+// @Description 诊所人员查看选择在本诊所配药的申请,按提交时间排序
+// @Tags 处方
+// @Produce json
+// @Param id path int true "诊所ID"
+// @Param status query string false "状态:pending,fulfilled,declined,cancelled"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/clinics/{id}/prescription-refills [get]
+func (h *PrescriptionHandler) ListClinicRefills(ctx context.Context, c *app.RequestContext) {
+	clinicID, ok := parseIDParam(c, "id", "诊所ID")
+	if !ok {
+		return
+	}
+
+	var req model.ListRefillRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取配药申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	refills, total, err := h.prescriptionService.ListClinicRefills(ctx, middleware.GetUserID(c), clinicID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      refills,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// HandleClinicRefill 诊所药房处理配药申请
+// @Summary 诊所药房处理配药申请
+// @Description 诊所人员完成或拒绝配药申请,完成时扣减处方剩余续配次数,拒绝时需填写原因
+// @Tags 处方
+// @Accept json
+// @Produce json
+// @Param id path int true "配药申请ID"
+// @Param request body model.HandleRefillRequest true "处理结果"
+// @Success 200 {object} utils.H
+// @Router /api/v1/prescription-refills/{id}/status [put]
+func (h *PrescriptionHandler) HandleClinicRefill(ctx context.Context, c *app.RequestContext) {
+	h.handleRefill(ctx, c, model.FillChannelClinic)
+}
+
+// AdminListRefills 商城药房的配药申请
+// @Summary 商城药房的配药申请
+// @Description 需要 prescription:fill 权限,按提交时间排序
+// @Tags 处方
+// @Produce json
+// @Param status query string false "状态:pending,fulfilled,declined,cancelled"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/prescription-refills [get]
+func (h *PrescriptionHandler) AdminListRefills(ctx context.Context, c *app.RequestContext) {
+	var req model.ListRefillRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取配药申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	refills, total, err := h.prescriptionService.ListShopRefills(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      refills,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// AdminHandleRefill 商城药房处理配药申请
+// @Summary 商城药房处理配药申请
+// @Description 需要 prescription:fill 权限,完成时扣减处方剩余续配次数,拒绝时需填写原因
+// @Tags 处方
+// @Accept json
+// @Produce json
+// @Param id path int true "配药申请ID"
+// @Param request body model.HandleRefillRequest true "处理结果"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/prescription-refills/{id}/status [put]
+func (h *PrescriptionHandler) AdminHandleRefill(ctx context.Context, c *app.RequestContext) {
+	h.handleRefill(ctx, c, model.FillChannelShop)
+}
+
+func (h *PrescriptionHandler) handleRefill(ctx context.Context, c *app.RequestContext, channel string) {
+	id, ok := parseIDParam(c, "id", "配药申请ID")
+	if !ok {
+		return
+	}
+
+	var req model.HandleRefillRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "处理配药申请参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	refill, err := h.prescriptionService.HandleRefill(ctx, middleware.GetUserID(c), id, channel, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    refill,
+	})
+}
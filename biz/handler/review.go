@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// ReviewHandler 评价处理器
+type ReviewHandler struct {
+	reviewService service.ReviewService
+}
+
+// NewReviewHandler 创建评价处理器
+func NewReviewHandler(reviewService service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// CreateReview 发表评价
+// @Summary 发表评价
+// @Description 对已完成的寄养、门诊预约发表1-5星评价,source_type为sitter_booking、appointment(诊所)或appointment_vet(接诊兽医),每个来源只能评价一次
+// @Tags 评价
+// @Accept json
+// @Produce json
+// @Param request body model.CreateReviewRequest true "评价"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews [post]
+func (h *ReviewHandler) CreateReview(ctx context.Context, c *app.RequestContext) {
+	var req model.CreateReviewRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发表评价参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	review, err := h.reviewService.CreateReview(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "评价成功",
+		"data":    review,
+	})
+}
+
+// ListReviews 评价列表
+// @Summary 评价列表
+// @Description 获取看护人、诊所等被评价对象的公开评价
+// @Tags 评价
+// @Produce json
+// @Param target_type query string true "被评价对象类型:sitter,clinic,vet"
+// @Param target_id query int true "被评价对象ID"
+// @Param rating query int false "按星级筛选"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews [get]
+func (h *ReviewHandler) ListReviews(ctx context.Context, c *app.RequestContext) {
+	var req model.ListReviewRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取评价列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	reviews, total, err := h.reviewService.ListReviews(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      reviews,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetSummary 评分汇总
+// @Summary 评分汇总
+// @Description 获取被评价对象的平均分、评价数及各星级数量
+// @Tags 评价
+// @Produce json
+// @Param target_type query string true "被评价对象类型:sitter,clinic,vet"
+// @Param target_id query int true "被评价对象ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews/summary [get]
+func (h *ReviewHandler) GetSummary(ctx context.Context, c *app.RequestContext) {
+	var req model.RatingSummaryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取评分汇总参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	summary, err := h.reviewService.GetSummary(ctx, req.TargetType, req.TargetID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    summary,
+	})
+}
+
+// GetReview 评价详情
+// @Summary 评价详情
+// @Description 获取公开评价详情
+// @Tags 评价
+// @Produce json
+// @Param id path int true "评价ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews/{id} [get]
+func (h *ReviewHandler) GetReview(ctx context.Context, c *app.RequestContext) {
+	reviewID, ok := parseIDParam(c, "id", "评价ID")
+	if !ok {
+		return
+	}
+
+	review, err := h.reviewService.GetReview(ctx, reviewID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    review,
+	})
+}
+
+// ListMyReviews 我的评价
+// @Summary 我的评价
+// @Description 获取我发表的评价
+// @Tags 评价
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/reviews [get]
+func (h *ReviewHandler) ListMyReviews(ctx context.Context, c *app.RequestContext) {
+	var req model.ListMyReviewRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取我的评价参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	reviews, total, err := h.reviewService.ListMyReviews(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      reviews,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// ReplyReview 回复评价
+// @Summary 回复评价
+// @Description 服务方回复评价,重复回复覆盖之前的内容
+// @Tags 评价
+// @Accept json
+// @Produce json
+// @Param id path int true "评价ID"
+// @Param request body model.ReplyReviewRequest true "回复内容"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews/{id}/reply [put]
+func (h *ReviewHandler) ReplyReview(ctx context.Context, c *app.RequestContext) {
+	reviewID, ok := parseIDParam(c, "id", "评价ID")
+	if !ok {
+		return
+	}
+
+	var req model.ReplyReviewRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "回复评价参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	review, err := h.reviewService.ReplyReview(ctx, middleware.GetUserID(c), reviewID, req.Content)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "回复成功",
+		"data":    review,
+	})
+}
+
+// FlagReview 举报评价
+// @Summary 举报评价
+// @Description 举报不当评价,达到一定次数后自动隐藏
+// @Tags 评价
+// @Accept json
+// @Produce json
+// @Param id path int true "评价ID"
+// @Param request body model.FlagReviewRequest true "举报原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews/{id}/flags [post]
+func (h *ReviewHandler) FlagReview(ctx context.Context, c *app.RequestContext) {
+	reviewID, ok := parseIDParam(c, "id", "评价ID")
+	if !ok {
+		return
+	}
+
+	var req model.FlagReviewRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "举报评价参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.reviewService.FlagReview(ctx, middleware.GetUserID(c), reviewID, req.Reason); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "举报成功",
+	})
+}
+
+// DeleteReview 删除评价
+// @Summary 删除评价
+// @Description 评价人删除自己的评价
+// @Tags 评价
+// @Produce json
+// @Param id path int true "评价ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(ctx context.Context, c *app.RequestContext) {
+	reviewID, ok := parseIDParam(c, "id", "评价ID")
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(ctx, middleware.GetUserID(c), reviewID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "删除成功",
+	})
+}
@@ -0,0 +1,626 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// ShopHandler 商城处理器
+type ShopHandler struct {
+	shopService service.ShopService
+}
+
+// NewShopHandler 创建商城处理器
+func NewShopHandler(shopService service.ShopService) *ShopHandler {
+	return &ShopHandler{shopService: shopService}
+}
+
+// ListCategories 商品分类
+// @Summary 商品分类
+// @Description 获取启用的商品分类,前端按parent_id组装为两级结构
+// @Tags 商城
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/shop/categories [get]
+func (h *ShopHandler) ListCategories(ctx context.Context, c *app.RequestContext) {
+	h.listCategories(ctx, c, false)
+}
+
+// ListProducts 商品列表
+// @Summary 商品列表
+// @Description 检索已上架商品,按一级分类筛选时包含子分类
+// @Tags 商城
+// @Produce json
+// @Param category_id query int false "分类ID"
+// @Param keyword query string false "关键词"
+// @Param min_price query int false "最低价(分)"
+// @Param max_price query int false "最高价(分)"
+// @Param sort query string false "排序:newest,price_asc,price_desc"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shop/products [get]
+func (h *ShopHandler) ListProducts(ctx context.Context, c *app.RequestContext) {
+	h.listProducts(ctx, c, false)
+}
+
+// GetProduct 商品详情
+// @Summary 商品详情
+// @Description 获取已上架商品及其在售规格
+// @Tags 商城
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shop/products/{id} [get]
+func (h *ShopHandler) GetProduct(ctx context.Context, c *app.RequestContext) {
+	h.getProduct(ctx, c, false)
+}
+
+// GetCart 我的购物车
+// @Summary 我的购物车
+// @Description 获取购物车,价格和库存按最新商品信息计算
+// @Tags 商城
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/cart [get]
+func (h *ShopHandler) GetCart(ctx context.Context, c *app.RequestContext) {
+	cart, err := h.shopService.GetCart(ctx, middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    cart,
+	})
+}
+
+// SetCartItem 设置购物车商品数量
+// @Summary 设置购物车商品数量
+// @Description 加入购物车或修改数量,数量为0时移除
+// @Tags 商城
+// @Accept json
+// @Produce json
+// @Param sku_id path int true "规格ID"
+// @Param request body model.UpdateCartItemRequest true "数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/cart/items/{sku_id} [put]
+func (h *ShopHandler) SetCartItem(ctx context.Context, c *app.RequestContext) {
+	skuID, ok := parseIDParam(c, "sku_id", "规格ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateCartItemRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "设置购物车参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	cart, err := h.shopService.SetCartItem(ctx, middleware.GetUserID(c), skuID, req.Quantity)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    cart,
+	})
+}
+
+// RemoveCartItem 移除购物车商品
+// @Summary 移除购物车商品
+// @Description 从购物车移除指定规格
+// @Tags 商城
+// @Produce json
+// @Param sku_id path int true "规格ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/cart/items/{sku_id} [delete]
+func (h *ShopHandler) RemoveCartItem(ctx context.Context, c *app.RequestContext) {
+	skuID, ok := parseIDParam(c, "sku_id", "规格ID")
+	if !ok {
+		return
+	}
+
+	cart, err := h.shopService.RemoveCartItem(ctx, middleware.GetUserID(c), skuID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "移除成功",
+		"data":    cart,
+	})
+}
+
+// ClearCart 清空购物车
+// @Summary 清空购物车
+// @Description 清空当前用户的购物车
+// @Tags 商城
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/cart [delete]
+func (h *ShopHandler) ClearCart(ctx context.Context, c *app.RequestContext) {
+	if err := h.shopService.ClearCart(ctx, middleware.GetUserID(c)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "清空成功",
+	})
+}
+
+// Checkout 结算
+// @Summary 结算
+// @Description 结算购物车并预占库存,预占15分钟内有效,超时未下单自动释放
+// @Tags 商城
+// @Accept json
+// @Produce json
+// @Param request body model.CheckoutRequest false "结算的规格,不传则结算全部"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shop/checkout [post]
+func (h *ShopHandler) Checkout(ctx context.Context, c *app.RequestContext) {
+	var req model.CheckoutRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "结算参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	reservation, err := h.shopService.Checkout(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "结算成功",
+		"data":    reservation,
+	})
+}
+
+// GetReservation 库存预占详情
+// @Summary 库存预占详情
+// @Description 获取本人结算生成的库存预占
+// @Tags 商城
+// @Produce json
+// @Param id path int true "预占ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shop/reservations/{id} [get]
+func (h *ShopHandler) GetReservation(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "预占ID")
+	if !ok {
+		return
+	}
+
+	reservation, err := h.shopService.GetReservation(ctx, middleware.GetUserID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    reservation,
+	})
+}
+
+// ReleaseReservation 取消结算
+// @Summary 取消结算
+// @Description 放弃结算并释放预占的库存
+// @Tags 商城
+// @Produce json
+// @Param id path int true "预占ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/shop/reservations/{id} [delete]
+func (h *ShopHandler) ReleaseReservation(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "预占ID")
+	if !ok {
+		return
+	}
+
+	if err := h.shopService.ReleaseReservation(ctx, middleware.GetUserID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已释放",
+	})
+}
+
+// AdminListCategories 管理端商品分类
+// @Summary 管理端商品分类
+// @Description 获取全部商品分类,包含已停用的分类
+// @Tags 商城管理
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/categories [get]
+func (h *ShopHandler) AdminListCategories(ctx context.Context, c *app.RequestContext) {
+	h.listCategories(ctx, c, true)
+}
+
+// CreateCategory 创建商品分类
+// @Summary 创建商品分类
+// @Description 创建商品分类,最多两级
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param request body model.SaveCategoryRequest true "分类"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/categories [post]
+func (h *ShopHandler) CreateCategory(ctx context.Context, c *app.RequestContext) {
+	var req model.SaveCategoryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建商品分类参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	category, err := h.shopService.CreateCategory(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    category,
+	})
+}
+
+// UpdateCategory 更新商品分类
+// @Summary 更新商品分类
+// @Description 修改分类名称、上级分类、排序和状态
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param id path int true "分类ID"
+// @Param request body model.SaveCategoryRequest true "分类"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/categories/{id} [put]
+func (h *ShopHandler) UpdateCategory(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "分类ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaveCategoryRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新商品分类参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	category, err := h.shopService.UpdateCategory(ctx, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    category,
+	})
+}
+
+// AdminListProducts 管理端商品列表
+// @Summary 管理端商品列表
+// @Description 检索全部商品,可按状态筛选
+// @Tags 商城管理
+// @Produce json
+// @Param status query string false "状态:draft,on_sale,off_sale"
+// @Param category_id query int false "分类ID"
+// @Param keyword query string false "关键词"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/products [get]
+func (h *ShopHandler) AdminListProducts(ctx context.Context, c *app.RequestContext) {
+	h.listProducts(ctx, c, true)
+}
+
+// AdminGetProduct 管理端商品详情
+// @Summary 管理端商品详情
+// @Description 获取商品及全部规格,包含未上架商品和停售规格
+// @Tags 商城管理
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/products/{id} [get]
+func (h *ShopHandler) AdminGetProduct(ctx context.Context, c *app.RequestContext) {
+	h.getProduct(ctx, c, true)
+}
+
+// CreateProduct 创建商品
+// @Summary 创建商品
+// @Description 创建商品及规格,新商品为草稿状态,需上架后用户可见
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param request body model.CreateProductRequest true "商品"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/products [post]
+func (h *ShopHandler) CreateProduct(ctx context.Context, c *app.RequestContext) {
+	var req model.CreateProductRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "创建商品参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	product, err := h.shopService.CreateProduct(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    product,
+	})
+}
+
+// UpdateProduct 更新商品
+// @Summary 更新商品
+// @Description 修改商品分类、名称、描述和图片
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body model.UpdateProductRequest true "商品"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/products/{id} [put]
+func (h *ShopHandler) UpdateProduct(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "商品ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateProductRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新商品参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	product, err := h.shopService.UpdateProduct(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    product,
+	})
+}
+
+// UpdateProductStatus 商品上下架
+// @Summary 商品上下架
+// @Description 修改商品状态,上架时至少需要一个在售规格
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body model.UpdateProductStatusRequest true "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/products/{id}/status [put]
+func (h *ShopHandler) UpdateProductStatus(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "商品ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateProductStatusRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新商品状态参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	product, err := h.shopService.UpdateProductStatus(ctx, id, req.Status)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    product,
+	})
+}
+
+// AddSKU 新增规格
+// @Summary 新增规格
+// @Description 为商品新增规格
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body model.SKURequest true "规格"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/products/{id}/skus [post]
+func (h *ShopHandler) AddSKU(ctx context.Context, c *app.RequestContext) {
+	productID, ok := parseIDParam(c, "id", "商品ID")
+	if !ok {
+		return
+	}
+
+	var req model.SKURequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "新增规格参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	sku, err := h.shopService.AddSKU(ctx, productID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "创建成功",
+		"data":    sku,
+	})
+}
+
+// UpdateSKU 更新规格
+// @Summary 更新规格
+// @Description 修改规格编码、名称、属性、价格和状态,库存需通过库存调整接口修改
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param id path int true "规格ID"
+// @Param request body model.SKURequest true "规格"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/skus/{id} [put]
+func (h *ShopHandler) UpdateSKU(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "规格ID")
+	if !ok {
+		return
+	}
+
+	var req model.SKURequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "更新规格参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	sku, err := h.shopService.UpdateSKU(ctx, id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "更新成功",
+		"data":    sku,
+	})
+}
+
+// AdjustStock 调整库存
+// @Summary 调整库存
+// @Description 入库(delta为正)或出库(delta为负),出库后库存不能为负
+// @Tags 商城管理
+// @Accept json
+// @Produce json
+// @Param id path int true "规格ID"
+// @Param request body model.AdjustStockRequest true "调整数量"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/shop/skus/{id}/stock [post]
+func (h *ShopHandler) AdjustStock(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "规格ID")
+	if !ok {
+		return
+	}
+
+	var req model.AdjustStockRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "调整库存参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	sku, err := h.shopService.AdjustStock(ctx, middleware.GetUserID(c), id, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "调整成功",
+		"data":    sku,
+	})
+}
+
+// listCategories 获取分类列表,管理端包含停用分类
+func (h *ShopHandler) listCategories(ctx context.Context, c *app.RequestContext, includeDisabled bool) {
+	categories, err := h.shopService.ListCategories(ctx, includeDisabled)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    categories,
+	})
+}
+
+// listProducts 获取商品列表,管理端包含未上架商品
+func (h *ShopHandler) listProducts(ctx context.Context, c *app.RequestContext, includeHidden bool) {
+	var req model.ListProductRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取商品列表参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	products, total, err := h.shopService.ListProducts(ctx, &req, includeHidden)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      products,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// getProduct 获取商品详情,管理端包含未上架商品和停售规格
+func (h *ShopHandler) getProduct(ctx context.Context, c *app.RequestContext, includeHidden bool) {
+	id, ok := parseIDParam(c, "id", "商品ID")
+	if !ok {
+		return
+	}
+
+	product, err := h.shopService.GetProduct(ctx, id, includeHidden)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    product,
+	})
+}
@@ -0,0 +1,436 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/biz/model"
+	"pet-service/biz/service"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/middleware"
+)
+
+// SitterHandler 寄养看护处理器
+type SitterHandler struct {
+	sitterService service.SitterService
+}
+
+// NewSitterHandler 创建寄养看护处理器
+func NewSitterHandler(sitterService service.SitterService) *SitterHandler {
+	return &SitterHandler{sitterService: sitterService}
+}
+
+// UpsertProfile 创建或更新看护人资料
+// @Summary 创建或更新看护人资料
+// @Description 设置提供的服务、每晚价格、接受的物种及服务区域
+// @Tags 寄养
+// @Accept json
+// @Produce json
+// @Param request body model.UpsertSitterProfileRequest true "看护人资料"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/sitter-profile [put]
+func (h *SitterHandler) UpsertProfile(ctx context.Context, c *app.RequestContext) {
+	var req model.UpsertSitterProfileRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "保存看护人资料参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	profile, err := h.sitterService.UpsertProfile(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "保存成功",
+		"data":    profile,
+	})
+}
+
+// GetMyProfile 获取我的看护人资料
+// @Summary 获取我的看护人资料
+// @Description 获取当前用户的看护人资料
+// @Tags 寄养
+// @Produce json
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/sitter-profile [get]
+func (h *SitterHandler) GetMyProfile(ctx context.Context, c *app.RequestContext) {
+	profile, err := h.sitterService.GetMyProfile(ctx, middleware.GetUserID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    profile,
+	})
+}
+
+// SetCalendar 设置档期
+// @Summary 设置档期
+// @Description 将日期区间设置为可约或不可约,已被预约占用的日期不受影响
+// @Tags 寄养
+// @Accept json
+// @Produce json
+// @Param request body model.SetCalendarRequest true "档期"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/sitter-profile/calendar [put]
+func (h *SitterHandler) SetCalendar(ctx context.Context, c *app.RequestContext) {
+	var req model.SetCalendarRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "设置档期参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	days, err := h.sitterService.SetCalendar(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "设置成功",
+		"data":    days,
+	})
+}
+
+// SearchSitters 检索看护人
+// @Summary 检索看护人
+// @Description 按城市、物种、服务类型、价格、档期和位置检索接单中的看护人
+// @Tags 寄养
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param city query string false "城市"
+// @Param species query string false "物种"
+// @Param service query string false "服务类型:boarding,house_sitting,drop_in,walking"
+// @Param max_price query int false "每晚最高价格(分)"
+// @Param start_date query string false "开始日期(YYYY-MM-DD)"
+// @Param end_date query string false "离开日期(YYYY-MM-DD)"
+// @Param lat query number false "纬度"
+// @Param lng query number false "经度"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitters [get]
+func (h *SitterHandler) SearchSitters(ctx context.Context, c *app.RequestContext) {
+	var req model.ListSitterRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "检索看护人参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	profiles, total, err := h.sitterService.SearchSitters(ctx, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      profiles,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// GetProfile 获取看护人资料
+// @Summary 获取看护人资料
+// @Description 获取看护人公开资料
+// @Tags 寄养
+// @Produce json
+// @Param id path int true "看护人ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitters/{id} [get]
+func (h *SitterHandler) GetProfile(ctx context.Context, c *app.RequestContext) {
+	sitterID, ok := parseIDParam(c, "id", "看护人ID")
+	if !ok {
+		return
+	}
+
+	profile, err := h.sitterService.GetProfile(ctx, sitterID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    profile,
+	})
+}
+
+// GetCalendar 查询看护人档期
+// @Summary 查询看护人档期
+// @Description 按天返回档期状态,默认返回今天起60天
+// @Tags 寄养
+// @Produce json
+// @Param id path int true "看护人ID"
+// @Param start_date query string false "开始日期(YYYY-MM-DD)"
+// @Param end_date query string false "结束日期(YYYY-MM-DD,包含)"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitters/{id}/calendar [get]
+func (h *SitterHandler) GetCalendar(ctx context.Context, c *app.RequestContext) {
+	sitterID, ok := parseIDParam(c, "id", "看护人ID")
+	if !ok {
+		return
+	}
+
+	var req model.CalendarRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "查询档期参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	days, err := h.sitterService.GetCalendar(ctx, sitterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    days,
+	})
+}
+
+// CreateBooking 发起寄养预约
+// @Summary 发起寄养预约
+// @Description 向看护人发起预约,所选日期需全部可约
+// @Tags 寄养
+// @Accept json
+// @Produce json
+// @Param id path int true "看护人ID"
+// @Param request body model.CreateBookingRequest true "预约请求"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitters/{id}/bookings [post]
+func (h *SitterHandler) CreateBooking(ctx context.Context, c *app.RequestContext) {
+	sitterID, ok := parseIDParam(c, "id", "看护人ID")
+	if !ok {
+		return
+	}
+
+	var req model.CreateBookingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "发起寄养预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	booking, err := h.sitterService.CreateBooking(ctx, middleware.GetUserID(c), sitterID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "预约申请已提交",
+		"data":    booking,
+	})
+}
+
+// GetBooking 获取预约详情
+// @Summary 获取预约详情
+// @Description 主人或看护人查看预约详情
+// @Tags 寄养
+// @Produce json
+// @Param id path int true "预约ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitter-bookings/{id} [get]
+func (h *SitterHandler) GetBooking(ctx context.Context, c *app.RequestContext) {
+	bookingID, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	booking, err := h.sitterService.GetBooking(ctx, middleware.GetUserID(c), bookingID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data":    booking,
+	})
+}
+
+// ListMyBookings 我的寄养预约
+// @Summary 我的寄养预约
+// @Description role=owner查看我发起的预约,role=sitter查看我收到的预约
+// @Tags 寄养
+// @Produce json
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Param role query string false "角色:owner,sitter"
+// @Param status query string false "状态"
+// @Success 200 {object} utils.H
+// @Router /api/v1/me/sitter-bookings [get]
+func (h *SitterHandler) ListMyBookings(ctx context.Context, c *app.RequestContext) {
+	var req model.ListBookingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "获取寄养预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	bookings, total, err := h.sitterService.ListMyBookings(ctx, middleware.GetUserID(c), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "获取成功",
+		"data": utils.H{
+			"list":      bookings,
+			"total":     total,
+			"page":      req.Page,
+			"page_size": req.PageSize,
+		},
+	})
+}
+
+// AcceptBooking 接受预约
+// @Summary 接受预约
+// @Description 看护人接受预约并占用档期,与之冲突的其他申请自动拒绝
+// @Tags 寄养
+// @Produce json
+// @Param id path int true "预约ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitter-bookings/{id}/accept [put]
+func (h *SitterHandler) AcceptBooking(ctx context.Context, c *app.RequestContext) {
+	bookingID, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	booking, err := h.sitterService.AcceptBooking(ctx, middleware.GetUserID(c), bookingID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已接受预约",
+		"data":    booking,
+	})
+}
+
+// DeclineBooking 拒绝预约
+// @Summary 拒绝预约
+// @Description 看护人拒绝预约申请
+// @Tags 寄养
+// @Accept json
+// @Produce json
+// @Param id path int true "预约ID"
+// @Param request body model.RespondBookingRequest false "拒绝原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitter-bookings/{id}/decline [put]
+func (h *SitterHandler) DeclineBooking(ctx context.Context, c *app.RequestContext) {
+	bookingID, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	var req model.RespondBookingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "拒绝预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	booking, err := h.sitterService.DeclineBooking(ctx, middleware.GetUserID(c), bookingID, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已拒绝预约",
+		"data":    booking,
+	})
+}
+
+// CancelBooking 取消预约
+// @Summary 取消预约
+// @Description 主人撤回申请或在寄养开始前取消,看护人可在开始前取消已接受的预约,取消后释放档期
+// @Tags 寄养
+// @Accept json
+// @Produce json
+// @Param id path int true "预约ID"
+// @Param request body model.RespondBookingRequest false "取消原因"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitter-bookings/{id}/cancel [put]
+func (h *SitterHandler) CancelBooking(ctx context.Context, c *app.RequestContext) {
+	bookingID, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	var req model.RespondBookingRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "取消预约参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	booking, err := h.sitterService.CancelBooking(ctx, middleware.GetUserID(c), bookingID, req.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "已取消预约",
+		"data":    booking,
+	})
+}
+
+// CompleteBooking 确认寄养完成
+// @Summary 确认寄养完成
+// @Description 寄养结束后主人或看护人确认完成,完成后可进行评价
+// @Tags 寄养
+// @Produce json
+// @Param id path int true "预约ID"
+// @Success 200 {object} utils.H
+// @Router /api/v1/sitter-bookings/{id}/complete [put]
+func (h *SitterHandler) CompleteBooking(ctx context.Context, c *app.RequestContext) {
+	bookingID, ok := parseIDParam(c, "id", "预约ID")
+	if !ok {
+		return
+	}
+
+	booking, err := h.sitterService.CompleteBooking(ctx, middleware.GetUserID(c), bookingID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "寄养已完成",
+		"data":    booking,
+	})
+}
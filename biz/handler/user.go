@@ -329,3 +329,39 @@ func (h *UserHandler) GetCurrentUser(ctx context.Context, c *app.RequestContext)
 		"data":    user,
 	})
 }
+
+// UpdateUserRole 修改用户角色
+// @Summary 修改用户角色
+// @Description 管理员修改用户角色,用户重新登录后生效
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param request body model.UpdateUserRoleRequest true "角色"
+// @Success 200 {object} utils.H
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *UserHandler) UpdateUserRole(ctx context.Context, c *app.RequestContext) {
+	id, ok := parseIDParam(c, "id", "用户ID")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateUserRoleRequest
+	if err := c.BindAndValidate(&req); err != nil {
+		logger.Error(ctx, "修改用户角色参数错误", logger.ErrorField(err))
+		respondBindError(c, err)
+		return
+	}
+
+	user, err := h.userService.UpdateRole(ctx, middleware.GetUserID(c), id, req.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{
+		"code":    0,
+		"message": "修改成功",
+		"data":    user,
+	})
+}
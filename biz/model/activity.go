@@ -0,0 +1,183 @@
+package model
+
+import (
+	"time"
+)
+
+// 活动类型
+const (
+	ActivityWalk  = "walk"  // 散步
+	ActivityRun   = "run"   // 跑步
+	ActivityHike  = "hike"  // 徒步
+	ActivityPlay  = "play"  // 玩耍
+	ActivitySwim  = "swim"  // 游泳
+	ActivityOther = "other" // 其他
+)
+
+// CalorieFactor 热量估算系数
+// PerKgKm 有距离时每公斤体重每公里消耗的千卡,PerKgHour 无距离时每公斤体重每小时消耗的千卡
+type CalorieFactor struct {
+	PerKgKm   float64
+	PerKgHour float64
+}
+
+// ActivityCalorieFactors 各活动类型的热量估算系数,同时作为支持的活动类型
+var ActivityCalorieFactors = map[string]CalorieFactor{
+	ActivityWalk:  {PerKgKm: 0.8, PerKgHour: 2.5},
+	ActivityRun:   {PerKgKm: 1.1, PerKgHour: 7},
+	ActivityHike:  {PerKgKm: 1.0, PerKgHour: 4},
+	ActivityPlay:  {PerKgKm: 1.0, PerKgHour: 4},
+	ActivitySwim:  {PerKgKm: 2.0, PerKgHour: 6},
+	ActivityOther: {PerKgKm: 0.8, PerKgHour: 2.5},
+}
+
+// 活动来源
+const (
+	ActivitySourceManual  = "manual"
+	ActivitySourceGPX     = "gpx"
+	ActivitySourceGeoJSON = "geojson"
+)
+
+// 活动目标周期与指标
+const (
+	GoalPeriodWeek  = "week"
+	GoalPeriodMonth = "month"
+
+	GoalMetricDistance = "distance" // 距离(米)
+	GoalMetricDuration = "duration" // 时长(秒)
+	GoalMetricCount    = "count"    // 次数
+)
+
+// Activity 宠物运动记录,轨迹单独存放在activity_tracks中
+type Activity struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	PetID        uint      `json:"pet_id" gorm:"index:idx_pet_started,priority:1;not null;comment:宠物ID"`
+	RecordedBy   uint      `json:"recorded_by" gorm:"comment:记录人用户ID"`
+	Type         string    `json:"type" gorm:"type:varchar(20);not null;comment:类型:walk,run,hike,play,swim,other"`
+	Title        string    `json:"title" gorm:"type:varchar(100);comment:标题"`
+	StartedAt    time.Time `json:"started_at" gorm:"index:idx_pet_started,priority:2;not null;comment:开始时间"`
+	DurationSec  int       `json:"duration_sec" gorm:"not null;comment:时长(秒)"`
+	DistanceM    float64   `json:"distance_m" gorm:"type:decimal(10,1);default:0;comment:距离(米)"`
+	PaceSecPerKm int       `json:"pace_sec_per_km" gorm:"default:0;comment:配速(秒/公里),无距离时为0"`
+	Calories     int       `json:"calories" gorm:"default:0;comment:估算消耗(千卡),缺少体重时为0"`
+	Source       string    `json:"source" gorm:"type:varchar(10);default:manual;comment:来源:manual,gpx,geojson"`
+	HasTrack     bool      `json:"has_track" gorm:"default:false;comment:是否有轨迹"`
+	Notes        string    `json:"notes" gorm:"type:varchar(500);comment:备注"`
+	IsDeleted    int       `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+}
+
+// TableName 指定表名
+func (Activity) TableName() string {
+	return "activities"
+}
+
+// ActivityTrack 活动轨迹,gzip压缩存储,与活动一对一
+type ActivityTrack struct {
+	ID         uint      `json:"-" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"-"`
+	ActivityID uint      `json:"activity_id" gorm:"uniqueIndex;not null;comment:活动ID"`
+	PointCount int       `json:"point_count" gorm:"comment:存储的轨迹点数"`
+	MinLat     float64   `json:"min_lat" gorm:"type:decimal(10,7);comment:外接矩形"`
+	MinLon     float64   `json:"min_lon" gorm:"type:decimal(10,7)"`
+	MaxLat     float64   `json:"max_lat" gorm:"type:decimal(10,7)"`
+	MaxLon     float64   `json:"max_lon" gorm:"type:decimal(10,7)"`
+	Data       []byte    `json:"-" gorm:"type:mediumblob;not null;comment:gzip压缩的轨迹点"`
+}
+
+// TableName 指定表名
+func (ActivityTrack) TableName() string {
+	return "activity_tracks"
+}
+
+// ActivityGoal 运动目标,同一宠物每个周期和指标只有一个目标
+type ActivityGoal struct {
+	ID        uint          `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	PetID     uint          `json:"pet_id" gorm:"uniqueIndex:idx_pet_period_metric,priority:1;not null;comment:宠物ID"`
+	Period    string        `json:"period" gorm:"type:varchar(10);uniqueIndex:idx_pet_period_metric,priority:2;not null;comment:周期:week,month"`
+	Metric    string        `json:"metric" gorm:"type:varchar(20);uniqueIndex:idx_pet_period_metric,priority:3;not null;comment:指标:distance,duration,count"`
+	Target    float64       `json:"target" gorm:"type:decimal(12,1);not null;comment:目标值,距离为米,时长为秒"`
+	Progress  *GoalProgress `json:"progress,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (ActivityGoal) TableName() string {
+	return "activity_goals"
+}
+
+// GoalProgress 当前周期的目标完成情况
+type GoalProgress struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Current     float64   `json:"current"`
+	Percent     float64   `json:"percent"`
+	Achieved    bool      `json:"achieved"`
+}
+
+// SaveActivityRequest 手动记录/修改活动请求
+type SaveActivityRequest struct {
+	Type        string    `json:"type" binding:"required,oneof=walk run hike play swim other"`
+	Title       string    `json:"title" binding:"max=100"`
+	StartedAt   time.Time `json:"started_at" binding:"required"`
+	DurationSec int       `json:"duration_sec" binding:"required,min=1"`
+	DistanceM   float64   `json:"distance_m" binding:"min=0"`
+	Notes       string    `json:"notes" binding:"max=500"`
+}
+
+// ImportActivityRequest 导入轨迹请求(multipart表单),未传的字段从轨迹中推算
+type ImportActivityRequest struct {
+	Type        string     `form:"type"`
+	Title       string     `form:"title"`
+	Notes       string     `form:"notes"`
+	StartedAt   *time.Time `form:"started_at"`   // 轨迹不含时间时必填
+	DurationSec int        `form:"duration_sec"` // 轨迹不含时间时必填
+}
+
+// ListActivityRequest 活动列表请求
+type ListActivityRequest struct {
+	Page     int        `form:"page,default=1" binding:"min=1"`
+	PageSize int        `form:"page_size,default=20" binding:"min=1,max=100"`
+	Type     string     `form:"type"`
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
+}
+
+// ActivityStatsRequest 活动统计请求,默认统计最近12周或12个月
+type ActivityStatsRequest struct {
+	Period string     `form:"period,default=week" binding:"oneof=week month"`
+	Type   string     `form:"type"`
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+}
+
+// ActivityBucket 按周(周一起始)或按月汇总的活动数据
+type ActivityBucket struct {
+	Bucket      time.Time `json:"bucket"`
+	Count       int64     `json:"count"`
+	DurationSec int64     `json:"duration_sec"`
+	DistanceM   float64   `json:"distance_m"`
+	Calories    int64     `json:"calories"`
+	// PaceSecPerKm 区间平均配速,仅统计有距离的活动
+	PaceSecPerKm int `json:"pace_sec_per_km"`
+	// TrackedDurationSec 有距离的活动时长,用于计算平均配速
+	TrackedDurationSec int64 `json:"-"`
+}
+
+// ActivityStats 活动统计结果
+type ActivityStats struct {
+	Period  string            `json:"period"`
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Buckets []*ActivityBucket `json:"buckets"`
+	Total   ActivityBucket    `json:"total"`
+}
+
+// SaveActivityGoalRequest 设置运动目标请求
+type SaveActivityGoalRequest struct {
+	Period string  `json:"period" binding:"required,oneof=week month"`
+	Metric string  `json:"metric" binding:"required,oneof=distance duration count"`
+	Target float64 `json:"target" binding:"required,gt=0"`
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"time"
+)
+
+// 领养信息状态
+const (
+	ListingStatusOpen    = "open"
+	ListingStatusPending = "pending"
+	ListingStatusAdopted = "adopted"
+	ListingStatusClosed  = "closed"
+)
+
+// 领养申请状态
+const (
+	ApplicationStatusSubmitted = "submitted"
+	ApplicationStatusScreening = "screening"
+	ApplicationStatusApproved  = "approved"
+	ApplicationStatusRejected  = "rejected"
+	ApplicationStatusCompleted = "completed"
+)
+
+// Shelter 救助机构模型
+type Shelter struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	OwnerID     uint      `json:"owner_id" gorm:"index;not null;comment:管理员用户ID"`
+	Name        string    `json:"name" gorm:"type:varchar(100);not null;comment:机构名称"`
+	City        string    `json:"city" gorm:"type:varchar(50);index;comment:城市"`
+	Address     string    `json:"address" gorm:"type:varchar(255);comment:地址"`
+	Phone       string    `json:"phone" gorm:"type:varchar(20);comment:联系电话"`
+	Email       string    `json:"email" gorm:"type:varchar(100);comment:联系邮箱"`
+	Description string    `json:"description" gorm:"type:text;comment:机构介绍"`
+	Status      int       `json:"status" gorm:"type:tinyint;default:1;comment:状态:0禁用,1正常"`
+}
+
+// TableName 指定表名
+func (Shelter) TableName() string {
+	return "shelters"
+}
+
+// AdoptionListing 领养信息模型
+type AdoptionListing struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	ShelterID   uint      `json:"shelter_id" gorm:"index;not null;comment:救助机构ID"`
+	PetID       uint      `json:"pet_id" gorm:"uniqueIndex;not null;comment:宠物ID"`
+	Title       string    `json:"title" gorm:"type:varchar(100);not null;comment:标题"`
+	Description string    `json:"description" gorm:"type:text;comment:描述"`
+	City        string    `json:"city" gorm:"type:varchar(50);index;comment:所在城市"`
+	Fee         int64     `json:"fee" gorm:"default:0;comment:领养费用(分)"`
+	Status      string    `json:"status" gorm:"type:varchar(20);index;default:open;comment:状态:open,pending,adopted,closed"`
+	Pet         *Pet      `json:"pet,omitempty" gorm:"foreignKey:PetID"`
+	Shelter     *Shelter  `json:"shelter,omitempty" gorm:"foreignKey:ShelterID"`
+}
+
+// TableName 指定表名
+func (AdoptionListing) TableName() string {
+	return "adoption_listings"
+}
+
+// QuestionAnswer 领养问卷答案
+type QuestionAnswer struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// AdoptionApplication 领养申请模型
+type AdoptionApplication struct {
+	ID          uint             `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	ListingID   uint             `json:"listing_id" gorm:"index;not null;comment:领养信息ID"`
+	ShelterID   uint             `json:"shelter_id" gorm:"index;not null;comment:救助机构ID"`
+	ApplicantID uint             `json:"applicant_id" gorm:"index;not null;comment:申请人用户ID"`
+	Answers     []QuestionAnswer `json:"answers" gorm:"type:json;serializer:json;comment:问卷答案"`
+	Status      string           `json:"status" gorm:"type:varchar(20);index;default:submitted;comment:状态"`
+	ReviewerID  uint             `json:"reviewer_id" gorm:"comment:审核人用户ID"`
+	ReviewNote  string           `json:"review_note" gorm:"type:varchar(500);comment:审核备注"`
+	ReviewedAt  *time.Time       `json:"reviewed_at" gorm:"comment:最近审核时间"`
+	CompletedAt *time.Time       `json:"completed_at" gorm:"comment:完成时间"`
+}
+
+// TableName 指定表名
+func (AdoptionApplication) TableName() string {
+	return "adoption_applications"
+}
+
+// adoptionTransitions 领养申请状态流转规则
+var adoptionTransitions = map[string][]string{
+	ApplicationStatusSubmitted: {ApplicationStatusScreening, ApplicationStatusRejected},
+	ApplicationStatusScreening: {ApplicationStatusApproved, ApplicationStatusRejected},
+	ApplicationStatusApproved:  {ApplicationStatusCompleted, ApplicationStatusRejected},
+}
+
+// CanTransitTo 判断申请能否流转到目标状态
+func (a *AdoptionApplication) CanTransitTo(status string) bool {
+	for _, next := range adoptionTransitions[a.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive 申请是否仍在处理中
+func (a *AdoptionApplication) IsActive() bool {
+	return a.Status != ApplicationStatusRejected && a.Status != ApplicationStatusCompleted
+}
+
+// CreateShelterRequest 创建救助机构请求
+type CreateShelterRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	City        string `json:"city" binding:"required,max=50"`
+	Address     string `json:"address" binding:"omitempty,max=255"`
+	Phone       string `json:"phone" binding:"omitempty,max=20"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Description string `json:"description"`
+}
+
+// UpdateShelterRequest 更新救助机构请求
+type UpdateShelterRequest struct {
+	Name        string `json:"name" binding:"omitempty,max=100"`
+	City        string `json:"city" binding:"omitempty,max=50"`
+	Address     string `json:"address" binding:"omitempty,max=255"`
+	Phone       string `json:"phone" binding:"omitempty,max=20"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Description string `json:"description"`
+}
+
+// CreateListingRequest 发布领养信息请求,同时创建由机构持有的宠物档案
+type CreateListingRequest struct {
+	Title       string           `json:"title" binding:"required,max=100"`
+	Description string           `json:"description"`
+	City        string           `json:"city" binding:"omitempty,max=50"`
+	Fee         int64            `json:"fee" binding:"min=0"`
+	Pet         CreatePetRequest `json:"pet" binding:"required"`
+}
+
+// UpdateListingRequest 更新领养信息请求
+type UpdateListingRequest struct {
+	Title       string `json:"title" binding:"omitempty,max=100"`
+	Description string `json:"description"`
+	City        string `json:"city" binding:"omitempty,max=50"`
+	Fee         *int64 `json:"fee" binding:"omitempty,min=0"`
+	Status      string `json:"status" binding:"omitempty,oneof=open closed"`
+}
+
+// ListListingRequest 领养信息检索请求
+type ListListingRequest struct {
+	Page         int    `form:"page,default=1" binding:"min=1"`
+	PageSize     int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Species      string `form:"species"`
+	Size         string `form:"size" binding:"omitempty,oneof=small medium large"`
+	City         string `form:"city"`
+	MinAgeMonths *int   `form:"min_age_months" binding:"omitempty,min=0"`
+	MaxAgeMonths *int   `form:"max_age_months" binding:"omitempty,min=0"`
+	ShelterID    uint   `form:"shelter_id"`
+	Keyword      string `form:"keyword"`
+}
+
+// SubmitApplicationRequest 提交领养申请请求
+type SubmitApplicationRequest struct {
+	Answers []QuestionAnswer `json:"answers" binding:"required"`
+}
+
+// ReviewApplicationRequest 审核领养申请请求
+type ReviewApplicationRequest struct {
+	Status string `json:"status" binding:"required,oneof=screening approved rejected completed"`
+	Note   string `json:"note" binding:"omitempty,max=500"`
+}
+
+// ListApplicationRequest 领养申请列表请求
+type ListApplicationRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Status   string `form:"status"`
+}
@@ -0,0 +1,274 @@
+package model
+
+import (
+	"time"
+)
+
+// 诊所服务类别
+const (
+	ServiceCategoryGrooming = "grooming"  // 美容
+	ServiceCategoryBath     = "bath"      // 洗澡
+	ServiceCategoryNailTrim = "nail_trim" // 修剪指甲
+	ServiceCategoryDental   = "dental"    // 洁牙
+	ServiceCategoryCheckup  = "checkup"   // 门诊/体检
+	ServiceCategoryOther    = "other"     // 其他
+)
+
+// ServiceCategories 支持的服务类别
+var ServiceCategories = map[string]bool{
+	ServiceCategoryGrooming: true,
+	ServiceCategoryBath:     true,
+	ServiceCategoryNailTrim: true,
+	ServiceCategoryDental:   true,
+	ServiceCategoryCheckup:  true,
+	ServiceCategoryOther:    true,
+}
+
+// PetSizes 支持的体型等级,服务按体型定价
+var PetSizes = map[string]bool{
+	PetSizeSmall:  true,
+	PetSizeMedium: true,
+	PetSizeLarge:  true,
+}
+
+// 诊所服务状态
+const (
+	ClinicServiceOffline = 0
+	ClinicServiceOnline  = 1
+)
+
+// 门诊预约状态
+const (
+	AppointmentRequested = "requested" // 待诊所确认
+	AppointmentConfirmed = "confirmed" // 已确认
+	AppointmentDeclined  = "declined"  // 诊所拒绝
+	AppointmentCancelled = "cancelled" // 已取消
+	AppointmentCompleted = "completed" // 已完成
+	AppointmentNoShow    = "no_show"   // 爽约
+)
+
+// AppointmentTransitions 诊所可执行的状态流转,主人只能取消
+var AppointmentTransitions = map[string][]string{
+	AppointmentRequested: {AppointmentConfirmed, AppointmentDeclined, AppointmentCancelled},
+	AppointmentConfirmed: {AppointmentCompleted, AppointmentNoShow, AppointmentCancelled},
+}
+
+// CanTransitAppointment 判断预约能否从from流转到to
+func CanTransitAppointment(from, to string) bool {
+	for _, s := range AppointmentTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveAppointmentStatuses 占用时段的预约状态
+var ActiveAppointmentStatuses = []string{AppointmentRequested, AppointmentConfirmed}
+
+// PeakConcurrency 计算区间[from, to)内同时进行的预约数峰值
+// 并发数只会在区间起点或某个预约开始时增加,逐一检查这些时刻即可
+func PeakConcurrency(appointments []*Appointment, from, to time.Time) int {
+	peak := 0
+	check := func(t time.Time) {
+		n := 0
+		for _, a := range appointments {
+			if !a.StartAt.After(t) && a.EndAt.After(t) {
+				n++
+			}
+		}
+		if n > peak {
+			peak = n
+		}
+	}
+	check(from)
+	for _, a := range appointments {
+		if a.StartAt.After(from) && a.StartAt.Before(to) {
+			check(a.StartAt)
+		}
+	}
+	return peak
+}
+
+// ClinicServiceItem 诊所可预约的服务项目,时长和价格按宠物体型区分
+type ClinicServiceItem struct {
+	ID          uint                  `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+	ClinicID    uint                  `json:"clinic_id" gorm:"index;not null;comment:诊所ID"`
+	Category    string                `json:"category" gorm:"type:varchar(20);not null;comment:类别:grooming,bath,nail_trim,dental,checkup,other"`
+	Name        string                `json:"name" gorm:"type:varchar(100);not null;comment:服务名称"`
+	Description string                `json:"description" gorm:"type:varchar(500);comment:服务说明"`
+	Status      int                   `json:"status" gorm:"type:tinyint;not null;comment:状态:0下架,1上架"`
+	IsDeleted   int                   `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	Prices      []*ClinicServicePrice `json:"prices" gorm:"foreignKey:ServiceID"`
+}
+
+// TableName 指定表名
+func (ClinicServiceItem) TableName() string {
+	return "clinic_services"
+}
+
+// PriceFor 获取指定体型的时长和价格,未提供该体型时返回nil
+func (s *ClinicServiceItem) PriceFor(size string) *ClinicServicePrice {
+	for _, p := range s.Prices {
+		if p.Size == size {
+			return p
+		}
+	}
+	return nil
+}
+
+// ClinicServicePrice 服务在某一体型下的时长与价格
+type ClinicServicePrice struct {
+	ID          uint   `json:"-" gorm:"primarykey"`
+	ServiceID   uint   `json:"-" gorm:"uniqueIndex:idx_service_size,priority:1;not null;comment:服务ID"`
+	Size        string `json:"size" gorm:"type:varchar(10);uniqueIndex:idx_service_size,priority:2;not null;comment:体型:small,medium,large"`
+	DurationMin int    `json:"duration_min" gorm:"not null;comment:时长(分钟)"`
+	Price       int64  `json:"price" gorm:"not null;comment:价格(分)"`
+}
+
+// TableName 指定表名
+func (ClinicServicePrice) TableName() string {
+	return "clinic_service_prices"
+}
+
+// Appointment 门诊预约,时长和价格由所选服务按宠物体型汇总
+type Appointment struct {
+	ID            uint               `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	AppointmentNo string             `json:"appointment_no" gorm:"type:varchar(32);uniqueIndex;not null;comment:预约编号"`
+	ClinicID      uint               `json:"clinic_id" gorm:"index:idx_clinic_start,priority:1;not null;comment:诊所ID"`
+	UserID        uint               `json:"user_id" gorm:"index;not null;comment:预约人用户ID"`
+	PetID         uint               `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	PetSize       string             `json:"pet_size" gorm:"type:varchar(10);not null;comment:计价体型"`
+	StartAt       time.Time          `json:"start_at" gorm:"index:idx_clinic_start,priority:2;not null;comment:开始时间"`
+	EndAt         time.Time          `json:"end_at" gorm:"not null;comment:结束时间"`
+	DurationMin   int                `json:"duration_min" gorm:"not null;comment:时长(分钟)"`
+	TotalPrice    int64              `json:"total_price" gorm:"not null;comment:总价(分)"`
+	Status        string             `json:"status" gorm:"type:varchar(20);index;not null;comment:状态:requested,confirmed,declined,cancelled,completed,no_show"`
+	Notes         string             `json:"notes" gorm:"type:varchar(500);comment:预约备注"`
+	StatusNote    string             `json:"status_note" gorm:"type:varchar(255);comment:拒绝/取消原因"`
+	ConfirmedAt   *time.Time         `json:"confirmed_at" gorm:"comment:确认时间"`
+	CompletedAt   *time.Time         `json:"completed_at" gorm:"comment:完成时间"`
+	VetID         uint               `json:"vet_id" gorm:"index;default:0;comment:接诊兽医用户ID,完成时记录"`
+	Items         []*AppointmentItem `json:"items,omitempty" gorm:"foreignKey:AppointmentID"`
+	Clinic        *Clinic            `json:"clinic,omitempty" gorm:"foreignKey:ClinicID"`
+}
+
+// TableName 指定表名
+func (Appointment) TableName() string {
+	return "appointments"
+}
+
+// Reviewable 已完成的预约可以评价
+func (a *Appointment) Reviewable() bool {
+	return a.Status == AppointmentCompleted
+}
+
+// AppointmentItem 预约包含的服务,保存下单时的名称、时长和价格快照
+type AppointmentItem struct {
+	ID            uint   `json:"id" gorm:"primarykey"`
+	AppointmentID uint   `json:"-" gorm:"index;not null;comment:预约ID"`
+	ServiceID     uint   `json:"service_id" gorm:"not null;comment:服务ID"`
+	Category      string `json:"category" gorm:"type:varchar(20);comment:服务类别"`
+	Name          string `json:"name" gorm:"type:varchar(100);not null;comment:服务名称"`
+	DurationMin   int    `json:"duration_min" gorm:"not null;comment:时长(分钟)"`
+	Price         int64  `json:"price" gorm:"not null;comment:价格(分)"`
+}
+
+// TableName 指定表名
+func (AppointmentItem) TableName() string {
+	return "appointment_items"
+}
+
+// ServicePriceRequest 服务在某一体型下的时长与价格
+type ServicePriceRequest struct {
+	Size        string `json:"size" binding:"required,oneof=small medium large"`
+	DurationMin int    `json:"duration_min" binding:"required,min=5"`
+	Price       int64  `json:"price" binding:"min=0"`
+}
+
+// SaveClinicServiceRequest 创建/修改诊所服务请求,prices整体覆盖
+type SaveClinicServiceRequest struct {
+	Category    string                `json:"category" binding:"required,oneof=grooming bath nail_trim dental checkup other"`
+	Name        string                `json:"name" binding:"required,max=100"`
+	Description string                `json:"description" binding:"max=500"`
+	Status      *int                  `json:"status" binding:"omitempty,oneof=0 1"`
+	Prices      []ServicePriceRequest `json:"prices" binding:"required"`
+}
+
+// ListClinicServiceRequest 诊所服务列表请求
+type ListClinicServiceRequest struct {
+	Category string `form:"category"`
+}
+
+// AppointmentQuoteRequest 预约报价请求,宠物未登记体型时需传pet_size
+type AppointmentQuoteRequest struct {
+	PetID      uint       `json:"pet_id" binding:"required"`
+	PetSize    string     `json:"pet_size" binding:"omitempty,oneof=small medium large"`
+	ServiceIDs []uint     `json:"service_ids" binding:"required"`
+	StartAt    *time.Time `json:"start_at"` // 传入时同时检查该时段是否可约
+}
+
+// AppointmentQuote 预约报价
+type AppointmentQuote struct {
+	ClinicID    uint               `json:"clinic_id"`
+	PetID       uint               `json:"pet_id"`
+	PetSize     string             `json:"pet_size"`
+	Items       []*AppointmentItem `json:"items"`
+	DurationMin int                `json:"duration_min"`
+	TotalPrice  int64              `json:"total_price"`
+	StartAt     *time.Time         `json:"start_at,omitempty"`
+	EndAt       *time.Time         `json:"end_at,omitempty"`
+	Available   *bool              `json:"available,omitempty"`
+	Reason      string             `json:"reason,omitempty"` // 不可约原因
+}
+
+// CreateAppointmentRequest 预约请求
+// quoted_price为报价接口返回的总价,价格变化时预约失败,需重新报价确认
+type CreateAppointmentRequest struct {
+	PetID       uint      `json:"pet_id" binding:"required"`
+	PetSize     string    `json:"pet_size" binding:"omitempty,oneof=small medium large"`
+	ServiceIDs  []uint    `json:"service_ids" binding:"required"`
+	StartAt     time.Time `json:"start_at" binding:"required"`
+	QuotedPrice int64     `json:"quoted_price" binding:"min=0"`
+	Notes       string    `json:"notes" binding:"max=500"`
+}
+
+// AppointmentAvailabilityRequest 可约时段查询请求
+type AppointmentAvailabilityRequest struct {
+	Date       string `form:"date" binding:"required"` // YYYY-MM-DD
+	PetID      uint   `form:"pet_id" binding:"required"`
+	PetSize    string `form:"pet_size"`
+	ServiceIDs []uint `form:"service_ids" binding:"required"`
+}
+
+// AppointmentAvailability 某天的可约开始时间
+type AppointmentAvailability struct {
+	Date        string      `json:"date"`
+	DurationMin int         `json:"duration_min"`
+	TotalPrice  int64       `json:"total_price"`
+	Slots       []time.Time `json:"slots"`
+}
+
+// ListAppointmentRequest 预约列表请求
+type ListAppointmentRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Status   string `form:"status"`
+	Date     string `form:"date"` // YYYY-MM-DD,仅诊所列表使用
+}
+
+// UpdateAppointmentStatusRequest 变更预约状态请求
+type UpdateAppointmentStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=confirmed declined cancelled completed no_show"`
+	Note   string `json:"note" binding:"max=255"`
+	VetID  uint   `json:"vet_id"` // 完成时的接诊兽医,不填且操作人为兽医时记为操作人
+}
+
+// CancelAppointmentRequest 主人取消预约请求
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
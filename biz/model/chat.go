@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+)
+
+// 会话参与方
+const (
+	ChatSideOwner  = "owner"  // 宠物主人
+	ChatSideClinic = "clinic" // 诊所人员,同一诊所的人员共享会话
+)
+
+// 聊天消息类型
+const (
+	ChatMessageText  = "text"
+	ChatMessageImage = "image"
+)
+
+// 实时推送事件类型
+const (
+	ChatEventMessage = "message" // 新消息
+	ChatEventRead    = "read"    // 已读回执
+	ChatEventAck     = "ack"     // 客户端发送结果
+	ChatEventError   = "error"   // 客户端请求出错
+	ChatEventPong    = "pong"    // 心跳响应
+)
+
+// Conversation 主人与诊所的会话,每个主人与每家诊所只有一个会话
+type Conversation struct {
+	ID                 uint       `json:"id" gorm:"primarykey"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	ClinicID           uint       `json:"clinic_id" gorm:"uniqueIndex:idx_clinic_owner,priority:1;not null;comment:诊所ID"`
+	OwnerID            uint       `json:"owner_id" gorm:"uniqueIndex:idx_clinic_owner,priority:2;index;not null;comment:宠物主人用户ID"`
+	PetID              uint       `json:"pet_id" gorm:"default:0;comment:最近咨询的宠物ID"`
+	LastMessageID      uint       `json:"last_message_id" gorm:"default:0;comment:最后一条消息ID"`
+	LastMessageAt      *time.Time `json:"last_message_at" gorm:"index;comment:最后消息时间"`
+	LastMessagePreview string     `json:"last_message_preview" gorm:"type:varchar(100);comment:最后消息摘要"`
+	OwnerUnread        int        `json:"owner_unread" gorm:"default:0;comment:主人未读数"`
+	ClinicUnread       int        `json:"clinic_unread" gorm:"default:0;comment:诊所未读数"`
+	OwnerReadID        uint       `json:"owner_read_id" gorm:"default:0;comment:主人已读到的消息ID"`
+	ClinicReadID       uint       `json:"clinic_read_id" gorm:"default:0;comment:诊所已读到的消息ID"`
+	Clinic             *Clinic    `json:"clinic,omitempty" gorm:"foreignKey:ClinicID"`
+	Owner              *User      `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
+	Pet                *Pet       `json:"pet,omitempty" gorm:"foreignKey:PetID"`
+}
+
+// TableName 指定表名
+func (Conversation) TableName() string {
+	return "conversations"
+}
+
+// ChatMessage 会话消息
+type ChatMessage struct {
+	ID             uint      `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time `json:"created_at"`
+	ConversationID uint      `json:"conversation_id" gorm:"index;not null;comment:会话ID"`
+	SenderID       uint      `json:"sender_id" gorm:"not null;comment:发送人用户ID"`
+	SenderSide     string    `json:"sender_side" gorm:"type:varchar(20);not null;comment:发送方:owner,clinic"`
+	Type           string    `json:"type" gorm:"type:varchar(20);default:text;comment:类型:text,image"`
+	Content        string    `json:"content" gorm:"type:text;comment:文本内容"`
+	FileID         uint      `json:"file_id" gorm:"default:0;comment:图片文件ID"`
+	ImageURL       string    `json:"image_url,omitempty" gorm:"-"`
+	Sender         *User     `json:"sender,omitempty" gorm:"foreignKey:SenderID"`
+}
+
+// TableName 指定表名
+func (ChatMessage) TableName() string {
+	return "chat_messages"
+}
+
+// StartConversationRequest 主人向诊所发起咨询
+type StartConversationRequest struct {
+	ClinicID uint   `json:"clinic_id" binding:"required"`
+	PetID    uint   `json:"pet_id"`
+	Content  string `json:"content" binding:"required,max=2000"`
+}
+
+// SendChatMessageRequest 发送消息请求,图片消息需先上传文件
+type SendChatMessageRequest struct {
+	Type    string `json:"type" binding:"omitempty,oneof=text image"`
+	Content string `json:"content" binding:"max=2000"`
+	FileID  uint   `json:"file_id"`
+}
+
+// ListConversationRequest 会话列表请求
+type ListConversationRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ListChatMessageRequest 历史消息请求,按消息ID向前翻页
+type ListChatMessageRequest struct {
+	BeforeID uint `form:"before_id"`
+	Limit    int  `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// MarkReadRequest 标记已读请求,message_id为空时标记到最新消息
+type MarkReadRequest struct {
+	MessageID uint `json:"message_id"`
+}
+
+// ChatMessagePage 历史消息分页,next_before_id为0表示没有更早的消息
+type ChatMessagePage struct {
+	List         []*ChatMessage `json:"list"`
+	NextBeforeID uint           `json:"next_before_id"`
+}
+
+// ChatUnread 未读统计
+type ChatUnread struct {
+	Owner  int64 `json:"owner"`  // 作为主人的未读消息数
+	Clinic int64 `json:"clinic"` // 任职诊所的未读消息数
+	Total  int64 `json:"total"`
+}
+
+// ChatEvent 实时推送事件
+type ChatEvent struct {
+	Type           string       `json:"type"`
+	ConversationID uint         `json:"conversation_id,omitempty"`
+	Message        *ChatMessage `json:"message,omitempty"`
+	ReaderSide     string       `json:"reader_side,omitempty"`
+	ReadID         uint         `json:"read_id,omitempty"`
+	RequestID      string       `json:"request_id,omitempty"`
+	Error          string       `json:"error,omitempty"`
+}
+
+// ChatClientEvent 客户端通过WebSocket发送的指令
+type ChatClientEvent struct {
+	Type           string `json:"type"` // send,read,ping
+	RequestID      string `json:"request_id"`
+	ConversationID uint   `json:"conversation_id"`
+	MessageType    string `json:"message_type"`
+	Content        string `json:"content"`
+	FileID         uint   `json:"file_id"`
+	MessageID      uint   `json:"message_id"`
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+)
+
+// 诊所人员角色
+const (
+	ClinicRoleManager = "manager" // 诊所管理员,可维护诊所信息和人员
+	ClinicRoleVet     = "vet"     // 执业兽医
+	ClinicRoleStaff   = "staff"   // 前台、助理等
+)
+
+// ClinicRoles 支持的诊所人员角色
+var ClinicRoles = map[string]bool{
+	ClinicRoleManager: true,
+	ClinicRoleVet:     true,
+	ClinicRoleStaff:   true,
+}
+
+// Clinic 宠物诊所
+type Clinic struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Name         string    `json:"name" gorm:"type:varchar(100);not null;comment:诊所名称"`
+	Phone        string    `json:"phone" gorm:"type:varchar(20);comment:联系电话"`
+	City         string    `json:"city" gorm:"type:varchar(50);index;comment:城市"`
+	Address      string    `json:"address" gorm:"type:varchar(255);comment:地址"`
+	Latitude     float64   `json:"latitude" gorm:"type:decimal(10,7);comment:纬度"`
+	Longitude    float64   `json:"longitude" gorm:"type:decimal(10,7);comment:经度"`
+	Description  string    `json:"description" gorm:"type:text;comment:简介"`
+	OpeningHours string    `json:"opening_hours" gorm:"type:varchar(255);comment:营业时间说明"`
+	Status       int       `json:"status" gorm:"type:tinyint;default:1;comment:状态:0停业,1营业"`
+}
+
+// TableName 指定表名
+func (Clinic) TableName() string {
+	return "clinics"
+}
+
+// ClinicStaff 诊所人员,同一用户在同一诊所只有一条记录
+type ClinicStaff struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ClinicID  uint      `json:"clinic_id" gorm:"uniqueIndex:idx_clinic_user,priority:1;not null;comment:诊所ID"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_clinic_user,priority:2;index;not null;comment:用户ID"`
+	Role      string    `json:"role" gorm:"type:varchar(20);not null;comment:角色:manager,vet,staff"`
+	Title     string    `json:"title" gorm:"type:varchar(50);comment:职称"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Clinic    *Clinic   `json:"clinic,omitempty" gorm:"foreignKey:ClinicID"`
+}
+
+// TableName 指定表名
+func (ClinicStaff) TableName() string {
+	return "clinic_staff"
+}
+
+// SaveClinicRequest 创建/更新诊所请求,manager_user_id仅创建时使用
+type SaveClinicRequest struct {
+	Name          string  `json:"name" binding:"required,max=100"`
+	Phone         string  `json:"phone" binding:"max=20"`
+	City          string  `json:"city" binding:"max=50"`
+	Address       string  `json:"address" binding:"max=255"`
+	Latitude      float64 `json:"latitude"`
+	Longitude     float64 `json:"longitude"`
+	Description   string  `json:"description"`
+	OpeningHours  string  `json:"opening_hours" binding:"max=255"`
+	Status        *int    `json:"status" binding:"omitempty,oneof=0 1"`
+	ManagerUserID uint    `json:"manager_user_id"`
+}
+
+// ListClinicRequest 诊所列表请求
+type ListClinicRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	City     string `form:"city"`
+	Keyword  string `form:"keyword"`
+}
+
+// SaveClinicStaffRequest 添加/修改诊所人员请求
+type SaveClinicStaffRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=manager vet staff"`
+	Title  string `json:"title" binding:"max=50"`
+}
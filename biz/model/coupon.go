@@ -0,0 +1,136 @@
+package model
+
+import (
+	"time"
+)
+
+// 优惠券类型
+const (
+	CouponTypePercent = "percent" // 按比例折扣,value为折扣百分比
+	CouponTypeFixed   = "fixed"   // 固定金额立减,value为金额(分)
+)
+
+// 优惠券状态
+const (
+	CouponStatusActive   = "active"
+	CouponStatusDisabled = "disabled"
+)
+
+// 优惠券使用状态
+const (
+	RedemptionStatusUsed     = "used"
+	RedemptionStatusReleased = "released" // 订单取消后退回
+)
+
+// Coupon 优惠券,用户下单时输入券码使用
+type Coupon struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Code         string    `json:"code" gorm:"type:varchar(32);uniqueIndex;not null;comment:券码"`
+	Name         string    `json:"name" gorm:"type:varchar(100);not null;comment:名称"`
+	Type         string    `json:"type" gorm:"type:varchar(20);not null;comment:类型:percent,fixed"`
+	Value        int64     `json:"value" gorm:"not null;comment:折扣百分比或立减金额(分)"`
+	MaxDiscount  int64     `json:"max_discount" gorm:"default:0;comment:折扣券最高优惠(分),0为不限"`
+	MinSpend     int64     `json:"min_spend" gorm:"default:0;comment:最低消费(分)"`
+	OrderTypes   []string  `json:"order_types" gorm:"type:json;serializer:json;comment:适用订单类型,空为全部"`
+	StartsAt     time.Time `json:"starts_at" gorm:"not null;comment:生效时间"`
+	EndsAt       time.Time `json:"ends_at" gorm:"not null;comment:失效时间"`
+	TotalLimit   int       `json:"total_limit" gorm:"default:0;comment:总使用次数上限,0为不限"`
+	PerUserLimit int       `json:"per_user_limit" gorm:"default:1;comment:每人使用次数上限,0为不限"`
+	UsedCount    int       `json:"used_count" gorm:"default:0;comment:已使用次数"`
+	Status       string    `json:"status" gorm:"type:varchar(20);default:active;comment:状态:active,disabled"`
+	CreatedBy    uint      `json:"created_by" gorm:"comment:创建人用户ID"`
+}
+
+// TableName 指定表名
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// AppliesTo 优惠券是否适用于该订单类型
+func (c *Coupon) AppliesTo(orderType string) bool {
+	if len(c.OrderTypes) == 0 {
+		return true
+	}
+	for _, t := range c.OrderTypes {
+		if t == orderType {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount 计算订单金额可享受的优惠,不超过订单金额
+func (c *Coupon) Discount(amount int64) int64 {
+	var discount int64
+	switch c.Type {
+	case CouponTypePercent:
+		discount = amount * c.Value / 100
+		if c.MaxDiscount > 0 && discount > c.MaxDiscount {
+			discount = c.MaxDiscount
+		}
+	case CouponTypeFixed:
+		discount = c.Value
+	}
+	if discount > amount {
+		discount = amount
+	}
+	return discount
+}
+
+// CouponRedemption 优惠券使用记录,每笔订单最多使用一张券
+type CouponRedemption struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CouponID  uint      `json:"coupon_id" gorm:"index:idx_coupon_user,priority:1;not null;comment:优惠券ID"`
+	UserID    uint      `json:"user_id" gorm:"index:idx_coupon_user,priority:2;not null;comment:用户ID"`
+	OrderID   uint      `json:"order_id" gorm:"uniqueIndex;not null;comment:订单ID"`
+	Discount  int64     `json:"discount" gorm:"not null;comment:优惠金额(分)"`
+	Status    string    `json:"status" gorm:"type:varchar(20);default:used;comment:状态:used,released"`
+}
+
+// TableName 指定表名
+func (CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
+
+// SaveCouponRequest 创建/更新优惠券请求,时间为RFC3339格式
+type SaveCouponRequest struct {
+	Code         string    `json:"code" binding:"required,max=32"`
+	Name         string    `json:"name" binding:"required,max=100"`
+	Type         string    `json:"type" binding:"required,oneof=percent fixed"`
+	Value        int64     `json:"value" binding:"required,min=1"`
+	MaxDiscount  int64     `json:"max_discount" binding:"min=0"`
+	MinSpend     int64     `json:"min_spend" binding:"min=0"`
+	OrderTypes   []string  `json:"order_types"`
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required"`
+	TotalLimit   int       `json:"total_limit" binding:"min=0"`
+	PerUserLimit *int      `json:"per_user_limit" binding:"omitempty,min=0"`
+	Status       string    `json:"status" binding:"omitempty,oneof=active disabled"`
+}
+
+// ListCouponRequest 优惠券列表请求
+type ListCouponRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Status   string `form:"status"`
+	Keyword  string `form:"keyword"`
+}
+
+// CheckCouponRequest 试算优惠券请求
+type CheckCouponRequest struct {
+	Code      string `json:"code" binding:"required"`
+	OrderType string `json:"order_type" binding:"required"`
+	Amount    int64  `json:"amount" binding:"required,min=1"`
+}
+
+// CouponQuote 优惠券试算结果
+type CouponQuote struct {
+	Coupon   *Coupon `json:"coupon"`
+	Discount int64   `json:"discount"`
+	Usable   bool    `json:"usable"`
+	Reason   string  `json:"reason,omitempty"`
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"time"
+)
+
+// 日常活动水平,用于计算每日能量需求(MER)
+const (
+	DietLevelWeightLoss = "weight_loss" // 减重
+	DietLevelLow        = "low"         // 活动较少
+	DietLevelNormal     = "normal"      // 正常
+	DietLevelHigh       = "high"        // 活跃
+	DietLevelWorking    = "working"     // 工作犬/高强度运动
+)
+
+// MERFactors 成年动物各活动水平对应的MER系数(MER = RER × 系数)
+// 正常活动水平按是否绝育区分,见NeuteredMERFactors
+var MERFactors = map[string]map[string]float64{
+	"dog": {
+		DietLevelWeightLoss: 1.0,
+		DietLevelLow:        1.4,
+		DietLevelNormal:     1.8,
+		DietLevelHigh:       2.0,
+		DietLevelWorking:    3.0,
+	},
+	"cat": {
+		DietLevelWeightLoss: 0.8,
+		DietLevelLow:        1.0,
+		DietLevelNormal:     1.4,
+		DietLevelHigh:       1.6,
+	},
+}
+
+// NeuteredMERFactors 已绝育成年动物正常活动水平的MER系数
+var NeuteredMERFactors = map[string]float64{
+	"dog": 1.6,
+	"cat": 1.2,
+}
+
+// 每日摄入与目标对比状态
+const (
+	IntakeUnder   = "under"    // 不足
+	IntakeOnTrack = "on_track" // 达标(目标±10%)
+	IntakeOver    = "over"     // 超出
+)
+
+// DietPlan 饮食计划,同一宠物的计划按日期区间首尾相接
+type DietPlan struct {
+	ID            uint        `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	PetID         uint        `json:"pet_id" gorm:"index:idx_pet_start,priority:1;not null;comment:宠物ID"`
+	CreatedBy     uint        `json:"created_by" gorm:"comment:创建人用户ID"`
+	ProductID     *uint       `json:"product_id" gorm:"comment:关联的商城商品ID"`
+	FoodName      string      `json:"food_name" gorm:"type:varchar(100);not null;comment:食物名称"`
+	KcalPerKg     int         `json:"kcal_per_kg" gorm:"not null;comment:食物代谢能(千卡/千克)"`
+	PortionGrams  float64     `json:"portion_grams" gorm:"type:decimal(8,1);not null;comment:每餐份量(克)"`
+	MealsPerDay   int         `json:"meals_per_day" gorm:"not null;comment:每日餐数"`
+	MealTimes     []string    `json:"meal_times" gorm:"type:json;serializer:json;comment:喂食时间HH:MM"`
+	ActivityLevel string      `json:"activity_level" gorm:"type:varchar(20);not null;comment:活动水平:weight_loss,low,normal,high,working"`
+	Neutered      bool        `json:"neutered" gorm:"comment:是否绝育"`
+	TargetKcal    int         `json:"target_kcal" gorm:"default:0;comment:兽医指定的每日目标热量,0表示按MER计算"`
+	StartDate     time.Time   `json:"start_date" gorm:"type:date;index:idx_pet_start,priority:2;not null;comment:开始日期"`
+	EndDate       *time.Time  `json:"end_date" gorm:"type:date;comment:结束日期,为空表示进行中"`
+	Notes         string      `json:"notes" gorm:"type:varchar(500);comment:备注"`
+	IsDeleted     int         `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	PlannedKcal   int         `json:"planned_kcal" gorm:"-"`     // 按计划份量每日摄入的热量
+	Target        *DietTarget `json:"target,omitempty" gorm:"-"` // 每日能量需求
+}
+
+// TableName 指定表名
+func (DietPlan) TableName() string {
+	return "diet_plans"
+}
+
+// DietTarget 每日能量需求,RER = 70 × 体重(kg)^0.75
+type DietTarget struct {
+	WeightKg   float64 `json:"weight_kg"`
+	RER        int     `json:"rer"`
+	LifeStage  string  `json:"life_stage"` // young,adult
+	Factor     float64 `json:"factor"`
+	MER        int     `json:"mer"`
+	TargetKcal int     `json:"target_kcal"`
+	Custom     bool    `json:"custom"` // 目标热量由兽医指定
+}
+
+// FeedingLog 喂食记录
+type FeedingLog struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	PetID     uint      `json:"pet_id" gorm:"index:idx_pet_fed,priority:1;not null;comment:宠物ID"`
+	PlanID    *uint     `json:"plan_id" gorm:"index;comment:所属饮食计划ID"`
+	FedBy     uint      `json:"fed_by" gorm:"comment:喂食人用户ID"`
+	FedAt     time.Time `json:"fed_at" gorm:"index:idx_pet_fed,priority:2;not null;comment:喂食时间"`
+	FoodName  string    `json:"food_name" gorm:"type:varchar(100);not null;comment:食物名称"`
+	Grams     float64   `json:"grams" gorm:"type:decimal(8,1);not null;comment:份量(克)"`
+	Kcal      int       `json:"kcal" gorm:"not null;comment:热量(千卡)"`
+	Notes     string    `json:"notes" gorm:"type:varchar(255);comment:备注"`
+}
+
+// TableName 指定表名
+func (FeedingLog) TableName() string {
+	return "feeding_logs"
+}
+
+// SaveDietPlanRequest 创建/修改饮食计划请求
+type SaveDietPlanRequest struct {
+	ProductID     *uint    `json:"product_id"`
+	FoodName      string   `json:"food_name" binding:"max=100"`
+	KcalPerKg     int      `json:"kcal_per_kg" binding:"required,min=1"`
+	PortionGrams  float64  `json:"portion_grams" binding:"required,gt=0"`
+	MealsPerDay   int      `json:"meals_per_day" binding:"required,min=1,max=10"`
+	MealTimes     []string `json:"meal_times"`
+	ActivityLevel string   `json:"activity_level" binding:"required,oneof=weight_loss low normal high working"`
+	Neutered      bool     `json:"neutered"`
+	TargetKcal    int      `json:"target_kcal" binding:"min=0"`
+	StartDate     string   `json:"start_date"` // YYYY-MM-DD,默认今天
+	Notes         string   `json:"notes" binding:"max=500"`
+}
+
+// ListDietPlanRequest 饮食计划列表请求
+type ListDietPlanRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// CreateFeedingLogRequest 记录喂食请求
+// 热量优先使用kcal,其次按kcal_per_kg计算,都未填写时按当日饮食计划的食物计算
+type CreateFeedingLogRequest struct {
+	FedAt     *time.Time `json:"fed_at"` // 默认当前时间
+	FoodName  string     `json:"food_name" binding:"max=100"`
+	Grams     float64    `json:"grams" binding:"required,gt=0"`
+	KcalPerKg int        `json:"kcal_per_kg" binding:"min=0"`
+	Kcal      int        `json:"kcal" binding:"min=0"`
+	Notes     string     `json:"notes" binding:"max=255"`
+}
+
+// ListFeedingLogRequest 喂食记录列表请求
+type ListFeedingLogRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	From     string `form:"from"` // YYYY-MM-DD
+	To       string `form:"to"`   // YYYY-MM-DD,包含当天
+}
+
+// DietSummaryRequest 每日饮食汇总请求
+type DietSummaryRequest struct {
+	Date string `form:"date"` // YYYY-MM-DD,默认今天
+}
+
+// DietDailySummary 每日摄入与目标对比
+type DietDailySummary struct {
+	Date          string        `json:"date"`
+	Plan          *DietPlan     `json:"plan"`
+	Target        *DietTarget   `json:"target"`
+	IntakeKcal    int           `json:"intake_kcal"`
+	IntakeGrams   float64       `json:"intake_grams"`
+	MealsPlanned  int           `json:"meals_planned"`
+	MealsFed      int           `json:"meals_fed"`
+	RemainingKcal int           `json:"remaining_kcal"`
+	Percent       float64       `json:"percent"`
+	Status        string        `json:"status"` // under,on_track,over,没有目标时为空
+	Logs          []*FeedingLog `json:"logs"`
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 文档类型
+const (
+	DocumentVaccinationCertificate = "vaccination_certificate" // 疫苗接种证明
+	DocumentOrderInvoice           = "order_invoice"           // 订单发票
+	DocumentAppointmentInvoice     = "appointment_invoice"     // 门诊预约发票
+	DocumentDonationReceipt        = "donation_receipt"        // 捐款收据
+)
+
+// IssuedDocument 已签发的PDF文档,验证码印在文档上,可通过公开接口核验真伪
+// 内容未变化时重复下载复用同一验证码,内容变化后签发新验证码,旧验证码仍可核验当时的内容
+type IssuedDocument struct {
+	ID        uint            `json:"-" gorm:"primarykey"`
+	CreatedAt time.Time       `json:"issued_at"`
+	Code      string          `json:"code" gorm:"type:varchar(16);uniqueIndex;not null;comment:验证码"`
+	Kind      string          `json:"kind" gorm:"type:varchar(30);index:idx_subject_digest,priority:1;not null;comment:文档类型"`
+	SubjectID uint            `json:"subject_id" gorm:"index:idx_subject_digest,priority:2;not null;comment:宠物ID、订单ID、预约ID或捐款ID"`
+	Digest    string          `json:"-" gorm:"type:char(64);index:idx_subject_digest,priority:3;not null;comment:内容摘要SHA-256"`
+	IssuedBy  uint            `json:"-" gorm:"comment:签发时的下载用户ID"`
+	Title     string          `json:"title" gorm:"type:varchar(100);not null;comment:文档标题"`
+	Summary   json.RawMessage `json:"summary" gorm:"type:json;comment:可公开核验的文档内容摘要"`
+}
+
+// TableName 指定表名
+func (IssuedDocument) TableName() string {
+	return "issued_documents"
+}
+
+// VaccinationSummary 疫苗接种证明的核验内容,不含主人信息
+type VaccinationSummary struct {
+	PetName      string              `json:"pet_name"`
+	Species      string              `json:"species"`
+	Breed        string              `json:"breed,omitempty"`
+	Microchip    string              `json:"microchip,omitempty"`
+	Vaccinations []VaccinationDetail `json:"vaccinations"`
+}
+
+// VaccinationDetail 单次疫苗接种
+type VaccinationDetail struct {
+	Vaccine    string `json:"vaccine"`
+	BatchNo    string `json:"batch_no,omitempty"`
+	Date       string `json:"date"`
+	ValidUntil string `json:"valid_until,omitempty"`
+	Clinic     string `json:"clinic,omitempty"`
+	Vet        string `json:"vet,omitempty"`
+}
+
+// InvoiceSummary 发票的核验内容,金额单位为分
+type InvoiceSummary struct {
+	InvoiceNo      string `json:"invoice_no"`
+	Subject        string `json:"subject"`
+	Currency       string `json:"currency"`
+	TotalAmount    int64  `json:"total_amount"`
+	DiscountAmount int64  `json:"discount_amount"`
+	PayAmount      int64  `json:"pay_amount"`
+	RefundedAmount int64  `json:"refunded_amount"`
+	PaidAt         string `json:"paid_at"`
+	ItemCount      int    `json:"item_count"`
+}
+
+// DocumentVerification 公开核验结果
+type DocumentVerification struct {
+	Valid    bool            `json:"valid"`
+	Document *IssuedDocument `json:"document,omitempty"`
+	// Superseded 同一对象之后签发过内容不同的新文档,当前文档内容可能已过时
+	Superseded bool `json:"superseded"`
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"time"
+)
+
+// 募捐活动状态,截止时间已过的进行中活动视为已结束
+const (
+	CampaignStatusActive = "active" // 进行中
+	CampaignStatusClosed = "closed" // 机构提前结束
+)
+
+// 捐款状态
+const (
+	DonationStatusPending = "pending" // 待支付
+	DonationStatusPaid    = "paid"    // 已到账
+)
+
+// AnonymousDonorName 匿名捐款对外展示的名称
+const AnonymousDonorName = "匿名爱心人士"
+
+// DonationCampaign 救助机构的募捐活动,已筹金额和捐款笔数在捐款到账时增量更新
+type DonationCampaign struct {
+	ID            uint       `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	ShelterID     uint       `json:"shelter_id" gorm:"index;not null;comment:救助机构ID"`
+	CreatedBy     uint       `json:"created_by" gorm:"comment:创建人用户ID"`
+	Title         string     `json:"title" gorm:"type:varchar(100);not null;comment:活动标题"`
+	Description   string     `json:"description" gorm:"type:text;comment:活动介绍与资金用途"`
+	GoalAmount    int64      `json:"goal_amount" gorm:"not null;comment:目标金额(分)"`
+	RaisedAmount  int64      `json:"raised_amount" gorm:"default:0;comment:已筹金额(分)"`
+	DonationCount int        `json:"donation_count" gorm:"default:0;comment:到账捐款笔数"`
+	Deadline      time.Time  `json:"deadline" gorm:"index:idx_status_deadline,priority:2;not null;comment:截止时间"`
+	Status        string     `json:"status" gorm:"type:varchar(20);index:idx_status_deadline,priority:1;not null;comment:状态:active,closed"`
+	ClosedAt      *time.Time `json:"closed_at" gorm:"comment:提前结束时间"`
+	Shelter       *Shelter   `json:"shelter,omitempty" gorm:"foreignKey:ShelterID"`
+	Progress      float64    `json:"progress" gorm:"-"` // 已筹金额占目标的百分比,可超过100
+	Ended         bool       `json:"ended" gorm:"-"`    // 已截止或提前结束
+}
+
+// TableName 指定表名
+func (DonationCampaign) TableName() string {
+	return "donation_campaigns"
+}
+
+// AcceptsDonations 活动进行中且未到截止时间
+func (c *DonationCampaign) AcceptsDonations(now time.Time) bool {
+	return c.Status == CampaignStatusActive && now.Before(c.Deadline)
+}
+
+// Donation 捐款记录,通过订单支付,到账后计入活动总额
+type Donation struct {
+	ID         uint              `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	DonationNo string            `json:"donation_no" gorm:"type:varchar(32);uniqueIndex;not null;comment:捐款编号,即收据号"`
+	CampaignID uint              `json:"campaign_id" gorm:"index:idx_campaign_status,priority:1;not null;comment:募捐活动ID"`
+	ShelterID  uint              `json:"shelter_id" gorm:"index:idx_shelter_paid,priority:1;not null;comment:救助机构ID"`
+	UserID     uint              `json:"user_id" gorm:"index;not null;comment:捐款人用户ID"`
+	DonorName  string            `json:"donor_name" gorm:"type:varchar(50);comment:捐款人名称快照"`
+	Anonymous  bool              `json:"anonymous" gorm:"comment:是否匿名"`
+	Amount     int64             `json:"amount" gorm:"not null;comment:捐款金额(分)"`
+	Currency   string            `json:"currency" gorm:"type:varchar(3);not null;comment:币种"`
+	Message    string            `json:"message" gorm:"type:varchar(255);comment:留言"`
+	Status     string            `json:"status" gorm:"type:varchar(20);index:idx_campaign_status,priority:2;not null;comment:状态:pending,paid"`
+	OrderID    uint              `json:"order_id" gorm:"default:0;comment:支付成功的订单ID"`
+	PaidAt     *time.Time        `json:"paid_at" gorm:"index:idx_shelter_paid,priority:2;comment:到账时间"`
+	Campaign   *DonationCampaign `json:"campaign,omitempty" gorm:"foreignKey:CampaignID"`
+}
+
+// TableName 指定表名
+func (Donation) TableName() string {
+	return "donations"
+}
+
+// DisplayName 对外展示的捐款人名称,匿名捐款不展示真实名称
+func (d *Donation) DisplayName() string {
+	if d.Anonymous {
+		return AnonymousDonorName
+	}
+	return d.DonorName
+}
+
+// PublicDonation 公开的捐款动态,不包含捐款人用户ID
+type PublicDonation struct {
+	DonorName string    `json:"donor_name"`
+	Anonymous bool      `json:"anonymous"`
+	Amount    int64     `json:"amount"`
+	Message   string    `json:"message"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+// SaveCampaignRequest 创建/修改募捐活动请求
+type SaveCampaignRequest struct {
+	Title       string    `json:"title" binding:"required,max=100"`
+	Description string    `json:"description"`
+	GoalAmount  int64     `json:"goal_amount" binding:"required,min=100"`
+	Deadline    time.Time `json:"deadline" binding:"required"`
+}
+
+// ListCampaignRequest 募捐活动列表请求
+type ListCampaignRequest struct {
+	Page      int  `form:"page,default=1" binding:"min=1"`
+	PageSize  int  `form:"page_size,default=10" binding:"min=1,max=100"`
+	ShelterID uint `form:"shelter_id"`
+	Ended     bool `form:"ended"` // true查看已结束的活动,默认查看进行中的活动
+}
+
+// CreateDonationRequest 捐款请求,金额单位为分
+type CreateDonationRequest struct {
+	Amount    int64  `json:"amount" binding:"required,min=100"`
+	Anonymous bool   `json:"anonymous"`
+	Message   string `json:"message" binding:"max=255"`
+}
+
+// DonationCheckout 捐款下单结果,使用订单ID发起支付
+type DonationCheckout struct {
+	Donation *Donation `json:"donation"`
+	Order    *Order    `json:"order"`
+}
+
+// ListDonationRequest 捐款列表请求
+type ListDonationRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
+
+// ExportDonationRequest 导出捐款请求,按到账日期筛选
+type ExportDonationRequest struct {
+	CampaignID uint   `form:"campaign_id"`
+	From       string `form:"from"` // YYYY-MM-DD
+	To         string `form:"to"`   // YYYY-MM-DD,包含当天
+}
+
+// DonationReceiptSummary 捐款收据的核验内容,不包含捐款人信息,金额单位为分
+type DonationReceiptSummary struct {
+	ReceiptNo string `json:"receipt_no"`
+	Shelter   string `json:"shelter"`
+	Campaign  string `json:"campaign"`
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+	PaidAt    string `json:"paid_at"`
+}
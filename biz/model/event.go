@@ -0,0 +1,125 @@
+package model
+
+import (
+	"time"
+)
+
+// 活动状态
+const (
+	EventStatusPublished = "published" // 报名中
+	EventStatusCancelled = "cancelled" // 已取消
+)
+
+// 报名状态
+const (
+	RSVPStatusGoing      = "going"      // 已报名
+	RSVPStatusWaitlisted = "waitlisted" // 候补中,有人取消时按候补顺序递补
+	RSVPStatusCancelled  = "cancelled"  // 已取消
+)
+
+// Event 社区活动,如遛狗聚会、领养日
+type Event struct {
+	ID              uint       `json:"id" gorm:"primarykey"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	OrganizerID     uint       `json:"organizer_id" gorm:"index;not null;comment:组织者用户ID"`
+	Title           string     `json:"title" gorm:"type:varchar(100);not null;comment:标题"`
+	Description     string     `json:"description" gorm:"type:text;comment:活动说明"`
+	City            string     `json:"city" gorm:"type:varchar(50);index;comment:城市"`
+	Location        string     `json:"location" gorm:"type:varchar(255);not null;comment:活动地点"`
+	Latitude        float64    `json:"latitude" gorm:"type:decimal(10,7);comment:纬度"`
+	Longitude       float64    `json:"longitude" gorm:"type:decimal(10,7);comment:经度"`
+	StartAt         time.Time  `json:"start_at" gorm:"index;not null;comment:开始时间"`
+	EndAt           time.Time  `json:"end_at" gorm:"not null;comment:结束时间"`
+	Capacity        int        `json:"capacity" gorm:"not null;comment:名额"`
+	AllowedSpecies  []string   `json:"allowed_species" gorm:"type:json;serializer:json;comment:允许携带的物种,为空表示不限"`
+	RemindBeforeMin int        `json:"remind_before_min" gorm:"comment:开始前多少分钟提醒,0表示不提醒"`
+	Status          string     `json:"status" gorm:"type:varchar(20);default:published;comment:状态:published,cancelled"`
+	AttendeeCount   int        `json:"attendee_count" gorm:"default:0;comment:已报名人数"`
+	WaitlistCount   int        `json:"waitlist_count" gorm:"default:0;comment:候补人数"`
+	RemindedAt      *time.Time `json:"-" gorm:"comment:开始前提醒发送时间"`
+	Organizer       *User      `json:"organizer,omitempty" gorm:"foreignKey:OrganizerID"`
+}
+
+// TableName 指定表名
+func (Event) TableName() string {
+	return "events"
+}
+
+// AllowsSpecies 是否允许携带该物种
+func (e *Event) AllowsSpecies(species string) bool {
+	if len(e.AllowedSpecies) == 0 {
+		return true
+	}
+	for _, s := range e.AllowedSpecies {
+		if s == species {
+			return true
+		}
+	}
+	return false
+}
+
+// EventRSVP 活动报名,同一用户对同一活动只有一条记录,取消后可重新报名
+type EventRSVP struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	EventID   uint      `json:"event_id" gorm:"uniqueIndex:idx_event_user,priority:1;index:idx_event_status_joined,priority:1;not null;comment:活动ID"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_event_user,priority:2;index;not null;comment:用户ID"`
+	Status    string    `json:"status" gorm:"type:varchar(20);index:idx_event_status_joined,priority:2;not null;comment:状态:going,waitlisted,cancelled"`
+	PetIDs    []uint    `json:"pet_ids" gorm:"type:json;serializer:json;comment:携带的宠物"`
+	Note      string    `json:"note" gorm:"type:varchar(200);comment:备注"`
+	JoinedAt  time.Time `json:"joined_at" gorm:"index:idx_event_status_joined,priority:3;comment:报名或进入候补的时间,决定递补顺序"`
+	// WaitlistPosition 候补中时的排队位置,从1开始
+	WaitlistPosition int    `json:"waitlist_position,omitempty" gorm:"-"`
+	User             *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Event            *Event `json:"event,omitempty" gorm:"foreignKey:EventID"`
+}
+
+// TableName 指定表名
+func (EventRSVP) TableName() string {
+	return "event_rsvps"
+}
+
+// SaveEventRequest 创建/更新活动请求
+type SaveEventRequest struct {
+	Title           string    `json:"title" binding:"required,max=100"`
+	Description     string    `json:"description"`
+	City            string    `json:"city" binding:"max=50"`
+	Location        string    `json:"location" binding:"required,max=255"`
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	StartAt         time.Time `json:"start_at" binding:"required"`
+	EndAt           time.Time `json:"end_at" binding:"required"`
+	Capacity        int       `json:"capacity" binding:"required,min=1"`
+	AllowedSpecies  []string  `json:"allowed_species"`
+	RemindBeforeMin *int      `json:"remind_before_min" binding:"omitempty,min=0,max=10080"`
+}
+
+// ListEventRequest 活动列表请求,默认只返回未结束的活动
+type ListEventRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	City     string `form:"city"`
+	Species  string `form:"species"`
+	Keyword  string `form:"keyword"`
+}
+
+// ListMyEventsRequest 我组织的活动或我的报名请求
+type ListMyEventsRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=10" binding:"min=1,max=100"`
+}
+
+// RSVPRequest 报名请求
+type RSVPRequest struct {
+	PetIDs []uint `json:"pet_ids"`
+	Note   string `json:"note" binding:"max=200"`
+}
+
+// ListRSVPRequest 报名名单请求
+type ListRSVPRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Status   string `form:"status" binding:"omitempty,oneof=going waitlisted cancelled"`
+}
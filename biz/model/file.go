@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+)
+
+// 文件用途
+const (
+	FilePurposeAvatar     = "avatar"
+	FilePurposePetPhoto   = "pet_photo"
+	FilePurposeAttachment = "attachment"
+)
+
+// 下载文件的版本
+const (
+	FileVariantOriginal  = "original"
+	FileVariantThumbnail = "thumb"
+)
+
+// File 上传文件模型
+type File struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	UserID       uint      `json:"user_id" gorm:"index;not null;comment:上传用户ID"`
+	Purpose      string    `json:"purpose" gorm:"type:varchar(20);not null;comment:用途:avatar,pet_photo,attachment"`
+	StorageKey   string    `json:"-" gorm:"type:varchar(255);not null;comment:存储Key"`
+	ThumbnailKey string    `json:"-" gorm:"type:varchar(255);comment:缩略图存储Key"`
+	OriginalName string    `json:"original_name" gorm:"type:varchar(255);comment:原始文件名"`
+	ContentType  string    `json:"content_type" gorm:"type:varchar(100);comment:文件类型"`
+	Size         int64     `json:"size" gorm:"comment:文件大小(字节)"`
+	Width        int       `json:"width" gorm:"comment:图片宽度"`
+	Height       int       `json:"height" gorm:"comment:图片高度"`
+	IsDeleted    int       `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	URL          string    `json:"url" gorm:"-"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty" gorm:"-"`
+	ExpiresAt    int64     `json:"expires_at" gorm:"-"`
+}
+
+// TableName 指定表名
+func (File) TableName() string {
+	return "files"
+}
+
+// IsImage 是否为图片
+func (f *File) IsImage() bool {
+	return f.Width > 0 && f.Height > 0
+}
+
+// UploadFileRequest 上传文件请求,文件通过multipart的file字段提交
+type UploadFileRequest struct {
+	Purpose string `form:"purpose" binding:"required,oneof=avatar pet_photo attachment"`
+}
+
+// DownloadFileRequest 下载文件请求
+type DownloadFileRequest struct {
+	Variant   string `form:"variant"`
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+)
+
+// UserFollow 用户关注关系
+type UserFollow struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	FollowerID uint      `json:"follower_id" gorm:"uniqueIndex:idx_follower_followee,priority:1;not null;comment:关注者用户ID"`
+	FolloweeID uint      `json:"followee_id" gorm:"uniqueIndex:idx_follower_followee,priority:2;index;not null;comment:被关注用户ID"`
+	Follower   *User     `json:"follower,omitempty" gorm:"foreignKey:FollowerID"`
+	Followee   *User     `json:"followee,omitempty" gorm:"foreignKey:FolloweeID"`
+}
+
+// TableName 指定表名
+func (UserFollow) TableName() string {
+	return "user_follows"
+}
+
+// PetFollow 用户关注宠物
+type PetFollow struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_user_pet,priority:1;not null;comment:关注者用户ID"`
+	PetID     uint      `json:"pet_id" gorm:"uniqueIndex:idx_user_pet,priority:2;index;not null;comment:被关注宠物ID"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Pet       *Pet      `json:"pet,omitempty" gorm:"foreignKey:PetID"`
+}
+
+// TableName 指定表名
+func (PetFollow) TableName() string {
+	return "pet_follows"
+}
+
+// UserBlock 用户拉黑关系,双方互相看不到对方的内容
+type UserBlock struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	BlockerID uint      `json:"blocker_id" gorm:"uniqueIndex:idx_blocker_blocked,priority:1;not null;comment:拉黑者用户ID"`
+	BlockedID uint      `json:"blocked_id" gorm:"uniqueIndex:idx_blocker_blocked,priority:2;index;not null;comment:被拉黑用户ID"`
+	Blocked   *User     `json:"blocked,omitempty" gorm:"foreignKey:BlockedID"`
+}
+
+// TableName 指定表名
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
+
+// UserRelation 当前用户与目标用户的关系及目标用户的关注计数
+type UserRelation struct {
+	UserID       uint  `json:"user_id"`
+	Followers    int64 `json:"followers"`
+	Following    int64 `json:"following"`
+	IsFollowing  bool  `json:"is_following"`   // 我关注了对方
+	IsFollowedBy bool  `json:"is_followed_by"` // 对方关注了我
+	IsMutual     bool  `json:"is_mutual"`
+	Blocked      bool  `json:"blocked"` // 我拉黑了对方
+}
+
+// PetFollowStats 宠物关注计数
+type PetFollowStats struct {
+	PetID       uint  `json:"pet_id"`
+	Followers   int64 `json:"followers"`
+	IsFollowing bool  `json:"is_following"`
+}
+
+// CursorRequest 游标分页请求,按记录ID倒序
+type CursorRequest struct {
+	Cursor uint `form:"cursor"` // 上一页返回的next_cursor,为空从最新开始
+	Limit  int  `form:"limit,default=20" binding:"min=1,max=100"`
+}
+
+// UserFollowPage 用户关注/粉丝列表,next_cursor为0表示没有更多
+type UserFollowPage struct {
+	List       []*UserFollow `json:"list"`
+	NextCursor uint          `json:"next_cursor"`
+}
+
+// PetFollowPage 宠物关注列表,next_cursor为0表示没有更多
+type PetFollowPage struct {
+	List       []*PetFollow `json:"list"`
+	NextCursor uint         `json:"next_cursor"`
+}
+
+// UserBlockPage 黑名单列表,next_cursor为0表示没有更多
+type UserBlockPage struct {
+	List       []*UserBlock `json:"list"`
+	NextCursor uint         `json:"next_cursor"`
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"time"
+)
+
+// 理赔状态
+const (
+	ClaimStatusDraft     = "draft"     // 草稿,可编辑
+	ClaimStatusSubmitted = "submitted" // 已提交给保险公司
+	ClaimStatusInReview  = "in_review" // 审核中
+	ClaimStatusApproved  = "approved"  // 已核准,待赔付
+	ClaimStatusDenied    = "denied"    // 已拒赔
+	ClaimStatusPaid      = "paid"      // 已赔付
+	ClaimStatusWithdrawn = "withdrawn" // 已撤回
+)
+
+// ClaimTransitions 理赔状态流转,键为当前状态,值为允许变更到的状态
+var ClaimTransitions = map[string][]string{
+	ClaimStatusDraft:     {ClaimStatusSubmitted, ClaimStatusWithdrawn},
+	ClaimStatusSubmitted: {ClaimStatusInReview, ClaimStatusApproved, ClaimStatusDenied, ClaimStatusWithdrawn},
+	ClaimStatusInReview:  {ClaimStatusApproved, ClaimStatusDenied},
+	ClaimStatusApproved:  {ClaimStatusPaid},
+}
+
+// CanTransitClaim 判断理赔能否从from变更到to
+func CanTransitClaim(from, to string) bool {
+	for _, status := range ClaimTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// InsurancePolicy 宠物保险保单,金额单位为分
+type InsurancePolicy struct {
+	ID                uint      `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	PetID             uint      `json:"pet_id" gorm:"uniqueIndex:uk_pet_policy_no,priority:1;not null;comment:宠物ID"`
+	CreatedBy         uint      `json:"created_by" gorm:"comment:创建人用户ID"`
+	Provider          string    `json:"provider" gorm:"type:varchar(100);not null;comment:保险公司"`
+	PolicyNumber      string    `json:"policy_number" gorm:"type:varchar(64);uniqueIndex:uk_pet_policy_no,priority:2;not null;comment:保单号"`
+	Coverage          string    `json:"coverage" gorm:"type:varchar(500);comment:保障范围"`
+	CoverageLimit     int64     `json:"coverage_limit" gorm:"default:0;comment:保额(分),0表示不限"`
+	Deductible        int64     `json:"deductible" gorm:"default:0;comment:免赔额(分)"`
+	ReimbursementRate int       `json:"reimbursement_rate" gorm:"comment:赔付比例(%)"`
+	StartDate         time.Time `json:"start_date" gorm:"type:date;not null;comment:生效日期"`
+	EndDate           time.Time `json:"end_date" gorm:"type:date;not null;comment:到期日期"`
+	Notes             string    `json:"notes" gorm:"type:text;comment:备注"`
+	IsDeleted         int       `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	Active            bool      `json:"active" gorm:"-"`
+}
+
+// TableName 指定表名
+func (InsurancePolicy) TableName() string {
+	return "insurance_policies"
+}
+
+// Covers 判断日期是否在保单有效期内
+func (p *InsurancePolicy) Covers(date time.Time) bool {
+	return !date.Before(p.StartDate) && !date.After(p.EndDate)
+}
+
+// InsuranceClaim 理赔申请,可关联就诊记录或发票号,金额单位为分
+type InsuranceClaim struct {
+	ID                uint                   `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	PolicyID          uint                   `json:"policy_id" gorm:"index;not null;comment:保单ID"`
+	PetID             uint                   `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	CreatedBy         uint                   `json:"created_by" gorm:"comment:创建人用户ID"`
+	MedicalRecordID   uint                   `json:"medical_record_id" gorm:"index;comment:关联就诊记录ID,0表示未关联"`
+	InvoiceNo         string                 `json:"invoice_no" gorm:"type:varchar(64);comment:发票号"`
+	TreatmentDate     time.Time              `json:"treatment_date" gorm:"type:date;not null;comment:治疗日期"`
+	Description       string                 `json:"description" gorm:"type:varchar(500);comment:理赔说明"`
+	ClaimedAmount     int64                  `json:"claimed_amount" gorm:"not null;comment:申请金额(分)"`
+	ApprovedAmount    int64                  `json:"approved_amount" gorm:"default:0;comment:核准金额(分)"`
+	PaidAmount        int64                  `json:"paid_amount" gorm:"default:0;comment:实际赔付金额(分)"`
+	Status            string                 `json:"status" gorm:"type:varchar(20);index;default:draft;comment:状态:draft,submitted,in_review,approved,denied,paid,withdrawn"`
+	StatusNote        string                 `json:"status_note" gorm:"type:varchar(500);comment:最近一次状态说明,如拒赔原因"`
+	SubmittedAt       *time.Time             `json:"submitted_at" gorm:"comment:提交时间"`
+	DecidedAt         *time.Time             `json:"decided_at" gorm:"comment:核准或拒赔时间"`
+	PaidAt            *time.Time             `json:"paid_at" gorm:"comment:赔付时间"`
+	AttachmentFileIDs []uint                 `json:"attachment_file_ids" gorm:"type:json;serializer:json;comment:附件文件ID(发票、病历等)"`
+	IsDeleted         int                    `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	Attachments       []string               `json:"attachments,omitempty" gorm:"-"`
+	Policy            *InsurancePolicy       `json:"policy,omitempty" gorm:"foreignKey:PolicyID"`
+	Events            []*InsuranceClaimEvent `json:"events,omitempty" gorm:"foreignKey:ClaimID"`
+}
+
+// TableName 指定表名
+func (InsuranceClaim) TableName() string {
+	return "insurance_claims"
+}
+
+// InsuranceClaimEvent 理赔状态变更历史
+type InsuranceClaimEvent struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	ClaimID    uint      `json:"claim_id" gorm:"index;not null;comment:理赔ID"`
+	UserID     uint      `json:"user_id" gorm:"comment:操作人用户ID"`
+	FromStatus string    `json:"from_status" gorm:"type:varchar(20);comment:原状态"`
+	ToStatus   string    `json:"to_status" gorm:"type:varchar(20);not null;comment:新状态"`
+	Amount     int64     `json:"amount" gorm:"default:0;comment:核准或赔付金额(分)"`
+	Note       string    `json:"note" gorm:"type:varchar(500);comment:说明"`
+}
+
+// TableName 指定表名
+func (InsuranceClaimEvent) TableName() string {
+	return "insurance_claim_events"
+}
+
+// SaveInsurancePolicyRequest 创建/更新保单请求,日期格式YYYY-MM-DD
+type SaveInsurancePolicyRequest struct {
+	Provider          string `json:"provider" binding:"required,max=100"`
+	PolicyNumber      string `json:"policy_number" binding:"required,max=64"`
+	Coverage          string `json:"coverage" binding:"max=500"`
+	CoverageLimit     int64  `json:"coverage_limit" binding:"min=0"`
+	Deductible        int64  `json:"deductible" binding:"min=0"`
+	ReimbursementRate int    `json:"reimbursement_rate" binding:"min=0,max=100"`
+	StartDate         string `json:"start_date" binding:"required"`
+	EndDate           string `json:"end_date" binding:"required"`
+	Notes             string `json:"notes"`
+}
+
+// SaveInsuranceClaimRequest 创建/更新理赔请求,仅草稿可修改
+type SaveInsuranceClaimRequest struct {
+	MedicalRecordID   uint   `json:"medical_record_id"`
+	InvoiceNo         string `json:"invoice_no" binding:"max=64"`
+	TreatmentDate     string `json:"treatment_date"`
+	Description       string `json:"description" binding:"max=500"`
+	ClaimedAmount     int64  `json:"claimed_amount" binding:"required,min=1"`
+	AttachmentFileIDs []uint `json:"attachment_file_ids" binding:"omitempty,max=20"`
+}
+
+// UpdateClaimStatusRequest 变更理赔状态请求,amount为核准或赔付金额
+type UpdateClaimStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Amount int64  `json:"amount" binding:"min=0"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// ListInsuranceClaimRequest 理赔列表请求
+type ListInsuranceClaimRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	PolicyID uint   `form:"policy_id"`
+	Status   string `form:"status"`
+	Year     int    `form:"year"`
+}
+
+// InsuranceSummaryRequest 理赔汇总请求,year为空时汇总所有年份
+type InsuranceSummaryRequest struct {
+	Year int `form:"year"`
+}
+
+// ClaimAmountRow 按年份、保单和状态聚合的理赔金额
+type ClaimAmountRow struct {
+	Year     int    `json:"year"`
+	PolicyID uint   `json:"policy_id"`
+	Status   string `json:"status"`
+	Count    int64  `json:"count"`
+	Claimed  int64  `json:"claimed"`
+	Approved int64  `json:"approved"`
+	Paid     int64  `json:"paid"`
+}
+
+// ClaimTotals 理赔金额汇总,单位为分
+// Outstanding为已提交尚未结案的申请金额加上已核准尚未赔付的核准金额
+type ClaimTotals struct {
+	ClaimCount  int64 `json:"claim_count"`
+	Claimed     int64 `json:"claimed"`
+	Approved    int64 `json:"approved"`
+	Reimbursed  int64 `json:"reimbursed"`
+	Outstanding int64 `json:"outstanding"`
+	Denied      int64 `json:"denied"`
+}
+
+// Add 累加一行聚合结果,草稿和已撤回的理赔不计入
+func (t *ClaimTotals) Add(row *ClaimAmountRow) {
+	switch row.Status {
+	case ClaimStatusDraft, ClaimStatusWithdrawn:
+		return
+	case ClaimStatusSubmitted, ClaimStatusInReview:
+		t.Outstanding += row.Claimed
+	case ClaimStatusApproved:
+		t.Approved += row.Approved
+		t.Outstanding += row.Approved
+	case ClaimStatusPaid:
+		t.Approved += row.Approved
+		t.Reimbursed += row.Paid
+	case ClaimStatusDenied:
+		t.Denied += row.Claimed
+	}
+	t.ClaimCount += row.Count
+	t.Claimed += row.Claimed
+}
+
+// PolicyClaimSummary 单个保单的年度理赔汇总
+type PolicyClaimSummary struct {
+	PolicyID     uint   `json:"policy_id"`
+	Provider     string `json:"provider"`
+	PolicyNumber string `json:"policy_number"`
+	ClaimTotals
+}
+
+// YearClaimSummary 年度理赔汇总
+type YearClaimSummary struct {
+	Year int `json:"year"`
+	ClaimTotals
+	Policies []*PolicyClaimSummary `json:"policies"`
+}
+
+// InsuranceSummary 宠物保险理赔汇总
+type InsuranceSummary struct {
+	PetID uint                `json:"pet_id"`
+	Years []*YearClaimSummary `json:"years"`
+	Total ClaimTotals         `json:"total"`
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"time"
+)
+
+// 走失/招领类型
+const (
+	ReportTypeLost  = "lost"
+	ReportTypeFound = "found"
+)
+
+// 走失/招领状态
+const (
+	ReportStatusOpen     = "open"
+	ReportStatusResolved = "resolved"
+)
+
+// LostFoundReport 走失/招领信息模型
+type LostFoundReport struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	UserID       uint      `json:"user_id" gorm:"index;not null;comment:发布人用户ID"`
+	PetID        uint      `json:"pet_id" gorm:"comment:关联宠物ID,走失时可选"`
+	Type         string    `json:"type" gorm:"type:varchar(10);index;not null;comment:类型:lost,found"`
+	Species      string    `json:"species" gorm:"type:varchar(20);index;not null;comment:物种"`
+	Breed        string    `json:"breed" gorm:"type:varchar(50);comment:品种"`
+	Color        string    `json:"color" gorm:"type:varchar(30);comment:毛色"`
+	Photos       []string  `json:"photos" gorm:"type:json;serializer:json;comment:照片"`
+	Latitude     float64   `json:"latitude" gorm:"type:decimal(10,7);comment:最后出现位置纬度"`
+	Longitude    float64   `json:"longitude" gorm:"type:decimal(10,7);comment:最后出现位置经度"`
+	Address      string    `json:"address" gorm:"type:varchar(255);comment:地址描述"`
+	OccurredAt   time.Time `json:"occurred_at" gorm:"comment:走失/发现时间"`
+	Description  string    `json:"description" gorm:"type:text;comment:描述"`
+	ContactPhone string    `json:"contact_phone" gorm:"type:varchar(20);comment:联系电话"`
+	Status       string    `json:"status" gorm:"type:varchar(20);index;default:open;comment:状态:open,resolved"`
+}
+
+// TableName 指定表名
+func (LostFoundReport) TableName() string {
+	return "lost_found_reports"
+}
+
+// OppositeType 匹配时对应的信息类型
+func (r *LostFoundReport) OppositeType() string {
+	if r.Type == ReportTypeLost {
+		return ReportTypeFound
+	}
+	return ReportTypeLost
+}
+
+// CreateReportRequest 发布走失/招领信息请求
+type CreateReportRequest struct {
+	Type         string    `json:"type" binding:"required,oneof=lost found"`
+	PetID        uint      `json:"pet_id"`
+	Species      string    `json:"species" binding:"required,max=20"`
+	Breed        string    `json:"breed" binding:"omitempty,max=50"`
+	Color        string    `json:"color" binding:"omitempty,max=30"`
+	Photos       []string  `json:"photos" binding:"omitempty,max=9"`
+	Latitude     float64   `json:"latitude" binding:"min=-90,max=90"`
+	Longitude    float64   `json:"longitude" binding:"min=-180,max=180"`
+	Address      string    `json:"address" binding:"omitempty,max=255"`
+	OccurredAt   time.Time `json:"occurred_at" binding:"required"`
+	Description  string    `json:"description"`
+	ContactPhone string    `json:"contact_phone" binding:"omitempty,max=20"`
+}
+
+// NearbyReportRequest 附近走失/招领信息查询请求
+type NearbyReportRequest struct {
+	Latitude  float64 `form:"lat" binding:"min=-90,max=90"`
+	Longitude float64 `form:"lng" binding:"min=-180,max=180"`
+	RadiusKm  float64 `form:"radius_km" binding:"omitempty,gt=0,lte=100"`
+	Type      string  `form:"type" binding:"omitempty,oneof=lost found"`
+	Species   string  `form:"species"`
+	Limit     int     `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// ListReportRequest 我的走失/招领信息列表请求
+type ListReportRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Status   string `form:"status"`
+}
+
+// NearbyReport 附近的走失/招领信息
+type NearbyReport struct {
+	*LostFoundReport
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// ReportMatch 走失与招领信息的疑似匹配
+type ReportMatch struct {
+	Report     *LostFoundReport `json:"report"`
+	DistanceKm float64          `json:"distance_km"`
+	Score      float64          `json:"score"`
+	Reasons    []string         `json:"reasons"`
+}
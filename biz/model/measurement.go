@@ -0,0 +1,139 @@
+package model
+
+import (
+	"time"
+)
+
+// 健康指标类型
+const (
+	MetricWeight          = "weight"
+	MetricBodyCondition   = "body_condition"
+	MetricTemperature     = "temperature"
+	MetricHeartRate       = "heart_rate"
+	MetricRespiratoryRate = "respiratory_rate"
+)
+
+// 降采样粒度
+const (
+	IntervalRaw  = "raw"
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+// MetricSpec 指标的单位与合理取值范围
+type MetricSpec struct {
+	Unit string
+	Min  float64
+	Max  float64
+}
+
+// MetricSpecs 支持的健康指标
+var MetricSpecs = map[string]MetricSpec{
+	MetricWeight:          {Unit: "kg", Min: 0.01, Max: 200},
+	MetricBodyCondition:   {Unit: "score", Min: 1, Max: 9},
+	MetricTemperature:     {Unit: "celsius", Min: 30, Max: 45},
+	MetricHeartRate:       {Unit: "bpm", Min: 10, Max: 400},
+	MetricRespiratoryRate: {Unit: "breaths/min", Min: 5, Max: 200},
+}
+
+// PetMeasurement 宠物健康指标测量记录
+type PetMeasurement struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	PetID      uint      `json:"pet_id" gorm:"index:idx_pet_metric_time,priority:1;not null;comment:宠物ID"`
+	Metric     string    `json:"metric" gorm:"type:varchar(30);index:idx_pet_metric_time,priority:2;not null;comment:指标类型"`
+	Value      float64   `json:"value" gorm:"type:decimal(10,3);not null;comment:测量值"`
+	Unit       string    `json:"unit" gorm:"type:varchar(20);comment:单位"`
+	MeasuredAt time.Time `json:"measured_at" gorm:"index:idx_pet_metric_time,priority:3;not null;comment:测量时间"`
+	Note       string    `json:"note" gorm:"type:varchar(255);comment:备注"`
+	RecordedBy uint      `json:"recorded_by" gorm:"comment:记录人用户ID"`
+}
+
+// TableName 指定表名
+func (PetMeasurement) TableName() string {
+	return "pet_measurements"
+}
+
+// Breed 品种目录
+type Breed struct {
+	ID          uint    `json:"id" gorm:"primarykey"`
+	Species     string  `json:"species" gorm:"type:varchar(20);uniqueIndex:idx_species_name,priority:1;not null;comment:物种"`
+	Name        string  `json:"name" gorm:"type:varchar(50);uniqueIndex:idx_species_name,priority:2;not null;comment:品种名称"`
+	Size        string  `json:"size" gorm:"type:varchar(10);comment:体型:small,medium,large"`
+	MinWeightKg float64 `json:"min_weight_kg" gorm:"type:decimal(6,2);comment:成年体重下限(kg)"`
+	MaxWeightKg float64 `json:"max_weight_kg" gorm:"type:decimal(6,2);comment:成年体重上限(kg)"`
+}
+
+// TableName 指定表名
+func (Breed) TableName() string {
+	return "breeds"
+}
+
+// MeasurementItem 单条测量数据
+type MeasurementItem struct {
+	Metric     string    `json:"metric" binding:"required"`
+	Value      float64   `json:"value" binding:"required"`
+	MeasuredAt time.Time `json:"measured_at" binding:"required"`
+	Note       string    `json:"note" binding:"omitempty,max=255"`
+}
+
+// CreateMeasurementsRequest 批量记录测量数据请求
+type CreateMeasurementsRequest struct {
+	Items []MeasurementItem `json:"items" binding:"required,min=1,max=500"`
+}
+
+// QueryMeasurementsRequest 测量数据区间查询请求
+type QueryMeasurementsRequest struct {
+	Metric   string     `form:"metric" binding:"required"`
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
+	Interval string     `form:"interval" binding:"omitempty,oneof=raw day week"`
+}
+
+// MeasurementAlertRequest 体重异常检查请求,未传参数时使用配置的默认值
+type MeasurementAlertRequest struct {
+	WindowDays   int     `form:"window_days" binding:"omitempty,min=1,max=365"`
+	ThresholdPct float64 `form:"threshold_pct" binding:"omitempty,gt=0,lte=100"`
+}
+
+// ListBreedRequest 品种目录查询请求
+type ListBreedRequest struct {
+	Species string `form:"species"`
+	Keyword string `form:"keyword"`
+}
+
+// MeasurementBucket 降采样后的时间桶
+type MeasurementBucket struct {
+	Bucket time.Time `json:"bucket"`
+	Avg    float64   `json:"avg"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	Count  int64     `json:"count"`
+}
+
+// 健康提醒类型
+const (
+	AlertWeightGain      = "weight_gain"
+	AlertWeightLoss      = "weight_loss"
+	AlertAboveBreedRange = "above_breed_range"
+	AlertBelowBreedRange = "below_breed_range"
+)
+
+// MeasurementAlert 健康指标提醒
+type MeasurementAlert struct {
+	Type    string  `json:"type"`
+	Metric  string  `json:"metric"`
+	Message string  `json:"message"`
+	Value   float64 `json:"value"`
+}
+
+// MeasurementSeries 测量数据查询结果,raw返回原始记录,day/week返回聚合桶
+type MeasurementSeries struct {
+	Metric   string               `json:"metric"`
+	Unit     string               `json:"unit"`
+	Interval string               `json:"interval"`
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Items    []*PetMeasurement    `json:"items,omitempty"`
+	Buckets  []*MeasurementBucket `json:"buckets,omitempty"`
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+)
+
+// 就诊记录类型
+const (
+	MedicalRecordCheckup     = "checkup"     // 体检
+	MedicalRecordVaccination = "vaccination" // 疫苗接种
+	MedicalRecordIllness     = "illness"     // 疾病诊疗
+	MedicalRecordSurgery     = "surgery"     // 手术
+	MedicalRecordDental      = "dental"      // 口腔
+	MedicalRecordOther       = "other"       // 其他
+)
+
+// MedicalRecordTypes 支持的就诊记录类型
+var MedicalRecordTypes = map[string]bool{
+	MedicalRecordCheckup:     true,
+	MedicalRecordVaccination: true,
+	MedicalRecordIllness:     true,
+	MedicalRecordSurgery:     true,
+	MedicalRecordDental:      true,
+	MedicalRecordOther:       true,
+}
+
+// MedicalRecord 宠物就诊记录,费用单位为分
+type MedicalRecord struct {
+	ID                uint       `json:"id" gorm:"primarykey"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	PetID             uint       `json:"pet_id" gorm:"index:idx_pet_visit,priority:1;not null;comment:宠物ID"`
+	RecordedBy        uint       `json:"recorded_by" gorm:"comment:记录人用户ID"`
+	Type              string     `json:"type" gorm:"type:varchar(20);not null;comment:类型:checkup,vaccination,illness,surgery,dental,other"`
+	VisitDate         time.Time  `json:"visit_date" gorm:"type:date;index:idx_pet_visit,priority:2;not null;comment:就诊日期"`
+	ClinicName        string     `json:"clinic_name" gorm:"type:varchar(100);comment:就诊机构"`
+	VetName           string     `json:"vet_name" gorm:"type:varchar(50);comment:兽医"`
+	Diagnosis         string     `json:"diagnosis" gorm:"type:varchar(500);comment:诊断"`
+	Treatment         string     `json:"treatment" gorm:"type:text;comment:治疗方案"`
+	Notes             string     `json:"notes" gorm:"type:text;comment:备注"`
+	Cost              int64      `json:"cost" gorm:"default:0;comment:费用(分)"`
+	InvoiceNo         string     `json:"invoice_no" gorm:"type:varchar(64);comment:发票号"`
+	VaccineName       string     `json:"vaccine_name" gorm:"type:varchar(100);comment:疫苗名称,仅疫苗接种记录"`
+	BatchNo           string     `json:"batch_no" gorm:"type:varchar(50);comment:疫苗批号"`
+	ValidUntil        *time.Time `json:"valid_until" gorm:"type:date;comment:免疫有效期至"`
+	AttachmentFileIDs []uint     `json:"attachment_file_ids" gorm:"type:json;serializer:json;comment:附件文件ID(病历、发票等)"`
+	IsDeleted         int        `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	Attachments       []string   `json:"attachments,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (MedicalRecord) TableName() string {
+	return "medical_records"
+}
+
+// SaveMedicalRecordRequest 创建/更新就诊记录请求
+type SaveMedicalRecordRequest struct {
+	Type              string `json:"type" binding:"required,oneof=checkup vaccination illness surgery dental other"`
+	VisitDate         string `json:"visit_date" binding:"required"`
+	ClinicName        string `json:"clinic_name" binding:"max=100"`
+	VetName           string `json:"vet_name" binding:"max=50"`
+	Diagnosis         string `json:"diagnosis" binding:"max=500"`
+	Treatment         string `json:"treatment"`
+	Notes             string `json:"notes"`
+	Cost              int64  `json:"cost" binding:"min=0"`
+	InvoiceNo         string `json:"invoice_no" binding:"max=64"`
+	VaccineName       string `json:"vaccine_name" binding:"max=100"` // 疫苗接种记录必填
+	BatchNo           string `json:"batch_no" binding:"max=50"`
+	ValidUntil        string `json:"valid_until"` // 免疫有效期至,格式YYYY-MM-DD
+	AttachmentFileIDs []uint `json:"attachment_file_ids" binding:"omitempty,max=20"`
+}
+
+// ListMedicalRecordRequest 就诊记录列表请求,日期格式YYYY-MM-DD
+type ListMedicalRecordRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Type     string `form:"type"`
+	From     string `form:"from"`
+	To       string `form:"to"`
+}
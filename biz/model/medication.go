@@ -0,0 +1,181 @@
+package model
+
+import (
+	"time"
+)
+
+// 用药计划频率规则
+const (
+	ScheduleInterval = "interval"
+	ScheduleCron     = "cron"
+)
+
+// 用药计划状态
+const (
+	MedicationStatusActive = "active"
+	MedicationStatusPaused = "paused"
+	MedicationStatusEnded  = "ended"
+)
+
+// 给药记录状态
+const (
+	DoseStatusScheduled = "scheduled"
+	DoseStatusGiven     = "given"
+	DoseStatusSkipped   = "skipped"
+	DoseStatusMissed    = "missed"
+)
+
+// MedicationPlan 用药计划
+type MedicationPlan struct {
+	ID              uint       `json:"id" gorm:"primarykey"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	PetID           uint       `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	CreatedBy       uint       `json:"created_by" gorm:"comment:创建人用户ID"`
+	Drug            string     `json:"drug" gorm:"type:varchar(100);not null;comment:药品名称"`
+	Dose            float64    `json:"dose" gorm:"type:decimal(10,3);not null;comment:单次剂量"`
+	Unit            string     `json:"unit" gorm:"type:varchar(20);not null;comment:剂量单位,如mg、ml、片"`
+	ScheduleType    string     `json:"schedule_type" gorm:"type:varchar(10);not null;comment:频率规则:interval,cron"`
+	IntervalHours   int        `json:"interval_hours" gorm:"default:0;comment:间隔小时数"`
+	Cron            string     `json:"cron" gorm:"type:varchar(100);comment:cron表达式(分 时 日 月 周)"`
+	StartAt         time.Time  `json:"start_at" gorm:"not null;comment:开始时间"`
+	EndAt           *time.Time `json:"end_at" gorm:"comment:结束时间,为空表示长期"`
+	RemindBeforeMin int        `json:"remind_before_min" gorm:"default:0;comment:提前提醒分钟数"`
+	Instructions    string     `json:"instructions" gorm:"type:varchar(500);comment:用药说明"`
+	Status          string     `json:"status" gorm:"type:varchar(20);index;default:active;comment:状态:active,paused,ended"`
+	GeneratedUntil  time.Time  `json:"-" gorm:"comment:已生成给药记录的截止时间"`
+}
+
+// TableName 指定表名
+func (MedicationPlan) TableName() string {
+	return "medication_plans"
+}
+
+// MedicationDose 按计划生成的单次给药记录
+type MedicationDose struct {
+	ID          uint            `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	PlanID      uint            `json:"plan_id" gorm:"uniqueIndex:idx_plan_time,priority:1;not null;comment:用药计划ID"`
+	PetID       uint            `json:"pet_id" gorm:"index:idx_pet_time,priority:1;not null;comment:宠物ID"`
+	ScheduledAt time.Time       `json:"scheduled_at" gorm:"uniqueIndex:idx_plan_time,priority:2;index:idx_pet_time,priority:2;index:idx_status_time,priority:2;not null;comment:计划给药时间"`
+	Status      string          `json:"status" gorm:"type:varchar(20);index:idx_status_time,priority:1;default:scheduled;comment:状态:scheduled,given,skipped,missed"`
+	ActedAt     *time.Time      `json:"acted_at" gorm:"comment:实际给药/跳过时间"`
+	ActedBy     uint            `json:"acted_by" gorm:"default:0;comment:操作人用户ID"`
+	Note        string          `json:"note" gorm:"type:varchar(255);comment:备注"`
+	RemindedAt  *time.Time      `json:"reminded_at" gorm:"comment:提醒发送时间"`
+	Plan        *MedicationPlan `json:"plan,omitempty" gorm:"foreignKey:PlanID"`
+}
+
+// TableName 指定表名
+func (MedicationDose) TableName() string {
+	return "medication_doses"
+}
+
+// CreateMedicationPlanRequest 创建用药计划请求
+type CreateMedicationPlanRequest struct {
+	Drug            string     `json:"drug" binding:"required,max=100"`
+	Dose            float64    `json:"dose" binding:"required,gt=0"`
+	Unit            string     `json:"unit" binding:"required,max=20"`
+	ScheduleType    string     `json:"schedule_type" binding:"required,oneof=interval cron"`
+	IntervalHours   int        `json:"interval_hours" binding:"omitempty,min=1,max=720"`
+	Cron            string     `json:"cron" binding:"omitempty,max=100"`
+	StartAt         time.Time  `json:"start_at" binding:"required"`
+	EndAt           *time.Time `json:"end_at"`
+	RemindBeforeMin int        `json:"remind_before_min" binding:"omitempty,min=0,max=1440"`
+	Instructions    string     `json:"instructions" binding:"omitempty,max=500"`
+}
+
+// ListMedicationPlanRequest 用药计划列表请求
+type ListMedicationPlanRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=active paused ended"`
+}
+
+// UpdateMedicationStatusRequest 暂停/恢复/结束用药计划请求
+type UpdateMedicationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active paused ended"`
+}
+
+// RecordDoseRequest 记录给药结果请求
+type RecordDoseRequest struct {
+	Status  string     `json:"status" binding:"required,oneof=given skipped"`
+	ActedAt *time.Time `json:"acted_at"`
+	Note    string     `json:"note" binding:"omitempty,max=255"`
+}
+
+// ListDoseRequest 给药记录查询请求
+type ListDoseRequest struct {
+	From   *time.Time `form:"from"`
+	To     *time.Time `form:"to"`
+	Status string     `form:"status" binding:"omitempty,oneof=scheduled given skipped missed"`
+	PlanID uint       `form:"plan_id"`
+}
+
+// AdherenceRequest 依从性统计请求
+type AdherenceRequest struct {
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
+	Interval string     `form:"interval,default=week" binding:"omitempty,oneof=day week"`
+	PlanID   uint       `form:"plan_id"`
+}
+
+// DoseCounts 各状态给药次数
+type DoseCounts struct {
+	Scheduled int64   `json:"scheduled"`
+	Given     int64   `json:"given"`
+	Skipped   int64   `json:"skipped"`
+	Missed    int64   `json:"missed"`
+	Adherence float64 `json:"adherence"` // 已给药/(已给药+跳过+漏服),无已到期记录时为0
+}
+
+// AdherenceBucket 按周期统计的依从性
+type AdherenceBucket struct {
+	Bucket time.Time `json:"bucket"`
+	DoseCounts
+}
+
+// PlanAdherence 单个计划的依从性统计
+type PlanAdherence struct {
+	PlanID uint   `json:"plan_id"`
+	Drug   string `json:"drug"`
+	DoseCounts
+}
+
+// AdherenceReport 依从性统计结果
+type AdherenceReport struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Total   DoseCounts         `json:"total"`
+	Plans   []*PlanAdherence   `json:"plans"`
+	Periods []*AdherenceBucket `json:"periods"`
+}
+
+// DoseStatusCount 按分组统计的状态计数(仓储层使用)
+type DoseStatusCount struct {
+	Bucket time.Time
+	PlanID uint
+	Status string
+	Count  int64
+}
+
+// Add 累加状态计数
+func (c *DoseCounts) Add(status string, n int64) {
+	switch status {
+	case DoseStatusScheduled:
+		c.Scheduled += n
+	case DoseStatusGiven:
+		c.Given += n
+	case DoseStatusSkipped:
+		c.Skipped += n
+	case DoseStatusMissed:
+		c.Missed += n
+	}
+}
+
+// Finish 计算依从率
+func (c *DoseCounts) Finish() {
+	due := c.Given + c.Skipped + c.Missed
+	if due > 0 {
+		c.Adherence = float64(c.Given*10000/due) / 10000
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"time"
+)
+
+// 芯片查询审计动作
+const (
+	ChipActionLookup  = "lookup"
+	ChipActionMessage = "message"
+)
+
+// MicrochipLookup 芯片查询审计记录,未查到宠物时PetID为0
+type MicrochipLookup struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+	Microchip string    `json:"microchip" gorm:"type:varchar(15);index;not null;comment:查询的芯片号"`
+	PetID     uint      `json:"pet_id" gorm:"index;comment:命中的宠物ID"`
+	Action    string    `json:"action" gorm:"type:varchar(20);not null;comment:动作:lookup,message"`
+	Found     bool      `json:"found" gorm:"comment:是否命中"`
+	UserID    uint      `json:"user_id" gorm:"comment:查询人用户ID,匿名为0"`
+	ClientIP  string    `json:"client_ip" gorm:"type:varchar(64);comment:客户端IP"`
+	UserAgent string    `json:"user_agent" gorm:"type:varchar(255);comment:客户端UA"`
+}
+
+// TableName 指定表名
+func (MicrochipLookup) TableName() string {
+	return "microchip_lookups"
+}
+
+// FoundPetMessage 拾获人通过芯片号转达给主人的留言
+type FoundPetMessage struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time `json:"created_at"`
+	PetID         uint      `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	FinderName    string    `json:"finder_name" gorm:"type:varchar(50);comment:拾获人称呼"`
+	FinderContact string    `json:"finder_contact" gorm:"type:varchar(100);not null;comment:拾获人联系方式"`
+	Content       string    `json:"content" gorm:"type:varchar(1000);not null;comment:留言内容"`
+	Location      string    `json:"location" gorm:"type:varchar(255);comment:发现地点"`
+	IsRead        bool      `json:"is_read" gorm:"default:false;comment:是否已读"`
+}
+
+// TableName 指定表名
+func (FoundPetMessage) TableName() string {
+	return "found_pet_messages"
+}
+
+// LookupClient 查询方信息,用于审计
+type LookupClient struct {
+	UserID    uint
+	ClientIP  string
+	UserAgent string
+}
+
+// ChipLookupResult 芯片公开查询结果,只包含宠物外观和脱敏联系方式
+type ChipLookupResult struct {
+	Microchip string         `json:"microchip"`
+	Pet       *ChipPetInfo   `json:"pet"`
+	Contact   *MaskedContact `json:"contact,omitempty"`
+}
+
+// ChipPetInfo 用于辨认宠物的公开信息
+type ChipPetInfo struct {
+	Name    string `json:"name"`
+	Species string `json:"species"`
+	Breed   string `json:"breed"`
+	Color   string `json:"color"`
+	Avatar  string `json:"avatar"`
+}
+
+// MaskedContact 脱敏后的主人联系方式
+type MaskedContact struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// SendFoundMessageRequest 向主人转达留言请求
+type SendFoundMessageRequest struct {
+	FinderName    string `json:"finder_name" binding:"omitempty,max=50"`
+	FinderContact string `json:"finder_contact" binding:"required,max=100"`
+	Content       string `json:"content" binding:"required,max=1000"`
+	Location      string `json:"location" binding:"omitempty,max=255"`
+}
+
+// ListChipRecordRequest 芯片查询记录/留言列表请求
+type ListChipRecordRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=10" binding:"min=1,max=100"`
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"time"
+)
+
+// 通知投递渠道
+const (
+	NotifyChannelInbox = "inbox" // 站内信,写入消息中心并实时推送给在线连接
+	NotifyChannelPush  = "push"  // 外部推送(短信、App推送等)
+)
+
+// NotifyChannels 支持的投递渠道
+var NotifyChannels = map[string]bool{
+	NotifyChannelInbox: true,
+	NotifyChannelPush:  true,
+}
+
+// 通知类型
+const (
+	NotifyTypeMedication    = "medication_reminder" // 用药提醒
+	NotifyTypeSitterBooking = "sitter_booking"      // 寄养预约状态变化
+	NotifyTypeFollow        = "new_follower"        // 新增关注者
+	NotifyTypeEventReminder = "event_reminder"      // 活动开始前提醒
+	NotifyTypeEventUpdate   = "event_update"        // 活动变更、取消及候补递补
+	NotifyTypeAppointment   = "clinic_appointment"  // 门诊预约状态变化
+	NotifyTypeDonation      = "donation"            // 捐款到账
+	NotifyTypePrescription  = "prescription"        // 处方签发及配药申请处理
+	NotifyTypeSystem        = "system"              // 系统通知
+)
+
+// NotificationType 通知类型说明及默认投递渠道
+type NotificationType struct {
+	Type     string   `json:"type"`
+	Name     string   `json:"name"`
+	Channels []string `json:"channels"`
+}
+
+// NotificationTypes 支持的通知类型,用户未设置偏好时按默认渠道投递
+var NotificationTypes = []NotificationType{
+	{Type: NotifyTypeMedication, Name: "用药提醒", Channels: []string{NotifyChannelInbox, NotifyChannelPush}},
+	{Type: NotifyTypeSitterBooking, Name: "寄养预约", Channels: []string{NotifyChannelInbox, NotifyChannelPush}},
+	{Type: NotifyTypeFollow, Name: "新增关注", Channels: []string{NotifyChannelInbox}},
+	{Type: NotifyTypeEventReminder, Name: "活动提醒", Channels: []string{NotifyChannelInbox, NotifyChannelPush}},
+	{Type: NotifyTypeEventUpdate, Name: "活动变更", Channels: []string{NotifyChannelInbox, NotifyChannelPush}},
+	{Type: NotifyTypeAppointment, Name: "门诊预约", Channels: []string{NotifyChannelInbox, NotifyChannelPush}},
+	{Type: NotifyTypeDonation, Name: "捐款到账", Channels: []string{NotifyChannelInbox}},
+	{Type: NotifyTypePrescription, Name: "处方与配药", Channels: []string{NotifyChannelInbox, NotifyChannelPush}},
+	{Type: NotifyTypeSystem, Name: "系统通知", Channels: []string{NotifyChannelInbox}},
+}
+
+// FindNotificationType 查找通知类型,未注册的类型返回nil
+func FindNotificationType(notifyType string) *NotificationType {
+	for i := range NotificationTypes {
+		if NotificationTypes[i].Type == notifyType {
+			return &NotificationTypes[i]
+		}
+	}
+	return nil
+}
+
+// Notification 站内通知
+type Notification struct {
+	ID        uint                   `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time              `json:"created_at"`
+	UserID    uint                   `json:"user_id" gorm:"index:idx_user_read,priority:1;not null;comment:接收用户ID"`
+	Type      string                 `json:"type" gorm:"type:varchar(50);not null;comment:通知类型"`
+	Title     string                 `json:"title" gorm:"type:varchar(100);not null;comment:标题"`
+	Content   string                 `json:"content" gorm:"type:varchar(500);comment:内容"`
+	Payload   map[string]interface{} `json:"payload" gorm:"type:json;serializer:json;comment:业务数据,用于客户端跳转"`
+	IsRead    int                    `json:"is_read" gorm:"type:tinyint;default:0;index:idx_user_read,priority:2;comment:是否已读"`
+	ReadAt    *time.Time             `json:"read_at" gorm:"comment:阅读时间"`
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationPreference 用户对某类通知的投递渠道设置
+type NotificationPreference struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_user_type,priority:1;not null;comment:用户ID"`
+	Type      string    `json:"type" gorm:"type:varchar(50);uniqueIndex:idx_user_type,priority:2;not null;comment:通知类型"`
+	Channels  []string  `json:"channels" gorm:"type:json;serializer:json;comment:开启的渠道,为空表示不接收"`
+}
+
+// TableName 指定表名
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// ListNotificationRequest 通知列表请求
+type ListNotificationRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Unread   bool   `form:"unread"`
+	Type     string `form:"type"`
+}
+
+// MarkAllNotificationsReadRequest 全部已读请求,type为空时标记全部类型
+type MarkAllNotificationsReadRequest struct {
+	Type string `json:"type"`
+}
+
+// UpdateNotificationPreferencesRequest 更新通知偏好请求,未出现的类型保持不变
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationType `json:"preferences" binding:"required"`
+}
+
+// NotificationPage 通知列表及未读总数
+type NotificationPage struct {
+	List     []*Notification `json:"list"`
+	Total    int64           `json:"total"`
+	Unread   int64           `json:"unread"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"time"
+)
+
+// 订单类型
+const (
+	OrderTypeShop          = "shop"
+	OrderTypeSitterBooking = "sitter_booking"
+	OrderTypeDonation      = "donation"
+)
+
+// 订单状态
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusFulfilled = "fulfilled"
+	OrderStatusRefunded  = "refunded"
+	OrderStatusCancelled = "cancelled"
+)
+
+// 退款状态
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+	RefundStatusFailed    = "failed"
+)
+
+// CurrencyCNY 人民币,金额单位为分
+const CurrencyCNY = "CNY"
+
+// Order 订单,金额均为整数(分)
+// 状态流转:pending → paid → fulfilled/refunded,pending超时或主动取消 → cancelled
+type Order struct {
+	ID             uint         `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	OrderNo        string       `json:"order_no" gorm:"type:varchar(32);uniqueIndex;not null;comment:订单号"`
+	UserID         uint         `json:"user_id" gorm:"index:idx_user_status,priority:1;not null;comment:下单用户ID"`
+	Type           string       `json:"type" gorm:"type:varchar(30);index:idx_source,priority:1;not null;comment:订单类型"`
+	SourceID       uint         `json:"source_id" gorm:"index:idx_source,priority:2;not null;comment:来源ID(库存预占/寄养预约/捐款)"`
+	Subject        string       `json:"subject" gorm:"type:varchar(255);not null;comment:订单标题"`
+	Currency       string       `json:"currency" gorm:"type:varchar(3);default:CNY;comment:币种"`
+	TotalAmount    int64        `json:"total_amount" gorm:"not null;comment:商品总额(分)"`
+	DiscountAmount int64        `json:"discount_amount" gorm:"default:0;comment:优惠金额(分),优惠券与积分抵扣之和"`
+	CouponID       uint         `json:"coupon_id" gorm:"default:0;comment:使用的优惠券ID"`
+	CouponCode     string       `json:"coupon_code" gorm:"type:varchar(32);comment:使用的券码"`
+	CouponDiscount int64        `json:"coupon_discount" gorm:"default:0;comment:优惠券抵扣(分)"`
+	PointsUsed     int64        `json:"points_used" gorm:"default:0;comment:使用积分"`
+	PointsDiscount int64        `json:"points_discount" gorm:"default:0;comment:积分抵扣(分)"`
+	PayAmount      int64        `json:"pay_amount" gorm:"not null;comment:应付金额(分)"`
+	RefundedAmount int64        `json:"refunded_amount" gorm:"default:0;comment:已退款金额(分),含处理中的退款"`
+	Status         string       `json:"status" gorm:"type:varchar(20);index:idx_user_status,priority:2;index:idx_status_expires,priority:1;default:pending;comment:状态"`
+	Gateway        string       `json:"gateway" gorm:"type:varchar(20);comment:支付网关"`
+	TradeNo        string       `json:"trade_no" gorm:"type:varchar(64);comment:网关交易号"`
+	ExpiresAt      time.Time    `json:"expires_at" gorm:"index:idx_status_expires,priority:2;not null;comment:支付截止时间"`
+	PaidAt         *time.Time   `json:"paid_at" gorm:"comment:支付时间"`
+	FulfilledAt    *time.Time   `json:"fulfilled_at" gorm:"comment:完成时间"`
+	CancelledAt    *time.Time   `json:"cancelled_at" gorm:"comment:取消时间"`
+	CancelReason   string       `json:"cancel_reason" gorm:"type:varchar(255);comment:取消原因"`
+	Items          []*OrderItem `json:"items,omitempty" gorm:"foreignKey:OrderID"`
+	Refunds        []*Refund    `json:"refunds,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+// TableName 指定表名
+func (Order) TableName() string {
+	return "orders"
+}
+
+// Refundable 可退款金额
+func (o *Order) Refundable() int64 {
+	if o.Status != OrderStatusPaid && o.Status != OrderStatusFulfilled {
+		return 0
+	}
+	return o.PayAmount - o.RefundedAmount
+}
+
+// OrderItem 订单明细,保存下单时的商品快照
+type OrderItem struct {
+	ID        uint   `json:"id" gorm:"primarykey"`
+	OrderID   uint   `json:"order_id" gorm:"index;not null;comment:订单ID"`
+	SKUID     uint   `json:"sku_id" gorm:"column:sku_id;default:0;comment:商品规格ID"`
+	ProductID uint   `json:"product_id" gorm:"default:0;comment:商品ID"`
+	Name      string `json:"name" gorm:"type:varchar(255);not null;comment:名称"`
+	SKUName   string `json:"sku_name" gorm:"column:sku_name;type:varchar(100);comment:规格名称"`
+	Price     int64  `json:"price" gorm:"not null;comment:单价(分)"`
+	Quantity  int    `json:"quantity" gorm:"not null;comment:数量"`
+	Amount    int64  `json:"amount" gorm:"not null;comment:小计(分)"`
+}
+
+// TableName 指定表名
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+// Refund 退款记录
+type Refund struct {
+	ID              uint       `json:"id" gorm:"primarykey"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	RefundNo        string     `json:"refund_no" gorm:"type:varchar(32);uniqueIndex;not null;comment:退款单号"`
+	OrderID         uint       `json:"order_id" gorm:"index;not null;comment:订单ID"`
+	Amount          int64      `json:"amount" gorm:"not null;comment:退款金额(分)"`
+	Reason          string     `json:"reason" gorm:"type:varchar(255);comment:退款原因"`
+	Status          string     `json:"status" gorm:"type:varchar(20);default:pending;comment:状态:pending,succeeded,failed"`
+	GatewayRefundNo string     `json:"gateway_refund_no" gorm:"type:varchar(64);comment:网关退款单号"`
+	FailReason      string     `json:"fail_reason" gorm:"type:varchar(255);comment:失败原因"`
+	OperatorID      uint       `json:"operator_id" gorm:"default:0;comment:操作人用户ID,0为系统"`
+	SucceededAt     *time.Time `json:"succeeded_at" gorm:"comment:退款成功时间"`
+}
+
+// TableName 指定表名
+func (Refund) TableName() string {
+	return "refunds"
+}
+
+// PaymentEvent 已处理的支付回调,按网关和事件ID去重
+type PaymentEvent struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	Gateway   string    `json:"gateway" gorm:"type:varchar(20);uniqueIndex:idx_gateway_event,priority:1;not null;comment:支付网关"`
+	EventID   string    `json:"event_id" gorm:"type:varchar(64);uniqueIndex:idx_gateway_event,priority:2;not null;comment:网关事件ID"`
+	Type      string    `json:"type" gorm:"type:varchar(30);not null;comment:事件类型"`
+	OrderNo   string    `json:"order_no" gorm:"type:varchar(32);index;comment:订单号"`
+	Payload   string    `json:"payload" gorm:"type:text;comment:回调原文"`
+}
+
+// TableName 指定表名
+func (PaymentEvent) TableName() string {
+	return "payment_events"
+}
+
+// CreateOrderRequest 创建订单请求
+type CreateOrderRequest struct {
+	Type       string `json:"type" binding:"required,oneof=shop sitter_booking donation"`
+	SourceID   uint   `json:"source_id" binding:"required"` // shop为结算返回的预占ID,sitter_booking为已接受的寄养预约ID,donation为待支付的捐款ID
+	CouponCode string `json:"coupon_code" binding:"omitempty,max=32"`
+	Points     int64  `json:"points" binding:"min=0"` // 使用的积分数
+}
+
+// ListOrderRequest 订单列表请求
+type ListOrderRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Status   string `form:"status"`
+	Type     string `form:"type"`
+	UserID   uint   `form:"user_id"` // 仅管理端生效
+}
+
+// CancelOrderRequest 取消订单请求
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// RefundOrderRequest 退款请求,金额为0时退还全部剩余金额
+type RefundOrderRequest struct {
+	Amount int64  `json:"amount" binding:"min=0"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+)
+
+// 宠物体型
+const (
+	PetSizeSmall  = "small"
+	PetSizeMedium = "medium"
+	PetSizeLarge  = "large"
+)
+
+// Pet 宠物模型
+type Pet struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	OwnerID     uint       `json:"owner_id" gorm:"index;comment:主人用户ID,0表示由救助机构持有"`
+	ShelterID   uint       `json:"shelter_id" gorm:"index;comment:所属救助机构ID"`
+	Name        string     `json:"name" gorm:"type:varchar(50);not null;comment:名字"`
+	Species     string     `json:"species" gorm:"type:varchar(20);index;not null;comment:物种:dog,cat等"`
+	Breed       string     `json:"breed" gorm:"type:varchar(50);comment:品种"`
+	Gender      string     `json:"gender" gorm:"type:varchar(10);comment:性别:male,female,unknown"`
+	BirthDate   *time.Time `json:"birth_date" gorm:"type:date;comment:出生日期"`
+	Color       string     `json:"color" gorm:"type:varchar(30);comment:毛色"`
+	Size        string     `json:"size" gorm:"type:varchar(10);comment:体型:small,medium,large"`
+	Avatar      string     `json:"avatar" gorm:"type:varchar(255);comment:照片"`
+	Description string     `json:"description" gorm:"type:text;comment:描述"`
+	Microchip   *string    `json:"microchip" gorm:"type:varchar(15);uniqueIndex;comment:芯片号(ISO 11784/11785)"`
+	IsDeleted   int        `json:"is_deleted" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	Role        string     `json:"role,omitempty" gorm:"-"` // 当前用户对该宠物的角色
+}
+
+// TableName 指定表名
+func (Pet) TableName() string {
+	return "pets"
+}
+
+// CreatePetRequest 创建宠物请求
+type CreatePetRequest struct {
+	Name        string     `json:"name" binding:"required,max=50"`
+	Species     string     `json:"species" binding:"required,max=20"`
+	Breed       string     `json:"breed" binding:"omitempty,max=50"`
+	Gender      string     `json:"gender" binding:"omitempty,oneof=male female unknown"`
+	BirthDate   *time.Time `json:"birth_date"`
+	Color       string     `json:"color" binding:"omitempty,max=30"`
+	Size        string     `json:"size" binding:"omitempty,oneof=small medium large"`
+	Avatar      string     `json:"avatar" binding:"omitempty,max=255"`
+	Description string     `json:"description"`
+	Microchip   string     `json:"microchip" binding:"omitempty,max=30"`
+}
+
+// UpdatePetRequest 更新宠物请求
+type UpdatePetRequest struct {
+	Name        string     `json:"name" binding:"omitempty,max=50"`
+	Breed       string     `json:"breed" binding:"omitempty,max=50"`
+	Gender      string     `json:"gender" binding:"omitempty,oneof=male female unknown"`
+	BirthDate   *time.Time `json:"birth_date"`
+	Color       string     `json:"color" binding:"omitempty,max=30"`
+	Size        string     `json:"size" binding:"omitempty,oneof=small medium large"`
+	Avatar      string     `json:"avatar" binding:"omitempty,max=255"`
+	Description string     `json:"description"`
+	Microchip   string     `json:"microchip" binding:"omitempty,max=30"`
+}
+
+// ListPetRequest 宠物列表请求
+type ListPetRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Species  string `form:"species"`
+}
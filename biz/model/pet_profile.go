@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+)
+
+// 二维码格式
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// PetProfile 宠物公开主页设置,默认不公开,通过不可猜测的slug访问
+type PetProfile struct {
+	ID               uint      `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	PetID            uint      `json:"pet_id" gorm:"uniqueIndex;not null;comment:宠物ID"`
+	Slug             string    `json:"slug" gorm:"type:varchar(32);uniqueIndex;not null;comment:公开访问标识"`
+	Enabled          bool      `json:"enabled" gorm:"default:false;comment:是否公开"`
+	ShowName         bool      `json:"show_name" gorm:"default:true;comment:展示名字"`
+	ShowPhoto        bool      `json:"show_photo" gorm:"default:true;comment:展示照片"`
+	ShowMedicalAlert bool      `json:"show_medical_alert" gorm:"default:false;comment:展示医疗提示"`
+	ShowContact      bool      `json:"show_contact" gorm:"default:false;comment:展示主人联系方式"`
+	MedicalAlert     string    `json:"medical_alert" gorm:"type:varchar(500);comment:医疗提示,如过敏、慢性病用药"`
+	ContactNote      string    `json:"contact_note" gorm:"type:varchar(255);comment:联系说明"`
+	URL              string    `json:"url" gorm:"-"`
+}
+
+// TableName 指定表名
+func (PetProfile) TableName() string {
+	return "pet_profiles"
+}
+
+// UpdatePetProfileRequest 更新公开主页设置请求,未传字段保持不变
+type UpdatePetProfileRequest struct {
+	Enabled          *bool   `json:"enabled"`
+	ShowName         *bool   `json:"show_name"`
+	ShowPhoto        *bool   `json:"show_photo"`
+	ShowMedicalAlert *bool   `json:"show_medical_alert"`
+	ShowContact      *bool   `json:"show_contact"`
+	MedicalAlert     *string `json:"medical_alert" binding:"omitempty,max=500"`
+	ContactNote      *string `json:"contact_note" binding:"omitempty,max=255"`
+}
+
+// QRCodeRequest 二维码生成请求
+type QRCodeRequest struct {
+	Format string `form:"format,default=png" binding:"omitempty,oneof=png svg"`
+	Size   int    `form:"size,default=256" binding:"omitempty,min=64,max=2048"`
+}
+
+// PublicPetProfile 公开主页展示内容,仅包含主人允许公开的字段
+type PublicPetProfile struct {
+	Slug         string         `json:"slug"`
+	Name         string         `json:"name,omitempty"`
+	Species      string         `json:"species"`
+	Breed        string         `json:"breed,omitempty"`
+	Color        string         `json:"color,omitempty"`
+	Photo        string         `json:"photo,omitempty"`
+	MedicalAlert string         `json:"medical_alert,omitempty"`
+	Contact      *PublicContact `json:"contact,omitempty"`
+	HasMicrochip bool           `json:"has_microchip"`
+}
+
+// PublicContact 主人选择公开的联系方式
+type PublicContact struct {
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
+	Email string `json:"email,omitempty"`
+	Note  string `json:"note,omitempty"`
+}
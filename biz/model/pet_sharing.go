@@ -0,0 +1,119 @@
+package model
+
+import (
+	"time"
+)
+
+// 宠物成员角色,权限依次递增
+const (
+	PetRoleViewer = "viewer"
+	PetRoleEditor = "editor"
+	PetRoleOwner  = "owner"
+)
+
+// petRoleRanks 角色权限等级
+var petRoleRanks = map[string]int{
+	PetRoleViewer: 1,
+	PetRoleEditor: 2,
+	PetRoleOwner:  3,
+}
+
+// PetRoleAtLeast 判断角色是否不低于要求的角色
+func PetRoleAtLeast(role, required string) bool {
+	return petRoleRanks[role] >= petRoleRanks[required] && petRoleRanks[role] > 0
+}
+
+// ValidPetRole 判断是否为已定义的角色
+func ValidPetRole(role string) bool {
+	_, ok := petRoleRanks[role]
+	return ok
+}
+
+// 邀请/转让状态
+const (
+	ShareStatusPending   = "pending"
+	ShareStatusAccepted  = "accepted"
+	ShareStatusDeclined  = "declined"
+	ShareStatusCancelled = "cancelled"
+)
+
+// PetMember 宠物共享成员,主人(pets.owner_id)不在此表中
+type PetMember struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	PetID     uint      `json:"pet_id" gorm:"uniqueIndex:idx_pet_user,priority:1;not null;comment:宠物ID"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_pet_user,priority:2;index;not null;comment:成员用户ID"`
+	Role      string    `json:"role" gorm:"type:varchar(10);not null;comment:角色:viewer,editor,owner"`
+	InvitedBy uint      `json:"invited_by" gorm:"comment:邀请人用户ID"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName 指定表名
+func (PetMember) TableName() string {
+	return "pet_members"
+}
+
+// PetInvitation 宠物共享邀请
+type PetInvitation struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	PetID       uint       `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	InviterID   uint       `json:"inviter_id" gorm:"not null;comment:邀请人用户ID"`
+	InviteeID   uint       `json:"invitee_id" gorm:"index;not null;comment:被邀请人用户ID"`
+	Role        string     `json:"role" gorm:"type:varchar(10);not null;comment:授予角色"`
+	Status      string     `json:"status" gorm:"type:varchar(20);index;default:pending;comment:状态:pending,accepted,declined,cancelled"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"comment:过期时间"`
+	RespondedAt *time.Time `json:"responded_at" gorm:"comment:处理时间"`
+	Pet         *Pet       `json:"pet,omitempty" gorm:"foreignKey:PetID"`
+}
+
+// TableName 指定表名
+func (PetInvitation) TableName() string {
+	return "pet_invitations"
+}
+
+// PetTransfer 宠物主人转让,需转出方和接收方双方确认
+type PetTransfer struct {
+	ID            uint       `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	PetID         uint       `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	FromUserID    uint       `json:"from_user_id" gorm:"index;not null;comment:转出方(当前主人)"`
+	ToUserID      uint       `json:"to_user_id" gorm:"index;not null;comment:接收方"`
+	FromConfirmed bool       `json:"from_confirmed" gorm:"default:false;comment:转出方已确认"`
+	ToConfirmed   bool       `json:"to_confirmed" gorm:"default:false;comment:接收方已确认"`
+	Status        string     `json:"status" gorm:"type:varchar(20);index;default:pending;comment:状态:pending,accepted,declined,cancelled"`
+	CompletedAt   *time.Time `json:"completed_at" gorm:"comment:完成时间"`
+	Pet           *Pet       `json:"pet,omitempty" gorm:"foreignKey:PetID"`
+}
+
+// TableName 指定表名
+func (PetTransfer) TableName() string {
+	return "pet_transfers"
+}
+
+// CreateInvitationRequest 发送共享邀请请求,用户名与邮箱二选一
+type CreateInvitationRequest struct {
+	Username string `json:"username" binding:"omitempty,max=50"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Role     string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+// UpdateMemberRoleRequest 修改成员角色请求
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+// CreateTransferRequest 发起主人转让请求
+// 当前主人指定接收方(用户名或邮箱);角色为owner的共同主人不传接收方时表示申请将自己设为主人
+type CreateTransferRequest struct {
+	Username string `json:"username" binding:"omitempty,max=50"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+// ListShareRequest 我的邀请/转让列表请求
+type ListShareRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending accepted declined cancelled"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"time"
+)
+
+// 积分流水类型
+const (
+	PointsEarnOrder       = "earn_order"       // 订单支付获得
+	PointsEarnAppointment = "earn_appointment" // 完成门诊预约获得
+	PointsRedeem          = "redeem"           // 下单抵扣
+	PointsReturn          = "return"           // 订单取消/退款退回抵扣的积分
+	PointsRevoke          = "revoke"           // 订单退款扣回获得的积分
+)
+
+// 积分来源类型
+const (
+	PointsSourceOrder       = "order"
+	PointsSourceAppointment = "appointment"
+)
+
+// UserPoints 用户积分账户
+type UserPoints struct {
+	UserID      uint      `json:"user_id" gorm:"primarykey;autoIncrement:false;comment:用户ID"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Balance     int64     `json:"balance" gorm:"default:0;comment:可用积分"`
+	TotalEarned int64     `json:"total_earned" gorm:"default:0;comment:累计获得积分"`
+}
+
+// TableName 指定表名
+func (UserPoints) TableName() string {
+	return "user_points"
+}
+
+// PointsTransaction 积分流水,同一业务来源的同类流水只记一次
+type PointsTransaction struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UserID       uint      `json:"user_id" gorm:"index:idx_user_created,priority:1;not null;comment:用户ID"`
+	Type         string    `json:"type" gorm:"type:varchar(30);uniqueIndex:idx_type_source,priority:1;not null;comment:流水类型"`
+	SourceType   string    `json:"source_type" gorm:"type:varchar(30);uniqueIndex:idx_type_source,priority:2;not null;comment:来源类型:order,appointment"`
+	SourceID     uint      `json:"source_id" gorm:"uniqueIndex:idx_type_source,priority:3;not null;comment:来源ID"`
+	Delta        int64     `json:"delta" gorm:"not null;comment:变动积分,正数增加负数减少"`
+	BalanceAfter int64     `json:"balance_after" gorm:"not null;comment:变动后余额"`
+	Remark       string    `json:"remark" gorm:"type:varchar(255);comment:说明"`
+}
+
+// TableName 指定表名
+func (PointsTransaction) TableName() string {
+	return "points_transactions"
+}
+
+// PointsSummary 积分账户与流水
+type PointsSummary struct {
+	Balance      int64                `json:"balance"`
+	TotalEarned  int64                `json:"total_earned"`
+	Transactions []*PointsTransaction `json:"transactions"`
+	Total        int64                `json:"total"`
+}
+
+// ListPointsRequest 积分流水请求
+type ListPointsRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Type     string `form:"type"`
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"time"
+)
+
+// Post 动态,点赞数和评论数由Redis计数定期回写
+type Post struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	UserID       uint      `json:"user_id" gorm:"index:idx_user_deleted,priority:1;not null;comment:发布人用户ID"`
+	Content      string    `json:"content" gorm:"type:text;comment:正文"`
+	PhotoFileIDs []uint    `json:"photo_file_ids" gorm:"type:json;serializer:json;comment:照片文件ID"`
+	LikeCount    int64     `json:"like_count" gorm:"default:0;comment:点赞数"`
+	CommentCount int64     `json:"comment_count" gorm:"default:0;comment:评论数"`
+	IsDeleted    int       `json:"-" gorm:"type:tinyint;index:idx_user_deleted,priority:2;default:0;comment:是否删除:0否,1是"`
+	Author       *User     `json:"author,omitempty" gorm:"foreignKey:UserID"`
+	Pets         []*Pet    `json:"pets,omitempty" gorm:"many2many:post_pets;joinForeignKey:PostID;joinReferences:PetID"`
+	Photos       []string  `json:"photos,omitempty" gorm:"-"`
+	Liked        bool      `json:"liked" gorm:"-"` // 当前用户是否已点赞
+}
+
+// TableName 指定表名
+func (Post) TableName() string {
+	return "posts"
+}
+
+// PostComment 动态评论,ParentID为0表示一级评论,回复只有一层
+type PostComment struct {
+	ID            uint           `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time      `json:"created_at"`
+	PostID        uint           `json:"post_id" gorm:"index:idx_post_parent,priority:1;not null;comment:动态ID"`
+	ParentID      uint           `json:"parent_id" gorm:"index:idx_post_parent,priority:2;default:0;comment:所属一级评论ID,0为一级评论"`
+	UserID        uint           `json:"user_id" gorm:"index;not null;comment:评论人用户ID"`
+	ReplyToUserID uint           `json:"reply_to_user_id" gorm:"default:0;comment:被回复的用户ID"`
+	Content       string         `json:"content" gorm:"type:varchar(1000);not null;comment:内容"`
+	ReplyCount    int64          `json:"reply_count" gorm:"default:0;comment:回复数"`
+	IsDeleted     int            `json:"-" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	Author        *User          `json:"author,omitempty" gorm:"foreignKey:UserID"`
+	Replies       []*PostComment `json:"replies,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (PostComment) TableName() string {
+	return "post_comments"
+}
+
+// PostLike 点赞记录,同一用户对同一动态只有一条
+type PostLike struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	PostID    uint      `json:"post_id" gorm:"uniqueIndex:idx_post_user,priority:1;not null;comment:动态ID"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_post_user,priority:2;index;not null;comment:点赞用户ID"`
+}
+
+// TableName 指定表名
+func (PostLike) TableName() string {
+	return "post_likes"
+}
+
+// PostStats 动态计数
+type PostStats struct {
+	LikeCount    int64 `json:"like_count"`
+	CommentCount int64 `json:"comment_count"`
+}
+
+// LikeResult 点赞/取消点赞结果
+type LikeResult struct {
+	Liked     bool  `json:"liked"`
+	LikeCount int64 `json:"like_count"`
+}
+
+// CreatePostRequest 发布动态请求
+type CreatePostRequest struct {
+	Content      string `json:"content" binding:"max=5000"`
+	PhotoFileIDs []uint `json:"photo_file_ids"`
+	PetIDs       []uint `json:"pet_ids"`
+}
+
+// ListPostRequest 动态列表请求,按ID倒序游标分页
+type ListPostRequest struct {
+	Cursor uint `form:"cursor"` // 上一页最后一条动态ID,为空从最新开始
+	Limit  int  `form:"limit,default=20" binding:"min=1,max=50"`
+	UserID uint `form:"user_id"`
+	PetID  uint `form:"pet_id"`
+}
+
+// PostPage 动态分页结果
+type PostPage struct {
+	List       []*Post `json:"list"`
+	NextCursor uint    `json:"next_cursor"` // 为0表示没有更多
+}
+
+// CreateCommentRequest 发表评论请求,parent_id为评论ID时表示回复
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,max=1000"`
+	ParentID uint   `json:"parent_id"`
+}
+
+// ListCommentRequest 评论列表请求
+type ListCommentRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=20" binding:"min=1,max=100"`
+}
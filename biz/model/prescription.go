@@ -0,0 +1,176 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// 处方状态,已签发处方过了有效期视为失效
+const (
+	PrescriptionDraft   = "draft"   // 草稿,开方兽医可修改
+	PrescriptionSigned  = "signed"  // 已签发,内容不可修改
+	PrescriptionRevoked = "revoked" // 已作废
+)
+
+// 配药渠道
+const (
+	FillChannelClinic = "clinic" // 开方诊所药房
+	FillChannelShop   = "shop"   // 商城药房
+)
+
+// 配药申请状态
+const (
+	RefillPending   = "pending"   // 待处理
+	RefillFulfilled = "fulfilled" // 已配药
+	RefillDeclined  = "declined"  // 已拒绝
+	RefillCancelled = "cancelled" // 已取消
+)
+
+// 处方数量与续配上限
+const (
+	MaxPrescriptionRefills = 12
+	MaxPrescriptionDays    = 366
+)
+
+// Prescription 兽医处方,关联就诊记录;签发后内容不可修改,配药时扣减剩余续配次数
+type Prescription struct {
+	ID               uint       `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	PrescriptionNo   string     `json:"prescription_no" gorm:"type:varchar(32);uniqueIndex;not null;comment:处方编号"`
+	ClinicID         uint       `json:"clinic_id" gorm:"index:idx_clinic_status,priority:1;not null;comment:开方诊所ID"`
+	VetID            uint       `json:"vet_id" gorm:"index;not null;comment:开方兽医用户ID"`
+	VetName          string     `json:"vet_name" gorm:"type:varchar(50);comment:开方兽医名称快照"`
+	MedicalRecordID  uint       `json:"medical_record_id" gorm:"index;not null;comment:就诊记录ID"`
+	PetID            uint       `json:"pet_id" gorm:"index;not null;comment:宠物ID"`
+	Drug             string     `json:"drug" gorm:"type:varchar(100);not null;comment:药品名称"`
+	Strength         string     `json:"strength" gorm:"type:varchar(50);not null;comment:规格,如50mg"`
+	Quantity         float64    `json:"quantity" gorm:"type:decimal(10,2);not null;comment:每次配药数量"`
+	Unit             string     `json:"unit" gorm:"type:varchar(20);not null;comment:数量单位,如片、ml"`
+	Refills          int        `json:"refills" gorm:"default:0;comment:首次配药之外可续配次数"`
+	RefillsRemaining int        `json:"refills_remaining" gorm:"default:0;comment:剩余续配次数"`
+	FillCount        int        `json:"fill_count" gorm:"default:0;comment:已配药次数"`
+	Instructions     string     `json:"instructions" gorm:"type:varchar(500);comment:用法用量"`
+	ExpiresAt        time.Time  `json:"expires_at" gorm:"type:date;not null;comment:有效期至(含当天)"`
+	Status           string     `json:"status" gorm:"type:varchar(20);index:idx_clinic_status,priority:2;not null;comment:状态:draft,signed,revoked"`
+	SignedAt         *time.Time `json:"signed_at" gorm:"comment:签发时间"`
+	Digest           string     `json:"digest" gorm:"type:char(64);comment:签发内容摘要SHA-256"`
+	RevokedAt        *time.Time `json:"revoked_at" gorm:"comment:作废时间"`
+	RevokeReason     string     `json:"revoke_reason" gorm:"type:varchar(255);comment:作废原因"`
+	Clinic           *Clinic    `json:"clinic,omitempty" gorm:"foreignKey:ClinicID"`
+	Expired          bool       `json:"expired" gorm:"-"`  // 已过有效期
+	Fillable         bool       `json:"fillable" gorm:"-"` // 已签发、未过期且仍有可配次数
+}
+
+// TableName 指定表名
+func (Prescription) TableName() string {
+	return "prescriptions"
+}
+
+// HasFillsLeft 首次配药尚未进行或仍有剩余续配次数
+func (p *Prescription) HasFillsLeft() bool {
+	return p.FillCount == 0 || p.RefillsRemaining > 0
+}
+
+// ContentDigest 计算处方签发内容的摘要,用于配药前校验内容未被改动
+func (p *Prescription) ContentDigest() string {
+	content, _ := json.Marshal(struct {
+		No              string  `json:"no"`
+		ClinicID        uint    `json:"clinic_id"`
+		VetID           uint    `json:"vet_id"`
+		MedicalRecordID uint    `json:"medical_record_id"`
+		PetID           uint    `json:"pet_id"`
+		Drug            string  `json:"drug"`
+		Strength        string  `json:"strength"`
+		Quantity        float64 `json:"quantity"`
+		Unit            string  `json:"unit"`
+		Refills         int     `json:"refills"`
+		Instructions    string  `json:"instructions"`
+		ExpiresAt       string  `json:"expires_at"`
+	}{
+		No:              p.PrescriptionNo,
+		ClinicID:        p.ClinicID,
+		VetID:           p.VetID,
+		MedicalRecordID: p.MedicalRecordID,
+		PetID:           p.PetID,
+		Drug:            p.Drug,
+		Strength:        p.Strength,
+		Quantity:        p.Quantity,
+		Unit:            p.Unit,
+		Refills:         p.Refills,
+		Instructions:    p.Instructions,
+		ExpiresAt:       p.ExpiresAt.Format(DateLayout),
+	})
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
+
+// PrescriptionRefill 主人提交的配药申请,首次配药和续配都通过申请处理
+type PrescriptionRefill struct {
+	ID             uint          `json:"id" gorm:"primarykey"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	PrescriptionID uint          `json:"prescription_id" gorm:"index;not null;comment:处方ID"`
+	ClinicID       uint          `json:"clinic_id" gorm:"index:idx_channel_clinic_status,priority:2;not null;comment:开方诊所ID"`
+	PetID          uint          `json:"pet_id" gorm:"not null;comment:宠物ID"`
+	RequestedBy    uint          `json:"requested_by" gorm:"index;not null;comment:申请人用户ID"`
+	Channel        string        `json:"channel" gorm:"type:varchar(10);index:idx_channel_clinic_status,priority:1;not null;comment:配药渠道:clinic,shop"`
+	Note           string        `json:"note" gorm:"type:varchar(255);comment:申请备注"`
+	Status         string        `json:"status" gorm:"type:varchar(20);index:idx_channel_clinic_status,priority:3;not null;comment:状态:pending,fulfilled,declined,cancelled"`
+	FillNo         int           `json:"fill_no" gorm:"default:0;comment:第几次配药,1为首次"`
+	HandledBy      uint          `json:"handled_by" gorm:"default:0;comment:处理人用户ID"`
+	HandledAt      *time.Time    `json:"handled_at" gorm:"comment:处理时间"`
+	HandleNote     string        `json:"handle_note" gorm:"type:varchar(255);comment:处理说明或拒绝原因"`
+	Prescription   *Prescription `json:"prescription,omitempty" gorm:"foreignKey:PrescriptionID"`
+}
+
+// TableName 指定表名
+func (PrescriptionRefill) TableName() string {
+	return "prescription_refills"
+}
+
+// SavePrescriptionRequest 创建/修改处方草稿请求,有效期格式YYYY-MM-DD
+type SavePrescriptionRequest struct {
+	ClinicID     uint    `json:"clinic_id" binding:"required"` // 仅创建时使用
+	Drug         string  `json:"drug" binding:"required,max=100"`
+	Strength     string  `json:"strength" binding:"required,max=50"`
+	Quantity     float64 `json:"quantity" binding:"required,gt=0"`
+	Unit         string  `json:"unit" binding:"required,max=20"`
+	Refills      int     `json:"refills" binding:"min=0,max=12"`
+	Instructions string  `json:"instructions" binding:"max=500"`
+	ExpiresAt    string  `json:"expires_at" binding:"required"`
+}
+
+// RevokePrescriptionRequest 作废处方请求
+type RevokePrescriptionRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ListPrescriptionRequest 处方列表请求
+type ListPrescriptionRequest struct {
+	Page            int    `form:"page,default=1" binding:"min=1"`
+	PageSize        int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Status          string `form:"status"`
+	MedicalRecordID uint   `form:"medical_record_id"`
+}
+
+// CreateRefillRequest 提交配药申请请求
+type CreateRefillRequest struct {
+	Channel string `json:"channel" binding:"required,oneof=clinic shop"`
+	Note    string `json:"note" binding:"max=255"`
+}
+
+// ListRefillRequest 配药申请列表请求
+type ListRefillRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=20" binding:"min=1,max=100"`
+	Status   string `form:"status"`
+}
+
+// HandleRefillRequest 药房处理配药申请请求
+type HandleRefillRequest struct {
+	Status string `json:"status" binding:"required,oneof=fulfilled declined"`
+	Note   string `json:"note" binding:"max=255"`
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"time"
+)
+
+// 评价来源类型
+const (
+	ReviewSourceSitterBooking  = "sitter_booking"
+	ReviewSourceAppointment    = "appointment"
+	ReviewSourceAppointmentVet = "appointment_vet" // 门诊预约的接诊兽医,与诊所评价分开
+)
+
+// 被评价对象类型
+const (
+	ReviewTargetSitter = "sitter"
+	ReviewTargetClinic = "clinic"
+	ReviewTargetVet    = "vet"
+)
+
+// 评价状态
+const (
+	ReviewStatusVisible = "visible"
+	ReviewStatusHidden  = "hidden"
+)
+
+// Review 评价
+type Review struct {
+	ID           uint       `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	SourceType   string     `json:"source_type" gorm:"type:varchar(30);uniqueIndex:idx_source,priority:1;not null;comment:来源类型"`
+	SourceID     uint       `json:"source_id" gorm:"uniqueIndex:idx_source,priority:2;not null;comment:来源ID(预约/寄养)"`
+	TargetType   string     `json:"target_type" gorm:"type:varchar(20);index:idx_target,priority:1;not null;comment:被评价对象类型"`
+	TargetID     uint       `json:"target_id" gorm:"index:idx_target,priority:2;not null;comment:被评价对象ID"`
+	ReviewerID   uint       `json:"reviewer_id" gorm:"index;not null;comment:评价人用户ID"`
+	Rating       int        `json:"rating" gorm:"type:tinyint;not null;comment:评分1-5"`
+	Content      string     `json:"content" gorm:"type:text;comment:评价内容"`
+	PhotoFileIDs []uint     `json:"photo_file_ids" gorm:"type:json;serializer:json;comment:照片文件ID"`
+	Reply        string     `json:"reply" gorm:"type:varchar(1000);comment:服务方回复"`
+	RepliedBy    uint       `json:"replied_by" gorm:"default:0;comment:回复人用户ID"`
+	RepliedAt    *time.Time `json:"replied_at" gorm:"comment:回复时间"`
+	FlagCount    int        `json:"-" gorm:"default:0;comment:被举报次数"`
+	Status       string     `json:"status" gorm:"type:varchar(20);index:idx_target,priority:3;default:visible;comment:状态:visible,hidden"`
+	Reviewer     *User      `json:"reviewer,omitempty" gorm:"foreignKey:ReviewerID"`
+	Photos       []string   `json:"photos,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// ReviewFlag 评价举报记录
+type ReviewFlag struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	ReviewID  uint      `json:"review_id" gorm:"uniqueIndex:idx_review_user,priority:1;not null;comment:评价ID"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_review_user,priority:2;not null;comment:举报人用户ID"`
+	Reason    string    `json:"reason" gorm:"type:varchar(255);not null;comment:举报原因"`
+}
+
+// TableName 指定表名
+func (ReviewFlag) TableName() string {
+	return "review_flags"
+}
+
+// RatingSummary 被评价对象的评分汇总,随评价增删增量维护
+type RatingSummary struct {
+	ID         uint      `json:"-" gorm:"primarykey"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	TargetType string    `json:"target_type" gorm:"type:varchar(20);uniqueIndex:idx_target,priority:1;not null;comment:被评价对象类型"`
+	TargetID   uint      `json:"target_id" gorm:"uniqueIndex:idx_target,priority:2;not null;comment:被评价对象ID"`
+	Count      int64     `json:"count" gorm:"default:0;comment:评价数"`
+	Sum        int64     `json:"-" gorm:"default:0;comment:评分总和"`
+	Star1      int64     `json:"star1" gorm:"column:star1;default:0;comment:1星数"`
+	Star2      int64     `json:"star2" gorm:"column:star2;default:0;comment:2星数"`
+	Star3      int64     `json:"star3" gorm:"column:star3;default:0;comment:3星数"`
+	Star4      int64     `json:"star4" gorm:"column:star4;default:0;comment:4星数"`
+	Star5      int64     `json:"star5" gorm:"column:star5;default:0;comment:5星数"`
+	Average    float64   `json:"average" gorm:"-"`
+}
+
+// TableName 指定表名
+func (RatingSummary) TableName() string {
+	return "rating_summaries"
+}
+
+// FillAverage 计算平均分,保留两位小数
+func (s *RatingSummary) FillAverage() {
+	if s.Count > 0 {
+		s.Average = float64(s.Sum*100/s.Count) / 100
+	}
+}
+
+// CreateReviewRequest 发表评价请求
+type CreateReviewRequest struct {
+	SourceType   string `json:"source_type" binding:"required"`
+	SourceID     uint   `json:"source_id" binding:"required"`
+	Rating       int    `json:"rating" binding:"required,min=1,max=5"`
+	Content      string `json:"content" binding:"omitempty,max=2000"`
+	PhotoFileIDs []uint `json:"photo_file_ids" binding:"omitempty,max=9"`
+}
+
+// ReplyReviewRequest 回复评价请求
+type ReplyReviewRequest struct {
+	Content string `json:"content" binding:"required,max=1000"`
+}
+
+// FlagReviewRequest 举报评价请求
+type FlagReviewRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ListReviewRequest 评价列表请求
+type ListReviewRequest struct {
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	PageSize   int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	TargetType string `form:"target_type" binding:"required"`
+	TargetID   uint   `form:"target_id" binding:"required"`
+	Rating     int    `form:"rating" binding:"omitempty,min=1,max=5"`
+}
+
+// RatingSummaryRequest 评分汇总请求
+type RatingSummaryRequest struct {
+	TargetType string `form:"target_type" binding:"required"`
+	TargetID   uint   `form:"target_id" binding:"required"`
+}
+
+// ListMyReviewRequest 我的评价列表请求
+type ListMyReviewRequest struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=10" binding:"min=1,max=100"`
+}
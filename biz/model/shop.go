@@ -0,0 +1,199 @@
+package model
+
+import (
+	"time"
+)
+
+// 商品状态
+const (
+	ProductStatusDraft   = "draft"
+	ProductStatusOnSale  = "on_sale"
+	ProductStatusOffSale = "off_sale"
+)
+
+// 库存预占状态
+const (
+	ReservationStatusReserved = "reserved"
+	ReservationStatusConsumed = "consumed"
+	ReservationStatusReleased = "released"
+)
+
+// ProductCategory 商品分类,支持两级
+type ProductCategory struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ParentID  uint      `json:"parent_id" gorm:"index;default:0;comment:上级分类ID,0为一级分类"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null;comment:分类名称"`
+	Sort      int       `json:"sort" gorm:"default:0;comment:排序,越小越靠前"`
+	Status    int       `json:"status" gorm:"type:tinyint;default:1;comment:状态:0停用,1启用"`
+}
+
+// TableName 指定表名
+func (ProductCategory) TableName() string {
+	return "product_categories"
+}
+
+// Product 商品
+type Product struct {
+	ID           uint             `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+	CategoryID   uint             `json:"category_id" gorm:"index;not null;comment:分类ID"`
+	Name         string           `json:"name" gorm:"type:varchar(100);not null;comment:商品名称"`
+	Description  string           `json:"description" gorm:"type:text;comment:商品描述"`
+	ImageFileIDs []uint           `json:"image_file_ids" gorm:"type:json;serializer:json;comment:图片文件ID"`
+	Status       string           `json:"status" gorm:"type:varchar(20);index;default:draft;comment:状态:draft,on_sale,off_sale"`
+	MinPrice     int64            `json:"min_price" gorm:"default:0;comment:在售规格最低价(分)"`
+	CreatedBy    uint             `json:"created_by" gorm:"comment:创建人用户ID"`
+	Category     *ProductCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	SKUs         []*ProductSKU    `json:"skus,omitempty" gorm:"foreignKey:ProductID"`
+	Images       []string         `json:"images,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (Product) TableName() string {
+	return "products"
+}
+
+// ProductSKU 商品规格,库存按规格管理
+type ProductSKU struct {
+	ID         uint              `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	ProductID  uint              `json:"product_id" gorm:"index;not null;comment:商品ID"`
+	SKUCode    string            `json:"sku_code" gorm:"column:sku_code;type:varchar(64);uniqueIndex;not null;comment:规格编码"`
+	Name       string            `json:"name" gorm:"type:varchar(100);not null;comment:规格名称"`
+	Attributes map[string]string `json:"attributes" gorm:"type:json;serializer:json;comment:规格属性,如口味、重量"`
+	Price      int64             `json:"price" gorm:"not null;comment:售价(分)"`
+	Stock      int               `json:"stock" gorm:"default:0;comment:可售库存"`
+	Status     int               `json:"status" gorm:"type:tinyint;default:1;comment:状态:0停售,1在售"`
+	Product    *Product          `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (ProductSKU) TableName() string {
+	return "product_skus"
+}
+
+// Purchasable 规格在售且所属商品已上架
+func (s *ProductSKU) Purchasable() bool {
+	return s.Status == 1 && s.Product != nil && s.Product.Status == ProductStatusOnSale
+}
+
+// ReservationItem 预占明细,记录下单时的商品快照
+type ReservationItem struct {
+	SKUID       uint   `json:"sku_id"`
+	ProductID   uint   `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SKUName     string `json:"sku_name"`
+	Price       int64  `json:"price"`
+	Quantity    int    `json:"quantity"`
+}
+
+// StockReservation 结算时的库存预占,超时未下单自动释放
+type StockReservation struct {
+	ID          uint               `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	UserID      uint               `json:"user_id" gorm:"index;not null;comment:用户ID"`
+	Items       []*ReservationItem `json:"items" gorm:"type:json;serializer:json;comment:预占明细"`
+	TotalAmount int64              `json:"total_amount" gorm:"not null;comment:商品总额(分)"`
+	Status      string             `json:"status" gorm:"type:varchar(20);index:idx_status_expires,priority:1;default:reserved;comment:状态:reserved,consumed,released"`
+	ExpiresAt   time.Time          `json:"expires_at" gorm:"index:idx_status_expires,priority:2;not null;comment:过期时间"`
+}
+
+// TableName 指定表名
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
+// CartItem 购物车条目,购物车存储在Redis中,展示时补齐商品信息
+type CartItem struct {
+	SKUID       uint              `json:"sku_id"`
+	Quantity    int               `json:"quantity"`
+	ProductID   uint              `json:"product_id"`
+	ProductName string            `json:"product_name"`
+	SKUName     string            `json:"sku_name"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Image       string            `json:"image,omitempty"`
+	Price       int64             `json:"price"`
+	Stock       int               `json:"stock"`
+	Available   bool              `json:"available"` // 已下架或库存不足时为false,结算时跳过
+	Subtotal    int64             `json:"subtotal"`
+}
+
+// Cart 购物车
+type Cart struct {
+	Items         []*CartItem `json:"items"`
+	TotalQuantity int         `json:"total_quantity"`
+	TotalAmount   int64       `json:"total_amount"` // 仅统计可购买的条目
+}
+
+// SaveCategoryRequest 创建/更新商品分类请求
+type SaveCategoryRequest struct {
+	ParentID uint   `json:"parent_id"`
+	Name     string `json:"name" binding:"required,max=50"`
+	Sort     int    `json:"sort"`
+	Status   *int   `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// SKURequest 创建/更新规格请求
+type SKURequest struct {
+	SKUCode    string            `json:"sku_code" binding:"required,max=64"`
+	Name       string            `json:"name" binding:"required,max=100"`
+	Attributes map[string]string `json:"attributes"`
+	Price      int64             `json:"price" binding:"min=0"`
+	Stock      int               `json:"stock" binding:"min=0"` // 仅创建时生效,之后通过库存调整接口修改
+	Status     *int              `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// CreateProductRequest 创建商品请求
+type CreateProductRequest struct {
+	CategoryID   uint          `json:"category_id" binding:"required"`
+	Name         string        `json:"name" binding:"required,max=100"`
+	Description  string        `json:"description"`
+	ImageFileIDs []uint        `json:"image_file_ids"`
+	SKUs         []*SKURequest `json:"skus" binding:"required"`
+}
+
+// UpdateProductRequest 更新商品请求
+type UpdateProductRequest struct {
+	CategoryID   uint   `json:"category_id" binding:"required"`
+	Name         string `json:"name" binding:"required,max=100"`
+	Description  string `json:"description"`
+	ImageFileIDs []uint `json:"image_file_ids"`
+}
+
+// UpdateProductStatusRequest 商品上下架请求
+type UpdateProductStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft on_sale off_sale"`
+}
+
+// AdjustStockRequest 库存调整请求,delta为正数入库、负数出库
+type AdjustStockRequest struct {
+	Delta  int    `json:"delta" binding:"required"`
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// ListProductRequest 商品列表请求
+type ListProductRequest struct {
+	Page       int    `form:"page,default=1" binding:"min=1"`
+	PageSize   int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	CategoryID uint   `form:"category_id"`
+	Keyword    string `form:"keyword"`
+	MinPrice   int64  `form:"min_price"`
+	MaxPrice   int64  `form:"max_price"`
+	Sort       string `form:"sort"`   // newest(默认),price_asc,price_desc
+	Status     string `form:"status"` // 仅管理端生效
+}
+
+// UpdateCartItemRequest 设置购物车条目数量请求,数量为0时移除
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"min=0,max=99"`
+}
+
+// CheckoutRequest 结算请求,不传sku_ids时结算购物车中全部可购买的条目
+type CheckoutRequest struct {
+	SKUIDs []uint `json:"sku_ids"`
+}
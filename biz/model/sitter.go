@@ -0,0 +1,207 @@
+package model
+
+import (
+	"time"
+)
+
+// 寄养服务类型
+const (
+	SitterServiceBoarding     = "boarding"      // 上门寄养(宠物住在看护人家)
+	SitterServiceHouseSitting = "house_sitting" // 住家看护
+	SitterServiceDropIn       = "drop_in"       // 上门喂养
+	SitterServiceWalking      = "walking"       // 遛狗
+)
+
+// SitterServices 支持的服务类型
+var SitterServices = map[string]bool{
+	SitterServiceBoarding:     true,
+	SitterServiceHouseSitting: true,
+	SitterServiceDropIn:       true,
+	SitterServiceWalking:      true,
+}
+
+// 档期状态
+const (
+	CalendarAvailable   = "available"
+	CalendarUnavailable = "unavailable"
+	CalendarBooked      = "booked"
+)
+
+// 寄养预约状态
+const (
+	BookingStatusRequested = "requested"
+	BookingStatusAccepted  = "accepted"
+	BookingStatusDeclined  = "declined"
+	BookingStatusCancelled = "cancelled"
+	BookingStatusCompleted = "completed"
+)
+
+// DateLayout 日期格式
+const DateLayout = "2006-01-02"
+
+// SitterProfile 寄养看护人资料
+type SitterProfile struct {
+	ID              uint      `json:"id" gorm:"primarykey"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	UserID          uint      `json:"user_id" gorm:"uniqueIndex;not null;comment:用户ID"`
+	Headline        string    `json:"headline" gorm:"type:varchar(100);not null;comment:标题"`
+	Bio             string    `json:"bio" gorm:"type:text;comment:个人介绍"`
+	Services        []string  `json:"services" gorm:"type:json;serializer:json;comment:服务类型"`
+	AcceptedSpecies []string  `json:"accepted_species" gorm:"type:json;serializer:json;comment:接受的物种"`
+	PricePerNight   int64     `json:"price_per_night" gorm:"not null;comment:每晚价格(分)"`
+	MaxPets         int       `json:"max_pets" gorm:"default:1;comment:单次最多接待宠物数"`
+	City            string    `json:"city" gorm:"type:varchar(50);index;comment:城市"`
+	ServiceArea     string    `json:"service_area" gorm:"type:varchar(255);comment:服务区域说明"`
+	Latitude        float64   `json:"latitude" gorm:"type:decimal(10,7);comment:纬度"`
+	Longitude       float64   `json:"longitude" gorm:"type:decimal(10,7);comment:经度"`
+	ServiceRadiusKm float64   `json:"service_radius_km" gorm:"type:decimal(6,2);default:0;comment:服务半径(km)"`
+	Status          int       `json:"status" gorm:"type:tinyint;default:1;comment:状态:0暂停接单,1接单中"`
+	CompletedStays  int       `json:"completed_stays" gorm:"default:0;comment:已完成寄养次数"`
+	User            *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Distance        *float64  `json:"distance_km,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (SitterProfile) TableName() string {
+	return "sitter_profiles"
+}
+
+// AcceptsSpecies 是否接受该物种
+func (p *SitterProfile) AcceptsSpecies(species string) bool {
+	for _, s := range p.AcceptedSpecies {
+		if s == species {
+			return true
+		}
+	}
+	return false
+}
+
+// OffersService 是否提供该服务
+func (p *SitterProfile) OffersService(service string) bool {
+	for _, s := range p.Services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// SitterCalendarDay 看护人档期,按天记录,预约占用时写入预约ID
+type SitterCalendarDay struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	UpdatedAt time.Time `json:"updated_at"`
+	SitterID  uint      `json:"sitter_id" gorm:"uniqueIndex:idx_sitter_date,priority:1;not null;comment:看护人资料ID"`
+	Date      time.Time `json:"date" gorm:"type:date;uniqueIndex:idx_sitter_date,priority:2;not null;comment:日期"`
+	Status    string    `json:"status" gorm:"type:varchar(20);not null;comment:状态:available,unavailable,booked"`
+	BookingID uint      `json:"booking_id" gorm:"index;default:0;comment:占用的预约ID"`
+}
+
+// TableName 指定表名
+func (SitterCalendarDay) TableName() string {
+	return "sitter_calendar_days"
+}
+
+// CalendarDay 对外展示的单日档期,未设置的日期视为不可约
+type CalendarDay struct {
+	Date   string `json:"date"`
+	Status string `json:"status"`
+}
+
+// SitterBooking 寄养预约
+type SitterBooking struct {
+	ID            uint           `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	SitterID      uint           `json:"sitter_id" gorm:"index:idx_sitter_status,priority:1;not null;comment:看护人资料ID"`
+	SitterUserID  uint           `json:"sitter_user_id" gorm:"index;not null;comment:看护人用户ID"`
+	OwnerID       uint           `json:"owner_id" gorm:"index;not null;comment:宠物主人用户ID"`
+	PetIDs        []uint         `json:"pet_ids" gorm:"type:json;serializer:json;comment:寄养宠物ID"`
+	Service       string         `json:"service" gorm:"type:varchar(20);not null;comment:服务类型"`
+	StartDate     time.Time      `json:"start_date" gorm:"type:date;not null;comment:开始日期"`
+	EndDate       time.Time      `json:"end_date" gorm:"type:date;not null;comment:结束日期(不含)"`
+	Nights        int            `json:"nights" gorm:"not null;comment:晚数"`
+	TotalPrice    int64          `json:"total_price" gorm:"not null;comment:总价(分)"`
+	Message       string         `json:"message" gorm:"type:varchar(500);comment:留言"`
+	Status        string         `json:"status" gorm:"type:varchar(20);index:idx_sitter_status,priority:2;default:requested;comment:状态"`
+	DeclineReason string         `json:"decline_reason" gorm:"type:varchar(255);comment:拒绝/取消原因"`
+	RespondedAt   *time.Time     `json:"responded_at" gorm:"comment:看护人响应时间"`
+	CompletedAt   *time.Time     `json:"completed_at" gorm:"comment:完成时间"`
+	Sitter        *SitterProfile `json:"sitter,omitempty" gorm:"foreignKey:SitterID"`
+	Pets          []*Pet         `json:"pets,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (SitterBooking) TableName() string {
+	return "sitter_bookings"
+}
+
+// Reviewable 已完成的寄养可以评价
+func (b *SitterBooking) Reviewable() bool {
+	return b.Status == BookingStatusCompleted
+}
+
+// UpsertSitterProfileRequest 创建/更新看护人资料请求
+type UpsertSitterProfileRequest struct {
+	Headline        string   `json:"headline" binding:"required,max=100"`
+	Bio             string   `json:"bio"`
+	Services        []string `json:"services" binding:"required"`
+	AcceptedSpecies []string `json:"accepted_species" binding:"required"`
+	PricePerNight   int64    `json:"price_per_night" binding:"min=0"`
+	MaxPets         int      `json:"max_pets" binding:"omitempty,min=1,max=10"`
+	City            string   `json:"city" binding:"required,max=50"`
+	ServiceArea     string   `json:"service_area" binding:"omitempty,max=255"`
+	Latitude        float64  `json:"latitude"`
+	Longitude       float64  `json:"longitude"`
+	ServiceRadiusKm float64  `json:"service_radius_km" binding:"omitempty,min=0,max=100"`
+	Status          *int     `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// ListSitterRequest 看护人检索请求
+type ListSitterRequest struct {
+	Page      int     `form:"page,default=1" binding:"min=1"`
+	PageSize  int     `form:"page_size,default=10" binding:"min=1,max=100"`
+	City      string  `form:"city"`
+	Species   string  `form:"species"`
+	Service   string  `form:"service"`
+	MaxPrice  int64   `form:"max_price"`
+	StartDate string  `form:"start_date"` // 同时传入start_date和end_date时只返回整段档期可约的看护人
+	EndDate   string  `form:"end_date"`
+	Latitude  float64 `form:"lat"`
+	Longitude float64 `form:"lng"`
+}
+
+// SetCalendarRequest 设置档期请求,日期区间为[start_date, end_date]
+type SetCalendarRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Available bool   `json:"available"`
+}
+
+// CalendarRequest 档期查询请求
+type CalendarRequest struct {
+	StartDate string `form:"start_date"`
+	EndDate   string `form:"end_date"`
+}
+
+// CreateBookingRequest 预约请求,end_date为离开日期(不计入晚数)
+type CreateBookingRequest struct {
+	PetIDs    []uint `json:"pet_ids" binding:"required"`
+	Service   string `json:"service" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Message   string `json:"message" binding:"omitempty,max=500"`
+}
+
+// RespondBookingRequest 拒绝/取消预约请求
+type RespondBookingRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=255"`
+}
+
+// ListBookingRequest 预约列表请求
+type ListBookingRequest struct {
+	Page     int    `form:"page,default=1" binding:"min=1"`
+	PageSize int    `form:"page_size,default=10" binding:"min=1,max=100"`
+	Role     string `form:"role,default=owner" binding:"omitempty,oneof=owner sitter"`
+	Status   string `form:"status"`
+}
@@ -6,17 +6,19 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        uint      `json:"id" gorm:"primarykey"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Username  string    `json:"username" gorm:"type:varchar(50);uniqueIndex;not null;comment:用户名"`
-	Password  string    `json:"-" gorm:"type:varchar(255);not null;comment:密码"`
-	Email     string    `json:"email" gorm:"type:varchar(100);uniqueIndex;comment:邮箱"`
-	Phone     string    `json:"phone" gorm:"type:varchar(20);uniqueIndex;comment:手机号"`
-	Nickname  string    `json:"nickname" gorm:"type:varchar(50);comment:昵称"`
-	Avatar    string    `json:"avatar" gorm:"type:varchar(255);comment:头像"`
-	Status    int       `json:"status" gorm:"type:tinyint;default:1;comment:状态:0禁用,1正常"`
-	IsDeleted int       `json:"is_deleted" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
+	ID           uint      `json:"id" gorm:"primarykey"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Username     string    `json:"username" gorm:"type:varchar(50);uniqueIndex;not null;comment:用户名"`
+	Password     string    `json:"-" gorm:"type:varchar(255);not null;comment:密码"`
+	Email        string    `json:"email" gorm:"type:varchar(100);uniqueIndex;comment:邮箱"`
+	Phone        string    `json:"phone" gorm:"type:varchar(20);uniqueIndex;comment:手机号"`
+	Nickname     string    `json:"nickname" gorm:"type:varchar(50);comment:昵称"`
+	Avatar       string    `json:"avatar" gorm:"type:varchar(255);comment:头像"`
+	AvatarFileID uint      `json:"avatar_file_id" gorm:"default:0;comment:头像文件ID"`
+	Status       int       `json:"status" gorm:"type:tinyint;default:1;comment:状态:0禁用,1正常"`
+	Role         string    `json:"role" gorm:"type:varchar(20);default:user;comment:角色:user,admin,shop_manager"`
+	IsDeleted    int       `json:"is_deleted" gorm:"type:tinyint;default:0;comment:是否删除:0否,1是"`
 }
 
 // TableName 指定表名
@@ -35,11 +37,17 @@ type CreateUserRequest struct {
 
 // UpdateUserRequest 更新用户请求
 type UpdateUserRequest struct {
-	Email    string `json:"email" binding:"omitempty,email"`
-	Phone    string `json:"phone" binding:"omitempty,len=11"`
-	Nickname string `json:"nickname" binding:"omitempty,max=50"`
-	Avatar   string `json:"avatar" binding:"omitempty,max=255"`
-	Status   *int   `json:"status" binding:"omitempty,oneof=0 1"`
+	Email        string `json:"email" binding:"omitempty,email"`
+	Phone        string `json:"phone" binding:"omitempty,len=11"`
+	Nickname     string `json:"nickname" binding:"omitempty,max=50"`
+	Avatar       string `json:"avatar" binding:"omitempty,max=255"`
+	AvatarFileID uint   `json:"avatar_file_id"`
+	Status       *int   `json:"status" binding:"omitempty,oneof=0 1"`
+}
+
+// UpdateUserRoleRequest 修改用户角色请求
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin shop_manager"`
 }
 
 // LoginRequest 登录请求
@@ -50,15 +58,17 @@ type LoginRequest struct {
 
 // UserResponse 用户响应
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Phone     string    `json:"phone"`
-	Nickname  string    `json:"nickname"`
-	Avatar    string    `json:"avatar"`
-	Status    int       `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `json:"id"`
+	Username     string    `json:"username"`
+	Email        string    `json:"email"`
+	Phone        string    `json:"phone"`
+	Nickname     string    `json:"nickname"`
+	Avatar       string    `json:"avatar"`
+	AvatarFileID uint      `json:"avatar_file_id"`
+	Status       int       `json:"status"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // LoginResponse 登录响应
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// activityBucketExprs 活动统计时间桶表达式,周以周一为起始,月以1日为起始
+var activityBucketExprs = map[string]string{
+	model.GoalPeriodWeek:  "DATE(DATE_SUB(started_at, INTERVAL WEEKDAY(started_at) DAY))",
+	model.GoalPeriodMonth: "DATE(DATE_SUB(started_at, INTERVAL DAYOFMONTH(started_at) - 1 DAY))",
+}
+
+// activitySumColumns 活动汇总字段,有距离的活动时长单独统计用于计算平均配速
+const activitySumColumns = "COUNT(*) AS count, COALESCE(SUM(duration_sec), 0) AS duration_sec, " +
+	"COALESCE(SUM(distance_m), 0) AS distance_m, COALESCE(SUM(calories), 0) AS calories, " +
+	"COALESCE(SUM(CASE WHEN distance_m > 0 THEN duration_sec ELSE 0 END), 0) AS tracked_duration_sec"
+
+// ActivityRepository 运动记录仓储接口
+type ActivityRepository interface {
+	// Create 创建活动,track不为空时在同一事务中保存轨迹
+	Create(ctx context.Context, activity *model.Activity, track *model.ActivityTrack) error
+	Update(ctx context.Context, activity *model.Activity) error
+	// Delete 软删除活动并删除轨迹数据
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*model.Activity, error)
+	GetTrack(ctx context.Context, activityID uint) (*model.ActivityTrack, error)
+	List(ctx context.Context, petID uint, req *model.ListActivityRequest, offset, limit int) ([]*model.Activity, int64, error)
+	Aggregate(ctx context.Context, petID uint, activityType string, from, to time.Time, period string) ([]*model.ActivityBucket, error)
+	Sum(ctx context.Context, petID uint, from, to time.Time) (*model.ActivityBucket, error)
+
+	ListGoals(ctx context.Context, petID uint) ([]*model.ActivityGoal, error)
+	GetGoal(ctx context.Context, id uint) (*model.ActivityGoal, error)
+	// SaveGoal 按宠物、周期和指标覆盖保存目标
+	SaveGoal(ctx context.Context, goal *model.ActivityGoal) error
+	DeleteGoal(ctx context.Context, id uint) error
+}
+
+// activityRepository 运动记录仓储实现
+type activityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository 创建运动记录仓储
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// Create 创建活动及轨迹
+func (r *activityRepository) Create(ctx context.Context, activity *model.Activity, track *model.ActivityTrack) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(activity).Error; err != nil {
+			return err
+		}
+		if track == nil {
+			return nil
+		}
+		track.ActivityID = activity.ID
+		return tx.Create(track).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "创建运动记录失败", logger.Int("pet_id", int(activity.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Update 更新活动
+func (r *activityRepository) Update(ctx context.Context, activity *model.Activity) error {
+	err := r.db.WithContext(ctx).Model(activity).
+		Select("type", "title", "started_at", "duration_sec", "distance_m", "pace_sec_per_km", "calories", "notes").
+		Updates(activity).Error
+	if err != nil {
+		logger.Error(ctx, "更新运动记录失败", logger.Int("id", int(activity.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Delete 软删除活动,轨迹数据较大,直接删除
+func (r *activityRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Activity{}).Where("id = ?", id).Update("is_deleted", 1).Error; err != nil {
+			return err
+		}
+		return tx.Where("activity_id = ?", id).Delete(&model.ActivityTrack{}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "删除运动记录失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 根据ID获取活动
+func (r *activityRepository) GetByID(ctx context.Context, id uint) (*model.Activity, error) {
+	var activity model.Activity
+	if err := r.db.WithContext(ctx).Where("id = ? AND is_deleted = 0", id).First(&activity).Error; err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// GetTrack 获取活动轨迹
+func (r *activityRepository) GetTrack(ctx context.Context, activityID uint) (*model.ActivityTrack, error) {
+	var track model.ActivityTrack
+	if err := r.db.WithContext(ctx).Where("activity_id = ?", activityID).First(&track).Error; err != nil {
+		return nil, err
+	}
+	return &track, nil
+}
+
+// List 分页获取宠物的活动,按开始时间倒序
+func (r *activityRepository) List(ctx context.Context, petID uint, req *model.ListActivityRequest, offset, limit int) ([]*model.Activity, int64, error) {
+	var activities []*model.Activity
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Activity{}).Where("pet_id = ? AND is_deleted = 0", petID)
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.From != nil {
+		query = query.Where("started_at >= ?", *req.From)
+	}
+	if req.To != nil {
+		query = query.Where("started_at < ?", *req.To)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取运动记录总数失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("started_at DESC, id DESC").Find(&activities).Error; err != nil {
+		logger.Error(ctx, "获取运动记录失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return activities, total, nil
+}
+
+// Aggregate 按周或按月汇总区间[from, to)内的活动
+func (r *activityRepository) Aggregate(ctx context.Context, petID uint, activityType string, from, to time.Time, period string) ([]*model.ActivityBucket, error) {
+	var buckets []*model.ActivityBucket
+	query := r.db.WithContext(ctx).Model(&model.Activity{}).
+		Select(activityBucketExprs[period]+" AS bucket, "+activitySumColumns).
+		Where("pet_id = ? AND is_deleted = 0 AND started_at >= ? AND started_at < ?", petID, from, to)
+	if activityType != "" {
+		query = query.Where("type = ?", activityType)
+	}
+	err := query.Group("bucket").Order("bucket ASC").Scan(&buckets).Error
+	if err != nil {
+		logger.Error(ctx, "汇总运动记录失败", logger.Int("pet_id", int(petID)), logger.String("period", period), logger.ErrorField(err))
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// Sum 汇总区间[from, to)内的全部活动
+func (r *activityRepository) Sum(ctx context.Context, petID uint, from, to time.Time) (*model.ActivityBucket, error) {
+	var bucket model.ActivityBucket
+	err := r.db.WithContext(ctx).Model(&model.Activity{}).
+		Select(activitySumColumns).
+		Where("pet_id = ? AND is_deleted = 0 AND started_at >= ? AND started_at < ?", petID, from, to).
+		Scan(&bucket).Error
+	if err != nil {
+		logger.Error(ctx, "汇总运动记录失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	bucket.Bucket = from
+	return &bucket, nil
+}
+
+// ListGoals 获取宠物的运动目标
+func (r *activityRepository) ListGoals(ctx context.Context, petID uint) ([]*model.ActivityGoal, error) {
+	var goals []*model.ActivityGoal
+	if err := r.db.WithContext(ctx).Where("pet_id = ?", petID).Order("period ASC, metric ASC").Find(&goals).Error; err != nil {
+		logger.Error(ctx, "获取运动目标失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return goals, nil
+}
+
+// GetGoal 根据ID获取运动目标
+func (r *activityRepository) GetGoal(ctx context.Context, id uint) (*model.ActivityGoal, error) {
+	var goal model.ActivityGoal
+	if err := r.db.WithContext(ctx).First(&goal, id).Error; err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// SaveGoal 保存运动目标,已存在同周期同指标的目标时更新目标值
+func (r *activityRepository) SaveGoal(ctx context.Context, goal *model.ActivityGoal) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "pet_id"}, {Name: "period"}, {Name: "metric"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target", "updated_at"}),
+	}).Create(goal).Error
+	if err != nil {
+		logger.Error(ctx, "保存运动目标失败", logger.Int("pet_id", int(goal.PetID)), logger.ErrorField(err))
+		return err
+	}
+	// 冲突更新时MySQL不会返回已有记录的ID,重新查询
+	return r.db.WithContext(ctx).
+		Where("pet_id = ? AND period = ? AND metric = ?", goal.PetID, goal.Period, goal.Metric).
+		First(goal).Error
+}
+
+// DeleteGoal 删除运动目标
+func (r *activityRepository) DeleteGoal(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&model.ActivityGoal{}, id).Error; err != nil {
+		logger.Error(ctx, "删除运动目标失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
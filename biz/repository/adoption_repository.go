@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+var (
+	// ErrListingNotOpen 领养信息已不在开放状态,无法通过新的申请
+	ErrListingNotOpen = errors.New("该宠物已有通过审核的申请或已停止领养")
+	// ErrListingStatusChanged 领养信息状态已变更
+	ErrListingStatusChanged = errors.New("领养信息状态已变更,请刷新后重试")
+)
+
+// ListingFilter 领养信息检索条件
+type ListingFilter struct {
+	Species    string
+	Size       string
+	City       string
+	ShelterID  uint
+	Keyword    string
+	BornAfter  *time.Time
+	BornBefore *time.Time
+	Offset     int
+	Limit      int
+}
+
+// AdoptionRepository 领养仓储接口
+type AdoptionRepository interface {
+	CreateListing(ctx context.Context, pet *model.Pet, listing *model.AdoptionListing) error
+	UpdateListing(ctx context.Context, listing *model.AdoptionListing, fromStatus string) error
+	GetListing(ctx context.Context, id uint) (*model.AdoptionListing, error)
+	SearchListings(ctx context.Context, filter *ListingFilter) ([]*model.AdoptionListing, int64, error)
+
+	CreateApplication(ctx context.Context, application *model.AdoptionApplication) error
+	GetApplication(ctx context.Context, id uint) (*model.AdoptionApplication, error)
+	HasActiveApplication(ctx context.Context, listingID, applicantID uint) (bool, error)
+	ListApplicationsByApplicant(ctx context.Context, applicantID uint, status string, offset, limit int) ([]*model.AdoptionApplication, int64, error)
+	ListApplicationsByShelter(ctx context.Context, shelterID uint, status string, offset, limit int) ([]*model.AdoptionApplication, int64, error)
+	UpdateApplicationStatus(ctx context.Context, application *model.AdoptionApplication, fromStatus string) error
+	CompleteApplication(ctx context.Context, application *model.AdoptionApplication, listing *model.AdoptionListing) error
+}
+
+// adoptionRepository 领养仓储实现
+type adoptionRepository struct {
+	db *gorm.DB
+}
+
+// NewAdoptionRepository 创建领养仓储
+func NewAdoptionRepository(db *gorm.DB) AdoptionRepository {
+	return &adoptionRepository{db: db}
+}
+
+// CreateListing 创建宠物档案并发布领养信息
+func (r *adoptionRepository) CreateListing(ctx context.Context, pet *model.Pet, listing *model.AdoptionListing) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pet).Error; err != nil {
+			return err
+		}
+		listing.PetID = pet.ID
+		return tx.Create(listing).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "发布领养信息失败", logger.Int("shelter_id", int(listing.ShelterID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "发布领养信息成功", logger.Int("id", int(listing.ID)), logger.Int("pet_id", int(pet.ID)))
+	return nil
+}
+
+// UpdateListing 更新领养信息,fromStatus为空时不修改状态;
+// 否则仅当领养信息仍处于fromStatus时才更新,避免覆盖审核通过时锁定的pending状态
+func (r *adoptionRepository) UpdateListing(ctx context.Context, listing *model.AdoptionListing, fromStatus string) error {
+	query := r.db.WithContext(ctx).Model(&model.AdoptionListing{}).Where("id = ?", listing.ID)
+	columns := []string{"title", "description", "city", "fee"}
+	if fromStatus != "" {
+		query = query.Where("status = ?", fromStatus)
+		columns = append(columns, "status")
+	}
+	result := query.Select(columns).Updates(listing)
+	if result.Error != nil {
+		logger.Error(ctx, "更新领养信息失败", logger.Int("id", int(listing.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if fromStatus != "" && result.RowsAffected == 0 {
+		return ErrListingStatusChanged
+	}
+	return nil
+}
+
+// GetListing 获取领养信息详情
+func (r *adoptionRepository) GetListing(ctx context.Context, id uint) (*model.AdoptionListing, error) {
+	var listing model.AdoptionListing
+	err := r.db.WithContext(ctx).Preload("Pet").Preload("Shelter").Where("id = ?", id).First(&listing).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取领养信息失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// SearchListings 检索可领养的宠物
+func (r *adoptionRepository) SearchListings(ctx context.Context, filter *ListingFilter) ([]*model.AdoptionListing, int64, error) {
+	var listings []*model.AdoptionListing
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.AdoptionListing{}).
+		Joins("JOIN pets ON pets.id = adoption_listings.pet_id AND pets.is_deleted = 0").
+		Where("adoption_listings.status = ?", model.ListingStatusOpen)
+
+	if filter.Species != "" {
+		query = query.Where("pets.species = ?", filter.Species)
+	}
+	if filter.Size != "" {
+		query = query.Where("pets.size = ?", filter.Size)
+	}
+	if filter.City != "" {
+		query = query.Where("adoption_listings.city = ?", filter.City)
+	}
+	if filter.ShelterID != 0 {
+		query = query.Where("adoption_listings.shelter_id = ?", filter.ShelterID)
+	}
+	if filter.BornAfter != nil {
+		query = query.Where("pets.birth_date >= ?", *filter.BornAfter)
+	}
+	if filter.BornBefore != nil {
+		query = query.Where("pets.birth_date <= ?", *filter.BornBefore)
+	}
+	if filter.Keyword != "" {
+		query = query.Where("adoption_listings.title LIKE ? OR pets.name LIKE ? OR pets.breed LIKE ?",
+			"%"+filter.Keyword+"%", "%"+filter.Keyword+"%", "%"+filter.Keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取领养信息总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+
+	err := query.Preload("Pet").Preload("Shelter").
+		Offset(filter.Offset).Limit(filter.Limit).
+		Order("adoption_listings.created_at DESC").
+		Find(&listings).Error
+	if err != nil {
+		logger.Error(ctx, "检索领养信息失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return listings, total, nil
+}
+
+// CreateApplication 创建领养申请
+func (r *adoptionRepository) CreateApplication(ctx context.Context, application *model.AdoptionApplication) error {
+	if err := r.db.WithContext(ctx).Create(application).Error; err != nil {
+		logger.Error(ctx, "创建领养申请失败", logger.Int("listing_id", int(application.ListingID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建领养申请成功", logger.Int("id", int(application.ID)))
+	return nil
+}
+
+// GetApplication 获取领养申请
+func (r *adoptionRepository) GetApplication(ctx context.Context, id uint) (*model.AdoptionApplication, error) {
+	var application model.AdoptionApplication
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&application).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取领养申请失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &application, nil
+}
+
+// HasActiveApplication 用户对该领养信息是否存在处理中的申请
+func (r *adoptionRepository) HasActiveApplication(ctx context.Context, listingID, applicantID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.AdoptionApplication{}).
+		Where("listing_id = ? AND applicant_id = ? AND status NOT IN ?", listingID, applicantID,
+			[]string{model.ApplicationStatusRejected, model.ApplicationStatusCompleted}).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询领养申请失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListApplicationsByApplicant 获取申请人的领养申请
+func (r *adoptionRepository) ListApplicationsByApplicant(ctx context.Context, applicantID uint, status string, offset, limit int) ([]*model.AdoptionApplication, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.AdoptionApplication{}).Where("applicant_id = ?", applicantID)
+	return r.listApplications(ctx, query, status, offset, limit)
+}
+
+// ListApplicationsByShelter 获取救助机构收到的领养申请
+func (r *adoptionRepository) ListApplicationsByShelter(ctx context.Context, shelterID uint, status string, offset, limit int) ([]*model.AdoptionApplication, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.AdoptionApplication{}).Where("shelter_id = ?", shelterID)
+	return r.listApplications(ctx, query, status, offset, limit)
+}
+
+func (r *adoptionRepository) listApplications(ctx context.Context, query *gorm.DB, status string, offset, limit int) ([]*model.AdoptionApplication, int64, error) {
+	var applications []*model.AdoptionApplication
+	var total int64
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取领养申请总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&applications).Error; err != nil {
+		logger.Error(ctx, "获取领养申请列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return applications, total, nil
+}
+
+// UpdateApplicationStatus 更新申请状态,仅当申请仍处于fromStatus时生效,避免并发审核覆盖
+// 通过审核时在同一事务内将领养信息由open锁定为pending,领养信息已不是open时审核失败;
+// 已通过的申请被拒绝时同一事务内将pending的领养信息重新开放
+func (r *adoptionRepository) UpdateApplicationStatus(ctx context.Context, application *model.AdoptionApplication, fromStatus string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.AdoptionApplication{}).
+			Where("id = ? AND status = ?", application.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":      application.Status,
+				"reviewer_id": application.ReviewerID,
+				"review_note": application.ReviewNote,
+				"reviewed_at": application.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("领养申请状态已变更,请刷新后重试")
+		}
+
+		switch {
+		case application.Status == model.ApplicationStatusApproved:
+			result = tx.Model(&model.AdoptionListing{}).
+				Where("id = ? AND status = ?", application.ListingID, model.ListingStatusOpen).
+				Update("status", model.ListingStatusPending)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrListingNotOpen
+			}
+		case fromStatus == model.ApplicationStatusApproved && application.Status == model.ApplicationStatusRejected:
+			return tx.Model(&model.AdoptionListing{}).
+				Where("id = ? AND status = ?", application.ListingID, model.ListingStatusPending).
+				Update("status", model.ListingStatusOpen).Error
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "更新领养申请状态失败", logger.Int("id", int(application.ID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "更新领养申请状态成功", logger.Int("id", int(application.ID)), logger.String("status", application.Status))
+	return nil
+}
+
+// CompleteApplication 完成领养:更新申请与领养信息状态,将宠物档案转移给领养人,并拒绝其他处理中的申请
+func (r *adoptionRepository) CompleteApplication(ctx context.Context, application *model.AdoptionApplication, listing *model.AdoptionListing) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.AdoptionApplication{}).
+			Where("id = ? AND status = ?", application.ID, model.ApplicationStatusApproved).
+			Updates(map[string]interface{}{
+				"status":       model.ApplicationStatusCompleted,
+				"reviewer_id":  application.ReviewerID,
+				"review_note":  application.ReviewNote,
+				"reviewed_at":  application.ReviewedAt,
+				"completed_at": application.CompletedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("领养申请状态已变更,请刷新后重试")
+		}
+
+		if err := tx.Model(&model.AdoptionListing{}).Where("id = ?", listing.ID).
+			Update("status", model.ListingStatusAdopted).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Pet{}).Where("id = ?", listing.PetID).
+			Updates(map[string]interface{}{"owner_id": application.ApplicantID, "shelter_id": 0}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.AdoptionApplication{}).
+			Where("listing_id = ? AND id <> ? AND status NOT IN ?", listing.ID, application.ID,
+				[]string{model.ApplicationStatusRejected, model.ApplicationStatusCompleted}).
+			Updates(map[string]interface{}{
+				"status":      model.ApplicationStatusRejected,
+				"review_note": "该宠物已被领养",
+			}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "完成领养失败", logger.Int("id", int(application.ID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "完成领养,宠物档案已转移",
+		logger.Int("application_id", int(application.ID)),
+		logger.Int("pet_id", int(listing.PetID)),
+		logger.Int("adopter_id", int(application.ApplicantID)),
+	)
+	return nil
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+var (
+	// ErrAppointmentSlotFull 所选时段已约满
+	ErrAppointmentSlotFull = errors.New("所选时段已约满,请选择其他时间")
+	// ErrPetAppointmentOverlap 宠物在该时段已有预约
+	ErrPetAppointmentOverlap = errors.New("该宠物在所选时段已有预约")
+	// ErrAppointmentStatusChanged 预约状态已变更
+	ErrAppointmentStatusChanged = errors.New("预约状态已变更,请刷新后重试")
+)
+
+// AppointmentRepository 诊所服务与门诊预约仓储接口
+type AppointmentRepository interface {
+	CreateService(ctx context.Context, item *model.ClinicServiceItem) error
+	// UpdateService 更新服务并整体替换各体型的时长与价格
+	UpdateService(ctx context.Context, item *model.ClinicServiceItem) error
+	DeleteService(ctx context.Context, id uint) error
+	GetService(ctx context.Context, id uint) (*model.ClinicServiceItem, error)
+	ListServices(ctx context.Context, clinicID uint, category string, onlineOnly bool) ([]*model.ClinicServiceItem, error)
+	// GetServicesByIDs 获取诊所内指定的服务,不含已删除的服务
+	GetServicesByIDs(ctx context.Context, clinicID uint, ids []uint) ([]*model.ClinicServiceItem, error)
+
+	// Create 创建预约,在锁定诊所的事务中校验时段容量和宠物的时间冲突
+	Create(ctx context.Context, appointment *model.Appointment, capacity int) error
+	GetByID(ctx context.Context, id uint) (*model.Appointment, error)
+	// ListActive 获取区间内占用时段的预约
+	ListActive(ctx context.Context, clinicID uint, from, to time.Time) ([]*model.Appointment, error)
+	ListByUser(ctx context.Context, userID uint, status string, offset, limit int) ([]*model.Appointment, int64, error)
+	ListByClinic(ctx context.Context, clinicID uint, status string, from, to *time.Time, offset, limit int) ([]*model.Appointment, int64, error)
+	// UpdateStatus 更新预约状态,仅当预约仍处于fromStatus时生效
+	UpdateStatus(ctx context.Context, appointment *model.Appointment, fromStatus string) error
+}
+
+// appointmentRepository 诊所服务与门诊预约仓储实现
+type appointmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAppointmentRepository 创建诊所服务与门诊预约仓储
+func NewAppointmentRepository(db *gorm.DB) AppointmentRepository {
+	return &appointmentRepository{db: db}
+}
+
+// CreateService 创建服务及价格
+func (r *appointmentRepository) CreateService(ctx context.Context, item *model.ClinicServiceItem) error {
+	if err := r.db.WithContext(ctx).Create(item).Error; err != nil {
+		logger.Error(ctx, "创建诊所服务失败", logger.Int("clinic_id", int(item.ClinicID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateService 更新服务及价格
+func (r *appointmentRepository) UpdateService(ctx context.Context, item *model.ClinicServiceItem) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(item).Select("category", "name", "description", "status").
+			Omit("Prices").Updates(item).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("service_id = ?", item.ID).Delete(&model.ClinicServicePrice{}).Error; err != nil {
+			return err
+		}
+		for _, price := range item.Prices {
+			price.ID = 0
+			price.ServiceID = item.ID
+		}
+		return tx.Create(&item.Prices).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "更新诊所服务失败", logger.Int("id", int(item.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteService 软删除服务,已有预约保留服务快照
+func (r *appointmentRepository) DeleteService(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Model(&model.ClinicServiceItem{}).Where("id = ?", id).Update("is_deleted", 1).Error; err != nil {
+		logger.Error(ctx, "删除诊所服务失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetService 根据ID获取服务
+func (r *appointmentRepository) GetService(ctx context.Context, id uint) (*model.ClinicServiceItem, error) {
+	var item model.ClinicServiceItem
+	err := r.db.WithContext(ctx).Preload("Prices", orderBySize).
+		Where("id = ? AND is_deleted = 0", id).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListServices 获取诊所的服务
+func (r *appointmentRepository) ListServices(ctx context.Context, clinicID uint, category string, onlineOnly bool) ([]*model.ClinicServiceItem, error) {
+	var items []*model.ClinicServiceItem
+	query := r.db.WithContext(ctx).Preload("Prices", orderBySize).
+		Where("clinic_id = ? AND is_deleted = 0", clinicID)
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if onlineOnly {
+		query = query.Where("status = ?", model.ClinicServiceOnline)
+	}
+	if err := query.Order("category ASC, id ASC").Find(&items).Error; err != nil {
+		logger.Error(ctx, "获取诊所服务失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetServicesByIDs 获取诊所内指定的服务
+func (r *appointmentRepository) GetServicesByIDs(ctx context.Context, clinicID uint, ids []uint) ([]*model.ClinicServiceItem, error) {
+	var items []*model.ClinicServiceItem
+	err := r.db.WithContext(ctx).Preload("Prices").
+		Where("clinic_id = ? AND id IN ? AND is_deleted = 0", clinicID, ids).
+		Find(&items).Error
+	if err != nil {
+		logger.Error(ctx, "获取诊所服务失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return items, nil
+}
+
+// Create 创建预约
+func (r *appointmentRepository) Create(ctx context.Context, appointment *model.Appointment, capacity int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定诊所行,串行化同一诊所的预约,避免并发超订
+		var clinic model.Clinic
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&clinic, appointment.ClinicID).Error; err != nil {
+			return err
+		}
+
+		overlap := tx.Model(&model.Appointment{}).
+			Where("status IN ? AND start_at < ? AND end_at > ?", model.ActiveAppointmentStatuses, appointment.EndAt, appointment.StartAt)
+		var petCount int64
+		if err := overlap.Session(&gorm.Session{}).Where("pet_id = ?", appointment.PetID).Count(&petCount).Error; err != nil {
+			return err
+		}
+		if petCount > 0 {
+			return ErrPetAppointmentOverlap
+		}
+
+		// 时段内任一时刻同时进行的预约数不超过容量:重叠预约按开始时间逐一检查峰值
+		var overlapping []*model.Appointment
+		err := overlap.Session(&gorm.Session{}).Where("clinic_id = ?", appointment.ClinicID).
+			Select("id", "start_at", "end_at").Find(&overlapping).Error
+		if err != nil {
+			return err
+		}
+		if model.PeakConcurrency(overlapping, appointment.StartAt, appointment.EndAt) >= capacity {
+			return ErrAppointmentSlotFull
+		}
+		return tx.Omit("Clinic").Create(appointment).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrAppointmentSlotFull) && !errors.Is(err, ErrPetAppointmentOverlap) {
+			logger.Error(ctx, "创建门诊预约失败", logger.Int("clinic_id", int(appointment.ClinicID)), logger.ErrorField(err))
+		}
+		return err
+	}
+	logger.Info(ctx, "创建门诊预约成功", logger.Int("id", int(appointment.ID)))
+	return nil
+}
+
+// GetByID 根据ID获取预约
+func (r *appointmentRepository) GetByID(ctx context.Context, id uint) (*model.Appointment, error) {
+	var appointment model.Appointment
+	err := r.db.WithContext(ctx).Preload("Items").Preload("Clinic").
+		Where("id = ?", id).First(&appointment).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取门诊预约失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// ListActive 获取区间内占用时段的预约
+func (r *appointmentRepository) ListActive(ctx context.Context, clinicID uint, from, to time.Time) ([]*model.Appointment, error) {
+	var appointments []*model.Appointment
+	err := r.db.WithContext(ctx).Select("id", "pet_id", "start_at", "end_at").
+		Where("clinic_id = ? AND status IN ? AND start_at < ? AND end_at > ?", clinicID, model.ActiveAppointmentStatuses, to, from).
+		Order("start_at ASC").Find(&appointments).Error
+	if err != nil {
+		logger.Error(ctx, "获取诊所预约占用失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return appointments, nil
+}
+
+// ListByUser 获取用户的预约,按开始时间倒序
+func (r *appointmentRepository) ListByUser(ctx context.Context, userID uint, status string, offset, limit int) ([]*model.Appointment, int64, error) {
+	return r.list(ctx, r.db.WithContext(ctx).Where("user_id = ?", userID), status, offset, limit)
+}
+
+// ListByClinic 获取诊所的预约,按开始时间倒序
+func (r *appointmentRepository) ListByClinic(ctx context.Context, clinicID uint, status string, from, to *time.Time, offset, limit int) ([]*model.Appointment, int64, error) {
+	query := r.db.WithContext(ctx).Where("clinic_id = ?", clinicID)
+	if from != nil {
+		query = query.Where("start_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("start_at < ?", *to)
+	}
+	return r.list(ctx, query, status, offset, limit)
+}
+
+func (r *appointmentRepository) list(ctx context.Context, query *gorm.DB, status string, offset, limit int) ([]*model.Appointment, int64, error) {
+	var appointments []*model.Appointment
+	var total int64
+
+	query = query.Model(&model.Appointment{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取门诊预约总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Items").Preload("Clinic").
+		Offset(offset).Limit(limit).
+		Order("start_at DESC, id DESC").
+		Find(&appointments).Error
+	if err != nil {
+		logger.Error(ctx, "获取门诊预约列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return appointments, total, nil
+}
+
+// UpdateStatus 更新预约状态
+func (r *appointmentRepository) UpdateStatus(ctx context.Context, appointment *model.Appointment, fromStatus string) error {
+	result := r.db.WithContext(ctx).Model(&model.Appointment{}).
+		Where("id = ? AND status = ?", appointment.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":       appointment.Status,
+			"status_note":  appointment.StatusNote,
+			"confirmed_at": appointment.ConfirmedAt,
+			"completed_at": appointment.CompletedAt,
+			"vet_id":       appointment.VetID,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新门诊预约状态失败", logger.Int("id", int(appointment.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAppointmentStatusChanged
+	}
+	return nil
+}
+
+// orderBySize 价格按体型从小到大排列
+func orderBySize(db *gorm.DB) *gorm.DB {
+	return db.Order("FIELD(size, 'small', 'medium', 'large')")
+}
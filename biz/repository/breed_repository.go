@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// BreedRepository 品种目录仓储接口
+type BreedRepository interface {
+	List(ctx context.Context, species, keyword string) ([]*model.Breed, error)
+	GetByName(ctx context.Context, species, name string) (*model.Breed, error)
+}
+
+// breedRepository 品种目录仓储实现
+type breedRepository struct {
+	db *gorm.DB
+}
+
+// NewBreedRepository 创建品种目录仓储
+func NewBreedRepository(db *gorm.DB) BreedRepository {
+	return &breedRepository{db: db}
+}
+
+// List 查询品种目录
+func (r *breedRepository) List(ctx context.Context, species, keyword string) ([]*model.Breed, error) {
+	var breeds []*model.Breed
+	query := r.db.WithContext(ctx).Model(&model.Breed{})
+	if species != "" {
+		query = query.Where("species = ?", species)
+	}
+	if keyword != "" {
+		query = query.Where("name LIKE ?", "%"+keyword+"%")
+	}
+	if err := query.Order("species ASC, name ASC").Find(&breeds).Error; err != nil {
+		logger.Error(ctx, "查询品种目录失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return breeds, nil
+}
+
+// GetByName 根据物种和名称获取品种
+func (r *breedRepository) GetByName(ctx context.Context, species, name string) (*model.Breed, error) {
+	var breed model.Breed
+	err := r.db.WithContext(ctx).Where("species = ? AND name = ?", species, name).First(&breed).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取品种失败", logger.String("name", name), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &breed, nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ChatRepository 会话消息仓储接口
+type ChatRepository interface {
+	GetOrCreateConversation(ctx context.Context, clinicID, ownerID uint) (*model.Conversation, error)
+	GetConversation(ctx context.Context, id uint) (*model.Conversation, error)
+	UpdateConversationPet(ctx context.Context, id, petID uint) error
+	ListOwnerConversations(ctx context.Context, ownerID uint, offset, limit int) ([]*model.Conversation, int64, error)
+	ListClinicConversations(ctx context.Context, clinicID uint, offset, limit int) ([]*model.Conversation, int64, error)
+
+	CreateMessage(ctx context.Context, message *model.ChatMessage, preview string) error
+	ListMessages(ctx context.Context, conversationID, beforeID uint, limit int) ([]*model.ChatMessage, error)
+	MarkRead(ctx context.Context, conversationID uint, side string, messageID uint) (bool, error)
+	SumOwnerUnread(ctx context.Context, ownerID uint) (int64, error)
+	SumClinicUnread(ctx context.Context, staffUserID uint) (int64, error)
+}
+
+// chatRepository 会话消息仓储实现
+type chatRepository struct {
+	db *gorm.DB
+}
+
+// NewChatRepository 创建会话消息仓储
+func NewChatRepository(db *gorm.DB) ChatRepository {
+	return &chatRepository{db: db}
+}
+
+// chatSideColumns 参与方对应的未读数和已读位置列
+func chatSideColumns(side string) (unread, readID string) {
+	if side == model.ChatSideClinic {
+		return "clinic_unread", "clinic_read_id"
+	}
+	return "owner_unread", "owner_read_id"
+}
+
+// GetOrCreateConversation 获取主人与诊所的会话,不存在时创建
+func (r *chatRepository) GetOrCreateConversation(ctx context.Context, clinicID, ownerID uint) (*model.Conversation, error) {
+	db := r.db.WithContext(ctx)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Conversation{ClinicID: clinicID, OwnerID: ownerID}).Error
+	if err != nil {
+		logger.Error(ctx, "创建会话失败",
+			logger.Int("clinic_id", int(clinicID)),
+			logger.Int("owner_id", int(ownerID)),
+			logger.ErrorField(err),
+		)
+		return nil, err
+	}
+
+	var conversation model.Conversation
+	if err := db.Where("clinic_id = ? AND owner_id = ?", clinicID, ownerID).First(&conversation).Error; err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// GetConversation 获取会话
+func (r *chatRepository) GetConversation(ctx context.Context, id uint) (*model.Conversation, error) {
+	var conversation model.Conversation
+	err := r.db.WithContext(ctx).
+		Preload("Clinic").
+		Preload("Owner", publicUserColumns).
+		Preload("Pet").
+		Where("id = ?", id).First(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// UpdateConversationPet 更新会话当前咨询的宠物
+func (r *chatRepository) UpdateConversationPet(ctx context.Context, id, petID uint) error {
+	err := r.db.WithContext(ctx).Model(&model.Conversation{}).Where("id = ?", id).Update("pet_id", petID).Error
+	if err != nil {
+		logger.Error(ctx, "更新会话宠物失败", logger.Int("conversation_id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// ListOwnerConversations 主人的会话列表,按最后消息时间倒序
+func (r *chatRepository) ListOwnerConversations(ctx context.Context, ownerID uint, offset, limit int) ([]*model.Conversation, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Conversation{}).Where("owner_id = ?", ownerID)
+	return r.listConversations(ctx, query.Preload("Clinic"), offset, limit)
+}
+
+// ListClinicConversations 诊所的会话列表,按最后消息时间倒序
+func (r *chatRepository) ListClinicConversations(ctx context.Context, clinicID uint, offset, limit int) ([]*model.Conversation, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Conversation{}).Where("clinic_id = ?", clinicID)
+	return r.listConversations(ctx, query.Preload("Owner", publicUserColumns), offset, limit)
+}
+
+func (r *chatRepository) listConversations(ctx context.Context, query *gorm.DB, offset, limit int) ([]*model.Conversation, int64, error) {
+	var conversations []*model.Conversation
+	var total int64
+
+	query = query.Where("last_message_id > 0")
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取会话总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Pet").Order("last_message_at DESC").Order("id DESC").
+		Offset(offset).Limit(limit).Find(&conversations).Error
+	if err != nil {
+		logger.Error(ctx, "获取会话列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return conversations, total, nil
+}
+
+// CreateMessage 保存消息并更新会话摘要,对方未读数加一,发送方视为已读到该消息
+func (r *chatRepository) CreateMessage(ctx context.Context, message *model.ChatMessage, preview string) error {
+	senderUnread, senderRead := chatSideColumns(message.SenderSide)
+	otherSide := model.ChatSideClinic
+	if message.SenderSide == model.ChatSideClinic {
+		otherSide = model.ChatSideOwner
+	}
+	otherUnread, _ := chatSideColumns(otherSide)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Conversation{}).Where("id = ?", message.ConversationID).Updates(map[string]interface{}{
+			"last_message_id":      message.ID,
+			"last_message_at":      message.CreatedAt,
+			"last_message_preview": preview,
+			otherUnread:            gorm.Expr(otherUnread + " + 1"),
+			senderUnread:           0,
+			senderRead:             message.ID,
+		}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "发送消息失败", logger.Int("conversation_id", int(message.ConversationID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// ListMessages 历史消息,按ID倒序返回beforeID之前的消息
+func (r *chatRepository) ListMessages(ctx context.Context, conversationID, beforeID uint, limit int) ([]*model.ChatMessage, error) {
+	var messages []*model.ChatMessage
+	query := r.db.WithContext(ctx).Preload("Sender", publicUserColumns).Where("conversation_id = ?", conversationID)
+	if err := cursorPage(query, "id", beforeID, limit).Find(&messages).Error; err != nil {
+		logger.Error(ctx, "获取历史消息失败", logger.Int("conversation_id", int(conversationID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return messages, nil
+}
+
+// MarkRead 将参与方的已读位置推进到messageID并重新计算未读数,已读位置不回退
+func (r *chatRepository) MarkRead(ctx context.Context, conversationID uint, side string, messageID uint) (bool, error) {
+	unreadColumn, readColumn := chatSideColumns(side)
+	result := r.db.WithContext(ctx).Model(&model.Conversation{}).
+		Where("id = ? AND "+readColumn+" < ?", conversationID, messageID).
+		Updates(map[string]interface{}{
+			readColumn: messageID,
+			unreadColumn: gorm.Expr("(SELECT COUNT(*) FROM chat_messages WHERE conversation_id = ? AND sender_side <> ? AND id > ?)",
+				conversationID, side, messageID),
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "标记已读失败", logger.Int("conversation_id", int(conversationID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SumOwnerUnread 用户作为主人的未读消息总数
+func (r *chatRepository) SumOwnerUnread(ctx context.Context, ownerID uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&model.Conversation{}).
+		Where("owner_id = ?", ownerID).Select("COALESCE(SUM(owner_unread), 0)").Scan(&total).Error
+	if err != nil {
+		logger.Error(ctx, "统计未读消息失败", logger.Int("owner_id", int(ownerID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return total, nil
+}
+
+// SumClinicUnread 用户任职诊所的未读消息总数
+func (r *chatRepository) SumClinicUnread(ctx context.Context, staffUserID uint) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&model.Conversation{}).
+		Where("clinic_id IN (?)", r.db.Model(&model.ClinicStaff{}).Select("clinic_id").Where("user_id = ?", staffUserID)).
+		Select("COALESCE(SUM(clinic_unread), 0)").Scan(&total).Error
+	if err != nil {
+		logger.Error(ctx, "统计诊所未读消息失败", logger.Int("user_id", int(staffUserID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return total, nil
+}
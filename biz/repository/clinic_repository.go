@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ClinicRepository 诊所仓储接口
+type ClinicRepository interface {
+	Create(ctx context.Context, clinic *model.Clinic, managerUserID uint) error
+	Update(ctx context.Context, clinic *model.Clinic) error
+	GetByID(ctx context.Context, id uint) (*model.Clinic, error)
+	List(ctx context.Context, city, keyword string, onlyOpen bool, offset, limit int) ([]*model.Clinic, int64, error)
+
+	GetStaff(ctx context.Context, clinicID, userID uint) (*model.ClinicStaff, error)
+	SaveStaff(ctx context.Context, staff *model.ClinicStaff) error
+	RemoveStaff(ctx context.Context, clinicID, userID uint) (bool, error)
+	ListStaff(ctx context.Context, clinicID uint) ([]*model.ClinicStaff, error)
+	ListStaffUserIDs(ctx context.Context, clinicID uint) ([]uint, error)
+	CountManagers(ctx context.Context, clinicID uint) (int64, error)
+	ListUserClinics(ctx context.Context, userID uint) ([]*model.ClinicStaff, error)
+}
+
+// clinicRepository 诊所仓储实现
+type clinicRepository struct {
+	db *gorm.DB
+}
+
+// NewClinicRepository 创建诊所仓储
+func NewClinicRepository(db *gorm.DB) ClinicRepository {
+	return &clinicRepository{db: db}
+}
+
+// Create 创建诊所并设置首位管理员
+func (r *clinicRepository) Create(ctx context.Context, clinic *model.Clinic, managerUserID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(clinic).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.ClinicStaff{
+			ClinicID: clinic.ID,
+			UserID:   managerUserID,
+			Role:     model.ClinicRoleManager,
+		}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "创建诊所失败", logger.String("name", clinic.Name), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建诊所成功", logger.Int("id", int(clinic.ID)), logger.Int("manager_user_id", int(managerUserID)))
+	return nil
+}
+
+// Update 更新诊所信息
+func (r *clinicRepository) Update(ctx context.Context, clinic *model.Clinic) error {
+	err := r.db.WithContext(ctx).Model(&model.Clinic{ID: clinic.ID}).
+		Select("name", "phone", "city", "address", "latitude", "longitude", "description", "opening_hours", "status").
+		Updates(clinic).Error
+	if err != nil {
+		logger.Error(ctx, "更新诊所失败", logger.Int("id", int(clinic.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 获取诊所
+func (r *clinicRepository) GetByID(ctx context.Context, id uint) (*model.Clinic, error) {
+	var clinic model.Clinic
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&clinic).Error; err != nil {
+		return nil, err
+	}
+	return &clinic, nil
+}
+
+// List 检索诊所
+func (r *clinicRepository) List(ctx context.Context, city, keyword string, onlyOpen bool, offset, limit int) ([]*model.Clinic, int64, error) {
+	var clinics []*model.Clinic
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Clinic{})
+	if onlyOpen {
+		query = query.Where("status = ?", 1)
+	}
+	if city != "" {
+		query = query.Where("city = ?", city)
+	}
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR address LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取诊所总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("id DESC").Find(&clinics).Error; err != nil {
+		logger.Error(ctx, "获取诊所列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return clinics, total, nil
+}
+
+// GetStaff 获取用户在诊所的人员记录
+func (r *clinicRepository) GetStaff(ctx context.Context, clinicID, userID uint) (*model.ClinicStaff, error) {
+	var staff model.ClinicStaff
+	if err := r.db.WithContext(ctx).Where("clinic_id = ? AND user_id = ?", clinicID, userID).First(&staff).Error; err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+// SaveStaff 添加人员,已存在时更新角色和职称
+func (r *clinicRepository) SaveStaff(ctx context.Context, staff *model.ClinicStaff) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "clinic_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "title", "updated_at"}),
+	}).Create(staff).Error
+	if err != nil {
+		logger.Error(ctx, "保存诊所人员失败",
+			logger.Int("clinic_id", int(staff.ClinicID)),
+			logger.Int("user_id", int(staff.UserID)),
+			logger.ErrorField(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// RemoveStaff 移除人员
+func (r *clinicRepository) RemoveStaff(ctx context.Context, clinicID, userID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("clinic_id = ? AND user_id = ?", clinicID, userID).Delete(&model.ClinicStaff{})
+	if result.Error != nil {
+		logger.Error(ctx, "移除诊所人员失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListStaff 诊所人员列表
+func (r *clinicRepository) ListStaff(ctx context.Context, clinicID uint) ([]*model.ClinicStaff, error) {
+	var staff []*model.ClinicStaff
+	err := r.db.WithContext(ctx).Preload("User", publicUserColumns).
+		Where("clinic_id = ?", clinicID).Order("id ASC").Find(&staff).Error
+	if err != nil {
+		logger.Error(ctx, "获取诊所人员失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return staff, nil
+}
+
+// ListStaffUserIDs 诊所全部人员的用户ID
+func (r *clinicRepository) ListStaffUserIDs(ctx context.Context, clinicID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.ClinicStaff{}).Where("clinic_id = ?", clinicID).Pluck("user_id", &ids).Error
+	if err != nil {
+		logger.Error(ctx, "获取诊所人员ID失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CountManagers 诊所管理员人数
+func (r *clinicRepository) CountManagers(ctx context.Context, clinicID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ClinicStaff{}).
+		Where("clinic_id = ? AND role = ?", clinicID, model.ClinicRoleManager).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "统计诊所管理员失败", logger.Int("clinic_id", int(clinicID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListUserClinics 用户任职的诊所
+func (r *clinicRepository) ListUserClinics(ctx context.Context, userID uint) ([]*model.ClinicStaff, error) {
+	var staff []*model.ClinicStaff
+	err := r.db.WithContext(ctx).Preload("Clinic").Where("user_id = ?", userID).Order("id ASC").Find(&staff).Error
+	if err != nil {
+		logger.Error(ctx, "获取任职诊所失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return staff, nil
+}
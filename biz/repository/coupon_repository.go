@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// CouponRepository 优惠券仓储接口
+type CouponRepository interface {
+	Create(ctx context.Context, coupon *model.Coupon) error
+	Update(ctx context.Context, coupon *model.Coupon) error
+	GetByID(ctx context.Context, id uint) (*model.Coupon, error)
+	GetByCode(ctx context.Context, code string) (*model.Coupon, error)
+	CodeExists(ctx context.Context, code string, excludeID uint) (bool, error)
+	List(ctx context.Context, status, keyword string, offset, limit int) ([]*model.Coupon, int64, error)
+	CountUserRedemptions(ctx context.Context, couponID, userID uint) (int64, error)
+}
+
+// couponRepository 优惠券仓储实现
+type couponRepository struct {
+	db *gorm.DB
+}
+
+// NewCouponRepository 创建优惠券仓储
+func NewCouponRepository(db *gorm.DB) CouponRepository {
+	return &couponRepository{db: db}
+}
+
+// Create 创建优惠券
+func (r *couponRepository) Create(ctx context.Context, coupon *model.Coupon) error {
+	if err := r.db.WithContext(ctx).Create(coupon).Error; err != nil {
+		logger.Error(ctx, "创建优惠券失败", logger.String("code", coupon.Code), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建优惠券成功", logger.Int("id", int(coupon.ID)), logger.String("code", coupon.Code))
+	return nil
+}
+
+// Update 更新优惠券,已使用次数不在此处修改
+func (r *couponRepository) Update(ctx context.Context, coupon *model.Coupon) error {
+	err := r.db.WithContext(ctx).Model(&model.Coupon{ID: coupon.ID}).
+		Select("code", "name", "type", "value", "max_discount", "min_spend", "order_types",
+			"starts_at", "ends_at", "total_limit", "per_user_limit", "status").
+		Updates(coupon).Error
+	if err != nil {
+		logger.Error(ctx, "更新优惠券失败", logger.Int("id", int(coupon.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 获取优惠券
+func (r *couponRepository) GetByID(ctx context.Context, id uint) (*model.Coupon, error) {
+	var coupon model.Coupon
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// GetByCode 根据券码获取优惠券
+func (r *couponRepository) GetByCode(ctx context.Context, code string) (*model.Coupon, error) {
+	var coupon model.Coupon
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// CodeExists 判断券码是否已被其他优惠券使用
+func (r *couponRepository) CodeExists(ctx context.Context, code string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Coupon{}).Where("code = ? AND id <> ?", code, excludeID).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "检查券码失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// List 优惠券列表
+func (r *couponRepository) List(ctx context.Context, status, keyword string, offset, limit int) ([]*model.Coupon, int64, error) {
+	var coupons []*model.Coupon
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Coupon{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if keyword != "" {
+		query = query.Where("code LIKE ? OR name LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取优惠券总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("id DESC").Find(&coupons).Error; err != nil {
+		logger.Error(ctx, "获取优惠券列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return coupons, total, nil
+}
+
+// CountUserRedemptions 用户已使用该券的次数(不含已退回的)
+func (r *couponRepository) CountUserRedemptions(ctx context.Context, couponID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CouponRedemption{}).
+		Where("coupon_id = ? AND user_id = ? AND status = ?", couponID, userID, model.RedemptionStatusUsed).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "统计优惠券使用次数失败", logger.ErrorField(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// redeemCoupon 在下单事务中核销优惠券
+// 锁定优惠券行后校验有效期、总次数和每人次数,同一张券的并发核销串行执行,不会超发
+func redeemCoupon(tx *gorm.DB, couponID, userID, orderID uint, discount int64) error {
+	var coupon model.Coupon
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", couponID).First(&coupon).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	if coupon.Status != model.CouponStatusActive || now.Before(coupon.StartsAt) || !now.Before(coupon.EndsAt) {
+		return errors.New("优惠券不在有效期内")
+	}
+	if coupon.TotalLimit > 0 && coupon.UsedCount >= coupon.TotalLimit {
+		return errors.New("优惠券已被领完")
+	}
+	if coupon.PerUserLimit > 0 {
+		var used int64
+		err := tx.Model(&model.CouponRedemption{}).
+			Where("coupon_id = ? AND user_id = ? AND status = ?", couponID, userID, model.RedemptionStatusUsed).
+			Count(&used).Error
+		if err != nil {
+			return err
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return errors.New("已达到该优惠券的使用次数上限")
+		}
+	}
+
+	redemption := &model.CouponRedemption{
+		CouponID: couponID,
+		UserID:   userID,
+		OrderID:  orderID,
+		Discount: discount,
+		Status:   model.RedemptionStatusUsed,
+	}
+	if err := tx.Create(redemption).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.Coupon{}).Where("id = ?", couponID).
+		Update("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+// releaseCoupon 订单取消时退回优惠券使用次数
+func releaseCoupon(tx *gorm.DB, orderID uint) error {
+	var redemption model.CouponRedemption
+	err := tx.Where("order_id = ? AND status = ?", orderID, model.RedemptionStatusUsed).First(&redemption).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	result := tx.Model(&model.CouponRedemption{}).
+		Where("id = ? AND status = ?", redemption.ID, model.RedemptionStatusUsed).
+		Update("status", model.RedemptionStatusReleased)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return tx.Model(&model.Coupon{}).Where("id = ? AND used_count > 0", redemption.CouponID).
+		Update("used_count", gorm.Expr("used_count - 1")).Error
+}
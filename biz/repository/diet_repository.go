@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ErrDietPlanStartDate 新计划的开始日期不晚于已有计划
+var ErrDietPlanStartDate = errors.New("新饮食计划的开始日期必须晚于当前最新计划的开始日期")
+
+// DietRepository 饮食计划仓储接口
+type DietRepository interface {
+	// CreatePlan 创建饮食计划,并将进行中的上一个计划结束于新计划开始的前一天
+	CreatePlan(ctx context.Context, plan *model.DietPlan) error
+	UpdatePlan(ctx context.Context, plan *model.DietPlan) error
+	DeletePlan(ctx context.Context, id uint) error
+	GetPlan(ctx context.Context, id uint) (*model.DietPlan, error)
+	// GetPlanForDate 获取某天生效的饮食计划
+	GetPlanForDate(ctx context.Context, petID uint, date time.Time) (*model.DietPlan, error)
+	ListPlans(ctx context.Context, petID uint, offset, limit int) ([]*model.DietPlan, int64, error)
+
+	CreateLog(ctx context.Context, log *model.FeedingLog) error
+	DeleteLog(ctx context.Context, id uint) error
+	GetLog(ctx context.Context, id uint) (*model.FeedingLog, error)
+	// ListLogs 分页获取区间[from, to)内的喂食记录,from/to为空表示不限
+	ListLogs(ctx context.Context, petID uint, from, to *time.Time, offset, limit int) ([]*model.FeedingLog, int64, error)
+	// ListLogsRange 获取区间[from, to)内的全部喂食记录,按时间正序
+	ListLogsRange(ctx context.Context, petID uint, from, to time.Time) ([]*model.FeedingLog, error)
+}
+
+// dietRepository 饮食计划仓储实现
+type dietRepository struct {
+	db *gorm.DB
+}
+
+// NewDietRepository 创建饮食计划仓储
+func NewDietRepository(db *gorm.DB) DietRepository {
+	return &dietRepository{db: db}
+}
+
+// CreatePlan 创建饮食计划
+func (r *dietRepository) CreatePlan(ctx context.Context, plan *model.DietPlan) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest model.DietPlan
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("pet_id = ? AND is_deleted = 0", plan.PetID).
+			Order("start_date DESC").First(&latest).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+		case err != nil:
+			return err
+		case !latest.StartDate.Before(plan.StartDate):
+			return ErrDietPlanStartDate
+		case latest.EndDate == nil || !latest.EndDate.Before(plan.StartDate):
+			end := plan.StartDate.AddDate(0, 0, -1)
+			if err := tx.Model(&latest).Update("end_date", end).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(plan).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrDietPlanStartDate) {
+			logger.Error(ctx, "创建饮食计划失败", logger.Int("pet_id", int(plan.PetID)), logger.ErrorField(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// UpdatePlan 更新饮食计划,开始和结束日期不可修改
+func (r *dietRepository) UpdatePlan(ctx context.Context, plan *model.DietPlan) error {
+	err := r.db.WithContext(ctx).Model(plan).
+		Select("product_id", "food_name", "kcal_per_kg", "portion_grams", "meals_per_day", "meal_times",
+			"activity_level", "neutered", "target_kcal", "notes").
+		Updates(plan).Error
+	if err != nil {
+		logger.Error(ctx, "更新饮食计划失败", logger.Int("id", int(plan.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// DeletePlan 软删除饮食计划
+func (r *dietRepository) DeletePlan(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Model(&model.DietPlan{}).Where("id = ?", id).Update("is_deleted", 1).Error; err != nil {
+		logger.Error(ctx, "删除饮食计划失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetPlan 根据ID获取饮食计划
+func (r *dietRepository) GetPlan(ctx context.Context, id uint) (*model.DietPlan, error) {
+	var plan model.DietPlan
+	if err := r.db.WithContext(ctx).Where("id = ? AND is_deleted = 0", id).First(&plan).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// GetPlanForDate 获取某天生效的饮食计划
+func (r *dietRepository) GetPlanForDate(ctx context.Context, petID uint, date time.Time) (*model.DietPlan, error) {
+	var plan model.DietPlan
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND is_deleted = 0 AND start_date <= ? AND (end_date IS NULL OR end_date >= ?)", petID, date, date).
+		Order("start_date DESC").First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ListPlans 分页获取宠物的饮食计划,按开始日期倒序
+func (r *dietRepository) ListPlans(ctx context.Context, petID uint, offset, limit int) ([]*model.DietPlan, int64, error) {
+	var plans []*model.DietPlan
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.DietPlan{}).Where("pet_id = ? AND is_deleted = 0", petID)
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取饮食计划总数失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("start_date DESC").Find(&plans).Error; err != nil {
+		logger.Error(ctx, "获取饮食计划失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return plans, total, nil
+}
+
+// CreateLog 创建喂食记录
+func (r *dietRepository) CreateLog(ctx context.Context, log *model.FeedingLog) error {
+	if err := r.db.WithContext(ctx).Create(log).Error; err != nil {
+		logger.Error(ctx, "创建喂食记录失败", logger.Int("pet_id", int(log.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteLog 删除喂食记录
+func (r *dietRepository) DeleteLog(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Delete(&model.FeedingLog{}, id).Error; err != nil {
+		logger.Error(ctx, "删除喂食记录失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetLog 根据ID获取喂食记录
+func (r *dietRepository) GetLog(ctx context.Context, id uint) (*model.FeedingLog, error) {
+	var log model.FeedingLog
+	if err := r.db.WithContext(ctx).First(&log, id).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+// ListLogs 分页获取喂食记录,按喂食时间倒序
+func (r *dietRepository) ListLogs(ctx context.Context, petID uint, from, to *time.Time, offset, limit int) ([]*model.FeedingLog, int64, error) {
+	var logs []*model.FeedingLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.FeedingLog{}).Where("pet_id = ?", petID)
+	if from != nil {
+		query = query.Where("fed_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("fed_at < ?", *to)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取喂食记录总数失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("fed_at DESC, id DESC").Find(&logs).Error; err != nil {
+		logger.Error(ctx, "获取喂食记录失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// ListLogsRange 获取区间内的全部喂食记录
+func (r *dietRepository) ListLogsRange(ctx context.Context, petID uint, from, to time.Time) ([]*model.FeedingLog, error) {
+	var logs []*model.FeedingLog
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND fed_at >= ? AND fed_at < ?", petID, from, to).
+		Order("fed_at ASC, id ASC").Find(&logs).Error
+	if err != nil {
+		logger.Error(ctx, "获取喂食记录失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// DocumentRepository 签发文档仓储接口
+type DocumentRepository interface {
+	Create(ctx context.Context, doc *model.IssuedDocument) error
+	GetByCode(ctx context.Context, code string) (*model.IssuedDocument, error)
+	GetLatest(ctx context.Context, kind string, subjectID uint) (*model.IssuedDocument, error)
+}
+
+// documentRepository 签发文档仓储实现
+type documentRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentRepository 创建签发文档仓储
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+// Create 记录签发的文档
+func (r *documentRepository) Create(ctx context.Context, doc *model.IssuedDocument) error {
+	if err := r.db.WithContext(ctx).Create(doc).Error; err != nil {
+		logger.Error(ctx, "记录签发文档失败", logger.String("kind", doc.Kind), logger.Int("subject_id", int(doc.SubjectID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByCode 根据验证码获取文档
+func (r *documentRepository) GetByCode(ctx context.Context, code string) (*model.IssuedDocument, error) {
+	var doc model.IssuedDocument
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&doc).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// GetLatest 获取对象最近一次签发的文档
+func (r *documentRepository) GetLatest(ctx context.Context, kind string, subjectID uint) (*model.IssuedDocument, error) {
+	var doc model.IssuedDocument
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND subject_id = ?", kind, subjectID).
+		Order("id DESC").
+		First(&doc).Error
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
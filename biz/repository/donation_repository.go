@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// DonationFilter 捐款导出筛选条件,时间区间按到账时间左闭右开
+type DonationFilter struct {
+	ShelterID  uint
+	CampaignID uint
+	From       *time.Time
+	To         *time.Time
+}
+
+// DonationRepository 募捐活动与捐款仓储接口
+type DonationRepository interface {
+	CreateCampaign(ctx context.Context, campaign *model.DonationCampaign) error
+	UpdateCampaign(ctx context.Context, campaign *model.DonationCampaign) error
+	// CloseCampaign 提前结束进行中的活动
+	CloseCampaign(ctx context.Context, id uint, closedAt time.Time) error
+	GetCampaign(ctx context.Context, id uint) (*model.DonationCampaign, error)
+	// ListCampaigns 募捐活动列表,ended为false时只返回进行中且未截止的活动
+	ListCampaigns(ctx context.Context, shelterID uint, ended bool, now time.Time, offset, limit int) ([]*model.DonationCampaign, int64, error)
+
+	CreateDonation(ctx context.Context, donation *model.Donation) error
+	GetDonation(ctx context.Context, id uint) (*model.Donation, error)
+	// MarkPaid 捐款到账并累加活动总额,重复通知时返回false
+	MarkPaid(ctx context.Context, donation *model.Donation, orderID uint, paidAt time.Time) (bool, error)
+	ListPaidByCampaign(ctx context.Context, campaignID uint, offset, limit int) ([]*model.Donation, int64, error)
+	ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*model.Donation, int64, error)
+	// ListPaid 获取已到账的捐款用于导出,按到账时间排序
+	ListPaid(ctx context.Context, filter *DonationFilter) ([]*model.Donation, error)
+}
+
+// donationRepository 募捐活动与捐款仓储实现
+type donationRepository struct {
+	db *gorm.DB
+}
+
+// NewDonationRepository 创建募捐活动与捐款仓储
+func NewDonationRepository(db *gorm.DB) DonationRepository {
+	return &donationRepository{db: db}
+}
+
+// CreateCampaign 创建募捐活动
+func (r *donationRepository) CreateCampaign(ctx context.Context, campaign *model.DonationCampaign) error {
+	if err := r.db.WithContext(ctx).Omit("Shelter").Create(campaign).Error; err != nil {
+		logger.Error(ctx, "创建募捐活动失败", logger.Int("shelter_id", int(campaign.ShelterID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建募捐活动成功", logger.Int("id", int(campaign.ID)))
+	return nil
+}
+
+// UpdateCampaign 更新募捐活动信息,不覆盖已筹金额和捐款笔数
+func (r *donationRepository) UpdateCampaign(ctx context.Context, campaign *model.DonationCampaign) error {
+	err := r.db.WithContext(ctx).Model(campaign).
+		Select("title", "description", "goal_amount", "deadline").
+		Updates(campaign).Error
+	if err != nil {
+		logger.Error(ctx, "更新募捐活动失败", logger.Int("id", int(campaign.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// CloseCampaign 提前结束募捐活动
+func (r *donationRepository) CloseCampaign(ctx context.Context, id uint, closedAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&model.DonationCampaign{}).
+		Where("id = ? AND status = ?", id, model.CampaignStatusActive).
+		Updates(map[string]interface{}{
+			"status":    model.CampaignStatusClosed,
+			"closed_at": closedAt,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "结束募捐活动失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("活动已结束")
+	}
+	return nil
+}
+
+// GetCampaign 根据ID获取募捐活动
+func (r *donationRepository) GetCampaign(ctx context.Context, id uint) (*model.DonationCampaign, error) {
+	var campaign model.DonationCampaign
+	err := r.db.WithContext(ctx).Preload("Shelter").Where("id = ?", id).First(&campaign).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取募捐活动失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// ListCampaigns 募捐活动列表
+func (r *donationRepository) ListCampaigns(ctx context.Context, shelterID uint, ended bool, now time.Time, offset, limit int) ([]*model.DonationCampaign, int64, error) {
+	var campaigns []*model.DonationCampaign
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.DonationCampaign{})
+	if shelterID > 0 {
+		query = query.Where("shelter_id = ?", shelterID)
+	}
+	order := "deadline ASC, id ASC"
+	if ended {
+		query = query.Where("status = ? OR deadline <= ?", model.CampaignStatusClosed, now)
+		order = "deadline DESC, id DESC"
+	} else {
+		query = query.Where("status = ? AND deadline > ?", model.CampaignStatusActive, now)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取募捐活动总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Shelter").Offset(offset).Limit(limit).Order(order).Find(&campaigns).Error
+	if err != nil {
+		logger.Error(ctx, "获取募捐活动列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return campaigns, total, nil
+}
+
+// CreateDonation 创建待支付的捐款
+func (r *donationRepository) CreateDonation(ctx context.Context, donation *model.Donation) error {
+	if err := r.db.WithContext(ctx).Omit("Campaign").Create(donation).Error; err != nil {
+		logger.Error(ctx, "创建捐款失败", logger.Int("campaign_id", int(donation.CampaignID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetDonation 根据ID获取捐款
+func (r *donationRepository) GetDonation(ctx context.Context, id uint) (*model.Donation, error) {
+	var donation model.Donation
+	err := r.db.WithContext(ctx).Preload("Campaign.Shelter").Where("id = ?", id).First(&donation).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取捐款失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &donation, nil
+}
+
+// MarkPaid 捐款到账,在同一事务中累加活动的已筹金额和捐款笔数
+func (r *donationRepository) MarkPaid(ctx context.Context, donation *model.Donation, orderID uint, paidAt time.Time) (bool, error) {
+	changed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Donation{}).
+			Where("id = ? AND status = ?", donation.ID, model.DonationStatusPending).
+			Updates(map[string]interface{}{
+				"status":   model.DonationStatusPaid,
+				"order_id": orderID,
+				"paid_at":  paidAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		changed = true
+		return tx.Model(&model.DonationCampaign{}).Where("id = ?", donation.CampaignID).
+			Updates(map[string]interface{}{
+				"raised_amount":  gorm.Expr("raised_amount + ?", donation.Amount),
+				"donation_count": gorm.Expr("donation_count + 1"),
+			}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "捐款到账失败", logger.Int("id", int(donation.ID)), logger.ErrorField(err))
+		return false, err
+	}
+	if changed {
+		donation.Status = model.DonationStatusPaid
+		donation.OrderID = orderID
+		donation.PaidAt = &paidAt
+		logger.Info(ctx, "捐款到账", logger.Int("id", int(donation.ID)), logger.Int64("amount", donation.Amount))
+	}
+	return changed, nil
+}
+
+// ListPaidByCampaign 活动已到账的捐款,按到账时间倒序
+func (r *donationRepository) ListPaidByCampaign(ctx context.Context, campaignID uint, offset, limit int) ([]*model.Donation, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Donation{}).
+		Where("campaign_id = ? AND status = ?", campaignID, model.DonationStatusPaid)
+	return r.list(ctx, query, "paid_at DESC, id DESC", offset, limit)
+}
+
+// ListByUser 用户的捐款,按创建时间倒序
+func (r *donationRepository) ListByUser(ctx context.Context, userID uint, offset, limit int) ([]*model.Donation, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Donation{}).Preload("Campaign.Shelter").
+		Where("user_id = ?", userID)
+	return r.list(ctx, query, "id DESC", offset, limit)
+}
+
+func (r *donationRepository) list(ctx context.Context, query *gorm.DB, order string, offset, limit int) ([]*model.Donation, int64, error) {
+	var donations []*model.Donation
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取捐款总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order(order).Find(&donations).Error; err != nil {
+		logger.Error(ctx, "获取捐款列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return donations, total, nil
+}
+
+// ListPaid 获取已到账的捐款
+func (r *donationRepository) ListPaid(ctx context.Context, filter *DonationFilter) ([]*model.Donation, error) {
+	var donations []*model.Donation
+	query := r.db.WithContext(ctx).Preload("Campaign").
+		Where("shelter_id = ? AND status = ?", filter.ShelterID, model.DonationStatusPaid)
+	if filter.CampaignID > 0 {
+		query = query.Where("campaign_id = ?", filter.CampaignID)
+	}
+	if filter.From != nil {
+		query = query.Where("paid_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("paid_at < ?", *filter.To)
+	}
+	if err := query.Order("paid_at ASC, id ASC").Find(&donations).Error; err != nil {
+		logger.Error(ctx, "导出捐款失败", logger.Int("shelter_id", int(filter.ShelterID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return donations, nil
+}
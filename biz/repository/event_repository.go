@@ -0,0 +1,397 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// EventRepository 社区活动仓储接口
+type EventRepository interface {
+	Create(ctx context.Context, event *model.Event) error
+	Update(ctx context.Context, event *model.Event) ([]*model.EventRSVP, error)
+	Cancel(ctx context.Context, id uint) (bool, error)
+	GetByID(ctx context.Context, id uint) (*model.Event, error)
+	List(ctx context.Context, city, species, keyword string, after time.Time, offset, limit int) ([]*model.Event, int64, error)
+	ListByOrganizer(ctx context.Context, organizerID uint, offset, limit int) ([]*model.Event, int64, error)
+
+	RSVP(ctx context.Context, rsvp *model.EventRSVP) error
+	CancelRSVP(ctx context.Context, eventID, userID uint) ([]*model.EventRSVP, error)
+	GetRSVP(ctx context.Context, eventID, userID uint) (*model.EventRSVP, error)
+	WaitlistPosition(ctx context.Context, rsvp *model.EventRSVP) (int, error)
+	ListRSVPs(ctx context.Context, eventID uint, status string, offset, limit int) ([]*model.EventRSVP, int64, error)
+	ListUserRSVPs(ctx context.Context, userID uint, offset, limit int) ([]*model.EventRSVP, int64, error)
+	ListAttendeeIDs(ctx context.Context, eventID uint, statuses ...string) ([]uint, error)
+
+	ListDueReminders(ctx context.Context, now time.Time, limit int) ([]*model.Event, error)
+	ClaimReminder(ctx context.Context, eventID uint, at time.Time) (bool, error)
+}
+
+// eventRepository 社区活动仓储实现
+type eventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository 创建社区活动仓储
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{db: db}
+}
+
+// Create 创建活动
+func (r *eventRepository) Create(ctx context.Context, event *model.Event) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		logger.Error(ctx, "创建活动失败", logger.String("title", event.Title), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Update 更新活动信息,名额不能少于已报名人数,名额增加时按候补顺序递补,返回递补成功的报名
+func (r *eventRepository) Update(ctx context.Context, event *model.Event) ([]*model.EventRSVP, error) {
+	var promoted []*model.EventRSVP
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := lockEvent(tx, event.ID)
+		if err != nil {
+			return err
+		}
+		if current.Status != model.EventStatusPublished {
+			return errors.New("活动已取消")
+		}
+		if event.Capacity < current.AttendeeCount {
+			return errors.New("名额不能少于已报名人数")
+		}
+
+		err = tx.Model(&model.Event{}).Where("id = ?", event.ID).
+			Select("title", "description", "city", "location", "latitude", "longitude",
+				"start_at", "end_at", "capacity", "allowed_species", "remind_before_min", "reminded_at").
+			Updates(event).Error
+		if err != nil {
+			return err
+		}
+		current.Capacity = event.Capacity
+		current.StartAt = event.StartAt
+		promoted, err = promoteWaitlist(tx, current)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "更新活动失败", logger.Int("id", int(event.ID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// Cancel 取消活动,已取消时返回false
+func (r *eventRepository) Cancel(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Event{}).
+		Where("id = ? AND status = ?", id, model.EventStatusPublished).
+		Update("status", model.EventStatusCancelled)
+	if result.Error != nil {
+		logger.Error(ctx, "取消活动失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByID 根据ID获取活动
+func (r *eventRepository) GetByID(ctx context.Context, id uint) (*model.Event, error) {
+	var event model.Event
+	if err := r.db.WithContext(ctx).Preload("Organizer", publicUserColumns).First(&event, id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// List 检索报名中且未结束的活动,按开始时间升序
+func (r *eventRepository) List(ctx context.Context, city, species, keyword string, after time.Time, offset, limit int) ([]*model.Event, int64, error) {
+	var events []*model.Event
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Event{}).
+		Where("status = ? AND end_at > ?", model.EventStatusPublished, after)
+	if city != "" {
+		query = query.Where("city = ?", city)
+	}
+	if species != "" {
+		query = query.Where("allowed_species IS NULL OR JSON_LENGTH(allowed_species) = 0 OR JSON_CONTAINS(allowed_species, JSON_QUOTE(?))", species)
+	}
+	if keyword != "" {
+		query = query.Where("title LIKE ? OR location LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取活动总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Organizer", publicUserColumns).
+		Offset(offset).Limit(limit).Order("start_at ASC, id ASC").Find(&events).Error
+	if err != nil {
+		logger.Error(ctx, "获取活动列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// ListByOrganizer 获取用户组织的活动,按开始时间倒序
+func (r *eventRepository) ListByOrganizer(ctx context.Context, organizerID uint, offset, limit int) ([]*model.Event, int64, error) {
+	var events []*model.Event
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Event{}).Where("organizer_id = ?", organizerID)
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取组织的活动总数失败", logger.Int("organizer_id", int(organizerID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("start_at DESC").Find(&events).Error; err != nil {
+		logger.Error(ctx, "获取组织的活动失败", logger.Int("organizer_id", int(organizerID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// RSVP 报名活动
+// 锁定活动行后判断名额,有空位且无人候补时直接报名成功,否则进入候补,同一活动的并发报名串行执行,不会超额
+// 已报名或候补中时只更新携带的宠物和备注
+func (r *eventRepository) RSVP(ctx context.Context, rsvp *model.EventRSVP) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, rsvp.EventID)
+		if err != nil {
+			return err
+		}
+		if event.Status != model.EventStatusPublished {
+			return errors.New("活动已取消")
+		}
+		if !event.StartAt.After(time.Now()) {
+			return errors.New("活动已开始,无法报名")
+		}
+
+		var existing model.EventRSVP
+		err = tx.Where("event_id = ? AND user_id = ?", rsvp.EventID, rsvp.UserID).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && existing.Status != model.RSVPStatusCancelled {
+			existing.PetIDs = rsvp.PetIDs
+			existing.Note = rsvp.Note
+			*rsvp = existing
+			return tx.Model(&existing).Select("pet_ids", "note").Updates(&existing).Error
+		}
+
+		rsvp.Status = model.RSVPStatusWaitlisted
+		counter := "waitlist_count"
+		if event.AttendeeCount < event.Capacity && event.WaitlistCount == 0 {
+			rsvp.Status = model.RSVPStatusGoing
+			counter = "attendee_count"
+		}
+		rsvp.JoinedAt = time.Now()
+		if existing.ID != 0 {
+			rsvp.ID = existing.ID
+			rsvp.CreatedAt = existing.CreatedAt
+			err = tx.Model(&existing).Select("status", "pet_ids", "note", "joined_at").Updates(rsvp).Error
+		} else {
+			err = tx.Create(rsvp).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.Event{}).Where("id = ?", event.ID).
+			Update(counter, gorm.Expr(counter+" + 1")).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "报名活动失败", logger.Int("event_id", int(rsvp.EventID)), logger.Int("user_id", int(rsvp.UserID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// CancelRSVP 取消报名,已报名的取消后由候补第一位递补,返回递补成功的报名
+func (r *eventRepository) CancelRSVP(ctx context.Context, eventID, userID uint) ([]*model.EventRSVP, error) {
+	var promoted []*model.EventRSVP
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		event, err := lockEvent(tx, eventID)
+		if err != nil {
+			return err
+		}
+
+		var rsvp model.EventRSVP
+		err = tx.Where("event_id = ? AND user_id = ? AND status IN ?", eventID, userID,
+			[]string{model.RSVPStatusGoing, model.RSVPStatusWaitlisted}).First(&rsvp).Error
+		if err != nil {
+			return err
+		}
+		// 不能用tx.Model(&rsvp)更新,gorm会把新状态回写到rsvp.Status
+		err = tx.Model(&model.EventRSVP{}).Where("id = ?", rsvp.ID).Update("status", model.RSVPStatusCancelled).Error
+		if err != nil {
+			return err
+		}
+
+		if rsvp.Status == model.RSVPStatusWaitlisted {
+			return tx.Model(&model.Event{}).Where("id = ? AND waitlist_count > 0", eventID).
+				Update("waitlist_count", gorm.Expr("waitlist_count - 1")).Error
+		}
+		err = tx.Model(&model.Event{}).Where("id = ? AND attendee_count > 0", eventID).
+			Update("attendee_count", gorm.Expr("attendee_count - 1")).Error
+		if err != nil {
+			return err
+		}
+		event.AttendeeCount--
+		promoted, err = promoteWaitlist(tx, event)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "取消报名失败", logger.Int("event_id", int(eventID)), logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// lockEvent 在事务中锁定活动行
+func lockEvent(tx *gorm.DB, eventID uint) (*model.Event, error) {
+	var event model.Event
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", eventID).First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// promoteWaitlist 按候补顺序填补空余名额,活动已取消或已开始时不再递补
+func promoteWaitlist(tx *gorm.DB, event *model.Event) ([]*model.EventRSVP, error) {
+	free := event.Capacity - event.AttendeeCount
+	if free <= 0 || event.Status != model.EventStatusPublished || !event.StartAt.After(time.Now()) {
+		return nil, nil
+	}
+
+	var waitlisted []*model.EventRSVP
+	err := tx.Where("event_id = ? AND status = ?", event.ID, model.RSVPStatusWaitlisted).
+		Order("joined_at ASC, id ASC").Limit(free).Find(&waitlisted).Error
+	if err != nil || len(waitlisted) == 0 {
+		return nil, err
+	}
+
+	ids := make([]uint, len(waitlisted))
+	for i, rsvp := range waitlisted {
+		ids[i] = rsvp.ID
+		rsvp.Status = model.RSVPStatusGoing
+	}
+	if err := tx.Model(&model.EventRSVP{}).Where("id IN ?", ids).Update("status", model.RSVPStatusGoing).Error; err != nil {
+		return nil, err
+	}
+	err = tx.Model(&model.Event{}).Where("id = ?", event.ID).Updates(map[string]interface{}{
+		"attendee_count": gorm.Expr("attendee_count + ?", len(ids)),
+		"waitlist_count": gorm.Expr("GREATEST(waitlist_count - ?, 0)", len(ids)),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return waitlisted, nil
+}
+
+// GetRSVP 获取用户对活动的报名记录
+func (r *eventRepository) GetRSVP(ctx context.Context, eventID, userID uint) (*model.EventRSVP, error) {
+	var rsvp model.EventRSVP
+	if err := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).First(&rsvp).Error; err != nil {
+		return nil, err
+	}
+	return &rsvp, nil
+}
+
+// WaitlistPosition 候补排队位置,从1开始
+func (r *eventRepository) WaitlistPosition(ctx context.Context, rsvp *model.EventRSVP) (int, error) {
+	var ahead int64
+	err := r.db.WithContext(ctx).Model(&model.EventRSVP{}).
+		Where("event_id = ? AND status = ?", rsvp.EventID, model.RSVPStatusWaitlisted).
+		Where("joined_at < ? OR (joined_at = ? AND id < ?)", rsvp.JoinedAt, rsvp.JoinedAt, rsvp.ID).
+		Count(&ahead).Error
+	if err != nil {
+		logger.Error(ctx, "获取候补位置失败", logger.Int("id", int(rsvp.ID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return int(ahead) + 1, nil
+}
+
+// ListRSVPs 活动报名名单,按报名顺序
+func (r *eventRepository) ListRSVPs(ctx context.Context, eventID uint, status string, offset, limit int) ([]*model.EventRSVP, int64, error) {
+	var rsvps []*model.EventRSVP
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.EventRSVP{}).Where("event_id = ?", eventID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取报名总数失败", logger.Int("event_id", int(eventID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("User", publicUserColumns).
+		Offset(offset).Limit(limit).Order("joined_at ASC, id ASC").Find(&rsvps).Error
+	if err != nil {
+		logger.Error(ctx, "获取报名名单失败", logger.Int("event_id", int(eventID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return rsvps, total, nil
+}
+
+// ListUserRSVPs 用户有效的报名记录,按活动开始时间倒序
+func (r *eventRepository) ListUserRSVPs(ctx context.Context, userID uint, offset, limit int) ([]*model.EventRSVP, int64, error) {
+	var rsvps []*model.EventRSVP
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.EventRSVP{}).
+		Where("event_rsvps.user_id = ? AND event_rsvps.status IN ?", userID, []string{model.RSVPStatusGoing, model.RSVPStatusWaitlisted})
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取我的报名总数失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Joins("Event").Offset(offset).Limit(limit).Order("Event.start_at DESC").Find(&rsvps).Error
+	if err != nil {
+		logger.Error(ctx, "获取我的报名失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return rsvps, total, nil
+}
+
+// ListAttendeeIDs 获取指定报名状态的用户ID
+func (r *eventRepository) ListAttendeeIDs(ctx context.Context, eventID uint, statuses ...string) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.EventRSVP{}).
+		Where("event_id = ? AND status IN ?", eventID, statuses).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		logger.Error(ctx, "获取报名用户失败", logger.Int("event_id", int(eventID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListDueReminders 查询已到提醒时间且未提醒的活动
+func (r *eventRepository) ListDueReminders(ctx context.Context, now time.Time, limit int) ([]*model.Event, error) {
+	var events []*model.Event
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reminded_at IS NULL AND remind_before_min > 0 AND start_at > ?", model.EventStatusPublished, now).
+		Where("start_at <= DATE_ADD(?, INTERVAL remind_before_min MINUTE)", now).
+		Order("start_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		logger.Error(ctx, "查询待提醒活动失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// ClaimReminder 占用提醒发送权,多实例部署时保证同一活动只提醒一次
+func (r *eventRepository) ClaimReminder(ctx context.Context, eventID uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Event{}).
+		Where("id = ? AND reminded_at IS NULL", eventID).
+		Update("reminded_at", at)
+	if result.Error != nil {
+		logger.Error(ctx, "标记活动提醒失败", logger.Int("id", int(eventID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
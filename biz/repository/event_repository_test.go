@@ -0,0 +1,89 @@
+//go:build cgo
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"pet-service/biz/model"
+)
+
+// newTestDB 创建内存SQLite数据库,无法创建时跳过
+func newTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err == nil {
+		err = db.AutoMigrate(models...)
+	}
+	if err != nil {
+		t.Skipf("SQLite不可用: %v", err)
+	}
+	return db
+}
+
+func TestCancelWaitlistedRSVPOnFullEvent(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &model.Event{}, &model.EventRSVP{})
+	repo := NewEventRepository(db)
+
+	event := &model.Event{
+		OrganizerID: 1,
+		Title:       "周末遛狗",
+		Location:    "公园",
+		StartAt:     time.Now().Add(24 * time.Hour),
+		EndAt:       time.Now().Add(26 * time.Hour),
+		Capacity:    1,
+		Status:      model.EventStatusPublished,
+	}
+	if err := repo.Create(ctx, event); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, userID := range []uint{1, 2} {
+		if err := repo.RSVP(ctx, &model.EventRSVP{EventID: event.ID, UserID: userID}); err != nil {
+			t.Fatalf("RSVP(%d): %v", userID, err)
+		}
+	}
+
+	promoted, err := repo.CancelRSVP(ctx, event.ID, 2)
+	if err != nil {
+		t.Fatalf("CancelRSVP: %v", err)
+	}
+	if len(promoted) != 0 {
+		t.Errorf("promoted = %d, want 0", len(promoted))
+	}
+
+	got, err := repo.GetByID(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.AttendeeCount != 1 || got.WaitlistCount != 0 {
+		t.Errorf("attendee_count = %d, waitlist_count = %d, want 1, 0", got.AttendeeCount, got.WaitlistCount)
+	}
+	wantStatus := map[uint]string{1: model.RSVPStatusGoing, 2: model.RSVPStatusCancelled}
+	for userID, want := range wantStatus {
+		rsvp, err := repo.GetRSVP(ctx, event.ID, userID)
+		if err != nil {
+			t.Fatalf("GetRSVP(%d): %v", userID, err)
+		}
+		if rsvp.Status != want {
+			t.Errorf("user %d status = %s, want %s", userID, rsvp.Status, want)
+		}
+	}
+
+	// 活动仍满员,新报名进入候补
+	rsvp := &model.EventRSVP{EventID: event.ID, UserID: 3}
+	if err := repo.RSVP(ctx, rsvp); err != nil {
+		t.Fatalf("RSVP(3): %v", err)
+	}
+	if rsvp.Status != model.RSVPStatusWaitlisted {
+		t.Errorf("user 3 status = %s, want %s", rsvp.Status, model.RSVPStatusWaitlisted)
+	}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// FileRepository 文件仓储接口
+type FileRepository interface {
+	Create(ctx context.Context, file *model.File) error
+	GetByID(ctx context.Context, id uint) (*model.File, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// fileRepository 文件仓储实现
+type fileRepository struct {
+	db *gorm.DB
+}
+
+// NewFileRepository 创建文件仓储
+func NewFileRepository(db *gorm.DB) FileRepository {
+	return &fileRepository{db: db}
+}
+
+// Create 创建文件记录
+func (r *fileRepository) Create(ctx context.Context, file *model.File) error {
+	if err := r.db.WithContext(ctx).Create(file).Error; err != nil {
+		logger.Error(ctx, "创建文件记录失败", logger.String("key", file.StorageKey), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建文件记录成功", logger.Int("id", int(file.ID)))
+	return nil
+}
+
+// GetByID 根据ID获取文件记录
+func (r *fileRepository) GetByID(ctx context.Context, id uint) (*model.File, error) {
+	var file model.File
+	err := r.db.WithContext(ctx).Where("id = ? AND is_deleted = 0", id).First(&file).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取文件记录失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+// Delete 删除文件记录(软删除)
+func (r *fileRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&model.File{}).Where("id = ?", id).Update("is_deleted", 1).Error
+	if err != nil {
+		logger.Error(ctx, "删除文件记录失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
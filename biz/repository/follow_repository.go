@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// FollowRepository 关注与拉黑仓储接口
+type FollowRepository interface {
+	FollowUser(ctx context.Context, followerID, followeeID uint) (bool, error)
+	UnfollowUser(ctx context.Context, followerID, followeeID uint) (bool, error)
+	IsFollowing(ctx context.Context, followerID, followeeID uint) (bool, error)
+	ListFollowers(ctx context.Context, userID, viewerID, cursor uint, limit int) ([]*model.UserFollow, error)
+	ListFollowing(ctx context.Context, userID, viewerID, cursor uint, limit int) ([]*model.UserFollow, error)
+	ListMutuals(ctx context.Context, userID, cursor uint, limit int) ([]*model.UserFollow, error)
+	CountUserFollows(ctx context.Context, userID uint) (followers, following int64, err error)
+
+	FollowPet(ctx context.Context, userID, petID uint) (bool, error)
+	UnfollowPet(ctx context.Context, userID, petID uint) (bool, error)
+	IsFollowingPet(ctx context.Context, userID, petID uint) (bool, error)
+	ListPetFollowers(ctx context.Context, petID, viewerID, cursor uint, limit int) ([]*model.PetFollow, error)
+	ListFollowedPets(ctx context.Context, userID, cursor uint, limit int) ([]*model.PetFollow, error)
+	CountPetFollowers(ctx context.Context, petID uint) (int64, error)
+
+	Block(ctx context.Context, blockerID, blockedID uint) error
+	Unblock(ctx context.Context, blockerID, blockedID uint) (bool, error)
+	IsBlocked(ctx context.Context, blockerID, blockedID uint) (bool, error)
+	BlockedEither(ctx context.Context, userID, otherID uint) (bool, error)
+	ListBlocks(ctx context.Context, blockerID, cursor uint, limit int) ([]*model.UserBlock, error)
+}
+
+// followRepository 关注与拉黑仓储实现
+type followRepository struct {
+	db *gorm.DB
+}
+
+// NewFollowRepository 创建关注与拉黑仓储
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// notBlockedWith 排除与viewerID存在任一方向拉黑关系的用户,column为待判断的用户ID列
+func notBlockedWith(viewerID uint, column string) (string, []interface{}) {
+	return "NOT EXISTS (SELECT 1 FROM user_blocks WHERE (user_blocks.blocker_id = ? AND user_blocks.blocked_id = " + column +
+		") OR (user_blocks.blocker_id = " + column + " AND user_blocks.blocked_id = ?))", []interface{}{viewerID, viewerID}
+}
+
+// cursorPage 按ID倒序的游标条件
+func cursorPage(query *gorm.DB, column string, cursor uint, limit int) *gorm.DB {
+	if cursor != 0 {
+		query = query.Where(column+" < ?", cursor)
+	}
+	return query.Order(column + " DESC").Limit(limit)
+}
+
+// FollowUser 关注用户,已关注时返回false
+func (r *followRepository) FollowUser(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserFollow{FollowerID: followerID, FolloweeID: followeeID})
+	if result.Error != nil {
+		logger.Error(ctx, "关注用户失败", logger.Int("follower_id", int(followerID)), logger.Int("followee_id", int(followeeID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnfollowUser 取消关注,未关注时返回false
+func (r *followRepository) UnfollowUser(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&model.UserFollow{})
+	if result.Error != nil {
+		logger.Error(ctx, "取消关注失败", logger.Int("follower_id", int(followerID)), logger.Int("followee_id", int(followeeID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsFollowing 判断是否已关注
+func (r *followRepository) IsFollowing(ctx context.Context, followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.UserFollow{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询关注关系失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListFollowers 粉丝列表,不含与viewerID互相拉黑的用户
+func (r *followRepository) ListFollowers(ctx context.Context, userID, viewerID, cursor uint, limit int) ([]*model.UserFollow, error) {
+	var follows []*model.UserFollow
+	query := r.db.WithContext(ctx).Preload("Follower", publicUserColumns).Where("followee_id = ?", userID)
+	if viewerID != 0 {
+		cond, args := notBlockedWith(viewerID, "user_follows.follower_id")
+		query = query.Where(cond, args...)
+	}
+	if err := cursorPage(query, "id", cursor, limit).Find(&follows).Error; err != nil {
+		logger.Error(ctx, "获取粉丝列表失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return follows, nil
+}
+
+// ListFollowing 关注列表,不含与viewerID互相拉黑的用户
+func (r *followRepository) ListFollowing(ctx context.Context, userID, viewerID, cursor uint, limit int) ([]*model.UserFollow, error) {
+	var follows []*model.UserFollow
+	query := r.db.WithContext(ctx).Preload("Followee", publicUserColumns).Where("follower_id = ?", userID)
+	if viewerID != 0 {
+		cond, args := notBlockedWith(viewerID, "user_follows.followee_id")
+		query = query.Where(cond, args...)
+	}
+	if err := cursorPage(query, "id", cursor, limit).Find(&follows).Error; err != nil {
+		logger.Error(ctx, "获取关注列表失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return follows, nil
+}
+
+// ListMutuals 互相关注的用户
+func (r *followRepository) ListMutuals(ctx context.Context, userID, cursor uint, limit int) ([]*model.UserFollow, error) {
+	var follows []*model.UserFollow
+	query := r.db.WithContext(ctx).Preload("Followee", publicUserColumns).
+		Where("follower_id = ?", userID).
+		Where("EXISTS (SELECT 1 FROM user_follows f WHERE f.follower_id = user_follows.followee_id AND f.followee_id = user_follows.follower_id)")
+	if err := cursorPage(query, "id", cursor, limit).Find(&follows).Error; err != nil {
+		logger.Error(ctx, "获取互相关注列表失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return follows, nil
+}
+
+// CountUserFollows 统计用户的粉丝数和关注数
+func (r *followRepository) CountUserFollows(ctx context.Context, userID uint) (int64, int64, error) {
+	var followers, following int64
+	if err := r.db.WithContext(ctx).Model(&model.UserFollow{}).Where("followee_id = ?", userID).Count(&followers).Error; err != nil {
+		logger.Error(ctx, "统计粉丝数失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return 0, 0, err
+	}
+	if err := r.db.WithContext(ctx).Model(&model.UserFollow{}).Where("follower_id = ?", userID).Count(&following).Error; err != nil {
+		logger.Error(ctx, "统计关注数失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
+
+// FollowPet 关注宠物,已关注时返回false
+func (r *followRepository) FollowPet(ctx context.Context, userID, petID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.PetFollow{UserID: userID, PetID: petID})
+	if result.Error != nil {
+		logger.Error(ctx, "关注宠物失败", logger.Int("user_id", int(userID)), logger.Int("pet_id", int(petID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnfollowPet 取消关注宠物,未关注时返回false
+func (r *followRepository) UnfollowPet(ctx context.Context, userID, petID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND pet_id = ?", userID, petID).Delete(&model.PetFollow{})
+	if result.Error != nil {
+		logger.Error(ctx, "取消关注宠物失败", logger.Int("user_id", int(userID)), logger.Int("pet_id", int(petID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsFollowingPet 判断是否已关注宠物
+func (r *followRepository) IsFollowingPet(ctx context.Context, userID, petID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PetFollow{}).
+		Where("user_id = ? AND pet_id = ?", userID, petID).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询宠物关注关系失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListPetFollowers 宠物的粉丝列表,不含与viewerID互相拉黑的用户
+func (r *followRepository) ListPetFollowers(ctx context.Context, petID, viewerID, cursor uint, limit int) ([]*model.PetFollow, error) {
+	var follows []*model.PetFollow
+	query := r.db.WithContext(ctx).Preload("User", publicUserColumns).Where("pet_id = ?", petID)
+	if viewerID != 0 {
+		cond, args := notBlockedWith(viewerID, "pet_follows.user_id")
+		query = query.Where(cond, args...)
+	}
+	if err := cursorPage(query, "id", cursor, limit).Find(&follows).Error; err != nil {
+		logger.Error(ctx, "获取宠物粉丝列表失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return follows, nil
+}
+
+// ListFollowedPets 用户关注的宠物,不含已删除的宠物
+func (r *followRepository) ListFollowedPets(ctx context.Context, userID, cursor uint, limit int) ([]*model.PetFollow, error) {
+	var follows []*model.PetFollow
+	query := r.db.WithContext(ctx).Preload("Pet", taggedPetColumns).
+		Where("user_id = ?", userID).
+		Where("EXISTS (SELECT 1 FROM pets WHERE pets.id = pet_follows.pet_id AND pets.is_deleted = 0)")
+	if err := cursorPage(query, "id", cursor, limit).Find(&follows).Error; err != nil {
+		logger.Error(ctx, "获取关注的宠物失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return follows, nil
+}
+
+// CountPetFollowers 统计宠物的粉丝数
+func (r *followRepository) CountPetFollowers(ctx context.Context, petID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.PetFollow{}).Where("pet_id = ?", petID).Count(&count).Error; err != nil {
+		logger.Error(ctx, "统计宠物粉丝数失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// Block 拉黑用户并解除双方的关注关系
+func (r *followRepository) Block(ctx context.Context, blockerID, blockedID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.UserBlock{BlockerID: blockerID, BlockedID: blockedID}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("(follower_id = ? AND followee_id = ?) OR (follower_id = ? AND followee_id = ?)",
+			blockerID, blockedID, blockedID, blockerID).Delete(&model.UserFollow{}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "拉黑用户失败", logger.Int("blocker_id", int(blockerID)), logger.Int("blocked_id", int(blockedID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "拉黑用户", logger.Int("blocker_id", int(blockerID)), logger.Int("blocked_id", int(blockedID)))
+	return nil
+}
+
+// Unblock 取消拉黑,未拉黑时返回false
+func (r *followRepository) Unblock(ctx context.Context, blockerID, blockedID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&model.UserBlock{})
+	if result.Error != nil {
+		logger.Error(ctx, "取消拉黑失败", logger.Int("blocker_id", int(blockerID)), logger.Int("blocked_id", int(blockedID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsBlocked 判断blockerID是否拉黑了blockedID
+func (r *followRepository) IsBlocked(ctx context.Context, blockerID, blockedID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.UserBlock{}).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询拉黑关系失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// BlockedEither 判断两个用户之间是否存在任一方向的拉黑
+func (r *followRepository) BlockedEither(ctx context.Context, userID, otherID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询拉黑关系失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListBlocks 黑名单
+func (r *followRepository) ListBlocks(ctx context.Context, blockerID, cursor uint, limit int) ([]*model.UserBlock, error) {
+	var blocks []*model.UserBlock
+	query := r.db.WithContext(ctx).Preload("Blocked", publicUserColumns).Where("blocker_id = ?", blockerID)
+	if err := cursorPage(query, "id", cursor, limit).Find(&blocks).Error; err != nil {
+		logger.Error(ctx, "获取黑名单失败", logger.Int("blocker_id", int(blockerID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return blocks, nil
+}
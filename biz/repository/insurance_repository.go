@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ErrClaimStatusChanged 理赔状态已被并发修改
+var ErrClaimStatusChanged = errors.New("理赔状态已变更,请刷新后重试")
+
+// InsuranceRepository 宠物保险仓储接口
+type InsuranceRepository interface {
+	CreatePolicy(ctx context.Context, policy *model.InsurancePolicy) error
+	UpdatePolicy(ctx context.Context, policy *model.InsurancePolicy) error
+	DeletePolicy(ctx context.Context, id uint) error
+	GetPolicy(ctx context.Context, id uint) (*model.InsurancePolicy, error)
+	ListPolicies(ctx context.Context, petID uint) ([]*model.InsurancePolicy, error)
+	ExistsPolicyNumber(ctx context.Context, petID uint, policyNumber string, excludeID uint) (bool, error)
+	CountActiveClaims(ctx context.Context, policyID uint) (int64, error)
+
+	CreateClaim(ctx context.Context, claim *model.InsuranceClaim) error
+	UpdateClaim(ctx context.Context, claim *model.InsuranceClaim) error
+	DeleteClaim(ctx context.Context, id uint) error
+	GetClaim(ctx context.Context, id uint) (*model.InsuranceClaim, error)
+	ListClaims(ctx context.Context, petID uint, req *model.ListInsuranceClaimRequest, offset, limit int) ([]*model.InsuranceClaim, int64, error)
+	// ChangeClaimStatus 仅当理赔仍处于fromStatus时更新状态,并写入状态历史
+	ChangeClaimStatus(ctx context.Context, claim *model.InsuranceClaim, fromStatus string, event *model.InsuranceClaimEvent) error
+	ListClaimEvents(ctx context.Context, claimID uint) ([]*model.InsuranceClaimEvent, error)
+	SumClaims(ctx context.Context, petID uint, year int) ([]*model.ClaimAmountRow, error)
+}
+
+// insuranceRepository 宠物保险仓储实现
+type insuranceRepository struct {
+	db *gorm.DB
+}
+
+// NewInsuranceRepository 创建宠物保险仓储
+func NewInsuranceRepository(db *gorm.DB) InsuranceRepository {
+	return &insuranceRepository{db: db}
+}
+
+// CreatePolicy 创建保单
+func (r *insuranceRepository) CreatePolicy(ctx context.Context, policy *model.InsurancePolicy) error {
+	if err := r.db.WithContext(ctx).Create(policy).Error; err != nil {
+		logger.Error(ctx, "创建保单失败", logger.Int("pet_id", int(policy.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// UpdatePolicy 更新保单
+func (r *insuranceRepository) UpdatePolicy(ctx context.Context, policy *model.InsurancePolicy) error {
+	err := r.db.WithContext(ctx).Model(policy).
+		Select("provider", "policy_number", "coverage", "coverage_limit", "deductible", "reimbursement_rate", "start_date", "end_date", "notes").
+		Updates(policy).Error
+	if err != nil {
+		logger.Error(ctx, "更新保单失败", logger.Int("id", int(policy.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// DeletePolicy 软删除保单
+func (r *insuranceRepository) DeletePolicy(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Model(&model.InsurancePolicy{}).Where("id = ?", id).Update("is_deleted", 1).Error; err != nil {
+		logger.Error(ctx, "删除保单失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetPolicy 根据ID获取保单
+func (r *insuranceRepository) GetPolicy(ctx context.Context, id uint) (*model.InsurancePolicy, error) {
+	var policy model.InsurancePolicy
+	if err := r.db.WithContext(ctx).Where("id = ? AND is_deleted = 0", id).First(&policy).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// ListPolicies 获取宠物的保单,按生效日期倒序
+func (r *insuranceRepository) ListPolicies(ctx context.Context, petID uint) ([]*model.InsurancePolicy, error) {
+	var policies []*model.InsurancePolicy
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND is_deleted = 0", petID).
+		Order("start_date DESC, id DESC").
+		Find(&policies).Error
+	if err != nil {
+		logger.Error(ctx, "获取保单列表失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return policies, nil
+}
+
+// ExistsPolicyNumber 检查宠物下是否已存在相同保单号
+func (r *insuranceRepository) ExistsPolicyNumber(ctx context.Context, petID uint, policyNumber string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.InsurancePolicy{}).
+		Where("pet_id = ? AND policy_number = ? AND id <> ?", petID, policyNumber, excludeID).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "检查保单号失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountActiveClaims 统计保单下未删除的理赔数
+func (r *insuranceRepository) CountActiveClaims(ctx context.Context, policyID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.InsuranceClaim{}).
+		Where("policy_id = ? AND is_deleted = 0", policyID).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "统计保单理赔数失败", logger.Int("policy_id", int(policyID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateClaim 创建理赔草稿
+func (r *insuranceRepository) CreateClaim(ctx context.Context, claim *model.InsuranceClaim) error {
+	if err := r.db.WithContext(ctx).Omit("Policy", "Events").Create(claim).Error; err != nil {
+		logger.Error(ctx, "创建理赔失败", logger.Int("policy_id", int(claim.PolicyID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateClaim 更新理赔草稿内容
+func (r *insuranceRepository) UpdateClaim(ctx context.Context, claim *model.InsuranceClaim) error {
+	result := r.db.WithContext(ctx).Model(claim).
+		Where("status = ?", model.ClaimStatusDraft).
+		Select("medical_record_id", "invoice_no", "treatment_date", "description", "claimed_amount", "attachment_file_ids").
+		Updates(claim)
+	if result.Error != nil {
+		logger.Error(ctx, "更新理赔失败", logger.Int("id", int(claim.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrClaimStatusChanged
+	}
+	return nil
+}
+
+// DeleteClaim 软删除理赔草稿
+func (r *insuranceRepository) DeleteClaim(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.InsuranceClaim{}).
+		Where("id = ? AND status = ?", id, model.ClaimStatusDraft).
+		Update("is_deleted", 1)
+	if result.Error != nil {
+		logger.Error(ctx, "删除理赔失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrClaimStatusChanged
+	}
+	return nil
+}
+
+// GetClaim 根据ID获取理赔,附带保单
+func (r *insuranceRepository) GetClaim(ctx context.Context, id uint) (*model.InsuranceClaim, error) {
+	var claim model.InsuranceClaim
+	if err := r.db.WithContext(ctx).Preload("Policy").Where("id = ? AND is_deleted = 0", id).First(&claim).Error; err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// ListClaims 分页获取宠物的理赔,按治疗日期倒序
+func (r *insuranceRepository) ListClaims(ctx context.Context, petID uint, req *model.ListInsuranceClaimRequest, offset, limit int) ([]*model.InsuranceClaim, int64, error) {
+	var claims []*model.InsuranceClaim
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.InsuranceClaim{}).Where("pet_id = ? AND is_deleted = 0", petID)
+	if req.PolicyID > 0 {
+		query = query.Where("policy_id = ?", req.PolicyID)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Year > 0 {
+		query = query.Where("YEAR(treatment_date) = ?", req.Year)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取理赔总数失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Preload("Policy").Offset(offset).Limit(limit).Order("treatment_date DESC, id DESC").Find(&claims).Error; err != nil {
+		logger.Error(ctx, "获取理赔列表失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return claims, total, nil
+}
+
+// ChangeClaimStatus 条件更新理赔状态并记录历史,状态已被修改时返回ErrClaimStatusChanged
+func (r *insuranceRepository) ChangeClaimStatus(ctx context.Context, claim *model.InsuranceClaim, fromStatus string, event *model.InsuranceClaimEvent) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(claim).
+			Where("status = ?", fromStatus).
+			Select("status", "status_note", "approved_amount", "paid_amount", "submitted_at", "decided_at", "paid_at").
+			Updates(claim)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrClaimStatusChanged
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil && !errors.Is(err, ErrClaimStatusChanged) {
+		logger.Error(ctx, "变更理赔状态失败", logger.Int("id", int(claim.ID)), logger.ErrorField(err))
+	}
+	return err
+}
+
+// ListClaimEvents 获取理赔的状态历史,按时间正序
+func (r *insuranceRepository) ListClaimEvents(ctx context.Context, claimID uint) ([]*model.InsuranceClaimEvent, error) {
+	var events []*model.InsuranceClaimEvent
+	if err := r.db.WithContext(ctx).Where("claim_id = ?", claimID).Order("id ASC").Find(&events).Error; err != nil {
+		logger.Error(ctx, "获取理赔历史失败", logger.Int("claim_id", int(claimID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// SumClaims 按治疗年份、保单和状态汇总宠物的理赔金额,year为0时不限年份
+func (r *insuranceRepository) SumClaims(ctx context.Context, petID uint, year int) ([]*model.ClaimAmountRow, error) {
+	var rows []*model.ClaimAmountRow
+	query := r.db.WithContext(ctx).Model(&model.InsuranceClaim{}).
+		Select("YEAR(treatment_date) AS year, policy_id, status, COUNT(*) AS count, "+
+			"SUM(claimed_amount) AS claimed, SUM(approved_amount) AS approved, SUM(paid_amount) AS paid").
+		Where("pet_id = ? AND is_deleted = 0", petID)
+	if year > 0 {
+		query = query.Where("YEAR(treatment_date) = ?", year)
+	}
+	err := query.Group("YEAR(treatment_date), policy_id, status").
+		Order("year DESC, policy_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		logger.Error(ctx, "汇总理赔金额失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ReportBoxFilter 走失/招领信息矩形范围查询条件
+type ReportBoxFilter struct {
+	Type    string
+	Species string
+	MinLat  float64
+	MaxLat  float64
+	MinLng  float64
+	MaxLng  float64
+	Limit   int
+}
+
+// LostFoundRepository 走失/招领仓储接口
+type LostFoundRepository interface {
+	Create(ctx context.Context, report *model.LostFoundReport) error
+	GetByID(ctx context.Context, id uint) (*model.LostFoundReport, error)
+	GetOpenByIDs(ctx context.Context, ids []uint) ([]*model.LostFoundReport, error)
+	ListByUser(ctx context.Context, userID uint, status string, offset, limit int) ([]*model.LostFoundReport, int64, error)
+	ListOpenInBox(ctx context.Context, filter *ReportBoxFilter) ([]*model.LostFoundReport, error)
+	ListOpenAfter(ctx context.Context, afterID uint, limit int) ([]*model.LostFoundReport, error)
+	UpdateStatus(ctx context.Context, id uint, status string) error
+}
+
+// lostFoundRepository 走失/招领仓储实现
+type lostFoundRepository struct {
+	db *gorm.DB
+}
+
+// NewLostFoundRepository 创建走失/招领仓储
+func NewLostFoundRepository(db *gorm.DB) LostFoundRepository {
+	return &lostFoundRepository{db: db}
+}
+
+// Create 创建走失/招领信息
+func (r *lostFoundRepository) Create(ctx context.Context, report *model.LostFoundReport) error {
+	if err := r.db.WithContext(ctx).Create(report).Error; err != nil {
+		logger.Error(ctx, "创建走失/招领信息失败", logger.Int("user_id", int(report.UserID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建走失/招领信息成功", logger.Int("id", int(report.ID)), logger.String("type", report.Type))
+	return nil
+}
+
+// GetByID 根据ID获取走失/招领信息
+func (r *lostFoundRepository) GetByID(ctx context.Context, id uint) (*model.LostFoundReport, error) {
+	var report model.LostFoundReport
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&report).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取走失/招领信息失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &report, nil
+}
+
+// GetOpenByIDs 批量获取未解决的走失/招领信息
+func (r *lostFoundRepository) GetOpenByIDs(ctx context.Context, ids []uint) ([]*model.LostFoundReport, error) {
+	var reports []*model.LostFoundReport
+	if len(ids) == 0 {
+		return reports, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ? AND status = ?", ids, model.ReportStatusOpen).Find(&reports).Error
+	if err != nil {
+		logger.Error(ctx, "批量获取走失/招领信息失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ListByUser 获取用户发布的走失/招领信息
+func (r *lostFoundRepository) ListByUser(ctx context.Context, userID uint, status string, offset, limit int) ([]*model.LostFoundReport, int64, error) {
+	var reports []*model.LostFoundReport
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.LostFoundReport{}).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取走失/招领信息总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&reports).Error; err != nil {
+		logger.Error(ctx, "获取走失/招领信息列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// ListOpenInBox 查询矩形范围内未解决的走失/招领信息,Redis不可用时作为降级查询
+func (r *lostFoundRepository) ListOpenInBox(ctx context.Context, filter *ReportBoxFilter) ([]*model.LostFoundReport, error) {
+	var reports []*model.LostFoundReport
+
+	query := r.db.WithContext(ctx).Model(&model.LostFoundReport{}).
+		Where("status = ?", model.ReportStatusOpen).
+		Where("latitude BETWEEN ? AND ?", filter.MinLat, filter.MaxLat).
+		Where("longitude BETWEEN ? AND ?", filter.MinLng, filter.MaxLng)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Species != "" {
+		query = query.Where("species = ?", filter.Species)
+	}
+	if err := query.Limit(filter.Limit).Order("occurred_at DESC").Find(&reports).Error; err != nil {
+		logger.Error(ctx, "按范围查询走失/招领信息失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return reports, nil
+}
+
+// ListOpenAfter 按ID顺序分批获取未解决的走失/招领信息,用于重建地理索引
+func (r *lostFoundRepository) ListOpenAfter(ctx context.Context, afterID uint, limit int) ([]*model.LostFoundReport, error) {
+	var reports []*model.LostFoundReport
+	err := r.db.WithContext(ctx).
+		Select("id", "type", "latitude", "longitude").
+		Where("status = ? AND id > ?", model.ReportStatusOpen, afterID).
+		Order("id ASC").Limit(limit).Find(&reports).Error
+	if err != nil {
+		logger.Error(ctx, "获取未解决的走失/招领信息失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return reports, nil
+}
+
+// UpdateStatus 更新走失/招领信息状态
+func (r *lostFoundRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
+	err := r.db.WithContext(ctx).Model(&model.LostFoundReport{}).Where("id = ?", id).Update("status", status).Error
+	if err != nil {
+		logger.Error(ctx, "更新走失/招领信息状态失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
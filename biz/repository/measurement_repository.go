@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// bucketExprs 降采样时间桶表达式,周以周一为起始
+var bucketExprs = map[string]string{
+	model.IntervalDay:  "DATE(measured_at)",
+	model.IntervalWeek: "DATE(DATE_SUB(measured_at, INTERVAL WEEKDAY(measured_at) DAY))",
+}
+
+// MeasurementRepository 健康指标仓储接口
+type MeasurementRepository interface {
+	BatchCreate(ctx context.Context, items []*model.PetMeasurement) error
+	Delete(ctx context.Context, petID, id uint) error
+	ListRange(ctx context.Context, petID uint, metric string, from, to time.Time, limit int) ([]*model.PetMeasurement, error)
+	Aggregate(ctx context.Context, petID uint, metric string, from, to time.Time, interval string) ([]*model.MeasurementBucket, error)
+	Latest(ctx context.Context, petID uint, metric string) (*model.PetMeasurement, error)
+	EarliestSince(ctx context.Context, petID uint, metric string, since time.Time) (*model.PetMeasurement, error)
+}
+
+// measurementRepository 健康指标仓储实现
+type measurementRepository struct {
+	db *gorm.DB
+}
+
+// NewMeasurementRepository 创建健康指标仓储
+func NewMeasurementRepository(db *gorm.DB) MeasurementRepository {
+	return &measurementRepository{db: db}
+}
+
+// BatchCreate 批量写入测量数据
+func (r *measurementRepository) BatchCreate(ctx context.Context, items []*model.PetMeasurement) error {
+	if err := r.db.WithContext(ctx).CreateInBatches(items, 100).Error; err != nil {
+		logger.Error(ctx, "批量写入测量数据失败", logger.Int("count", len(items)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Delete 删除测量数据
+func (r *measurementRepository) Delete(ctx context.Context, petID, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND pet_id = ?", id, petID).Delete(&model.PetMeasurement{})
+	if result.Error != nil {
+		logger.Error(ctx, "删除测量数据失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListRange 按时间区间查询原始测量数据
+func (r *measurementRepository) ListRange(ctx context.Context, petID uint, metric string, from, to time.Time, limit int) ([]*model.PetMeasurement, error) {
+	var items []*model.PetMeasurement
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND metric = ? AND measured_at BETWEEN ? AND ?", petID, metric, from, to).
+		Order("measured_at ASC").
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		logger.Error(ctx, "查询测量数据失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return items, nil
+}
+
+// Aggregate 按天或周聚合测量数据
+func (r *measurementRepository) Aggregate(ctx context.Context, petID uint, metric string, from, to time.Time, interval string) ([]*model.MeasurementBucket, error) {
+	expr, ok := bucketExprs[interval]
+	if !ok {
+		return nil, fmt.Errorf("聚合粒度不支持: %s", interval)
+	}
+	var buckets []*model.MeasurementBucket
+	err := r.db.WithContext(ctx).Model(&model.PetMeasurement{}).
+		Select(expr+" AS bucket, AVG(value) AS avg, MIN(value) AS min, MAX(value) AS max, COUNT(*) AS count").
+		Where("pet_id = ? AND metric = ? AND measured_at BETWEEN ? AND ?", petID, metric, from, to).
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&buckets).Error
+	if err != nil {
+		logger.Error(ctx, "聚合测量数据失败", logger.Int("pet_id", int(petID)), logger.String("interval", interval), logger.ErrorField(err))
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// Latest 获取最近一次测量
+func (r *measurementRepository) Latest(ctx context.Context, petID uint, metric string) (*model.PetMeasurement, error) {
+	var item model.PetMeasurement
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND metric = ?", petID, metric).
+		Order("measured_at DESC").
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// EarliestSince 获取指定时间之后的第一次测量
+func (r *measurementRepository) EarliestSince(ctx context.Context, petID uint, metric string, since time.Time) (*model.PetMeasurement, error) {
+	var item model.PetMeasurement
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND metric = ? AND measured_at >= ?", petID, metric, since).
+		Order("measured_at ASC").
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
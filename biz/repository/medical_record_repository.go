@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// MedicalRecordRepository 就诊记录仓储接口
+type MedicalRecordRepository interface {
+	Create(ctx context.Context, record *model.MedicalRecord) error
+	Update(ctx context.Context, record *model.MedicalRecord) error
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*model.MedicalRecord, error)
+	List(ctx context.Context, petID uint, recordType string, from, to *time.Time, offset, limit int) ([]*model.MedicalRecord, int64, error)
+	ListByType(ctx context.Context, petID uint, recordType string) ([]*model.MedicalRecord, error)
+}
+
+// medicalRecordRepository 就诊记录仓储实现
+type medicalRecordRepository struct {
+	db *gorm.DB
+}
+
+// NewMedicalRecordRepository 创建就诊记录仓储
+func NewMedicalRecordRepository(db *gorm.DB) MedicalRecordRepository {
+	return &medicalRecordRepository{db: db}
+}
+
+// Create 创建就诊记录
+func (r *medicalRecordRepository) Create(ctx context.Context, record *model.MedicalRecord) error {
+	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+		logger.Error(ctx, "创建就诊记录失败", logger.Int("pet_id", int(record.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Update 更新就诊记录
+func (r *medicalRecordRepository) Update(ctx context.Context, record *model.MedicalRecord) error {
+	err := r.db.WithContext(ctx).Model(record).
+		Select("type", "visit_date", "clinic_name", "vet_name", "diagnosis", "treatment", "notes", "cost", "invoice_no", "vaccine_name", "batch_no", "valid_until", "attachment_file_ids").
+		Updates(record).Error
+	if err != nil {
+		logger.Error(ctx, "更新就诊记录失败", logger.Int("id", int(record.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Delete 软删除就诊记录
+func (r *medicalRecordRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.db.WithContext(ctx).Model(&model.MedicalRecord{}).Where("id = ?", id).Update("is_deleted", 1).Error; err != nil {
+		logger.Error(ctx, "删除就诊记录失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 根据ID获取就诊记录
+func (r *medicalRecordRepository) GetByID(ctx context.Context, id uint) (*model.MedicalRecord, error) {
+	var record model.MedicalRecord
+	if err := r.db.WithContext(ctx).Where("id = ? AND is_deleted = 0", id).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// List 分页获取宠物的就诊记录,按就诊日期倒序
+func (r *medicalRecordRepository) List(ctx context.Context, petID uint, recordType string, from, to *time.Time, offset, limit int) ([]*model.MedicalRecord, int64, error) {
+	var records []*model.MedicalRecord
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MedicalRecord{}).Where("pet_id = ? AND is_deleted = 0", petID)
+	if recordType != "" {
+		query = query.Where("type = ?", recordType)
+	}
+	if from != nil {
+		query = query.Where("visit_date >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("visit_date <= ?", *to)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取就诊记录总数失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("visit_date DESC, id DESC").Find(&records).Error; err != nil {
+		logger.Error(ctx, "获取就诊记录失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// ListByType 获取宠物某一类型的全部就诊记录,按就诊日期正序
+func (r *medicalRecordRepository) ListByType(ctx context.Context, petID uint, recordType string) ([]*model.MedicalRecord, error) {
+	var records []*model.MedicalRecord
+	err := r.db.WithContext(ctx).
+		Where("pet_id = ? AND type = ? AND is_deleted = 0", petID, recordType).
+		Order("visit_date ASC, id ASC").
+		Find(&records).Error
+	if err != nil {
+		logger.Error(ctx, "获取就诊记录失败", logger.Int("pet_id", int(petID)), logger.String("type", recordType), logger.ErrorField(err))
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// doseBucketExprs 依从性统计时间桶表达式,周以周一为起始
+var doseBucketExprs = map[string]string{
+	model.IntervalDay:  "DATE(scheduled_at)",
+	model.IntervalWeek: "DATE(DATE_SUB(scheduled_at, INTERVAL WEEKDAY(scheduled_at) DAY))",
+}
+
+// MedicationRepository 用药计划仓储接口
+type MedicationRepository interface {
+	CreatePlan(ctx context.Context, plan *model.MedicationPlan) error
+	GetPlan(ctx context.Context, id uint) (*model.MedicationPlan, error)
+	UpdatePlan(ctx context.Context, plan *model.MedicationPlan) error
+	ListPlans(ctx context.Context, petID uint, status string) ([]*model.MedicationPlan, error)
+	ListPlansToGenerate(ctx context.Context, until time.Time, limit int) ([]*model.MedicationPlan, error)
+	EndExpiredPlans(ctx context.Context, now time.Time) (int64, error)
+
+	CreateDoses(ctx context.Context, planID uint, doses []*model.MedicationDose, generatedUntil time.Time) error
+	DeletePendingDoses(ctx context.Context, planID uint, after time.Time) error
+	GetDose(ctx context.Context, id uint) (*model.MedicationDose, error)
+	UpdateDoseStatus(ctx context.Context, dose *model.MedicationDose, fromStatus string) error
+	ListDoses(ctx context.Context, petID uint, req *model.ListDoseRequest, from, to time.Time, limit int) ([]*model.MedicationDose, error)
+	ListDueReminders(ctx context.Context, now time.Time, limit int) ([]*model.MedicationDose, error)
+	ClaimReminder(ctx context.Context, doseID uint, at time.Time) (bool, error)
+	MarkMissed(ctx context.Context, before time.Time) (int64, error)
+	CountByPlan(ctx context.Context, petID, planID uint, from, to time.Time) ([]*model.DoseStatusCount, error)
+	CountByPeriod(ctx context.Context, petID, planID uint, from, to time.Time, interval string) ([]*model.DoseStatusCount, error)
+}
+
+// medicationRepository 用药计划仓储实现
+type medicationRepository struct {
+	db *gorm.DB
+}
+
+// NewMedicationRepository 创建用药计划仓储
+func NewMedicationRepository(db *gorm.DB) MedicationRepository {
+	return &medicationRepository{db: db}
+}
+
+// CreatePlan 创建用药计划
+func (r *medicationRepository) CreatePlan(ctx context.Context, plan *model.MedicationPlan) error {
+	if err := r.db.WithContext(ctx).Create(plan).Error; err != nil {
+		logger.Error(ctx, "创建用药计划失败", logger.Int("pet_id", int(plan.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetPlan 获取用药计划
+func (r *medicationRepository) GetPlan(ctx context.Context, id uint) (*model.MedicationPlan, error) {
+	var plan model.MedicationPlan
+	if err := r.db.WithContext(ctx).First(&plan, id).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// UpdatePlan 保存用药计划
+func (r *medicationRepository) UpdatePlan(ctx context.Context, plan *model.MedicationPlan) error {
+	if err := r.db.WithContext(ctx).Save(plan).Error; err != nil {
+		logger.Error(ctx, "更新用药计划失败", logger.Int("id", int(plan.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// ListPlans 获取宠物的用药计划
+func (r *medicationRepository) ListPlans(ctx context.Context, petID uint, status string) ([]*model.MedicationPlan, error) {
+	var plans []*model.MedicationPlan
+	query := r.db.WithContext(ctx).Where("pet_id = ?", petID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("id DESC").Find(&plans).Error; err != nil {
+		logger.Error(ctx, "查询用药计划失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return plans, nil
+}
+
+// ListPlansToGenerate 获取给药记录尚未生成到指定时间的进行中计划
+func (r *medicationRepository) ListPlansToGenerate(ctx context.Context, until time.Time, limit int) ([]*model.MedicationPlan, error) {
+	var plans []*model.MedicationPlan
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND generated_until < ?", model.MedicationStatusActive, until).
+		Where("end_at IS NULL OR end_at > generated_until").
+		Order("generated_until ASC").
+		Limit(limit).
+		Find(&plans).Error
+	if err != nil {
+		logger.Error(ctx, "查询待生成用药计划失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return plans, nil
+}
+
+// EndExpiredPlans 将已过结束时间的计划标记为已结束
+func (r *medicationRepository) EndExpiredPlans(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.MedicationPlan{}).
+		Where("status <> ? AND end_at IS NOT NULL AND end_at < ?", model.MedicationStatusEnded, now).
+		Update("status", model.MedicationStatusEnded)
+	if result.Error != nil {
+		logger.Error(ctx, "结束过期用药计划失败", logger.ErrorField(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CreateDoses 写入给药记录并推进计划的生成进度,已存在的同一时间点记录会被忽略
+func (r *medicationRepository) CreateDoses(ctx context.Context, planID uint, doses []*model.MedicationDose, generatedUntil time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(doses) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(doses, 100).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.MedicationPlan{}).Where("id = ?", planID).
+			Update("generated_until", generatedUntil).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "生成给药记录失败", logger.Int("plan_id", int(planID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// DeletePendingDoses 删除指定时间之后尚未执行的给药记录
+func (r *medicationRepository) DeletePendingDoses(ctx context.Context, planID uint, after time.Time) error {
+	err := r.db.WithContext(ctx).
+		Where("plan_id = ? AND status = ? AND scheduled_at > ?", planID, model.DoseStatusScheduled, after).
+		Delete(&model.MedicationDose{}).Error
+	if err != nil {
+		logger.Error(ctx, "删除待执行给药记录失败", logger.Int("plan_id", int(planID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetDose 获取给药记录
+func (r *medicationRepository) GetDose(ctx context.Context, id uint) (*model.MedicationDose, error) {
+	var dose model.MedicationDose
+	if err := r.db.WithContext(ctx).Preload("Plan").First(&dose, id).Error; err != nil {
+		return nil, err
+	}
+	return &dose, nil
+}
+
+// UpdateDoseStatus 更新给药结果,仅当记录仍处于fromStatus时生效
+func (r *medicationRepository) UpdateDoseStatus(ctx context.Context, dose *model.MedicationDose, fromStatus string) error {
+	result := r.db.WithContext(ctx).Model(&model.MedicationDose{}).
+		Where("id = ? AND status = ?", dose.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":   dose.Status,
+			"acted_at": dose.ActedAt,
+			"acted_by": dose.ActedBy,
+			"note":     dose.Note,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新给药记录失败", logger.Int("id", int(dose.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListDoses 按时间区间查询宠物的给药记录
+func (r *medicationRepository) ListDoses(ctx context.Context, petID uint, req *model.ListDoseRequest, from, to time.Time, limit int) ([]*model.MedicationDose, error) {
+	var doses []*model.MedicationDose
+	query := r.db.WithContext(ctx).Preload("Plan").
+		Where("pet_id = ? AND scheduled_at BETWEEN ? AND ?", petID, from, to)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.PlanID > 0 {
+		query = query.Where("plan_id = ?", req.PlanID)
+	}
+	if err := query.Order("scheduled_at ASC").Limit(limit).Find(&doses).Error; err != nil {
+		logger.Error(ctx, "查询给药记录失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return doses, nil
+}
+
+// ListDueReminders 查询已到提醒时间且未提醒的给药记录
+func (r *medicationRepository) ListDueReminders(ctx context.Context, now time.Time, limit int) ([]*model.MedicationDose, error) {
+	var doses []*model.MedicationDose
+	err := r.db.WithContext(ctx).Preload("Plan").
+		Joins("JOIN medication_plans ON medication_plans.id = medication_doses.plan_id").
+		Where("medication_doses.status = ? AND medication_doses.reminded_at IS NULL", model.DoseStatusScheduled).
+		Where("medication_plans.status = ?", model.MedicationStatusActive).
+		Where("medication_doses.scheduled_at <= DATE_ADD(?, INTERVAL medication_plans.remind_before_min MINUTE)", now).
+		Order("medication_doses.scheduled_at ASC").
+		Limit(limit).
+		Find(&doses).Error
+	if err != nil {
+		logger.Error(ctx, "查询待提醒给药记录失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return doses, nil
+}
+
+// ClaimReminder 占用提醒发送权,多实例部署时保证同一记录只提醒一次
+func (r *medicationRepository) ClaimReminder(ctx context.Context, doseID uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.MedicationDose{}).
+		Where("id = ? AND reminded_at IS NULL", doseID).
+		Update("reminded_at", at)
+	if result.Error != nil {
+		logger.Error(ctx, "标记给药提醒失败", logger.Int("id", int(doseID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkMissed 将超过宽限期仍未执行的给药记录标记为漏服
+func (r *medicationRepository) MarkMissed(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.MedicationDose{}).
+		Where("status = ? AND scheduled_at < ?", model.DoseStatusScheduled, before).
+		Update("status", model.DoseStatusMissed)
+	if result.Error != nil {
+		logger.Error(ctx, "标记漏服记录失败", logger.ErrorField(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// CountByPlan 按计划和状态统计给药次数
+func (r *medicationRepository) CountByPlan(ctx context.Context, petID, planID uint, from, to time.Time) ([]*model.DoseStatusCount, error) {
+	var counts []*model.DoseStatusCount
+	err := r.doseScope(ctx, petID, planID, from, to).
+		Select("plan_id, status, COUNT(*) AS count").
+		Group("plan_id, status").
+		Scan(&counts).Error
+	if err != nil {
+		logger.Error(ctx, "统计用药依从性失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountByPeriod 按天或周和状态统计给药次数
+func (r *medicationRepository) CountByPeriod(ctx context.Context, petID, planID uint, from, to time.Time, interval string) ([]*model.DoseStatusCount, error) {
+	var counts []*model.DoseStatusCount
+	err := r.doseScope(ctx, petID, planID, from, to).
+		Select(doseBucketExprs[interval] + " AS bucket, status, COUNT(*) AS count").
+		Group("bucket, status").
+		Order("bucket ASC").
+		Scan(&counts).Error
+	if err != nil {
+		logger.Error(ctx, "统计用药依从性失败", logger.Int("pet_id", int(petID)), logger.String("interval", interval), logger.ErrorField(err))
+		return nil, err
+	}
+	return counts, nil
+}
+
+// doseScope 依从性统计的公共查询条件
+func (r *medicationRepository) doseScope(ctx context.Context, petID, planID uint, from, to time.Time) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.MedicationDose{}).
+		Where("pet_id = ? AND scheduled_at BETWEEN ? AND ?", petID, from, to)
+	if planID > 0 {
+		query = query.Where("plan_id = ?", planID)
+	}
+	return query
+}
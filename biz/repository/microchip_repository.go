@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// MicrochipRepository 芯片查询审计与留言仓储接口
+type MicrochipRepository interface {
+	CreateLookup(ctx context.Context, lookup *model.MicrochipLookup) error
+	ListLookupsByPet(ctx context.Context, petID uint, offset, limit int) ([]*model.MicrochipLookup, int64, error)
+	CreateMessage(ctx context.Context, message *model.FoundPetMessage) error
+	ListMessagesByPet(ctx context.Context, petID uint, offset, limit int) ([]*model.FoundPetMessage, int64, error)
+	MarkMessagesRead(ctx context.Context, petID uint, ids []uint) error
+}
+
+// microchipRepository 芯片查询审计与留言仓储实现
+type microchipRepository struct {
+	db *gorm.DB
+}
+
+// NewMicrochipRepository 创建芯片仓储
+func NewMicrochipRepository(db *gorm.DB) MicrochipRepository {
+	return &microchipRepository{db: db}
+}
+
+// CreateLookup 写入查询审计记录
+func (r *microchipRepository) CreateLookup(ctx context.Context, lookup *model.MicrochipLookup) error {
+	if err := r.db.WithContext(ctx).Create(lookup).Error; err != nil {
+		logger.Error(ctx, "写入芯片查询记录失败", logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// ListLookupsByPet 获取宠物芯片被查询的记录
+func (r *microchipRepository) ListLookupsByPet(ctx context.Context, petID uint, offset, limit int) ([]*model.MicrochipLookup, int64, error) {
+	var lookups []*model.MicrochipLookup
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.MicrochipLookup{}).Where("pet_id = ?", petID)
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取芯片查询记录总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&lookups).Error; err != nil {
+		logger.Error(ctx, "获取芯片查询记录失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return lookups, total, nil
+}
+
+// CreateMessage 保存拾获人留言
+func (r *microchipRepository) CreateMessage(ctx context.Context, message *model.FoundPetMessage) error {
+	if err := r.db.WithContext(ctx).Create(message).Error; err != nil {
+		logger.Error(ctx, "保存拾获留言失败", logger.Int("pet_id", int(message.PetID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "保存拾获留言成功", logger.Int("id", int(message.ID)), logger.Int("pet_id", int(message.PetID)))
+	return nil
+}
+
+// ListMessagesByPet 获取宠物收到的拾获留言
+func (r *microchipRepository) ListMessagesByPet(ctx context.Context, petID uint, offset, limit int) ([]*model.FoundPetMessage, int64, error) {
+	var messages []*model.FoundPetMessage
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.FoundPetMessage{}).Where("pet_id = ?", petID)
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取拾获留言总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&messages).Error; err != nil {
+		logger.Error(ctx, "获取拾获留言失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// MarkMessagesRead 标记留言已读
+func (r *microchipRepository) MarkMessagesRead(ctx context.Context, petID uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Model(&model.FoundPetMessage{}).
+		Where("pet_id = ? AND id IN ? AND is_read = ?", petID, ids, false).
+		Update("is_read", true).Error
+	if err != nil {
+		logger.Error(ctx, "标记拾获留言已读失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
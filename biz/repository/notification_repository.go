@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// NotificationRepository 站内通知仓储接口
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *model.Notification) error
+	GetByID(ctx context.Context, id uint) (*model.Notification, error)
+	List(ctx context.Context, userID uint, unreadOnly bool, notifyType string, offset, limit int) ([]*model.Notification, int64, error)
+	ListAfter(ctx context.Context, userID, afterID uint, limit int) ([]*model.Notification, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, userID, id uint) error
+	MarkAllRead(ctx context.Context, userID uint, notifyType string) (int64, error)
+
+	GetPreference(ctx context.Context, userID uint, notifyType string) (*model.NotificationPreference, error)
+	ListPreferences(ctx context.Context, userID uint) ([]*model.NotificationPreference, error)
+	SavePreferences(ctx context.Context, preferences []*model.NotificationPreference) error
+}
+
+// notificationRepository 站内通知仓储实现
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建站内通知仓储
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create 创建通知
+func (r *notificationRepository) Create(ctx context.Context, notification *model.Notification) error {
+	if err := r.db.WithContext(ctx).Create(notification).Error; err != nil {
+		logger.Error(ctx, "创建通知失败", logger.Int("user_id", int(notification.UserID)), logger.String("type", notification.Type), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 根据ID获取通知
+func (r *notificationRepository) GetByID(ctx context.Context, id uint) (*model.Notification, error) {
+	var notification model.Notification
+	if err := r.db.WithContext(ctx).First(&notification, id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// List 分页获取用户的通知,按时间倒序
+func (r *notificationRepository) List(ctx context.Context, userID uint, unreadOnly bool, notifyType string, offset, limit int) ([]*model.Notification, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = 0")
+	}
+	if notifyType != "" {
+		query = query.Where("type = ?", notifyType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "统计通知失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+
+	var notifications []*model.Notification
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
+		logger.Error(ctx, "获取通知列表失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// ListAfter 获取指定ID之后的通知,按ID正序,用于断线重连后补发
+func (r *notificationRepository) ListAfter(ctx context.Context, userID, afterID uint, limit int) ([]*model.Notification, error) {
+	var notifications []*model.Notification
+	err := r.db.WithContext(ctx).Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id ASC").Limit(limit).Find(&notifications).Error
+	if err != nil {
+		logger.Error(ctx, "获取补发通知失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// CountUnread 统计未读通知数
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.Notification{}).Where("user_id = ? AND is_read = 0", userID).Count(&count).Error; err != nil {
+		logger.Error(ctx, "统计未读通知失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkRead 标记单条通知已读,已读的通知不重复更新
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id uint) error {
+	err := r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("id = ? AND user_id = ? AND is_read = 0", id, userID).
+		Updates(map[string]interface{}{"is_read": 1, "read_at": time.Now()}).Error
+	if err != nil {
+		logger.Error(ctx, "标记通知已读失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead 标记用户全部未读通知为已读,返回更新条数
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uint, notifyType string) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Notification{}).Where("user_id = ? AND is_read = 0", userID)
+	if notifyType != "" {
+		query = query.Where("type = ?", notifyType)
+	}
+	result := query.Updates(map[string]interface{}{"is_read": 1, "read_at": time.Now()})
+	if result.Error != nil {
+		logger.Error(ctx, "全部标记已读失败", logger.Int("user_id", int(userID)), logger.ErrorField(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// GetPreference 获取用户对某类通知的渠道设置
+func (r *notificationRepository) GetPreference(ctx context.Context, userID uint, notifyType string) (*model.NotificationPreference, error) {
+	var preference model.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND type = ?", userID, notifyType).First(&preference).Error; err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// ListPreferences 获取用户的全部渠道设置
+func (r *notificationRepository) ListPreferences(ctx context.Context, userID uint) ([]*model.NotificationPreference, error) {
+	var preferences []*model.NotificationPreference
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&preferences).Error; err != nil {
+		logger.Error(ctx, "获取通知偏好失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// SavePreferences 批量保存渠道设置,已存在的类型覆盖
+func (r *notificationRepository) SavePreferences(ctx context.Context, preferences []*model.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channels", "updated_at"}),
+	}).Create(&preferences).Error
+	if err != nil {
+		logger.Error(ctx, "保存通知偏好失败", logger.Int("user_id", int(preferences[0].UserID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,454 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// OrderFilter 订单检索条件
+type OrderFilter struct {
+	UserID uint
+	Status string
+	Type   string
+	Offset int
+	Limit  int
+}
+
+// OrderRepository 订单仓储接口
+type OrderRepository interface {
+	Create(ctx context.Context, order *model.Order, reservationID uint) error
+	GetByID(ctx context.Context, id uint) (*model.Order, error)
+	GetByNo(ctx context.Context, orderNo string) (*model.Order, error)
+	HasActiveOrder(ctx context.Context, orderType string, sourceID uint) (bool, error)
+	List(ctx context.Context, filter *OrderFilter) ([]*model.Order, int64, error)
+	ListExpiredPending(ctx context.Context, now time.Time, limit int) ([]*model.Order, error)
+
+	SetPayment(ctx context.Context, id uint, gateway, tradeNo string) error
+	MarkPaid(ctx context.Context, orderNo, tradeNo string, paidAt time.Time) (bool, error)
+	RecordLatePayment(ctx context.Context, orderNo, tradeNo string, paidAt time.Time) (bool, error)
+	Cancel(ctx context.Context, order *model.Order, reason string) error
+	Fulfill(ctx context.Context, id uint) error
+
+	ReserveRefund(ctx context.Context, refund *model.Refund) error
+	CompleteRefund(ctx context.Context, refund *model.Refund) error
+	FailRefund(ctx context.Context, refund *model.Refund) error
+	GetRefundByNo(ctx context.Context, refundNo string) (*model.Refund, error)
+
+	EventProcessed(ctx context.Context, gateway, eventID string) (bool, error)
+	SaveEvent(ctx context.Context, event *model.PaymentEvent) error
+}
+
+// orderRepository 订单仓储实现
+type orderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository 创建订单仓储
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepository{db: db}
+}
+
+// Create 创建订单及明细,并在同一事务中核销优惠券、扣减积分
+// reservationID不为0时同时消耗库存预占(商城订单库存在结算时已扣减)
+func (r *orderRepository) Create(ctx context.Context, order *model.Order, reservationID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if reservationID != 0 {
+			result := tx.Model(&model.StockReservation{}).
+				Where("id = ? AND status = ? AND expires_at > ?", reservationID, model.ReservationStatusReserved, time.Now()).
+				Update("status", model.ReservationStatusConsumed)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("库存预占已失效,请重新结算")
+			}
+		}
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if order.CouponID != 0 {
+			if err := redeemCoupon(tx, order.CouponID, order.UserID, order.ID, order.CouponDiscount); err != nil {
+				return err
+			}
+		}
+		if order.PointsUsed > 0 {
+			_, err := changePoints(tx, &model.PointsTransaction{
+				UserID:     order.UserID,
+				Type:       model.PointsRedeem,
+				SourceType: model.PointsSourceOrder,
+				SourceID:   order.ID,
+				Delta:      -order.PointsUsed,
+				Remark:     "订单" + order.OrderNo + "抵扣",
+			}, false)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "创建订单失败", logger.Int("user_id", int(order.UserID)), logger.String("type", order.Type), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建订单成功", logger.String("order_no", order.OrderNo), logger.Int64("pay_amount", order.PayAmount))
+	return nil
+}
+
+// GetByID 获取订单,包含明细和退款记录
+func (r *orderRepository) GetByID(ctx context.Context, id uint) (*model.Order, error) {
+	var order model.Order
+	err := r.db.WithContext(ctx).Preload("Items").Preload("Refunds").Where("id = ?", id).First(&order).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取订单失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetByNo 根据订单号获取订单
+func (r *orderRepository) GetByNo(ctx context.Context, orderNo string) (*model.Order, error) {
+	var order model.Order
+	err := r.db.WithContext(ctx).Preload("Items").Where("order_no = ?", orderNo).First(&order).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "根据订单号获取订单失败", logger.String("order_no", orderNo), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// HasActiveOrder 判断业务记录是否已有待支付或已支付的订单
+func (r *orderRepository) HasActiveOrder(ctx context.Context, orderType string, sourceID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("type = ? AND source_id = ? AND status IN ?", orderType, sourceID,
+			[]string{model.OrderStatusPending, model.OrderStatusPaid, model.OrderStatusFulfilled}).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "检查订单失败", logger.String("type", orderType), logger.Int("source_id", int(sourceID)), logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// List 订单列表
+func (r *orderRepository) List(ctx context.Context, filter *OrderFilter) ([]*model.Order, int64, error) {
+	var orders []*model.Order
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Order{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取订单总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+
+	err := query.Preload("Items").Offset(filter.Offset).Limit(filter.Limit).Order("id DESC").Find(&orders).Error
+	if err != nil {
+		logger.Error(ctx, "获取订单列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// ListExpiredPending 获取超过支付截止时间仍未支付的订单
+func (r *orderRepository) ListExpiredPending(ctx context.Context, now time.Time, limit int) ([]*model.Order, error) {
+	var orders []*model.Order
+	err := r.db.WithContext(ctx).Preload("Items").
+		Where("status = ? AND expires_at <= ?", model.OrderStatusPending, now).
+		Order("expires_at ASC").Limit(limit).Find(&orders).Error
+	if err != nil {
+		logger.Error(ctx, "获取超时订单失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return orders, nil
+}
+
+// SetPayment 记录发起支付使用的网关和交易号
+func (r *orderRepository) SetPayment(ctx context.Context, id uint, gateway, tradeNo string) error {
+	result := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("id = ? AND status = ?", id, model.OrderStatusPending).
+		Updates(map[string]interface{}{"gateway": gateway, "trade_no": tradeNo})
+	if result.Error != nil {
+		logger.Error(ctx, "记录支付交易失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("订单状态已变更,请刷新后重试")
+	}
+	return nil
+}
+
+// MarkPaid 将待支付订单标记为已支付,订单不处于待支付状态时返回false
+func (r *orderRepository) MarkPaid(ctx context.Context, orderNo, tradeNo string, paidAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("order_no = ? AND status = ?", orderNo, model.OrderStatusPending).
+		Updates(map[string]interface{}{
+			"status":   model.OrderStatusPaid,
+			"trade_no": tradeNo,
+			"paid_at":  paidAt,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新订单支付状态失败", logger.String("order_no", orderNo), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	logger.Info(ctx, "订单支付成功", logger.String("order_no", orderNo))
+	return true, nil
+}
+
+// RecordLatePayment 记录订单取消后才到达的付款,用于后续原路退回,已记录过时返回false
+func (r *orderRepository) RecordLatePayment(ctx context.Context, orderNo, tradeNo string, paidAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("order_no = ? AND status = ? AND paid_at IS NULL", orderNo, model.OrderStatusCancelled).
+		Updates(map[string]interface{}{
+			"trade_no": tradeNo,
+			"paid_at":  paidAt,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "记录取消后付款失败", logger.String("order_no", orderNo), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		logger.Warn(ctx, "订单取消后收到付款", logger.String("order_no", orderNo))
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Cancel 取消待支付订单,退回优惠券和抵扣的积分,商城订单同时归还库存
+func (r *orderRepository) Cancel(ctx context.Context, order *model.Order, reason string) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", order.ID, model.OrderStatusPending).
+			Updates(map[string]interface{}{
+				"status":        model.OrderStatusCancelled,
+				"cancelled_at":  now,
+				"cancel_reason": reason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("订单状态已变更,请刷新后重试")
+		}
+		if err := releaseCoupon(tx, order.ID); err != nil {
+			return err
+		}
+		if err := returnPoints(tx, order); err != nil {
+			return err
+		}
+		if order.Type != model.OrderTypeShop {
+			return nil
+		}
+		quantities := make(map[uint]int, len(order.Items))
+		for _, item := range order.Items {
+			quantities[item.SKUID] += item.Quantity
+		}
+		return restoreStock(tx, quantities)
+	})
+	if err != nil {
+		logger.Error(ctx, "取消订单失败", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+		return err
+	}
+	order.Status = model.OrderStatusCancelled
+	order.CancelledAt = &now
+	order.CancelReason = reason
+	logger.Info(ctx, "取消订单成功", logger.String("order_no", order.OrderNo), logger.String("reason", reason))
+	return nil
+}
+
+// Fulfill 已支付订单标记为已完成
+func (r *orderRepository) Fulfill(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("id = ? AND status = ?", id, model.OrderStatusPaid).
+		Updates(map[string]interface{}{
+			"status":       model.OrderStatusFulfilled,
+			"fulfilled_at": time.Now(),
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "完成订单失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("订单状态已变更,请刷新后重试")
+	}
+	return nil
+}
+
+// ReserveRefund 占用订单可退金额并创建退款记录,并发退款不会超过实付金额
+// 已取消但收到付款的订单也可以退款
+func (r *orderRepository) ReserveRefund(ctx context.Context, refund *model.Refund) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND refunded_amount + ? <= pay_amount", refund.OrderID, refund.Amount).
+			Where("status IN ? OR (status = ? AND paid_at IS NOT NULL)",
+				[]string{model.OrderStatusPaid, model.OrderStatusFulfilled}, model.OrderStatusCancelled).
+			Update("refunded_amount", gorm.Expr("refunded_amount + ?", refund.Amount))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("订单可退款金额不足")
+		}
+		return tx.Create(refund).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "创建退款失败", logger.Int("order_id", int(refund.OrderID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// CompleteRefund 退款成功,已支付订单全额退款后状态变为已退款,同时退回抵扣积分并扣回获得的积分
+func (r *orderRepository) CompleteRefund(ctx context.Context, refund *model.Refund) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Refund{}).
+			Where("id = ? AND status = ?", refund.ID, model.RefundStatusPending).
+			Updates(map[string]interface{}{
+				"status":            model.RefundStatusSucceeded,
+				"gateway_refund_no": refund.GatewayRefundNo,
+				"succeeded_at":      now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("退款状态已变更")
+		}
+		result = tx.Model(&model.Order{}).
+			Where("id = ? AND status IN ? AND refunded_amount >= pay_amount",
+				refund.OrderID, []string{model.OrderStatusPaid, model.OrderStatusFulfilled}).
+			Where("NOT EXISTS (SELECT 1 FROM refunds WHERE refunds.order_id = orders.id AND refunds.status = ?)", model.RefundStatusPending).
+			Update("status", model.OrderStatusRefunded)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var order model.Order
+		if err := tx.Where("id = ?", refund.OrderID).First(&order).Error; err != nil {
+			return err
+		}
+		if err := returnPoints(tx, &order); err != nil {
+			return err
+		}
+		var earned model.PointsTransaction
+		err := tx.Where("type = ? AND source_type = ? AND source_id = ?", model.PointsEarnOrder, model.PointsSourceOrder, order.ID).First(&earned).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		// 获得的积分可能已被使用,最多扣到0
+		_, err = changePoints(tx, &model.PointsTransaction{
+			UserID:     order.UserID,
+			Type:       model.PointsRevoke,
+			SourceType: model.PointsSourceOrder,
+			SourceID:   order.ID,
+			Delta:      -earned.Delta,
+			Remark:     "订单" + order.OrderNo + "退款扣回",
+		}, true)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "更新退款成功状态失败", logger.String("refund_no", refund.RefundNo), logger.ErrorField(err))
+		return err
+	}
+	refund.Status = model.RefundStatusSucceeded
+	refund.SucceededAt = &now
+	logger.Info(ctx, "退款成功", logger.String("refund_no", refund.RefundNo), logger.Int64("amount", refund.Amount))
+	return nil
+}
+
+// FailRefund 退款失败,释放占用的可退金额
+func (r *orderRepository) FailRefund(ctx context.Context, refund *model.Refund) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Refund{}).
+			Where("id = ? AND status = ?", refund.ID, model.RefundStatusPending).
+			Updates(map[string]interface{}{
+				"status":      model.RefundStatusFailed,
+				"fail_reason": refund.FailReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("退款状态已变更")
+		}
+		return tx.Model(&model.Order{}).Where("id = ?", refund.OrderID).
+			Update("refunded_amount", gorm.Expr("refunded_amount - ?", refund.Amount)).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "更新退款失败状态失败", logger.String("refund_no", refund.RefundNo), logger.ErrorField(err))
+		return err
+	}
+	refund.Status = model.RefundStatusFailed
+	return nil
+}
+
+// GetRefundByNo 根据退款单号获取退款记录
+func (r *orderRepository) GetRefundByNo(ctx context.Context, refundNo string) (*model.Refund, error) {
+	var refund model.Refund
+	if err := r.db.WithContext(ctx).Where("refund_no = ?", refundNo).First(&refund).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// EventProcessed 判断回调事件是否已处理
+func (r *orderRepository) EventProcessed(ctx context.Context, gateway, eventID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PaymentEvent{}).
+		Where("gateway = ? AND event_id = ?", gateway, eventID).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询支付回调记录失败", logger.String("event_id", eventID), logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SaveEvent 记录已处理的回调事件,重复事件忽略
+func (r *orderRepository) SaveEvent(ctx context.Context, event *model.PaymentEvent) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+	if err != nil {
+		logger.Error(ctx, "记录支付回调失败", logger.String("event_id", event.EventID), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// returnPoints 退回订单抵扣的积分
+func returnPoints(tx *gorm.DB, order *model.Order) error {
+	if order.PointsUsed <= 0 {
+		return nil
+	}
+	_, err := changePoints(tx, &model.PointsTransaction{
+		UserID:     order.UserID,
+		Type:       model.PointsReturn,
+		SourceType: model.PointsSourceOrder,
+		SourceID:   order.ID,
+		Delta:      order.PointsUsed,
+		Remark:     "订单" + order.OrderNo + "退回抵扣",
+	}, false)
+	return err
+}
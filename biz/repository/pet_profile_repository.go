@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// PetProfileRepository 宠物公开主页仓储接口
+type PetProfileRepository interface {
+	Create(ctx context.Context, profile *model.PetProfile) error
+	Update(ctx context.Context, profile *model.PetProfile) error
+	GetByPetID(ctx context.Context, petID uint) (*model.PetProfile, error)
+	GetBySlug(ctx context.Context, slug string) (*model.PetProfile, error)
+}
+
+// petProfileRepository 宠物公开主页仓储实现
+type petProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewPetProfileRepository 创建宠物公开主页仓储
+func NewPetProfileRepository(db *gorm.DB) PetProfileRepository {
+	return &petProfileRepository{db: db}
+}
+
+// Create 创建公开主页设置
+func (r *petProfileRepository) Create(ctx context.Context, profile *model.PetProfile) error {
+	if err := r.db.WithContext(ctx).Create(profile).Error; err != nil {
+		logger.Error(ctx, "创建公开主页失败", logger.Int("pet_id", int(profile.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Update 更新公开主页设置
+func (r *petProfileRepository) Update(ctx context.Context, profile *model.PetProfile) error {
+	// 使用Select("*")使关闭的开关也能写入
+	err := r.db.WithContext(ctx).Model(&model.PetProfile{}).Where("id = ?", profile.ID).
+		Select("*").Omit("id", "created_at", "pet_id").Updates(profile).Error
+	if err != nil {
+		logger.Error(ctx, "更新公开主页失败", logger.Int("pet_id", int(profile.PetID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByPetID 根据宠物ID获取公开主页设置
+func (r *petProfileRepository) GetByPetID(ctx context.Context, petID uint) (*model.PetProfile, error) {
+	var profile model.PetProfile
+	err := r.db.WithContext(ctx).Where("pet_id = ?", petID).First(&profile).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取公开主页失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetBySlug 根据slug获取公开主页设置
+func (r *petProfileRepository) GetBySlug(ctx context.Context, slug string) (*model.PetProfile, error) {
+	var profile model.PetProfile
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&profile).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "根据slug获取公开主页失败", logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// PetRepository 宠物仓储接口
+type PetRepository interface {
+	Create(ctx context.Context, pet *model.Pet) error
+	Update(ctx context.Context, pet *model.Pet) error
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*model.Pet, error)
+	GetByMicrochip(ctx context.Context, microchip string) (*model.Pet, error)
+	ListAccessible(ctx context.Context, userID uint, species string, offset, limit int) ([]*model.Pet, int64, error)
+}
+
+// petRepository 宠物仓储实现
+type petRepository struct {
+	db *gorm.DB
+}
+
+// NewPetRepository 创建宠物仓储
+func NewPetRepository(db *gorm.DB) PetRepository {
+	return &petRepository{db: db}
+}
+
+// Create 创建宠物
+func (r *petRepository) Create(ctx context.Context, pet *model.Pet) error {
+	if err := r.db.WithContext(ctx).Create(pet).Error; err != nil {
+		logger.Error(ctx, "创建宠物失败", logger.String("name", pet.Name), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建宠物成功", logger.Int("id", int(pet.ID)))
+	return nil
+}
+
+// Update 更新宠物
+func (r *petRepository) Update(ctx context.Context, pet *model.Pet) error {
+	if err := r.db.WithContext(ctx).Save(pet).Error; err != nil {
+		logger.Error(ctx, "更新宠物失败", logger.Int("id", int(pet.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Delete 删除宠物(软删除),同时释放芯片号以便重新登记
+func (r *petRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.Pet{}).Where("id = ? AND is_deleted = 0", id).
+		Updates(map[string]interface{}{"is_deleted": 1, "microchip": nil})
+	if result.Error != nil {
+		logger.Error(ctx, "删除宠物失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	logger.Info(ctx, "删除宠物成功", logger.Int("id", int(id)))
+	return nil
+}
+
+// GetByID 根据ID获取宠物
+func (r *petRepository) GetByID(ctx context.Context, id uint) (*model.Pet, error) {
+	var pet model.Pet
+	err := r.db.WithContext(ctx).Where("id = ? AND is_deleted = 0", id).First(&pet).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取宠物失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &pet, nil
+}
+
+// GetByMicrochip 根据芯片号获取宠物
+func (r *petRepository) GetByMicrochip(ctx context.Context, microchip string) (*model.Pet, error) {
+	var pet model.Pet
+	err := r.db.WithContext(ctx).Where("microchip = ? AND is_deleted = 0", microchip).First(&pet).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "根据芯片号获取宠物失败", logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &pet, nil
+}
+
+// ListAccessible 获取用户作为主人或共享成员的宠物列表
+func (r *petRepository) ListAccessible(ctx context.Context, userID uint, species string, offset, limit int) ([]*model.Pet, int64, error) {
+	var pets []*model.Pet
+	var total int64
+
+	memberPets := r.db.Model(&model.PetMember{}).Select("pet_id").Where("user_id = ?", userID)
+	query := r.db.WithContext(ctx).Model(&model.Pet{}).
+		Where("(owner_id = ? OR id IN (?)) AND is_deleted = 0", userID, memberPets)
+	if species != "" {
+		query = query.Where("species = ?", species)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取宠物总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&pets).Error; err != nil {
+		logger.Error(ctx, "获取宠物列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return pets, total, nil
+}
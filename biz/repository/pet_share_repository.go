@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// PetShareRepository 宠物共享仓储接口,包含成员、邀请与主人转让
+type PetShareRepository interface {
+	GetMember(ctx context.Context, petID, userID uint) (*model.PetMember, error)
+	ListMembers(ctx context.Context, petID uint) ([]*model.PetMember, error)
+	ListRolesByUser(ctx context.Context, userID uint, petIDs []uint) (map[uint]string, error)
+	UpdateMemberRole(ctx context.Context, petID, userID uint, role string) error
+	DeleteMember(ctx context.Context, petID, userID uint) error
+
+	CreateInvitation(ctx context.Context, invitation *model.PetInvitation) error
+	GetInvitation(ctx context.Context, id uint) (*model.PetInvitation, error)
+	HasPendingInvitation(ctx context.Context, petID, inviteeID uint) (bool, error)
+	ListInvitationsByInvitee(ctx context.Context, inviteeID uint, status string) ([]*model.PetInvitation, error)
+	ListInvitationsByPet(ctx context.Context, petID uint, status string) ([]*model.PetInvitation, error)
+	UpdateInvitationStatus(ctx context.Context, invitation *model.PetInvitation, fromStatus string) error
+	AcceptInvitation(ctx context.Context, invitation *model.PetInvitation) error
+
+	CreateTransfer(ctx context.Context, transfer *model.PetTransfer) error
+	GetTransfer(ctx context.Context, id uint) (*model.PetTransfer, error)
+	HasPendingTransfer(ctx context.Context, petID uint) (bool, error)
+	ListTransfersByUser(ctx context.Context, userID uint, status string) ([]*model.PetTransfer, error)
+	UpdateTransfer(ctx context.Context, transfer *model.PetTransfer) error
+	CompleteTransfer(ctx context.Context, transfer *model.PetTransfer) error
+}
+
+// petShareRepository 宠物共享仓储实现
+type petShareRepository struct {
+	db *gorm.DB
+}
+
+// NewPetShareRepository 创建宠物共享仓储
+func NewPetShareRepository(db *gorm.DB) PetShareRepository {
+	return &petShareRepository{db: db}
+}
+
+// GetMember 获取宠物成员
+func (r *petShareRepository) GetMember(ctx context.Context, petID, userID uint) (*model.PetMember, error) {
+	var member model.PetMember
+	err := r.db.WithContext(ctx).Where("pet_id = ? AND user_id = ?", petID, userID).First(&member).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取宠物成员失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ListMembers 获取宠物的共享成员,仅加载成员的公开信息
+func (r *petShareRepository) ListMembers(ctx context.Context, petID uint) ([]*model.PetMember, error) {
+	var members []*model.PetMember
+	err := r.db.WithContext(ctx).
+		Preload("User", publicUserColumns).
+		Where("pet_id = ?", petID).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		logger.Error(ctx, "获取宠物成员列表失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return members, nil
+}
+
+// ListRolesByUser 批量获取用户在多只宠物上的成员角色
+func (r *petShareRepository) ListRolesByUser(ctx context.Context, userID uint, petIDs []uint) (map[uint]string, error) {
+	roles := make(map[uint]string, len(petIDs))
+	if len(petIDs) == 0 {
+		return roles, nil
+	}
+	var members []*model.PetMember
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND pet_id IN ?", userID, petIDs).Find(&members).Error; err != nil {
+		logger.Error(ctx, "获取成员角色失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	for _, m := range members {
+		roles[m.PetID] = m.Role
+	}
+	return roles, nil
+}
+
+// UpdateMemberRole 修改成员角色
+func (r *petShareRepository) UpdateMemberRole(ctx context.Context, petID, userID uint, role string) error {
+	result := r.db.WithContext(ctx).Model(&model.PetMember{}).
+		Where("pet_id = ? AND user_id = ?", petID, userID).Update("role", role)
+	if result.Error != nil {
+		logger.Error(ctx, "修改成员角色失败", logger.Int("pet_id", int(petID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteMember 移除成员
+func (r *petShareRepository) DeleteMember(ctx context.Context, petID, userID uint) error {
+	result := r.db.WithContext(ctx).Where("pet_id = ? AND user_id = ?", petID, userID).Delete(&model.PetMember{})
+	if result.Error != nil {
+		logger.Error(ctx, "移除宠物成员失败", logger.Int("pet_id", int(petID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	logger.Info(ctx, "移除宠物成员成功", logger.Int("pet_id", int(petID)), logger.Int("user_id", int(userID)))
+	return nil
+}
+
+// CreateInvitation 创建共享邀请
+func (r *petShareRepository) CreateInvitation(ctx context.Context, invitation *model.PetInvitation) error {
+	if err := r.db.WithContext(ctx).Create(invitation).Error; err != nil {
+		logger.Error(ctx, "创建共享邀请失败", logger.Int("pet_id", int(invitation.PetID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建共享邀请成功", logger.Int("id", int(invitation.ID)))
+	return nil
+}
+
+// GetInvitation 获取共享邀请
+func (r *petShareRepository) GetInvitation(ctx context.Context, id uint) (*model.PetInvitation, error) {
+	var invitation model.PetInvitation
+	err := r.db.WithContext(ctx).Preload("Pet").Where("id = ?", id).First(&invitation).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取共享邀请失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// HasPendingInvitation 判断是否已有未过期的待处理邀请
+func (r *petShareRepository) HasPendingInvitation(ctx context.Context, petID, inviteeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PetInvitation{}).
+		Where("pet_id = ? AND invitee_id = ? AND status = ? AND expires_at > ?", petID, inviteeID, model.ShareStatusPending, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询待处理邀请失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListInvitationsByInvitee 获取用户收到的邀请
+func (r *petShareRepository) ListInvitationsByInvitee(ctx context.Context, inviteeID uint, status string) ([]*model.PetInvitation, error) {
+	var invitations []*model.PetInvitation
+	query := r.db.WithContext(ctx).Preload("Pet").Where("invitee_id = ?", inviteeID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Limit(100).Find(&invitations).Error; err != nil {
+		logger.Error(ctx, "获取收到的邀请失败", logger.Int("user_id", int(inviteeID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// ListInvitationsByPet 获取宠物发出的邀请
+func (r *petShareRepository) ListInvitationsByPet(ctx context.Context, petID uint, status string) ([]*model.PetInvitation, error) {
+	var invitations []*model.PetInvitation
+	query := r.db.WithContext(ctx).Where("pet_id = ?", petID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Limit(100).Find(&invitations).Error; err != nil {
+		logger.Error(ctx, "获取宠物邀请失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// UpdateInvitationStatus 基于原状态更新邀请,状态已被并发修改时返回错误
+func (r *petShareRepository) UpdateInvitationStatus(ctx context.Context, invitation *model.PetInvitation, fromStatus string) error {
+	result := r.db.WithContext(ctx).Model(&model.PetInvitation{}).
+		Where("id = ? AND status = ?", invitation.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":       invitation.Status,
+			"responded_at": invitation.RespondedAt,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新邀请状态失败", logger.Int("id", int(invitation.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("邀请状态已变更,请刷新后重试")
+	}
+	return nil
+}
+
+// AcceptInvitation 接受邀请并写入成员,已是成员时更新为邀请中的角色
+func (r *petShareRepository) AcceptInvitation(ctx context.Context, invitation *model.PetInvitation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PetInvitation{}).
+			Where("id = ? AND status = ?", invitation.ID, model.ShareStatusPending).
+			Updates(map[string]interface{}{
+				"status":       model.ShareStatusAccepted,
+				"responded_at": invitation.RespondedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("邀请状态已变更,请刷新后重试")
+		}
+
+		member := &model.PetMember{
+			PetID:     invitation.PetID,
+			UserID:    invitation.InviteeID,
+			Role:      invitation.Role,
+			InvitedBy: invitation.InviterID,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "pet_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by", "updated_at"}),
+		}).Create(member).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "接受共享邀请失败", logger.Int("id", int(invitation.ID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "接受共享邀请成功", logger.Int("id", int(invitation.ID)), logger.Int("pet_id", int(invitation.PetID)))
+	return nil
+}
+
+// CreateTransfer 创建主人转让
+func (r *petShareRepository) CreateTransfer(ctx context.Context, transfer *model.PetTransfer) error {
+	if err := r.db.WithContext(ctx).Create(transfer).Error; err != nil {
+		logger.Error(ctx, "创建主人转让失败", logger.Int("pet_id", int(transfer.PetID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建主人转让成功", logger.Int("id", int(transfer.ID)))
+	return nil
+}
+
+// GetTransfer 获取主人转让
+func (r *petShareRepository) GetTransfer(ctx context.Context, id uint) (*model.PetTransfer, error) {
+	var transfer model.PetTransfer
+	err := r.db.WithContext(ctx).Preload("Pet").Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取主人转让失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// HasPendingTransfer 判断宠物是否有进行中的转让
+func (r *petShareRepository) HasPendingTransfer(ctx context.Context, petID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.PetTransfer{}).
+		Where("pet_id = ? AND status = ?", petID, model.ShareStatusPending).
+		Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "查询进行中的转让失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListTransfersByUser 获取用户参与的转让
+func (r *petShareRepository) ListTransfersByUser(ctx context.Context, userID uint, status string) ([]*model.PetTransfer, error) {
+	var transfers []*model.PetTransfer
+	query := r.db.WithContext(ctx).Preload("Pet").Where("from_user_id = ? OR to_user_id = ?", userID, userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Limit(100).Find(&transfers).Error; err != nil {
+		logger.Error(ctx, "获取主人转让列表失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// UpdateTransfer 更新进行中的转让(确认标记或状态)
+func (r *petShareRepository) UpdateTransfer(ctx context.Context, transfer *model.PetTransfer) error {
+	result := r.db.WithContext(ctx).Model(&model.PetTransfer{}).
+		Where("id = ? AND status = ?", transfer.ID, model.ShareStatusPending).
+		Updates(map[string]interface{}{
+			"from_confirmed": transfer.FromConfirmed,
+			"to_confirmed":   transfer.ToConfirmed,
+			"status":         transfer.Status,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新主人转让失败", logger.Int("id", int(transfer.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("转让状态已变更,请刷新后重试")
+	}
+	return nil
+}
+
+// CompleteTransfer 完成转让:更新宠物主人,接收方不再作为共享成员,原主人移出成员
+func (r *petShareRepository) CompleteTransfer(ctx context.Context, transfer *model.PetTransfer) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PetTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, model.ShareStatusPending).
+			Updates(map[string]interface{}{
+				"from_confirmed": true,
+				"to_confirmed":   true,
+				"status":         model.ShareStatusAccepted,
+				"completed_at":   transfer.CompletedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("转让状态已变更,请刷新后重试")
+		}
+
+		result = tx.Model(&model.Pet{}).
+			Where("id = ? AND owner_id = ? AND is_deleted = 0", transfer.PetID, transfer.FromUserID).
+			Update("owner_id", transfer.ToUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("宠物主人已变更,转让失效")
+		}
+
+		return tx.Where("pet_id = ? AND user_id IN ?", transfer.PetID, []uint{transfer.FromUserID, transfer.ToUserID}).
+			Delete(&model.PetMember{}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "完成主人转让失败", logger.Int("id", int(transfer.ID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "完成主人转让", logger.Int("id", int(transfer.ID)), logger.Int("pet_id", int(transfer.PetID)))
+	return nil
+}
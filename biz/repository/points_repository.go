@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ErrInsufficientPoints 积分不足
+var ErrInsufficientPoints = errors.New("积分不足")
+
+// PointsRepository 积分仓储接口
+type PointsRepository interface {
+	Change(ctx context.Context, tx *model.PointsTransaction) (bool, error)
+	GetAccount(ctx context.Context, userID uint) (*model.UserPoints, error)
+	ListTransactions(ctx context.Context, userID uint, txType string, offset, limit int) ([]*model.PointsTransaction, int64, error)
+}
+
+// pointsRepository 积分仓储实现
+type pointsRepository struct {
+	db *gorm.DB
+}
+
+// NewPointsRepository 创建积分仓储
+func NewPointsRepository(db *gorm.DB) PointsRepository {
+	return &pointsRepository{db: db}
+}
+
+// Change 记一笔积分流水并更新余额,同一来源的同类流水已存在时返回false
+func (r *pointsRepository) Change(ctx context.Context, entry *model.PointsTransaction) (bool, error) {
+	var applied bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = changePoints(tx, entry, false)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInsufficientPoints) {
+			logger.Error(ctx, "积分变动失败", logger.Int("user_id", int(entry.UserID)), logger.String("type", entry.Type), logger.ErrorField(err))
+		}
+		return false, err
+	}
+	if applied {
+		logger.Info(ctx, "积分变动",
+			logger.Int("user_id", int(entry.UserID)),
+			logger.String("type", entry.Type),
+			logger.Int64("delta", entry.Delta),
+			logger.Int64("balance", entry.BalanceAfter),
+		)
+	}
+	return applied, nil
+}
+
+// GetAccount 获取积分账户,未开户时返回零余额
+func (r *pointsRepository) GetAccount(ctx context.Context, userID uint) (*model.UserPoints, error) {
+	var account model.UserPoints
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&account).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.UserPoints{UserID: userID}, nil
+		}
+		logger.Error(ctx, "获取积分账户失败", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return &account, nil
+}
+
+// ListTransactions 积分流水,按时间倒序
+func (r *pointsRepository) ListTransactions(ctx context.Context, userID uint, txType string, offset, limit int) ([]*model.PointsTransaction, int64, error) {
+	var entries []*model.PointsTransaction
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.PointsTransaction{}).Where("user_id = ?", userID)
+	if txType != "" {
+		query = query.Where("type = ?", txType)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取积分流水总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Offset(offset).Limit(limit).Order("id DESC").Find(&entries).Error; err != nil {
+		logger.Error(ctx, "获取积分流水失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// changePoints 在事务中变动积分:锁定账户后写流水再更新余额
+// 扣减超过余额时,partial为true则扣到0为止,否则返回ErrInsufficientPoints
+func changePoints(tx *gorm.DB, entry *model.PointsTransaction, partial bool) (bool, error) {
+	if entry.Delta == 0 {
+		return false, nil
+	}
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.UserPoints{UserID: entry.UserID}).Error
+	if err != nil {
+		return false, err
+	}
+	var account model.UserPoints
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", entry.UserID).First(&account).Error
+	if err != nil {
+		return false, err
+	}
+
+	if account.Balance+entry.Delta < 0 {
+		if !partial {
+			return false, ErrInsufficientPoints
+		}
+		entry.Delta = -account.Balance
+		if entry.Delta == 0 {
+			return false, nil
+		}
+	}
+	entry.BalanceAfter = account.Balance + entry.Delta
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	updates := map[string]interface{}{"balance": entry.BalanceAfter}
+	if entry.Type == model.PointsEarnOrder || entry.Type == model.PointsEarnAppointment {
+		updates["total_earned"] = gorm.Expr("total_earned + ?", entry.Delta)
+	}
+	if err := tx.Model(&model.UserPoints{}).Where("user_id = ?", entry.UserID).Updates(updates).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// PostFilter 动态检索条件,按ID倒序游标分页
+type PostFilter struct {
+	UserID     uint
+	PetID      uint
+	FollowerID uint // 不为0时查询该用户、其关注用户以及标记了其关注宠物的动态(时间线)
+	ViewerID   uint // 不为0时排除与该用户互相拉黑的用户发布的动态
+	Cursor     uint
+	Limit      int
+}
+
+// PostRepository 动态仓储接口
+type PostRepository interface {
+	Create(ctx context.Context, post *model.Post) error
+	GetByID(ctx context.Context, id uint) (*model.Post, error)
+	List(ctx context.Context, filter *PostFilter) ([]*model.Post, error)
+	Delete(ctx context.Context, id uint) error
+
+	Like(ctx context.Context, postID, userID uint) (bool, error)
+	Unlike(ctx context.Context, postID, userID uint) (bool, error)
+	LikedPostIDs(ctx context.Context, userID uint, postIDs []uint) (map[uint]bool, error)
+	CountStats(ctx context.Context, postIDs []uint) (map[uint]*model.PostStats, error)
+	UpdateStats(ctx context.Context, id uint, stats *model.PostStats) error
+
+	CreateComment(ctx context.Context, comment *model.PostComment) error
+	GetComment(ctx context.Context, id uint) (*model.PostComment, error)
+	DeleteComment(ctx context.Context, comment *model.PostComment) error
+	ListComments(ctx context.Context, postID, viewerID uint, offset, limit int) ([]*model.PostComment, int64, error)
+	ListReplies(ctx context.Context, parentID, viewerID uint, offset, limit int) ([]*model.PostComment, int64, error)
+}
+
+// postRepository 动态仓储实现
+type postRepository struct {
+	db *gorm.DB
+}
+
+// NewPostRepository 创建动态仓储
+func NewPostRepository(db *gorm.DB) PostRepository {
+	return &postRepository{db: db}
+}
+
+// taggedPetColumns 动态中标记的宠物只返回基本信息
+func taggedPetColumns(db *gorm.DB) *gorm.DB {
+	return db.Select("pets.id", "pets.name", "pets.species", "pets.breed", "pets.avatar")
+}
+
+// Create 发布动态,同时写入标记的宠物关联
+func (r *postRepository) Create(ctx context.Context, post *model.Post) error {
+	if err := r.db.WithContext(ctx).Omit("Author", "Pets.*").Create(post).Error; err != nil {
+		logger.Error(ctx, "发布动态失败", logger.Int("user_id", int(post.UserID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "发布动态成功", logger.Int("id", int(post.ID)), logger.Int("user_id", int(post.UserID)))
+	return nil
+}
+
+// GetByID 获取未删除的动态
+func (r *postRepository) GetByID(ctx context.Context, id uint) (*model.Post, error) {
+	var post model.Post
+	err := r.db.WithContext(ctx).
+		Preload("Author", publicUserColumns).
+		Preload("Pets", taggedPetColumns).
+		Where("id = ? AND is_deleted = 0", id).First(&post).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取动态失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &post, nil
+}
+
+// List 动态列表
+func (r *postRepository) List(ctx context.Context, filter *PostFilter) ([]*model.Post, error) {
+	var posts []*model.Post
+
+	query := r.db.WithContext(ctx).Model(&model.Post{}).Where("posts.is_deleted = 0")
+	if filter.UserID != 0 {
+		query = query.Where("posts.user_id = ?", filter.UserID)
+	}
+	if filter.PetID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM post_pets WHERE post_pets.post_id = posts.id AND post_pets.pet_id = ?)", filter.PetID)
+	}
+	if filter.FollowerID != 0 {
+		following := r.db.Model(&model.UserFollow{}).Select("followee_id").Where("follower_id = ?", filter.FollowerID)
+		query = query.Where("posts.user_id = ? OR posts.user_id IN (?) OR EXISTS (SELECT 1 FROM post_pets JOIN pet_follows ON pet_follows.pet_id = post_pets.pet_id WHERE post_pets.post_id = posts.id AND pet_follows.user_id = ?)",
+			filter.FollowerID, following, filter.FollowerID)
+	}
+	if filter.ViewerID != 0 {
+		cond, args := notBlockedWith(filter.ViewerID, "posts.user_id")
+		query = query.Where(cond, args...)
+	}
+	if filter.Cursor != 0 {
+		query = query.Where("posts.id < ?", filter.Cursor)
+	}
+
+	err := query.Preload("Author", publicUserColumns).
+		Preload("Pets", taggedPetColumns).
+		Order("posts.id DESC").Limit(filter.Limit).Find(&posts).Error
+	if err != nil {
+		logger.Error(ctx, "获取动态列表失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// Delete 删除动态
+func (r *postRepository) Delete(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id).Update("is_deleted", 1).Error
+	if err != nil {
+		logger.Error(ctx, "删除动态失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "删除动态成功", logger.Int("id", int(id)))
+	return nil
+}
+
+// Like 点赞,已点赞时返回false
+func (r *postRepository) Like(ctx context.Context, postID, userID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.PostLike{PostID: postID, UserID: userID})
+	if result.Error != nil {
+		logger.Error(ctx, "点赞失败", logger.Int("post_id", int(postID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Unlike 取消点赞,未点赞时返回false
+func (r *postRepository) Unlike(ctx context.Context, postID, userID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("post_id = ? AND user_id = ?", postID, userID).Delete(&model.PostLike{})
+	if result.Error != nil {
+		logger.Error(ctx, "取消点赞失败", logger.Int("post_id", int(postID)), logger.ErrorField(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// LikedPostIDs 用户点赞过的动态
+func (r *postRepository) LikedPostIDs(ctx context.Context, userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.PostLike{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).Pluck("post_id", &ids).Error
+	if err != nil {
+		logger.Error(ctx, "获取点赞状态失败", logger.ErrorField(err))
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
+// CountStats 从明细表统计动态的点赞数和评论数
+func (r *postRepository) CountStats(ctx context.Context, postIDs []uint) (map[uint]*model.PostStats, error) {
+	stats := make(map[uint]*model.PostStats, len(postIDs))
+	if len(postIDs) == 0 {
+		return stats, nil
+	}
+	for _, id := range postIDs {
+		stats[id] = &model.PostStats{}
+	}
+
+	type row struct {
+		PostID uint
+		Total  int64
+	}
+	var likes []row
+	err := r.db.WithContext(ctx).Model(&model.PostLike{}).
+		Select("post_id, COUNT(*) AS total").
+		Where("post_id IN ?", postIDs).Group("post_id").Scan(&likes).Error
+	if err != nil {
+		logger.Error(ctx, "统计点赞数失败", logger.ErrorField(err))
+		return nil, err
+	}
+	for _, item := range likes {
+		stats[item.PostID].LikeCount = item.Total
+	}
+
+	var comments []row
+	err = r.db.WithContext(ctx).Model(&model.PostComment{}).
+		Select("post_id, COUNT(*) AS total").
+		Where("post_id IN ? AND is_deleted = 0", postIDs).Group("post_id").Scan(&comments).Error
+	if err != nil {
+		logger.Error(ctx, "统计评论数失败", logger.ErrorField(err))
+		return nil, err
+	}
+	for _, item := range comments {
+		stats[item.PostID].CommentCount = item.Total
+	}
+	return stats, nil
+}
+
+// UpdateStats 回写动态计数
+func (r *postRepository) UpdateStats(ctx context.Context, id uint, stats *model.PostStats) error {
+	err := r.db.WithContext(ctx).Model(&model.Post{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"like_count":    stats.LikeCount,
+			"comment_count": stats.CommentCount,
+		}).Error
+	if err != nil {
+		logger.Error(ctx, "回写动态计数失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// CreateComment 发表评论,回复同时累加一级评论的回复数
+func (r *postRepository) CreateComment(ctx context.Context, comment *model.PostComment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Author").Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID == 0 {
+			return nil
+		}
+		return tx.Model(&model.PostComment{}).Where("id = ?", comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "发表评论失败", logger.Int("post_id", int(comment.PostID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetComment 获取未删除的评论
+func (r *postRepository) GetComment(ctx context.Context, id uint) (*model.PostComment, error) {
+	var comment model.PostComment
+	err := r.db.WithContext(ctx).Preload("Author", publicUserColumns).
+		Where("id = ? AND is_deleted = 0", id).First(&comment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// DeleteComment 删除评论,一级评论连同其回复一起删除
+func (r *postRepository) DeleteComment(ctx context.Context, comment *model.PostComment) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if comment.ParentID == 0 {
+			return tx.Model(&model.PostComment{}).
+				Where("id = ? OR parent_id = ?", comment.ID, comment.ID).
+				Update("is_deleted", 1).Error
+		}
+		result := tx.Model(&model.PostComment{}).Where("id = ? AND is_deleted = 0", comment.ID).Update("is_deleted", 1)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&model.PostComment{}).Where("id = ? AND reply_count > 0", comment.ParentID).
+			UpdateColumn("reply_count", gorm.Expr("reply_count - 1")).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "删除评论失败", logger.Int("id", int(comment.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// ListComments 一级评论列表,按时间正序,排除与viewerID互相拉黑的用户的评论
+func (r *postRepository) ListComments(ctx context.Context, postID, viewerID uint, offset, limit int) ([]*model.PostComment, int64, error) {
+	return r.listComments(ctx, r.db.WithContext(ctx).Where("post_id = ? AND parent_id = 0", postID), viewerID, offset, limit)
+}
+
+// ListReplies 一级评论下的回复,按时间正序,排除与viewerID互相拉黑的用户的回复
+func (r *postRepository) ListReplies(ctx context.Context, parentID, viewerID uint, offset, limit int) ([]*model.PostComment, int64, error) {
+	return r.listComments(ctx, r.db.WithContext(ctx).Where("parent_id = ?", parentID), viewerID, offset, limit)
+}
+
+func (r *postRepository) listComments(ctx context.Context, query *gorm.DB, viewerID uint, offset, limit int) ([]*model.PostComment, int64, error) {
+	var comments []*model.PostComment
+	var total int64
+
+	query = query.Model(&model.PostComment{}).Where("is_deleted = 0")
+	if viewerID != 0 {
+		cond, args := notBlockedWith(viewerID, "post_comments.user_id")
+		query = query.Where(cond, args...)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取评论总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Author", publicUserColumns).
+		Offset(offset).Limit(limit).Order("id ASC").Find(&comments).Error
+	if err != nil {
+		logger.Error(ctx, "获取评论列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
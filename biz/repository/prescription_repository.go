@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+var (
+	// ErrPrescriptionSigned 处方已签发或作废,内容不可修改
+	ErrPrescriptionSigned = errors.New("处方已签发,内容不可修改")
+	// ErrPrescriptionNotFillable 处方已作废、过期或没有剩余配药次数
+	ErrPrescriptionNotFillable = errors.New("处方已失效或没有剩余可配次数")
+	// ErrRefillPending 处方已有待处理的配药申请
+	ErrRefillPending = errors.New("该处方已有待处理的配药申请")
+	// ErrRefillStatusChanged 配药申请状态已变更
+	ErrRefillStatusChanged = errors.New("配药申请状态已变更,请刷新后重试")
+)
+
+// PrescriptionRepository 处方与配药申请仓储接口
+type PrescriptionRepository interface {
+	Create(ctx context.Context, prescription *model.Prescription) error
+	// UpdateDraft 修改处方草稿,已签发的处方返回ErrPrescriptionSigned
+	UpdateDraft(ctx context.Context, prescription *model.Prescription) error
+	DeleteDraft(ctx context.Context, id uint) error
+	// Sign 签发处方,写入签发时间和内容摘要
+	Sign(ctx context.Context, prescription *model.Prescription) error
+	// Revoke 作废已签发的处方,并取消待处理的配药申请
+	Revoke(ctx context.Context, prescription *model.Prescription) error
+	GetByID(ctx context.Context, id uint) (*model.Prescription, error)
+	// ListByPet 宠物的处方,不返回草稿
+	ListByPet(ctx context.Context, petID, recordID uint, status string, offset, limit int) ([]*model.Prescription, int64, error)
+	ListByClinic(ctx context.Context, clinicID, recordID uint, status string, offset, limit int) ([]*model.Prescription, int64, error)
+
+	// CreateRefill 提交配药申请,同一处方同时只能有一个待处理的申请
+	CreateRefill(ctx context.Context, refill *model.PrescriptionRefill) error
+	GetRefill(ctx context.Context, id uint) (*model.PrescriptionRefill, error)
+	ListRefills(ctx context.Context, prescriptionID uint) ([]*model.PrescriptionRefill, error)
+	// ListRefillsByChannel 药房的配药申请队列,clinicID为0时不按诊所筛选
+	ListRefillsByChannel(ctx context.Context, channel string, clinicID uint, status string, offset, limit int) ([]*model.PrescriptionRefill, int64, error)
+	// CloseRefill 取消或拒绝待处理的配药申请
+	CloseRefill(ctx context.Context, refill *model.PrescriptionRefill) error
+	// FulfillRefill 完成配药,在同一事务中扣减处方的剩余次数
+	FulfillRefill(ctx context.Context, refill *model.PrescriptionRefill, today time.Time) error
+}
+
+// prescriptionRepository 处方与配药申请仓储实现
+type prescriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewPrescriptionRepository 创建处方与配药申请仓储
+func NewPrescriptionRepository(db *gorm.DB) PrescriptionRepository {
+	return &prescriptionRepository{db: db}
+}
+
+// Create 创建处方草稿
+func (r *prescriptionRepository) Create(ctx context.Context, prescription *model.Prescription) error {
+	if err := r.db.WithContext(ctx).Omit("Clinic").Create(prescription).Error; err != nil {
+		logger.Error(ctx, "创建处方失败", logger.Int("medical_record_id", int(prescription.MedicalRecordID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建处方成功", logger.Int("id", int(prescription.ID)))
+	return nil
+}
+
+// UpdateDraft 修改处方草稿,按状态条件更新,签发后的处方不会被覆盖
+func (r *prescriptionRepository) UpdateDraft(ctx context.Context, prescription *model.Prescription) error {
+	result := r.db.WithContext(ctx).Model(&model.Prescription{}).
+		Where("id = ? AND status = ?", prescription.ID, model.PrescriptionDraft).
+		Updates(map[string]interface{}{
+			"drug":              prescription.Drug,
+			"strength":          prescription.Strength,
+			"quantity":          prescription.Quantity,
+			"unit":              prescription.Unit,
+			"refills":           prescription.Refills,
+			"refills_remaining": prescription.RefillsRemaining,
+			"instructions":      prescription.Instructions,
+			"expires_at":        prescription.ExpiresAt,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新处方失败", logger.Int("id", int(prescription.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPrescriptionSigned
+	}
+	return nil
+}
+
+// DeleteDraft 删除处方草稿
+func (r *prescriptionRepository) DeleteDraft(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND status = ?", id, model.PrescriptionDraft).Delete(&model.Prescription{})
+	if result.Error != nil {
+		logger.Error(ctx, "删除处方失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPrescriptionSigned
+	}
+	return nil
+}
+
+// Sign 签发处方
+func (r *prescriptionRepository) Sign(ctx context.Context, prescription *model.Prescription) error {
+	result := r.db.WithContext(ctx).Model(&model.Prescription{}).
+		Where("id = ? AND status = ?", prescription.ID, model.PrescriptionDraft).
+		Updates(map[string]interface{}{
+			"status":    model.PrescriptionSigned,
+			"signed_at": prescription.SignedAt,
+			"digest":    prescription.Digest,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "签发处方失败", logger.Int("id", int(prescription.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPrescriptionSigned
+	}
+	logger.Info(ctx, "处方已签发", logger.Int("id", int(prescription.ID)), logger.Int("vet_id", int(prescription.VetID)))
+	return nil
+}
+
+// Revoke 作废处方
+func (r *prescriptionRepository) Revoke(ctx context.Context, prescription *model.Prescription) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Prescription{}).
+			Where("id = ? AND status = ?", prescription.ID, model.PrescriptionSigned).
+			Updates(map[string]interface{}{
+				"status":        model.PrescriptionRevoked,
+				"revoked_at":    prescription.RevokedAt,
+				"revoke_reason": prescription.RevokeReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("只能作废已签发的处方")
+		}
+		return tx.Model(&model.PrescriptionRefill{}).
+			Where("prescription_id = ? AND status = ?", prescription.ID, model.RefillPending).
+			Updates(map[string]interface{}{
+				"status":      model.RefillCancelled,
+				"handled_at":  prescription.RevokedAt,
+				"handle_note": "处方已作废",
+			}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "作废处方失败", logger.Int("id", int(prescription.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 根据ID获取处方
+func (r *prescriptionRepository) GetByID(ctx context.Context, id uint) (*model.Prescription, error) {
+	var prescription model.Prescription
+	err := r.db.WithContext(ctx).Preload("Clinic").Where("id = ?", id).First(&prescription).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取处方失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &prescription, nil
+}
+
+// ListByPet 宠物已签发或作废的处方,按创建时间倒序
+func (r *prescriptionRepository) ListByPet(ctx context.Context, petID, recordID uint, status string, offset, limit int) ([]*model.Prescription, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Prescription{}).
+		Where("pet_id = ? AND status <> ?", petID, model.PrescriptionDraft)
+	return r.list(ctx, query, recordID, status, offset, limit)
+}
+
+// ListByClinic 诊所开具的处方,按创建时间倒序
+func (r *prescriptionRepository) ListByClinic(ctx context.Context, clinicID, recordID uint, status string, offset, limit int) ([]*model.Prescription, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Prescription{}).Where("clinic_id = ?", clinicID)
+	return r.list(ctx, query, recordID, status, offset, limit)
+}
+
+func (r *prescriptionRepository) list(ctx context.Context, query *gorm.DB, recordID uint, status string, offset, limit int) ([]*model.Prescription, int64, error) {
+	var prescriptions []*model.Prescription
+	var total int64
+	if recordID > 0 {
+		query = query.Where("medical_record_id = ?", recordID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取处方总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	if err := query.Preload("Clinic").Offset(offset).Limit(limit).Order("id DESC").Find(&prescriptions).Error; err != nil {
+		logger.Error(ctx, "获取处方列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return prescriptions, total, nil
+}
+
+// CreateRefill 提交配药申请,锁定处方行避免并发提交多个待处理申请
+func (r *prescriptionRepository) CreateRefill(ctx context.Context, refill *model.PrescriptionRefill) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var prescription model.Prescription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&prescription, refill.PrescriptionID).Error; err != nil {
+			return err
+		}
+		var pending int64
+		err := tx.Model(&model.PrescriptionRefill{}).
+			Where("prescription_id = ? AND status = ?", refill.PrescriptionID, model.RefillPending).
+			Count(&pending).Error
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return ErrRefillPending
+		}
+		return tx.Omit("Prescription").Create(refill).Error
+	})
+	if err != nil && !errors.Is(err, ErrRefillPending) {
+		logger.Error(ctx, "提交配药申请失败", logger.Int("prescription_id", int(refill.PrescriptionID)), logger.ErrorField(err))
+	}
+	return err
+}
+
+// GetRefill 根据ID获取配药申请
+func (r *prescriptionRepository) GetRefill(ctx context.Context, id uint) (*model.PrescriptionRefill, error) {
+	var refill model.PrescriptionRefill
+	err := r.db.WithContext(ctx).Preload("Prescription.Clinic").Where("id = ?", id).First(&refill).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取配药申请失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &refill, nil
+}
+
+// ListRefills 处方的全部配药申请,按提交时间倒序
+func (r *prescriptionRepository) ListRefills(ctx context.Context, prescriptionID uint) ([]*model.PrescriptionRefill, error) {
+	var refills []*model.PrescriptionRefill
+	err := r.db.WithContext(ctx).Where("prescription_id = ?", prescriptionID).Order("id DESC").Find(&refills).Error
+	if err != nil {
+		logger.Error(ctx, "获取配药申请列表失败", logger.Int("prescription_id", int(prescriptionID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return refills, nil
+}
+
+// ListRefillsByChannel 药房的配药申请队列,按提交时间排序
+func (r *prescriptionRepository) ListRefillsByChannel(ctx context.Context, channel string, clinicID uint, status string, offset, limit int) ([]*model.PrescriptionRefill, int64, error) {
+	var refills []*model.PrescriptionRefill
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.PrescriptionRefill{}).Where("channel = ?", channel)
+	if clinicID > 0 {
+		query = query.Where("clinic_id = ?", clinicID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取配药申请总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Prescription.Clinic").Offset(offset).Limit(limit).Order("id ASC").Find(&refills).Error
+	if err != nil {
+		logger.Error(ctx, "获取配药申请列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return refills, total, nil
+}
+
+// CloseRefill 取消或拒绝配药申请
+func (r *prescriptionRepository) CloseRefill(ctx context.Context, refill *model.PrescriptionRefill) error {
+	result := r.db.WithContext(ctx).Model(&model.PrescriptionRefill{}).
+		Where("id = ? AND status = ?", refill.ID, model.RefillPending).
+		Updates(map[string]interface{}{
+			"status":      refill.Status,
+			"handled_by":  refill.HandledBy,
+			"handled_at":  refill.HandledAt,
+			"handle_note": refill.HandleNote,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新配药申请失败", logger.Int("id", int(refill.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRefillStatusChanged
+	}
+	return nil
+}
+
+// FulfillRefill 完成配药。首次配药只累加配药次数,之后每次配药扣减一次剩余续配次数;
+// 扣减使用条件更新,并发配药时不会超出处方允许的次数
+func (r *prescriptionRepository) FulfillRefill(ctx context.Context, refill *model.PrescriptionRefill, today time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PrescriptionRefill{}).
+			Where("id = ? AND status = ?", refill.ID, model.RefillPending).
+			Updates(map[string]interface{}{
+				"status":      model.RefillFulfilled,
+				"handled_by":  refill.HandledBy,
+				"handled_at":  refill.HandledAt,
+				"handle_note": refill.HandleNote,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefillStatusChanged
+		}
+
+		fillable := tx.Model(&model.Prescription{}).
+			Where("id = ? AND status = ? AND expires_at >= ?", refill.PrescriptionID, model.PrescriptionSigned, today)
+		result = fillable.Session(&gorm.Session{}).Where("fill_count = 0").
+			Update("fill_count", gorm.Expr("fill_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			result = fillable.Session(&gorm.Session{}).Where("fill_count > 0 AND refills_remaining > 0").
+				Updates(map[string]interface{}{
+					"fill_count":        gorm.Expr("fill_count + 1"),
+					"refills_remaining": gorm.Expr("refills_remaining - 1"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrPrescriptionNotFillable
+			}
+		}
+
+		var prescription model.Prescription
+		if err := tx.Select("id", "fill_count").First(&prescription, refill.PrescriptionID).Error; err != nil {
+			return err
+		}
+		refill.FillNo = prescription.FillCount
+		return tx.Model(&model.PrescriptionRefill{}).Where("id = ?", refill.ID).Update("fill_no", refill.FillNo).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrRefillStatusChanged) && !errors.Is(err, ErrPrescriptionNotFillable) {
+			logger.Error(ctx, "完成配药失败", logger.Int("id", int(refill.ID)), logger.ErrorField(err))
+		}
+		return err
+	}
+	refill.Status = model.RefillFulfilled
+	logger.Info(ctx, "配药完成", logger.Int("id", int(refill.ID)), logger.Int("prescription_id", int(refill.PrescriptionID)), logger.Int("fill_no", refill.FillNo))
+	return nil
+}
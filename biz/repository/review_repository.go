@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ReviewRepository 评价仓储接口
+type ReviewRepository interface {
+	Create(ctx context.Context, review *model.Review) error
+	GetByID(ctx context.Context, id uint) (*model.Review, error)
+	ListByTarget(ctx context.Context, targetType string, targetID uint, rating, offset, limit int) ([]*model.Review, int64, error)
+	ListByReviewer(ctx context.Context, reviewerID uint, offset, limit int) ([]*model.Review, int64, error)
+	UpdateReply(ctx context.Context, review *model.Review) error
+	AddFlag(ctx context.Context, review *model.Review, flag *model.ReviewFlag, hideThreshold int) (bool, error)
+	Delete(ctx context.Context, review *model.Review) error
+	GetSummary(ctx context.Context, targetType string, targetID uint) (*model.RatingSummary, error)
+}
+
+// reviewRepository 评价仓储实现
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository 创建评价仓储
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// Create 发表评价并在同一事务中累加评分汇总,同一来源记录只能评价一次
+func (r *reviewRepository) Create(ctx context.Context, review *model.Review) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit("Reviewer").Clauses(clause.OnConflict{DoNothing: true}).Create(review)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("该订单已评价过")
+		}
+		return adjustRatingSummary(tx, review.TargetType, review.TargetID, review.Rating, 1)
+	})
+	if err != nil {
+		logger.Error(ctx, "发表评价失败", logger.String("source_type", review.SourceType), logger.Int("source_id", int(review.SourceID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "发表评价成功", logger.Int("id", int(review.ID)))
+	return nil
+}
+
+// GetByID 获取评价
+func (r *reviewRepository) GetByID(ctx context.Context, id uint) (*model.Review, error) {
+	var review model.Review
+	err := r.db.WithContext(ctx).Preload("Reviewer", publicUserColumns).Where("id = ?", id).First(&review).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取评价失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListByTarget 获取被评价对象的公开评价
+func (r *reviewRepository) ListByTarget(ctx context.Context, targetType string, targetID uint, rating, offset, limit int) ([]*model.Review, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Review{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, model.ReviewStatusVisible)
+	if rating > 0 {
+		query = query.Where("rating = ?", rating)
+	}
+	return r.list(ctx, query, offset, limit)
+}
+
+// ListByReviewer 获取用户发表的评价
+func (r *reviewRepository) ListByReviewer(ctx context.Context, reviewerID uint, offset, limit int) ([]*model.Review, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Review{}).Where("reviewer_id = ?", reviewerID)
+	return r.list(ctx, query, offset, limit)
+}
+
+func (r *reviewRepository) list(ctx context.Context, query *gorm.DB, offset, limit int) ([]*model.Review, int64, error) {
+	var reviews []*model.Review
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取评价总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Reviewer", publicUserColumns).
+		Offset(offset).Limit(limit).
+		Order("id DESC").
+		Find(&reviews).Error
+	if err != nil {
+		logger.Error(ctx, "获取评价列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// UpdateReply 保存服务方回复
+func (r *reviewRepository) UpdateReply(ctx context.Context, review *model.Review) error {
+	err := r.db.WithContext(ctx).Model(&model.Review{}).Where("id = ?", review.ID).
+		Updates(map[string]interface{}{
+			"reply":      review.Reply,
+			"replied_by": review.RepliedBy,
+			"replied_at": review.RepliedAt,
+		}).Error
+	if err != nil {
+		logger.Error(ctx, "回复评价失败", logger.Int("id", int(review.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// AddFlag 举报评价,举报数达到阈值时自动隐藏并从评分汇总中扣除,返回本次是否触发隐藏
+func (r *reviewRepository) AddFlag(ctx context.Context, review *model.Review, flag *model.ReviewFlag, hideThreshold int) (bool, error) {
+	hidden := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(flag)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("已举报过该评价")
+		}
+		if err := tx.Model(&model.Review{}).Where("id = ?", review.ID).
+			UpdateColumn("flag_count", gorm.Expr("flag_count + 1")).Error; err != nil {
+			return err
+		}
+
+		result = tx.Model(&model.Review{}).
+			Where("id = ? AND status = ? AND flag_count >= ?", review.ID, model.ReviewStatusVisible, hideThreshold).
+			Update("status", model.ReviewStatusHidden)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		hidden = true
+		return adjustRatingSummary(tx, review.TargetType, review.TargetID, review.Rating, -1)
+	})
+	if err != nil {
+		logger.Error(ctx, "举报评价失败", logger.Int("review_id", int(review.ID)), logger.ErrorField(err))
+		return false, err
+	}
+	return hidden, nil
+}
+
+// Delete 删除评价及举报记录,公开的评价同时从评分汇总中扣除
+func (r *reviewRepository) Delete(ctx context.Context, review *model.Review) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 以删除时的状态为准,避免与举报隐藏并发时重复扣减
+		var current model.Review
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", review.ID).First(&current).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", review.ID).Delete(&model.ReviewFlag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.Review{}, review.ID).Error; err != nil {
+			return err
+		}
+		if current.Status != model.ReviewStatusVisible {
+			return nil
+		}
+		return adjustRatingSummary(tx, current.TargetType, current.TargetID, current.Rating, -1)
+	})
+	if err != nil {
+		logger.Error(ctx, "删除评价失败", logger.Int("id", int(review.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetSummary 获取评分汇总,尚无评价时返回空汇总
+func (r *reviewRepository) GetSummary(ctx context.Context, targetType string, targetID uint) (*model.RatingSummary, error) {
+	var summary model.RatingSummary
+	err := r.db.WithContext(ctx).Where("target_type = ? AND target_id = ?", targetType, targetID).First(&summary).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.RatingSummary{TargetType: targetType, TargetID: targetID}, nil
+		}
+		logger.Error(ctx, "获取评分汇总失败", logger.String("target_type", targetType), logger.Int("target_id", int(targetID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// adjustRatingSummary 增量更新评分汇总,delta为1表示新增一条评价,-1表示移除
+func adjustRatingSummary(tx *gorm.DB, targetType string, targetID uint, rating, delta int) error {
+	star := fmt.Sprintf("star%d", rating)
+	summary := map[string]interface{}{
+		"target_type": targetType,
+		"target_id":   targetID,
+		"count":       delta,
+		"sum":         rating * delta,
+		star:          delta,
+		"updated_at":  time.Now(),
+	}
+	return tx.Model(&model.RatingSummary{}).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"count":      gorm.Expr("`count` + ?", delta),
+			"sum":        gorm.Expr("`sum` + ?", rating*delta),
+			star:         gorm.Expr(star+" + ?", delta),
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}).Create(summary).Error
+}
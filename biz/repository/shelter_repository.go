@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ShelterRepository 救助机构仓储接口
+type ShelterRepository interface {
+	Create(ctx context.Context, shelter *model.Shelter) error
+	Update(ctx context.Context, shelter *model.Shelter) error
+	GetByID(ctx context.Context, id uint) (*model.Shelter, error)
+}
+
+// shelterRepository 救助机构仓储实现
+type shelterRepository struct {
+	db *gorm.DB
+}
+
+// NewShelterRepository 创建救助机构仓储
+func NewShelterRepository(db *gorm.DB) ShelterRepository {
+	return &shelterRepository{db: db}
+}
+
+// Create 创建救助机构
+func (r *shelterRepository) Create(ctx context.Context, shelter *model.Shelter) error {
+	if err := r.db.WithContext(ctx).Create(shelter).Error; err != nil {
+		logger.Error(ctx, "创建救助机构失败", logger.String("name", shelter.Name), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建救助机构成功", logger.Int("id", int(shelter.ID)))
+	return nil
+}
+
+// Update 更新救助机构
+func (r *shelterRepository) Update(ctx context.Context, shelter *model.Shelter) error {
+	if err := r.db.WithContext(ctx).Save(shelter).Error; err != nil {
+		logger.Error(ctx, "更新救助机构失败", logger.Int("id", int(shelter.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetByID 根据ID获取救助机构
+func (r *shelterRepository) GetByID(ctx context.Context, id uint) (*model.Shelter, error) {
+	var shelter model.Shelter
+	err := r.db.WithContext(ctx).Where("id = ? AND status = 1", id).First(&shelter).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取救助机构失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &shelter, nil
+}
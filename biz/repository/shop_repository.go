@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// ProductFilter 商品检索条件
+type ProductFilter struct {
+	CategoryIDs []uint
+	Keyword     string
+	MinPrice    int64
+	MaxPrice    int64
+	Status      string
+	Sort        string
+	Offset      int
+	Limit       int
+}
+
+// ShopRepository 商城仓储接口
+type ShopRepository interface {
+	CreateCategory(ctx context.Context, category *model.ProductCategory) error
+	UpdateCategory(ctx context.Context, category *model.ProductCategory) error
+	GetCategory(ctx context.Context, id uint) (*model.ProductCategory, error)
+	ListCategories(ctx context.Context, enabledOnly bool) ([]*model.ProductCategory, error)
+
+	CreateProduct(ctx context.Context, product *model.Product) error
+	UpdateProduct(ctx context.Context, product *model.Product) error
+	UpdateProductStatus(ctx context.Context, id uint, status string) error
+	GetProduct(ctx context.Context, id uint) (*model.Product, error)
+	ListProducts(ctx context.Context, filter *ProductFilter) ([]*model.Product, int64, error)
+
+	CreateSKU(ctx context.Context, sku *model.ProductSKU) error
+	UpdateSKU(ctx context.Context, sku *model.ProductSKU) error
+	GetSKU(ctx context.Context, id uint) (*model.ProductSKU, error)
+	GetSKUsByIDs(ctx context.Context, ids []uint) ([]*model.ProductSKU, error)
+	SKUCodeExists(ctx context.Context, codes []string, excludeID uint) (bool, error)
+	AdjustStock(ctx context.Context, skuID uint, delta int) error
+
+	Reserve(ctx context.Context, reservation *model.StockReservation) error
+	GetReservation(ctx context.Context, id uint) (*model.StockReservation, error)
+	ReleaseReservation(ctx context.Context, id uint) error
+	ListExpiredReservations(ctx context.Context, now time.Time, limit int) ([]uint, error)
+}
+
+// shopRepository 商城仓储实现
+type shopRepository struct {
+	db *gorm.DB
+}
+
+// NewShopRepository 创建商城仓储
+func NewShopRepository(db *gorm.DB) ShopRepository {
+	return &shopRepository{db: db}
+}
+
+// CreateCategory 创建商品分类
+func (r *shopRepository) CreateCategory(ctx context.Context, category *model.ProductCategory) error {
+	if err := r.db.WithContext(ctx).Create(category).Error; err != nil {
+		logger.Error(ctx, "创建商品分类失败", logger.String("name", category.Name), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建商品分类成功", logger.Int("id", int(category.ID)))
+	return nil
+}
+
+// UpdateCategory 更新商品分类
+func (r *shopRepository) UpdateCategory(ctx context.Context, category *model.ProductCategory) error {
+	err := r.db.WithContext(ctx).Model(category).
+		Select("parent_id", "name", "sort", "status").Updates(category).Error
+	if err != nil {
+		logger.Error(ctx, "更新商品分类失败", logger.Int("id", int(category.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetCategory 获取商品分类
+func (r *shopRepository) GetCategory(ctx context.Context, id uint) (*model.ProductCategory, error) {
+	var category model.ProductCategory
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListCategories 获取全部商品分类
+func (r *shopRepository) ListCategories(ctx context.Context, enabledOnly bool) ([]*model.ProductCategory, error) {
+	var categories []*model.ProductCategory
+	query := r.db.WithContext(ctx).Model(&model.ProductCategory{})
+	if enabledOnly {
+		query = query.Where("status = ?", 1)
+	}
+	if err := query.Order("sort ASC, id ASC").Find(&categories).Error; err != nil {
+		logger.Error(ctx, "获取商品分类失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return categories, nil
+}
+
+// CreateProduct 创建商品及其规格
+func (r *shopRepository) CreateProduct(ctx context.Context, product *model.Product) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return refreshMinPrice(tx, product.ID)
+	})
+	if err != nil {
+		logger.Error(ctx, "创建商品失败", logger.String("name", product.Name), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建商品成功", logger.Int("id", int(product.ID)), logger.Int("skus", len(product.SKUs)))
+	return nil
+}
+
+// UpdateProduct 更新商品基本信息
+func (r *shopRepository) UpdateProduct(ctx context.Context, product *model.Product) error {
+	err := r.db.WithContext(ctx).Model(&model.Product{ID: product.ID}).
+		Select("category_id", "name", "description", "image_file_ids").Updates(product).Error
+	if err != nil {
+		logger.Error(ctx, "更新商品失败", logger.Int("id", int(product.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateProductStatus 商品上下架
+func (r *shopRepository) UpdateProductStatus(ctx context.Context, id uint, status string) error {
+	err := r.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).Update("status", status).Error
+	if err != nil {
+		logger.Error(ctx, "更新商品状态失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "更新商品状态成功", logger.Int("id", int(id)), logger.String("status", status))
+	return nil
+}
+
+// GetProduct 获取商品详情,包含分类和全部规格
+func (r *shopRepository) GetProduct(ctx context.Context, id uint) (*model.Product, error) {
+	var product model.Product
+	err := r.db.WithContext(ctx).
+		Preload("Category").
+		Preload("SKUs", func(db *gorm.DB) *gorm.DB { return db.Order("price ASC, id ASC") }).
+		Where("id = ?", id).First(&product).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取商品失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ListProducts 检索商品
+func (r *shopRepository) ListProducts(ctx context.Context, filter *ProductFilter) ([]*model.Product, int64, error) {
+	var products []*model.Product
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.Product{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Keyword+"%")
+	}
+	if filter.MinPrice > 0 {
+		query = query.Where("min_price >= ?", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("min_price <= ?", filter.MaxPrice)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取商品总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+
+	order := "id DESC"
+	switch filter.Sort {
+	case "price_asc":
+		order = "min_price ASC, id DESC"
+	case "price_desc":
+		order = "min_price DESC, id DESC"
+	}
+	err := query.Offset(filter.Offset).Limit(filter.Limit).Order(order).Find(&products).Error
+	if err != nil {
+		logger.Error(ctx, "获取商品列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// CreateSKU 新增规格
+func (r *shopRepository) CreateSKU(ctx context.Context, sku *model.ProductSKU) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(sku).Error; err != nil {
+			return err
+		}
+		return refreshMinPrice(tx, sku.ProductID)
+	})
+	if err != nil {
+		logger.Error(ctx, "新增规格失败", logger.Int("product_id", int(sku.ProductID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// UpdateSKU 更新规格信息,库存不在此处修改
+func (r *shopRepository) UpdateSKU(ctx context.Context, sku *model.ProductSKU) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.ProductSKU{ID: sku.ID}).
+			Select("sku_code", "name", "attributes", "price", "status").Updates(sku).Error
+		if err != nil {
+			return err
+		}
+		return refreshMinPrice(tx, sku.ProductID)
+	})
+	if err != nil {
+		logger.Error(ctx, "更新规格失败", logger.Int("id", int(sku.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GetSKU 获取规格
+func (r *shopRepository) GetSKU(ctx context.Context, id uint) (*model.ProductSKU, error) {
+	var sku model.ProductSKU
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&sku).Error; err != nil {
+		return nil, err
+	}
+	return &sku, nil
+}
+
+// GetSKUsByIDs 批量获取规格,包含所属商品
+func (r *shopRepository) GetSKUsByIDs(ctx context.Context, ids []uint) ([]*model.ProductSKU, error) {
+	var skus []*model.ProductSKU
+	if len(ids) == 0 {
+		return skus, nil
+	}
+	if err := r.db.WithContext(ctx).Preload("Product").Where("id IN ?", ids).Find(&skus).Error; err != nil {
+		logger.Error(ctx, "批量获取规格失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return skus, nil
+}
+
+// SKUCodeExists 判断规格编码是否已被其他规格占用
+func (r *shopRepository) SKUCodeExists(ctx context.Context, codes []string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ProductSKU{}).
+		Where("sku_code IN ? AND id <> ?", codes, excludeID).Count(&count).Error
+	if err != nil {
+		logger.Error(ctx, "检查规格编码失败", logger.ErrorField(err))
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AdjustStock 调整库存,扣减后库存不能为负
+func (r *shopRepository) AdjustStock(ctx context.Context, skuID uint, delta int) error {
+	result := r.db.WithContext(ctx).Model(&model.ProductSKU{}).
+		Where("id = ? AND stock + ? >= 0", skuID, delta).
+		Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		logger.Error(ctx, "调整库存失败", logger.Int("sku_id", int(skuID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("库存不足,无法扣减")
+	}
+	logger.Info(ctx, "调整库存成功", logger.Int("sku_id", int(skuID)), logger.Int("delta", delta))
+	return nil
+}
+
+// Reserve 预占库存并创建预占记录
+// 按规格ID顺序逐条条件扣减(stock >= 数量),并发结算时不会超卖,固定加锁顺序避免死锁
+func (r *shopRepository) Reserve(ctx context.Context, reservation *model.StockReservation) error {
+	items := make([]*model.ReservationItem, len(reservation.Items))
+	copy(items, reservation.Items)
+	sort.Slice(items, func(i, j int) bool { return items[i].SKUID < items[j].SKUID })
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			result := tx.Model(&model.ProductSKU{}).
+				Where("id = ? AND stock >= ?", item.SKUID, item.Quantity).
+				Update("stock", gorm.Expr("stock - ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return errors.New("商品库存不足: " + item.ProductName + " " + item.SKUName)
+			}
+		}
+		return tx.Create(reservation).Error
+	})
+	if err != nil {
+		logger.Warn(ctx, "预占库存失败", logger.Int("user_id", int(reservation.UserID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "预占库存成功", logger.Int("id", int(reservation.ID)), logger.Int64("total_amount", reservation.TotalAmount))
+	return nil
+}
+
+// GetReservation 获取库存预占记录
+func (r *shopRepository) GetReservation(ctx context.Context, id uint) (*model.StockReservation, error) {
+	var reservation model.StockReservation
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&reservation).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ReleaseReservation 释放预占并归还库存,仅对仍处于预占状态的记录生效
+func (r *shopRepository) ReleaseReservation(ctx context.Context, id uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservation model.StockReservation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reservation).Error
+		if err != nil {
+			return err
+		}
+		if reservation.Status != model.ReservationStatusReserved {
+			return errors.New("预占状态已变更,请刷新后重试")
+		}
+		if err := tx.Model(&reservation).Update("status", model.ReservationStatusReleased).Error; err != nil {
+			return err
+		}
+		quantities := make(map[uint]int, len(reservation.Items))
+		for _, item := range reservation.Items {
+			quantities[item.SKUID] += item.Quantity
+		}
+		return restoreStock(tx, quantities)
+	})
+	if err != nil {
+		logger.Error(ctx, "释放库存预占失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "释放库存预占成功", logger.Int("id", int(id)))
+	return nil
+}
+
+// ListExpiredReservations 获取已过期仍未释放的预占ID
+func (r *shopRepository) ListExpiredReservations(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.StockReservation{}).
+		Where("status = ? AND expires_at <= ?", model.ReservationStatusReserved, now).
+		Order("expires_at ASC").Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		logger.Error(ctx, "获取过期库存预占失败", logger.ErrorField(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// restoreStock 归还库存,quantities为规格ID到数量的映射,按规格ID顺序更新避免死锁
+func restoreStock(tx *gorm.DB, quantities map[uint]int) error {
+	ids := make([]uint, 0, len(quantities))
+	for skuID := range quantities {
+		ids = append(ids, skuID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, skuID := range ids {
+		err := tx.Model(&model.ProductSKU{}).Where("id = ?", skuID).
+			Update("stock", gorm.Expr("stock + ?", quantities[skuID])).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshMinPrice 按在售规格重新计算商品最低价
+func refreshMinPrice(tx *gorm.DB, productID uint) error {
+	return tx.Model(&model.Product{}).Where("id = ?", productID).
+		Update("min_price", gorm.Expr("(SELECT COALESCE(MIN(price), 0) FROM product_skus WHERE product_id = ? AND status = 1)", productID)).Error
+}
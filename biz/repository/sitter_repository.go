@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+)
+
+// SitterFilter 看护人检索条件
+type SitterFilter struct {
+	City      string
+	Species   string
+	Service   string
+	MaxPrice  int64
+	StartDate *time.Time // 与EndDate同时设置时只返回[StartDate, EndDate)整段可约的看护人
+	EndDate   *time.Time
+	Nights    int
+	Latitude  float64 // 设置坐标时只返回服务半径覆盖该位置的看护人
+	Longitude float64
+	HasPoint  bool
+	Offset    int
+	Limit     int
+}
+
+// SitterRepository 寄养看护仓储接口
+type SitterRepository interface {
+	GetProfile(ctx context.Context, id uint) (*model.SitterProfile, error)
+	GetProfileByUser(ctx context.Context, userID uint) (*model.SitterProfile, error)
+	SaveProfile(ctx context.Context, profile *model.SitterProfile) error
+	SearchProfiles(ctx context.Context, filter *SitterFilter) ([]*model.SitterProfile, int64, error)
+
+	SetCalendar(ctx context.Context, sitterID uint, dates []time.Time, status string) error
+	ListCalendar(ctx context.Context, sitterID uint, from, to time.Time) ([]*model.SitterCalendarDay, error)
+	CountAvailable(ctx context.Context, sitterID uint, from, to time.Time) (int64, error)
+
+	CreateBooking(ctx context.Context, booking *model.SitterBooking) error
+	GetBooking(ctx context.Context, id uint) (*model.SitterBooking, error)
+	HasOverlappingBooking(ctx context.Context, sitterID, ownerID uint, from, to time.Time) (bool, error)
+	ListBookingsByOwner(ctx context.Context, ownerID uint, status string, offset, limit int) ([]*model.SitterBooking, int64, error)
+	ListBookingsBySitter(ctx context.Context, sitterID uint, status string, offset, limit int) ([]*model.SitterBooking, int64, error)
+	AcceptBooking(ctx context.Context, booking *model.SitterBooking) error
+	UpdateBookingStatus(ctx context.Context, booking *model.SitterBooking, fromStatus string) error
+	CancelBooking(ctx context.Context, booking *model.SitterBooking, fromStatus string) error
+	CompleteBooking(ctx context.Context, booking *model.SitterBooking) error
+}
+
+// sitterRepository 寄养看护仓储实现
+type sitterRepository struct {
+	db *gorm.DB
+}
+
+// NewSitterRepository 创建寄养看护仓储
+func NewSitterRepository(db *gorm.DB) SitterRepository {
+	return &sitterRepository{db: db}
+}
+
+// GetProfile 获取看护人资料
+func (r *sitterRepository) GetProfile(ctx context.Context, id uint) (*model.SitterProfile, error) {
+	var profile model.SitterProfile
+	err := r.db.WithContext(ctx).Preload("User", publicUserColumns).Where("id = ?", id).First(&profile).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取看护人资料失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetProfileByUser 获取用户的看护人资料
+func (r *sitterRepository) GetProfileByUser(ctx context.Context, userID uint) (*model.SitterProfile, error) {
+	var profile model.SitterProfile
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveProfile 创建或更新看护人资料
+func (r *sitterRepository) SaveProfile(ctx context.Context, profile *model.SitterProfile) error {
+	if err := r.db.WithContext(ctx).Omit("User").Save(profile).Error; err != nil {
+		logger.Error(ctx, "保存看护人资料失败", logger.Int("user_id", int(profile.UserID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// SearchProfiles 检索接单中的看护人,按已完成寄养次数排序
+func (r *sitterRepository) SearchProfiles(ctx context.Context, filter *SitterFilter) ([]*model.SitterProfile, int64, error) {
+	var profiles []*model.SitterProfile
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.SitterProfile{}).Where("status = ?", 1)
+	if filter.City != "" {
+		query = query.Where("city = ?", filter.City)
+	}
+	if filter.Species != "" {
+		query = query.Where("JSON_CONTAINS(accepted_species, JSON_QUOTE(?))", filter.Species)
+	}
+	if filter.Service != "" {
+		query = query.Where("JSON_CONTAINS(services, JSON_QUOTE(?))", filter.Service)
+	}
+	if filter.MaxPrice > 0 {
+		query = query.Where("price_per_night <= ?", filter.MaxPrice)
+	}
+	if filter.StartDate != nil && filter.EndDate != nil {
+		query = query.Where("(SELECT COUNT(*) FROM sitter_calendar_days d WHERE d.sitter_id = sitter_profiles.id AND d.date >= ? AND d.date < ? AND d.status = ?) = ?",
+			*filter.StartDate, *filter.EndDate, model.CalendarAvailable, filter.Nights)
+	}
+	if filter.HasPoint {
+		query = query.Where("ST_Distance_Sphere(POINT(longitude, latitude), POINT(?, ?)) <= service_radius_km * 1000",
+			filter.Longitude, filter.Latitude)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取看护人总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+
+	err := query.Preload("User", publicUserColumns).
+		Offset(filter.Offset).Limit(filter.Limit).
+		Order("completed_stays DESC, id DESC").
+		Find(&profiles).Error
+	if err != nil {
+		logger.Error(ctx, "检索看护人失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return profiles, total, nil
+}
+
+// SetCalendar 批量设置档期,已被预约占用的日期保持不变
+func (r *sitterRepository) SetCalendar(ctx context.Context, sitterID uint, dates []time.Time, status string) error {
+	days := make([]*model.SitterCalendarDay, 0, len(dates))
+	for _, d := range dates {
+		days = append(days, &model.SitterCalendarDay{SitterID: sitterID, Date: d, Status: status})
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":     gorm.Expr("IF(status = ?, status, VALUES(status))", model.CalendarBooked),
+			"updated_at": gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).CreateInBatches(days, 100).Error
+	if err != nil {
+		logger.Error(ctx, "设置档期失败", logger.Int("sitter_id", int(sitterID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// ListCalendar 查询[from, to)区间内已设置的档期
+func (r *sitterRepository) ListCalendar(ctx context.Context, sitterID uint, from, to time.Time) ([]*model.SitterCalendarDay, error) {
+	var days []*model.SitterCalendarDay
+	err := r.db.WithContext(ctx).
+		Where("sitter_id = ? AND date >= ? AND date < ?", sitterID, from, to).
+		Order("date ASC").
+		Find(&days).Error
+	if err != nil {
+		logger.Error(ctx, "查询档期失败", logger.Int("sitter_id", int(sitterID)), logger.ErrorField(err))
+		return nil, err
+	}
+	return days, nil
+}
+
+// CountAvailable 统计[from, to)区间内可约的天数
+func (r *sitterRepository) CountAvailable(ctx context.Context, sitterID uint, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SitterCalendarDay{}).
+		Where("sitter_id = ? AND date >= ? AND date < ? AND status = ?", sitterID, from, to, model.CalendarAvailable).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateBooking 创建预约
+func (r *sitterRepository) CreateBooking(ctx context.Context, booking *model.SitterBooking) error {
+	if err := r.db.WithContext(ctx).Omit("Sitter").Create(booking).Error; err != nil {
+		logger.Error(ctx, "创建寄养预约失败", logger.Int("sitter_id", int(booking.SitterID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "创建寄养预约成功", logger.Int("id", int(booking.ID)))
+	return nil
+}
+
+// GetBooking 获取预约详情
+func (r *sitterRepository) GetBooking(ctx context.Context, id uint) (*model.SitterBooking, error) {
+	var booking model.SitterBooking
+	err := r.db.WithContext(ctx).Preload("Sitter").Preload("Sitter.User", publicUserColumns).
+		Where("id = ?", id).First(&booking).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(ctx, "获取寄养预约失败", logger.Int("id", int(id)), logger.ErrorField(err))
+		}
+		return nil, err
+	}
+	return &booking, nil
+}
+
+// HasOverlappingBooking 主人在该看护人处是否已有时间重叠的有效预约
+func (r *sitterRepository) HasOverlappingBooking(ctx context.Context, sitterID, ownerID uint, from, to time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SitterBooking{}).
+		Where("sitter_id = ? AND owner_id = ? AND status IN ?", sitterID, ownerID,
+			[]string{model.BookingStatusRequested, model.BookingStatusAccepted}).
+		Where("start_date < ? AND end_date > ?", to, from).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListBookingsByOwner 获取主人发起的预约
+func (r *sitterRepository) ListBookingsByOwner(ctx context.Context, ownerID uint, status string, offset, limit int) ([]*model.SitterBooking, int64, error) {
+	return r.listBookings(ctx, r.db.WithContext(ctx).Where("owner_id = ?", ownerID), status, offset, limit)
+}
+
+// ListBookingsBySitter 获取看护人收到的预约
+func (r *sitterRepository) ListBookingsBySitter(ctx context.Context, sitterID uint, status string, offset, limit int) ([]*model.SitterBooking, int64, error) {
+	return r.listBookings(ctx, r.db.WithContext(ctx).Where("sitter_id = ?", sitterID), status, offset, limit)
+}
+
+func (r *sitterRepository) listBookings(ctx context.Context, query *gorm.DB, status string, offset, limit int) ([]*model.SitterBooking, int64, error) {
+	var bookings []*model.SitterBooking
+	var total int64
+
+	query = query.Model(&model.SitterBooking{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		logger.Error(ctx, "获取寄养预约总数失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	err := query.Preload("Sitter").Preload("Sitter.User", publicUserColumns).
+		Offset(offset).Limit(limit).
+		Order("start_date DESC, id DESC").
+		Find(&bookings).Error
+	if err != nil {
+		logger.Error(ctx, "获取寄养预约列表失败", logger.ErrorField(err))
+		return nil, 0, err
+	}
+	return bookings, total, nil
+}
+
+// AcceptBooking 接受预约:占用档期并拒绝与之冲突的其他待处理预约
+// 档期按天逐行占用,只有全部日期仍为可约状态时才成功,以此避免重复预约
+func (r *sitterRepository) AcceptBooking(ctx context.Context, booking *model.SitterBooking) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SitterBooking{}).
+			Where("id = ? AND status = ?", booking.ID, model.BookingStatusRequested).
+			Updates(map[string]interface{}{
+				"status":       model.BookingStatusAccepted,
+				"responded_at": booking.RespondedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("预约状态已变更,请刷新后重试")
+		}
+
+		result = tx.Model(&model.SitterCalendarDay{}).
+			Where("sitter_id = ? AND date >= ? AND date < ? AND status = ?",
+				booking.SitterID, booking.StartDate, booking.EndDate, model.CalendarAvailable).
+			Updates(map[string]interface{}{
+				"status":     model.CalendarBooked,
+				"booking_id": booking.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(booking.Nights) {
+			return errors.New("所选日期档期已被占用")
+		}
+
+		return tx.Model(&model.SitterBooking{}).
+			Where("sitter_id = ? AND status = ? AND id <> ?", booking.SitterID, model.BookingStatusRequested, booking.ID).
+			Where("start_date < ? AND end_date > ?", booking.EndDate, booking.StartDate).
+			Updates(map[string]interface{}{
+				"status":         model.BookingStatusDeclined,
+				"decline_reason": "档期已被其他预约占用",
+				"responded_at":   booking.RespondedAt,
+			}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "接受寄养预约失败", logger.Int("id", int(booking.ID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "接受寄养预约成功", logger.Int("id", int(booking.ID)))
+	return nil
+}
+
+// UpdateBookingStatus 更新预约状态,仅当预约仍处于fromStatus时生效
+func (r *sitterRepository) UpdateBookingStatus(ctx context.Context, booking *model.SitterBooking, fromStatus string) error {
+	result := r.db.WithContext(ctx).Model(&model.SitterBooking{}).
+		Where("id = ? AND status = ?", booking.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":         booking.Status,
+			"decline_reason": booking.DeclineReason,
+			"responded_at":   booking.RespondedAt,
+		})
+	if result.Error != nil {
+		logger.Error(ctx, "更新寄养预约状态失败", logger.Int("id", int(booking.ID)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("预约状态已变更,请刷新后重试")
+	}
+	return nil
+}
+
+// CancelBooking 取消预约并释放已占用的档期
+func (r *sitterRepository) CancelBooking(ctx context.Context, booking *model.SitterBooking, fromStatus string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SitterBooking{}).
+			Where("id = ? AND status = ?", booking.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":         model.BookingStatusCancelled,
+				"decline_reason": booking.DeclineReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("预约状态已变更,请刷新后重试")
+		}
+		return tx.Model(&model.SitterCalendarDay{}).
+			Where("sitter_id = ? AND booking_id = ? AND status = ?", booking.SitterID, booking.ID, model.CalendarBooked).
+			Updates(map[string]interface{}{
+				"status":     model.CalendarAvailable,
+				"booking_id": 0,
+			}).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "取消寄养预约失败", logger.Int("id", int(booking.ID)), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// CompleteBooking 完成寄养并累加看护人的完成次数
+func (r *sitterRepository) CompleteBooking(ctx context.Context, booking *model.SitterBooking) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.SitterBooking{}).
+			Where("id = ? AND status = ?", booking.ID, model.BookingStatusAccepted).
+			Updates(map[string]interface{}{
+				"status":       model.BookingStatusCompleted,
+				"completed_at": booking.CompletedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("预约状态已变更,请刷新后重试")
+		}
+		return tx.Model(&model.SitterProfile{}).Where("id = ?", booking.SitterID).
+			UpdateColumn("completed_stays", gorm.Expr("completed_stays + 1")).Error
+	})
+	if err != nil {
+		logger.Error(ctx, "完成寄养预约失败", logger.Int("id", int(booking.ID)), logger.ErrorField(err))
+		return err
+	}
+	logger.Info(ctx, "寄养已完成", logger.Int("id", int(booking.ID)))
+	return nil
+}
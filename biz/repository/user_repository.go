@@ -8,6 +8,11 @@ import (
 	"pet-service/pkg/logger"
 )
 
+// publicUserColumns 预加载关联用户时只查询公开字段
+func publicUserColumns(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "nickname", "avatar")
+}
+
 // UserRepository 用户仓储接口
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
@@ -17,6 +22,7 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	List(ctx context.Context, offset, limit int, keyword string, status *int) ([]*model.User, int64, error)
+	UpdateRole(ctx context.Context, id uint, role string) error
 }
 
 // userRepository 用户仓储实现
@@ -40,9 +46,10 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 	return nil
 }
 
-// Update 更新用户
+// Update 更新用户资料,只写入资料修改涉及的列,置零的字段(如清空头像文件ID)也会写入
 func (r *userRepository) Update(ctx context.Context, id uint, user *model.User) error {
-	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(user)
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).
+		Select("email", "phone", "nickname", "avatar", "avatar_file_id", "status").Updates(user)
 	if result.Error != nil {
 		logger.Error(ctx, "更新用户失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
 		return result.Error
@@ -146,3 +153,16 @@ func (r *userRepository) List(ctx context.Context, offset, limit int, keyword st
 	logger.Debug(ctx, "获取用户列表成功", logger.Int64("total", total), logger.Int("count", len(users)))
 	return users, total, nil
 }
+
+// UpdateRole 修改用户角色
+func (r *userRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		logger.Error(ctx, "修改用户角色失败", logger.Int("id", int(id)), logger.ErrorField(result.Error))
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("用户不存在")
+	}
+	return nil
+}
@@ -0,0 +1,498 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/track"
+)
+
+const (
+	// maxTrackFileSize 轨迹文件大小上限
+	maxTrackFileSize = 5 << 20
+	// maxStoredTrackPoints 轨迹入库前抽稀到的点数上限,距离按原始轨迹计算
+	maxStoredTrackPoints = 10000
+	// maxActivityDuration 单次活动时长上限
+	maxActivityDuration = 24 * time.Hour
+	// maxActivityDistance 单次活动距离上限(米)
+	maxActivityDistance = 300000
+	// defaultStatsBuckets 未指定区间时统计最近的周/月数
+	defaultStatsBuckets = 12
+)
+
+// ActivityService 运动记录服务接口
+type ActivityService interface {
+	CreateActivity(ctx context.Context, userID, petID uint, req *model.SaveActivityRequest) (*model.Activity, error)
+	// ImportActivity 导入GPX/GeoJSON轨迹,距离和配速由服务端计算
+	ImportActivity(ctx context.Context, userID, petID uint, req *model.ImportActivityRequest, header *multipart.FileHeader) (*model.Activity, error)
+	GetActivity(ctx context.Context, userID, id uint) (*model.Activity, error)
+	UpdateActivity(ctx context.Context, userID, id uint, req *model.SaveActivityRequest) (*model.Activity, error)
+	DeleteActivity(ctx context.Context, userID, id uint) error
+	ListActivities(ctx context.Context, userID, petID uint, req *model.ListActivityRequest) ([]*model.Activity, int64, error)
+	// GetTrack 以GeoJSON Feature返回活动轨迹
+	GetTrack(ctx context.Context, userID, id uint) (map[string]interface{}, error)
+	Stats(ctx context.Context, userID, petID uint, req *model.ActivityStatsRequest) (*model.ActivityStats, error)
+
+	SaveGoal(ctx context.Context, userID, petID uint, req *model.SaveActivityGoalRequest) (*model.ActivityGoal, error)
+	// ListGoals 宠物的运动目标及当前周期完成情况
+	ListGoals(ctx context.Context, userID, petID uint) ([]*model.ActivityGoal, error)
+	DeleteGoal(ctx context.Context, userID, id uint) error
+}
+
+// activityService 运动记录服务实现
+type activityService struct {
+	activityRepo    repository.ActivityRepository
+	measurementRepo repository.MeasurementRepository
+	petService      PetService
+}
+
+// NewActivityService 创建运动记录服务
+func NewActivityService(activityRepo repository.ActivityRepository, measurementRepo repository.MeasurementRepository, petService PetService) ActivityService {
+	return &activityService{
+		activityRepo:    activityRepo,
+		measurementRepo: measurementRepo,
+		petService:      petService,
+	}
+}
+
+// CreateActivity 手动记录活动
+func (s *activityService) CreateActivity(ctx context.Context, userID, petID uint, req *model.SaveActivityRequest) (*model.Activity, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+
+	activity := &model.Activity{
+		PetID:      petID,
+		RecordedBy: userID,
+		Source:     model.ActivitySourceManual,
+	}
+	if err := s.apply(ctx, activity, req); err != nil {
+		return nil, err
+	}
+	if err := s.activityRepo.Create(ctx, activity, nil); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// ImportActivity 导入轨迹文件创建活动
+// 开始时间和时长优先使用请求参数,未传时取轨迹中第一个和最后一个带时间的点
+func (s *activityService) ImportActivity(ctx context.Context, userID, petID uint, req *model.ImportActivityRequest, header *multipart.FileHeader) (*model.Activity, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+	if header.Size > maxTrackFileSize {
+		return nil, fmt.Errorf("轨迹文件大小不能超过%dMB", maxTrackFileSize>>20)
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxTrackFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTrackFileSize {
+		return nil, fmt.Errorf("轨迹文件大小不能超过%dMB", maxTrackFileSize>>20)
+	}
+
+	t, format, err := track.Parse(data)
+	if err != nil {
+		logger.Warn(ctx, "解析轨迹文件失败", logger.Int("pet_id", int(petID)), logger.String("filename", header.Filename), logger.ErrorField(err))
+		return nil, err
+	}
+
+	save := &model.SaveActivityRequest{
+		Type:        req.Type,
+		Title:       req.Title,
+		DurationSec: req.DurationSec,
+		DistanceM:   math.Round(t.Distance()*10) / 10,
+		Notes:       req.Notes,
+	}
+	if save.Type == "" {
+		save.Type = model.ActivityWalk
+	}
+	start, end := t.TimeRange()
+	if req.StartedAt != nil {
+		save.StartedAt = *req.StartedAt
+	} else if !start.IsZero() {
+		save.StartedAt = start.In(time.Local)
+	} else {
+		return nil, errors.New("轨迹不含时间信息,请填写开始时间")
+	}
+	if save.DurationSec == 0 {
+		save.DurationSec = int(end.Sub(start).Seconds())
+	}
+	if save.DurationSec <= 0 {
+		return nil, errors.New("轨迹不含时间信息,请填写活动时长")
+	}
+
+	activity := &model.Activity{
+		PetID:      petID,
+		RecordedBy: userID,
+		Source:     format,
+		HasTrack:   true,
+	}
+	if err := s.apply(ctx, activity, save); err != nil {
+		return nil, err
+	}
+
+	t.Downsample(maxStoredTrackPoints)
+	encoded, err := track.Encode(t)
+	if err != nil {
+		return nil, err
+	}
+	bounds := t.Bounds()
+	stored := &model.ActivityTrack{
+		PointCount: t.PointCount(),
+		MinLat:     bounds.MinLat,
+		MinLon:     bounds.MinLon,
+		MaxLat:     bounds.MaxLat,
+		MaxLon:     bounds.MaxLon,
+		Data:       encoded,
+	}
+	if err := s.activityRepo.Create(ctx, activity, stored); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "导入运动轨迹",
+		logger.Int("activity_id", int(activity.ID)),
+		logger.String("format", format),
+		logger.Int("points", stored.PointCount))
+	return activity, nil
+}
+
+// GetActivity 获取活动详情,宠物成员可见
+func (s *activityService) GetActivity(ctx context.Context, userID, id uint) (*model.Activity, error) {
+	return s.authorize(ctx, userID, id, model.PetRoleViewer)
+}
+
+// UpdateActivity 修改活动,导入的活动距离以轨迹为准
+func (s *activityService) UpdateActivity(ctx context.Context, userID, id uint, req *model.SaveActivityRequest) (*model.Activity, error) {
+	activity, err := s.authorize(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if activity.HasTrack {
+		req.DistanceM = activity.DistanceM
+	}
+	if err := s.apply(ctx, activity, req); err != nil {
+		return nil, err
+	}
+	if err := s.activityRepo.Update(ctx, activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// DeleteActivity 删除活动及其轨迹
+func (s *activityService) DeleteActivity(ctx context.Context, userID, id uint) error {
+	if _, err := s.authorize(ctx, userID, id, model.PetRoleEditor); err != nil {
+		return err
+	}
+	return s.activityRepo.Delete(ctx, id)
+}
+
+// ListActivities 宠物的活动列表,可按类型和时间区间筛选
+func (s *activityService) ListActivities(ctx context.Context, userID, petID uint, req *model.ListActivityRequest) ([]*model.Activity, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.activityRepo.List(ctx, petID, req, offset, limit)
+}
+
+// GetTrack 获取活动轨迹,bbox按GeoJSON规范为[最小经度, 最小纬度, 最大经度, 最大纬度]
+func (s *activityService) GetTrack(ctx context.Context, userID, id uint) (map[string]interface{}, error) {
+	activity, err := s.authorize(ctx, userID, id, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if !activity.HasTrack {
+		return nil, notFound("该活动没有轨迹")
+	}
+	stored, err := s.activityRepo.GetTrack(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "该活动没有轨迹")
+	}
+	t, err := track.Decode(stored.Data)
+	if err != nil {
+		logger.Error(ctx, "解码运动轨迹失败", logger.Int("activity_id", int(id)), logger.ErrorField(err))
+		return nil, err
+	}
+
+	feature := track.GeoJSON(t)
+	feature["bbox"] = []float64{stored.MinLon, stored.MinLat, stored.MaxLon, stored.MaxLat}
+	properties := feature["properties"].(map[string]interface{})
+	properties["activity_id"] = activity.ID
+	properties["distance_m"] = activity.DistanceM
+	properties["duration_sec"] = activity.DurationSec
+	properties["point_count"] = stored.PointCount
+	return feature, nil
+}
+
+// Stats 按周或按月汇总活动,无活动的周期补零以便绘图
+func (s *activityService) Stats(ctx context.Context, userID, petID uint, req *model.ActivityStatsRequest) (*model.ActivityStats, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	period := req.Period
+	if period == "" {
+		period = model.GoalPeriodWeek
+	}
+	if period != model.GoalPeriodWeek && period != model.GoalPeriodMonth {
+		return nil, fmt.Errorf("统计周期不支持: %s", period)
+	}
+	if req.Type != "" {
+		if _, ok := model.ActivityCalorieFactors[req.Type]; !ok {
+			return nil, fmt.Errorf("活动类型不支持: %s", req.Type)
+		}
+	}
+
+	// 区间对齐到周期边界,保证首尾桶完整
+	to := periodStart(period, time.Now())
+	if req.To != nil {
+		to = periodStart(period, *req.To)
+	}
+	to = nextPeriod(period, to)
+	from := addPeriods(period, to, -defaultStatsBuckets)
+	if req.From != nil {
+		from = periodStart(period, *req.From)
+	}
+	if !from.Before(to) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+	if addPeriods(period, from, 104).Before(to) {
+		return nil, errors.New("统计区间最多104个周期")
+	}
+
+	rows, err := s.activityRepo.Aggregate(ctx, petID, req.Type, from, to, period)
+	if err != nil {
+		return nil, err
+	}
+	byBucket := make(map[string]*model.ActivityBucket, len(rows))
+	for _, row := range rows {
+		byBucket[row.Bucket.Format(model.DateLayout)] = row
+	}
+
+	stats := &model.ActivityStats{Period: period, From: from, To: to, Buckets: make([]*model.ActivityBucket, 0)}
+	for b := from; b.Before(to); b = nextPeriod(period, b) {
+		bucket, ok := byBucket[b.Format(model.DateLayout)]
+		if !ok {
+			bucket = &model.ActivityBucket{}
+		}
+		bucket.Bucket = b
+		bucket.DistanceM = math.Round(bucket.DistanceM*10) / 10
+		bucket.PaceSecPerKm = paceSecPerKm(float64(bucket.TrackedDurationSec), bucket.DistanceM)
+		stats.Buckets = append(stats.Buckets, bucket)
+
+		stats.Total.Count += bucket.Count
+		stats.Total.DurationSec += bucket.DurationSec
+		stats.Total.DistanceM += bucket.DistanceM
+		stats.Total.Calories += bucket.Calories
+		stats.Total.TrackedDurationSec += bucket.TrackedDurationSec
+	}
+	stats.Total.Bucket = from
+	stats.Total.DistanceM = math.Round(stats.Total.DistanceM*10) / 10
+	stats.Total.PaceSecPerKm = paceSecPerKm(float64(stats.Total.TrackedDurationSec), stats.Total.DistanceM)
+	return stats, nil
+}
+
+// SaveGoal 设置运动目标,同一周期和指标已有目标时覆盖
+func (s *activityService) SaveGoal(ctx context.Context, userID, petID uint, req *model.SaveActivityGoalRequest) (*model.ActivityGoal, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+	if req.Period != model.GoalPeriodWeek && req.Period != model.GoalPeriodMonth {
+		return nil, fmt.Errorf("目标周期不支持: %s", req.Period)
+	}
+	switch req.Metric {
+	case model.GoalMetricDistance, model.GoalMetricDuration, model.GoalMetricCount:
+	default:
+		return nil, fmt.Errorf("目标指标不支持: %s", req.Metric)
+	}
+	if req.Target <= 0 {
+		return nil, errors.New("目标值必须大于0")
+	}
+	if req.Metric == model.GoalMetricCount && req.Target != math.Trunc(req.Target) {
+		return nil, errors.New("次数目标必须为整数")
+	}
+
+	goal := &model.ActivityGoal{
+		PetID:  petID,
+		Period: req.Period,
+		Metric: req.Metric,
+		Target: math.Round(req.Target*10) / 10,
+	}
+	if err := s.activityRepo.SaveGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, petID, []*model.ActivityGoal{goal}); err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// ListGoals 获取运动目标及本周(周一起)或本月的完成情况
+func (s *activityService) ListGoals(ctx context.Context, userID, petID uint) ([]*model.ActivityGoal, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	goals, err := s.activityRepo.ListGoals(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, petID, goals); err != nil {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// DeleteGoal 删除运动目标
+func (s *activityService) DeleteGoal(ctx context.Context, userID, id uint) error {
+	goal, err := s.activityRepo.GetGoal(ctx, id)
+	if err != nil {
+		return checkNotFound(err, "运动目标不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, goal.PetID, model.PetRoleEditor); err != nil {
+		return err
+	}
+	return s.activityRepo.DeleteGoal(ctx, id)
+}
+
+// authorize 获取活动并校验当前用户对所属宠物的角色
+func (s *activityService) authorize(ctx context.Context, userID, id uint, required string) (*model.Activity, error) {
+	activity, err := s.activityRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "运动记录不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, activity.PetID, required); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// apply 校验请求并写入活动,同时计算配速和热量
+func (s *activityService) apply(ctx context.Context, activity *model.Activity, req *model.SaveActivityRequest) error {
+	factor, ok := model.ActivityCalorieFactors[req.Type]
+	if !ok {
+		return fmt.Errorf("活动类型不支持: %s", req.Type)
+	}
+	if req.StartedAt.IsZero() {
+		return errors.New("开始时间不能为空")
+	}
+	if req.StartedAt.After(time.Now().Add(5 * time.Minute)) {
+		return errors.New("开始时间不能晚于当前时间")
+	}
+	if req.DurationSec <= 0 {
+		return errors.New("活动时长必须大于0")
+	}
+	if time.Duration(req.DurationSec)*time.Second > maxActivityDuration {
+		return fmt.Errorf("单次活动时长不能超过%d小时", int(maxActivityDuration.Hours()))
+	}
+	if req.DistanceM < 0 || req.DistanceM > maxActivityDistance {
+		return fmt.Errorf("活动距离需在0-%dkm之间", maxActivityDistance/1000)
+	}
+
+	activity.Type = req.Type
+	activity.Title = truncate(strings.TrimSpace(req.Title), 100)
+	activity.StartedAt = req.StartedAt
+	activity.DurationSec = req.DurationSec
+	activity.DistanceM = math.Round(req.DistanceM*10) / 10
+	activity.Notes = truncate(strings.TrimSpace(req.Notes), 500)
+	activity.PaceSecPerKm = paceSecPerKm(float64(activity.DurationSec), activity.DistanceM)
+
+	// 热量按最近一次体重估算,没有体重记录时不估算
+	activity.Calories = 0
+	weight, err := s.measurementRepo.Latest(ctx, activity.PetID, model.MetricWeight)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if activity.DistanceM > 0 {
+		activity.Calories = int(math.Round(factor.PerKgKm * weight.Value * activity.DistanceM / 1000))
+	} else {
+		activity.Calories = int(math.Round(factor.PerKgHour * weight.Value * float64(activity.DurationSec) / 3600))
+	}
+	return nil
+}
+
+// fillProgress 计算目标在当前周期的完成情况,同一周期只汇总一次
+func (s *activityService) fillProgress(ctx context.Context, petID uint, goals []*model.ActivityGoal) error {
+	now := time.Now()
+	sums := make(map[string]*model.ActivityBucket, 2)
+	for _, goal := range goals {
+		start := periodStart(goal.Period, now)
+		end := nextPeriod(goal.Period, start)
+		sum, ok := sums[goal.Period]
+		if !ok {
+			var err error
+			if sum, err = s.activityRepo.Sum(ctx, petID, start, end); err != nil {
+				return err
+			}
+			sums[goal.Period] = sum
+		}
+
+		var current float64
+		switch goal.Metric {
+		case model.GoalMetricDistance:
+			current = math.Round(sum.DistanceM*10) / 10
+		case model.GoalMetricDuration:
+			current = float64(sum.DurationSec)
+		case model.GoalMetricCount:
+			current = float64(sum.Count)
+		}
+		goal.Progress = &model.GoalProgress{
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Current:     current,
+			Percent:     round2(math.Min(current/goal.Target*100, 100)),
+			Achieved:    current >= goal.Target,
+		}
+	}
+	return nil
+}
+
+// paceSecPerKm 计算配速(秒/公里),无距离时为0
+func paceSecPerKm(durationSec, distanceM float64) int {
+	if distanceM <= 0 {
+		return 0
+	}
+	return int(math.Round(durationSec / (distanceM / 1000)))
+}
+
+// periodStart 返回t所在周期的起点,周以周一为起始
+func periodStart(period string, t time.Time) time.Time {
+	t = t.In(time.Local)
+	if period == model.GoalPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.Local)
+}
+
+// nextPeriod 返回下一个周期的起点
+func nextPeriod(period string, start time.Time) time.Time {
+	return addPeriods(period, start, 1)
+}
+
+// addPeriods 在周期起点上增加n个周期
+func addPeriods(period string, start time.Time, n int) time.Time {
+	if period == model.GoalPeriodMonth {
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, 7*n)
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+)
+
+// AdoptionService 领养服务接口
+type AdoptionService interface {
+	CreateShelter(ctx context.Context, userID uint, req *model.CreateShelterRequest) (*model.Shelter, error)
+	UpdateShelter(ctx context.Context, userID, shelterID uint, req *model.UpdateShelterRequest) (*model.Shelter, error)
+	GetShelter(ctx context.Context, shelterID uint) (*model.Shelter, error)
+
+	CreateListing(ctx context.Context, userID, shelterID uint, req *model.CreateListingRequest) (*model.AdoptionListing, error)
+	UpdateListing(ctx context.Context, userID, listingID uint, req *model.UpdateListingRequest) (*model.AdoptionListing, error)
+	GetListing(ctx context.Context, listingID uint) (*model.AdoptionListing, error)
+	SearchListings(ctx context.Context, req *model.ListListingRequest) ([]*model.AdoptionListing, int64, error)
+
+	SubmitApplication(ctx context.Context, userID, listingID uint, req *model.SubmitApplicationRequest) (*model.AdoptionApplication, error)
+	GetApplication(ctx context.Context, userID, applicationID uint) (*model.AdoptionApplication, error)
+	ListMyApplications(ctx context.Context, userID uint, req *model.ListApplicationRequest) ([]*model.AdoptionApplication, int64, error)
+	ListShelterApplications(ctx context.Context, userID, shelterID uint, req *model.ListApplicationRequest) ([]*model.AdoptionApplication, int64, error)
+	ReviewApplication(ctx context.Context, userID, applicationID uint, req *model.ReviewApplicationRequest) (*model.AdoptionApplication, error)
+}
+
+// adoptionService 领养服务实现
+type adoptionService struct {
+	shelterRepo  repository.ShelterRepository
+	adoptionRepo repository.AdoptionRepository
+}
+
+// NewAdoptionService 创建领养服务
+func NewAdoptionService(shelterRepo repository.ShelterRepository, adoptionRepo repository.AdoptionRepository) AdoptionService {
+	return &adoptionService{
+		shelterRepo:  shelterRepo,
+		adoptionRepo: adoptionRepo,
+	}
+}
+
+// CreateShelter 创建救助机构,创建者成为机构管理员
+func (s *adoptionService) CreateShelter(ctx context.Context, userID uint, req *model.CreateShelterRequest) (*model.Shelter, error) {
+	shelter := &model.Shelter{
+		OwnerID:     userID,
+		Name:        req.Name,
+		City:        req.City,
+		Address:     req.Address,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Description: req.Description,
+		Status:      1,
+	}
+	if err := s.shelterRepo.Create(ctx, shelter); err != nil {
+		return nil, err
+	}
+	return shelter, nil
+}
+
+// UpdateShelter 更新救助机构信息
+func (s *adoptionService) UpdateShelter(ctx context.Context, userID, shelterID uint, req *model.UpdateShelterRequest) (*model.Shelter, error) {
+	shelter, err := s.getManagedShelter(ctx, userID, shelterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		shelter.Name = req.Name
+	}
+	if req.City != "" {
+		shelter.City = req.City
+	}
+	if req.Address != "" {
+		shelter.Address = req.Address
+	}
+	if req.Phone != "" {
+		shelter.Phone = req.Phone
+	}
+	if req.Email != "" {
+		shelter.Email = req.Email
+	}
+	if req.Description != "" {
+		shelter.Description = req.Description
+	}
+
+	if err := s.shelterRepo.Update(ctx, shelter); err != nil {
+		return nil, err
+	}
+	return shelter, nil
+}
+
+// GetShelter 获取救助机构详情
+func (s *adoptionService) GetShelter(ctx context.Context, shelterID uint) (*model.Shelter, error) {
+	shelter, err := s.shelterRepo.GetByID(ctx, shelterID)
+	if err != nil {
+		return nil, checkNotFound(err, "救助机构不存在")
+	}
+	return shelter, nil
+}
+
+// CreateListing 发布领养信息,宠物档案由机构持有直至领养完成
+func (s *adoptionService) CreateListing(ctx context.Context, userID, shelterID uint, req *model.CreateListingRequest) (*model.AdoptionListing, error) {
+	shelter, err := s.getManagedShelter(ctx, userID, shelterID)
+	if err != nil {
+		return nil, err
+	}
+
+	pet := newPetFromRequest(&req.Pet)
+	pet.ShelterID = shelter.ID
+
+	city := req.City
+	if city == "" {
+		city = shelter.City
+	}
+	listing := &model.AdoptionListing{
+		ShelterID:   shelter.ID,
+		Title:       req.Title,
+		Description: req.Description,
+		City:        city,
+		Fee:         req.Fee,
+		Status:      model.ListingStatusOpen,
+	}
+
+	if err := s.adoptionRepo.CreateListing(ctx, pet, listing); err != nil {
+		return nil, err
+	}
+	listing.Pet = pet
+	listing.Shelter = shelter
+	return listing, nil
+}
+
+// UpdateListing 更新领养信息,已领养的信息不可修改,有通过审核的申请时不可修改状态
+func (s *adoptionService) UpdateListing(ctx context.Context, userID, listingID uint, req *model.UpdateListingRequest) (*model.AdoptionListing, error) {
+	listing, err := s.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getManagedShelter(ctx, userID, listing.ShelterID); err != nil {
+		return nil, err
+	}
+	if listing.Status == model.ListingStatusAdopted {
+		return nil, errors.New("宠物已被领养,无法修改领养信息")
+	}
+
+	if req.Title != "" {
+		listing.Title = req.Title
+	}
+	if req.Description != "" {
+		listing.Description = req.Description
+	}
+	if req.City != "" {
+		listing.City = req.City
+	}
+	if req.Fee != nil {
+		listing.Fee = *req.Fee
+	}
+	// 仅允许在open和closed之间切换,审核通过后的pending状态由申请流转维护
+	var fromStatus string
+	if req.Status != "" && req.Status != model.ListingStatusOpen && req.Status != model.ListingStatusClosed {
+		return nil, invalidParam("领养状态只能修改为open或closed")
+	}
+	if req.Status != "" && req.Status != listing.Status {
+		if listing.Status != model.ListingStatusOpen && listing.Status != model.ListingStatusClosed {
+			return nil, errors.New("该宠物已有通过审核的申请,无法修改领养状态")
+		}
+		fromStatus = listing.Status
+		listing.Status = req.Status
+	}
+
+	if err := s.adoptionRepo.UpdateListing(ctx, listing, fromStatus); err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// GetListing 获取领养信息详情
+func (s *adoptionService) GetListing(ctx context.Context, listingID uint) (*model.AdoptionListing, error) {
+	listing, err := s.adoptionRepo.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, checkNotFound(err, "领养信息不存在")
+	}
+	return listing, nil
+}
+
+// SearchListings 按物种、年龄、体型、城市检索可领养宠物
+func (s *adoptionService) SearchListings(ctx context.Context, req *model.ListListingRequest) ([]*model.AdoptionListing, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	filter := &repository.ListingFilter{
+		Species:   req.Species,
+		Size:      req.Size,
+		City:      req.City,
+		ShelterID: req.ShelterID,
+		Keyword:   req.Keyword,
+		Offset:    offset,
+		Limit:     limit,
+	}
+
+	// 年龄区间换算为出生日期区间: 年龄不小于min即出生不晚于now-min,年龄不大于max即出生不早于now-(max+1)
+	now := time.Now()
+	if req.MinAgeMonths != nil {
+		bornBefore := now.AddDate(0, -*req.MinAgeMonths, 0)
+		filter.BornBefore = &bornBefore
+	}
+	if req.MaxAgeMonths != nil {
+		bornAfter := now.AddDate(0, -(*req.MaxAgeMonths + 1), 0)
+		filter.BornAfter = &bornAfter
+	}
+
+	return s.adoptionRepo.SearchListings(ctx, filter)
+}
+
+// SubmitApplication 提交领养申请
+func (s *adoptionService) SubmitApplication(ctx context.Context, userID, listingID uint, req *model.SubmitApplicationRequest) (*model.AdoptionApplication, error) {
+	listing, err := s.GetListing(ctx, listingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status != model.ListingStatusOpen {
+		return nil, errors.New("该宠物当前不接受领养申请")
+	}
+	if listing.Shelter != nil && listing.Shelter.OwnerID == userID {
+		return nil, errors.New("不能申请领养本机构发布的宠物")
+	}
+	if len(req.Answers) == 0 {
+		return nil, errors.New("请填写领养问卷")
+	}
+
+	exists, err := s.adoptionRepo.HasActiveApplication(ctx, listingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("已存在处理中的领养申请")
+	}
+
+	application := &model.AdoptionApplication{
+		ListingID:   listing.ID,
+		ShelterID:   listing.ShelterID,
+		ApplicantID: userID,
+		Answers:     req.Answers,
+		Status:      model.ApplicationStatusSubmitted,
+	}
+	if err := s.adoptionRepo.CreateApplication(ctx, application); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// GetApplication 获取领养申请,仅申请人与机构管理员可查看
+func (s *adoptionService) GetApplication(ctx context.Context, userID, applicationID uint) (*model.AdoptionApplication, error) {
+	application, err := s.adoptionRepo.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, checkNotFound(err, "领养申请不存在")
+	}
+	if application.ApplicantID == userID {
+		return application, nil
+	}
+	if _, err := s.getManagedShelter(ctx, userID, application.ShelterID); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// ListMyApplications 获取当前用户提交的领养申请
+func (s *adoptionService) ListMyApplications(ctx context.Context, userID uint, req *model.ListApplicationRequest) ([]*model.AdoptionApplication, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.adoptionRepo.ListApplicationsByApplicant(ctx, userID, req.Status, offset, limit)
+}
+
+// ListShelterApplications 获取机构收到的领养申请
+func (s *adoptionService) ListShelterApplications(ctx context.Context, userID, shelterID uint, req *model.ListApplicationRequest) ([]*model.AdoptionApplication, int64, error) {
+	if _, err := s.getManagedShelter(ctx, userID, shelterID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.adoptionRepo.ListApplicationsByShelter(ctx, shelterID, req.Status, offset, limit)
+}
+
+// ReviewApplication 审核领养申请,按 submitted → screening → approved/rejected → completed 流转
+func (s *adoptionService) ReviewApplication(ctx context.Context, userID, applicationID uint, req *model.ReviewApplicationRequest) (*model.AdoptionApplication, error) {
+	application, err := s.adoptionRepo.GetApplication(ctx, applicationID)
+	if err != nil {
+		return nil, checkNotFound(err, "领养申请不存在")
+	}
+	if _, err := s.getManagedShelter(ctx, userID, application.ShelterID); err != nil {
+		return nil, err
+	}
+	if !application.CanTransitTo(req.Status) {
+		logger.Warn(ctx, "领养申请状态流转不合法",
+			logger.Int("id", int(applicationID)),
+			logger.String("from", application.Status),
+			logger.String("to", req.Status),
+		)
+		return nil, errors.New("当前状态不允许该操作")
+	}
+
+	listing, err := s.GetListing(ctx, application.ListingID)
+	if err != nil {
+		return nil, err
+	}
+	if listing.Status == model.ListingStatusAdopted && req.Status != model.ApplicationStatusRejected {
+		return nil, errors.New("该宠物已被领养")
+	}
+	if req.Status == model.ApplicationStatusApproved && listing.Status != model.ListingStatusOpen {
+		return nil, errors.New("该宠物已有通过审核的申请")
+	}
+
+	now := time.Now()
+	fromStatus := application.Status
+	application.Status = req.Status
+	application.ReviewerID = userID
+	application.ReviewNote = req.Note
+	application.ReviewedAt = &now
+
+	if req.Status == model.ApplicationStatusCompleted {
+		application.CompletedAt = &now
+		if err := s.adoptionRepo.CompleteApplication(ctx, application, listing); err != nil {
+			return nil, err
+		}
+		return application, nil
+	}
+
+	// 通过审核时领养信息锁定为pending,已通过的申请被拒绝时重新开放,与申请状态在同一事务内更新
+	if err := s.adoptionRepo.UpdateApplicationStatus(ctx, application, fromStatus); err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+// getManagedShelter 获取机构并校验当前用户是否为管理员
+func (s *adoptionService) getManagedShelter(ctx context.Context, userID, shelterID uint) (*model.Shelter, error) {
+	shelter, err := s.GetShelter(ctx, shelterID)
+	if err != nil {
+		return nil, err
+	}
+	if shelter.OwnerID != userID {
+		logger.Warn(ctx, "非机构管理员操作", logger.Int("shelter_id", int(shelterID)), logger.Int("user_id", int(userID)))
+		return nil, forbidden("仅机构管理员可执行该操作")
+	}
+	return shelter, nil
+}
@@ -0,0 +1,637 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/config"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+)
+
+const (
+	// maxAppointmentServices 单次预约最多选择的服务数
+	maxAppointmentServices = 10
+	// maxServiceDurationMin 单项服务的最长时长(分钟)
+	maxServiceDurationMin = 480
+)
+
+// AppointmentService 诊所服务目录与门诊预约服务接口
+type AppointmentService interface {
+	CreateService(ctx context.Context, userID, clinicID uint, req *model.SaveClinicServiceRequest) (*model.ClinicServiceItem, error)
+	UpdateService(ctx context.Context, userID, serviceID uint, req *model.SaveClinicServiceRequest) (*model.ClinicServiceItem, error)
+	DeleteService(ctx context.Context, userID, serviceID uint) error
+	// ListServices 诊所上架的服务目录
+	ListServices(ctx context.Context, clinicID uint, req *model.ListClinicServiceRequest) ([]*model.ClinicServiceItem, error)
+	// ListAllServices 诊所全部服务,含已下架的服务,需要诊所人员权限
+	ListAllServices(ctx context.Context, userID, clinicID uint, req *model.ListClinicServiceRequest) ([]*model.ClinicServiceItem, error)
+
+	// Quote 按宠物体型汇总所选服务的时长与价格,传入开始时间时同时检查是否可约
+	Quote(ctx context.Context, userID, clinicID uint, req *model.AppointmentQuoteRequest) (*model.AppointmentQuote, error)
+	Availability(ctx context.Context, userID, clinicID uint, req *model.AppointmentAvailabilityRequest) (*model.AppointmentAvailability, error)
+	Book(ctx context.Context, userID, clinicID uint, req *model.CreateAppointmentRequest) (*model.Appointment, error)
+	Get(ctx context.Context, userID, appointmentID uint) (*model.Appointment, error)
+	ListMine(ctx context.Context, userID uint, req *model.ListAppointmentRequest) ([]*model.Appointment, int64, error)
+	Cancel(ctx context.Context, userID, appointmentID uint, reason string) (*model.Appointment, error)
+
+	ListClinic(ctx context.Context, userID, clinicID uint, req *model.ListAppointmentRequest) ([]*model.Appointment, int64, error)
+	// UpdateStatus 诊所人员确认、拒绝、取消、完成预约或标记爽约
+	UpdateStatus(ctx context.Context, userID, appointmentID uint, req *model.UpdateAppointmentStatusRequest) (*model.Appointment, error)
+}
+
+// appointmentService 诊所服务目录与门诊预约服务实现
+type appointmentService struct {
+	appointmentRepo repository.AppointmentRepository
+	clinicService   ClinicService
+	petService      PetService
+	pointsService   PointsService
+	notifier        notifier.Notifier
+	cfg             *config.AppointmentConfig
+}
+
+// NewAppointmentService 创建诊所服务目录与门诊预约服务
+func NewAppointmentService(appointmentRepo repository.AppointmentRepository, clinicService ClinicService, petService PetService,
+	pointsService PointsService, n notifier.Notifier, cfg *config.AppointmentConfig) AppointmentService {
+	// 时段间隔用于取模和步进,配置非法时使用默认值
+	if cfg.SlotMinutes <= 0 {
+		cfg.SlotMinutes = 15
+	}
+	return &appointmentService{
+		appointmentRepo: appointmentRepo,
+		clinicService:   clinicService,
+		petService:      petService,
+		pointsService:   pointsService,
+		notifier:        n,
+		cfg:             cfg,
+	}
+}
+
+// CreateService 创建服务,需要诊所管理员或前台权限,默认上架
+func (s *appointmentService) CreateService(ctx context.Context, userID, clinicID uint, req *model.SaveClinicServiceRequest) (*model.ClinicServiceItem, error) {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID, model.ClinicRoleManager, model.ClinicRoleStaff); err != nil {
+		return nil, err
+	}
+	item := &model.ClinicServiceItem{ClinicID: clinicID, Status: model.ClinicServiceOnline}
+	if err := applyServiceRequest(item, req); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.CreateService(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.appointmentRepo.GetService(ctx, item.ID)
+}
+
+// UpdateService 修改服务,价格整体覆盖;已有预约使用下单时的快照,不受影响
+func (s *appointmentService) UpdateService(ctx context.Context, userID, serviceID uint, req *model.SaveClinicServiceRequest) (*model.ClinicServiceItem, error) {
+	item, err := s.getManagedService(ctx, userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyServiceRequest(item, req); err != nil {
+		return nil, err
+	}
+	if err := s.appointmentRepo.UpdateService(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.appointmentRepo.GetService(ctx, item.ID)
+}
+
+// DeleteService 删除服务
+func (s *appointmentService) DeleteService(ctx context.Context, userID, serviceID uint) error {
+	item, err := s.getManagedService(ctx, userID, serviceID)
+	if err != nil {
+		return err
+	}
+	return s.appointmentRepo.DeleteService(ctx, item.ID)
+}
+
+// ListServices 诊所上架的服务目录
+func (s *appointmentService) ListServices(ctx context.Context, clinicID uint, req *model.ListClinicServiceRequest) ([]*model.ClinicServiceItem, error) {
+	if _, err := s.clinicService.GetClinic(ctx, clinicID); err != nil {
+		return nil, err
+	}
+	return s.appointmentRepo.ListServices(ctx, clinicID, req.Category, true)
+}
+
+// ListAllServices 诊所全部服务
+func (s *appointmentService) ListAllServices(ctx context.Context, userID, clinicID uint, req *model.ListClinicServiceRequest) ([]*model.ClinicServiceItem, error) {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID); err != nil {
+		return nil, err
+	}
+	return s.appointmentRepo.ListServices(ctx, clinicID, req.Category, false)
+}
+
+// Quote 预约报价
+func (s *appointmentService) Quote(ctx context.Context, userID, clinicID uint, req *model.AppointmentQuoteRequest) (*model.AppointmentQuote, error) {
+	quote, err := s.buildQuote(ctx, userID, clinicID, req.PetID, req.PetSize, req.ServiceIDs, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if req.StartAt == nil {
+		return quote, nil
+	}
+
+	start := req.StartAt.In(time.Local)
+	end := start.Add(time.Duration(quote.DurationMin) * time.Minute)
+	quote.StartAt, quote.EndAt = &start, &end
+	reason, err := s.checkSlot(ctx, clinicID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	available := reason == ""
+	quote.Available = &available
+	quote.Reason = reason
+	return quote, nil
+}
+
+// Availability 某天可约的开始时间,按所选服务的总时长和诊所同时接待容量计算
+func (s *appointmentService) Availability(ctx context.Context, userID, clinicID uint, req *model.AppointmentAvailabilityRequest) (*model.AppointmentAvailability, error) {
+	day, err := parseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.buildQuote(ctx, userID, clinicID, req.PetID, req.PetSize, req.ServiceIDs, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.AppointmentAvailability{
+		Date:        req.Date,
+		DurationMin: quote.DurationMin,
+		TotalPrice:  quote.TotalPrice,
+		Slots:       []time.Time{},
+	}
+	duration := time.Duration(quote.DurationMin) * time.Minute
+	open := day.Add(time.Duration(s.cfg.OpenHour) * time.Hour)
+	closing := day.Add(time.Duration(s.cfg.CloseHour) * time.Hour)
+	if open.Add(duration).After(closing) {
+		return result, nil
+	}
+
+	booked, err := s.appointmentRepo.ListActive(ctx, clinicID, open, closing)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	step := time.Duration(s.cfg.SlotMinutes) * time.Minute
+	for start := open; !start.Add(duration).After(closing); start = start.Add(step) {
+		end := start.Add(duration)
+		if s.slotReason(start, end, now) != "" {
+			continue
+		}
+		if model.PeakConcurrency(booked, start, end) >= s.cfg.Capacity {
+			continue
+		}
+		result.Slots = append(result.Slots, start)
+	}
+	return result, nil
+}
+
+// Book 预约,价格与报价不一致时需重新报价确认
+func (s *appointmentService) Book(ctx context.Context, userID, clinicID uint, req *model.CreateAppointmentRequest) (*model.Appointment, error) {
+	quote, err := s.buildQuote(ctx, userID, clinicID, req.PetID, req.PetSize, req.ServiceIDs, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if req.QuotedPrice != quote.TotalPrice {
+		return nil, errors.New("服务价格已变化,请重新报价确认")
+	}
+
+	start := req.StartAt.In(time.Local)
+	end := start.Add(time.Duration(quote.DurationMin) * time.Minute)
+	if reason := s.slotReason(start, end, time.Now()); reason != "" {
+		return nil, errors.New(reason)
+	}
+
+	no, err := newOrderNo("A")
+	if err != nil {
+		return nil, err
+	}
+	appointment := &model.Appointment{
+		AppointmentNo: no,
+		ClinicID:      clinicID,
+		UserID:        userID,
+		PetID:         quote.PetID,
+		PetSize:       quote.PetSize,
+		StartAt:       start,
+		EndAt:         end,
+		DurationMin:   quote.DurationMin,
+		TotalPrice:    quote.TotalPrice,
+		Status:        model.AppointmentRequested,
+		Notes:         strings.TrimSpace(req.Notes),
+		Items:         quote.Items,
+	}
+	if err := s.appointmentRepo.Create(ctx, appointment, s.cfg.Capacity); err != nil {
+		return nil, err
+	}
+	s.notifyClinic(ctx, appointment, "新的门诊预约",
+		fmt.Sprintf("收到%s的预约申请,预计%d分钟", appointment.StartAt.Format("2006-01-02 15:04"), appointment.DurationMin))
+	return s.appointmentRepo.GetByID(ctx, appointment.ID)
+}
+
+// Get 预约详情,仅预约人和诊所人员可见
+func (s *appointmentService) Get(ctx context.Context, userID, appointmentID uint) (*model.Appointment, error) {
+	appointment, err := s.getAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.UserID == userID {
+		return appointment, nil
+	}
+	if _, err := s.clinicService.Authorize(ctx, userID, appointment.ClinicID); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return nil, forbidden("无权查看该预约")
+		}
+		return nil, err
+	}
+	return appointment, nil
+}
+
+// ListMine 我的预约
+func (s *appointmentService) ListMine(ctx context.Context, userID uint, req *model.ListAppointmentRequest) ([]*model.Appointment, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.appointmentRepo.ListByUser(ctx, userID, req.Status, offset, limit)
+}
+
+// Cancel 预约人在开始前取消预约
+func (s *appointmentService) Cancel(ctx context.Context, userID, appointmentID uint, reason string) (*model.Appointment, error) {
+	appointment, err := s.getAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.UserID != userID {
+		return nil, forbidden("只能取消自己的预约")
+	}
+	if !model.CanTransitAppointment(appointment.Status, model.AppointmentCancelled) {
+		return nil, errors.New("当前状态不可取消")
+	}
+	if !time.Now().Before(appointment.StartAt) {
+		return nil, errors.New("预约已开始,无法取消")
+	}
+
+	from := appointment.Status
+	appointment.Status = model.AppointmentCancelled
+	appointment.StatusNote = strings.TrimSpace(reason)
+	if err := s.appointmentRepo.UpdateStatus(ctx, appointment, from); err != nil {
+		return nil, err
+	}
+	s.notifyClinic(ctx, appointment, "门诊预约已取消",
+		fmt.Sprintf("%s的预约已被预约人取消", appointment.StartAt.Format("2006-01-02 15:04")))
+	return appointment, nil
+}
+
+// ListClinic 诊所的预约,需要诊所人员权限
+func (s *appointmentService) ListClinic(ctx context.Context, userID, clinicID uint, req *model.ListAppointmentRequest) ([]*model.Appointment, int64, error) {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID); err != nil {
+		return nil, 0, err
+	}
+	var from, to *time.Time
+	if req.Date != "" {
+		day, err := parseDate(req.Date)
+		if err != nil {
+			return nil, 0, err
+		}
+		next := day.AddDate(0, 0, 1)
+		from, to = &day, &next
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.appointmentRepo.ListByClinic(ctx, clinicID, req.Status, from, to, offset, limit)
+}
+
+// UpdateStatus 诊所人员变更预约状态,完成时记录接诊兽医并为预约人发放积分
+func (s *appointmentService) UpdateStatus(ctx context.Context, userID, appointmentID uint, req *model.UpdateAppointmentStatusRequest) (*model.Appointment, error) {
+	appointment, err := s.getAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.clinicService.Authorize(ctx, userID, appointment.ClinicID)
+	if err != nil {
+		return nil, err
+	}
+	if !model.CanTransitAppointment(appointment.Status, req.Status) {
+		return nil, fmt.Errorf("预约状态不能从%s变更为%s", appointment.Status, req.Status)
+	}
+	now := time.Now()
+	if (req.Status == model.AppointmentCompleted || req.Status == model.AppointmentNoShow) && now.Before(appointment.StartAt) {
+		return nil, errors.New("预约开始后才能标记完成或爽约")
+	}
+
+	from := appointment.Status
+	appointment.Status = req.Status
+	appointment.StatusNote = strings.TrimSpace(req.Note)
+	switch req.Status {
+	case model.AppointmentConfirmed:
+		appointment.ConfirmedAt = &now
+	case model.AppointmentCompleted:
+		appointment.CompletedAt = &now
+		vetID, err := s.attendingVet(ctx, staff, req.VetID)
+		if err != nil {
+			return nil, err
+		}
+		appointment.VetID = vetID
+	}
+	if err := s.appointmentRepo.UpdateStatus(ctx, appointment, from); err != nil {
+		return nil, err
+	}
+
+	when := appointment.StartAt.Format("2006-01-02 15:04")
+	switch req.Status {
+	case model.AppointmentConfirmed:
+		s.notifyOwner(ctx, appointment, "门诊预约已确认", fmt.Sprintf("您%s的预约已确认,请准时到店", when))
+	case model.AppointmentDeclined:
+		s.notifyOwner(ctx, appointment, "门诊预约未通过", fmt.Sprintf("您%s的预约未被诊所接受", when))
+	case model.AppointmentCancelled:
+		s.notifyOwner(ctx, appointment, "门诊预约已取消", fmt.Sprintf("您%s的预约已被诊所取消", when))
+	case model.AppointmentCompleted:
+		s.pointsService.EarnForAppointment(ctx, appointment.UserID, appointment.ID)
+		s.notifyOwner(ctx, appointment, "门诊服务已完成", "感谢您的光临,欢迎评价本次服务")
+	}
+	return appointment, nil
+}
+
+// attendingVet 确定接诊兽医:指定时须为本诊所兽医,未指定且操作人为兽医时取操作人,否则不记录
+func (s *appointmentService) attendingVet(ctx context.Context, operator *model.ClinicStaff, vetID uint) (uint, error) {
+	if vetID == 0 {
+		if operator.Role == model.ClinicRoleVet {
+			return operator.UserID, nil
+		}
+		return 0, nil
+	}
+	if _, err := s.clinicService.Authorize(ctx, vetID, operator.ClinicID, model.ClinicRoleVet); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return 0, errors.New("接诊兽医须为本诊所的执业兽医")
+		}
+		return 0, err
+	}
+	return vetID, nil
+}
+
+// buildQuote 校验宠物权限和所选服务,按体型汇总时长与价格并生成服务快照
+func (s *appointmentService) buildQuote(ctx context.Context, userID, clinicID, petID uint, petSize string, serviceIDs []uint, role string) (*model.AppointmentQuote, error) {
+	clinic, err := s.clinicService.GetClinic(ctx, clinicID)
+	if err != nil {
+		return nil, err
+	}
+	if clinic.Status != 1 {
+		return nil, errors.New("该诊所暂停营业")
+	}
+	pet, err := s.petService.Authorize(ctx, userID, petID, role)
+	if err != nil {
+		return nil, err
+	}
+	size := pet.Size
+	if size == "" {
+		size = petSize
+	}
+	if !model.PetSizes[size] {
+		return nil, errors.New("宠物未登记体型,请选择体型后报价")
+	}
+
+	ids := uniqueIDs(serviceIDs)
+	if len(ids) == 0 {
+		return nil, errors.New("请选择预约的服务")
+	}
+	if len(ids) > maxAppointmentServices {
+		return nil, fmt.Errorf("单次预约最多选择%d项服务", maxAppointmentServices)
+	}
+	services, err := s.appointmentRepo.GetServicesByIDs(ctx, clinicID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*model.ClinicServiceItem, len(services))
+	for _, item := range services {
+		byID[item.ID] = item
+	}
+
+	quote := &model.AppointmentQuote{ClinicID: clinicID, PetID: pet.ID, PetSize: size}
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok || item.Status != model.ClinicServiceOnline {
+			return nil, fmt.Errorf("服务%d不存在或已下架", id)
+		}
+		price := item.PriceFor(size)
+		if price == nil {
+			return nil, fmt.Errorf("服务「%s」不接待该体型的宠物", item.Name)
+		}
+		quote.Items = append(quote.Items, &model.AppointmentItem{
+			ServiceID:   item.ID,
+			Category:    item.Category,
+			Name:        item.Name,
+			DurationMin: price.DurationMin,
+			Price:       price.Price,
+		})
+		quote.DurationMin += price.DurationMin
+		quote.TotalPrice += price.Price
+	}
+	return quote, nil
+}
+
+// checkSlot 检查时段是否可约,返回不可约原因,可约时返回空字符串
+func (s *appointmentService) checkSlot(ctx context.Context, clinicID uint, start, end time.Time) (string, error) {
+	if reason := s.slotReason(start, end, time.Now()); reason != "" {
+		return reason, nil
+	}
+	booked, err := s.appointmentRepo.ListActive(ctx, clinicID, start, end)
+	if err != nil {
+		return "", err
+	}
+	if model.PeakConcurrency(booked, start, end) >= s.cfg.Capacity {
+		return repository.ErrAppointmentSlotFull.Error(), nil
+	}
+	return "", nil
+}
+
+// slotReason 校验开始时间是否落在可约网格、营业时间和可预约天数内
+func (s *appointmentService) slotReason(start, end, now time.Time) string {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	open := day.Add(time.Duration(s.cfg.OpenHour) * time.Hour)
+	closing := day.Add(time.Duration(s.cfg.CloseHour) * time.Hour)
+	if start.Before(open) || end.After(closing) {
+		return fmt.Sprintf("预约须在%02d:00至%02d:00之间完成", s.cfg.OpenHour, s.cfg.CloseHour)
+	}
+	if start.Sub(open)%(time.Duration(s.cfg.SlotMinutes)*time.Minute) != 0 {
+		return fmt.Sprintf("开始时间须为整%d分钟", s.cfg.SlotMinutes)
+	}
+	if start.Before(now.Add(time.Duration(s.cfg.MinLeadMinutes) * time.Minute)) {
+		return fmt.Sprintf("请至少提前%d分钟预约", s.cfg.MinLeadMinutes)
+	}
+	if daysBetween(now, start) > s.cfg.MaxDaysAhead {
+		return fmt.Sprintf("最多可提前%d天预约", s.cfg.MaxDaysAhead)
+	}
+	return ""
+}
+
+// getManagedService 获取服务并校验诊所管理权限
+func (s *appointmentService) getManagedService(ctx context.Context, userID, serviceID uint) (*model.ClinicServiceItem, error) {
+	item, err := s.appointmentRepo.GetService(ctx, serviceID)
+	if err != nil {
+		return nil, checkNotFound(err, "服务不存在")
+	}
+	if _, err := s.clinicService.Authorize(ctx, userID, item.ClinicID, model.ClinicRoleManager, model.ClinicRoleStaff); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// getAppointment 获取预约
+func (s *appointmentService) getAppointment(ctx context.Context, appointmentID uint) (*model.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, checkNotFound(err, "预约不存在")
+	}
+	return appointment, nil
+}
+
+// notifyClinic 通知诊所全部人员
+func (s *appointmentService) notifyClinic(ctx context.Context, appointment *model.Appointment, title, content string) {
+	staffIDs, err := s.clinicService.StaffUserIDs(ctx, appointment.ClinicID)
+	if err != nil {
+		logger.Error(ctx, "获取诊所人员失败", logger.Int("clinic_id", int(appointment.ClinicID)), logger.ErrorField(err))
+		return
+	}
+	for _, staffID := range staffIDs {
+		s.notify(ctx, appointment, staffID, title, content)
+	}
+}
+
+// notifyOwner 通知预约人
+func (s *appointmentService) notifyOwner(ctx context.Context, appointment *model.Appointment, title, content string) {
+	s.notify(ctx, appointment, appointment.UserID, title, content)
+}
+
+func (s *appointmentService) notify(ctx context.Context, appointment *model.Appointment, userID uint, title, content string) {
+	err := s.notifier.Notify(ctx, &notifier.Message{
+		UserID:  userID,
+		Type:    model.NotifyTypeAppointment,
+		Title:   title,
+		Content: content,
+		Data: map[string]interface{}{
+			"appointment_id": appointment.ID,
+			"clinic_id":      appointment.ClinicID,
+			"status":         appointment.Status,
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "发送门诊预约通知失败", logger.Int("appointment_id", int(appointment.ID)), logger.Int("user_id", int(userID)), logger.ErrorField(err))
+	}
+}
+
+// applyServiceRequest 校验并写入服务信息与各体型价格
+func applyServiceRequest(item *model.ClinicServiceItem, req *model.SaveClinicServiceRequest) error {
+	if !model.ServiceCategories[req.Category] {
+		return fmt.Errorf("不支持的服务类别: %s", req.Category)
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("服务名称不能为空")
+	}
+	if len(req.Prices) == 0 {
+		return errors.New("请至少设置一种体型的时长和价格")
+	}
+	seen := make(map[string]bool, len(req.Prices))
+	prices := make([]*model.ClinicServicePrice, 0, len(req.Prices))
+	for _, p := range req.Prices {
+		if !model.PetSizes[p.Size] {
+			return fmt.Errorf("不支持的体型: %s", p.Size)
+		}
+		if seen[p.Size] {
+			return fmt.Errorf("体型%s的价格重复设置", p.Size)
+		}
+		seen[p.Size] = true
+		if p.DurationMin < 5 || p.DurationMin > maxServiceDurationMin {
+			return fmt.Errorf("服务时长须在5至%d分钟之间", maxServiceDurationMin)
+		}
+		if p.Price < 0 {
+			return errors.New("价格不能为负数")
+		}
+		prices = append(prices, &model.ClinicServicePrice{Size: p.Size, DurationMin: p.DurationMin, Price: p.Price})
+	}
+
+	item.Category = req.Category
+	item.Name = name
+	item.Description = strings.TrimSpace(req.Description)
+	if req.Status != nil {
+		item.Status = *req.Status
+	}
+	item.Prices = prices
+	return nil
+}
+
+// appointmentReviewSource 门诊预约评价来源:预约人可在服务完成后评价诊所
+type appointmentReviewSource struct {
+	appointmentRepo repository.AppointmentRepository
+	clinicRepo      repository.ClinicRepository
+}
+
+// NewAppointmentReviewSource 创建门诊预约评价来源
+func NewAppointmentReviewSource(appointmentRepo repository.AppointmentRepository, clinicRepo repository.ClinicRepository) ReviewSource {
+	return &appointmentReviewSource{appointmentRepo: appointmentRepo, clinicRepo: clinicRepo}
+}
+
+// ReviewTarget 校验预约属于该用户且已完成
+func (r *appointmentReviewSource) ReviewTarget(ctx context.Context, userID, appointmentID uint) (*ReviewTarget, error) {
+	appointment, err := reviewableAppointment(ctx, r.appointmentRepo, userID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReviewTarget{Type: model.ReviewTargetClinic, ID: appointment.ClinicID}, nil
+}
+
+// IsProvider 诊所人员可回复评价
+func (r *appointmentReviewSource) IsProvider(ctx context.Context, target *ReviewTarget, userID uint) (bool, error) {
+	if _, err := r.clinicRepo.GetStaff(ctx, target.ID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// appointmentVetReviewSource 门诊预约兽医评价来源:预约人可在服务完成后评价完成时记录的接诊兽医
+type appointmentVetReviewSource struct {
+	appointmentRepo repository.AppointmentRepository
+}
+
+// NewAppointmentVetReviewSource 创建门诊预约兽医评价来源
+func NewAppointmentVetReviewSource(appointmentRepo repository.AppointmentRepository) ReviewSource {
+	return &appointmentVetReviewSource{appointmentRepo: appointmentRepo}
+}
+
+// ReviewTarget 校验预约属于该用户、已完成且记录了接诊兽医
+func (r *appointmentVetReviewSource) ReviewTarget(ctx context.Context, userID, appointmentID uint) (*ReviewTarget, error) {
+	appointment, err := reviewableAppointment(ctx, r.appointmentRepo, userID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.VetID == 0 {
+		return nil, errors.New("该预约未记录接诊兽医")
+	}
+	return &ReviewTarget{Type: model.ReviewTargetVet, ID: appointment.VetID}, nil
+}
+
+// IsProvider 被评价的兽医本人可回复评价
+func (r *appointmentVetReviewSource) IsProvider(ctx context.Context, target *ReviewTarget, userID uint) (bool, error) {
+	return target.ID == userID, nil
+}
+
+// reviewableAppointment 获取该用户已完成、可评价的预约
+func reviewableAppointment(ctx context.Context, appointmentRepo repository.AppointmentRepository, userID, appointmentID uint) (*model.Appointment, error) {
+	appointment, err := appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return nil, checkNotFound(err, "预约不存在")
+	}
+	if appointment.UserID != userID {
+		return nil, forbidden("只能评价自己的门诊预约")
+	}
+	if !appointment.Reviewable() {
+		return nil, errors.New("服务完成后才能评价")
+	}
+	return appointment, nil
+}
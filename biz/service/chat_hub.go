@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/websocket"
+)
+
+const (
+	// chatSendBuffer 单个连接待发送事件的缓冲数,写满说明客户端过慢,直接断开
+	chatSendBuffer = 64
+	// chatPingPeriod 服务端ping间隔
+	chatPingPeriod = 30 * time.Second
+	// chatIdleTimeout 超过该时间未收到客户端任何帧视为断线
+	chatIdleTimeout = 75 * time.Second
+)
+
+// chatClient 单个WebSocket连接
+type chatClient struct {
+	userID uint
+	conn   *websocket.Conn
+	send   chan *model.ChatEvent
+	once   sync.Once
+	done   chan struct{}
+}
+
+// close 停止写循环,读循环会因连接关闭而退出
+func (c *chatClient) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// writeLoop 串行发送事件并定时ping
+func (c *chatClient) writeLoop(ctx context.Context) {
+	ticker := time.NewTicker(chatPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case event := <-c.send:
+			if err := c.conn.WriteJSON(event); err != nil {
+				logger.Debug(ctx, "推送聊天事件失败", logger.Int("user_id", int(c.userID)), logger.ErrorField(err))
+				c.close()
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// chatHub 当前实例上的在线连接,跨实例的事件经Redis频道分发后由各实例投递给本地连接
+type chatHub struct {
+	mu      sync.RWMutex
+	clients map[uint]map[*chatClient]struct{}
+}
+
+func newChatHub() *chatHub {
+	return &chatHub{clients: make(map[uint]map[*chatClient]struct{})}
+}
+
+func (h *chatHub) register(client *chatClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[client.userID] == nil {
+		h.clients[client.userID] = make(map[*chatClient]struct{})
+	}
+	h.clients[client.userID][client] = struct{}{}
+}
+
+func (h *chatHub) unregister(client *chatClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if set := h.clients[client.userID]; set != nil {
+		delete(set, client)
+		if len(set) == 0 {
+			delete(h.clients, client.userID)
+		}
+	}
+}
+
+// deliver 投递给本实例上这些用户的全部连接,缓冲已满的连接被断开
+func (h *chatHub) deliver(userIDs []uint, event *model.ChatEvent) {
+	h.mu.RLock()
+	var slow []*chatClient
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			select {
+			case client.send <- event:
+			default:
+				slow = append(slow, client)
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range slow {
+		client.close()
+	}
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/redis"
+	"pet-service/pkg/websocket"
+)
+
+const (
+	// chatChannel 聊天事件的Redis发布频道,所有实例订阅后投递给本地连接
+	chatChannel = "chat:events"
+	// maxChatContent 单条消息最大字数
+	maxChatContent = 2000
+	// chatPreviewSize 会话列表消息摘要长度(字节)
+	chatPreviewSize = 100
+)
+
+// chatEnvelope 频道消息,携带接收人列表
+type chatEnvelope struct {
+	UserIDs []uint           `json:"user_ids"`
+	Event   *model.ChatEvent `json:"event"`
+}
+
+// ChatService 主人与诊所会话服务接口
+type ChatService interface {
+	StartConversation(ctx context.Context, userID uint, req *model.StartConversationRequest) (*model.Conversation, error)
+	GetConversation(ctx context.Context, userID, id uint) (*model.Conversation, error)
+	ListMyConversations(ctx context.Context, userID uint, req *model.ListConversationRequest) ([]*model.Conversation, int64, error)
+	ListClinicConversations(ctx context.Context, userID, clinicID uint, req *model.ListConversationRequest) ([]*model.Conversation, int64, error)
+
+	ListMessages(ctx context.Context, userID, conversationID uint, req *model.ListChatMessageRequest) (*model.ChatMessagePage, error)
+	SendMessage(ctx context.Context, userID, conversationID uint, req *model.SendChatMessageRequest) (*model.ChatMessage, error)
+	MarkRead(ctx context.Context, userID, conversationID, messageID uint) error
+	GetUnread(ctx context.Context, userID uint) (*model.ChatUnread, error)
+
+	// Serve 处理WebSocket连接,阻塞到连接断开
+	Serve(ctx context.Context, userID uint, conn *websocket.Conn)
+	// RunSubscriber 订阅Redis频道并投递给本实例的连接,ctx取消后退出
+	RunSubscriber(ctx context.Context)
+}
+
+// chatService 会话服务实现
+type chatService struct {
+	chatRepo      repository.ChatRepository
+	clinicService ClinicService
+	petService    PetService
+	fileService   FileService
+	hub           *chatHub
+}
+
+// NewChatService 创建会话服务
+func NewChatService(chatRepo repository.ChatRepository, clinicService ClinicService, petService PetService, fileService FileService) ChatService {
+	return &chatService{
+		chatRepo:      chatRepo,
+		clinicService: clinicService,
+		petService:    petService,
+		fileService:   fileService,
+		hub:           newChatHub(),
+	}
+}
+
+// StartConversation 主人向诊所发起咨询,已有会话时沿用并发送消息
+func (s *chatService) StartConversation(ctx context.Context, userID uint, req *model.StartConversationRequest) (*model.Conversation, error) {
+	clinic, err := s.clinicService.GetClinic(ctx, req.ClinicID)
+	if err != nil {
+		return nil, err
+	}
+	if clinic.Status != 1 {
+		return nil, errors.New("诊所暂停营业")
+	}
+	if req.PetID != 0 {
+		if _, err := s.petService.Authorize(ctx, userID, req.PetID, model.PetRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	conversation, err := s.chatRepo.GetOrCreateConversation(ctx, clinic.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.PetID != 0 && req.PetID != conversation.PetID {
+		if err := s.chatRepo.UpdateConversationPet(ctx, conversation.ID, req.PetID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.send(ctx, userID, conversation, model.ChatSideOwner, &model.SendChatMessageRequest{Content: req.Content}); err != nil {
+		return nil, err
+	}
+	return s.chatRepo.GetConversation(ctx, conversation.ID)
+}
+
+// GetConversation 获取会话,仅主人和诊所人员可查看
+func (s *chatService) GetConversation(ctx context.Context, userID, id uint) (*model.Conversation, error) {
+	conversation, _, err := s.access(ctx, userID, id)
+	return conversation, err
+}
+
+// ListMyConversations 我作为主人的会话
+func (s *chatService) ListMyConversations(ctx context.Context, userID uint, req *model.ListConversationRequest) ([]*model.Conversation, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.chatRepo.ListOwnerConversations(ctx, userID, offset, limit)
+}
+
+// ListClinicConversations 诊所收到的会话,诊所人员共享
+func (s *chatService) ListClinicConversations(ctx context.Context, userID, clinicID uint, req *model.ListConversationRequest) ([]*model.Conversation, int64, error) {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.chatRepo.ListClinicConversations(ctx, clinicID, offset, limit)
+}
+
+// ListMessages 历史消息,按ID从新到旧翻页
+func (s *chatService) ListMessages(ctx context.Context, userID, conversationID uint, req *model.ListChatMessageRequest) (*model.ChatMessagePage, error) {
+	if _, _, err := s.access(ctx, userID, conversationID); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	messages, err := s.chatRepo.ListMessages(ctx, conversationID, req.BeforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.ChatMessagePage{List: messages}
+	for _, message := range messages {
+		s.fillImage(message)
+	}
+	if len(messages) == limit {
+		page.NextBeforeID = messages[len(messages)-1].ID
+	}
+	return page, nil
+}
+
+// SendMessage 发送消息并实时推送给会话双方
+func (s *chatService) SendMessage(ctx context.Context, userID, conversationID uint, req *model.SendChatMessageRequest) (*model.ChatMessage, error) {
+	conversation, side, err := s.access(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, userID, conversation, side, req)
+}
+
+func (s *chatService) send(ctx context.Context, userID uint, conversation *model.Conversation, side string, req *model.SendChatMessageRequest) (*model.ChatMessage, error) {
+	message := &model.ChatMessage{
+		ConversationID: conversation.ID,
+		SenderID:       userID,
+		SenderSide:     side,
+		Type:           req.Type,
+		Content:        strings.TrimSpace(req.Content),
+	}
+	if message.Type == "" {
+		message.Type = model.ChatMessageText
+	}
+	if len([]rune(message.Content)) > maxChatContent {
+		return nil, errors.New("消息内容不能超过2000字")
+	}
+
+	preview := message.Content
+	switch message.Type {
+	case model.ChatMessageText:
+		if message.Content == "" {
+			return nil, errors.New("消息内容不能为空")
+		}
+	case model.ChatMessageImage:
+		if req.FileID == 0 {
+			return nil, errors.New("请上传图片")
+		}
+		if _, err := s.fileService.GetOwnedImage(ctx, userID, req.FileID); err != nil {
+			return nil, err
+		}
+		message.FileID = req.FileID
+		preview = "[图片]"
+	default:
+		return nil, errors.New("消息类型无效")
+	}
+
+	if err := s.chatRepo.CreateMessage(ctx, message, truncate(preview, chatPreviewSize)); err != nil {
+		return nil, err
+	}
+	s.fillImage(message)
+
+	s.publish(ctx, conversation, &model.ChatEvent{
+		Type:           model.ChatEventMessage,
+		ConversationID: conversation.ID,
+		Message:        message,
+	})
+	return message, nil
+}
+
+// MarkRead 标记已读并向会话双方推送已读回执
+func (s *chatService) MarkRead(ctx context.Context, userID, conversationID, messageID uint) error {
+	conversation, side, err := s.access(ctx, userID, conversationID)
+	if err != nil {
+		return err
+	}
+	if messageID == 0 || messageID > conversation.LastMessageID {
+		messageID = conversation.LastMessageID
+	}
+	if messageID == 0 {
+		return nil
+	}
+
+	advanced, err := s.chatRepo.MarkRead(ctx, conversationID, side, messageID)
+	if err != nil {
+		return err
+	}
+	if advanced {
+		s.publish(ctx, conversation, &model.ChatEvent{
+			Type:           model.ChatEventRead,
+			ConversationID: conversationID,
+			ReaderSide:     side,
+			ReadID:         messageID,
+		})
+	}
+	return nil
+}
+
+// GetUnread 未读消息数,分别统计作为主人和作为诊所人员的未读
+func (s *chatService) GetUnread(ctx context.Context, userID uint) (*model.ChatUnread, error) {
+	owner, err := s.chatRepo.SumOwnerUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	clinic, err := s.chatRepo.SumClinicUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ChatUnread{Owner: owner, Clinic: clinic, Total: owner + clinic}, nil
+}
+
+// access 校验会话访问权限并返回当前用户所在的一方,主人同时是诊所人员时按主人处理
+func (s *chatService) access(ctx context.Context, userID, conversationID uint) (*model.Conversation, string, error) {
+	conversation, err := s.chatRepo.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, "", checkNotFound(err, "会话不存在")
+	}
+	if conversation.OwnerID == userID {
+		return conversation, model.ChatSideOwner, nil
+	}
+	if _, err := s.clinicService.Authorize(ctx, userID, conversation.ClinicID); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return nil, "", forbidden("无权限查看该会话")
+		}
+		return nil, "", err
+	}
+	return conversation, model.ChatSideClinic, nil
+}
+
+// fillImage 填充图片消息的访问地址
+func (s *chatService) fillImage(message *model.ChatMessage) {
+	if message.Type == model.ChatMessageImage && message.FileID != 0 {
+		message.ImageURL = s.fileService.SignedURL(message.FileID, model.FileVariantOriginal)
+	}
+}
+
+// publish 将事件发给会话的主人和诊所全部人员,Redis不可用时只投递给本实例的连接
+func (s *chatService) publish(ctx context.Context, conversation *model.Conversation, event *model.ChatEvent) {
+	staffIDs, err := s.clinicService.StaffUserIDs(ctx, conversation.ClinicID)
+	if err != nil {
+		logger.Warn(ctx, "获取诊所人员失败,仅推送给主人", logger.Int("clinic_id", int(conversation.ClinicID)), logger.ErrorField(err))
+	}
+	userIDs := uniqueIDs(append([]uint{conversation.OwnerID}, staffIDs...))
+
+	payload, err := json.Marshal(&chatEnvelope{UserIDs: userIDs, Event: event})
+	if err != nil {
+		logger.Error(ctx, "序列化聊天事件失败", logger.ErrorField(err))
+		return
+	}
+	if err := redis.Publish(ctx, chatChannel, payload); err != nil {
+		s.hub.deliver(userIDs, event)
+	}
+}
+
+// Serve 注册连接并处理客户端指令,客户端可通过WebSocket发送消息、标记已读和心跳
+func (s *chatService) Serve(ctx context.Context, userID uint, conn *websocket.Conn) {
+	conn.SetReadLimit(16 << 10)
+	_ = conn.SetIdleTimeout(chatIdleTimeout)
+	client := &chatClient{
+		userID: userID,
+		conn:   conn,
+		send:   make(chan *model.ChatEvent, chatSendBuffer),
+		done:   make(chan struct{}),
+	}
+	s.hub.register(client)
+	defer func() {
+		s.hub.unregister(client)
+		client.close()
+	}()
+	go client.writeLoop(ctx)
+
+	logger.Info(ctx, "聊天连接已建立", logger.Int("user_id", int(userID)), logger.String("remote", conn.RemoteAddr().String()))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			logger.Info(ctx, "聊天连接已断开", logger.Int("user_id", int(userID)), logger.String("reason", err.Error()))
+			return
+		}
+
+		var cmd model.ChatClientEvent
+		if err := json.Unmarshal(data, &cmd); err != nil {
+			s.reply(client, &model.ChatEvent{Type: model.ChatEventError, Error: "指令格式错误"})
+			continue
+		}
+		s.handleCommand(ctx, client, &cmd)
+	}
+}
+
+// handleCommand 处理单条客户端指令,结果只回复给当前连接
+func (s *chatService) handleCommand(ctx context.Context, client *chatClient, cmd *model.ChatClientEvent) {
+	var err error
+	switch cmd.Type {
+	case "send":
+		var message *model.ChatMessage
+		message, err = s.SendMessage(ctx, client.userID, cmd.ConversationID, &model.SendChatMessageRequest{
+			Type:    cmd.MessageType,
+			Content: cmd.Content,
+			FileID:  cmd.FileID,
+		})
+		if err == nil {
+			s.reply(client, &model.ChatEvent{
+				Type:           model.ChatEventAck,
+				ConversationID: cmd.ConversationID,
+				Message:        message,
+				RequestID:      cmd.RequestID,
+			})
+		}
+	case "read":
+		err = s.MarkRead(ctx, client.userID, cmd.ConversationID, cmd.MessageID)
+		if err == nil {
+			s.reply(client, &model.ChatEvent{Type: model.ChatEventAck, ConversationID: cmd.ConversationID, RequestID: cmd.RequestID})
+		}
+	case "ping":
+		s.reply(client, &model.ChatEvent{Type: model.ChatEventPong, RequestID: cmd.RequestID})
+	default:
+		err = errors.New("不支持的指令类型")
+	}
+
+	if err != nil {
+		s.reply(client, &model.ChatEvent{
+			Type:           model.ChatEventError,
+			ConversationID: cmd.ConversationID,
+			RequestID:      cmd.RequestID,
+			Error:          err.Error(),
+		})
+	}
+}
+
+// reply 回复当前连接,缓冲已满时丢弃
+func (s *chatService) reply(client *chatClient, event *model.ChatEvent) {
+	select {
+	case client.send <- event:
+	default:
+	}
+}
+
+// RunSubscriber 订阅聊天频道,将其他实例发布的事件投递给本实例的连接
+func (s *chatService) RunSubscriber(ctx context.Context) {
+	sub := redis.Subscribe(ctx, chatChannel)
+	defer sub.Close()
+
+	logger.Info(ctx, "聊天事件订阅已启动")
+	events := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "聊天事件订阅已停止")
+			return
+		case msg, ok := <-events:
+			if !ok {
+				return
+			}
+			var envelope chatEnvelope
+			if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil || envelope.Event == nil {
+				logger.Warn(ctx, "聊天事件格式错误", logger.String("payload", truncate(msg.Payload, 200)))
+				continue
+			}
+			s.hub.deliver(envelope.UserIDs, envelope.Event)
+		}
+	}
+}
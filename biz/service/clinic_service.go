@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/geo"
+)
+
+// ClinicService 诊所服务接口
+type ClinicService interface {
+	CreateClinic(ctx context.Context, req *model.SaveClinicRequest) (*model.Clinic, error)
+	UpdateClinic(ctx context.Context, userID, clinicID uint, req *model.SaveClinicRequest) (*model.Clinic, error)
+	GetClinic(ctx context.Context, id uint) (*model.Clinic, error)
+	ListClinics(ctx context.Context, req *model.ListClinicRequest) ([]*model.Clinic, int64, error)
+
+	ListStaff(ctx context.Context, userID, clinicID uint) ([]*model.ClinicStaff, error)
+	SaveStaff(ctx context.Context, userID, clinicID uint, req *model.SaveClinicStaffRequest) (*model.ClinicStaff, error)
+	RemoveStaff(ctx context.Context, userID, clinicID, staffUserID uint) error
+	ListMyClinics(ctx context.Context, userID uint) ([]*model.ClinicStaff, error)
+
+	// Authorize 校验用户是否为诊所人员,指定roles时还需具备其中之一
+	Authorize(ctx context.Context, userID, clinicID uint, roles ...string) (*model.ClinicStaff, error)
+	StaffUserIDs(ctx context.Context, clinicID uint) ([]uint, error)
+}
+
+// clinicService 诊所服务实现
+type clinicService struct {
+	clinicRepo repository.ClinicRepository
+	userRepo   repository.UserRepository
+}
+
+// NewClinicService 创建诊所服务
+func NewClinicService(clinicRepo repository.ClinicRepository, userRepo repository.UserRepository) ClinicService {
+	return &clinicService{
+		clinicRepo: clinicRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// CreateClinic 创建诊所,由平台管理员操作并指定诊所管理员
+func (s *clinicService) CreateClinic(ctx context.Context, req *model.SaveClinicRequest) (*model.Clinic, error) {
+	if req.ManagerUserID == 0 {
+		return nil, errors.New("请指定诊所管理员")
+	}
+	if _, err := s.userRepo.GetByID(ctx, req.ManagerUserID); err != nil {
+		return nil, checkNotFound(err, "诊所管理员用户不存在")
+	}
+
+	clinic := &model.Clinic{Status: 1}
+	if err := applyClinic(clinic, req); err != nil {
+		return nil, err
+	}
+	if err := s.clinicRepo.Create(ctx, clinic, req.ManagerUserID); err != nil {
+		return nil, err
+	}
+	return clinic, nil
+}
+
+// UpdateClinic 诊所管理员更新诊所信息
+func (s *clinicService) UpdateClinic(ctx context.Context, userID, clinicID uint, req *model.SaveClinicRequest) (*model.Clinic, error) {
+	if _, err := s.Authorize(ctx, userID, clinicID, model.ClinicRoleManager); err != nil {
+		return nil, err
+	}
+	clinic, err := s.GetClinic(ctx, clinicID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyClinic(clinic, req); err != nil {
+		return nil, err
+	}
+	if err := s.clinicRepo.Update(ctx, clinic); err != nil {
+		return nil, err
+	}
+	return s.GetClinic(ctx, clinicID)
+}
+
+// applyClinic 校验请求并写入诊所字段
+func applyClinic(clinic *model.Clinic, req *model.SaveClinicRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("诊所名称不能为空")
+	}
+	if !geo.ValidCoordinate(req.Latitude, req.Longitude) {
+		return errors.New("经纬度不合法")
+	}
+	if req.Status != nil {
+		if *req.Status != 0 && *req.Status != 1 {
+			return errors.New("诊所状态无效")
+		}
+		clinic.Status = *req.Status
+	}
+	clinic.Name = name
+	clinic.Phone = req.Phone
+	clinic.City = strings.TrimSpace(req.City)
+	clinic.Address = req.Address
+	clinic.Latitude = req.Latitude
+	clinic.Longitude = req.Longitude
+	clinic.Description = req.Description
+	clinic.OpeningHours = req.OpeningHours
+	return nil
+}
+
+// GetClinic 获取诊所
+func (s *clinicService) GetClinic(ctx context.Context, id uint) (*model.Clinic, error) {
+	clinic, err := s.clinicRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "诊所不存在")
+	}
+	return clinic, nil
+}
+
+// ListClinics 检索营业中的诊所
+func (s *clinicService) ListClinics(ctx context.Context, req *model.ListClinicRequest) ([]*model.Clinic, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.clinicRepo.List(ctx, req.City, req.Keyword, true, offset, limit)
+}
+
+// ListStaff 诊所人员列表,仅诊所人员可查看
+func (s *clinicService) ListStaff(ctx context.Context, userID, clinicID uint) ([]*model.ClinicStaff, error) {
+	if _, err := s.Authorize(ctx, userID, clinicID); err != nil {
+		return nil, err
+	}
+	return s.clinicRepo.ListStaff(ctx, clinicID)
+}
+
+// SaveStaff 诊所管理员添加人员或修改人员角色,诊所至少保留一名管理员
+func (s *clinicService) SaveStaff(ctx context.Context, userID, clinicID uint, req *model.SaveClinicStaffRequest) (*model.ClinicStaff, error) {
+	if _, err := s.Authorize(ctx, userID, clinicID, model.ClinicRoleManager); err != nil {
+		return nil, err
+	}
+	if !model.ClinicRoles[req.Role] {
+		return nil, errors.New("人员角色无效")
+	}
+	if _, err := s.userRepo.GetByID(ctx, req.UserID); err != nil {
+		return nil, checkNotFound(err, "用户不存在")
+	}
+	if req.Role != model.ClinicRoleManager {
+		if err := s.checkLastManager(ctx, clinicID, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	staff := &model.ClinicStaff{
+		ClinicID: clinicID,
+		UserID:   req.UserID,
+		Role:     req.Role,
+		Title:    req.Title,
+	}
+	if err := s.clinicRepo.SaveStaff(ctx, staff); err != nil {
+		return nil, err
+	}
+	return s.clinicRepo.GetStaff(ctx, clinicID, req.UserID)
+}
+
+// RemoveStaff 诊所管理员移除人员
+func (s *clinicService) RemoveStaff(ctx context.Context, userID, clinicID, staffUserID uint) error {
+	if _, err := s.Authorize(ctx, userID, clinicID, model.ClinicRoleManager); err != nil {
+		return err
+	}
+	if err := s.checkLastManager(ctx, clinicID, staffUserID); err != nil {
+		return err
+	}
+	removed, err := s.clinicRepo.RemoveStaff(ctx, clinicID, staffUserID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return notFound("该用户不是诊所人员")
+	}
+	return nil
+}
+
+// checkLastManager 目标用户是唯一管理员时不能降级或移除
+func (s *clinicService) checkLastManager(ctx context.Context, clinicID, staffUserID uint) error {
+	staff, err := s.clinicRepo.GetStaff(ctx, clinicID, staffUserID)
+	if err != nil || staff.Role != model.ClinicRoleManager {
+		return nil
+	}
+	count, err := s.clinicRepo.CountManagers(ctx, clinicID)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return errors.New("诊所至少需要保留一名管理员")
+	}
+	return nil
+}
+
+// ListMyClinics 我任职的诊所
+func (s *clinicService) ListMyClinics(ctx context.Context, userID uint) ([]*model.ClinicStaff, error) {
+	return s.clinicRepo.ListUserClinics(ctx, userID)
+}
+
+// Authorize 校验用户是否为诊所人员
+func (s *clinicService) Authorize(ctx context.Context, userID, clinicID uint, roles ...string) (*model.ClinicStaff, error) {
+	staff, err := s.clinicRepo.GetStaff(ctx, clinicID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, forbidden("无权限操作该诊所")
+		}
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return staff, nil
+	}
+	for _, role := range roles {
+		if staff.Role == role {
+			return staff, nil
+		}
+	}
+	return nil, forbidden("无权限操作该诊所")
+}
+
+// StaffUserIDs 诊所全部人员的用户ID
+func (s *clinicService) StaffUserIDs(ctx context.Context, clinicID uint) ([]uint, error) {
+	return s.clinicRepo.ListStaffUserIDs(ctx, clinicID)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+)
+
+// couponOrderTypes 优惠券可适用的订单类型
+var couponOrderTypes = map[string]bool{
+	model.OrderTypeShop:          true,
+	model.OrderTypeSitterBooking: true,
+}
+
+// CouponService 优惠券服务接口
+type CouponService interface {
+	CreateCoupon(ctx context.Context, operatorID uint, req *model.SaveCouponRequest) (*model.Coupon, error)
+	UpdateCoupon(ctx context.Context, id uint, req *model.SaveCouponRequest) (*model.Coupon, error)
+	GetCoupon(ctx context.Context, id uint) (*model.Coupon, error)
+	ListCoupons(ctx context.Context, req *model.ListCouponRequest) ([]*model.Coupon, int64, error)
+
+	CheckCoupon(ctx context.Context, userID uint, req *model.CheckCouponRequest) (*model.CouponQuote, error)
+	Quote(ctx context.Context, userID uint, code, orderType string, amount int64) (*model.Coupon, int64, error)
+}
+
+// couponService 优惠券服务实现
+type couponService struct {
+	couponRepo repository.CouponRepository
+}
+
+// NewCouponService 创建优惠券服务
+func NewCouponService(couponRepo repository.CouponRepository) CouponService {
+	return &couponService{couponRepo: couponRepo}
+}
+
+// CreateCoupon 创建优惠券
+func (s *couponService) CreateCoupon(ctx context.Context, operatorID uint, req *model.SaveCouponRequest) (*model.Coupon, error) {
+	coupon := &model.Coupon{PerUserLimit: 1, Status: model.CouponStatusActive, CreatedBy: operatorID}
+	if err := s.applyCoupon(ctx, coupon, req); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Create(ctx, coupon); err != nil {
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// UpdateCoupon 更新优惠券,已使用次数保持不变
+func (s *couponService) UpdateCoupon(ctx context.Context, id uint, req *model.SaveCouponRequest) (*model.Coupon, error) {
+	coupon, err := s.GetCoupon(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyCoupon(ctx, coupon, req); err != nil {
+		return nil, err
+	}
+	if err := s.couponRepo.Update(ctx, coupon); err != nil {
+		return nil, err
+	}
+	return s.GetCoupon(ctx, id)
+}
+
+// applyCoupon 校验请求并写入优惠券字段
+func (s *couponService) applyCoupon(ctx context.Context, coupon *model.Coupon, req *model.SaveCouponRequest) error {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if code == "" || strings.TrimSpace(req.Name) == "" {
+		return errors.New("券码和名称不能为空")
+	}
+	switch req.Type {
+	case model.CouponTypePercent:
+		if req.Value < 1 || req.Value > 99 {
+			return errors.New("折扣百分比需在1-99之间")
+		}
+	case model.CouponTypeFixed:
+		if req.Value < 1 {
+			return errors.New("立减金额必须大于0")
+		}
+	default:
+		return errors.New("优惠券类型无效")
+	}
+	if req.MaxDiscount < 0 || req.MinSpend < 0 || req.TotalLimit < 0 {
+		return errors.New("金额和次数不能为负数")
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return errors.New("失效时间必须晚于生效时间")
+	}
+	for _, t := range req.OrderTypes {
+		if !couponOrderTypes[t] {
+			return fmt.Errorf("订单类型不支持: %s", t)
+		}
+	}
+	if req.PerUserLimit != nil {
+		if *req.PerUserLimit < 0 {
+			return errors.New("每人使用次数不能为负数")
+		}
+		coupon.PerUserLimit = *req.PerUserLimit
+	}
+	if req.Status != "" {
+		if req.Status != model.CouponStatusActive && req.Status != model.CouponStatusDisabled {
+			return errors.New("优惠券状态无效")
+		}
+		coupon.Status = req.Status
+	}
+
+	exists, err := s.couponRepo.CodeExists(ctx, code, coupon.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("券码已存在")
+	}
+
+	coupon.Code = code
+	coupon.Name = strings.TrimSpace(req.Name)
+	coupon.Type = req.Type
+	coupon.Value = req.Value
+	coupon.MaxDiscount = req.MaxDiscount
+	coupon.MinSpend = req.MinSpend
+	coupon.OrderTypes = req.OrderTypes
+	coupon.StartsAt = req.StartsAt
+	coupon.EndsAt = req.EndsAt
+	coupon.TotalLimit = req.TotalLimit
+	return nil
+}
+
+// GetCoupon 获取优惠券
+func (s *couponService) GetCoupon(ctx context.Context, id uint) (*model.Coupon, error) {
+	coupon, err := s.couponRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "优惠券不存在")
+	}
+	return coupon, nil
+}
+
+// ListCoupons 优惠券列表
+func (s *couponService) ListCoupons(ctx context.Context, req *model.ListCouponRequest) ([]*model.Coupon, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.couponRepo.List(ctx, req.Status, strings.TrimSpace(req.Keyword), offset, limit)
+}
+
+// CheckCoupon 下单前试算优惠券,不可用时返回原因
+func (s *couponService) CheckCoupon(ctx context.Context, userID uint, req *model.CheckCouponRequest) (*model.CouponQuote, error) {
+	coupon, discount, err := s.Quote(ctx, userID, req.Code, req.OrderType, req.Amount)
+	if err != nil {
+		if coupon == nil {
+			return nil, err
+		}
+		return &model.CouponQuote{Coupon: coupon, Reason: err.Error()}, nil
+	}
+	return &model.CouponQuote{Coupon: coupon, Discount: discount, Usable: true}, nil
+}
+
+// Quote 校验券码对该订单是否可用并计算优惠金额
+// 券码不存在时coupon为nil,其余不可用情况会同时返回优惠券和原因
+// 次数上限在下单事务中会加锁再次校验
+func (s *couponService) Quote(ctx context.Context, userID uint, code, orderType string, amount int64) (*model.Coupon, int64, error) {
+	coupon, err := s.couponRepo.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, 0, checkNotFound(err, "优惠券不存在")
+	}
+	now := time.Now()
+	if coupon.Status != model.CouponStatusActive || now.Before(coupon.StartsAt) || !now.Before(coupon.EndsAt) {
+		return coupon, 0, errors.New("优惠券不在有效期内")
+	}
+	if !coupon.AppliesTo(orderType) {
+		return coupon, 0, errors.New("优惠券不适用于该订单")
+	}
+	if amount < coupon.MinSpend {
+		return coupon, 0, fmt.Errorf("订单满%.2f元才能使用该优惠券", float64(coupon.MinSpend)/100)
+	}
+	if coupon.TotalLimit > 0 && coupon.UsedCount >= coupon.TotalLimit {
+		return coupon, 0, errors.New("优惠券已被领完")
+	}
+	if coupon.PerUserLimit > 0 {
+		used, err := s.couponRepo.CountUserRedemptions(ctx, coupon.ID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return coupon, 0, errors.New("已达到该优惠券的使用次数上限")
+		}
+	}
+	discount := coupon.Discount(amount)
+	logger.Debug(ctx, "优惠券试算", logger.String("code", coupon.Code), logger.Int64("amount", amount), logger.Int64("discount", discount))
+	return coupon, discount, nil
+}
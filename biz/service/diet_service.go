@@ -0,0 +1,492 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+)
+
+const (
+	// rerCoefficient RER = 70 × 体重(kg)^0.75
+	rerCoefficient = 70
+	// intakeTolerance 摄入热量在目标±10%以内视为达标
+	intakeTolerance = 0.1
+	// maxFeedingLogDays 喂食记录列表单次查询的天数上限
+	maxFeedingLogDays = 366
+)
+
+// DietService 饮食计划服务接口
+type DietService interface {
+	CreatePlan(ctx context.Context, userID, petID uint, req *model.SaveDietPlanRequest) (*model.DietPlan, error)
+	GetPlan(ctx context.Context, userID, id uint) (*model.DietPlan, error)
+	UpdatePlan(ctx context.Context, userID, id uint, req *model.SaveDietPlanRequest) (*model.DietPlan, error)
+	DeletePlan(ctx context.Context, userID, id uint) error
+	ListPlans(ctx context.Context, userID, petID uint, req *model.ListDietPlanRequest) ([]*model.DietPlan, int64, error)
+
+	CreateLog(ctx context.Context, userID, petID uint, req *model.CreateFeedingLogRequest) (*model.FeedingLog, error)
+	DeleteLog(ctx context.Context, userID, id uint) error
+	ListLogs(ctx context.Context, userID, petID uint, req *model.ListFeedingLogRequest) ([]*model.FeedingLog, int64, error)
+	// DailySummary 某天的摄入热量与目标对比
+	DailySummary(ctx context.Context, userID, petID uint, req *model.DietSummaryRequest) (*model.DietDailySummary, error)
+}
+
+// dietService 饮食计划服务实现
+type dietService struct {
+	dietRepo        repository.DietRepository
+	measurementRepo repository.MeasurementRepository
+	petService      PetService
+	shopService     ShopService
+}
+
+// NewDietService 创建饮食计划服务
+func NewDietService(dietRepo repository.DietRepository, measurementRepo repository.MeasurementRepository, petService PetService, shopService ShopService) DietService {
+	return &dietService{
+		dietRepo:        dietRepo,
+		measurementRepo: measurementRepo,
+		petService:      petService,
+		shopService:     shopService,
+	}
+}
+
+// CreatePlan 创建饮食计划,进行中的上一个计划自动结束于新计划开始的前一天
+func (s *dietService) CreatePlan(ctx context.Context, userID, petID uint, req *model.SaveDietPlanRequest) (*model.DietPlan, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &model.DietPlan{PetID: petID, CreatedBy: userID, StartDate: today()}
+	if req.StartDate != "" {
+		if plan.StartDate, err = parseDate(req.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.apply(ctx, pet, plan, req); err != nil {
+		return nil, err
+	}
+	if err := s.dietRepo.CreatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	if err := s.fillTarget(ctx, pet, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// GetPlan 获取饮食计划及每日能量需求
+func (s *dietService) GetPlan(ctx context.Context, userID, id uint) (*model.DietPlan, error) {
+	plan, pet, err := s.authorizePlan(ctx, userID, id, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillTarget(ctx, pet, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// UpdatePlan 修改饮食计划,开始日期不可修改
+func (s *dietService) UpdatePlan(ctx context.Context, userID, id uint, req *model.SaveDietPlanRequest) (*model.DietPlan, error) {
+	plan, pet, err := s.authorizePlan(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, pet, plan, req); err != nil {
+		return nil, err
+	}
+	if err := s.dietRepo.UpdatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	if err := s.fillTarget(ctx, pet, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// DeletePlan 删除饮食计划,已有的喂食记录保留
+func (s *dietService) DeletePlan(ctx context.Context, userID, id uint) error {
+	if _, _, err := s.authorizePlan(ctx, userID, id, model.PetRoleEditor); err != nil {
+		return err
+	}
+	return s.dietRepo.DeletePlan(ctx, id)
+}
+
+// ListPlans 宠物的饮食计划,按开始日期倒序
+func (s *dietService) ListPlans(ctx context.Context, userID, petID uint, req *model.ListDietPlanRequest) ([]*model.DietPlan, int64, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	plans, total, err := s.dietRepo.ListPlans(ctx, petID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	weight, err := s.latestWeight(ctx, petID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, plan := range plans {
+		plan.PlannedKcal = plannedKcal(plan)
+		plan.Target = dietTarget(pet, plan, weight)
+	}
+	return plans, total, nil
+}
+
+// CreateLog 记录一次喂食,自动关联当天生效的饮食计划
+func (s *dietService) CreateLog(ctx context.Context, userID, petID uint, req *model.CreateFeedingLogRequest) (*model.FeedingLog, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	fedAt := now
+	if req.FedAt != nil {
+		fedAt = *req.FedAt
+	}
+	if fedAt.After(now.Add(5 * time.Minute)) {
+		return nil, errors.New("喂食时间不能晚于当前时间")
+	}
+	if req.Grams <= 0 || req.Grams > 10000 {
+		return nil, errors.New("份量需在0-10000克之间")
+	}
+	if req.Kcal < 0 || req.KcalPerKg < 0 {
+		return nil, errors.New("热量不能为负数")
+	}
+
+	log := &model.FeedingLog{
+		PetID:    petID,
+		FedBy:    userID,
+		FedAt:    fedAt,
+		FoodName: truncate(strings.TrimSpace(req.FoodName), 100),
+		Grams:    math.Round(req.Grams*10) / 10,
+		Notes:    truncate(strings.TrimSpace(req.Notes), 255),
+	}
+	local := fedAt.In(time.Local)
+	plan, err := s.dietRepo.GetPlanForDate(ctx, petID, time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if plan != nil {
+		log.PlanID = &plan.ID
+		if log.FoodName == "" {
+			log.FoodName = plan.FoodName
+		}
+	}
+
+	switch {
+	case req.Kcal > 0:
+		log.Kcal = req.Kcal
+	case req.KcalPerKg > 0:
+		log.Kcal = int(math.Round(log.Grams * float64(req.KcalPerKg) / 1000))
+	case plan != nil:
+		log.Kcal = int(math.Round(log.Grams * float64(plan.KcalPerKg) / 1000))
+	default:
+		return nil, errors.New("当天没有饮食计划,请填写热量或食物代谢能")
+	}
+	if log.FoodName == "" {
+		return nil, errors.New("请填写食物名称")
+	}
+
+	if err := s.dietRepo.CreateLog(ctx, log); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// DeleteLog 删除喂食记录
+func (s *dietService) DeleteLog(ctx context.Context, userID, id uint) error {
+	log, err := s.dietRepo.GetLog(ctx, id)
+	if err != nil {
+		return checkNotFound(err, "喂食记录不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, log.PetID, model.PetRoleEditor); err != nil {
+		return err
+	}
+	return s.dietRepo.DeleteLog(ctx, id)
+}
+
+// ListLogs 喂食记录列表,按喂食时间倒序
+func (s *dietService) ListLogs(ctx context.Context, userID, petID uint, req *model.ListFeedingLogRequest) ([]*model.FeedingLog, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+
+	var from, to *time.Time
+	if req.From != "" {
+		d, err := parseDate(req.From)
+		if err != nil {
+			return nil, 0, err
+		}
+		from = &d
+	}
+	if req.To != "" {
+		d, err := parseDate(req.To)
+		if err != nil {
+			return nil, 0, err
+		}
+		// 结束日期包含当天
+		d = d.AddDate(0, 0, 1)
+		to = &d
+	}
+	if from != nil && to != nil {
+		if !from.Before(*to) {
+			return nil, 0, errors.New("开始日期不能晚于结束日期")
+		}
+		if daysBetween(*from, *to) > maxFeedingLogDays {
+			return nil, 0, fmt.Errorf("查询区间不能超过%d天", maxFeedingLogDays)
+		}
+	}
+
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.dietRepo.ListLogs(ctx, petID, from, to, offset, limit)
+}
+
+// DailySummary 汇总某天的喂食记录并与当天饮食计划的目标热量对比
+func (s *dietService) DailySummary(ctx context.Context, userID, petID uint, req *model.DietSummaryRequest) (*model.DietDailySummary, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	date := today()
+	if req.Date != "" {
+		if date, err = parseDate(req.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	logs, err := s.dietRepo.ListLogsRange(ctx, petID, date, date.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	summary := &model.DietDailySummary{
+		Date:     date.Format(model.DateLayout),
+		MealsFed: len(logs),
+		Logs:     logs,
+	}
+	var grams float64
+	for _, log := range logs {
+		summary.IntakeKcal += log.Kcal
+		grams += log.Grams
+	}
+	summary.IntakeGrams = math.Round(grams*10) / 10
+
+	plan, err := s.dietRepo.GetPlanForDate(ctx, petID, date)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return summary, nil
+		}
+		return nil, err
+	}
+	if err := s.fillTarget(ctx, pet, plan); err != nil {
+		return nil, err
+	}
+	summary.Plan = plan
+	summary.Target = plan.Target
+	summary.MealsPlanned = plan.MealsPerDay
+
+	// 没有目标热量时与计划份量对比
+	target := plan.PlannedKcal
+	if plan.Target != nil {
+		target = plan.Target.TargetKcal
+	}
+	if target > 0 {
+		summary.RemainingKcal = target - summary.IntakeKcal
+		summary.Percent = round2(float64(summary.IntakeKcal) / float64(target) * 100)
+		switch ratio := float64(summary.IntakeKcal) / float64(target); {
+		case ratio < 1-intakeTolerance:
+			summary.Status = model.IntakeUnder
+		case ratio > 1+intakeTolerance:
+			summary.Status = model.IntakeOver
+		default:
+			summary.Status = model.IntakeOnTrack
+		}
+	}
+	return summary, nil
+}
+
+// authorizePlan 获取饮食计划并校验当前用户对所属宠物的角色
+func (s *dietService) authorizePlan(ctx context.Context, userID, id uint, required string) (*model.DietPlan, *model.Pet, error) {
+	plan, err := s.dietRepo.GetPlan(ctx, id)
+	if err != nil {
+		return nil, nil, checkNotFound(err, "饮食计划不存在")
+	}
+	pet, err := s.petService.Authorize(ctx, userID, plan.PetID, required)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plan, pet, nil
+}
+
+// apply 校验请求并写入饮食计划
+func (s *dietService) apply(ctx context.Context, pet *model.Pet, plan *model.DietPlan, req *model.SaveDietPlanRequest) error {
+	if req.KcalPerKg <= 0 || req.KcalPerKg > 10000 {
+		return errors.New("食物代谢能需在1-10000千卡/千克之间")
+	}
+	if req.PortionGrams <= 0 || req.PortionGrams > 5000 {
+		return errors.New("每餐份量需在0-5000克之间")
+	}
+	if req.MealsPerDay < 1 || req.MealsPerDay > 10 {
+		return errors.New("每日餐数需在1-10之间")
+	}
+	if req.TargetKcal < 0 {
+		return errors.New("目标热量不能为负数")
+	}
+	if _, ok := merFactor(pet, req.ActivityLevel, req.Neutered); !ok && req.TargetKcal == 0 {
+		if _, known := model.MERFactors[pet.Species]; known {
+			return fmt.Errorf("活动水平不支持: %s", req.ActivityLevel)
+		}
+		return errors.New("该物种无法按公式计算能量需求,请填写目标热量")
+	}
+	mealTimes, err := normalizeMealTimes(req.MealTimes, req.MealsPerDay)
+	if err != nil {
+		return err
+	}
+
+	foodName := strings.TrimSpace(req.FoodName)
+	if req.ProductID != nil && *req.ProductID > 0 {
+		product, err := s.shopService.GetProduct(ctx, *req.ProductID, false)
+		if err != nil {
+			return err
+		}
+		if foodName == "" {
+			foodName = product.Name
+		}
+		plan.ProductID = &product.ID
+	} else {
+		plan.ProductID = nil
+	}
+	if foodName == "" {
+		return errors.New("请填写食物名称或选择商品")
+	}
+
+	plan.FoodName = truncate(foodName, 100)
+	plan.KcalPerKg = req.KcalPerKg
+	plan.PortionGrams = math.Round(req.PortionGrams*10) / 10
+	plan.MealsPerDay = req.MealsPerDay
+	plan.MealTimes = mealTimes
+	plan.ActivityLevel = req.ActivityLevel
+	plan.Neutered = req.Neutered
+	plan.TargetKcal = req.TargetKcal
+	plan.Notes = truncate(strings.TrimSpace(req.Notes), 500)
+	return nil
+}
+
+// fillTarget 计算计划份量热量和每日能量需求
+func (s *dietService) fillTarget(ctx context.Context, pet *model.Pet, plan *model.DietPlan) error {
+	weight, err := s.latestWeight(ctx, pet.ID)
+	if err != nil {
+		return err
+	}
+	plan.PlannedKcal = plannedKcal(plan)
+	plan.Target = dietTarget(pet, plan, weight)
+	return nil
+}
+
+// latestWeight 最近一次体重(kg),没有体重记录时返回0
+func (s *dietService) latestWeight(ctx context.Context, petID uint) (float64, error) {
+	weight, err := s.measurementRepo.Latest(ctx, petID, model.MetricWeight)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return weight.Value, nil
+}
+
+// merFactor 根据物种、年龄、活动水平和是否绝育确定MER系数
+// 幼犬4月龄以下为3.0、12月龄以下为2.0,幼猫12月龄以下为2.5,生长期不区分活动水平
+func merFactor(pet *model.Pet, level string, neutered bool) (float64, bool) {
+	factors, ok := model.MERFactors[pet.Species]
+	if !ok {
+		return 0, false
+	}
+	if _, ok := factors[level]; !ok {
+		return 0, false
+	}
+	if months := ageMonths(pet, time.Now()); months >= 0 && months < 12 {
+		if pet.Species == "cat" {
+			return 2.5, true
+		}
+		if months < 4 {
+			return 3.0, true
+		}
+		return 2.0, true
+	}
+	if level == model.DietLevelNormal && neutered {
+		return model.NeuteredMERFactors[pet.Species], true
+	}
+	return factors[level], true
+}
+
+// dietTarget 计算每日能量需求,兽医指定的目标热量优先;缺少体重且未指定目标时返回nil
+func dietTarget(pet *model.Pet, plan *model.DietPlan, weightKg float64) *model.DietTarget {
+	target := &model.DietTarget{WeightKg: weightKg, LifeStage: "adult"}
+	if months := ageMonths(pet, time.Now()); months >= 0 && months < 12 {
+		target.LifeStage = "young"
+	}
+	if weightKg > 0 {
+		target.RER = int(math.Round(rerCoefficient * math.Pow(weightKg, 0.75)))
+		if factor, ok := merFactor(pet, plan.ActivityLevel, plan.Neutered); ok {
+			target.Factor = factor
+			target.MER = int(math.Round(float64(target.RER) * factor))
+			target.TargetKcal = target.MER
+		}
+	}
+	if plan.TargetKcal > 0 {
+		target.TargetKcal = plan.TargetKcal
+		target.Custom = true
+	}
+	if target.TargetKcal == 0 {
+		return nil
+	}
+	return target
+}
+
+// plannedKcal 按计划份量每日摄入的热量
+func plannedKcal(plan *model.DietPlan) int {
+	return int(math.Round(plan.PortionGrams * float64(plan.MealsPerDay) * float64(plan.KcalPerKg) / 1000))
+}
+
+// ageMonths 计算月龄,未填写出生日期时返回-1
+func ageMonths(pet *model.Pet, now time.Time) int {
+	if pet.BirthDate == nil {
+		return -1
+	}
+	b := *pet.BirthDate
+	months := (now.Year()-b.Year())*12 + int(now.Month()) - int(b.Month())
+	if now.Day() < b.Day() {
+		months--
+	}
+	return months
+}
+
+// normalizeMealTimes 校验HH:MM格式的喂食时间并排序,填写时数量须与每日餐数一致
+func normalizeMealTimes(times []string, mealsPerDay int) ([]string, error) {
+	if len(times) == 0 {
+		return nil, nil
+	}
+	if len(times) != mealsPerDay {
+		return nil, fmt.Errorf("喂食时间数量(%d)与每日餐数(%d)不一致", len(times), mealsPerDay)
+	}
+	result := make([]string, 0, len(times))
+	for _, t := range times {
+		parsed, err := time.Parse("15:04", strings.TrimSpace(t))
+		if err != nil {
+			return nil, fmt.Errorf("喂食时间格式错误,应为HH:MM: %s", t)
+		}
+		result = append(result, parsed.Format("15:04"))
+	}
+	sort.Strings(result)
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"pet-service/biz/model"
+)
+
+// petAged 按当前时间构造指定年龄的宠物,months<0表示未填写出生日期
+func petAged(species string, months int) *model.Pet {
+	pet := &model.Pet{Species: species}
+	if months >= 0 {
+		birth := time.Now().AddDate(0, -months, 0)
+		pet.BirthDate = &birth
+	}
+	return pet
+}
+
+func TestMERFactor(t *testing.T) {
+	tests := []struct {
+		name     string
+		pet      *model.Pet
+		level    string
+		neutered bool
+		want     float64
+		wantOK   bool
+	}{
+		{"成年犬正常活动", petAged("dog", 36), model.DietLevelNormal, false, 1.8, true},
+		{"成年犬绝育", petAged("dog", 36), model.DietLevelNormal, true, 1.6, true},
+		{"绝育只影响正常活动水平", petAged("dog", 36), model.DietLevelHigh, true, 2.0, true},
+		{"成年犬工作犬", petAged("dog", 36), model.DietLevelWorking, false, 3.0, true},
+		{"成年猫减重", petAged("cat", 36), model.DietLevelWeightLoss, false, 0.8, true},
+		{"成年猫绝育", petAged("cat", 36), model.DietLevelNormal, true, 1.2, true},
+		{"未填写出生日期按成年计算", petAged("dog", -1), model.DietLevelLow, false, 1.4, true},
+		{"4月龄以下幼犬", petAged("dog", 2), model.DietLevelNormal, true, 3.0, true},
+		{"4至12月龄幼犬", petAged("dog", 8), model.DietLevelLow, false, 2.0, true},
+		{"幼猫", petAged("cat", 2), model.DietLevelWeightLoss, true, 2.5, true},
+		{"猫没有工作活动水平", petAged("cat", 36), model.DietLevelWorking, false, 0, false},
+		{"未知活动水平", petAged("dog", 36), "lazy", false, 0, false},
+		{"未知物种", petAged("rabbit", 36), model.DietLevelNormal, false, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := merFactor(tt.pet, tt.level, tt.neutered)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("merFactor = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDietTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		pet    *model.Pet
+		plan   *model.DietPlan
+		weight float64
+		want   *model.DietTarget
+	}{
+		{
+			name:   "成年犬10kg",
+			pet:    petAged("dog", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal},
+			weight: 10,
+			want:   &model.DietTarget{WeightKg: 10, RER: 394, LifeStage: "adult", Factor: 1.8, MER: 709, TargetKcal: 709},
+		},
+		{
+			name:   "成年犬绝育",
+			pet:    petAged("dog", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal, Neutered: true},
+			weight: 10,
+			want:   &model.DietTarget{WeightKg: 10, RER: 394, LifeStage: "adult", Factor: 1.6, MER: 630, TargetKcal: 630},
+		},
+		{
+			name:   "体重1kg时RER等于系数",
+			pet:    petAged("cat", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelLow},
+			weight: 1,
+			want:   &model.DietTarget{WeightKg: 1, RER: 70, LifeStage: "adult", Factor: 1.0, MER: 70, TargetKcal: 70},
+		},
+		{
+			name:   "成年猫4kg绝育",
+			pet:    petAged("cat", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal, Neutered: true},
+			weight: 4,
+			want:   &model.DietTarget{WeightKg: 4, RER: 198, LifeStage: "adult", Factor: 1.2, MER: 238, TargetKcal: 238},
+		},
+		{
+			name:   "幼犬",
+			pet:    petAged("dog", 2),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal},
+			weight: 10,
+			want:   &model.DietTarget{WeightKg: 10, RER: 394, LifeStage: "young", Factor: 3.0, MER: 1182, TargetKcal: 1182},
+		},
+		{
+			name:   "兽医指定目标优先",
+			pet:    petAged("dog", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelWeightLoss, TargetKcal: 300},
+			weight: 10,
+			want:   &model.DietTarget{WeightKg: 10, RER: 394, LifeStage: "adult", Factor: 1.0, MER: 394, TargetKcal: 300, Custom: true},
+		},
+		{
+			name:   "没有体重时只使用指定目标",
+			pet:    petAged("dog", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal, TargetKcal: 500},
+			weight: 0,
+			want:   &model.DietTarget{LifeStage: "adult", TargetKcal: 500, Custom: true},
+		},
+		{
+			name:   "没有体重且未指定目标",
+			pet:    petAged("dog", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal},
+			weight: 0,
+			want:   nil,
+		},
+		{
+			name:   "未知物种且未指定目标",
+			pet:    petAged("rabbit", 36),
+			plan:   &model.DietPlan{ActivityLevel: model.DietLevelNormal},
+			weight: 2,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dietTarget(tt.pet, tt.plan, tt.weight)
+			if got == nil || tt.want == nil {
+				if got != tt.want {
+					t.Fatalf("dietTarget = %+v, want %+v", got, tt.want)
+				}
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("dietTarget = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestPlannedKcal(t *testing.T) {
+	tests := []struct {
+		name string
+		plan *model.DietPlan
+		want int
+	}{
+		{"每日两餐", &model.DietPlan{PortionGrams: 100, MealsPerDay: 2, KcalPerKg: 3500}, 700},
+		{"四舍五入", &model.DietPlan{PortionGrams: 33.3, MealsPerDay: 3, KcalPerKg: 3800}, 380},
+		{"未填写热量密度", &model.DietPlan{PortionGrams: 100, MealsPerDay: 2}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plannedKcal(tt.plan); got != tt.want {
+				t.Errorf("plannedKcal = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgeMonths(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) *time.Time {
+		d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	tests := []struct {
+		name  string
+		birth *time.Time
+		want  int
+	}{
+		{"未填写出生日期", nil, -1},
+		{"当天出生", date(2026, 10, 18), 0},
+		{"满一个月", date(2026, 9, 18), 1},
+		{"差一天满一个月", date(2026, 9, 19), 0},
+		{"跨年", date(2025, 11, 30), 10},
+		{"满三年", date(2023, 10, 18), 36},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ageMonths(&model.Pet{BirthDate: tt.birth}, now); got != tt.want {
+				t.Errorf("ageMonths = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,474 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/pdf"
+)
+
+// verifyCodeEncoding 去掉易混淆字符0/1/I/O的base32字母表,验证码不区分大小写
+var verifyCodeEncoding = base32.NewEncoding("ABCDEFGHJKLMNPQRSTUVWXYZ23456789").WithPadding(base32.NoPadding)
+
+// verifyCodeLen 验证码长度,12位约60位随机数
+const verifyCodeLen = 12
+
+// DocumentService PDF文档服务接口
+type DocumentService interface {
+	// VaccinationCertificate 生成宠物的疫苗接种证明
+	VaccinationCertificate(ctx context.Context, userID, petID uint) ([]byte, *model.IssuedDocument, error)
+	// OrderInvoice 生成已支付订单的发票
+	OrderInvoice(ctx context.Context, userID, orderID uint) ([]byte, *model.IssuedDocument, error)
+	// AppointmentInvoice 生成已完成门诊预约的发票
+	AppointmentInvoice(ctx context.Context, userID, appointmentID uint) ([]byte, *model.IssuedDocument, error)
+	// DonationReceipt 生成已到账捐款的收据
+	DonationReceipt(ctx context.Context, userID, donationID uint) ([]byte, *model.IssuedDocument, error)
+	// Verify 公开核验文档验证码
+	Verify(ctx context.Context, code string) (*model.DocumentVerification, error)
+}
+
+// documentService PDF文档服务实现
+type documentService struct {
+	documentRepo       repository.DocumentRepository
+	recordRepo         repository.MedicalRecordRepository
+	petService         PetService
+	orderService       OrderService
+	appointmentService AppointmentService
+	donationService    DonationService
+	renderer           *pdf.Renderer
+	publicURL          string
+}
+
+// NewDocumentService 创建PDF文档服务
+func NewDocumentService(documentRepo repository.DocumentRepository, recordRepo repository.MedicalRecordRepository, petService PetService, orderService OrderService,
+	appointmentService AppointmentService, donationService DonationService, renderer *pdf.Renderer, publicURL string) DocumentService {
+	return &documentService{
+		documentRepo:       documentRepo,
+		recordRepo:         recordRepo,
+		petService:         petService,
+		orderService:       orderService,
+		appointmentService: appointmentService,
+		donationService:    donationService,
+		renderer:           renderer,
+		publicURL:          strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// VaccinationCertificate 根据疫苗接种类型的就诊记录生成证明,宠物成员均可下载
+func (s *documentService) VaccinationCertificate(ctx context.Context, userID, petID uint) ([]byte, *model.IssuedDocument, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer)
+	if err != nil {
+		return nil, nil, err
+	}
+	records, err := s.recordRepo.ListByType(ctx, petID, model.MedicalRecordVaccination)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, errors.New("暂无疫苗接种记录,无法生成证明")
+	}
+
+	summary := &model.VaccinationSummary{
+		PetName:      pet.Name,
+		Species:      pet.Species,
+		Breed:        pet.Breed,
+		Vaccinations: make([]model.VaccinationDetail, 0, len(records)),
+	}
+	if pet.Microchip != nil {
+		summary.Microchip = *pet.Microchip
+	}
+	for _, record := range records {
+		detail := model.VaccinationDetail{
+			Vaccine: record.VaccineName,
+			BatchNo: record.BatchNo,
+			Date:    record.VisitDate.Format(model.DateLayout),
+			Clinic:  record.ClinicName,
+			Vet:     record.VetName,
+		}
+		if record.ValidUntil != nil {
+			detail.ValidUntil = record.ValidUntil.Format(model.DateLayout)
+		}
+		summary.Vaccinations = append(summary.Vaccinations, detail)
+	}
+
+	r := s.renderer
+	title := r.Label("疫苗接种证明", "Vaccination Certificate")
+	issued, err := s.issue(ctx, userID, model.DocumentVaccinationCertificate, petID, title, summary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	petFields := []pdf.Field{
+		{Label: r.Label("宠物名称", "Name"), Value: summary.PetName},
+		{Label: r.Label("物种", "Species"), Value: summary.Species},
+	}
+	if summary.Breed != "" {
+		petFields = append(petFields, pdf.Field{Label: r.Label("品种", "Breed"), Value: summary.Breed})
+	}
+	if pet.BirthDate != nil {
+		petFields = append(petFields, pdf.Field{Label: r.Label("出生日期", "Date of birth"), Value: pet.BirthDate.Format(model.DateLayout)})
+	}
+	if summary.Microchip != "" {
+		petFields = append(petFields, pdf.Field{Label: r.Label("芯片号", "Microchip"), Value: summary.Microchip})
+	}
+
+	rows := make([][]string, 0, len(summary.Vaccinations))
+	for _, v := range summary.Vaccinations {
+		provider := v.Clinic
+		if v.Vet != "" {
+			provider = strings.TrimSpace(provider + " " + v.Vet)
+		}
+		rows = append(rows, []string{v.Vaccine, v.BatchNo, v.Date, v.ValidUntil, provider})
+	}
+
+	data, err := r.Render(&pdf.Document{
+		Title:    title,
+		Subtitle: r.Label("宠物服务平台", "Pet Service"),
+		Sections: []pdf.Section{
+			{Heading: r.Label("宠物信息", "Pet"), Fields: petFields},
+			{
+				Heading: r.Label("接种记录", "Vaccinations"),
+				Table: &pdf.Table{
+					Columns: []pdf.Column{
+						{Title: r.Label("疫苗", "Vaccine"), Width: 50},
+						{Title: r.Label("批号", "Batch"), Width: 25},
+						{Title: r.Label("接种日期", "Date"), Width: 25, Align: "C"},
+						{Title: r.Label("有效期至", "Valid until"), Width: 25, Align: "C"},
+						{Title: r.Label("接种机构/兽医", "Clinic / Vet"), Width: 55},
+					},
+					Rows: rows,
+				},
+			},
+		},
+		Notes: []string{
+			r.Label("本证明根据宠物主人在平台登记的疫苗接种记录生成,请以接种机构出具的原始凭证为准。",
+				"Generated from vaccination records entered by the pet's owner; the original records issued by the clinic prevail."),
+		},
+		VerifyCode:  formatVerifyCode(issued.Code),
+		VerifyURL:   s.verifyURL(issued.Code),
+		VerifyLabel: r.Label("扫描二维码或访问以下链接核验本证明", "Scan the QR code or visit the link below to verify this document"),
+		IssuedAt:    issued.CreatedAt,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, issued, nil
+}
+
+// OrderInvoice 生成订单发票,仅下单用户可下载,未支付的订单不能开具
+func (s *documentService) OrderInvoice(ctx context.Context, userID, orderID uint) ([]byte, *model.IssuedDocument, error) {
+	order, err := s.orderService.GetOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if order.PaidAt == nil {
+		return nil, nil, errors.New("订单未支付,无法开具发票")
+	}
+
+	inv := &invoice{
+		Summary: model.InvoiceSummary{
+			InvoiceNo:      order.OrderNo,
+			Subject:        order.Subject,
+			Currency:       order.Currency,
+			TotalAmount:    order.TotalAmount,
+			DiscountAmount: order.DiscountAmount,
+			PayAmount:      order.PayAmount,
+			RefundedAmount: order.RefundedAmount,
+			PaidAt:         order.PaidAt.Format("2006-01-02 15:04:05"),
+			ItemCount:      len(order.Items),
+		},
+		CouponDiscount: order.CouponDiscount,
+		PointsDiscount: order.PointsDiscount,
+	}
+	for _, item := range order.Items {
+		inv.Items = append(inv.Items, invoiceItem{
+			Name:     item.Name,
+			Spec:     item.SKUName,
+			Price:    item.Price,
+			Quantity: item.Quantity,
+			Amount:   item.Amount,
+		})
+	}
+	return s.renderInvoice(ctx, userID, model.DocumentOrderInvoice, order.ID, inv)
+}
+
+// AppointmentInvoice 按预约时的服务快照开具发票,预约人和诊所人员均可下载
+func (s *documentService) AppointmentInvoice(ctx context.Context, userID, appointmentID uint) ([]byte, *model.IssuedDocument, error) {
+	appointment, err := s.appointmentService.Get(ctx, userID, appointmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if appointment.Status != model.AppointmentCompleted || appointment.CompletedAt == nil {
+		return nil, nil, errors.New("服务完成后才能开具发票")
+	}
+
+	subject := "门诊服务"
+	if appointment.Clinic != nil {
+		subject = appointment.Clinic.Name + " " + subject
+	}
+	inv := &invoice{
+		Summary: model.InvoiceSummary{
+			InvoiceNo:   appointment.AppointmentNo,
+			Subject:     subject,
+			Currency:    model.CurrencyCNY,
+			TotalAmount: appointment.TotalPrice,
+			PayAmount:   appointment.TotalPrice,
+			PaidAt:      appointment.CompletedAt.Format("2006-01-02 15:04:05"),
+			ItemCount:   len(appointment.Items),
+		},
+	}
+	for _, item := range appointment.Items {
+		inv.Items = append(inv.Items, invoiceItem{
+			Name:     item.Name,
+			Spec:     fmt.Sprintf("%s/%d分钟", appointment.PetSize, item.DurationMin),
+			Price:    item.Price,
+			Quantity: 1,
+			Amount:   item.Price,
+		})
+	}
+	return s.renderInvoice(ctx, userID, model.DocumentAppointmentInvoice, appointment.ID, inv)
+}
+
+// DonationReceipt 捐款收据,匿名捐款的收据同样印有捐款人名称,仅捐款人本人可下载
+func (s *documentService) DonationReceipt(ctx context.Context, userID, donationID uint) ([]byte, *model.IssuedDocument, error) {
+	donation, err := s.donationService.GetMyDonation(ctx, userID, donationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if donation.Status != model.DonationStatusPaid || donation.PaidAt == nil {
+		return nil, nil, errors.New("捐款到账后才能开具收据")
+	}
+
+	summary := &model.DonationReceiptSummary{
+		ReceiptNo: donation.DonationNo,
+		Currency:  donation.Currency,
+		Amount:    donation.Amount,
+		PaidAt:    donation.PaidAt.Format("2006-01-02 15:04:05"),
+	}
+	if donation.Campaign != nil {
+		summary.Campaign = donation.Campaign.Title
+		if donation.Campaign.Shelter != nil {
+			summary.Shelter = donation.Campaign.Shelter.Name
+		}
+	}
+
+	r := s.renderer
+	title := r.Label("捐款收据", "Donation Receipt")
+	issued, err := s.issue(ctx, userID, model.DocumentDonationReceipt, donation.ID, title, summary)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := r.Render(&pdf.Document{
+		Title:    title,
+		Subtitle: r.Label("宠物服务平台", "Pet Service"),
+		Sections: []pdf.Section{
+			{Fields: []pdf.Field{
+				{Label: r.Label("收据号", "Receipt No."), Value: summary.ReceiptNo},
+				{Label: r.Label("捐款人", "Donor"), Value: donation.DonorName},
+				{Label: r.Label("受赠机构", "Recipient"), Value: summary.Shelter},
+				{Label: r.Label("募捐活动", "Campaign"), Value: summary.Campaign},
+				{Label: r.Label("捐款金额", "Amount"), Value: formatYuan(summary.Amount) + " " + summary.Currency},
+				{Label: r.Label("到账时间", "Received at"), Value: summary.PaidAt},
+			}},
+		},
+		Notes: []string{
+			r.Label("感谢您对流浪动物救助事业的支持。本收据为平台出具的捐款凭证,不作为税前扣除凭证。",
+				"Thank you for supporting animal rescue. This receipt is issued by the platform as proof of donation and is not a tax deduction certificate."),
+		},
+		VerifyCode:  formatVerifyCode(issued.Code),
+		VerifyURL:   s.verifyURL(issued.Code),
+		VerifyLabel: r.Label("扫描二维码或访问以下链接核验本收据", "Scan the QR code or visit the link below to verify this receipt"),
+		IssuedAt:    issued.CreatedAt,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, issued, nil
+}
+
+// Verify 核验验证码,验证码不存在时返回valid=false而非错误
+func (s *documentService) Verify(ctx context.Context, code string) (*model.DocumentVerification, error) {
+	code = normalizeVerifyCode(code)
+	if len(code) != verifyCodeLen {
+		return &model.DocumentVerification{}, nil
+	}
+	doc, err := s.documentRepo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.DocumentVerification{}, nil
+		}
+		return nil, err
+	}
+	latest, err := s.documentRepo.GetLatest(ctx, doc.Kind, doc.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.DocumentVerification{
+		Valid:      true,
+		Document:   doc,
+		Superseded: latest.ID != doc.ID,
+	}, nil
+}
+
+// invoice 发票模板数据,金额单位为分
+type invoice struct {
+	Summary        model.InvoiceSummary
+	Items          []invoiceItem
+	CouponDiscount int64
+	PointsDiscount int64
+}
+
+type invoiceItem struct {
+	Name     string
+	Spec     string
+	Price    int64
+	Quantity int
+	Amount   int64
+}
+
+// renderInvoice 签发并渲染发票
+func (s *documentService) renderInvoice(ctx context.Context, userID uint, kind string, subjectID uint, inv *invoice) ([]byte, *model.IssuedDocument, error) {
+	r := s.renderer
+	title := r.Label("电子发票", "Invoice")
+	issued, err := s.issue(ctx, userID, kind, subjectID, title, &inv.Summary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sum := &inv.Summary
+	rows := make([][]string, 0, len(inv.Items))
+	for _, item := range inv.Items {
+		rows = append(rows, []string{item.Name, item.Spec, formatYuan(item.Price), fmt.Sprint(item.Quantity), formatYuan(item.Amount)})
+	}
+	footer := []pdf.Field{{Label: r.Label("商品总额", "Subtotal"), Value: formatYuan(sum.TotalAmount)}}
+	if inv.CouponDiscount > 0 {
+		footer = append(footer, pdf.Field{Label: r.Label("优惠券抵扣", "Coupon"), Value: "-" + formatYuan(inv.CouponDiscount)})
+	}
+	if inv.PointsDiscount > 0 {
+		footer = append(footer, pdf.Field{Label: r.Label("积分抵扣", "Points"), Value: "-" + formatYuan(inv.PointsDiscount)})
+	}
+	footer = append(footer, pdf.Field{Label: r.Label("实付金额", "Amount paid"), Value: formatYuan(sum.PayAmount) + " " + sum.Currency})
+	if sum.RefundedAmount > 0 {
+		footer = append(footer, pdf.Field{Label: r.Label("已退款", "Refunded"), Value: "-" + formatYuan(sum.RefundedAmount)})
+	}
+
+	data, err := r.Render(&pdf.Document{
+		Title:    title,
+		Subtitle: r.Label("宠物服务平台", "Pet Service"),
+		Sections: []pdf.Section{
+			{Fields: []pdf.Field{
+				{Label: r.Label("发票号", "Invoice No."), Value: sum.InvoiceNo},
+				{Label: r.Label("内容", "Subject"), Value: sum.Subject},
+				{Label: r.Label("支付时间", "Paid at"), Value: sum.PaidAt},
+			}},
+			{
+				Heading: r.Label("明细", "Items"),
+				Table: &pdf.Table{
+					Columns: []pdf.Column{
+						{Title: r.Label("名称", "Item"), Width: 75},
+						{Title: r.Label("规格", "Spec"), Width: 35},
+						{Title: r.Label("单价(元)", "Price"), Width: 25, Align: "R"},
+						{Title: r.Label("数量", "Qty"), Width: 15, Align: "R"},
+						{Title: r.Label("金额(元)", "Amount"), Width: 30, Align: "R"},
+					},
+					Rows:   rows,
+					Footer: footer,
+				},
+			},
+		},
+		VerifyCode:  formatVerifyCode(issued.Code),
+		VerifyURL:   s.verifyURL(issued.Code),
+		VerifyLabel: r.Label("扫描二维码或访问以下链接核验本发票", "Scan the QR code or visit the link below to verify this invoice"),
+		IssuedAt:    issued.CreatedAt,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, issued, nil
+}
+
+// issue 签发文档:内容与最近一次签发相同时复用验证码,否则生成新验证码
+func (s *documentService) issue(ctx context.Context, userID uint, kind string, subjectID uint, title string, summary interface{}) (*model.IssuedDocument, error) {
+	content, err := json.Marshal(summary)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(content)
+	digest := hex.EncodeToString(hash[:])
+
+	latest, err := s.documentRepo.GetLatest(ctx, kind, subjectID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if latest != nil && latest.Digest == digest && latest.Title == title {
+		return latest, nil
+	}
+
+	code, err := newVerifyCode()
+	if err != nil {
+		return nil, err
+	}
+	doc := &model.IssuedDocument{
+		Code:      code,
+		Kind:      kind,
+		SubjectID: subjectID,
+		Digest:    digest,
+		IssuedBy:  userID,
+		Title:     title,
+		Summary:   content,
+	}
+	if err := s.documentRepo.Create(ctx, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (s *documentService) verifyURL(code string) string {
+	return s.publicURL + "/api/v1/documents/verify/" + code
+}
+
+// newVerifyCode 生成12位验证码
+func newVerifyCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return verifyCodeEncoding.EncodeToString(b)[:verifyCodeLen], nil
+}
+
+// normalizeVerifyCode 去掉分隔符并转为大写,便于用户手工输入
+func normalizeVerifyCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+// formatVerifyCode 每4位插入分隔符,便于阅读
+func formatVerifyCode(code string) string {
+	var b strings.Builder
+	for i, r := range code {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte('-')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatYuan 将分格式化为元,保留两位小数
+func formatYuan(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
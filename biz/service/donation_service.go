@@ -0,0 +1,419 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+)
+
+const (
+	// maxCampaignDays 募捐活动的最长持续天数
+	maxCampaignDays = 366
+	// maxDonationAmount 单笔捐款上限(分)
+	maxDonationAmount = 100000000
+)
+
+// DonationService 募捐活动与捐款服务接口
+type DonationService interface {
+	CreateCampaign(ctx context.Context, userID, shelterID uint, req *model.SaveCampaignRequest) (*model.DonationCampaign, error)
+	UpdateCampaign(ctx context.Context, userID, campaignID uint, req *model.SaveCampaignRequest) (*model.DonationCampaign, error)
+	CloseCampaign(ctx context.Context, userID, campaignID uint) (*model.DonationCampaign, error)
+	GetCampaign(ctx context.Context, campaignID uint) (*model.DonationCampaign, error)
+	ListCampaigns(ctx context.Context, req *model.ListCampaignRequest) ([]*model.DonationCampaign, int64, error)
+	// ListDonors 活动已到账的捐款动态,匿名捐款不展示捐款人
+	ListDonors(ctx context.Context, campaignID uint, req *model.ListDonationRequest) ([]*model.PublicDonation, int64, error)
+
+	// Donate 创建捐款及待支付订单,使用返回的订单发起支付
+	Donate(ctx context.Context, userID, campaignID uint, req *model.CreateDonationRequest) (*model.DonationCheckout, error)
+	GetMyDonation(ctx context.Context, userID, donationID uint) (*model.Donation, error)
+	ListMyDonations(ctx context.Context, userID uint, req *model.ListDonationRequest) ([]*model.Donation, int64, error)
+	// ExportDonations 导出机构已到账的捐款(CSV),供财务对账
+	ExportDonations(ctx context.Context, userID, shelterID uint, req *model.ExportDonationRequest) ([]byte, error)
+
+	// HandlePaid 捐款订单支付成功回调
+	HandlePaid(ctx context.Context, order *model.Order) error
+}
+
+// donationService 募捐活动与捐款服务实现
+type donationService struct {
+	donationRepo    repository.DonationRepository
+	userRepo        repository.UserRepository
+	adoptionService AdoptionService
+	orderService    OrderService
+	notifier        notifier.Notifier
+}
+
+// NewDonationService 创建募捐活动与捐款服务
+func NewDonationService(donationRepo repository.DonationRepository, userRepo repository.UserRepository, adoptionService AdoptionService,
+	orderService OrderService, n notifier.Notifier) DonationService {
+	return &donationService{
+		donationRepo:    donationRepo,
+		userRepo:        userRepo,
+		adoptionService: adoptionService,
+		orderService:    orderService,
+		notifier:        n,
+	}
+}
+
+// CreateCampaign 创建募捐活动,仅机构管理员可操作
+func (s *donationService) CreateCampaign(ctx context.Context, userID, shelterID uint, req *model.SaveCampaignRequest) (*model.DonationCampaign, error) {
+	shelter, err := s.getManagedShelter(ctx, userID, shelterID)
+	if err != nil {
+		return nil, err
+	}
+	campaign := &model.DonationCampaign{
+		ShelterID: shelter.ID,
+		CreatedBy: userID,
+		Status:    model.CampaignStatusActive,
+	}
+	if err := applyCampaignRequest(campaign, req, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.donationRepo.CreateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(ctx, campaign.ID)
+}
+
+// UpdateCampaign 修改进行中的募捐活动
+func (s *donationService) UpdateCampaign(ctx context.Context, userID, campaignID uint, req *model.SaveCampaignRequest) (*model.DonationCampaign, error) {
+	campaign, err := s.getManagedCampaign(ctx, userID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !campaign.AcceptsDonations(now) {
+		return nil, errors.New("活动已结束,不能修改")
+	}
+	if err := applyCampaignRequest(campaign, req, now); err != nil {
+		return nil, err
+	}
+	if err := s.donationRepo.UpdateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(ctx, campaign.ID)
+}
+
+// CloseCampaign 提前结束募捐活动,已创建的待支付订单仍可完成支付
+func (s *donationService) CloseCampaign(ctx context.Context, userID, campaignID uint) (*model.DonationCampaign, error) {
+	campaign, err := s.getManagedCampaign(ctx, userID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.donationRepo.CloseCampaign(ctx, campaign.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.GetCampaign(ctx, campaign.ID)
+}
+
+// GetCampaign 募捐活动详情,含实时筹款进度
+func (s *donationService) GetCampaign(ctx context.Context, campaignID uint) (*model.DonationCampaign, error) {
+	campaign, err := s.donationRepo.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, checkNotFound(err, "募捐活动不存在")
+	}
+	fillCampaignProgress(campaign, time.Now())
+	return campaign, nil
+}
+
+// ListCampaigns 募捐活动列表,默认返回进行中的活动,按截止时间排序
+func (s *donationService) ListCampaigns(ctx context.Context, req *model.ListCampaignRequest) ([]*model.DonationCampaign, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	now := time.Now()
+	campaigns, total, err := s.donationRepo.ListCampaigns(ctx, req.ShelterID, req.Ended, now, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, campaign := range campaigns {
+		fillCampaignProgress(campaign, now)
+	}
+	return campaigns, total, nil
+}
+
+// ListDonors 活动的捐款动态
+func (s *donationService) ListDonors(ctx context.Context, campaignID uint, req *model.ListDonationRequest) ([]*model.PublicDonation, int64, error) {
+	if _, err := s.GetCampaign(ctx, campaignID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	donations, total, err := s.donationRepo.ListPaidByCampaign(ctx, campaignID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	result := make([]*model.PublicDonation, 0, len(donations))
+	for _, d := range donations {
+		item := &model.PublicDonation{
+			DonorName: d.DisplayName(),
+			Anonymous: d.Anonymous,
+			Amount:    d.Amount,
+			Message:   d.Message,
+		}
+		if d.PaidAt != nil {
+			item.PaidAt = *d.PaidAt
+		}
+		result = append(result, item)
+	}
+	return result, total, nil
+}
+
+// Donate 捐款,金额到账后才计入活动总额
+func (s *donationService) Donate(ctx context.Context, userID, campaignID uint, req *model.CreateDonationRequest) (*model.DonationCheckout, error) {
+	campaign, err := s.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Ended {
+		return nil, errors.New("募捐活动已结束")
+	}
+	if campaign.Shelter == nil || campaign.Shelter.Status != 1 {
+		return nil, errors.New("救助机构暂停募捐")
+	}
+	if req.Amount < 100 || req.Amount > maxDonationAmount {
+		return nil, fmt.Errorf("捐款金额需在1-%d元之间", maxDonationAmount/100)
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, checkNotFound(err, "用户不存在")
+	}
+	donorName := user.Nickname
+	if donorName == "" {
+		donorName = user.Username
+	}
+
+	no, err := newOrderNo("D")
+	if err != nil {
+		return nil, err
+	}
+	donation := &model.Donation{
+		DonationNo: no,
+		CampaignID: campaign.ID,
+		ShelterID:  campaign.ShelterID,
+		UserID:     userID,
+		DonorName:  truncate(donorName, 50),
+		Anonymous:  req.Anonymous,
+		Amount:     req.Amount,
+		Currency:   model.CurrencyCNY,
+		Message:    truncate(strings.TrimSpace(req.Message), 255),
+		Status:     model.DonationStatusPending,
+	}
+	if err := s.donationRepo.CreateDonation(ctx, donation); err != nil {
+		return nil, err
+	}
+	order, err := s.orderService.CreateOrder(ctx, userID, &model.CreateOrderRequest{
+		Type:     model.OrderTypeDonation,
+		SourceID: donation.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.DonationCheckout{Donation: donation, Order: order}, nil
+}
+
+// GetMyDonation 获取本人的捐款
+func (s *donationService) GetMyDonation(ctx context.Context, userID, donationID uint) (*model.Donation, error) {
+	donation, err := s.donationRepo.GetDonation(ctx, donationID)
+	if err != nil {
+		return nil, checkNotFound(err, "捐款不存在")
+	}
+	if donation.UserID != userID {
+		return nil, notFound("捐款不存在")
+	}
+	return donation, nil
+}
+
+// ListMyDonations 我的捐款
+func (s *donationService) ListMyDonations(ctx context.Context, userID uint, req *model.ListDonationRequest) ([]*model.Donation, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.donationRepo.ListByUser(ctx, userID, offset, limit)
+}
+
+// ExportDonations 导出已到账的捐款,匿名捐款不导出捐款人名称
+func (s *donationService) ExportDonations(ctx context.Context, userID, shelterID uint, req *model.ExportDonationRequest) ([]byte, error) {
+	if _, err := s.getManagedShelter(ctx, userID, shelterID); err != nil {
+		return nil, err
+	}
+	filter := &repository.DonationFilter{ShelterID: shelterID, CampaignID: req.CampaignID}
+	if req.From != "" {
+		from, err := parseDate(req.From)
+		if err != nil {
+			return nil, err
+		}
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, err := parseDate(req.To)
+		if err != nil {
+			return nil, err
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	donations, err := s.donationRepo.ListPaid(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	// 写入UTF-8 BOM,便于Excel正确识别中文
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"收据号", "到账时间", "募捐活动", "捐款人", "匿名", "金额(元)", "币种", "留言", "订单ID"})
+	for _, d := range donations {
+		campaign := ""
+		if d.Campaign != nil {
+			campaign = d.Campaign.Title
+		}
+		paidAt := ""
+		if d.PaidAt != nil {
+			paidAt = d.PaidAt.Format("2006-01-02 15:04:05")
+		}
+		anonymous := "否"
+		if d.Anonymous {
+			anonymous = "是"
+		}
+		_ = w.Write([]string{
+			d.DonationNo,
+			paidAt,
+			csvSafe(campaign),
+			csvSafe(d.DisplayName()),
+			anonymous,
+			formatYuan(d.Amount),
+			d.Currency,
+			csvSafe(d.Message),
+			strconv.FormatUint(uint64(d.OrderID), 10),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// HandlePaid 捐款到账后累加活动总额,订单直接完成,并通知捐款人和机构
+// 重复通知时到账只计入一次,并补完成上次未完成的订单
+func (s *donationService) HandlePaid(ctx context.Context, order *model.Order) error {
+	donation, err := s.donationRepo.GetDonation(ctx, order.SourceID)
+	if err != nil {
+		logger.Error(ctx, "获取捐款订单对应的捐款失败", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+		return err
+	}
+	paidAt := time.Now()
+	if order.PaidAt != nil {
+		paidAt = *order.PaidAt
+	}
+	changed, err := s.donationRepo.MarkPaid(ctx, donation, order.ID, paidAt)
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		title := ""
+		if donation.Campaign != nil {
+			title = donation.Campaign.Title
+		}
+		s.notify(ctx, donation, donation.UserID, "捐款已到账",
+			fmt.Sprintf("感谢您为「%s」捐款%s元,可在我的捐款中下载收据", title, formatYuan(donation.Amount)))
+		if donation.Campaign != nil && donation.Campaign.Shelter != nil {
+			s.notify(ctx, donation, donation.Campaign.Shelter.OwnerID, "收到新的捐款",
+				fmt.Sprintf("%s为「%s」捐款%s元", donation.DisplayName(), title, formatYuan(donation.Amount)))
+		}
+	}
+
+	// 捐款无需履约,到账即完成,不支持用户自助退款
+	if order.Status == model.OrderStatusPaid {
+		if _, err := s.orderService.FulfillOrder(ctx, order.ID); err != nil {
+			logger.Error(ctx, "完成捐款订单失败", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *donationService) notify(ctx context.Context, donation *model.Donation, userID uint, title, content string) {
+	err := s.notifier.Notify(ctx, &notifier.Message{
+		UserID:  userID,
+		Type:    model.NotifyTypeDonation,
+		Title:   title,
+		Content: content,
+		Data: map[string]interface{}{
+			"donation_id": donation.ID,
+			"campaign_id": donation.CampaignID,
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "发送捐款通知失败", logger.Int("donation_id", int(donation.ID)), logger.Int("user_id", int(userID)), logger.ErrorField(err))
+	}
+}
+
+// getManagedShelter 获取机构并校验当前用户是否为管理员
+func (s *donationService) getManagedShelter(ctx context.Context, userID, shelterID uint) (*model.Shelter, error) {
+	shelter, err := s.adoptionService.GetShelter(ctx, shelterID)
+	if err != nil {
+		return nil, err
+	}
+	if shelter.OwnerID != userID {
+		return nil, forbidden("仅机构管理员可执行该操作")
+	}
+	return shelter, nil
+}
+
+// getManagedCampaign 获取活动并校验当前用户是否为所属机构的管理员
+func (s *donationService) getManagedCampaign(ctx context.Context, userID, campaignID uint) (*model.DonationCampaign, error) {
+	campaign, err := s.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Shelter == nil || campaign.Shelter.OwnerID != userID {
+		return nil, forbidden("仅机构管理员可执行该操作")
+	}
+	return campaign, nil
+}
+
+// applyCampaignRequest 校验并写入活动信息
+func applyCampaignRequest(campaign *model.DonationCampaign, req *model.SaveCampaignRequest, now time.Time) error {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return errors.New("活动标题不能为空")
+	}
+	if req.GoalAmount < 100 {
+		return errors.New("目标金额至少1元")
+	}
+	if !req.Deadline.After(now) {
+		return errors.New("截止时间必须晚于当前时间")
+	}
+	if req.Deadline.After(now.AddDate(0, 0, maxCampaignDays)) {
+		return fmt.Errorf("活动最长持续%d天", maxCampaignDays)
+	}
+	campaign.Title = truncate(title, 100)
+	campaign.Description = strings.TrimSpace(req.Description)
+	campaign.GoalAmount = req.GoalAmount
+	campaign.Deadline = req.Deadline
+	return nil
+}
+
+// fillCampaignProgress 计算筹款进度百分比与是否已结束
+func fillCampaignProgress(campaign *model.DonationCampaign, now time.Time) {
+	if campaign.GoalAmount > 0 {
+		campaign.Progress = round2(float64(campaign.RaisedAmount) * 100 / float64(campaign.GoalAmount))
+	}
+	campaign.Ended = !campaign.AcceptsDonations(now)
+}
+
+// csvSafe 用户填写的内容以公式字符开头时加单引号,避免在表格软件中被当作公式执行
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
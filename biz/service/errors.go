@@ -0,0 +1,60 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrForbidden 无权限操作
+	ErrForbidden = errors.New("无权限操作")
+	// ErrNotFound 资源不存在
+	ErrNotFound = errors.New("资源不存在")
+	// ErrInvalidParam 请求参数不合法
+	ErrInvalidParam = errors.New("参数错误")
+)
+
+// bizError 带提示信息的业务错误,可通过errors.Is判断错误类别
+type bizError struct {
+	msg  string
+	kind error
+}
+
+func (e *bizError) Error() string { return e.msg }
+
+func (e *bizError) Unwrap() error { return e.kind }
+
+// forbidden 创建无权限错误
+func forbidden(msg string) error {
+	return &bizError{msg: msg, kind: ErrForbidden}
+}
+
+// notFound 创建资源不存在错误
+func notFound(msg string) error {
+	return &bizError{msg: msg, kind: ErrNotFound}
+}
+
+// invalidParam 创建参数不合法错误
+func invalidParam(msg string) error {
+	return &bizError{msg: msg, kind: ErrInvalidParam}
+}
+
+// checkNotFound 将仓储层的记录不存在错误转换为业务错误
+func checkNotFound(err error, msg string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound(msg)
+	}
+	return err
+}
+
+// pageOffset 计算分页偏移量,页码与每页数量非法时使用默认值
+func pageOffset(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+	return (page - 1) * pageSize, pageSize
+}
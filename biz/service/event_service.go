@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/ical"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+)
+
+const (
+	// defaultEventRemindMin 未指定时活动开始前提醒的分钟数
+	defaultEventRemindMin = 120
+	// maxEventDuration 单个活动最长持续时间
+	maxEventDuration = 7 * 24 * time.Hour
+	// eventSchedulerInterval 活动提醒调度间隔
+	eventSchedulerInterval = time.Minute
+	// eventSchedulerBatch 每轮处理的活动数量
+	eventSchedulerBatch = 50
+	// eventICSProdID 导出日历的产品标识
+	eventICSProdID = "-//pet-service//events//CN"
+)
+
+// EventService 社区活动服务接口
+type EventService interface {
+	CreateEvent(ctx context.Context, userID uint, req *model.SaveEventRequest) (*model.Event, error)
+	UpdateEvent(ctx context.Context, userID, id uint, req *model.SaveEventRequest) (*model.Event, error)
+	CancelEvent(ctx context.Context, userID, id uint) (*model.Event, error)
+	GetEvent(ctx context.Context, id uint) (*model.Event, error)
+	ListEvents(ctx context.Context, req *model.ListEventRequest) ([]*model.Event, int64, error)
+	ListOrganizedEvents(ctx context.Context, userID uint, req *model.ListMyEventsRequest) ([]*model.Event, int64, error)
+	ExportICS(ctx context.Context, id uint) ([]byte, error)
+
+	RSVP(ctx context.Context, userID, eventID uint, req *model.RSVPRequest) (*model.EventRSVP, error)
+	CancelRSVP(ctx context.Context, userID, eventID uint) error
+	GetMyRSVP(ctx context.Context, userID, eventID uint) (*model.EventRSVP, error)
+	ListMyRSVPs(ctx context.Context, userID uint, req *model.ListMyEventsRequest) ([]*model.EventRSVP, int64, error)
+	ListRSVPs(ctx context.Context, userID, eventID uint, req *model.ListRSVPRequest) ([]*model.EventRSVP, int64, error)
+
+	// RunScheduler 定时发送活动开始前提醒,ctx取消后退出
+	RunScheduler(ctx context.Context)
+}
+
+// eventService 社区活动服务实现
+type eventService struct {
+	eventRepo  repository.EventRepository
+	petService PetService
+	notifier   notifier.Notifier
+	publicURL  string
+}
+
+// NewEventService 创建社区活动服务,publicURL用于生成日历中的活动链接
+func NewEventService(eventRepo repository.EventRepository, petService PetService, n notifier.Notifier, publicURL string) EventService {
+	return &eventService{
+		eventRepo:  eventRepo,
+		petService: petService,
+		notifier:   n,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// CreateEvent 创建活动,创建者为组织者
+func (s *eventService) CreateEvent(ctx context.Context, userID uint, req *model.SaveEventRequest) (*model.Event, error) {
+	if err := validateEvent(req); err != nil {
+		return nil, err
+	}
+	if !req.StartAt.After(time.Now()) {
+		return nil, errors.New("开始时间必须晚于当前时间")
+	}
+
+	event := &model.Event{
+		OrganizerID: userID,
+		Status:      model.EventStatusPublished,
+	}
+	applyEventRequest(event, req)
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "创建活动", logger.Int("id", int(event.ID)), logger.Int("organizer_id", int(userID)))
+	return s.GetEvent(ctx, event.ID)
+}
+
+// UpdateEvent 组织者修改活动,时间或地点变化时通知报名者,名额增加时按候补顺序递补
+func (s *eventService) UpdateEvent(ctx context.Context, userID, id uint, req *model.SaveEventRequest) (*model.Event, error) {
+	event, err := s.organizerEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != model.EventStatusPublished {
+		return nil, errors.New("活动已取消")
+	}
+	if !event.StartAt.After(time.Now()) {
+		return nil, errors.New("活动已开始,无法修改")
+	}
+	if err := validateEvent(req); err != nil {
+		return nil, err
+	}
+
+	rescheduled := !event.StartAt.Equal(req.StartAt) || !event.EndAt.Equal(req.EndAt)
+	relocated := event.Location != req.Location
+	if rescheduled {
+		if !req.StartAt.After(time.Now()) {
+			return nil, errors.New("开始时间必须晚于当前时间")
+		}
+		// 时间变化后按新的开始时间重新提醒
+		event.RemindedAt = nil
+	}
+	applyEventRequest(event, req)
+
+	promoted, err := s.eventRepo.Update(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	for _, rsvp := range promoted {
+		s.notifyPromoted(ctx, event, rsvp.UserID)
+	}
+	if rescheduled || relocated {
+		s.notifyAttendees(ctx, event, model.NotifyTypeEventUpdate, "活动信息变更",
+			fmt.Sprintf("%s 调整为 %s 在 %s 举行", event.Title, event.StartAt.Format("01-02 15:04"), event.Location))
+	}
+	return s.GetEvent(ctx, id)
+}
+
+// CancelEvent 组织者取消活动并通知全部报名者
+func (s *eventService) CancelEvent(ctx context.Context, userID, id uint) (*model.Event, error) {
+	event, err := s.organizerEvent(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !event.EndAt.After(time.Now()) {
+		return nil, errors.New("活动已结束")
+	}
+
+	cancelled, err := s.eventRepo.Cancel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, errors.New("活动已取消")
+	}
+	event.Status = model.EventStatusCancelled
+	s.notifyAttendees(ctx, event, model.NotifyTypeEventUpdate, "活动已取消",
+		fmt.Sprintf("原定 %s 举行的 %s 已被组织者取消", event.StartAt.Format("01-02 15:04"), event.Title))
+	logger.Info(ctx, "取消活动", logger.Int("id", int(id)), logger.Int("organizer_id", int(userID)))
+	return event, nil
+}
+
+// GetEvent 获取活动详情
+func (s *eventService) GetEvent(ctx context.Context, id uint) (*model.Event, error) {
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "活动不存在")
+	}
+	return event, nil
+}
+
+// ListEvents 检索未结束的活动
+func (s *eventService) ListEvents(ctx context.Context, req *model.ListEventRequest) ([]*model.Event, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.eventRepo.List(ctx, req.City, req.Species, strings.TrimSpace(req.Keyword), time.Now(), offset, limit)
+}
+
+// ListOrganizedEvents 我组织的活动
+func (s *eventService) ListOrganizedEvents(ctx context.Context, userID uint, req *model.ListMyEventsRequest) ([]*model.Event, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.eventRepo.ListByOrganizer(ctx, userID, offset, limit)
+}
+
+// ExportICS 导出活动的iCalendar文件,包含开始前的提醒
+func (s *eventService) ExportICS(ctx context.Context, id uint) ([]byte, error) {
+	event, err := s.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	description := event.Description
+	if event.Organizer != nil {
+		organizer := event.Organizer.Nickname
+		if organizer == "" {
+			organizer = event.Organizer.Username
+		}
+		description = strings.TrimSpace(description + "\n组织者: " + organizer)
+	}
+	location := event.Location
+	if event.City != "" && !strings.HasPrefix(location, event.City) {
+		location = event.City + " " + location
+	}
+	return ical.Calendar(eventICSProdID, &ical.Event{
+		UID:         fmt.Sprintf("event-%d@pet-service", event.ID),
+		Summary:     event.Title,
+		Description: description,
+		Location:    location,
+		URL:         fmt.Sprintf("%s/api/v1/events/%d", s.publicURL, event.ID),
+		Start:       event.StartAt,
+		End:         event.EndAt,
+		Created:     event.CreatedAt,
+		Cancelled:   event.Status == model.EventStatusCancelled,
+		Latitude:    event.Latitude,
+		Longitude:   event.Longitude,
+		AlarmBefore: time.Duration(event.RemindBeforeMin) * time.Minute,
+	}), nil
+}
+
+// RSVP 报名活动,携带的宠物须为自己可编辑的宠物且物种符合活动要求,名额已满时进入候补
+func (s *eventService) RSVP(ctx context.Context, userID, eventID uint, req *model.RSVPRequest) (*model.EventRSVP, error) {
+	event, err := s.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.OrganizerID == userID {
+		return nil, errors.New("组织者无需报名")
+	}
+
+	petIDs := uniqueIDs(req.PetIDs)
+	for _, petID := range petIDs {
+		pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if !event.AllowsSpecies(pet.Species) {
+			return nil, fmt.Errorf("该活动不允许携带%s", pet.Species)
+		}
+	}
+
+	rsvp := &model.EventRSVP{
+		EventID: eventID,
+		UserID:  userID,
+		PetIDs:  petIDs,
+		Note:    req.Note,
+	}
+	if err := s.eventRepo.RSVP(ctx, rsvp); err != nil {
+		return nil, err
+	}
+	if err := s.fillPosition(ctx, rsvp); err != nil {
+		return nil, err
+	}
+	return rsvp, nil
+}
+
+// CancelRSVP 取消报名,空出的名额由候补第一位递补并通知
+func (s *eventService) CancelRSVP(ctx context.Context, userID, eventID uint) error {
+	event, err := s.GetEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	promoted, err := s.eventRepo.CancelRSVP(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return notFound("未报名该活动")
+		}
+		return err
+	}
+	for _, rsvp := range promoted {
+		s.notifyPromoted(ctx, event, rsvp.UserID)
+	}
+	return nil
+}
+
+// GetMyRSVP 获取当前用户的报名状态,候补中时返回排队位置
+func (s *eventService) GetMyRSVP(ctx context.Context, userID, eventID uint) (*model.EventRSVP, error) {
+	rsvp, err := s.eventRepo.GetRSVP(ctx, eventID, userID)
+	if err != nil {
+		return nil, checkNotFound(err, "未报名该活动")
+	}
+	if err := s.fillPosition(ctx, rsvp); err != nil {
+		return nil, err
+	}
+	return rsvp, nil
+}
+
+// ListMyRSVPs 我报名或候补中的活动
+func (s *eventService) ListMyRSVPs(ctx context.Context, userID uint, req *model.ListMyEventsRequest) ([]*model.EventRSVP, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.eventRepo.ListUserRSVPs(ctx, userID, offset, limit)
+}
+
+// ListRSVPs 组织者查看报名名单
+func (s *eventService) ListRSVPs(ctx context.Context, userID, eventID uint, req *model.ListRSVPRequest) ([]*model.EventRSVP, int64, error) {
+	if _, err := s.organizerEvent(ctx, userID, eventID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.eventRepo.ListRSVPs(ctx, eventID, req.Status, offset, limit)
+}
+
+// organizerEvent 获取活动并校验当前用户为组织者
+func (s *eventService) organizerEvent(ctx context.Context, userID, id uint) (*model.Event, error) {
+	event, err := s.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.OrganizerID != userID {
+		return nil, forbidden("只有组织者可以管理该活动")
+	}
+	return event, nil
+}
+
+// fillPosition 候补中的报名填充排队位置
+func (s *eventService) fillPosition(ctx context.Context, rsvp *model.EventRSVP) error {
+	if rsvp.Status != model.RSVPStatusWaitlisted {
+		return nil
+	}
+	position, err := s.eventRepo.WaitlistPosition(ctx, rsvp)
+	if err != nil {
+		return err
+	}
+	rsvp.WaitlistPosition = position
+	return nil
+}
+
+// notifyPromoted 通知候补递补成功的用户
+func (s *eventService) notifyPromoted(ctx context.Context, event *model.Event, userID uint) {
+	s.notify(ctx, event, userID, model.NotifyTypeEventUpdate, "候补成功",
+		fmt.Sprintf("你已从候补转为正式报名: %s, %s", event.Title, event.StartAt.Format("01-02 15:04")))
+}
+
+// notifyAttendees 通知已报名和候补中的全部用户
+func (s *eventService) notifyAttendees(ctx context.Context, event *model.Event, notifyType, title, content string) {
+	userIDs, err := s.eventRepo.ListAttendeeIDs(ctx, event.ID, model.RSVPStatusGoing, model.RSVPStatusWaitlisted)
+	if err != nil {
+		return
+	}
+	for _, userID := range userIDs {
+		s.notify(ctx, event, userID, notifyType, title, content)
+	}
+}
+
+// notify 发送活动通知,发送失败不影响活动操作
+func (s *eventService) notify(ctx context.Context, event *model.Event, userID uint, notifyType, title, content string) {
+	err := s.notifier.Notify(ctx, &notifier.Message{
+		UserID:  userID,
+		Type:    notifyType,
+		Title:   title,
+		Content: content,
+		Data: map[string]interface{}{
+			"event_id": event.ID,
+			"start_at": event.StartAt,
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "发送活动通知失败", logger.Int("event_id", int(event.ID)), logger.Int("user_id", int(userID)), logger.ErrorField(err))
+	}
+}
+
+// RunScheduler 每分钟检查即将开始的活动并提醒报名者和组织者
+func (s *eventService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(eventSchedulerInterval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "活动提醒调度已启动")
+	for {
+		s.runOnce(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "活动提醒调度已停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 执行一轮提醒,单个活动失败只记录日志
+func (s *eventService) runOnce(ctx context.Context, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "活动提醒调度异常", logger.Any("panic", r))
+		}
+	}()
+
+	events, err := s.eventRepo.ListDueReminders(ctx, now, eventSchedulerBatch)
+	if err != nil {
+		return
+	}
+	for _, event := range events {
+		claimed, err := s.eventRepo.ClaimReminder(ctx, event.ID, now)
+		if err != nil || !claimed {
+			continue
+		}
+		content := fmt.Sprintf("%s 将于 %s 在 %s 开始", event.Title, event.StartAt.Format("01-02 15:04"), event.Location)
+		s.notifyAttendees(ctx, event, model.NotifyTypeEventReminder, "活动即将开始", content)
+		s.notify(ctx, event, event.OrganizerID, model.NotifyTypeEventReminder, "活动即将开始", content)
+	}
+}
+
+// validateEvent 校验活动时间、名额和物种
+func validateEvent(req *model.SaveEventRequest) error {
+	if strings.TrimSpace(req.Title) == "" || strings.TrimSpace(req.Location) == "" {
+		return errors.New("标题和地点不能为空")
+	}
+	if req.Capacity < 1 {
+		return errors.New("名额至少为1")
+	}
+	if !req.EndAt.After(req.StartAt) {
+		return errors.New("结束时间必须晚于开始时间")
+	}
+	if req.EndAt.Sub(req.StartAt) > maxEventDuration {
+		return errors.New("活动持续时间不能超过7天")
+	}
+	if req.RemindBeforeMin != nil && (*req.RemindBeforeMin < 0 || *req.RemindBeforeMin > 10080) {
+		return errors.New("提醒时间需在0到10080分钟之间")
+	}
+	return nil
+}
+
+// applyEventRequest 将请求内容写入活动
+func applyEventRequest(event *model.Event, req *model.SaveEventRequest) {
+	event.Title = strings.TrimSpace(req.Title)
+	event.Description = req.Description
+	event.City = req.City
+	event.Location = strings.TrimSpace(req.Location)
+	event.Latitude = req.Latitude
+	event.Longitude = req.Longitude
+	event.StartAt = req.StartAt
+	event.EndAt = req.EndAt
+	event.Capacity = req.Capacity
+
+	event.AllowedSpecies = []string{}
+	seen := make(map[string]bool)
+	for _, species := range req.AllowedSpecies {
+		species = strings.TrimSpace(species)
+		if species != "" && !seen[species] {
+			seen[species] = true
+			event.AllowedSpecies = append(event.AllowedSpecies, species)
+		}
+	}
+
+	switch {
+	case req.RemindBeforeMin != nil:
+		event.RemindBeforeMin = *req.RemindBeforeMin
+	case event.ID == 0:
+		event.RemindBeforeMin = defaultEventRemindMin
+	}
+}
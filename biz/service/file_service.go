@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/config"
+	"pet-service/pkg/imaging"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/storage"
+)
+
+const (
+	// avatarMaxSize 头像大小上限
+	avatarMaxSize = 2 << 20
+	// thumbnailMaxSide 缩略图长边像素
+	thumbnailMaxSide = 320
+	// maxImagePixels 图片像素上限,防止解码炸弹
+	maxImagePixels = 40_000_000
+)
+
+// imageTypes 允许上传的图片类型及扩展名
+var imageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// attachmentTypes 附件额外允许的类型
+var attachmentTypes = map[string]string{
+	"application/pdf": ".pdf",
+}
+
+// FileService 文件服务接口
+type FileService interface {
+	Upload(ctx context.Context, userID uint, purpose string, header *multipart.FileHeader) (*model.File, error)
+	GetFile(ctx context.Context, userID, fileID uint) (*model.File, error)
+	DeleteFile(ctx context.Context, userID, fileID uint) error
+	Open(ctx context.Context, fileID uint, req *model.DownloadFileRequest) (io.ReadCloser, string, error)
+	GetOwnedImage(ctx context.Context, userID, fileID uint) (*model.File, error)
+	SignedURL(fileID uint, variant string) string
+}
+
+// fileService 文件服务实现
+type fileService struct {
+	fileRepo repository.FileRepository
+	store    storage.Storage
+	signer   *storage.URLSigner
+	cfg      *config.StorageConfig
+}
+
+// NewFileService 创建文件服务
+func NewFileService(fileRepo repository.FileRepository, store storage.Storage, cfg *config.StorageConfig) FileService {
+	return &fileService{
+		fileRepo: fileRepo,
+		store:    store,
+		signer:   storage.NewURLSigner(cfg.SignSecret),
+		cfg:      cfg,
+	}
+}
+
+// Upload 上传文件:校验大小、嗅探类型、生成缩略图并写入存储
+func (s *fileService) Upload(ctx context.Context, userID uint, purpose string, header *multipart.FileHeader) (*model.File, error) {
+	limit := s.cfg.MaxUploadSize
+	if purpose == model.FilePurposeAvatar && limit > avatarMaxSize {
+		limit = avatarMaxSize
+	}
+	if header.Size > limit {
+		return nil, fmt.Errorf("文件大小不能超过%dMB", limit>>20)
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("文件大小不能超过%dMB", limit>>20)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("文件内容为空")
+	}
+
+	// 以文件内容嗅探类型,不信任客户端声明的Content-Type
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	ext, ok := imageTypes[contentType]
+	if !ok && purpose == model.FilePurposeAttachment {
+		ext, ok = attachmentTypes[contentType]
+	}
+	if !ok {
+		logger.Warn(ctx, "不支持的文件类型", logger.String("content_type", contentType), logger.String("purpose", purpose))
+		return nil, fmt.Errorf("不支持的文件类型: %s", contentType)
+	}
+
+	file := &model.File{
+		UserID:       userID,
+		Purpose:      purpose,
+		OriginalName: path.Base(header.Filename),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+	}
+	prefix := fmt.Sprintf("%s/%s/%s", purpose, time.Now().Format("2006/01/02"), uuid.New().String())
+	file.StorageKey = prefix + ext
+
+	var thumb *imaging.Thumbnail
+	if _, isImage := imageTypes[contentType]; isImage {
+		width, height, err := imaging.DecodeConfig(data)
+		if err != nil {
+			return nil, errors.New("图片格式错误")
+		}
+		if width*height > maxImagePixels {
+			return nil, errors.New("图片分辨率过大")
+		}
+		file.Width, file.Height = width, height
+
+		thumb, err = imaging.MakeThumbnail(data, thumbnailMaxSide)
+		if err != nil {
+			logger.Error(ctx, "生成缩略图失败", logger.ErrorField(err))
+			return nil, errors.New("图片处理失败")
+		}
+		file.ThumbnailKey = prefix + "_thumb" + imageTypes[thumb.ContentType]
+	}
+
+	if err := s.store.Put(ctx, file.StorageKey, bytes.NewReader(data), file.Size, contentType); err != nil {
+		logger.Error(ctx, "写入文件失败", logger.String("key", file.StorageKey), logger.ErrorField(err))
+		return nil, errors.New("文件保存失败")
+	}
+	if thumb != nil {
+		if err := s.store.Put(ctx, file.ThumbnailKey, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumb.ContentType); err != nil {
+			logger.Error(ctx, "写入缩略图失败", logger.String("key", file.ThumbnailKey), logger.ErrorField(err))
+			s.removeObjects(ctx, file.StorageKey)
+			return nil, errors.New("文件保存失败")
+		}
+	}
+
+	if err := s.fileRepo.Create(ctx, file); err != nil {
+		s.removeObjects(ctx, file.StorageKey, file.ThumbnailKey)
+		return nil, err
+	}
+
+	s.fillURLs(file)
+	logger.Info(ctx, "文件上传成功",
+		logger.Int("id", int(file.ID)),
+		logger.String("content_type", contentType),
+		logger.Int64("size", file.Size),
+	)
+	return file, nil
+}
+
+// GetFile 获取文件信息及新的签名链接,仅上传者可访问
+func (s *fileService) GetFile(ctx context.Context, userID, fileID uint) (*model.File, error) {
+	file, err := s.getOwnedFile(ctx, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	s.fillURLs(file)
+	return file, nil
+}
+
+// DeleteFile 删除文件
+func (s *fileService) DeleteFile(ctx context.Context, userID, fileID uint) error {
+	file, err := s.getOwnedFile(ctx, userID, fileID)
+	if err != nil {
+		return err
+	}
+	if err := s.fileRepo.Delete(ctx, fileID); err != nil {
+		return err
+	}
+	s.removeObjects(ctx, file.StorageKey, file.ThumbnailKey)
+	return nil
+}
+
+// Open 校验签名后打开文件内容
+func (s *fileService) Open(ctx context.Context, fileID uint, req *model.DownloadFileRequest) (io.ReadCloser, string, error) {
+	variant := req.Variant
+	if variant == "" {
+		variant = model.FileVariantOriginal
+	}
+	if !s.signer.Verify(fileResource(fileID, variant), req.Expires, req.Signature) {
+		return nil, "", forbidden("下载链接无效或已过期")
+	}
+
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, "", checkNotFound(err, "文件不存在")
+	}
+
+	key, contentType := file.StorageKey, file.ContentType
+	if variant == model.FileVariantThumbnail && file.ThumbnailKey != "" {
+		key = file.ThumbnailKey
+		contentType = "image/jpeg"
+		if strings.HasSuffix(key, ".png") {
+			contentType = "image/png"
+		}
+	}
+
+	r, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, "", notFound("文件不存在")
+		}
+		logger.Error(ctx, "读取文件失败", logger.String("key", key), logger.ErrorField(err))
+		return nil, "", err
+	}
+	return r, contentType, nil
+}
+
+// GetOwnedImage 获取当前用户上传的图片文件,用于头像、宠物照片等引用
+func (s *fileService) GetOwnedImage(ctx context.Context, userID, fileID uint) (*model.File, error) {
+	file, err := s.getOwnedFile(ctx, userID, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if !file.IsImage() {
+		return nil, errors.New("文件不是图片")
+	}
+	return file, nil
+}
+
+// SignedURL 生成带签名的限时下载链接
+func (s *fileService) SignedURL(fileID uint, variant string) string {
+	expires := time.Now().Add(s.cfg.URLExpire).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signer.Sign(fileResource(fileID, variant), expires))
+	if variant != model.FileVariantOriginal {
+		query.Set("variant", variant)
+	}
+	return fmt.Sprintf("%s/api/v1/files/%d/download?%s", strings.TrimRight(s.cfg.PublicBaseURL, "/"), fileID, query.Encode())
+}
+
+// fillURLs 填充文件的签名下载链接
+func (s *fileService) fillURLs(file *model.File) {
+	file.URL = s.SignedURL(file.ID, model.FileVariantOriginal)
+	if file.ThumbnailKey != "" {
+		file.ThumbnailURL = s.SignedURL(file.ID, model.FileVariantThumbnail)
+	}
+	file.ExpiresAt = time.Now().Add(s.cfg.URLExpire).Unix()
+}
+
+// getOwnedFile 获取文件并校验上传者
+func (s *fileService) getOwnedFile(ctx context.Context, userID, fileID uint) (*model.File, error) {
+	file, err := s.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, checkNotFound(err, "文件不存在")
+	}
+	if file.UserID != userID {
+		return nil, forbidden("无权访问该文件")
+	}
+	return file, nil
+}
+
+// removeObjects 清理存储中的对象,失败仅记录日志
+func (s *fileService) removeObjects(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			logger.Warn(ctx, "清理存储对象失败", logger.String("key", key), logger.ErrorField(err))
+		}
+	}
+}
+
+// fileResource 签名覆盖的资源标识
+func fileResource(fileID uint, variant string) string {
+	return fmt.Sprintf("file:%d:%s", fileID, variant)
+}
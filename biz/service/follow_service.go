@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+	"pet-service/pkg/redis"
+)
+
+// followStatsTTL 关注计数缓存时长
+const followStatsTTL = 24 * time.Hour
+
+// FollowService 关注与拉黑服务接口
+type FollowService interface {
+	FollowUser(ctx context.Context, userID, targetID uint) (*model.UserRelation, error)
+	UnfollowUser(ctx context.Context, userID, targetID uint) (*model.UserRelation, error)
+	GetRelation(ctx context.Context, viewerID, targetID uint) (*model.UserRelation, error)
+	ListFollowers(ctx context.Context, viewerID, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error)
+	ListFollowing(ctx context.Context, viewerID, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error)
+	ListMutuals(ctx context.Context, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error)
+
+	FollowPet(ctx context.Context, userID, petID uint) (*model.PetFollowStats, error)
+	UnfollowPet(ctx context.Context, userID, petID uint) (*model.PetFollowStats, error)
+	GetPetStats(ctx context.Context, userID, petID uint) (*model.PetFollowStats, error)
+	ListPetFollowers(ctx context.Context, viewerID, petID uint, req *model.CursorRequest) (*model.PetFollowPage, error)
+	ListFollowedPets(ctx context.Context, userID uint, req *model.CursorRequest) (*model.PetFollowPage, error)
+
+	BlockUser(ctx context.Context, userID, targetID uint) error
+	UnblockUser(ctx context.Context, userID, targetID uint) error
+	ListBlocks(ctx context.Context, userID uint, req *model.CursorRequest) (*model.UserBlockPage, error)
+}
+
+// followService 关注与拉黑服务实现
+type followService struct {
+	followRepo repository.FollowRepository
+	userRepo   repository.UserRepository
+	petRepo    repository.PetRepository
+	notifier   notifier.Notifier
+}
+
+// NewFollowService 创建关注与拉黑服务
+func NewFollowService(followRepo repository.FollowRepository, userRepo repository.UserRepository, petRepo repository.PetRepository, n notifier.Notifier) FollowService {
+	return &followService{
+		followRepo: followRepo,
+		userRepo:   userRepo,
+		petRepo:    petRepo,
+		notifier:   n,
+	}
+}
+
+// FollowUser 关注用户,重复关注不重复计数,与对方互相拉黑时不能关注
+func (s *followService) FollowUser(ctx context.Context, userID, targetID uint) (*model.UserRelation, error) {
+	if err := s.checkTarget(ctx, userID, targetID); err != nil {
+		return nil, err
+	}
+	blocked, err := s.followRepo.BlockedEither(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, forbidden("无法关注该用户")
+	}
+
+	added, err := s.followRepo.FollowUser(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		s.bumpStats(ctx, userStatsKey(userID), "following", 1)
+		s.bumpStats(ctx, userStatsKey(targetID), "followers", 1)
+		s.notifyFollow(ctx, userID, targetID)
+	}
+	return s.GetRelation(ctx, userID, targetID)
+}
+
+// notifyFollow 通知被关注的用户,发送失败不影响关注
+func (s *followService) notifyFollow(ctx context.Context, followerID, targetID uint) {
+	follower, err := s.userRepo.GetByID(ctx, followerID)
+	if err != nil {
+		return
+	}
+	name := follower.Nickname
+	if name == "" {
+		name = follower.Username
+	}
+	err = s.notifier.Notify(ctx, &notifier.Message{
+		UserID:  targetID,
+		Type:    model.NotifyTypeFollow,
+		Title:   "新增关注",
+		Content: fmt.Sprintf("%s 关注了你", name),
+		Data:    map[string]interface{}{"user_id": followerID},
+	})
+	if err != nil {
+		logger.Error(ctx, "发送关注通知失败", logger.Int("user_id", int(targetID)), logger.ErrorField(err))
+	}
+}
+
+// UnfollowUser 取消关注,未关注时直接返回
+func (s *followService) UnfollowUser(ctx context.Context, userID, targetID uint) (*model.UserRelation, error) {
+	removed, err := s.followRepo.UnfollowUser(ctx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		s.bumpStats(ctx, userStatsKey(userID), "following", -1)
+		s.bumpStats(ctx, userStatsKey(targetID), "followers", -1)
+	}
+	return s.GetRelation(ctx, userID, targetID)
+}
+
+// GetRelation 当前用户与目标用户的关系及目标用户的关注计数
+func (s *followService) GetRelation(ctx context.Context, viewerID, targetID uint) (*model.UserRelation, error) {
+	if _, err := s.userRepo.GetByID(ctx, targetID); err != nil {
+		return nil, checkNotFound(err, "用户不存在")
+	}
+	stats, err := s.userStats(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	relation := &model.UserRelation{
+		UserID:    targetID,
+		Followers: stats["followers"],
+		Following: stats["following"],
+	}
+	if viewerID == targetID {
+		return relation, nil
+	}
+
+	if relation.IsFollowing, err = s.followRepo.IsFollowing(ctx, viewerID, targetID); err != nil {
+		return nil, err
+	}
+	if relation.IsFollowedBy, err = s.followRepo.IsFollowing(ctx, targetID, viewerID); err != nil {
+		return nil, err
+	}
+	if relation.Blocked, err = s.followRepo.IsBlocked(ctx, viewerID, targetID); err != nil {
+		return nil, err
+	}
+	relation.IsMutual = relation.IsFollowing && relation.IsFollowedBy
+	return relation, nil
+}
+
+// ListFollowers 用户的粉丝
+func (s *followService) ListFollowers(ctx context.Context, viewerID, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error) {
+	if err := s.checkVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+	limit := cursorLimit(req.Limit)
+	follows, err := s.followRepo.ListFollowers(ctx, userID, viewerID, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return userFollowPage(follows, limit), nil
+}
+
+// ListFollowing 用户关注的人
+func (s *followService) ListFollowing(ctx context.Context, viewerID, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error) {
+	if err := s.checkVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+	limit := cursorLimit(req.Limit)
+	follows, err := s.followRepo.ListFollowing(ctx, userID, viewerID, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return userFollowPage(follows, limit), nil
+}
+
+// ListMutuals 与当前用户互相关注的人
+func (s *followService) ListMutuals(ctx context.Context, userID uint, req *model.CursorRequest) (*model.UserFollowPage, error) {
+	limit := cursorLimit(req.Limit)
+	follows, err := s.followRepo.ListMutuals(ctx, userID, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return userFollowPage(follows, limit), nil
+}
+
+// FollowPet 关注宠物,与宠物主人互相拉黑时不能关注
+func (s *followService) FollowPet(ctx context.Context, userID, petID uint) (*model.PetFollowStats, error) {
+	pet, err := s.petRepo.GetByID(ctx, petID)
+	if err != nil {
+		return nil, checkNotFound(err, "宠物不存在")
+	}
+	if pet.OwnerID != 0 && pet.OwnerID != userID {
+		blocked, err := s.followRepo.BlockedEither(ctx, userID, pet.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, forbidden("无法关注该宠物")
+		}
+	}
+
+	added, err := s.followRepo.FollowPet(ctx, userID, petID)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		s.bumpStats(ctx, petStatsKey(petID), "followers", 1)
+	}
+	return s.GetPetStats(ctx, userID, petID)
+}
+
+// UnfollowPet 取消关注宠物,未关注时直接返回
+func (s *followService) UnfollowPet(ctx context.Context, userID, petID uint) (*model.PetFollowStats, error) {
+	removed, err := s.followRepo.UnfollowPet(ctx, userID, petID)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		s.bumpStats(ctx, petStatsKey(petID), "followers", -1)
+	}
+	return s.GetPetStats(ctx, userID, petID)
+}
+
+// GetPetStats 宠物的粉丝数及当前用户是否已关注
+func (s *followService) GetPetStats(ctx context.Context, userID, petID uint) (*model.PetFollowStats, error) {
+	if _, err := s.petRepo.GetByID(ctx, petID); err != nil {
+		return nil, checkNotFound(err, "宠物不存在")
+	}
+	key := petStatsKey(petID)
+	stats, ok := s.cachedStats(ctx, key)
+	if !ok {
+		followers, err := s.followRepo.CountPetFollowers(ctx, petID)
+		if err != nil {
+			return nil, err
+		}
+		stats = map[string]int64{"followers": followers}
+		s.cacheStats(ctx, key, stats)
+	}
+	following, err := s.followRepo.IsFollowingPet(ctx, userID, petID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PetFollowStats{PetID: petID, Followers: stats["followers"], IsFollowing: following}, nil
+}
+
+// ListPetFollowers 宠物的粉丝
+func (s *followService) ListPetFollowers(ctx context.Context, viewerID, petID uint, req *model.CursorRequest) (*model.PetFollowPage, error) {
+	if _, err := s.petRepo.GetByID(ctx, petID); err != nil {
+		return nil, checkNotFound(err, "宠物不存在")
+	}
+	limit := cursorLimit(req.Limit)
+	follows, err := s.followRepo.ListPetFollowers(ctx, petID, viewerID, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return petFollowPage(follows, limit), nil
+}
+
+// ListFollowedPets 当前用户关注的宠物
+func (s *followService) ListFollowedPets(ctx context.Context, userID uint, req *model.CursorRequest) (*model.PetFollowPage, error) {
+	limit := cursorLimit(req.Limit)
+	follows, err := s.followRepo.ListFollowedPets(ctx, userID, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return petFollowPage(follows, limit), nil
+}
+
+// BlockUser 拉黑用户,同时解除双方的关注关系
+func (s *followService) BlockUser(ctx context.Context, userID, targetID uint) error {
+	if err := s.checkTarget(ctx, userID, targetID); err != nil {
+		return err
+	}
+	if err := s.followRepo.Block(ctx, userID, targetID); err != nil {
+		return err
+	}
+	// 关注关系可能已被删除,计数缓存失效后重新统计
+	_ = redis.Del(ctx, userStatsKey(userID), userStatsKey(targetID))
+	return nil
+}
+
+// UnblockUser 取消拉黑,不恢复之前的关注关系
+func (s *followService) UnblockUser(ctx context.Context, userID, targetID uint) error {
+	_, err := s.followRepo.Unblock(ctx, userID, targetID)
+	return err
+}
+
+// ListBlocks 当前用户的黑名单
+func (s *followService) ListBlocks(ctx context.Context, userID uint, req *model.CursorRequest) (*model.UserBlockPage, error) {
+	limit := cursorLimit(req.Limit)
+	blocks, err := s.followRepo.ListBlocks(ctx, userID, req.Cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	page := &model.UserBlockPage{List: blocks}
+	if len(blocks) == limit {
+		page.NextCursor = blocks[len(blocks)-1].ID
+	}
+	return page, nil
+}
+
+// checkTarget 校验关注或拉黑的目标用户
+func (s *followService) checkTarget(ctx context.Context, userID, targetID uint) error {
+	if userID == targetID {
+		return errors.New("不能对自己执行该操作")
+	}
+	if _, err := s.userRepo.GetByID(ctx, targetID); err != nil {
+		return checkNotFound(err, "用户不存在")
+	}
+	return nil
+}
+
+// checkVisible 与目标用户互相拉黑时不能查看其关注列表
+func (s *followService) checkVisible(ctx context.Context, viewerID, userID uint) error {
+	if viewerID == userID {
+		return nil
+	}
+	blocked, err := s.followRepo.BlockedEither(ctx, viewerID, userID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return notFound("用户不存在")
+	}
+	return nil
+}
+
+// userStats 用户关注计数,优先读取Redis缓存
+func (s *followService) userStats(ctx context.Context, userID uint) (map[string]int64, error) {
+	key := userStatsKey(userID)
+	if stats, ok := s.cachedStats(ctx, key); ok {
+		return stats, nil
+	}
+	followers, following, err := s.followRepo.CountUserFollows(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]int64{"followers": followers, "following": following}
+	s.cacheStats(ctx, key, stats)
+	return stats, nil
+}
+
+func (s *followService) cachedStats(ctx context.Context, key string) (map[string]int64, bool) {
+	values, err := redis.HGetAll(ctx, key)
+	if err != nil || len(values) == 0 {
+		return nil, false
+	}
+	stats := make(map[string]int64, len(values))
+	for field, value := range values {
+		n, _ := strconv.ParseInt(value, 10, 64)
+		stats[field] = max(n, 0)
+	}
+	return stats, true
+}
+
+func (s *followService) cacheStats(ctx context.Context, key string, stats map[string]int64) {
+	values := make(map[string]interface{}, len(stats))
+	for field, value := range stats {
+		values[field] = value
+	}
+	_ = redis.HSetAllWithExpire(ctx, key, values, followStatsTTL)
+}
+
+// bumpStats 增量更新已缓存的计数,未缓存时下次读取重新统计
+func (s *followService) bumpStats(ctx context.Context, key, field string, delta int64) {
+	_, _ = redis.HIncrByIfExists(ctx, key, field, delta)
+}
+
+// cursorLimit 游标分页的每页数量,非法时使用默认值
+func cursorLimit(limit int) int {
+	if limit < 1 || limit > 100 {
+		return 20
+	}
+	return limit
+}
+
+func userFollowPage(follows []*model.UserFollow, limit int) *model.UserFollowPage {
+	page := &model.UserFollowPage{List: follows}
+	if len(follows) == limit {
+		page.NextCursor = follows[len(follows)-1].ID
+	}
+	return page
+}
+
+func petFollowPage(follows []*model.PetFollow, limit int) *model.PetFollowPage {
+	page := &model.PetFollowPage{List: follows}
+	if len(follows) == limit {
+		page.NextCursor = follows[len(follows)-1].ID
+	}
+	return page
+}
+
+func userStatsKey(userID uint) string {
+	return fmt.Sprintf("follow:user:%d", userID)
+}
+
+func petStatsKey(petID uint) string {
+	return fmt.Sprintf("follow:pet:%d", petID)
+}
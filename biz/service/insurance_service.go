@@ -0,0 +1,445 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+)
+
+// InsuranceService 宠物保险服务接口
+type InsuranceService interface {
+	CreatePolicy(ctx context.Context, userID, petID uint, req *model.SaveInsurancePolicyRequest) (*model.InsurancePolicy, error)
+	GetPolicy(ctx context.Context, userID, id uint) (*model.InsurancePolicy, error)
+	UpdatePolicy(ctx context.Context, userID, id uint, req *model.SaveInsurancePolicyRequest) (*model.InsurancePolicy, error)
+	DeletePolicy(ctx context.Context, userID, id uint) error
+	ListPolicies(ctx context.Context, userID, petID uint) ([]*model.InsurancePolicy, error)
+
+	CreateClaim(ctx context.Context, userID, policyID uint, req *model.SaveInsuranceClaimRequest) (*model.InsuranceClaim, error)
+	GetClaim(ctx context.Context, userID, id uint) (*model.InsuranceClaim, error)
+	UpdateClaim(ctx context.Context, userID, id uint, req *model.SaveInsuranceClaimRequest) (*model.InsuranceClaim, error)
+	DeleteClaim(ctx context.Context, userID, id uint) error
+	ListClaims(ctx context.Context, userID, petID uint, req *model.ListInsuranceClaimRequest) ([]*model.InsuranceClaim, int64, error)
+	UpdateClaimStatus(ctx context.Context, userID, id uint, req *model.UpdateClaimStatusRequest) (*model.InsuranceClaim, error)
+	GetSummary(ctx context.Context, userID, petID uint, year int) (*model.InsuranceSummary, error)
+}
+
+// insuranceService 宠物保险服务实现
+type insuranceService struct {
+	insuranceRepo repository.InsuranceRepository
+	petService    PetService
+	recordService MedicalRecordService
+	fileService   FileService
+}
+
+// NewInsuranceService 创建宠物保险服务
+func NewInsuranceService(insuranceRepo repository.InsuranceRepository, petService PetService, recordService MedicalRecordService, fileService FileService) InsuranceService {
+	return &insuranceService{
+		insuranceRepo: insuranceRepo,
+		petService:    petService,
+		recordService: recordService,
+		fileService:   fileService,
+	}
+}
+
+// CreatePolicy 添加保单,需要宠物编辑权限
+func (s *insuranceService) CreatePolicy(ctx context.Context, userID, petID uint, req *model.SaveInsurancePolicyRequest) (*model.InsurancePolicy, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+
+	policy := &model.InsurancePolicy{PetID: petID, CreatedBy: userID}
+	if err := s.applyPolicy(ctx, policy, req); err != nil {
+		return nil, err
+	}
+	if err := s.insuranceRepo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	fillPolicyActive(policy)
+	return policy, nil
+}
+
+// GetPolicy 获取保单详情
+func (s *insuranceService) GetPolicy(ctx context.Context, userID, id uint) (*model.InsurancePolicy, error) {
+	policy, err := s.authorizePolicy(ctx, userID, id, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	fillPolicyActive(policy)
+	return policy, nil
+}
+
+// UpdatePolicy 修改保单,已有理赔的治疗日期须仍在有效期内由用户自行保证
+func (s *insuranceService) UpdatePolicy(ctx context.Context, userID, id uint, req *model.SaveInsurancePolicyRequest) (*model.InsurancePolicy, error) {
+	policy, err := s.authorizePolicy(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyPolicy(ctx, policy, req); err != nil {
+		return nil, err
+	}
+	if err := s.insuranceRepo.UpdatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	fillPolicyActive(policy)
+	return policy, nil
+}
+
+// DeletePolicy 删除保单,存在理赔记录时不允许删除
+func (s *insuranceService) DeletePolicy(ctx context.Context, userID, id uint) error {
+	if _, err := s.authorizePolicy(ctx, userID, id, model.PetRoleEditor); err != nil {
+		return err
+	}
+	count, err := s.insuranceRepo.CountActiveClaims(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("保单下存在理赔记录,无法删除")
+	}
+	return s.insuranceRepo.DeletePolicy(ctx, id)
+}
+
+// ListPolicies 宠物的保单列表
+func (s *insuranceService) ListPolicies(ctx context.Context, userID, petID uint) ([]*model.InsurancePolicy, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	policies, err := s.insuranceRepo.ListPolicies(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range policies {
+		fillPolicyActive(policy)
+	}
+	return policies, nil
+}
+
+// CreateClaim 在保单下创建理赔草稿
+func (s *insuranceService) CreateClaim(ctx context.Context, userID, policyID uint, req *model.SaveInsuranceClaimRequest) (*model.InsuranceClaim, error) {
+	policy, err := s.authorizePolicy(ctx, userID, policyID, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &model.InsuranceClaim{
+		PolicyID:  policy.ID,
+		PetID:     policy.PetID,
+		CreatedBy: userID,
+		Status:    model.ClaimStatusDraft,
+	}
+	if err := s.applyClaim(ctx, userID, policy, claim, req); err != nil {
+		return nil, err
+	}
+	if err := s.insuranceRepo.CreateClaim(ctx, claim); err != nil {
+		return nil, err
+	}
+	claim.Policy = policy
+	s.fillAttachments(claim)
+	return claim, nil
+}
+
+// GetClaim 获取理赔详情及状态历史
+func (s *insuranceService) GetClaim(ctx context.Context, userID, id uint) (*model.InsuranceClaim, error) {
+	claim, err := s.authorizeClaim(ctx, userID, id, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.insuranceRepo.ListClaimEvents(ctx, claim.ID)
+	if err != nil {
+		return nil, err
+	}
+	claim.Events = events
+	s.fillAttachments(claim)
+	return claim, nil
+}
+
+// UpdateClaim 修改理赔草稿
+func (s *insuranceService) UpdateClaim(ctx context.Context, userID, id uint, req *model.SaveInsuranceClaimRequest) (*model.InsuranceClaim, error) {
+	claim, err := s.authorizeClaim(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != model.ClaimStatusDraft {
+		return nil, errors.New("仅草稿状态的理赔可以修改")
+	}
+	if err := s.applyClaim(ctx, userID, claim.Policy, claim, req); err != nil {
+		return nil, err
+	}
+	if err := s.insuranceRepo.UpdateClaim(ctx, claim); err != nil {
+		return nil, err
+	}
+	s.fillAttachments(claim)
+	return claim, nil
+}
+
+// DeleteClaim 删除理赔草稿
+func (s *insuranceService) DeleteClaim(ctx context.Context, userID, id uint) error {
+	claim, err := s.authorizeClaim(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return err
+	}
+	if claim.Status != model.ClaimStatusDraft {
+		return errors.New("仅草稿状态的理赔可以删除,已提交的理赔请撤回")
+	}
+	return s.insuranceRepo.DeleteClaim(ctx, id)
+}
+
+// ListClaims 宠物的理赔列表
+func (s *insuranceService) ListClaims(ctx context.Context, userID, petID uint, req *model.ListInsuranceClaimRequest) ([]*model.InsuranceClaim, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	claims, total, err := s.insuranceRepo.ListClaims(ctx, petID, req, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, claim := range claims {
+		s.fillAttachments(claim)
+	}
+	return claims, total, nil
+}
+
+// UpdateClaimStatus 按状态流转变更理赔状态
+// 核准时amount为核准金额且不超过申请金额;赔付时amount为实际赔付金额,默认等于核准金额;拒赔须填写原因
+func (s *insuranceService) UpdateClaimStatus(ctx context.Context, userID, id uint, req *model.UpdateClaimStatusRequest) (*model.InsuranceClaim, error) {
+	claim, err := s.authorizeClaim(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	from := claim.Status
+	if !model.CanTransitClaim(from, req.Status) {
+		return nil, errors.New("当前状态不允许变更为" + req.Status)
+	}
+	if req.Amount < 0 {
+		return nil, errors.New("金额不能为负数")
+	}
+
+	now := time.Now()
+	note := truncate(strings.TrimSpace(req.Note), 500)
+	event := &model.InsuranceClaimEvent{
+		ClaimID:    claim.ID,
+		UserID:     userID,
+		FromStatus: from,
+		ToStatus:   req.Status,
+		Note:       note,
+	}
+
+	switch req.Status {
+	case model.ClaimStatusSubmitted:
+		claim.SubmittedAt = &now
+	case model.ClaimStatusApproved:
+		amount := req.Amount
+		if amount == 0 {
+			amount = claim.ClaimedAmount
+		}
+		if amount > claim.ClaimedAmount {
+			return nil, errors.New("核准金额不能超过申请金额")
+		}
+		claim.ApprovedAmount = amount
+		claim.DecidedAt = &now
+		event.Amount = amount
+	case model.ClaimStatusDenied:
+		if note == "" {
+			return nil, errors.New("请填写拒赔原因")
+		}
+		claim.DecidedAt = &now
+	case model.ClaimStatusPaid:
+		amount := req.Amount
+		if amount == 0 {
+			amount = claim.ApprovedAmount
+		}
+		if amount > claim.ApprovedAmount {
+			return nil, errors.New("赔付金额不能超过核准金额")
+		}
+		claim.PaidAmount = amount
+		claim.PaidAt = &now
+		event.Amount = amount
+	}
+	claim.Status = req.Status
+	claim.StatusNote = note
+
+	if err := s.insuranceRepo.ChangeClaimStatus(ctx, claim, from, event); err != nil {
+		return nil, err
+	}
+	return s.GetClaim(ctx, userID, id)
+}
+
+// GetSummary 按治疗年份汇总已赔付与待赔付金额,并细分到保单
+func (s *insuranceService) GetSummary(ctx context.Context, userID, petID uint, year int) (*model.InsuranceSummary, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	rows, err := s.insuranceRepo.SumClaims(ctx, petID, year)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.insuranceRepo.ListPolicies(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	policyMap := make(map[uint]*model.InsurancePolicy, len(policies))
+	for _, policy := range policies {
+		policyMap[policy.ID] = policy
+	}
+
+	summary := &model.InsuranceSummary{PetID: petID, Years: []*model.YearClaimSummary{}}
+	var current *model.YearClaimSummary
+	byPolicy := make(map[uint]*model.PolicyClaimSummary)
+	// rows已按年份倒序、保单正序排列
+	for _, row := range rows {
+		if current == nil || current.Year != row.Year {
+			current = &model.YearClaimSummary{Year: row.Year, Policies: []*model.PolicyClaimSummary{}}
+			summary.Years = append(summary.Years, current)
+			byPolicy = make(map[uint]*model.PolicyClaimSummary)
+		}
+		item, ok := byPolicy[row.PolicyID]
+		if !ok {
+			item = &model.PolicyClaimSummary{PolicyID: row.PolicyID}
+			if policy := policyMap[row.PolicyID]; policy != nil {
+				item.Provider = policy.Provider
+				item.PolicyNumber = policy.PolicyNumber
+			}
+			byPolicy[row.PolicyID] = item
+			current.Policies = append(current.Policies, item)
+		}
+		item.Add(row)
+		current.Add(row)
+		summary.Total.Add(row)
+	}
+	return summary, nil
+}
+
+// authorizePolicy 获取保单并校验当前用户对所属宠物的角色
+func (s *insuranceService) authorizePolicy(ctx context.Context, userID, id uint, required string) (*model.InsurancePolicy, error) {
+	policy, err := s.insuranceRepo.GetPolicy(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "保单不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, policy.PetID, required); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// authorizeClaim 获取理赔并校验当前用户对所属宠物的角色
+func (s *insuranceService) authorizeClaim(ctx context.Context, userID, id uint, required string) (*model.InsuranceClaim, error) {
+	claim, err := s.insuranceRepo.GetClaim(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "理赔不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, claim.PetID, required); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// applyPolicy 校验保单请求并写入保单
+func (s *insuranceService) applyPolicy(ctx context.Context, policy *model.InsurancePolicy, req *model.SaveInsurancePolicyRequest) error {
+	provider := strings.TrimSpace(req.Provider)
+	number := strings.TrimSpace(req.PolicyNumber)
+	if provider == "" || number == "" {
+		return errors.New("保险公司和保单号不能为空")
+	}
+	if req.CoverageLimit < 0 || req.Deductible < 0 {
+		return errors.New("保额和免赔额不能为负数")
+	}
+	if req.ReimbursementRate < 0 || req.ReimbursementRate > 100 {
+		return errors.New("赔付比例须在0到100之间")
+	}
+	start, err := parseDate(req.StartDate)
+	if err != nil {
+		return err
+	}
+	end, err := parseDate(req.EndDate)
+	if err != nil {
+		return err
+	}
+	if end.Before(start) {
+		return errors.New("到期日期不能早于生效日期")
+	}
+	exists, err := s.insuranceRepo.ExistsPolicyNumber(ctx, policy.PetID, number, policy.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("该宠物已存在相同保单号")
+	}
+
+	policy.Provider = provider
+	policy.PolicyNumber = number
+	policy.Coverage = strings.TrimSpace(req.Coverage)
+	policy.CoverageLimit = req.CoverageLimit
+	policy.Deductible = req.Deductible
+	policy.ReimbursementRate = req.ReimbursementRate
+	policy.StartDate = start
+	policy.EndDate = end
+	policy.Notes = req.Notes
+	return nil
+}
+
+// applyClaim 校验理赔请求并写入理赔,关联就诊记录时未填写的日期和发票号取自就诊记录
+func (s *insuranceService) applyClaim(ctx context.Context, userID uint, policy *model.InsurancePolicy, claim *model.InsuranceClaim, req *model.SaveInsuranceClaimRequest) error {
+	if req.ClaimedAmount <= 0 {
+		return errors.New("申请金额必须大于0")
+	}
+
+	invoiceNo := strings.TrimSpace(req.InvoiceNo)
+	var treatmentDate time.Time
+	if req.TreatmentDate != "" {
+		d, err := parseDate(req.TreatmentDate)
+		if err != nil {
+			return err
+		}
+		treatmentDate = d
+	}
+	if req.MedicalRecordID > 0 {
+		record, err := s.recordService.Authorize(ctx, userID, req.MedicalRecordID, model.PetRoleViewer)
+		if err != nil {
+			return err
+		}
+		if record.PetID != policy.PetID {
+			return errors.New("就诊记录不属于该宠物")
+		}
+		if treatmentDate.IsZero() {
+			treatmentDate = record.VisitDate
+		}
+		if invoiceNo == "" {
+			invoiceNo = record.InvoiceNo
+		}
+	}
+	if treatmentDate.IsZero() {
+		return errors.New("请填写治疗日期或关联就诊记录")
+	}
+	if !policy.Covers(treatmentDate) {
+		return errors.New("治疗日期不在保单有效期内")
+	}
+	attachments, err := checkAttachments(ctx, s.fileService, userID, req.AttachmentFileIDs, claim.AttachmentFileIDs)
+	if err != nil {
+		return err
+	}
+
+	claim.MedicalRecordID = req.MedicalRecordID
+	claim.InvoiceNo = invoiceNo
+	claim.TreatmentDate = treatmentDate
+	claim.Description = strings.TrimSpace(req.Description)
+	claim.ClaimedAmount = req.ClaimedAmount
+	claim.AttachmentFileIDs = attachments
+	return nil
+}
+
+func (s *insuranceService) fillAttachments(claim *model.InsuranceClaim) {
+	claim.Attachments = attachmentURLs(s.fileService, claim.AttachmentFileIDs)
+	if claim.Policy != nil {
+		fillPolicyActive(claim.Policy)
+	}
+}
+
+// fillPolicyActive 计算保单当前是否在有效期内
+func fillPolicyActive(policy *model.InsurancePolicy) {
+	policy.Active = policy.Covers(today())
+}
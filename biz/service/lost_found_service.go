@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/geo"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/redis"
+)
+
+const (
+	// defaultNearbyRadiusKm 附近查询默认半径
+	defaultNearbyRadiusKm = 5.0
+	// maxNearbyRadiusKm 附近查询最大半径
+	maxNearbyRadiusKm = 100.0
+	// defaultNearbyLimit 附近查询默认返回数量
+	defaultNearbyLimit = 20
+	// matchRadiusKm 疑似匹配的最大距离
+	matchRadiusKm = 10.0
+	// matchWindow 疑似匹配的最大时间跨度
+	matchWindow = 30 * 24 * time.Hour
+	// matchMinScore 疑似匹配的最低得分
+	matchMinScore = 0.3
+	// matchLimit 疑似匹配最多返回数量
+	matchLimit = 10
+	// geoRetryInterval 地理索引写入失败后的重试间隔
+	geoRetryInterval = time.Minute
+	// geoRebuildBatch 重建地理索引时每批读取的记录数
+	geoRebuildBatch = 500
+)
+
+// LostFoundService 走失/招领服务接口
+type LostFoundService interface {
+	CreateReport(ctx context.Context, userID uint, req *model.CreateReportRequest) (*model.LostFoundReport, []*model.ReportMatch, error)
+	GetReport(ctx context.Context, reportID uint) (*model.LostFoundReport, error)
+	ResolveReport(ctx context.Context, userID, reportID uint) error
+	ListMyReports(ctx context.Context, userID uint, req *model.ListReportRequest) ([]*model.LostFoundReport, int64, error)
+	SearchNearby(ctx context.Context, req *model.NearbyReportRequest) ([]*model.NearbyReport, error)
+	SuggestMatches(ctx context.Context, reportID uint) ([]*model.ReportMatch, error)
+
+	// RunGeoIndexer 启动时用数据库重建地理索引,之后定时重试写入失败的记录
+	RunGeoIndexer(ctx context.Context)
+}
+
+// lostFoundService 走失/招领服务实现
+type lostFoundService struct {
+	reportRepo repository.LostFoundRepository
+	petService PetService
+
+	// geoPending 写入地理索引失败、等待重试的记录,由geoMu保护
+	geoMu      sync.Mutex
+	geoPending map[uint]*model.LostFoundReport
+}
+
+// NewLostFoundService 创建走失/招领服务
+func NewLostFoundService(reportRepo repository.LostFoundRepository, petService PetService) LostFoundService {
+	return &lostFoundService{
+		reportRepo: reportRepo,
+		petService: petService,
+		geoPending: make(map[uint]*model.LostFoundReport),
+	}
+}
+
+// CreateReport 发布走失/招领信息,写入地理索引并返回疑似匹配
+func (s *lostFoundService) CreateReport(ctx context.Context, userID uint, req *model.CreateReportRequest) (*model.LostFoundReport, []*model.ReportMatch, error) {
+	if !geo.ValidIndexCoordinate(req.Latitude, req.Longitude) {
+		return nil, nil, errors.New("经纬度不合法")
+	}
+	if req.OccurredAt.After(time.Now().Add(time.Hour)) {
+		return nil, nil, errors.New("走失/发现时间不能晚于当前时间")
+	}
+
+	if req.PetID != 0 {
+		if _, err := s.petService.Authorize(ctx, userID, req.PetID, model.PetRoleEditor); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	report := &model.LostFoundReport{
+		UserID:       userID,
+		PetID:        req.PetID,
+		Type:         req.Type,
+		Species:      req.Species,
+		Breed:        req.Breed,
+		Color:        req.Color,
+		Photos:       req.Photos,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Address:      req.Address,
+		OccurredAt:   req.OccurredAt,
+		Description:  req.Description,
+		ContactPhone: req.ContactPhone,
+		Status:       model.ReportStatusOpen,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, nil, err
+	}
+
+	// 地理索引写入失败不影响发布,由后台任务重试
+	if err := indexReport(ctx, report); err != nil {
+		logger.Warn(ctx, "走失/招领信息写入地理索引失败,稍后重试", logger.Int("id", int(report.ID)), logger.ErrorField(err))
+		s.geoMu.Lock()
+		s.geoPending[report.ID] = report
+		s.geoMu.Unlock()
+	}
+
+	matches, err := s.findMatches(ctx, report)
+	if err != nil {
+		logger.Warn(ctx, "计算疑似匹配失败", logger.Int("id", int(report.ID)), logger.ErrorField(err))
+	}
+	return report, matches, nil
+}
+
+// GetReport 获取走失/招领信息详情
+func (s *lostFoundService) GetReport(ctx context.Context, reportID uint) (*model.LostFoundReport, error) {
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, checkNotFound(err, "走失/招领信息不存在")
+	}
+	return report, nil
+}
+
+// ResolveReport 标记为已解决并移出地理索引
+func (s *lostFoundService) ResolveReport(ctx context.Context, userID, reportID uint) error {
+	report, err := s.GetReport(ctx, reportID)
+	if err != nil {
+		return err
+	}
+	if report.UserID != userID {
+		return forbidden("只能操作自己发布的信息")
+	}
+	if report.Status == model.ReportStatusResolved {
+		return nil
+	}
+
+	if err := s.reportRepo.UpdateStatus(ctx, reportID, model.ReportStatusResolved); err != nil {
+		return err
+	}
+	s.geoMu.Lock()
+	delete(s.geoPending, reportID)
+	s.geoMu.Unlock()
+	// 移出索引失败时残留的记录会在附近查询回表时按状态过滤
+	_ = redis.GeoRemove(ctx, reportGeoKey(report.Type), strconv.FormatUint(uint64(reportID), 10))
+
+	logger.Info(ctx, "走失/招领信息已解决", logger.Int("id", int(reportID)))
+	return nil
+}
+
+// ListMyReports 获取当前用户发布的走失/招领信息
+func (s *lostFoundService) ListMyReports(ctx context.Context, userID uint, req *model.ListReportRequest) ([]*model.LostFoundReport, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.reportRepo.ListByUser(ctx, userID, req.Status, offset, limit)
+}
+
+// SearchNearby 查询某点半径范围内的走失/招领信息,按距离由近到远排序
+func (s *lostFoundService) SearchNearby(ctx context.Context, req *model.NearbyReportRequest) ([]*model.NearbyReport, error) {
+	if !geo.ValidCoordinate(req.Latitude, req.Longitude) {
+		return nil, errors.New("经纬度不合法")
+	}
+	radius := req.RadiusKm
+	if radius <= 0 {
+		radius = defaultNearbyRadiusKm
+	}
+	if radius > maxNearbyRadiusKm {
+		radius = maxNearbyRadiusKm
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultNearbyLimit
+	}
+
+	types := []string{model.ReportTypeLost, model.ReportTypeFound}
+	if req.Type != "" {
+		types = []string{req.Type}
+	}
+
+	var result []*model.NearbyReport
+	for _, t := range types {
+		reports, err := s.searchNearbyByType(ctx, t, req.Species, req.Latitude, req.Longitude, radius, limit)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, reports...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DistanceKm < result[j].DistanceKm
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// SuggestMatches 为走失/招领信息推荐疑似匹配
+func (s *lostFoundService) SuggestMatches(ctx context.Context, reportID uint) ([]*model.ReportMatch, error) {
+	report, err := s.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.Status != model.ReportStatusOpen {
+		return []*model.ReportMatch{}, nil
+	}
+	return s.findMatches(ctx, report)
+}
+
+// RunGeoIndexer 启动时用数据库中未解决的记录重建地理索引,Redis暂不可用时在下一轮继续重建;
+// 之后定时重试发布时写入失败的记录
+func (s *lostFoundService) RunGeoIndexer(ctx context.Context) {
+	ticker := time.NewTicker(geoRetryInterval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "走失/招领地理索引任务已启动")
+	rebuilt := false
+	for {
+		if !rebuilt {
+			rebuilt = s.rebuildGeoIndex(ctx)
+		} else {
+			s.retryGeoIndex(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "走失/招领地理索引任务已停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rebuildGeoIndex 将全部未解决的记录写入地理索引,GEOADD对已存在的成员只更新坐标
+// 单条记录写入失败时记录日志并转入重试,全部失败时视为Redis不可用,下一轮重新重建
+func (s *lostFoundService) rebuildGeoIndex(ctx context.Context) bool {
+	var afterID uint
+	count := 0
+	var failed []*model.LostFoundReport
+	for {
+		reports, err := s.reportRepo.ListOpenAfter(ctx, afterID, geoRebuildBatch)
+		if err != nil {
+			return false
+		}
+		for _, report := range reports {
+			afterID = report.ID
+			if !geo.ValidIndexCoordinate(report.Latitude, report.Longitude) {
+				logger.Warn(ctx, "走失/招领信息坐标超出地理索引范围,跳过", logger.Int("id", int(report.ID)))
+				continue
+			}
+			count++
+			if err := indexReport(ctx, report); err != nil {
+				logger.Warn(ctx, "重建走失/招领地理索引失败,稍后重试", logger.Int("id", int(report.ID)), logger.ErrorField(err))
+				failed = append(failed, report)
+			}
+		}
+		if len(reports) < geoRebuildBatch {
+			break
+		}
+	}
+	if count > 0 && len(failed) == count {
+		return false
+	}
+
+	// 重建已覆盖此前写入失败的记录,本轮失败的留待重试
+	s.geoMu.Lock()
+	for id := range s.geoPending {
+		if id <= afterID {
+			delete(s.geoPending, id)
+		}
+	}
+	for _, report := range failed {
+		s.geoPending[report.ID] = report
+	}
+	s.geoMu.Unlock()
+	logger.Info(ctx, "走失/招领地理索引重建完成", logger.Int("count", count-len(failed)), logger.Int("failed", len(failed)))
+	return true
+}
+
+// retryGeoIndex 重试写入失败的记录,仍失败的留待下一轮,坐标无法索引的直接丢弃
+func (s *lostFoundService) retryGeoIndex(ctx context.Context) {
+	s.geoMu.Lock()
+	pending := make([]*model.LostFoundReport, 0, len(s.geoPending))
+	for _, report := range s.geoPending {
+		pending = append(pending, report)
+	}
+	s.geoMu.Unlock()
+
+	for _, report := range pending {
+		if geo.ValidIndexCoordinate(report.Latitude, report.Longitude) {
+			if err := indexReport(ctx, report); err != nil {
+				logger.Warn(ctx, "补写走失/招领地理索引失败,稍后重试", logger.Int("id", int(report.ID)), logger.ErrorField(err))
+				continue
+			}
+			logger.Info(ctx, "走失/招领信息补写地理索引成功", logger.Int("id", int(report.ID)))
+		} else {
+			logger.Warn(ctx, "走失/招领信息坐标超出地理索引范围,不再重试", logger.Int("id", int(report.ID)))
+		}
+		s.geoMu.Lock()
+		delete(s.geoPending, report.ID)
+		s.geoMu.Unlock()
+	}
+}
+
+// indexReport 将记录写入对应类型的地理索引
+func indexReport(ctx context.Context, report *model.LostFoundReport) error {
+	return redis.GeoAdd(ctx, reportGeoKey(report.Type), strconv.FormatUint(uint64(report.ID), 10), report.Longitude, report.Latitude)
+}
+
+// searchNearbyByType 优先使用Redis GEO查询,失败时降级为数据库矩形范围查询
+func (s *lostFoundService) searchNearbyByType(ctx context.Context, reportType, species string, lat, lng, radius float64, limit int) ([]*model.NearbyReport, error) {
+	// 物种过滤在取回记录后进行,因此多取一些候选
+	locations, err := redis.GeoSearchRadius(ctx, reportGeoKey(reportType), lng, lat, radius, limit*5)
+	if err != nil {
+		logger.Warn(ctx, "Redis附近查询失败,降级为数据库查询", logger.ErrorField(err))
+		return s.searchNearbyFromDB(ctx, reportType, species, lat, lng, radius, limit)
+	}
+
+	ids := make([]uint, 0, len(locations))
+	distances := make(map[uint]float64, len(locations))
+	for _, l := range locations {
+		id, err := strconv.ParseUint(l.Member, 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+		distances[uint(id)] = l.Dist
+	}
+
+	reports, err := s.reportRepo.GetOpenByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.NearbyReport, 0, len(reports))
+	for _, r := range reports {
+		if species != "" && !strings.EqualFold(r.Species, species) {
+			continue
+		}
+		result = append(result, &model.NearbyReport{LostFoundReport: r, DistanceKm: distances[r.ID]})
+	}
+	return result, nil
+}
+
+// searchNearbyFromDB 数据库降级查询:先按外接矩形粗筛,再按球面距离精确过滤
+func (s *lostFoundService) searchNearbyFromDB(ctx context.Context, reportType, species string, lat, lng, radius float64, limit int) ([]*model.NearbyReport, error) {
+	minLat, maxLat, minLng, maxLng := geo.BoundingBox(lat, lng, radius)
+	reports, err := s.reportRepo.ListOpenInBox(ctx, &repository.ReportBoxFilter{
+		Type:    reportType,
+		Species: species,
+		MinLat:  minLat,
+		MaxLat:  maxLat,
+		MinLng:  minLng,
+		MaxLng:  maxLng,
+		Limit:   limit * 5,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.NearbyReport, 0, len(reports))
+	for _, r := range reports {
+		d := geo.Distance(lat, lng, r.Latitude, r.Longitude)
+		if d > radius {
+			continue
+		}
+		result = append(result, &model.NearbyReport{LostFoundReport: r, DistanceKm: d})
+	}
+	return result, nil
+}
+
+// findMatches 在附近的相反类型信息中按物种、毛色、距离和时间打分
+func (s *lostFoundService) findMatches(ctx context.Context, report *model.LostFoundReport) ([]*model.ReportMatch, error) {
+	candidates, err := s.searchNearbyByType(ctx, report.OppositeType(), report.Species,
+		report.Latitude, report.Longitude, matchRadiusKm, matchLimit*5)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*model.ReportMatch, 0)
+	for _, c := range candidates {
+		if c.UserID == report.UserID {
+			continue
+		}
+		if match := scoreMatch(report, c); match != nil {
+			matches = append(matches, match)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > matchLimit {
+		matches = matches[:matchLimit]
+	}
+	return matches, nil
+}
+
+// scoreMatch 计算候选信息的匹配得分,不满足基本条件时返回nil
+func scoreMatch(report *model.LostFoundReport, candidate *model.NearbyReport) *model.ReportMatch {
+	if !strings.EqualFold(report.Species, candidate.Species) {
+		return nil
+	}
+
+	// 招领时间应不早于走失时间(允许1天误差),且两者间隔在匹配窗口内
+	lost, found := report, candidate.LostFoundReport
+	if report.Type == model.ReportTypeFound {
+		lost, found = candidate.LostFoundReport, report
+	}
+	gap := found.OccurredAt.Sub(lost.OccurredAt)
+	if gap < -24*time.Hour || gap > matchWindow {
+		return nil
+	}
+
+	match := &model.ReportMatch{
+		Report:     candidate.LostFoundReport,
+		DistanceKm: candidate.DistanceKm,
+		Reasons:    []string{"物种相同"},
+	}
+
+	proximity := math.Max(0, 1-candidate.DistanceKm/matchRadiusKm)
+	match.Score += 0.4 * proximity
+	match.Reasons = append(match.Reasons, fmt.Sprintf("相距%.1f公里", candidate.DistanceKm))
+
+	if score, reason := colorSimilarity(report.Color, candidate.Color); score > 0 {
+		match.Score += score
+		match.Reasons = append(match.Reasons, reason)
+	}
+
+	if report.Breed != "" && strings.EqualFold(report.Breed, candidate.Breed) {
+		match.Score += 0.1
+		match.Reasons = append(match.Reasons, "品种相同")
+	}
+
+	if gap < 0 {
+		gap = 0
+	}
+	match.Score += 0.1 * (1 - float64(gap)/float64(matchWindow))
+
+	if match.Score < matchMinScore {
+		return nil
+	}
+	match.Score = math.Round(match.Score*100) / 100
+	return match
+}
+
+// colorSimilarity 毛色相似度:完全相同0.4,互相包含0.2
+func colorSimilarity(a, b string) (float64, string) {
+	a, b = strings.ToLower(strings.TrimSpace(a)), strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return 0, ""
+	}
+	if a == b {
+		return 0.4, "毛色相同"
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return 0.2, "毛色相近"
+	}
+	return 0, ""
+}
+
+// reportGeoKey 走失/招领地理索引的Redis Key
+func reportGeoKey(reportType string) string {
+	return "lostfound:geo:" + reportType
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/config"
+	"pet-service/pkg/logger"
+)
+
+const (
+	// defaultMeasurementRange 未指定区间时默认查询最近90天
+	defaultMeasurementRange = 90 * 24 * time.Hour
+	// maxRawMeasurements 原始数据单次返回上限
+	maxRawMeasurements = 1000
+	// adultAgeMonths 成年月龄,未成年宠物不与品种体重范围比较
+	adultAgeMonths = 12
+)
+
+// MeasurementService 健康指标服务接口
+type MeasurementService interface {
+	Record(ctx context.Context, userID, petID uint, req *model.CreateMeasurementsRequest) ([]*model.PetMeasurement, []*model.MeasurementAlert, error)
+	Query(ctx context.Context, userID, petID uint, req *model.QueryMeasurementsRequest) (*model.MeasurementSeries, error)
+	Delete(ctx context.Context, userID, petID, measurementID uint) error
+	CheckAlerts(ctx context.Context, userID, petID uint, req *model.MeasurementAlertRequest) ([]*model.MeasurementAlert, error)
+	ListBreeds(ctx context.Context, req *model.ListBreedRequest) ([]*model.Breed, error)
+}
+
+// measurementService 健康指标服务实现
+type measurementService struct {
+	measurementRepo repository.MeasurementRepository
+	breedRepo       repository.BreedRepository
+	petService      PetService
+	cfg             *config.HealthConfig
+}
+
+// NewMeasurementService 创建健康指标服务
+func NewMeasurementService(measurementRepo repository.MeasurementRepository, breedRepo repository.BreedRepository, petService PetService, cfg *config.HealthConfig) MeasurementService {
+	return &measurementService{
+		measurementRepo: measurementRepo,
+		breedRepo:       breedRepo,
+		petService:      petService,
+		cfg:             cfg,
+	}
+}
+
+// Record 批量记录测量数据,包含体重时同时返回体重提醒
+func (s *measurementService) Record(ctx context.Context, userID, petID uint, req *model.CreateMeasurementsRequest) ([]*model.PetMeasurement, []*model.MeasurementAlert, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	hasWeight := false
+	items := make([]*model.PetMeasurement, 0, len(req.Items))
+	for i, item := range req.Items {
+		spec, ok := model.MetricSpecs[item.Metric]
+		if !ok {
+			return nil, nil, fmt.Errorf("第%d条数据指标类型不支持: %s", i+1, item.Metric)
+		}
+		if item.Value < spec.Min || item.Value > spec.Max {
+			return nil, nil, fmt.Errorf("第%d条数据超出合理范围(%g-%g%s)", i+1, spec.Min, spec.Max, spec.Unit)
+		}
+		if item.MeasuredAt.After(now.Add(5 * time.Minute)) {
+			return nil, nil, fmt.Errorf("第%d条数据测量时间不能晚于当前时间", i+1)
+		}
+		hasWeight = hasWeight || item.Metric == model.MetricWeight
+		items = append(items, &model.PetMeasurement{
+			PetID:      petID,
+			Metric:     item.Metric,
+			Value:      item.Value,
+			Unit:       spec.Unit,
+			MeasuredAt: item.MeasuredAt,
+			Note:       item.Note,
+			RecordedBy: userID,
+		})
+	}
+
+	if err := s.measurementRepo.BatchCreate(ctx, items); err != nil {
+		return nil, nil, err
+	}
+	logger.Info(ctx, "记录测量数据成功", logger.Int("pet_id", int(petID)), logger.Int("count", len(items)))
+
+	if !hasWeight {
+		return items, nil, nil
+	}
+	// 提醒计算失败不影响写入结果
+	alerts, err := s.weightAlerts(ctx, pet, s.cfg.WeightAlertWindowDays, float64(s.cfg.WeightAlertPercent))
+	if err != nil {
+		logger.Warn(ctx, "计算体重提醒失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+	}
+	return items, alerts, nil
+}
+
+// Query 按区间查询测量数据,支持按天/周降采样
+func (s *measurementService) Query(ctx context.Context, userID, petID uint, req *model.QueryMeasurementsRequest) (*model.MeasurementSeries, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	spec, ok := model.MetricSpecs[req.Metric]
+	if !ok {
+		return nil, fmt.Errorf("指标类型不支持: %s", req.Metric)
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.Add(-defaultMeasurementRange)
+	if req.From != nil {
+		from = *req.From
+	}
+	if !from.Before(to) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+
+	interval := req.Interval
+	switch interval {
+	case "":
+		interval = model.IntervalRaw
+	case model.IntervalRaw, model.IntervalDay, model.IntervalWeek:
+	default:
+		return nil, invalidParam(fmt.Sprintf("聚合粒度不支持: %s", interval))
+	}
+	series := &model.MeasurementSeries{
+		Metric:   req.Metric,
+		Unit:     spec.Unit,
+		Interval: interval,
+		From:     from,
+		To:       to,
+	}
+
+	var err error
+	if interval == model.IntervalRaw {
+		series.Items, err = s.measurementRepo.ListRange(ctx, petID, req.Metric, from, to, maxRawMeasurements)
+	} else {
+		series.Buckets, err = s.measurementRepo.Aggregate(ctx, petID, req.Metric, from, to, interval)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// Delete 删除测量数据
+func (s *measurementService) Delete(ctx context.Context, userID, petID, measurementID uint) error {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return err
+	}
+	return checkNotFound(s.measurementRepo.Delete(ctx, petID, measurementID), "测量数据不存在")
+}
+
+// CheckAlerts 检查体重变化及与品种标准的偏离
+func (s *measurementService) CheckAlerts(ctx context.Context, userID, petID uint, req *model.MeasurementAlertRequest) ([]*model.MeasurementAlert, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	windowDays := req.WindowDays
+	if windowDays == 0 {
+		windowDays = s.cfg.WeightAlertWindowDays
+	}
+	thresholdPct := req.ThresholdPct
+	if thresholdPct == 0 {
+		thresholdPct = float64(s.cfg.WeightAlertPercent)
+	}
+	return s.weightAlerts(ctx, pet, windowDays, thresholdPct)
+}
+
+// ListBreeds 查询品种目录
+func (s *measurementService) ListBreeds(ctx context.Context, req *model.ListBreedRequest) ([]*model.Breed, error) {
+	return s.breedRepo.List(ctx, req.Species, req.Keyword)
+}
+
+// weightAlerts 计算窗口期内的体重变化提醒,成年宠物额外比较品种体重范围
+func (s *measurementService) weightAlerts(ctx context.Context, pet *model.Pet, windowDays int, thresholdPct float64) ([]*model.MeasurementAlert, error) {
+	alerts := make([]*model.MeasurementAlert, 0)
+
+	// 最新体重单独查询,窗口期内记录较多时不受条数上限影响
+	latest, err := s.measurementRepo.Latest(ctx, pet.ID, model.MetricWeight)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return alerts, nil
+		}
+		return nil, err
+	}
+	now := time.Now()
+	first, err := s.measurementRepo.EarliestSince(ctx, pet.ID, model.MetricWeight, now.AddDate(0, 0, -windowDays))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if first != nil && first.ID != latest.ID {
+		changePct := (latest.Value - first.Value) / first.Value * 100
+		if math.Abs(changePct) >= thresholdPct {
+			alert := &model.MeasurementAlert{Type: model.AlertWeightGain, Metric: model.MetricWeight, Value: round2(changePct)}
+			verb := "增加"
+			if changePct < 0 {
+				alert.Type, verb = model.AlertWeightLoss, "下降"
+			}
+			alert.Message = fmt.Sprintf("近%d天体重%s%.1f%%(%.2fkg → %.2fkg),超过%.0f%%的提醒阈值",
+				windowDays, verb, math.Abs(changePct), first.Value, latest.Value, thresholdPct)
+			alerts = append(alerts, alert)
+		}
+	}
+
+	if pet.Breed == "" || (pet.BirthDate != nil && pet.BirthDate.AddDate(0, adultAgeMonths, 0).After(now)) {
+		return alerts, nil
+	}
+	breed, err := s.breedRepo.GetByName(ctx, pet.Species, pet.Breed)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return alerts, nil
+		}
+		return alerts, err
+	}
+
+	switch {
+	case breed.MaxWeightKg > 0 && latest.Value > breed.MaxWeightKg:
+		alerts = append(alerts, &model.MeasurementAlert{
+			Type:    model.AlertAboveBreedRange,
+			Metric:  model.MetricWeight,
+			Value:   latest.Value,
+			Message: fmt.Sprintf("当前体重%.2fkg高于%s成年标准(%.1f-%.1fkg)", latest.Value, breed.Name, breed.MinWeightKg, breed.MaxWeightKg),
+		})
+	case breed.MinWeightKg > 0 && latest.Value < breed.MinWeightKg:
+		alerts = append(alerts, &model.MeasurementAlert{
+			Type:    model.AlertBelowBreedRange,
+			Metric:  model.MetricWeight,
+			Value:   latest.Value,
+			Message: fmt.Sprintf("当前体重%.2fkg低于%s成年标准(%.1f-%.1fkg)", latest.Value, breed.Name, breed.MinWeightKg, breed.MaxWeightKg),
+		})
+	}
+	return alerts, nil
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+)
+
+// maxAttachments 单条记录的附件数量上限
+const maxAttachments = 20
+
+// MedicalRecordService 就诊记录服务接口
+type MedicalRecordService interface {
+	CreateRecord(ctx context.Context, userID, petID uint, req *model.SaveMedicalRecordRequest) (*model.MedicalRecord, error)
+	GetRecord(ctx context.Context, userID, id uint) (*model.MedicalRecord, error)
+	UpdateRecord(ctx context.Context, userID, id uint, req *model.SaveMedicalRecordRequest) (*model.MedicalRecord, error)
+	DeleteRecord(ctx context.Context, userID, id uint) error
+	ListRecords(ctx context.Context, userID, petID uint, req *model.ListMedicalRecordRequest) ([]*model.MedicalRecord, int64, error)
+	// Authorize 获取就诊记录并校验当前用户对所属宠物的角色
+	Authorize(ctx context.Context, userID, id uint, required string) (*model.MedicalRecord, error)
+}
+
+// medicalRecordService 就诊记录服务实现
+type medicalRecordService struct {
+	recordRepo  repository.MedicalRecordRepository
+	petService  PetService
+	fileService FileService
+}
+
+// NewMedicalRecordService 创建就诊记录服务
+func NewMedicalRecordService(recordRepo repository.MedicalRecordRepository, petService PetService, fileService FileService) MedicalRecordService {
+	return &medicalRecordService{
+		recordRepo:  recordRepo,
+		petService:  petService,
+		fileService: fileService,
+	}
+}
+
+// CreateRecord 记录一次就诊,需要宠物编辑权限
+func (s *medicalRecordService) CreateRecord(ctx context.Context, userID, petID uint, req *model.SaveMedicalRecordRequest) (*model.MedicalRecord, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+
+	record := &model.MedicalRecord{PetID: petID, RecordedBy: userID}
+	if err := s.apply(ctx, userID, record, req); err != nil {
+		return nil, err
+	}
+	if err := s.recordRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+	s.fillAttachments(record)
+	return record, nil
+}
+
+// GetRecord 获取就诊记录,宠物成员可见
+func (s *medicalRecordService) GetRecord(ctx context.Context, userID, id uint) (*model.MedicalRecord, error) {
+	record, err := s.Authorize(ctx, userID, id, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	s.fillAttachments(record)
+	return record, nil
+}
+
+// UpdateRecord 修改就诊记录
+func (s *medicalRecordService) UpdateRecord(ctx context.Context, userID, id uint, req *model.SaveMedicalRecordRequest) (*model.MedicalRecord, error) {
+	record, err := s.Authorize(ctx, userID, id, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(ctx, userID, record, req); err != nil {
+		return nil, err
+	}
+	if err := s.recordRepo.Update(ctx, record); err != nil {
+		return nil, err
+	}
+	s.fillAttachments(record)
+	return record, nil
+}
+
+// DeleteRecord 删除就诊记录
+func (s *medicalRecordService) DeleteRecord(ctx context.Context, userID, id uint) error {
+	if _, err := s.Authorize(ctx, userID, id, model.PetRoleEditor); err != nil {
+		return err
+	}
+	return s.recordRepo.Delete(ctx, id)
+}
+
+// ListRecords 宠物的就诊记录,可按类型和日期区间筛选
+func (s *medicalRecordService) ListRecords(ctx context.Context, userID, petID uint, req *model.ListMedicalRecordRequest) ([]*model.MedicalRecord, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+
+	var from, to *time.Time
+	if req.From != "" {
+		d, err := parseDate(req.From)
+		if err != nil {
+			return nil, 0, err
+		}
+		from = &d
+	}
+	if req.To != "" {
+		d, err := parseDate(req.To)
+		if err != nil {
+			return nil, 0, err
+		}
+		to = &d
+	}
+
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	records, total, err := s.recordRepo.List(ctx, petID, req.Type, from, to, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, record := range records {
+		s.fillAttachments(record)
+	}
+	return records, total, nil
+}
+
+// Authorize 获取就诊记录并校验当前用户对所属宠物的角色
+func (s *medicalRecordService) Authorize(ctx context.Context, userID, id uint, required string) (*model.MedicalRecord, error) {
+	record, err := s.recordRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "就诊记录不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, record.PetID, required); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// apply 校验请求并写入记录
+func (s *medicalRecordService) apply(ctx context.Context, userID uint, record *model.MedicalRecord, req *model.SaveMedicalRecordRequest) error {
+	if !model.MedicalRecordTypes[req.Type] {
+		return errors.New("不支持的就诊记录类型")
+	}
+	visitDate, err := parseDate(req.VisitDate)
+	if err != nil {
+		return err
+	}
+	if visitDate.After(today()) {
+		return errors.New("就诊日期不能晚于今天")
+	}
+	if req.Cost < 0 {
+		return errors.New("费用不能为负数")
+	}
+	// 疫苗信息仅对疫苗接种记录有效,用于生成疫苗接种证明
+	vaccineName, batchNo := strings.TrimSpace(req.VaccineName), strings.TrimSpace(req.BatchNo)
+	var validUntil *time.Time
+	if req.Type == model.MedicalRecordVaccination {
+		if vaccineName == "" {
+			return errors.New("疫苗接种记录须填写疫苗名称")
+		}
+		if req.ValidUntil != "" {
+			d, err := parseDate(req.ValidUntil)
+			if err != nil {
+				return err
+			}
+			if d.Before(visitDate) {
+				return errors.New("免疫有效期不能早于接种日期")
+			}
+			validUntil = &d
+		}
+	} else {
+		vaccineName, batchNo = "", ""
+	}
+	attachments, err := checkAttachments(ctx, s.fileService, userID, req.AttachmentFileIDs, record.AttachmentFileIDs)
+	if err != nil {
+		return err
+	}
+
+	record.Type = req.Type
+	record.VisitDate = visitDate
+	record.ClinicName = strings.TrimSpace(req.ClinicName)
+	record.VetName = strings.TrimSpace(req.VetName)
+	record.Diagnosis = strings.TrimSpace(req.Diagnosis)
+	record.Treatment = req.Treatment
+	record.Notes = req.Notes
+	record.Cost = req.Cost
+	record.InvoiceNo = strings.TrimSpace(req.InvoiceNo)
+	record.VaccineName = vaccineName
+	record.BatchNo = batchNo
+	record.ValidUntil = validUntil
+	record.AttachmentFileIDs = attachments
+	return nil
+}
+
+func (s *medicalRecordService) fillAttachments(record *model.MedicalRecord) {
+	record.Attachments = attachmentURLs(s.fileService, record.AttachmentFileIDs)
+}
+
+// checkAttachments 校验附件,新增的附件须为当前用户上传的文件,已关联的附件保留不再校验
+func checkAttachments(ctx context.Context, fileService FileService, userID uint, ids, existing []uint) ([]uint, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > maxAttachments {
+		return nil, errors.New("附件数量不能超过20个")
+	}
+	kept := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		kept[id] = true
+	}
+	for _, id := range ids {
+		if kept[id] {
+			continue
+		}
+		if _, err := fileService.GetFile(ctx, userID, id); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// attachmentURLs 生成附件的签名下载链接
+func attachmentURLs(fileService FileService, ids []uint) []string {
+	urls := make([]string, 0, len(ids))
+	for _, id := range ids {
+		urls = append(urls, fileService.SignedURL(id, model.FileVariantOriginal))
+	}
+	return urls
+}
@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+	"pet-service/pkg/schedule"
+)
+
+const (
+	// doseHorizon 给药记录提前生成的时长
+	doseHorizon = 7 * 24 * time.Hour
+	// maxDosesPerRun 单个计划单次最多生成的给药记录数,避免高频cron一次写入过多
+	maxDosesPerRun = 200
+	// doseMissedGrace 超过计划时间该时长仍未记录时视为漏服
+	doseMissedGrace = 6 * time.Hour
+	// doseEarlyWindow 最多可提前多久记录给药
+	doseEarlyWindow = 12 * time.Hour
+	// defaultAdherenceRange 未指定区间时默认统计最近30天
+	defaultAdherenceRange = 30 * 24 * time.Hour
+	// maxDoseRange 给药记录单次查询的最大区间
+	maxDoseRange = 92 * 24 * time.Hour
+	// maxDoseList 给药记录单次返回上限
+	maxDoseList = 1000
+	// medicationSchedulerInterval 用药调度执行间隔
+	medicationSchedulerInterval = time.Minute
+	// medicationSchedulerBatch 调度每轮处理的计划/提醒数量
+	medicationSchedulerBatch = 100
+)
+
+// doseIntervals 依从性统计支持的汇总粒度
+var doseIntervals = map[string]bool{model.IntervalDay: true, model.IntervalWeek: true}
+
+// MedicationService 用药计划服务接口
+type MedicationService interface {
+	CreatePlan(ctx context.Context, userID, petID uint, req *model.CreateMedicationPlanRequest) (*model.MedicationPlan, error)
+	GetPlan(ctx context.Context, userID, planID uint) (*model.MedicationPlan, error)
+	UpdatePlan(ctx context.Context, userID, planID uint, req *model.CreateMedicationPlanRequest) (*model.MedicationPlan, error)
+	UpdatePlanStatus(ctx context.Context, userID, planID uint, status string) (*model.MedicationPlan, error)
+	ListPlans(ctx context.Context, userID, petID uint, req *model.ListMedicationPlanRequest) ([]*model.MedicationPlan, error)
+	ListDoses(ctx context.Context, userID, petID uint, req *model.ListDoseRequest) ([]*model.MedicationDose, error)
+	RecordDose(ctx context.Context, userID, doseID uint, req *model.RecordDoseRequest) (*model.MedicationDose, error)
+	Adherence(ctx context.Context, userID, petID uint, req *model.AdherenceRequest) (*model.AdherenceReport, error)
+	RunScheduler(ctx context.Context)
+}
+
+// medicationService 用药计划服务实现
+type medicationService struct {
+	medicationRepo repository.MedicationRepository
+	petRepo        repository.PetRepository
+	shareRepo      repository.PetShareRepository
+	petService     PetService
+	notifier       notifier.Notifier
+}
+
+// NewMedicationService 创建用药计划服务
+func NewMedicationService(medicationRepo repository.MedicationRepository, petRepo repository.PetRepository, shareRepo repository.PetShareRepository, petService PetService, n notifier.Notifier) MedicationService {
+	return &medicationService{
+		medicationRepo: medicationRepo,
+		petRepo:        petRepo,
+		shareRepo:      shareRepo,
+		petService:     petService,
+		notifier:       n,
+	}
+}
+
+// CreatePlan 创建用药计划并生成未来一周的给药记录
+func (s *medicationService) CreatePlan(ctx context.Context, userID, petID uint, req *model.CreateMedicationPlanRequest) (*model.MedicationPlan, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+
+	plan := &model.MedicationPlan{
+		PetID:     petID,
+		CreatedBy: userID,
+		Status:    model.MedicationStatusActive,
+	}
+	if err := applyPlanRequest(plan, req); err != nil {
+		return nil, err
+	}
+	plan.GeneratedUntil = laterOf(plan.StartAt, time.Now())
+
+	if err := s.medicationRepo.CreatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	if err := s.generateDoses(ctx, plan, time.Now()); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "创建用药计划成功", logger.Int("plan_id", int(plan.ID)), logger.Int("pet_id", int(petID)))
+	return plan, nil
+}
+
+// GetPlan 获取用药计划
+func (s *medicationService) GetPlan(ctx context.Context, userID, planID uint) (*model.MedicationPlan, error) {
+	return s.authorizePlan(ctx, userID, planID, model.PetRoleViewer)
+}
+
+// UpdatePlan 修改用药计划,未执行的后续给药记录按新规则重新生成
+func (s *medicationService) UpdatePlan(ctx context.Context, userID, planID uint, req *model.CreateMedicationPlanRequest) (*model.MedicationPlan, error) {
+	plan, err := s.authorizePlan(ctx, userID, planID, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status == model.MedicationStatusEnded {
+		return nil, errors.New("用药计划已结束,无法修改")
+	}
+	if err := applyPlanRequest(plan, req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.medicationRepo.DeletePendingDoses(ctx, plan.ID, now); err != nil {
+		return nil, err
+	}
+	plan.GeneratedUntil = laterOf(plan.StartAt, now)
+	if err := s.medicationRepo.UpdatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	if plan.Status == model.MedicationStatusActive {
+		if err := s.generateDoses(ctx, plan, now); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// UpdatePlanStatus 暂停、恢复或结束用药计划
+func (s *medicationService) UpdatePlanStatus(ctx context.Context, userID, planID uint, status string) (*model.MedicationPlan, error) {
+	switch status {
+	case model.MedicationStatusActive, model.MedicationStatusPaused, model.MedicationStatusEnded:
+	default:
+		return nil, errors.New("状态不合法")
+	}
+	plan, err := s.authorizePlan(ctx, userID, planID, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status == status {
+		return plan, nil
+	}
+	if plan.Status == model.MedicationStatusEnded {
+		return nil, errors.New("用药计划已结束,无法变更状态")
+	}
+
+	now := time.Now()
+	if status == model.MedicationStatusActive {
+		if plan.EndAt != nil && plan.EndAt.Before(now) {
+			return nil, errors.New("用药计划已过结束时间,无法恢复")
+		}
+		// 恢复时从当前时间开始生成,暂停期间的记录不补
+		plan.GeneratedUntil = laterOf(plan.StartAt, now)
+	} else if err := s.medicationRepo.DeletePendingDoses(ctx, plan.ID, now); err != nil {
+		return nil, err
+	}
+
+	plan.Status = status
+	if err := s.medicationRepo.UpdatePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	if status == model.MedicationStatusActive {
+		if err := s.generateDoses(ctx, plan, now); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info(ctx, "用药计划状态变更", logger.Int("plan_id", int(planID)), logger.String("status", status))
+	return plan, nil
+}
+
+// ListPlans 获取宠物的用药计划
+func (s *medicationService) ListPlans(ctx context.Context, userID, petID uint, req *model.ListMedicationPlanRequest) ([]*model.MedicationPlan, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.medicationRepo.ListPlans(ctx, petID, req.Status)
+}
+
+// ListDoses 查询宠物的给药记录,默认返回今天起一周内的记录
+func (s *medicationService) ListDoses(ctx context.Context, userID, petID uint, req *model.ListDoseRequest) ([]*model.MedicationDose, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if req.From != nil {
+		from = *req.From
+	}
+	to := from.Add(doseHorizon)
+	if req.To != nil {
+		to = *req.To
+	}
+	if !from.Before(to) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+	if to.Sub(from) > maxDoseRange {
+		return nil, errors.New("查询区间不能超过92天")
+	}
+	return s.medicationRepo.ListDoses(ctx, petID, req, from, to, maxDoseList)
+}
+
+// RecordDose 记录给药或跳过,漏服的记录可以补记
+func (s *medicationService) RecordDose(ctx context.Context, userID, doseID uint, req *model.RecordDoseRequest) (*model.MedicationDose, error) {
+	if req.Status != model.DoseStatusGiven && req.Status != model.DoseStatusSkipped {
+		return nil, errors.New("给药结果仅支持given或skipped")
+	}
+	dose, err := s.medicationRepo.GetDose(ctx, doseID)
+	if err != nil {
+		return nil, checkNotFound(err, "给药记录不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, dose.PetID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+	if dose.Status != model.DoseStatusScheduled && dose.Status != model.DoseStatusMissed {
+		return nil, errors.New("该给药记录已处理")
+	}
+
+	now := time.Now()
+	if dose.ScheduledAt.After(now.Add(doseEarlyWindow)) {
+		return nil, errors.New("尚未到给药时间")
+	}
+	actedAt := now
+	if req.ActedAt != nil {
+		if req.ActedAt.After(now.Add(5 * time.Minute)) {
+			return nil, errors.New("给药时间不能晚于当前时间")
+		}
+		actedAt = *req.ActedAt
+	}
+
+	fromStatus := dose.Status
+	dose.Status = req.Status
+	dose.ActedAt = &actedAt
+	dose.ActedBy = userID
+	dose.Note = req.Note
+	if err := s.medicationRepo.UpdateDoseStatus(ctx, dose, fromStatus); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("状态已变更,请刷新后重试")
+		}
+		return nil, err
+	}
+	return dose, nil
+}
+
+// Adherence 统计区间内的用药依从性,分别按计划和按天/周汇总
+func (s *medicationService) Adherence(ctx context.Context, userID, petID uint, req *model.AdherenceRequest) (*model.AdherenceReport, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.Add(-defaultAdherenceRange)
+	if req.From != nil {
+		from = *req.From
+	}
+	if !from.Before(to) {
+		return nil, errors.New("开始时间必须早于结束时间")
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return nil, errors.New("统计区间不能超过一年")
+	}
+	interval := req.Interval
+	if _, ok := doseIntervals[interval]; !ok {
+		interval = model.IntervalWeek
+	}
+
+	planCounts, err := s.medicationRepo.CountByPlan(ctx, petID, req.PlanID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	periodCounts, err := s.medicationRepo.CountByPeriod(ctx, petID, req.PlanID, from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := s.medicationRepo.ListPlans(ctx, petID, "")
+	if err != nil {
+		return nil, err
+	}
+	drugs := make(map[uint]string, len(plans))
+	for _, plan := range plans {
+		drugs[plan.ID] = plan.Drug
+	}
+
+	report := &model.AdherenceReport{From: from, To: to}
+	byPlan := make(map[uint]*model.PlanAdherence)
+	for _, c := range planCounts {
+		item, ok := byPlan[c.PlanID]
+		if !ok {
+			item = &model.PlanAdherence{PlanID: c.PlanID, Drug: drugs[c.PlanID]}
+			byPlan[c.PlanID] = item
+			report.Plans = append(report.Plans, item)
+		}
+		item.Add(c.Status, c.Count)
+		report.Total.Add(c.Status, c.Count)
+	}
+	for _, item := range report.Plans {
+		item.Finish()
+	}
+	report.Total.Finish()
+
+	var last *model.AdherenceBucket
+	for _, c := range periodCounts {
+		if last == nil || !last.Bucket.Equal(c.Bucket) {
+			last = &model.AdherenceBucket{Bucket: c.Bucket}
+			report.Periods = append(report.Periods, last)
+		}
+		last.Add(c.Status, c.Count)
+	}
+	for _, bucket := range report.Periods {
+		bucket.Finish()
+	}
+	return report, nil
+}
+
+// RunScheduler 后台调度:补齐给药记录、发送用药提醒、标记漏服,ctx取消后退出
+func (s *medicationService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(medicationSchedulerInterval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "用药调度已启动")
+	for {
+		s.runOnce(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "用药调度已停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce 执行一轮调度,单步失败只记录日志,下一轮重试
+func (s *medicationService) runOnce(ctx context.Context, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "用药调度异常", logger.Any("panic", r))
+		}
+	}()
+
+	if n, err := s.medicationRepo.EndExpiredPlans(ctx, now); err == nil && n > 0 {
+		logger.Info(ctx, "用药计划已到期结束", logger.Int64("count", n))
+	}
+
+	plans, err := s.medicationRepo.ListPlansToGenerate(ctx, now.Add(doseHorizon), medicationSchedulerBatch)
+	if err == nil {
+		for _, plan := range plans {
+			if err := s.generateDoses(ctx, plan, now); err != nil {
+				logger.Error(ctx, "生成给药记录失败", logger.Int("plan_id", int(plan.ID)), logger.ErrorField(err))
+			}
+		}
+	}
+
+	doses, err := s.medicationRepo.ListDueReminders(ctx, now, medicationSchedulerBatch)
+	if err == nil {
+		for _, dose := range doses {
+			s.remind(ctx, dose, now)
+		}
+	}
+
+	if n, err := s.medicationRepo.MarkMissed(ctx, now.Add(-doseMissedGrace)); err == nil && n > 0 {
+		logger.Info(ctx, "标记漏服给药记录", logger.Int64("count", n))
+	}
+}
+
+// generateDoses 从计划的生成进度开始生成到now+doseHorizon为止的给药记录
+func (s *medicationService) generateDoses(ctx context.Context, plan *model.MedicationPlan, now time.Time) error {
+	until := now.Add(doseHorizon)
+	if plan.EndAt != nil && plan.EndAt.Before(until) {
+		until = *plan.EndAt
+	}
+	if !plan.GeneratedUntil.Before(until) {
+		return nil
+	}
+
+	times, err := planOccurrences(plan, plan.GeneratedUntil, until, maxDosesPerRun)
+	if err != nil {
+		return err
+	}
+	cursor := until
+	if len(times) == maxDosesPerRun {
+		cursor = times[len(times)-1].Add(time.Second)
+	}
+
+	doses := make([]*model.MedicationDose, 0, len(times))
+	for _, t := range times {
+		doses = append(doses, &model.MedicationDose{
+			PlanID:      plan.ID,
+			PetID:       plan.PetID,
+			ScheduledAt: t,
+			Status:      model.DoseStatusScheduled,
+		})
+	}
+	if err := s.medicationRepo.CreateDoses(ctx, plan.ID, doses, cursor); err != nil {
+		return err
+	}
+	plan.GeneratedUntil = cursor
+	return nil
+}
+
+// remind 向宠物主人及可编辑成员发送用药提醒
+func (s *medicationService) remind(ctx context.Context, dose *model.MedicationDose, now time.Time) {
+	claimed, err := s.medicationRepo.ClaimReminder(ctx, dose.ID, now)
+	if err != nil || !claimed {
+		return
+	}
+	pet, err := s.petRepo.GetByID(ctx, dose.PetID)
+	if err != nil {
+		logger.Error(ctx, "用药提醒获取宠物失败", logger.Int("dose_id", int(dose.ID)), logger.ErrorField(err))
+		return
+	}
+	recipients, err := s.reminderRecipients(ctx, pet)
+	if err != nil {
+		return
+	}
+
+	plan := dose.Plan
+	for _, uid := range recipients {
+		msg := &notifier.Message{
+			UserID:  uid,
+			Type:    model.NotifyTypeMedication,
+			Title:   "用药提醒",
+			Content: fmt.Sprintf("%s 在 %s 需要服用 %s %g%s", pet.Name, dose.ScheduledAt.Format("01-02 15:04"), plan.Drug, plan.Dose, plan.Unit),
+			Data: map[string]interface{}{
+				"pet_id":       dose.PetID,
+				"plan_id":      dose.PlanID,
+				"dose_id":      dose.ID,
+				"scheduled_at": dose.ScheduledAt,
+			},
+		}
+		if err := s.notifier.Notify(ctx, msg); err != nil {
+			logger.Error(ctx, "发送用药提醒失败", logger.Int("dose_id", int(dose.ID)), logger.Int("user_id", int(uid)), logger.ErrorField(err))
+		}
+	}
+}
+
+// reminderRecipients 获取接收用药提醒的用户:主人以及editor及以上角色的成员
+func (s *medicationService) reminderRecipients(ctx context.Context, pet *model.Pet) ([]uint, error) {
+	members, err := s.shareRepo.ListMembers(ctx, pet.ID)
+	if err != nil {
+		return nil, err
+	}
+	recipients := []uint{pet.OwnerID}
+	for _, m := range members {
+		if m.UserID != pet.OwnerID && model.PetRoleAtLeast(m.Role, model.PetRoleEditor) {
+			recipients = append(recipients, m.UserID)
+		}
+	}
+	return recipients, nil
+}
+
+// authorizePlan 获取用药计划并校验对所属宠物的角色
+func (s *medicationService) authorizePlan(ctx context.Context, userID, planID uint, required string) (*model.MedicationPlan, error) {
+	plan, err := s.medicationRepo.GetPlan(ctx, planID)
+	if err != nil {
+		return nil, checkNotFound(err, "用药计划不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, plan.PetID, required); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// applyPlanRequest 校验请求并写入计划字段
+func applyPlanRequest(plan *model.MedicationPlan, req *model.CreateMedicationPlanRequest) error {
+	switch req.ScheduleType {
+	case model.ScheduleInterval:
+		if req.IntervalHours <= 0 {
+			return errors.New("按间隔给药时需填写间隔小时数")
+		}
+		plan.IntervalHours = req.IntervalHours
+		plan.Cron = ""
+	case model.ScheduleCron:
+		if _, err := schedule.ParseCron(req.Cron); err != nil {
+			return fmt.Errorf("cron表达式不合法: %v", err)
+		}
+		plan.Cron = req.Cron
+		plan.IntervalHours = 0
+	default:
+		return errors.New("频率规则仅支持interval或cron")
+	}
+	if req.Drug == "" || req.Unit == "" || req.Dose <= 0 {
+		return errors.New("请填写药品名称、剂量和单位")
+	}
+	if req.StartAt.IsZero() {
+		return errors.New("请填写开始时间")
+	}
+	if req.EndAt != nil && !req.EndAt.After(req.StartAt) {
+		return errors.New("结束时间必须晚于开始时间")
+	}
+
+	plan.Drug = req.Drug
+	plan.Dose = req.Dose
+	plan.Unit = req.Unit
+	plan.ScheduleType = req.ScheduleType
+	plan.StartAt = req.StartAt
+	plan.EndAt = req.EndAt
+	plan.RemindBeforeMin = req.RemindBeforeMin
+	plan.Instructions = req.Instructions
+	return nil
+}
+
+// planOccurrences 计算[from, to)区间内的给药时间点,最多返回limit个
+func planOccurrences(plan *model.MedicationPlan, from, to time.Time, limit int) ([]time.Time, error) {
+	var times []time.Time
+	switch plan.ScheduleType {
+	case model.ScheduleInterval:
+		step := time.Duration(plan.IntervalHours) * time.Hour
+		t := plan.StartAt
+		if from.After(t) {
+			k := (from.Sub(t) + step - 1) / step
+			t = t.Add(k * step)
+		}
+		for ; t.Before(to) && len(times) < limit; t = t.Add(step) {
+			times = append(times, t)
+		}
+	case model.ScheduleCron:
+		c, err := schedule.ParseCron(plan.Cron)
+		if err != nil {
+			return nil, err
+		}
+		for t := c.Next(from.Add(-time.Minute)); !t.IsZero() && t.Before(to) && len(times) < limit; t = c.Next(t) {
+			if !t.Before(from) {
+				times = append(times, t)
+			}
+		}
+	}
+	return times, nil
+}
+
+// laterOf 返回两个时间中较晚的一个
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/microchip"
+)
+
+// MicrochipService 芯片登记查询服务接口
+type MicrochipService interface {
+	Lookup(ctx context.Context, code string, client *model.LookupClient) (*model.ChipLookupResult, error)
+	SendMessage(ctx context.Context, code string, client *model.LookupClient, req *model.SendFoundMessageRequest) error
+	ListLookups(ctx context.Context, userID, petID uint, req *model.ListChipRecordRequest) ([]*model.MicrochipLookup, int64, error)
+	ListMessages(ctx context.Context, userID, petID uint, req *model.ListChipRecordRequest) ([]*model.FoundPetMessage, int64, error)
+}
+
+// microchipService 芯片登记查询服务实现
+type microchipService struct {
+	chipRepo   repository.MicrochipRepository
+	petRepo    repository.PetRepository
+	userRepo   repository.UserRepository
+	petService PetService
+}
+
+// NewMicrochipService 创建芯片登记查询服务
+func NewMicrochipService(chipRepo repository.MicrochipRepository, petRepo repository.PetRepository, userRepo repository.UserRepository, petService PetService) MicrochipService {
+	return &microchipService{
+		chipRepo:   chipRepo,
+		petRepo:    petRepo,
+		userRepo:   userRepo,
+		petService: petService,
+	}
+}
+
+// Lookup 公开查询芯片号,返回宠物外观信息及脱敏的主人联系方式,每次查询都会记录审计
+func (s *microchipService) Lookup(ctx context.Context, code string, client *model.LookupClient) (*model.ChipLookupResult, error) {
+	code, pet, err := s.findPet(ctx, code, client, model.ChipActionLookup)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ChipLookupResult{
+		Microchip: code,
+		Pet: &model.ChipPetInfo{
+			Name:    pet.Name,
+			Species: pet.Species,
+			Breed:   pet.Breed,
+			Color:   pet.Color,
+			Avatar:  pet.Avatar,
+		},
+	}
+	if pet.OwnerID != 0 {
+		if owner, err := s.userRepo.GetByID(ctx, pet.OwnerID); err == nil {
+			name := owner.Nickname
+			if name == "" {
+				name = owner.Username
+			}
+			result.Contact = &model.MaskedContact{
+				Name:  maskName(name),
+				Phone: maskPhone(owner.Phone),
+				Email: maskEmail(owner.Email),
+			}
+		}
+	}
+	return result, nil
+}
+
+// SendMessage 拾获人留言转达给宠物主人,主人的联系方式不会返回给拾获人
+func (s *microchipService) SendMessage(ctx context.Context, code string, client *model.LookupClient, req *model.SendFoundMessageRequest) error {
+	message := &model.FoundPetMessage{
+		FinderName:    strings.TrimSpace(req.FinderName),
+		FinderContact: strings.TrimSpace(req.FinderContact),
+		Content:       strings.TrimSpace(req.Content),
+		Location:      strings.TrimSpace(req.Location),
+	}
+	switch {
+	case message.Content == "":
+		return invalidParam("留言内容不能为空")
+	case utf8.RuneCountInString(message.Content) > 1000:
+		return invalidParam("留言内容不能超过1000字")
+	case message.FinderContact == "":
+		return invalidParam("请填写联系方式")
+	case utf8.RuneCountInString(message.FinderContact) > 100:
+		return invalidParam("联系方式不能超过100字")
+	case utf8.RuneCountInString(message.FinderName) > 50:
+		return invalidParam("称呼不能超过50字")
+	case utf8.RuneCountInString(message.Location) > 255:
+		return invalidParam("发现地点不能超过255字")
+	}
+
+	_, pet, err := s.findPet(ctx, code, client, model.ChipActionMessage)
+	if err != nil {
+		return err
+	}
+	message.PetID = pet.ID
+	return s.chipRepo.CreateMessage(ctx, message)
+}
+
+// ListLookups 宠物成员查看芯片被查询的记录
+func (s *microchipService) ListLookups(ctx context.Context, userID, petID uint, req *model.ListChipRecordRequest) ([]*model.MicrochipLookup, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.chipRepo.ListLookupsByPet(ctx, petID, offset, limit)
+}
+
+// ListMessages 宠物成员查看拾获留言,返回的留言标记为已读
+func (s *microchipService) ListMessages(ctx context.Context, userID, petID uint, req *model.ListChipRecordRequest) ([]*model.FoundPetMessage, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	messages, total, err := s.chipRepo.ListMessagesByPet(ctx, petID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	unread := make([]uint, 0, len(messages))
+	for _, m := range messages {
+		if !m.IsRead {
+			unread = append(unread, m.ID)
+		}
+	}
+	if err := s.chipRepo.MarkMessagesRead(ctx, petID, unread); err != nil {
+		logger.Warn(ctx, "标记拾获留言已读失败", logger.Int("pet_id", int(petID)), logger.ErrorField(err))
+	}
+	return messages, total, nil
+}
+
+// findPet 校验芯片号并查找宠物,无论是否命中都写入审计记录
+func (s *microchipService) findPet(ctx context.Context, code string, client *model.LookupClient, action string) (string, *model.Pet, error) {
+	code = microchip.Normalize(code)
+	if err := microchip.Validate(code); err != nil {
+		return code, nil, err
+	}
+
+	pet, err := s.petRepo.GetByMicrochip(ctx, code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return code, nil, err
+	}
+
+	lookup := &model.MicrochipLookup{
+		Microchip: code,
+		Action:    action,
+		Found:     pet != nil,
+		UserID:    client.UserID,
+		ClientIP:  client.ClientIP,
+		UserAgent: truncate(client.UserAgent, 255),
+	}
+	if pet != nil {
+		lookup.PetID = pet.ID
+	}
+	// 审计写入失败时拒绝本次查询,保证每次查询都有记录
+	if err := s.chipRepo.CreateLookup(ctx, lookup); err != nil {
+		return code, nil, err
+	}
+
+	if pet == nil {
+		return code, nil, notFound("未找到该芯片号登记的宠物")
+	}
+	logger.Info(ctx, "芯片查询命中", logger.Int("pet_id", int(pet.ID)), logger.String("action", action))
+	return code, pet, nil
+}
+
+// maskName 保留姓名首字
+func maskName(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return ""
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-1)
+}
+
+// maskPhone 保留手机号前3位和后4位
+func maskPhone(phone string) string {
+	if len(phone) < 7 {
+		return ""
+	}
+	return phone[:3] + strings.Repeat("*", len(phone)-7) + phone[len(phone)-4:]
+}
+
+// maskEmail 保留邮箱用户名首字符和域名
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return ""
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// truncate 按字节截断字符串,保证不截断多字节字符
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"sync"
+
+	"pet-service/biz/model"
+)
+
+// notificationStreamBuffer 单个事件流待推送通知的缓冲数,写满时丢弃,客户端重连后按Last-Event-ID补发
+const notificationStreamBuffer = 32
+
+// NotificationStream 一次实时订阅,Backlog为订阅时补发的历史通知,C接收之后的新通知
+type NotificationStream struct {
+	Backlog []*model.Notification
+	C       <-chan *model.Notification
+
+	hub    *notificationHub
+	userID uint
+	ch     chan *model.Notification
+}
+
+// Close 取消订阅
+func (s *NotificationStream) Close() {
+	s.hub.unregister(s.userID, s.ch)
+}
+
+// notificationHub 当前实例上的实时订阅,跨实例的通知经Redis频道分发后由各实例投递
+type notificationHub struct {
+	mu      sync.RWMutex
+	streams map[uint]map[chan *model.Notification]struct{}
+}
+
+func newNotificationHub() *notificationHub {
+	return &notificationHub{streams: make(map[uint]map[chan *model.Notification]struct{})}
+}
+
+func (h *notificationHub) register(userID uint) chan *model.Notification {
+	ch := make(chan *model.Notification, notificationStreamBuffer)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.streams[userID] == nil {
+		h.streams[userID] = make(map[chan *model.Notification]struct{})
+	}
+	h.streams[userID][ch] = struct{}{}
+	return ch
+}
+
+func (h *notificationHub) unregister(userID uint, ch chan *model.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if set := h.streams[userID]; set != nil {
+		delete(set, ch)
+		if len(set) == 0 {
+			delete(h.streams, userID)
+		}
+	}
+}
+
+// deliver 投递给本实例上该用户的全部订阅,缓冲已满的订阅丢弃本条
+func (h *notificationHub) deliver(notification *model.Notification) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.streams[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+	"pet-service/pkg/redis"
+)
+
+const (
+	// notificationChannel 新通知的Redis发布频道,所有实例订阅后推送给本地事件流
+	notificationChannel = "notify:events"
+	// notificationBacklogLimit 重连补发的最大条数
+	notificationBacklogLimit = 100
+)
+
+// NotificationService 站内通知服务接口,同时作为业务模块使用的通知发送实现
+type NotificationService interface {
+	notifier.Notifier
+
+	List(ctx context.Context, userID uint, req *model.ListNotificationRequest) (*model.NotificationPage, error)
+	MarkRead(ctx context.Context, userID, id uint) error
+	MarkAllRead(ctx context.Context, userID uint, notifyType string) (int64, error)
+	GetPreferences(ctx context.Context, userID uint) ([]model.NotificationType, error)
+	UpdatePreferences(ctx context.Context, userID uint, req *model.UpdateNotificationPreferencesRequest) ([]model.NotificationType, error)
+
+	// Subscribe 订阅当前用户的新通知,lastID不为0时补发该ID之后的通知,使用方负责关闭
+	Subscribe(ctx context.Context, userID, lastID uint) (*NotificationStream, error)
+	// RunSubscriber 订阅Redis频道并推送给本实例的事件流,ctx取消后退出
+	RunSubscriber(ctx context.Context)
+}
+
+// notificationService 站内通知服务实现
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	push             notifier.Notifier
+	hub              *notificationHub
+}
+
+// NewNotificationService 创建站内通知服务,push为外部推送渠道的实现
+func NewNotificationService(notificationRepo repository.NotificationRepository, push notifier.Notifier) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		push:             push,
+		hub:              newNotificationHub(),
+	}
+}
+
+// Notify 按用户偏好投递通知:站内信写入消息中心并实时推送,外部推送交给push渠道
+func (s *notificationService) Notify(ctx context.Context, msg *notifier.Message) error {
+	channels := s.channels(ctx, msg.UserID, msg.Type)
+
+	var errs []error
+	if channels[model.NotifyChannelInbox] {
+		notification := &model.Notification{
+			UserID:  msg.UserID,
+			Type:    msg.Type,
+			Title:   truncate(msg.Title, 100),
+			Content: truncate(msg.Content, 500),
+			Payload: msg.Data,
+		}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			errs = append(errs, err)
+		} else {
+			s.publish(ctx, notification)
+		}
+	}
+	if channels[model.NotifyChannelPush] {
+		if err := s.push.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// channels 用户对该类通知开启的渠道,未设置时使用默认渠道,读取失败时只投递站内信
+func (s *notificationService) channels(ctx context.Context, userID uint, notifyType string) map[string]bool {
+	enabled := make(map[string]bool)
+	preference, err := s.notificationRepo.GetPreference(ctx, userID, notifyType)
+	switch {
+	case err == nil:
+		for _, channel := range preference.Channels {
+			enabled[channel] = true
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if t := model.FindNotificationType(notifyType); t != nil {
+			for _, channel := range t.Channels {
+				enabled[channel] = true
+			}
+		} else {
+			enabled[model.NotifyChannelInbox] = true
+		}
+	default:
+		logger.Warn(ctx, "获取通知偏好失败,仅投递站内信", logger.Int("user_id", int(userID)), logger.ErrorField(err))
+		enabled[model.NotifyChannelInbox] = true
+	}
+	return enabled
+}
+
+// publish 发布新通知,Redis不可用时只推送给本实例的事件流
+func (s *notificationService) publish(ctx context.Context, notification *model.Notification) {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		logger.Error(ctx, "序列化通知失败", logger.ErrorField(err))
+		return
+	}
+	if err := redis.Publish(ctx, notificationChannel, payload); err != nil {
+		s.hub.deliver(notification)
+	}
+}
+
+// List 获取当前用户的通知及未读总数
+func (s *notificationService) List(ctx context.Context, userID uint, req *model.ListNotificationRequest) (*model.NotificationPage, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	notifications, total, err := s.notificationRepo.List(ctx, userID, req.Unread, req.Type, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NotificationPage{
+		List:     notifications,
+		Total:    total,
+		Unread:   unread,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// MarkRead 标记单条通知已读
+func (s *notificationService) MarkRead(ctx context.Context, userID, id uint) error {
+	notification, err := s.notificationRepo.GetByID(ctx, id)
+	if err != nil {
+		return checkNotFound(err, "通知不存在")
+	}
+	if notification.UserID != userID {
+		return notFound("通知不存在")
+	}
+	return s.notificationRepo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead 标记全部通知已读,可按类型限定
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uint, notifyType string) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID, notifyType)
+}
+
+// GetPreferences 获取全部通知类型的渠道设置,未设置的类型返回默认渠道
+func (s *notificationService) GetPreferences(ctx context.Context, userID uint) ([]model.NotificationType, error) {
+	preferences, err := s.notificationRepo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	saved := make(map[string][]string, len(preferences))
+	for _, p := range preferences {
+		saved[p.Type] = p.Channels
+	}
+
+	result := make([]model.NotificationType, 0, len(model.NotificationTypes))
+	for _, t := range model.NotificationTypes {
+		if channels, ok := saved[t.Type]; ok {
+			t.Channels = channels
+		}
+		if t.Channels == nil {
+			t.Channels = []string{}
+		}
+		result = append(result, t)
+	}
+	return result, nil
+}
+
+// UpdatePreferences 更新通知类型的渠道设置,channels为空表示不接收该类通知
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID uint, req *model.UpdateNotificationPreferencesRequest) ([]model.NotificationType, error) {
+	preferences := make([]*model.NotificationPreference, 0, len(req.Preferences))
+	seen := make(map[string]bool, len(req.Preferences))
+	for _, item := range req.Preferences {
+		if model.FindNotificationType(item.Type) == nil {
+			return nil, fmt.Errorf("不支持的通知类型: %s", item.Type)
+		}
+		if seen[item.Type] {
+			return nil, fmt.Errorf("通知类型重复: %s", item.Type)
+		}
+		seen[item.Type] = true
+
+		channels := []string{}
+		enabled := make(map[string]bool)
+		for _, channel := range item.Channels {
+			if !model.NotifyChannels[channel] {
+				return nil, fmt.Errorf("不支持的通知渠道: %s", channel)
+			}
+			if !enabled[channel] {
+				enabled[channel] = true
+				channels = append(channels, channel)
+			}
+		}
+		preferences = append(preferences, &model.NotificationPreference{
+			UserID:   userID,
+			Type:     item.Type,
+			Channels: channels,
+		})
+	}
+
+	if err := s.notificationRepo.SavePreferences(ctx, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// Subscribe 先注册订阅再查询补发,避免两者之间产生的通知丢失,重复的通知由调用方按ID去重
+func (s *notificationService) Subscribe(ctx context.Context, userID, lastID uint) (*NotificationStream, error) {
+	ch := s.hub.register(userID)
+	stream := &NotificationStream{C: ch, hub: s.hub, userID: userID, ch: ch}
+	if lastID == 0 {
+		return stream, nil
+	}
+
+	backlog, err := s.notificationRepo.ListAfter(ctx, userID, lastID, notificationBacklogLimit)
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+	stream.Backlog = backlog
+	return stream, nil
+}
+
+// RunSubscriber 订阅通知频道,将各实例产生的新通知推送给本实例的事件流
+func (s *notificationService) RunSubscriber(ctx context.Context) {
+	sub := redis.Subscribe(ctx, notificationChannel)
+	defer sub.Close()
+
+	logger.Info(ctx, "通知事件订阅已启动")
+	events := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(ctx, "通知事件订阅已停止")
+			return
+		case msg, ok := <-events:
+			if !ok {
+				return
+			}
+			var notification model.Notification
+			if err := json.Unmarshal([]byte(msg.Payload), &notification); err != nil || notification.UserID == 0 {
+				logger.Warn(ctx, "通知事件格式错误", logger.String("payload", truncate(msg.Payload, 200)))
+				continue
+			}
+			s.hub.deliver(&notification)
+		}
+	}
+}
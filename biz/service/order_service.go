@@ -0,0 +1,676 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/payment"
+)
+
+const (
+	// orderExpireInterval 超时订单扫描间隔
+	orderExpireInterval = time.Minute
+	// orderExpireBatch 每轮最多取消的超时订单数
+	orderExpireBatch = 100
+)
+
+// OrderPaidHook 订单支付成功后的业务回调,由各业务模块注册
+// 返回错误时回调事件不记录为已处理,网关重发通知时会再次执行,因此回调需保证幂等
+type OrderPaidHook func(ctx context.Context, order *model.Order) error
+
+// OrderService 订单服务接口
+type OrderService interface {
+	CreateOrder(ctx context.Context, userID uint, req *model.CreateOrderRequest) (*model.Order, error)
+	GetOrder(ctx context.Context, userID, id uint) (*model.Order, error)
+	ListMyOrders(ctx context.Context, userID uint, req *model.ListOrderRequest) ([]*model.Order, int64, error)
+	Pay(ctx context.Context, userID, id uint) (*payment.Prepay, error)
+	CancelOrder(ctx context.Context, userID, id uint, reason string) (*model.Order, error)
+	ConfirmOrder(ctx context.Context, userID, id uint) (*model.Order, error)
+	RequestRefund(ctx context.Context, userID, id uint, reason string) (*model.Order, error)
+	SimulatePayment(ctx context.Context, userID, id uint) (*model.Order, error)
+	HandleCallback(ctx context.Context, gateway string, body []byte, signature string) error
+
+	AdminGetOrder(ctx context.Context, id uint) (*model.Order, error)
+	AdminListOrders(ctx context.Context, req *model.ListOrderRequest) ([]*model.Order, int64, error)
+	FulfillOrder(ctx context.Context, id uint) (*model.Order, error)
+	RefundOrder(ctx context.Context, operatorID, id uint, req *model.RefundOrderRequest) (*model.Order, error)
+
+	OnPaid(orderType string, hook OrderPaidHook)
+	RunExpirationWorker(ctx context.Context)
+}
+
+// orderService 订单服务实现
+type orderService struct {
+	orderRepo     repository.OrderRepository
+	shopRepo      repository.ShopRepository
+	sitterRepo    repository.SitterRepository
+	donationRepo  repository.DonationRepository
+	pointsRepo    repository.PointsRepository
+	couponService CouponService
+	gateway       payment.PaymentGateway
+	payTimeout    time.Duration
+	publicURL     string
+	paidHooks     map[string][]OrderPaidHook
+}
+
+// NewOrderService 创建订单服务,payTimeout为下单后的支付时限
+func NewOrderService(orderRepo repository.OrderRepository, shopRepo repository.ShopRepository, sitterRepo repository.SitterRepository, donationRepo repository.DonationRepository, pointsRepo repository.PointsRepository, couponService CouponService, gateway payment.PaymentGateway, payTimeout time.Duration, publicURL string) OrderService {
+	return &orderService{
+		orderRepo:     orderRepo,
+		shopRepo:      shopRepo,
+		sitterRepo:    sitterRepo,
+		donationRepo:  donationRepo,
+		pointsRepo:    pointsRepo,
+		couponService: couponService,
+		gateway:       gateway,
+		payTimeout:    payTimeout,
+		publicURL:     strings.TrimRight(publicURL, "/"),
+		paidHooks:     make(map[string][]OrderPaidHook),
+	}
+}
+
+// OnPaid 注册订单支付成功回调,需在服务启动前调用
+func (s *orderService) OnPaid(orderType string, hook OrderPaidHook) {
+	s.paidHooks[orderType] = append(s.paidHooks[orderType], hook)
+}
+
+// CreateOrder 创建待支付订单,可同时使用优惠券和积分抵扣
+func (s *orderService) CreateOrder(ctx context.Context, userID uint, req *model.CreateOrderRequest) (*model.Order, error) {
+	orderNo, err := newOrderNo("P")
+	if err != nil {
+		return nil, err
+	}
+	order := &model.Order{
+		OrderNo:   orderNo,
+		UserID:    userID,
+		Type:      req.Type,
+		SourceID:  req.SourceID,
+		Currency:  model.CurrencyCNY,
+		Status:    model.OrderStatusPending,
+		ExpiresAt: time.Now().Add(s.payTimeout),
+	}
+
+	var reservationID uint
+	switch req.Type {
+	case model.OrderTypeShop:
+		reservationID, err = s.buildShopOrder(ctx, order)
+	case model.OrderTypeSitterBooking:
+		err = s.buildBookingOrder(ctx, order)
+	case model.OrderTypeDonation:
+		if req.CouponCode != "" || req.Points > 0 {
+			return nil, errors.New("捐款不能使用优惠券或积分抵扣")
+		}
+		err = s.buildDonationOrder(ctx, order)
+	default:
+		return nil, errors.New("订单类型无效")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyBenefits(ctx, order, req); err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.Create(ctx, order, reservationID); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// applyBenefits 计算优惠券和积分抵扣,先用券再用积分,实付至少1分
+// 券的次数和积分余额在下单事务中会再次校验
+func (s *orderService) applyBenefits(ctx context.Context, order *model.Order, req *model.CreateOrderRequest) error {
+	if req.CouponCode != "" {
+		coupon, discount, err := s.couponService.Quote(ctx, order.UserID, req.CouponCode, order.Type, order.TotalAmount)
+		if err != nil {
+			return err
+		}
+		if discount >= order.TotalAmount {
+			discount = order.TotalAmount - 1
+		}
+		order.CouponID = coupon.ID
+		order.CouponCode = coupon.Code
+		order.CouponDiscount = discount
+	}
+	if req.Points < 0 {
+		return errors.New("积分不能为负数")
+	}
+	if req.Points > 0 {
+		account, err := s.pointsRepo.GetAccount(ctx, order.UserID)
+		if err != nil {
+			return err
+		}
+		discount, err := pointsDiscount(req.Points, account.Balance, order.TotalAmount-order.CouponDiscount)
+		if err != nil {
+			return err
+		}
+		order.PointsUsed = req.Points
+		order.PointsDiscount = discount
+	}
+
+	order.DiscountAmount = order.CouponDiscount + order.PointsDiscount
+	order.PayAmount = order.TotalAmount - order.DiscountAmount
+	if order.PayAmount <= 0 {
+		return errors.New("订单金额无效")
+	}
+	return nil
+}
+
+// buildShopOrder 由结算生成的库存预占填充商城订单,返回需消耗的预占ID
+func (s *orderService) buildShopOrder(ctx context.Context, order *model.Order) (uint, error) {
+	reservation, err := s.shopRepo.GetReservation(ctx, order.SourceID)
+	if err != nil {
+		return 0, checkNotFound(err, "预占记录不存在")
+	}
+	if reservation.UserID != order.UserID {
+		return 0, notFound("预占记录不存在")
+	}
+	if reservation.Status != model.ReservationStatusReserved || !reservation.ExpiresAt.After(time.Now()) {
+		return 0, errors.New("库存预占已失效,请重新结算")
+	}
+
+	quantity := 0
+	for _, item := range reservation.Items {
+		order.Items = append(order.Items, &model.OrderItem{
+			SKUID:     item.SKUID,
+			ProductID: item.ProductID,
+			Name:      item.ProductName,
+			SKUName:   item.SKUName,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+			Amount:    item.Price * int64(item.Quantity),
+		})
+		quantity += item.Quantity
+	}
+	if len(order.Items) == 0 {
+		return 0, errors.New("预占记录没有商品")
+	}
+	order.Subject = truncate(fmt.Sprintf("%s 等%d件商品", order.Items[0].Name, quantity), 255)
+	order.TotalAmount = reservation.TotalAmount
+	return reservation.ID, nil
+}
+
+// buildBookingOrder 为已接受的寄养预约填充订单,同一预约只能有一笔有效订单
+func (s *orderService) buildBookingOrder(ctx context.Context, order *model.Order) error {
+	booking, err := s.sitterRepo.GetBooking(ctx, order.SourceID)
+	if err != nil {
+		return checkNotFound(err, "寄养预约不存在")
+	}
+	if booking.OwnerID != order.UserID {
+		return forbidden("只有预约发起人可以支付")
+	}
+	if booking.Status != model.BookingStatusAccepted {
+		return errors.New("看护人接受预约后才能支付")
+	}
+	exists, err := s.orderRepo.HasActiveOrder(ctx, model.OrderTypeSitterBooking, booking.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("该预约已有待支付或已支付的订单")
+	}
+
+	name := fmt.Sprintf("宠物寄养 %s 至 %s", booking.StartDate.Format(model.DateLayout), booking.EndDate.Format(model.DateLayout))
+	order.Items = []*model.OrderItem{{
+		Name:     name,
+		SKUName:  fmt.Sprintf("%d晚", booking.Nights),
+		Price:    booking.TotalPrice,
+		Quantity: 1,
+		Amount:   booking.TotalPrice,
+	}}
+	order.Subject = name
+	order.TotalAmount = booking.TotalPrice
+	return nil
+}
+
+// buildDonationOrder 为待支付的捐款填充订单,同一捐款只能有一笔有效订单
+func (s *orderService) buildDonationOrder(ctx context.Context, order *model.Order) error {
+	donation, err := s.donationRepo.GetDonation(ctx, order.SourceID)
+	if err != nil {
+		return checkNotFound(err, "捐款不存在")
+	}
+	if donation.UserID != order.UserID {
+		return notFound("捐款不存在")
+	}
+	if donation.Status != model.DonationStatusPending {
+		return errors.New("该捐款已到账")
+	}
+	if donation.Campaign == nil || !donation.Campaign.AcceptsDonations(time.Now()) {
+		return errors.New("募捐活动已结束")
+	}
+	exists, err := s.orderRepo.HasActiveOrder(ctx, model.OrderTypeDonation, donation.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("该捐款已有待支付或已支付的订单")
+	}
+
+	name := truncate("捐款 "+donation.Campaign.Title, 255)
+	order.Items = []*model.OrderItem{{
+		Name:     name,
+		Price:    donation.Amount,
+		Quantity: 1,
+		Amount:   donation.Amount,
+	}}
+	order.Subject = name
+	order.TotalAmount = donation.Amount
+	return nil
+}
+
+// GetOrder 获取本人订单
+func (s *orderService) GetOrder(ctx context.Context, userID, id uint) (*model.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "订单不存在")
+	}
+	if order.UserID != userID {
+		return nil, notFound("订单不存在")
+	}
+	return order, nil
+}
+
+// ListMyOrders 我的订单
+func (s *orderService) ListMyOrders(ctx context.Context, userID uint, req *model.ListOrderRequest) ([]*model.Order, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.orderRepo.List(ctx, &repository.OrderFilter{
+		UserID: userID,
+		Status: req.Status,
+		Type:   req.Type,
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+// Pay 发起支付,返回网关支付参数
+func (s *orderService) Pay(ctx context.Context, userID, id uint) (*payment.Prepay, error) {
+	order, err := s.GetOrder(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, errors.New("订单不是待支付状态")
+	}
+	if !order.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("订单已超时,请重新下单")
+	}
+
+	prepay, err := s.gateway.Prepay(ctx, &payment.PrepayRequest{
+		OrderNo:   order.OrderNo,
+		Amount:    order.PayAmount,
+		Subject:   order.Subject,
+		NotifyURL: fmt.Sprintf("%s/api/v1/payments/%s/callback", s.publicURL, s.gateway.Name()),
+		ExpireAt:  order.ExpiresAt,
+	})
+	if err != nil {
+		logger.Error(ctx, "发起支付失败", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+		return nil, fmt.Errorf("发起支付失败: %w", err)
+	}
+	if err := s.orderRepo.SetPayment(ctx, order.ID, s.gateway.Name(), prepay.TradeNo); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "发起支付", logger.String("order_no", order.OrderNo), logger.String("trade_no", prepay.TradeNo))
+	return prepay, nil
+}
+
+// CancelOrder 取消待支付订单
+func (s *orderService) CancelOrder(ctx context.Context, userID, id uint, reason string) (*model.Order, error) {
+	order, err := s.GetOrder(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil, errors.New("只能取消待支付的订单")
+	}
+	if reason == "" {
+		reason = "用户取消"
+	}
+	if err := s.orderRepo.Cancel(ctx, order, reason); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ConfirmOrder 用户确认收货/服务完成
+func (s *orderService) ConfirmOrder(ctx context.Context, userID, id uint) (*model.Order, error) {
+	order, err := s.GetOrder(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusPaid {
+		return nil, errors.New("只能确认已支付的订单")
+	}
+	if err := s.orderRepo.Fulfill(ctx, order.ID); err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, userID, id)
+}
+
+// RequestRefund 用户对已支付未完成的订单申请全额退款
+func (s *orderService) RequestRefund(ctx context.Context, userID, id uint, reason string) (*model.Order, error) {
+	order, err := s.GetOrder(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusPaid {
+		return nil, errors.New("订单已完成或未支付,无法自助退款")
+	}
+	amount := order.Refundable()
+	if amount <= 0 {
+		return nil, errors.New("订单没有可退款金额")
+	}
+	if reason == "" {
+		reason = "用户申请退款"
+	}
+	if _, err := s.refund(ctx, order, amount, reason, userID); err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, userID, id)
+}
+
+// SimulatePayment 使用模拟网关完成支付,仅在fake网关下可用
+func (s *orderService) SimulatePayment(ctx context.Context, userID, id uint) (*model.Order, error) {
+	fake, ok := s.gateway.(*payment.FakeGateway)
+	if !ok {
+		return nil, notFound("当前支付网关不支持模拟支付")
+	}
+	order, err := s.GetOrder(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	tradeNo := order.TradeNo
+	if tradeNo == "" {
+		prepay, err := s.Pay(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+		tradeNo = prepay.TradeNo
+	}
+	body, signature, err := fake.BuildCallback(order.OrderNo, tradeNo, order.PayAmount)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.HandleCallback(ctx, fake.Name(), body, signature); err != nil {
+		return nil, err
+	}
+	return s.GetOrder(ctx, userID, id)
+}
+
+// HandleCallback 处理网关回调,同一事件重复通知只处理一次
+func (s *orderService) HandleCallback(ctx context.Context, gateway string, body []byte, signature string) error {
+	if gateway != s.gateway.Name() {
+		return notFound("支付网关不存在")
+	}
+	event, err := s.gateway.ParseCallback(body, signature)
+	if err != nil {
+		logger.Warn(ctx, "支付回调校验失败", logger.String("gateway", gateway), logger.ErrorField(err))
+		return err
+	}
+	if event.EventID == "" {
+		return errors.New("支付回调缺少事件ID")
+	}
+
+	processed, err := s.orderRepo.EventProcessed(ctx, gateway, event.EventID)
+	if err != nil {
+		return err
+	}
+	if processed {
+		logger.Info(ctx, "支付回调已处理,忽略重复通知", logger.String("event_id", event.EventID))
+		return nil
+	}
+
+	switch event.Type {
+	case payment.EventPaymentSucceeded:
+		err = s.handlePaid(ctx, event)
+	case payment.EventRefundSucceeded:
+		err = s.handleRefunded(ctx, event)
+	default:
+		logger.Warn(ctx, "忽略未知的支付回调事件", logger.String("type", event.Type), logger.String("event_id", event.EventID))
+	}
+	if err != nil {
+		return err
+	}
+
+	// 状态流转均为条件更新,并发的重复回调即使同时通过上面的检查也只会生效一次
+	// 业务处理成功后才记录事件,处理失败时网关重发的通知会重新处理
+	return s.orderRepo.SaveEvent(ctx, &model.PaymentEvent{
+		Gateway: gateway,
+		EventID: event.EventID,
+		Type:    event.Type,
+		OrderNo: event.OrderNo,
+		Payload: string(body),
+	})
+}
+
+// handlePaid 处理支付成功通知,订单已取消时原路退回
+func (s *orderService) handlePaid(ctx context.Context, event *payment.CallbackEvent) error {
+	order, err := s.orderRepo.GetByNo(ctx, event.OrderNo)
+	if err != nil {
+		return checkNotFound(err, "订单不存在")
+	}
+	if event.Amount != order.PayAmount {
+		logger.Error(ctx, "支付金额与订单不符",
+			logger.String("order_no", order.OrderNo),
+			logger.Int64("amount", event.Amount),
+			logger.Int64("pay_amount", order.PayAmount),
+		)
+		return errors.New("支付金额与订单不符")
+	}
+	paidAt := event.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+
+	changed, err := s.orderRepo.MarkPaid(ctx, order.OrderNo, event.TradeNo, paidAt)
+	if err != nil {
+		return err
+	}
+	if changed {
+		order.Status = model.OrderStatusPaid
+		order.TradeNo = event.TradeNo
+		order.PaidAt = &paidAt
+	}
+	// 订单已由本次付款支付但上次通知的业务回调未全部成功时,重发的通知再次执行回调
+	if changed || (order.TradeNo == event.TradeNo &&
+		(order.Status == model.OrderStatusPaid || order.Status == model.OrderStatusFulfilled)) {
+		for _, hook := range s.paidHooks[order.Type] {
+			if err := hook(ctx, order); err != nil {
+				logger.Error(ctx, "订单支付回调执行失败,等待网关重发通知", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+				return err
+			}
+		}
+		return nil
+	}
+
+	late, err := s.orderRepo.RecordLatePayment(ctx, order.OrderNo, event.TradeNo, paidAt)
+	if err != nil {
+		return err
+	}
+	if late {
+		order.TradeNo = event.TradeNo
+		order.PaidAt = &paidAt
+		if _, err := s.refund(ctx, order, order.PayAmount, "订单已取消,自动退款", 0); err != nil {
+			logger.Error(ctx, "取消订单自动退款失败", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+		}
+	}
+	return nil
+}
+
+// handleRefunded 处理网关异步退款成功通知
+func (s *orderService) handleRefunded(ctx context.Context, event *payment.CallbackEvent) error {
+	refund, err := s.orderRepo.GetRefundByNo(ctx, event.RefundNo)
+	if err != nil {
+		return checkNotFound(err, "退款记录不存在")
+	}
+	if refund.Status != model.RefundStatusPending {
+		return nil
+	}
+	if event.TradeNo != "" {
+		refund.GatewayRefundNo = event.TradeNo
+	}
+	return s.orderRepo.CompleteRefund(ctx, refund)
+}
+
+// AdminGetOrder 管理端获取订单
+func (s *orderService) AdminGetOrder(ctx context.Context, id uint) (*model.Order, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "订单不存在")
+	}
+	return order, nil
+}
+
+// AdminListOrders 管理端订单列表
+func (s *orderService) AdminListOrders(ctx context.Context, req *model.ListOrderRequest) ([]*model.Order, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.orderRepo.List(ctx, &repository.OrderFilter{
+		UserID: req.UserID,
+		Status: req.Status,
+		Type:   req.Type,
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+// FulfillOrder 管理端发货/完成订单
+func (s *orderService) FulfillOrder(ctx context.Context, id uint) (*model.Order, error) {
+	order, err := s.AdminGetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusPaid {
+		return nil, errors.New("只能完成已支付的订单")
+	}
+	if err := s.orderRepo.Fulfill(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.AdminGetOrder(ctx, id)
+}
+
+// RefundOrder 管理端退款,支持部分退款,金额为0时退还全部剩余金额
+func (s *orderService) RefundOrder(ctx context.Context, operatorID, id uint, req *model.RefundOrderRequest) (*model.Order, error) {
+	order, err := s.AdminGetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	refundable := order.Refundable()
+	if refundable <= 0 {
+		return nil, errors.New("订单没有可退款金额")
+	}
+	amount := req.Amount
+	if amount == 0 {
+		amount = refundable
+	}
+	if amount < 0 || amount > refundable {
+		return nil, fmt.Errorf("退款金额需在1-%d分之间", refundable)
+	}
+	reason := req.Reason
+	if reason == "" {
+		reason = "商家退款"
+	}
+	if _, err := s.refund(ctx, order, amount, reason, operatorID); err != nil {
+		return nil, err
+	}
+	return s.AdminGetOrder(ctx, id)
+}
+
+// refund 占用可退金额后调用网关退款,网关失败时释放占用的金额
+func (s *orderService) refund(ctx context.Context, order *model.Order, amount int64, reason string, operatorID uint) (*model.Refund, error) {
+	refundNo, err := newOrderNo("R")
+	if err != nil {
+		return nil, err
+	}
+	refund := &model.Refund{
+		RefundNo:   refundNo,
+		OrderID:    order.ID,
+		Amount:     amount,
+		Reason:     truncate(reason, 255),
+		Status:     model.RefundStatusPending,
+		OperatorID: operatorID,
+	}
+	if err := s.orderRepo.ReserveRefund(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	result, err := s.gateway.Refund(ctx, &payment.RefundRequest{
+		OrderNo:     order.OrderNo,
+		TradeNo:     order.TradeNo,
+		RefundNo:    refund.RefundNo,
+		Amount:      amount,
+		TotalAmount: order.PayAmount,
+		Reason:      refund.Reason,
+	})
+	if err != nil {
+		logger.Error(ctx, "网关退款失败", logger.String("refund_no", refund.RefundNo), logger.ErrorField(err))
+		refund.FailReason = truncate(err.Error(), 255)
+		_ = s.orderRepo.FailRefund(ctx, refund)
+		return nil, fmt.Errorf("退款失败: %w", err)
+	}
+
+	refund.GatewayRefundNo = result.GatewayRefundNo
+	if result.Status == payment.RefundSucceeded {
+		if err := s.orderRepo.CompleteRefund(ctx, refund); err != nil {
+			return nil, err
+		}
+	}
+	logger.Info(ctx, "发起退款",
+		logger.String("order_no", order.OrderNo),
+		logger.String("refund_no", refund.RefundNo),
+		logger.Int64("amount", amount),
+		logger.String("status", result.Status),
+	)
+	return refund, nil
+}
+
+// RunExpirationWorker 后台取消超时未支付的订单,ctx取消后退出
+func (s *orderService) RunExpirationWorker(ctx context.Context) {
+	ticker := time.NewTicker(orderExpireInterval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "超时订单取消任务已启动")
+	for {
+		s.cancelExpired(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "超时订单取消任务已停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cancelExpired 取消一批超时订单,与支付回调并发时以先完成的状态更新为准
+func (s *orderService) cancelExpired(ctx context.Context, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "超时订单取消异常", logger.Any("panic", r))
+		}
+	}()
+
+	orders, err := s.orderRepo.ListExpiredPending(ctx, now, orderExpireBatch)
+	if err != nil {
+		return
+	}
+	for _, order := range orders {
+		_ = s.orderRepo.Cancel(ctx, order, "超时未支付")
+	}
+	if len(orders) > 0 {
+		logger.Info(ctx, "取消超时订单", logger.Int("count", len(orders)))
+	}
+}
+
+// newOrderNo 生成订单号:前缀+时间+6位随机数
+func newOrderNo(prefix string) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s%06d", prefix, time.Now().Format("20060102150405"), n.Int64()), nil
+}
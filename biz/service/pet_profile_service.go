@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/qrcode"
+)
+
+// slugEncoding 小写base32,12字节随机数编码为20位slug
+var slugEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// PetProfileService 宠物公开主页服务接口
+type PetProfileService interface {
+	GetSettings(ctx context.Context, userID, petID uint) (*model.PetProfile, error)
+	UpdateSettings(ctx context.Context, userID, petID uint, req *model.UpdatePetProfileRequest) (*model.PetProfile, error)
+	RegenerateSlug(ctx context.Context, userID, petID uint) (*model.PetProfile, error)
+	GetPublic(ctx context.Context, slug string) (*model.PublicPetProfile, error)
+	QRCode(ctx context.Context, userID, petID uint, req *model.QRCodeRequest) ([]byte, string, error)
+}
+
+// petProfileService 宠物公开主页服务实现
+type petProfileService struct {
+	profileRepo repository.PetProfileRepository
+	petRepo     repository.PetRepository
+	userRepo    repository.UserRepository
+	petService  PetService
+	baseURL     string
+}
+
+// NewPetProfileService 创建宠物公开主页服务,baseURL为对外访问地址
+func NewPetProfileService(profileRepo repository.PetProfileRepository, petRepo repository.PetRepository, userRepo repository.UserRepository, petService PetService, baseURL string) PetProfileService {
+	return &petProfileService{
+		profileRepo: profileRepo,
+		petRepo:     petRepo,
+		userRepo:    userRepo,
+		petService:  petService,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GetSettings 获取公开主页设置,未设置过时返回默认值
+func (s *petProfileService) GetSettings(ctx context.Context, userID, petID uint) (*model.PetProfile, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	profile, err := s.profileRepo.GetByPetID(ctx, petID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultProfile(petID), nil
+		}
+		return nil, err
+	}
+	s.fillURL(profile)
+	return profile, nil
+}
+
+// UpdateSettings 更新公开主页设置,涉及主人联系方式,需owner角色
+func (s *petProfileService) UpdateSettings(ctx context.Context, userID, petID uint, req *model.UpdatePetProfileRequest) (*model.PetProfile, error) {
+	profile, err := s.getOrCreate(ctx, userID, petID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		profile.Enabled = *req.Enabled
+	}
+	if req.ShowName != nil {
+		profile.ShowName = *req.ShowName
+	}
+	if req.ShowPhoto != nil {
+		profile.ShowPhoto = *req.ShowPhoto
+	}
+	if req.ShowMedicalAlert != nil {
+		profile.ShowMedicalAlert = *req.ShowMedicalAlert
+	}
+	if req.ShowContact != nil {
+		profile.ShowContact = *req.ShowContact
+	}
+	if req.MedicalAlert != nil {
+		profile.MedicalAlert = strings.TrimSpace(*req.MedicalAlert)
+	}
+	if req.ContactNote != nil {
+		profile.ContactNote = strings.TrimSpace(*req.ContactNote)
+	}
+
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "更新公开主页设置", logger.Int("pet_id", int(petID)), logger.Any("enabled", profile.Enabled))
+	s.fillURL(profile)
+	return profile, nil
+}
+
+// RegenerateSlug 重新生成slug,旧的链接和已打印的二维码立即失效
+func (s *petProfileService) RegenerateSlug(ctx context.Context, userID, petID uint) (*model.PetProfile, error) {
+	profile, err := s.getOrCreate(ctx, userID, petID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Slug, err = newSlug(); err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "重新生成公开主页slug", logger.Int("pet_id", int(petID)))
+	s.fillURL(profile)
+	return profile, nil
+}
+
+// GetPublic 获取公开主页,只返回主人允许公开的字段
+func (s *petProfileService) GetPublic(ctx context.Context, slug string) (*model.PublicPetProfile, error) {
+	profile, err := s.profileRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, checkNotFound(err, "主页不存在")
+	}
+	if !profile.Enabled {
+		return nil, notFound("主页不存在")
+	}
+	pet, err := s.petRepo.GetByID(ctx, profile.PetID)
+	if err != nil {
+		return nil, checkNotFound(err, "主页不存在")
+	}
+
+	public := &model.PublicPetProfile{
+		Slug:         profile.Slug,
+		Species:      pet.Species,
+		Breed:        pet.Breed,
+		Color:        pet.Color,
+		HasMicrochip: pet.Microchip != nil,
+	}
+	if profile.ShowName {
+		public.Name = pet.Name
+	}
+	if profile.ShowPhoto {
+		public.Photo = pet.Avatar
+	}
+	if profile.ShowMedicalAlert {
+		public.MedicalAlert = profile.MedicalAlert
+	}
+	if profile.ShowContact && pet.OwnerID != 0 {
+		if owner, err := s.userRepo.GetByID(ctx, pet.OwnerID); err == nil {
+			name := owner.Nickname
+			if name == "" {
+				name = owner.Username
+			}
+			public.Contact = &model.PublicContact{
+				Name:  name,
+				Phone: owner.Phone,
+				Email: owner.Email,
+				Note:  profile.ContactNote,
+			}
+		}
+	}
+	return public, nil
+}
+
+// QRCode 生成指向公开主页的二维码,用于打印在项圈吊牌上
+func (s *petProfileService) QRCode(ctx context.Context, userID, petID uint, req *model.QRCodeRequest) ([]byte, string, error) {
+	// 绑定时不会校验尺寸和填充默认值,需在生成前检查
+	size := req.Size
+	if size == 0 {
+		size = qrcode.DefaultSize
+	}
+	if size < qrcode.MinSize || size > qrcode.MaxSize {
+		return nil, "", invalidParam(fmt.Sprintf("二维码尺寸须在%d-%d之间", qrcode.MinSize, qrcode.MaxSize))
+	}
+	if req.Format != "" && req.Format != model.QRFormatPNG && req.Format != model.QRFormatSVG {
+		return nil, "", invalidParam("二维码格式只支持png和svg")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, "", err
+	}
+	profile, err := s.profileRepo.GetByPetID(ctx, petID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("请先开启公开主页")
+		}
+		return nil, "", err
+	}
+	s.fillURL(profile)
+
+	if req.Format == model.QRFormatSVG {
+		data, err := qrcode.SVG(profile.URL, size)
+		return data, "image/svg+xml", err
+	}
+	data, err := qrcode.PNG(profile.URL, size)
+	return data, "image/png", err
+}
+
+// getOrCreate 校验owner角色并获取公开主页设置,不存在时创建默认设置
+func (s *petProfileService) getOrCreate(ctx context.Context, userID, petID uint) (*model.PetProfile, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleOwner); err != nil {
+		return nil, err
+	}
+	profile, err := s.profileRepo.GetByPetID(ctx, petID)
+	if err == nil {
+		return profile, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	profile = defaultProfile(petID)
+	if profile.Slug, err = newSlug(); err != nil {
+		return nil, err
+	}
+	if err := s.profileRepo.Create(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// fillURL 填充公开主页链接
+func (s *petProfileService) fillURL(profile *model.PetProfile) {
+	if profile.Slug != "" {
+		profile.URL = s.baseURL + "/p/" + profile.Slug
+	}
+}
+
+// defaultProfile 默认设置:不公开,开启后默认只展示名字和照片
+func defaultProfile(petID uint) *model.PetProfile {
+	return &model.PetProfile{
+		PetID:     petID,
+		ShowName:  true,
+		ShowPhoto: true,
+	}
+}
+
+// newSlug 生成96位随机slug
+func newSlug() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return slugEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/microchip"
+)
+
+// PetService 宠物服务接口
+type PetService interface {
+	CreatePet(ctx context.Context, userID uint, req *model.CreatePetRequest) (*model.Pet, error)
+	UpdatePet(ctx context.Context, userID, petID uint, req *model.UpdatePetRequest) (*model.Pet, error)
+	DeletePet(ctx context.Context, userID, petID uint) error
+	GetPet(ctx context.Context, userID, petID uint) (*model.Pet, error)
+	ListMyPets(ctx context.Context, userID uint, req *model.ListPetRequest) ([]*model.Pet, int64, error)
+	Authorize(ctx context.Context, userID, petID uint, required string) (*model.Pet, error)
+}
+
+// petService 宠物服务实现
+type petService struct {
+	petRepo   repository.PetRepository
+	shareRepo repository.PetShareRepository
+}
+
+// NewPetService 创建宠物服务
+func NewPetService(petRepo repository.PetRepository, shareRepo repository.PetShareRepository) PetService {
+	return &petService{petRepo: petRepo, shareRepo: shareRepo}
+}
+
+// CreatePet 创建宠物档案
+func (s *petService) CreatePet(ctx context.Context, userID uint, req *model.CreatePetRequest) (*model.Pet, error) {
+	pet := newPetFromRequest(req)
+	pet.OwnerID = userID
+	if err := s.assignMicrochip(ctx, pet, req.Microchip); err != nil {
+		return nil, err
+	}
+	if err := s.petRepo.Create(ctx, pet); err != nil {
+		return nil, err
+	}
+	pet.Role = model.PetRoleOwner
+	return pet, nil
+}
+
+// UpdatePet 更新宠物档案
+func (s *petService) UpdatePet(ctx context.Context, userID, petID uint, req *model.UpdatePetRequest) (*model.Pet, error) {
+	pet, err := s.Authorize(ctx, userID, petID, model.PetRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		pet.Name = req.Name
+	}
+	if req.Breed != "" {
+		pet.Breed = req.Breed
+	}
+	if req.Gender != "" {
+		pet.Gender = req.Gender
+	}
+	if req.BirthDate != nil {
+		pet.BirthDate = req.BirthDate
+	}
+	if req.Color != "" {
+		pet.Color = req.Color
+	}
+	if req.Size != "" {
+		pet.Size = req.Size
+	}
+	if req.Avatar != "" {
+		pet.Avatar = req.Avatar
+	}
+	if req.Description != "" {
+		pet.Description = req.Description
+	}
+	if err := s.assignMicrochip(ctx, pet, req.Microchip); err != nil {
+		return nil, err
+	}
+
+	if err := s.petRepo.Update(ctx, pet); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "宠物更新成功", logger.Int("id", int(petID)))
+	return pet, nil
+}
+
+// DeletePet 删除宠物档案,需owner角色
+func (s *petService) DeletePet(ctx context.Context, userID, petID uint) error {
+	if _, err := s.Authorize(ctx, userID, petID, model.PetRoleOwner); err != nil {
+		return err
+	}
+	return checkNotFound(s.petRepo.Delete(ctx, petID), "宠物不存在")
+}
+
+// GetPet 获取宠物详情
+func (s *petService) GetPet(ctx context.Context, userID, petID uint) (*model.Pet, error) {
+	return s.Authorize(ctx, userID, petID, model.PetRoleViewer)
+}
+
+// ListMyPets 获取当前用户拥有及共享给自己的宠物列表
+func (s *petService) ListMyPets(ctx context.Context, userID uint, req *model.ListPetRequest) ([]*model.Pet, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	pets, total, err := s.petRepo.ListAccessible(ctx, userID, req.Species, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sharedIDs := make([]uint, 0, len(pets))
+	for _, pet := range pets {
+		if pet.OwnerID != userID {
+			sharedIDs = append(sharedIDs, pet.ID)
+		}
+	}
+	roles, err := s.shareRepo.ListRolesByUser(ctx, userID, sharedIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, pet := range pets {
+		if pet.OwnerID == userID {
+			pet.Role = model.PetRoleOwner
+		} else {
+			pet.Role = roles[pet.ID]
+		}
+	}
+	return pets, total, nil
+}
+
+// Authorize 获取宠物并校验当前用户的角色不低于required,主人始终视为owner
+// 所有宠物相关的服务都通过该方法做权限校验
+func (s *petService) Authorize(ctx context.Context, userID, petID uint, required string) (*model.Pet, error) {
+	pet, err := s.petRepo.GetByID(ctx, petID)
+	if err != nil {
+		return nil, checkNotFound(err, "宠物不存在")
+	}
+
+	if pet.OwnerID == userID {
+		pet.Role = model.PetRoleOwner
+		return pet, nil
+	}
+
+	member, err := s.shareRepo.GetMember(ctx, petID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if member == nil {
+		logger.Warn(ctx, "无权访问宠物", logger.Int("pet_id", int(petID)), logger.Int("user_id", int(userID)))
+		return nil, forbidden("无权访问该宠物")
+	}
+	if !model.PetRoleAtLeast(member.Role, required) {
+		logger.Warn(ctx, "宠物角色权限不足", logger.Int("pet_id", int(petID)), logger.Int("user_id", int(userID)), logger.String("role", member.Role))
+		return nil, forbidden("当前角色无权执行该操作")
+	}
+	pet.Role = member.Role
+	return pet, nil
+}
+
+// assignMicrochip 校验芯片号格式与唯一性后写入宠物,code为空时不修改
+func (s *petService) assignMicrochip(ctx context.Context, pet *model.Pet, code string) error {
+	if code == "" {
+		return nil
+	}
+	code = microchip.Normalize(code)
+	if err := microchip.Validate(code); err != nil {
+		return err
+	}
+	existing, err := s.petRepo.GetByMicrochip(ctx, code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != pet.ID {
+		return errors.New("该芯片号已被其他宠物登记")
+	}
+	pet.Microchip = &code
+	return nil
+}
+
+// newPetFromRequest 根据请求构造宠物档案
+func newPetFromRequest(req *model.CreatePetRequest) *model.Pet {
+	gender := req.Gender
+	if gender == "" {
+		gender = "unknown"
+	}
+	return &model.Pet{
+		Name:        req.Name,
+		Species:     req.Species,
+		Breed:       req.Breed,
+		Gender:      gender,
+		BirthDate:   req.BirthDate,
+		Color:       req.Color,
+		Size:        req.Size,
+		Avatar:      req.Avatar,
+		Description: req.Description,
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+)
+
+// invitationTTL 共享邀请有效期
+const invitationTTL = 7 * 24 * time.Hour
+
+// PetShareService 宠物共享服务接口
+type PetShareService interface {
+	Invite(ctx context.Context, userID, petID uint, req *model.CreateInvitationRequest) (*model.PetInvitation, error)
+	ListPetInvitations(ctx context.Context, userID, petID uint, req *model.ListShareRequest) ([]*model.PetInvitation, error)
+	ListMyInvitations(ctx context.Context, userID uint, req *model.ListShareRequest) ([]*model.PetInvitation, error)
+	AcceptInvitation(ctx context.Context, userID, invitationID uint) error
+	DeclineInvitation(ctx context.Context, userID, invitationID uint) error
+	CancelInvitation(ctx context.Context, userID, invitationID uint) error
+
+	ListMembers(ctx context.Context, userID, petID uint) ([]*model.PetMember, error)
+	UpdateMemberRole(ctx context.Context, userID, petID, memberUserID uint, req *model.UpdateMemberRoleRequest) error
+	RemoveMember(ctx context.Context, userID, petID, memberUserID uint) error
+
+	RequestTransfer(ctx context.Context, userID, petID uint, req *model.CreateTransferRequest) (*model.PetTransfer, error)
+	ConfirmTransfer(ctx context.Context, userID, transferID uint) (*model.PetTransfer, error)
+	DeclineTransfer(ctx context.Context, userID, transferID uint) error
+	ListMyTransfers(ctx context.Context, userID uint, req *model.ListShareRequest) ([]*model.PetTransfer, error)
+}
+
+// petShareService 宠物共享服务实现
+type petShareService struct {
+	shareRepo  repository.PetShareRepository
+	userRepo   repository.UserRepository
+	petService PetService
+}
+
+// NewPetShareService 创建宠物共享服务
+func NewPetShareService(shareRepo repository.PetShareRepository, userRepo repository.UserRepository, petService PetService) PetShareService {
+	return &petShareService{
+		shareRepo:  shareRepo,
+		userRepo:   userRepo,
+		petService: petService,
+	}
+}
+
+// Invite 通过用户名或邮箱邀请用户共享宠物,需owner角色
+func (s *petShareService) Invite(ctx context.Context, userID, petID uint, req *model.CreateInvitationRequest) (*model.PetInvitation, error) {
+	if !model.ValidPetRole(req.Role) {
+		return nil, invalidParam("角色不合法")
+	}
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.resolveUser(ctx, req.Username, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if invitee.ID == pet.OwnerID || invitee.ID == userID {
+		return nil, errors.New("不能邀请宠物主人或自己")
+	}
+	if _, err := s.shareRepo.GetMember(ctx, petID, invitee.ID); err == nil {
+		return nil, errors.New("该用户已是共享成员,请直接修改角色")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	pending, err := s.shareRepo.HasPendingInvitation(ctx, petID, invitee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("已向该用户发送过邀请,请等待处理")
+	}
+
+	invitation := &model.PetInvitation{
+		PetID:     petID,
+		InviterID: userID,
+		InviteeID: invitee.ID,
+		Role:      req.Role,
+		Status:    model.ShareStatusPending,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	}
+	if err := s.shareRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListPetInvitations 获取宠物发出的邀请,需owner角色
+func (s *petShareService) ListPetInvitations(ctx context.Context, userID, petID uint, req *model.ListShareRequest) ([]*model.PetInvitation, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleOwner); err != nil {
+		return nil, err
+	}
+	return s.shareRepo.ListInvitationsByPet(ctx, petID, req.Status)
+}
+
+// ListMyInvitations 获取我收到的邀请
+func (s *petShareService) ListMyInvitations(ctx context.Context, userID uint, req *model.ListShareRequest) ([]*model.PetInvitation, error) {
+	return s.shareRepo.ListInvitationsByInvitee(ctx, userID, req.Status)
+}
+
+// AcceptInvitation 接受邀请
+func (s *petShareService) AcceptInvitation(ctx context.Context, userID, invitationID uint) error {
+	invitation, err := s.getPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.InviteeID != userID {
+		return forbidden("无权处理该邀请")
+	}
+	if invitation.Pet == nil || invitation.Pet.IsDeleted == 1 {
+		return notFound("宠物不存在")
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return errors.New("邀请已过期")
+	}
+
+	now := time.Now()
+	invitation.RespondedAt = &now
+	return s.shareRepo.AcceptInvitation(ctx, invitation)
+}
+
+// DeclineInvitation 拒绝邀请
+func (s *petShareService) DeclineInvitation(ctx context.Context, userID, invitationID uint) error {
+	invitation, err := s.getPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.InviteeID != userID {
+		return forbidden("无权处理该邀请")
+	}
+	return s.respondInvitation(ctx, invitation, model.ShareStatusDeclined)
+}
+
+// CancelInvitation 撤回邀请,邀请人或宠物owner角色可操作
+func (s *petShareService) CancelInvitation(ctx context.Context, userID, invitationID uint) error {
+	invitation, err := s.getPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.InviterID != userID {
+		if _, err := s.petService.Authorize(ctx, userID, invitation.PetID, model.PetRoleOwner); err != nil {
+			return err
+		}
+	}
+	return s.respondInvitation(ctx, invitation, model.ShareStatusCancelled)
+}
+
+// ListMembers 获取宠物共享成员
+func (s *petShareService) ListMembers(ctx context.Context, userID, petID uint) ([]*model.PetMember, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.shareRepo.ListMembers(ctx, petID)
+}
+
+// UpdateMemberRole 修改成员角色,需owner角色
+func (s *petShareService) UpdateMemberRole(ctx context.Context, userID, petID, memberUserID uint, req *model.UpdateMemberRoleRequest) error {
+	if !model.ValidPetRole(req.Role) {
+		return invalidParam("角色不合法")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleOwner); err != nil {
+		return err
+	}
+	if memberUserID == userID {
+		return errors.New("不能修改自己的角色")
+	}
+	return checkNotFound(s.shareRepo.UpdateMemberRole(ctx, petID, memberUserID, req.Role), "成员不存在")
+}
+
+// RemoveMember 移除成员,owner角色可移除他人,成员可自行退出
+func (s *petShareService) RemoveMember(ctx context.Context, userID, petID, memberUserID uint) error {
+	required := model.PetRoleOwner
+	if memberUserID == userID {
+		required = model.PetRoleViewer
+	}
+	pet, err := s.petService.Authorize(ctx, userID, petID, required)
+	if err != nil {
+		return err
+	}
+	if memberUserID == pet.OwnerID {
+		return errors.New("宠物主人不能退出,请先转让主人")
+	}
+	return checkNotFound(s.shareRepo.DeleteMember(ctx, petID, memberUserID), "成员不存在")
+}
+
+// RequestTransfer 发起主人转让
+// 当前主人指定接收方时视为转出方已确认;owner角色的共同主人可申请将自己设为主人,视为接收方已确认
+func (s *petShareService) RequestTransfer(ctx context.Context, userID, petID uint, req *model.CreateTransferRequest) (*model.PetTransfer, error) {
+	pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	transfer := &model.PetTransfer{
+		PetID:      petID,
+		FromUserID: pet.OwnerID,
+		Status:     model.ShareStatusPending,
+	}
+	if pet.OwnerID == userID {
+		if req.Username == "" && req.Email == "" {
+			return nil, errors.New("请指定接收方")
+		}
+		recipient, err := s.resolveUser(ctx, req.Username, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if recipient.ID == userID {
+			return nil, errors.New("不能转让给自己")
+		}
+		transfer.ToUserID = recipient.ID
+		transfer.FromConfirmed = true
+	} else {
+		if req.Username != "" || req.Email != "" {
+			return nil, forbidden("只有宠物主人可以指定接收方")
+		}
+		transfer.ToUserID = userID
+		transfer.ToConfirmed = true
+	}
+
+	pending, err := s.shareRepo.HasPendingTransfer(ctx, petID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("该宠物已有进行中的转让")
+	}
+
+	if err := s.shareRepo.CreateTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// ConfirmTransfer 转让双方确认,双方均确认后完成转让
+func (s *petShareService) ConfirmTransfer(ctx context.Context, userID, transferID uint) (*model.PetTransfer, error) {
+	transfer, err := s.getPendingTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case userID == transfer.FromUserID && !transfer.FromConfirmed:
+		transfer.FromConfirmed = true
+	case userID == transfer.ToUserID && !transfer.ToConfirmed:
+		transfer.ToConfirmed = true
+	case userID == transfer.FromUserID || userID == transfer.ToUserID:
+		return nil, errors.New("您已确认,请等待对方确认")
+	default:
+		return nil, forbidden("无权处理该转让")
+	}
+
+	if !transfer.FromConfirmed || !transfer.ToConfirmed {
+		if err := s.shareRepo.UpdateTransfer(ctx, transfer); err != nil {
+			return nil, err
+		}
+		return transfer, nil
+	}
+
+	now := time.Now()
+	transfer.CompletedAt = &now
+	if err := s.shareRepo.CompleteTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+	transfer.Status = model.ShareStatusAccepted
+	return transfer, nil
+}
+
+// DeclineTransfer 拒绝或撤回转让,双方均可操作
+func (s *petShareService) DeclineTransfer(ctx context.Context, userID, transferID uint) error {
+	transfer, err := s.getPendingTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+	if userID != transfer.FromUserID && userID != transfer.ToUserID {
+		return forbidden("无权处理该转让")
+	}
+
+	// 发起方撤回记为cancelled,另一方拒绝记为declined
+	initiator := transfer.FromUserID
+	if !transfer.FromConfirmed {
+		initiator = transfer.ToUserID
+	}
+	transfer.Status = model.ShareStatusDeclined
+	if userID == initiator {
+		transfer.Status = model.ShareStatusCancelled
+	}
+	return s.shareRepo.UpdateTransfer(ctx, transfer)
+}
+
+// ListMyTransfers 获取我参与的转让
+func (s *petShareService) ListMyTransfers(ctx context.Context, userID uint, req *model.ListShareRequest) ([]*model.PetTransfer, error) {
+	return s.shareRepo.ListTransfersByUser(ctx, userID, req.Status)
+}
+
+// resolveUser 根据用户名或邮箱查找用户
+func (s *petShareService) resolveUser(ctx context.Context, username, email string) (*model.User, error) {
+	var (
+		user *model.User
+		err  error
+	)
+	switch {
+	case username != "":
+		user, err = s.userRepo.GetByUsername(ctx, username)
+	case email != "":
+		user, err = s.userRepo.GetByEmail(ctx, email)
+	default:
+		return nil, errors.New("用户名和邮箱不能同时为空")
+	}
+	if err != nil {
+		return nil, notFound("用户不存在")
+	}
+	return user, nil
+}
+
+// getPendingInvitation 获取待处理的邀请
+func (s *petShareService) getPendingInvitation(ctx context.Context, invitationID uint) (*model.PetInvitation, error) {
+	invitation, err := s.shareRepo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, checkNotFound(err, "邀请不存在")
+	}
+	if invitation.Status != model.ShareStatusPending {
+		return nil, errors.New("邀请已处理")
+	}
+	return invitation, nil
+}
+
+// respondInvitation 将待处理邀请更新为指定状态
+func (s *petShareService) respondInvitation(ctx context.Context, invitation *model.PetInvitation, status string) error {
+	now := time.Now()
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	if err := s.shareRepo.UpdateInvitationStatus(ctx, invitation, model.ShareStatusPending); err != nil {
+		return err
+	}
+	logger.Info(ctx, "共享邀请已处理", logger.Int("id", int(invitation.ID)), logger.String("status", status))
+	return nil
+}
+
+// getPendingTransfer 获取进行中的转让
+func (s *petShareService) getPendingTransfer(ctx context.Context, transferID uint) (*model.PetTransfer, error) {
+	transfer, err := s.shareRepo.GetTransfer(ctx, transferID)
+	if err != nil {
+		return nil, checkNotFound(err, "转让不存在")
+	}
+	if transfer.Status != model.ShareStatusPending {
+		return nil, errors.New("转让已处理")
+	}
+	return transfer, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+)
+
+const (
+	// pointsPerYuan 每实付1元获得的积分
+	pointsPerYuan = 1
+	// pointValue 每积分抵扣金额(分)
+	pointValue = 1
+	// maxPointsPercent 积分最多抵扣订单金额(扣除优惠券后)的百分比
+	maxPointsPercent = 50
+	// appointmentPoints 完成一次门诊预约获得的积分
+	appointmentPoints = 20
+)
+
+// PointsService 积分服务接口
+type PointsService interface {
+	GetMyPoints(ctx context.Context, userID uint, req *model.ListPointsRequest) (*model.PointsSummary, error)
+	EarnForOrder(ctx context.Context, order *model.Order) error
+	EarnForAppointment(ctx context.Context, userID, appointmentID uint)
+}
+
+// pointsService 积分服务实现
+type pointsService struct {
+	pointsRepo repository.PointsRepository
+}
+
+// NewPointsService 创建积分服务
+func NewPointsService(pointsRepo repository.PointsRepository) PointsService {
+	return &pointsService{pointsRepo: pointsRepo}
+}
+
+// GetMyPoints 积分余额与流水
+func (s *pointsService) GetMyPoints(ctx context.Context, userID uint, req *model.ListPointsRequest) (*model.PointsSummary, error) {
+	account, err := s.pointsRepo.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	entries, total, err := s.pointsRepo.ListTransactions(ctx, userID, req.Type, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PointsSummary{
+		Balance:      account.Balance,
+		TotalEarned:  account.TotalEarned,
+		Transactions: entries,
+		Total:        total,
+	}, nil
+}
+
+// EarnForOrder 订单支付成功后按实付金额发放积分,作为订单支付回调注册
+// 同一订单重复通知只发放一次,发放失败时返回错误,由网关重发通知重试
+func (s *pointsService) EarnForOrder(ctx context.Context, order *model.Order) error {
+	points := order.PayAmount / 100 * pointsPerYuan
+	if points <= 0 {
+		return nil
+	}
+	_, err := s.pointsRepo.Change(ctx, &model.PointsTransaction{
+		UserID:     order.UserID,
+		Type:       model.PointsEarnOrder,
+		SourceType: model.PointsSourceOrder,
+		SourceID:   order.ID,
+		Delta:      points,
+		Remark:     "订单" + order.OrderNo + "支付",
+	})
+	if err != nil {
+		logger.Error(ctx, "发放订单积分失败", logger.String("order_no", order.OrderNo), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// EarnForAppointment 门诊预约完成后发放积分,同一预约只发放一次
+func (s *pointsService) EarnForAppointment(ctx context.Context, userID, appointmentID uint) {
+	_, err := s.pointsRepo.Change(ctx, &model.PointsTransaction{
+		UserID:     userID,
+		Type:       model.PointsEarnAppointment,
+		SourceType: model.PointsSourceAppointment,
+		SourceID:   appointmentID,
+		Delta:      appointmentPoints,
+		Remark:     fmt.Sprintf("完成门诊预约#%d", appointmentID),
+	})
+	if err != nil {
+		logger.Error(ctx, "发放预约积分失败", logger.Int("appointment_id", int(appointmentID)), logger.ErrorField(err))
+	}
+}
+
+// pointsDiscount 计算使用积分可抵扣的金额,超过余额或抵扣上限时返回错误
+func pointsDiscount(points, balance, amount int64) (int64, error) {
+	if points <= 0 {
+		return 0, nil
+	}
+	if points > balance {
+		return 0, repository.ErrInsufficientPoints
+	}
+	maxPoints := amount * maxPointsPercent / 100 / pointValue
+	if points > maxPoints {
+		if maxPoints <= 0 {
+			return 0, errors.New("该订单不能使用积分抵扣")
+		}
+		return 0, fmt.Errorf("该订单最多可使用%d积分", maxPoints)
+	}
+	return points * pointValue, nil
+}
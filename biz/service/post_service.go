@@ -0,0 +1,484 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/redis"
+)
+
+const (
+	// maxPostPhotos 单条动态最多照片数
+	maxPostPhotos = 9
+	// maxTaggedPets 单条动态最多标记的宠物数
+	maxTaggedPets = 5
+	// replyPreviewSize 评论列表中每条一级评论附带的回复数
+	replyPreviewSize = 3
+	// postStatsTTL 动态计数缓存时长
+	postStatsTTL = 24 * time.Hour
+	// postStatsDirtyKey 计数有变化、待回写MySQL的动态ID集合
+	postStatsDirtyKey = "post:stats:dirty"
+	// postFlushInterval 计数回写间隔
+	postFlushInterval = 30 * time.Second
+	// postFlushBatch 每批回写的动态数
+	postFlushBatch = 200
+)
+
+// PostService 社区动态服务接口
+type PostService interface {
+	CreatePost(ctx context.Context, userID uint, req *model.CreatePostRequest) (*model.Post, error)
+	GetPost(ctx context.Context, viewerID, id uint) (*model.Post, error)
+	ListPosts(ctx context.Context, viewerID uint, req *model.ListPostRequest) (*model.PostPage, error)
+	Timeline(ctx context.Context, userID uint, req *model.ListPostRequest) (*model.PostPage, error)
+	DeletePost(ctx context.Context, userID, id uint) error
+
+	LikePost(ctx context.Context, userID, postID uint) (*model.LikeResult, error)
+	UnlikePost(ctx context.Context, userID, postID uint) (*model.LikeResult, error)
+
+	CreateComment(ctx context.Context, userID, postID uint, req *model.CreateCommentRequest) (*model.PostComment, error)
+	ListComments(ctx context.Context, viewerID, postID uint, req *model.ListCommentRequest) ([]*model.PostComment, int64, error)
+	ListReplies(ctx context.Context, viewerID, commentID uint, req *model.ListCommentRequest) ([]*model.PostComment, int64, error)
+	DeleteComment(ctx context.Context, userID, commentID uint) error
+
+	RunCounterFlusher(ctx context.Context)
+}
+
+// postService 社区动态服务实现
+type postService struct {
+	postRepo    repository.PostRepository
+	followRepo  repository.FollowRepository
+	petService  PetService
+	fileService FileService
+}
+
+// NewPostService 创建社区动态服务
+func NewPostService(postRepo repository.PostRepository, followRepo repository.FollowRepository, petService PetService, fileService FileService) PostService {
+	return &postService{
+		postRepo:    postRepo,
+		followRepo:  followRepo,
+		petService:  petService,
+		fileService: fileService,
+	}
+}
+
+// CreatePost 发布动态,可附带照片并标记自己有编辑权限的宠物
+func (s *postService) CreatePost(ctx context.Context, userID uint, req *model.CreatePostRequest) (*model.Post, error) {
+	content := strings.TrimSpace(req.Content)
+	photoIDs := uniqueIDs(req.PhotoFileIDs)
+	if content == "" && len(photoIDs) == 0 {
+		return nil, errors.New("动态内容和照片不能都为空")
+	}
+	if len([]rune(content)) > 5000 {
+		return nil, errors.New("动态内容不能超过5000字")
+	}
+	if len(photoIDs) > maxPostPhotos {
+		return nil, fmt.Errorf("最多上传%d张照片", maxPostPhotos)
+	}
+	for _, fileID := range photoIDs {
+		if _, err := s.fileService.GetOwnedImage(ctx, userID, fileID); err != nil {
+			return nil, err
+		}
+	}
+
+	petIDs := uniqueIDs(req.PetIDs)
+	if len(petIDs) > maxTaggedPets {
+		return nil, fmt.Errorf("最多标记%d只宠物", maxTaggedPets)
+	}
+	post := &model.Post{
+		UserID:       userID,
+		Content:      content,
+		PhotoFileIDs: photoIDs,
+	}
+	for _, petID := range petIDs {
+		if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor); err != nil {
+			return nil, err
+		}
+		post.Pets = append(post.Pets, &model.Pet{ID: petID})
+	}
+
+	if err := s.postRepo.Create(ctx, post); err != nil {
+		return nil, err
+	}
+	return s.GetPost(ctx, userID, post.ID)
+}
+
+// GetPost 动态详情,与发布人互相拉黑时不可见
+func (s *postService) GetPost(ctx context.Context, viewerID, id uint) (*model.Post, error) {
+	post, err := s.visiblePost(ctx, viewerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillPosts(ctx, viewerID, []*model.Post{post}); err != nil {
+		return nil, err
+	}
+	return post, nil
+}
+
+// ListPosts 动态广场,可按发布人或标记的宠物筛选
+func (s *postService) ListPosts(ctx context.Context, viewerID uint, req *model.ListPostRequest) (*model.PostPage, error) {
+	return s.listPosts(ctx, viewerID, &repository.PostFilter{
+		UserID:   req.UserID,
+		PetID:    req.PetID,
+		ViewerID: viewerID,
+		Cursor:   req.Cursor,
+		Limit:    req.Limit,
+	})
+}
+
+// Timeline 时间线:自己、关注用户以及标记了关注宠物的动态
+func (s *postService) Timeline(ctx context.Context, userID uint, req *model.ListPostRequest) (*model.PostPage, error) {
+	return s.listPosts(ctx, userID, &repository.PostFilter{
+		FollowerID: userID,
+		ViewerID:   userID,
+		Cursor:     req.Cursor,
+		Limit:      req.Limit,
+	})
+}
+
+func (s *postService) listPosts(ctx context.Context, viewerID uint, filter *repository.PostFilter) (*model.PostPage, error) {
+	if filter.Limit < 1 || filter.Limit > 50 {
+		filter.Limit = 20
+	}
+	posts, err := s.postRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillPosts(ctx, viewerID, posts); err != nil {
+		return nil, err
+	}
+	page := &model.PostPage{List: posts}
+	if len(posts) == filter.Limit {
+		page.NextCursor = posts[len(posts)-1].ID
+	}
+	return page, nil
+}
+
+// DeletePost 删除自己的动态
+func (s *postService) DeletePost(ctx context.Context, userID, id uint) error {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return checkNotFound(err, "动态不存在")
+	}
+	if post.UserID != userID {
+		return forbidden("只能删除自己的动态")
+	}
+	return s.postRepo.Delete(ctx, id)
+}
+
+// LikePost 点赞,重复点赞不重复计数
+func (s *postService) LikePost(ctx context.Context, userID, postID uint) (*model.LikeResult, error) {
+	if _, err := s.visiblePost(ctx, userID, postID); err != nil {
+		return nil, err
+	}
+	added, err := s.postRepo.Like(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if added {
+		s.bumpStats(ctx, postID, "likes", 1)
+	}
+	return s.likeResult(ctx, postID, true)
+}
+
+// UnlikePost 取消点赞,未点赞时直接返回
+func (s *postService) UnlikePost(ctx context.Context, userID, postID uint) (*model.LikeResult, error) {
+	removed, err := s.postRepo.Unlike(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		s.bumpStats(ctx, postID, "likes", -1)
+	}
+	return s.likeResult(ctx, postID, false)
+}
+
+func (s *postService) likeResult(ctx context.Context, postID uint, liked bool) (*model.LikeResult, error) {
+	stats, err := s.loadStats(ctx, []uint{postID})
+	if err != nil {
+		return nil, err
+	}
+	return &model.LikeResult{Liked: liked, LikeCount: stats[postID].LikeCount}, nil
+}
+
+// CreateComment 发表评论或回复,回复的回复挂在同一条一级评论下
+func (s *postService) CreateComment(ctx context.Context, userID, postID uint, req *model.CreateCommentRequest) (*model.PostComment, error) {
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		return nil, errors.New("评论内容不能为空")
+	}
+	if len([]rune(content)) > 1000 {
+		return nil, errors.New("评论内容不能超过1000字")
+	}
+	if _, err := s.visiblePost(ctx, userID, postID); err != nil {
+		return nil, err
+	}
+
+	comment := &model.PostComment{
+		PostID:  postID,
+		UserID:  userID,
+		Content: content,
+	}
+	if req.ParentID != 0 {
+		parent, err := s.postRepo.GetComment(ctx, req.ParentID)
+		if err != nil {
+			return nil, checkNotFound(err, "回复的评论不存在")
+		}
+		if parent.PostID != postID {
+			return nil, notFound("回复的评论不存在")
+		}
+		if parent.UserID != userID {
+			blocked, err := s.followRepo.BlockedEither(ctx, userID, parent.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if blocked {
+				return nil, forbidden("无法回复该用户")
+			}
+		}
+		comment.ParentID = parent.ID
+		if parent.ParentID != 0 {
+			comment.ParentID = parent.ParentID
+		}
+		comment.ReplyToUserID = parent.UserID
+	}
+
+	if err := s.postRepo.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	s.bumpStats(ctx, postID, "comments", 1)
+	return s.postRepo.GetComment(ctx, comment.ID)
+}
+
+// ListComments 一级评论列表,每条附带最早的几条回复
+func (s *postService) ListComments(ctx context.Context, viewerID, postID uint, req *model.ListCommentRequest) ([]*model.PostComment, int64, error) {
+	if _, err := s.visiblePost(ctx, viewerID, postID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	comments, total, err := s.postRepo.ListComments(ctx, postID, viewerID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, comment := range comments {
+		if comment.ReplyCount == 0 {
+			continue
+		}
+		replies, _, err := s.postRepo.ListReplies(ctx, comment.ID, viewerID, 0, replyPreviewSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		comment.Replies = replies
+	}
+	return comments, total, nil
+}
+
+// ListReplies 一级评论下的全部回复
+func (s *postService) ListReplies(ctx context.Context, viewerID, commentID uint, req *model.ListCommentRequest) ([]*model.PostComment, int64, error) {
+	comment, err := s.postRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return nil, 0, checkNotFound(err, "评论不存在")
+	}
+	if comment.ParentID != 0 {
+		return nil, 0, errors.New("只能查看一级评论的回复")
+	}
+	if _, err := s.visiblePost(ctx, viewerID, comment.PostID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.postRepo.ListReplies(ctx, commentID, viewerID, offset, limit)
+}
+
+// DeleteComment 评论人或动态发布人可删除评论
+func (s *postService) DeleteComment(ctx context.Context, userID, commentID uint) error {
+	comment, err := s.postRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return checkNotFound(err, "评论不存在")
+	}
+	if comment.UserID != userID {
+		post, err := s.postRepo.GetByID(ctx, comment.PostID)
+		if err != nil {
+			return checkNotFound(err, "评论不存在")
+		}
+		if post.UserID != userID {
+			return forbidden("只能删除自己的评论或自己动态下的评论")
+		}
+	}
+	if err := s.postRepo.DeleteComment(ctx, comment); err != nil {
+		return err
+	}
+	// 一级评论会连带删除回复,数量以回写时的统计为准
+	s.bumpStats(ctx, comment.PostID, "comments", -1-comment.ReplyCount)
+	return nil
+}
+
+// visiblePost 获取动态,与发布人存在任一方向的拉黑时视为不存在
+func (s *postService) visiblePost(ctx context.Context, viewerID, id uint) (*model.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "动态不存在")
+	}
+	if post.UserID != viewerID {
+		blocked, err := s.followRepo.BlockedEither(ctx, viewerID, post.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, notFound("动态不存在")
+		}
+	}
+	return post, nil
+}
+
+// fillPosts 填充照片链接、计数和当前用户的点赞状态
+func (s *postService) fillPosts(ctx context.Context, viewerID uint, posts []*model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	stats, err := s.loadStats(ctx, ids)
+	if err != nil {
+		return err
+	}
+	liked, err := s.postRepo.LikedPostIDs(ctx, viewerID, ids)
+	if err != nil {
+		return err
+	}
+	for _, post := range posts {
+		post.Photos = make([]string, 0, len(post.PhotoFileIDs))
+		for _, fileID := range post.PhotoFileIDs {
+			post.Photos = append(post.Photos, s.fileService.SignedURL(fileID, model.FileVariantOriginal))
+		}
+		post.LikeCount = stats[post.ID].LikeCount
+		post.CommentCount = stats[post.ID].CommentCount
+		post.Liked = liked[post.ID]
+	}
+	return nil
+}
+
+// loadStats 从Redis读取动态计数,未缓存的从明细表统计后写入缓存
+func (s *postService) loadStats(ctx context.Context, ids []uint) (map[uint]*model.PostStats, error) {
+	stats := make(map[uint]*model.PostStats, len(ids))
+	var missing []uint
+	for _, id := range ids {
+		values, err := redis.HGetAll(ctx, postStatsKey(id))
+		if err != nil || len(values) == 0 {
+			missing = append(missing, id)
+			continue
+		}
+		likes, _ := strconv.ParseInt(values["likes"], 10, 64)
+		comments, _ := strconv.ParseInt(values["comments"], 10, 64)
+		stats[id] = &model.PostStats{LikeCount: max(likes, 0), CommentCount: max(comments, 0)}
+	}
+	if len(missing) == 0 {
+		return stats, nil
+	}
+
+	counted, err := s.postRepo.CountStats(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for id, item := range counted {
+		stats[id] = item
+		s.cacheStats(ctx, id, item)
+	}
+	return stats, nil
+}
+
+// bumpStats 增量更新缓存的计数并标记待回写,缓存不存在时下次读取会重新统计
+func (s *postService) bumpStats(ctx context.Context, postID uint, field string, delta int64) {
+	_, _ = redis.HIncrByIfExists(ctx, postStatsKey(postID), field, delta)
+	_ = redis.SAdd(ctx, postStatsDirtyKey, postID)
+}
+
+func (s *postService) cacheStats(ctx context.Context, postID uint, stats *model.PostStats) {
+	_ = redis.HSetAllWithExpire(ctx, postStatsKey(postID), map[string]interface{}{
+		"likes":    stats.LikeCount,
+		"comments": stats.CommentCount,
+	}, postStatsTTL)
+}
+
+// RunCounterFlusher 定期将有变化的动态计数回写MySQL,ctx取消后退出
+// 点赞和评论只写明细表和Redis,动态表每个周期最多更新一次,避免热门动态的行锁竞争
+func (s *postService) RunCounterFlusher(ctx context.Context) {
+	ticker := time.NewTicker(postFlushInterval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "动态计数回写已启动")
+	for {
+		select {
+		case <-ctx.Done():
+			// 未回写的动态ID仍保留在Redis集合中,重启后继续处理
+			logger.Info(context.Background(), "动态计数回写已停止")
+			return
+		case <-ticker.C:
+			s.flushStats(ctx)
+		}
+	}
+}
+
+// flushStats 回写待更新的动态计数,以明细表统计为准并校正缓存
+func (s *postService) flushStats(ctx context.Context) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "动态计数回写异常", logger.Any("panic", r))
+		}
+	}()
+
+	flushed := 0
+	for {
+		members, err := redis.SPopN(ctx, postStatsDirtyKey, postFlushBatch)
+		if err != nil || len(members) == 0 {
+			break
+		}
+		ids := make([]uint, 0, len(members))
+		for _, member := range members {
+			if id, err := strconv.ParseUint(member, 10, 64); err == nil {
+				ids = append(ids, uint(id))
+			}
+		}
+
+		stats, err := s.postRepo.CountStats(ctx, ids)
+		if err != nil {
+			s.markDirty(ctx, ids)
+			break
+		}
+		for id, item := range stats {
+			if err := s.postRepo.UpdateStats(ctx, id, item); err != nil {
+				s.markDirty(ctx, []uint{id})
+				continue
+			}
+			s.cacheStats(ctx, id, item)
+			flushed++
+		}
+		if len(members) < postFlushBatch {
+			break
+		}
+	}
+	if flushed > 0 {
+		logger.Info(ctx, "回写动态计数", logger.Int("count", flushed))
+	}
+}
+
+func (s *postService) markDirty(ctx context.Context, ids []uint) {
+	members := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, id)
+	}
+	if len(members) > 0 {
+		_ = redis.SAdd(ctx, postStatsDirtyKey, members...)
+	}
+}
+
+func postStatsKey(postID uint) string {
+	return fmt.Sprintf("post:stats:%d", postID)
+}
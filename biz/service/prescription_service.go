@@ -0,0 +1,495 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+)
+
+// maxPrescriptionQuantity 单次配药数量上限
+const maxPrescriptionQuantity = 100000
+
+// PrescriptionService 处方与配药服务接口
+type PrescriptionService interface {
+	// CreatePrescription 兽医为就诊记录开具处方草稿
+	CreatePrescription(ctx context.Context, userID, recordID uint, req *model.SavePrescriptionRequest) (*model.Prescription, error)
+	UpdatePrescription(ctx context.Context, userID, id uint, req *model.SavePrescriptionRequest) (*model.Prescription, error)
+	DeletePrescription(ctx context.Context, userID, id uint) error
+	// SignPrescription 开方兽医签发处方,签发后内容不可修改
+	SignPrescription(ctx context.Context, userID, id uint) (*model.Prescription, error)
+	RevokePrescription(ctx context.Context, userID, id uint, req *model.RevokePrescriptionRequest) (*model.Prescription, error)
+	GetPrescription(ctx context.Context, userID, id uint) (*model.Prescription, error)
+	ListPetPrescriptions(ctx context.Context, userID, petID uint, req *model.ListPrescriptionRequest) ([]*model.Prescription, int64, error)
+	ListClinicPrescriptions(ctx context.Context, userID, clinicID uint, req *model.ListPrescriptionRequest) ([]*model.Prescription, int64, error)
+
+	// RequestRefill 主人提交配药申请
+	RequestRefill(ctx context.Context, userID, prescriptionID uint, req *model.CreateRefillRequest) (*model.PrescriptionRefill, error)
+	CancelRefill(ctx context.Context, userID, refillID uint) (*model.PrescriptionRefill, error)
+	ListRefills(ctx context.Context, userID, prescriptionID uint) ([]*model.PrescriptionRefill, error)
+	// ListClinicRefills 诊所药房的配药申请队列
+	ListClinicRefills(ctx context.Context, userID, clinicID uint, req *model.ListRefillRequest) ([]*model.PrescriptionRefill, int64, error)
+	// ListShopRefills 商城药房的配药申请队列,权限由路由校验
+	ListShopRefills(ctx context.Context, req *model.ListRefillRequest) ([]*model.PrescriptionRefill, int64, error)
+	// HandleRefill 药房完成或拒绝配药申请,channel为shop时权限由路由校验
+	HandleRefill(ctx context.Context, userID, refillID uint, channel string, req *model.HandleRefillRequest) (*model.PrescriptionRefill, error)
+}
+
+// prescriptionService 处方与配药服务实现
+type prescriptionService struct {
+	prescriptionRepo     repository.PrescriptionRepository
+	userRepo             repository.UserRepository
+	medicalRecordService MedicalRecordService
+	petService           PetService
+	clinicService        ClinicService
+	notifier             notifier.Notifier
+}
+
+// NewPrescriptionService 创建处方与配药服务
+func NewPrescriptionService(prescriptionRepo repository.PrescriptionRepository, userRepo repository.UserRepository, medicalRecordService MedicalRecordService,
+	petService PetService, clinicService ClinicService, n notifier.Notifier) PrescriptionService {
+	return &prescriptionService{
+		prescriptionRepo:     prescriptionRepo,
+		userRepo:             userRepo,
+		medicalRecordService: medicalRecordService,
+		petService:           petService,
+		clinicService:        clinicService,
+		notifier:             n,
+	}
+}
+
+// CreatePrescription 开具处方草稿,需要是诊所的执业兽医,且能查看该就诊记录
+func (s *prescriptionService) CreatePrescription(ctx context.Context, userID, recordID uint, req *model.SavePrescriptionRequest) (*model.Prescription, error) {
+	if err := s.authorizeVet(ctx, userID, req.ClinicID); err != nil {
+		return nil, err
+	}
+	record, err := s.medicalRecordService.Authorize(ctx, userID, recordID, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, checkNotFound(err, "用户不存在")
+	}
+	vetName := user.Nickname
+	if vetName == "" {
+		vetName = user.Username
+	}
+
+	no, err := newOrderNo("RX")
+	if err != nil {
+		return nil, err
+	}
+	prescription := &model.Prescription{
+		PrescriptionNo:  no,
+		ClinicID:        req.ClinicID,
+		VetID:           userID,
+		VetName:         truncate(vetName, 50),
+		MedicalRecordID: record.ID,
+		PetID:           record.PetID,
+		Status:          model.PrescriptionDraft,
+	}
+	if err := applyPrescriptionRequest(prescription, req, today()); err != nil {
+		return nil, err
+	}
+	if err := s.prescriptionRepo.Create(ctx, prescription); err != nil {
+		return nil, err
+	}
+	return s.getPrescription(ctx, prescription.ID)
+}
+
+// UpdatePrescription 修改处方草稿,仅开方兽医可操作
+func (s *prescriptionService) UpdatePrescription(ctx context.Context, userID, id uint, req *model.SavePrescriptionRequest) (*model.Prescription, error) {
+	prescription, err := s.getDraft(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPrescriptionRequest(prescription, req, today()); err != nil {
+		return nil, err
+	}
+	if err := s.prescriptionRepo.UpdateDraft(ctx, prescription); err != nil {
+		return nil, err
+	}
+	return s.getPrescription(ctx, prescription.ID)
+}
+
+// DeletePrescription 删除处方草稿,已签发的处方只能作废
+func (s *prescriptionService) DeletePrescription(ctx context.Context, userID, id uint) error {
+	prescription, err := s.getDraft(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.prescriptionRepo.DeleteDraft(ctx, prescription.ID)
+}
+
+// SignPrescription 签发处方,记录内容摘要并通知宠物主人
+func (s *prescriptionService) SignPrescription(ctx context.Context, userID, id uint) (*model.Prescription, error) {
+	prescription, err := s.getDraft(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if today().After(prescription.ExpiresAt) {
+		return nil, errors.New("处方有效期已过,请修改后再签发")
+	}
+	pet, err := s.petService.Authorize(ctx, userID, prescription.PetID, model.PetRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	prescription.SignedAt = &now
+	prescription.Digest = prescription.ContentDigest()
+	if err := s.prescriptionRepo.Sign(ctx, prescription); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, pet.OwnerID, prescription, "收到新的处方",
+		fmt.Sprintf("%s医生为%s开具了处方:%s %s,可在处方中申请配药", prescription.VetName, pet.Name, prescription.Drug, prescription.Strength))
+	return s.getPrescription(ctx, prescription.ID)
+}
+
+// RevokePrescription 作废处方,诊所兽医可操作,待处理的配药申请随之取消
+func (s *prescriptionService) RevokePrescription(ctx context.Context, userID, id uint, req *model.RevokePrescriptionRequest) (*model.Prescription, error) {
+	prescription, err := s.getPrescription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeVet(ctx, userID, prescription.ClinicID); err != nil {
+		return nil, err
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.New("请填写作废原因")
+	}
+	now := time.Now()
+	prescription.RevokedAt = &now
+	prescription.RevokeReason = truncate(reason, 255)
+	if err := s.prescriptionRepo.Revoke(ctx, prescription); err != nil {
+		return nil, err
+	}
+	return s.getPrescription(ctx, prescription.ID)
+}
+
+// GetPrescription 获取处方,宠物成员可查看已签发的处方,诊所人员可查看本诊所的处方
+func (s *prescriptionService) GetPrescription(ctx context.Context, userID, id uint) (*model.Prescription, error) {
+	return s.authorizeView(ctx, userID, id)
+}
+
+// ListPetPrescriptions 宠物的处方,不包含草稿
+func (s *prescriptionService) ListPetPrescriptions(ctx context.Context, userID, petID uint, req *model.ListPrescriptionRequest) ([]*model.Prescription, int64, error) {
+	if _, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleViewer); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	prescriptions, total, err := s.prescriptionRepo.ListByPet(ctx, petID, req.MedicalRecordID, req.Status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	fillPrescriptionState(today(), prescriptions...)
+	return prescriptions, total, nil
+}
+
+// ListClinicPrescriptions 诊所开具的处方,诊所人员可查看
+func (s *prescriptionService) ListClinicPrescriptions(ctx context.Context, userID, clinicID uint, req *model.ListPrescriptionRequest) ([]*model.Prescription, int64, error) {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	prescriptions, total, err := s.prescriptionRepo.ListByClinic(ctx, clinicID, req.MedicalRecordID, req.Status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	fillPrescriptionState(today(), prescriptions...)
+	return prescriptions, total, nil
+}
+
+// RequestRefill 提交配药申请,需要宠物编辑权限,处方须已签发、未过期且有剩余次数
+func (s *prescriptionService) RequestRefill(ctx context.Context, userID, prescriptionID uint, req *model.CreateRefillRequest) (*model.PrescriptionRefill, error) {
+	prescription, err := s.getPrescription(ctx, prescriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if prescription.Status == model.PrescriptionDraft {
+		return nil, notFound("处方不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, prescription.PetID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+	if req.Channel != model.FillChannelClinic && req.Channel != model.FillChannelShop {
+		return nil, fmt.Errorf("不支持的配药渠道: %s", req.Channel)
+	}
+	if !prescription.Fillable {
+		return nil, repository.ErrPrescriptionNotFillable
+	}
+
+	refill := &model.PrescriptionRefill{
+		PrescriptionID: prescription.ID,
+		ClinicID:       prescription.ClinicID,
+		PetID:          prescription.PetID,
+		RequestedBy:    userID,
+		Channel:        req.Channel,
+		Note:           truncate(strings.TrimSpace(req.Note), 255),
+		Status:         model.RefillPending,
+	}
+	if err := s.prescriptionRepo.CreateRefill(ctx, refill); err != nil {
+		return nil, err
+	}
+
+	if refill.Channel == model.FillChannelClinic {
+		staffIDs, err := s.clinicService.StaffUserIDs(ctx, prescription.ClinicID)
+		if err != nil {
+			logger.Error(ctx, "获取诊所人员失败", logger.Int("clinic_id", int(prescription.ClinicID)), logger.ErrorField(err))
+		}
+		for _, staffID := range staffIDs {
+			s.notify(ctx, staffID, prescription, "新的配药申请",
+				fmt.Sprintf("处方%s(%s)有新的配药申请", prescription.PrescriptionNo, prescription.Drug))
+		}
+	}
+	refill.Prescription = prescription
+	return refill, nil
+}
+
+// CancelRefill 取消待处理的配药申请
+func (s *prescriptionService) CancelRefill(ctx context.Context, userID, refillID uint) (*model.PrescriptionRefill, error) {
+	refill, err := s.getRefill(ctx, refillID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.petService.Authorize(ctx, userID, refill.PetID, model.PetRoleEditor); err != nil {
+		return nil, err
+	}
+	if refill.Status != model.RefillPending {
+		return nil, errors.New("只能取消待处理的配药申请")
+	}
+	now := time.Now()
+	refill.Status = model.RefillCancelled
+	refill.HandledBy = userID
+	refill.HandledAt = &now
+	if err := s.prescriptionRepo.CloseRefill(ctx, refill); err != nil {
+		return nil, err
+	}
+	return refill, nil
+}
+
+// ListRefills 处方的配药记录
+func (s *prescriptionService) ListRefills(ctx context.Context, userID, prescriptionID uint) ([]*model.PrescriptionRefill, error) {
+	if _, err := s.authorizeView(ctx, userID, prescriptionID); err != nil {
+		return nil, err
+	}
+	return s.prescriptionRepo.ListRefills(ctx, prescriptionID)
+}
+
+// ListClinicRefills 诊所药房的配药申请,默认按提交时间排序
+func (s *prescriptionService) ListClinicRefills(ctx context.Context, userID, clinicID uint, req *model.ListRefillRequest) ([]*model.PrescriptionRefill, int64, error) {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID); err != nil {
+		return nil, 0, err
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.listRefills(ctx, model.FillChannelClinic, clinicID, req.Status, offset, limit)
+}
+
+// ListShopRefills 商城药房的配药申请
+func (s *prescriptionService) ListShopRefills(ctx context.Context, req *model.ListRefillRequest) ([]*model.PrescriptionRefill, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	return s.listRefills(ctx, model.FillChannelShop, 0, req.Status, offset, limit)
+}
+
+// HandleRefill 完成或拒绝配药申请,完成时校验处方内容摘要并扣减剩余次数
+func (s *prescriptionService) HandleRefill(ctx context.Context, userID, refillID uint, channel string, req *model.HandleRefillRequest) (*model.PrescriptionRefill, error) {
+	refill, err := s.getRefill(ctx, refillID)
+	if err != nil {
+		return nil, err
+	}
+	if refill.Channel != channel {
+		return nil, notFound("配药申请不存在")
+	}
+	if channel == model.FillChannelClinic {
+		if _, err := s.clinicService.Authorize(ctx, userID, refill.ClinicID); err != nil {
+			return nil, err
+		}
+	}
+	if refill.Status != model.RefillPending {
+		return nil, errors.New("配药申请已处理")
+	}
+
+	now := time.Now()
+	refill.HandledBy = userID
+	refill.HandledAt = &now
+	refill.HandleNote = truncate(strings.TrimSpace(req.Note), 255)
+	prescription := refill.Prescription
+	switch req.Status {
+	case model.RefillFulfilled:
+		if prescription == nil || prescription.Digest == "" || prescription.Digest != prescription.ContentDigest() {
+			logger.Error(ctx, "处方内容与签发摘要不一致", logger.Int("prescription_id", int(refill.PrescriptionID)))
+			return nil, errors.New("处方内容校验失败,请联系开方诊所")
+		}
+		if err := s.prescriptionRepo.FulfillRefill(ctx, refill, today()); err != nil {
+			return nil, err
+		}
+		s.notify(ctx, refill.RequestedBy, prescription, "配药完成",
+			fmt.Sprintf("处方%s(%s)的第%d次配药已完成", prescription.PrescriptionNo, prescription.Drug, refill.FillNo))
+	case model.RefillDeclined:
+		if refill.HandleNote == "" {
+			return nil, errors.New("请填写拒绝原因")
+		}
+		refill.Status = model.RefillDeclined
+		if err := s.prescriptionRepo.CloseRefill(ctx, refill); err != nil {
+			return nil, err
+		}
+		if prescription != nil {
+			s.notify(ctx, refill.RequestedBy, prescription, "配药申请未通过",
+				fmt.Sprintf("处方%s(%s)的配药申请未通过:%s", prescription.PrescriptionNo, prescription.Drug, refill.HandleNote))
+		}
+	default:
+		return nil, fmt.Errorf("不支持的处理结果: %s", req.Status)
+	}
+	return s.getRefill(ctx, refill.ID)
+}
+
+func (s *prescriptionService) listRefills(ctx context.Context, channel string, clinicID uint, status string, offset, limit int) ([]*model.PrescriptionRefill, int64, error) {
+	refills, total, err := s.prescriptionRepo.ListRefillsByChannel(ctx, channel, clinicID, status, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := today()
+	for _, refill := range refills {
+		if refill.Prescription != nil {
+			fillPrescriptionState(now, refill.Prescription)
+		}
+	}
+	return refills, total, nil
+}
+
+// authorizeView 校验查看权限:宠物成员可查看已签发或作废的处方,诊所人员可查看本诊所的全部处方
+func (s *prescriptionService) authorizeView(ctx context.Context, userID, id uint) (*model.Prescription, error) {
+	prescription, err := s.getPrescription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.clinicService.Authorize(ctx, userID, prescription.ClinicID); err == nil {
+		return prescription, nil
+	}
+	if prescription.Status == model.PrescriptionDraft {
+		return nil, notFound("处方不存在")
+	}
+	if _, err := s.petService.Authorize(ctx, userID, prescription.PetID, model.PetRoleViewer); err != nil {
+		return nil, err
+	}
+	return prescription, nil
+}
+
+// getDraft 获取处方草稿并校验当前用户为开方兽医
+func (s *prescriptionService) getDraft(ctx context.Context, userID, id uint) (*model.Prescription, error) {
+	prescription, err := s.getPrescription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if prescription.VetID != userID {
+		return nil, forbidden("仅开方兽医可修改或签发处方")
+	}
+	if prescription.Status != model.PrescriptionDraft {
+		return nil, repository.ErrPrescriptionSigned
+	}
+	if err := s.authorizeVet(ctx, userID, prescription.ClinicID); err != nil {
+		return nil, err
+	}
+	return prescription, nil
+}
+
+// authorizeVet 校验当前用户是诊所的执业兽医
+func (s *prescriptionService) authorizeVet(ctx context.Context, userID, clinicID uint) error {
+	if _, err := s.clinicService.Authorize(ctx, userID, clinicID, model.ClinicRoleVet); err != nil {
+		if errors.Is(err, ErrForbidden) {
+			return forbidden("仅诊所执业兽医可开具或作废处方")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *prescriptionService) getPrescription(ctx context.Context, id uint) (*model.Prescription, error) {
+	prescription, err := s.prescriptionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "处方不存在")
+	}
+	fillPrescriptionState(today(), prescription)
+	return prescription, nil
+}
+
+func (s *prescriptionService) getRefill(ctx context.Context, id uint) (*model.PrescriptionRefill, error) {
+	refill, err := s.prescriptionRepo.GetRefill(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "配药申请不存在")
+	}
+	if refill.Prescription != nil {
+		fillPrescriptionState(today(), refill.Prescription)
+	}
+	return refill, nil
+}
+
+func (s *prescriptionService) notify(ctx context.Context, userID uint, prescription *model.Prescription, title, content string) {
+	err := s.notifier.Notify(ctx, &notifier.Message{
+		UserID:  userID,
+		Type:    model.NotifyTypePrescription,
+		Title:   title,
+		Content: content,
+		Data: map[string]interface{}{
+			"prescription_id": prescription.ID,
+			"pet_id":          prescription.PetID,
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "发送处方通知失败", logger.Int("prescription_id", int(prescription.ID)), logger.Int("user_id", int(userID)), logger.ErrorField(err))
+	}
+}
+
+// applyPrescriptionRequest 校验并写入处方内容,草稿的剩余续配次数与续配次数保持一致
+func applyPrescriptionRequest(prescription *model.Prescription, req *model.SavePrescriptionRequest, today time.Time) error {
+	drug := strings.TrimSpace(req.Drug)
+	strength := strings.TrimSpace(req.Strength)
+	unit := strings.TrimSpace(req.Unit)
+	if drug == "" || strength == "" || unit == "" {
+		return errors.New("药品名称、规格和单位不能为空")
+	}
+	quantity := round2(req.Quantity)
+	if quantity <= 0 || quantity > maxPrescriptionQuantity {
+		return fmt.Errorf("配药数量需在0.01-%d之间", maxPrescriptionQuantity)
+	}
+	if req.Refills < 0 || req.Refills > model.MaxPrescriptionRefills {
+		return fmt.Errorf("续配次数需在0-%d之间", model.MaxPrescriptionRefills)
+	}
+	expiresAt, err := parseDate(req.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if expiresAt.Before(today) {
+		return errors.New("有效期不能早于今天")
+	}
+	if expiresAt.After(today.AddDate(0, 0, model.MaxPrescriptionDays)) {
+		return fmt.Errorf("处方有效期最长%d天", model.MaxPrescriptionDays)
+	}
+
+	prescription.Drug = truncate(drug, 100)
+	prescription.Strength = truncate(strength, 50)
+	prescription.Quantity = quantity
+	prescription.Unit = truncate(unit, 20)
+	prescription.Refills = req.Refills
+	prescription.RefillsRemaining = req.Refills
+	prescription.Instructions = truncate(strings.TrimSpace(req.Instructions), 500)
+	prescription.ExpiresAt = expiresAt
+	return nil
+}
+
+// fillPrescriptionState 计算处方是否过期及能否配药
+func fillPrescriptionState(today time.Time, prescriptions ...*model.Prescription) {
+	for _, p := range prescriptions {
+		p.Expired = today.After(p.ExpiresAt)
+		p.Fillable = p.Status == model.PrescriptionSigned && !p.Expired && p.HasFillsLeft()
+	}
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/redis"
+)
+
+const (
+	// reviewHideFlagThreshold 评价被举报达到该次数后自动隐藏
+	reviewHideFlagThreshold = 3
+	// ratingCacheTTL 评分汇总缓存时长
+	ratingCacheTTL = time.Hour
+)
+
+// ReviewTarget 被评价对象
+type ReviewTarget struct {
+	Type string
+	ID   uint
+}
+
+// ReviewSource 可评价的业务来源(寄养、门诊预约等),由各业务模块实现
+type ReviewSource interface {
+	// ReviewTarget 校验用户可以评价该来源记录(本人且已完成),返回被评价对象
+	ReviewTarget(ctx context.Context, userID, sourceID uint) (*ReviewTarget, error)
+	// IsProvider 判断用户是否为被评价对象的服务方,服务方可以回复评价
+	IsProvider(ctx context.Context, target *ReviewTarget, userID uint) (bool, error)
+}
+
+// ReviewService 评价服务接口
+type ReviewService interface {
+	CreateReview(ctx context.Context, userID uint, req *model.CreateReviewRequest) (*model.Review, error)
+	GetReview(ctx context.Context, reviewID uint) (*model.Review, error)
+	ListReviews(ctx context.Context, req *model.ListReviewRequest) ([]*model.Review, int64, error)
+	ListMyReviews(ctx context.Context, userID uint, req *model.ListMyReviewRequest) ([]*model.Review, int64, error)
+	ReplyReview(ctx context.Context, userID, reviewID uint, content string) (*model.Review, error)
+	FlagReview(ctx context.Context, userID, reviewID uint, reason string) error
+	DeleteReview(ctx context.Context, userID, reviewID uint) error
+	GetSummary(ctx context.Context, targetType string, targetID uint) (*model.RatingSummary, error)
+}
+
+// reviewService 评价服务实现
+type reviewService struct {
+	reviewRepo  repository.ReviewRepository
+	fileService FileService
+	sources     map[string]ReviewSource
+}
+
+// NewReviewService 创建评价服务,sources按来源类型注册可评价的业务
+func NewReviewService(reviewRepo repository.ReviewRepository, fileService FileService, sources map[string]ReviewSource) ReviewService {
+	return &reviewService{
+		reviewRepo:  reviewRepo,
+		fileService: fileService,
+		sources:     sources,
+	}
+}
+
+// CreateReview 对已完成的预约/寄养发表评价,每个来源记录只能评价一次
+func (s *reviewService) CreateReview(ctx context.Context, userID uint, req *model.CreateReviewRequest) (*model.Review, error) {
+	source, ok := s.sources[req.SourceType]
+	if !ok {
+		return nil, fmt.Errorf("不支持评价该类型: %s", req.SourceType)
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		return nil, errors.New("评分需在1-5星之间")
+	}
+	if len([]rune(req.Content)) > 2000 {
+		return nil, errors.New("评价内容不能超过2000字")
+	}
+	photoIDs := uniqueIDs(req.PhotoFileIDs)
+	if len(photoIDs) > 9 {
+		return nil, errors.New("最多上传9张照片")
+	}
+	for _, fileID := range photoIDs {
+		if _, err := s.fileService.GetOwnedImage(ctx, userID, fileID); err != nil {
+			return nil, err
+		}
+	}
+
+	target, err := source.ReviewTarget(ctx, userID, req.SourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &model.Review{
+		SourceType:   req.SourceType,
+		SourceID:     req.SourceID,
+		TargetType:   target.Type,
+		TargetID:     target.ID,
+		ReviewerID:   userID,
+		Rating:       req.Rating,
+		Content:      strings.TrimSpace(req.Content),
+		PhotoFileIDs: photoIDs,
+		Status:       model.ReviewStatusVisible,
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	s.invalidateSummary(ctx, target.Type, target.ID)
+	s.fillPhotos(review)
+	return review, nil
+}
+
+// GetReview 获取评价详情,隐藏的评价不对外展示
+func (s *reviewService) GetReview(ctx context.Context, reviewID uint) (*model.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, checkNotFound(err, "评价不存在")
+	}
+	if review.Status != model.ReviewStatusVisible {
+		return nil, notFound("评价不存在")
+	}
+	s.fillPhotos(review)
+	return review, nil
+}
+
+// ListReviews 获取被评价对象的公开评价
+func (s *reviewService) ListReviews(ctx context.Context, req *model.ListReviewRequest) ([]*model.Review, int64, error) {
+	if req.TargetType == "" || req.TargetID == 0 {
+		return nil, 0, errors.New("请指定被评价对象")
+	}
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	reviews, total, err := s.reviewRepo.ListByTarget(ctx, req.TargetType, req.TargetID, req.Rating, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, review := range reviews {
+		s.fillPhotos(review)
+	}
+	return reviews, total, nil
+}
+
+// ListMyReviews 获取我发表的评价,包括被隐藏的评价
+func (s *reviewService) ListMyReviews(ctx context.Context, userID uint, req *model.ListMyReviewRequest) ([]*model.Review, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	reviews, total, err := s.reviewRepo.ListByReviewer(ctx, userID, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, review := range reviews {
+		s.fillPhotos(review)
+	}
+	return reviews, total, nil
+}
+
+// ReplyReview 服务方回复评价,重复回复时覆盖之前的内容
+func (s *reviewService) ReplyReview(ctx context.Context, userID, reviewID uint, content string) (*model.Review, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("回复内容不能为空")
+	}
+	if len([]rune(content)) > 1000 {
+		return nil, errors.New("回复内容不能超过1000字")
+	}
+
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, checkNotFound(err, "评价不存在")
+	}
+	source, ok := s.sources[review.SourceType]
+	if !ok {
+		return nil, errors.New("该评价不支持回复")
+	}
+	isProvider, err := source.IsProvider(ctx, &ReviewTarget{Type: review.TargetType, ID: review.TargetID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isProvider {
+		logger.Warn(ctx, "非服务方回复评价", logger.Int("review_id", int(reviewID)), logger.Int("user_id", int(userID)))
+		return nil, forbidden("仅服务方可以回复评价")
+	}
+
+	now := time.Now()
+	review.Reply = content
+	review.RepliedBy = userID
+	review.RepliedAt = &now
+	if err := s.reviewRepo.UpdateReply(ctx, review); err != nil {
+		return nil, err
+	}
+	s.fillPhotos(review)
+	return review, nil
+}
+
+// FlagReview 举报评价,同一用户只能举报一次,达到阈值后自动隐藏
+func (s *reviewService) FlagReview(ctx context.Context, userID, reviewID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("请填写举报原因")
+	}
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return checkNotFound(err, "评价不存在")
+	}
+	if review.ReviewerID == userID {
+		return errors.New("不能举报自己的评价")
+	}
+	if review.Status != model.ReviewStatusVisible {
+		return errors.New("该评价已被隐藏")
+	}
+
+	flag := &model.ReviewFlag{ReviewID: reviewID, UserID: userID, Reason: truncate(reason, 255)}
+	hidden, err := s.reviewRepo.AddFlag(ctx, review, flag, reviewHideFlagThreshold)
+	if err != nil {
+		return err
+	}
+	if hidden {
+		logger.Warn(ctx, "评价因举报被隐藏", logger.Int("review_id", int(reviewID)))
+		s.invalidateSummary(ctx, review.TargetType, review.TargetID)
+	}
+	return nil
+}
+
+// DeleteReview 评价人删除自己的评价
+func (s *reviewService) DeleteReview(ctx context.Context, userID, reviewID uint) error {
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return checkNotFound(err, "评价不存在")
+	}
+	if review.ReviewerID != userID {
+		return forbidden("只能删除自己的评价")
+	}
+	if err := s.reviewRepo.Delete(ctx, review); err != nil {
+		return err
+	}
+	s.invalidateSummary(ctx, review.TargetType, review.TargetID)
+	return nil
+}
+
+// GetSummary 获取评分汇总,优先读取Redis缓存
+func (s *reviewService) GetSummary(ctx context.Context, targetType string, targetID uint) (*model.RatingSummary, error) {
+	if targetType == "" || targetID == 0 {
+		return nil, errors.New("请指定被评价对象")
+	}
+	key := ratingCacheKey(targetType, targetID)
+	if cached, err := redis.Get(ctx, key); err == nil && cached != "" {
+		var summary model.RatingSummary
+		if err := json.Unmarshal([]byte(cached), &summary); err == nil {
+			return &summary, nil
+		}
+	}
+
+	summary, err := s.reviewRepo.GetSummary(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	summary.FillAverage()
+	if data, err := json.Marshal(summary); err == nil {
+		_ = redis.Set(ctx, key, data, ratingCacheTTL)
+	}
+	return summary, nil
+}
+
+// invalidateSummary 评分变化后删除缓存,下次读取时从汇总表重建
+func (s *reviewService) invalidateSummary(ctx context.Context, targetType string, targetID uint) {
+	_ = redis.Del(ctx, ratingCacheKey(targetType, targetID))
+}
+
+// fillPhotos 生成评价照片的签名链接
+func (s *reviewService) fillPhotos(review *model.Review) {
+	for _, fileID := range review.PhotoFileIDs {
+		review.Photos = append(review.Photos, s.fileService.SignedURL(fileID, model.FileVariantOriginal))
+	}
+}
+
+// ratingCacheKey 评分汇总缓存key
+func ratingCacheKey(targetType string, targetID uint) string {
+	return fmt.Sprintf("rating:%s:%d", targetType, targetID)
+}
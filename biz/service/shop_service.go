@@ -0,0 +1,660 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/redis"
+)
+
+const (
+	// cartTTL 购物车无操作后的保留时长
+	cartTTL = 30 * 24 * time.Hour
+	// cartMaxItems 购物车最多条目数
+	cartMaxItems = 50
+	// reservationTTL 结算后库存预占时长,超时未下单自动释放
+	reservationTTL = 15 * time.Minute
+	// reservationReapInterval 过期预占扫描间隔
+	reservationReapInterval = time.Minute
+	// reservationReapBatch 每轮最多释放的过期预占数
+	reservationReapBatch = 100
+)
+
+// ShopService 商城服务接口
+type ShopService interface {
+	CreateCategory(ctx context.Context, req *model.SaveCategoryRequest) (*model.ProductCategory, error)
+	UpdateCategory(ctx context.Context, id uint, req *model.SaveCategoryRequest) (*model.ProductCategory, error)
+	ListCategories(ctx context.Context, includeDisabled bool) ([]*model.ProductCategory, error)
+
+	CreateProduct(ctx context.Context, operatorID uint, req *model.CreateProductRequest) (*model.Product, error)
+	UpdateProduct(ctx context.Context, operatorID, id uint, req *model.UpdateProductRequest) (*model.Product, error)
+	UpdateProductStatus(ctx context.Context, id uint, status string) (*model.Product, error)
+	GetProduct(ctx context.Context, id uint, includeHidden bool) (*model.Product, error)
+	ListProducts(ctx context.Context, req *model.ListProductRequest, includeHidden bool) ([]*model.Product, int64, error)
+	AddSKU(ctx context.Context, productID uint, req *model.SKURequest) (*model.ProductSKU, error)
+	UpdateSKU(ctx context.Context, skuID uint, req *model.SKURequest) (*model.ProductSKU, error)
+	AdjustStock(ctx context.Context, operatorID, skuID uint, req *model.AdjustStockRequest) (*model.ProductSKU, error)
+
+	GetCart(ctx context.Context, userID uint) (*model.Cart, error)
+	SetCartItem(ctx context.Context, userID, skuID uint, quantity int) (*model.Cart, error)
+	RemoveCartItem(ctx context.Context, userID, skuID uint) (*model.Cart, error)
+	ClearCart(ctx context.Context, userID uint) error
+
+	Checkout(ctx context.Context, userID uint, req *model.CheckoutRequest) (*model.StockReservation, error)
+	GetReservation(ctx context.Context, userID, id uint) (*model.StockReservation, error)
+	ReleaseReservation(ctx context.Context, userID, id uint) error
+	RunReservationReaper(ctx context.Context)
+}
+
+// shopService 商城服务实现
+type shopService struct {
+	shopRepo    repository.ShopRepository
+	fileService FileService
+}
+
+// NewShopService 创建商城服务
+func NewShopService(shopRepo repository.ShopRepository, fileService FileService) ShopService {
+	return &shopService{
+		shopRepo:    shopRepo,
+		fileService: fileService,
+	}
+}
+
+// CreateCategory 创建商品分类
+func (s *shopService) CreateCategory(ctx context.Context, req *model.SaveCategoryRequest) (*model.ProductCategory, error) {
+	category := &model.ProductCategory{Status: 1}
+	if err := s.applyCategoryRequest(ctx, category, req); err != nil {
+		return nil, err
+	}
+	if err := s.shopRepo.CreateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory 更新商品分类
+func (s *shopService) UpdateCategory(ctx context.Context, id uint, req *model.SaveCategoryRequest) (*model.ProductCategory, error) {
+	category, err := s.shopRepo.GetCategory(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "商品分类不存在")
+	}
+	if err := s.applyCategoryRequest(ctx, category, req); err != nil {
+		return nil, err
+	}
+	if err := s.shopRepo.UpdateCategory(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// applyCategoryRequest 校验并写入分类字段,分类最多两级
+func (s *shopService) applyCategoryRequest(ctx context.Context, category *model.ProductCategory, req *model.SaveCategoryRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("分类名称不能为空")
+	}
+	if req.ParentID != 0 {
+		if req.ParentID == category.ID {
+			return errors.New("上级分类不能是自身")
+		}
+		parent, err := s.shopRepo.GetCategory(ctx, req.ParentID)
+		if err != nil {
+			return checkNotFound(err, "上级分类不存在")
+		}
+		if parent.ParentID != 0 {
+			return errors.New("分类最多支持两级")
+		}
+	}
+	if req.Status != nil {
+		if *req.Status != 0 && *req.Status != 1 {
+			return errors.New("分类状态无效")
+		}
+		category.Status = *req.Status
+	}
+	category.ParentID = req.ParentID
+	category.Name = name
+	category.Sort = req.Sort
+	return nil
+}
+
+// ListCategories 获取商品分类,用户端只返回启用的分类
+func (s *shopService) ListCategories(ctx context.Context, includeDisabled bool) ([]*model.ProductCategory, error) {
+	return s.shopRepo.ListCategories(ctx, !includeDisabled)
+}
+
+// CreateProduct 创建商品,新商品为草稿状态,上架后用户可见
+func (s *shopService) CreateProduct(ctx context.Context, operatorID uint, req *model.CreateProductRequest) (*model.Product, error) {
+	if len(req.SKUs) == 0 {
+		return nil, errors.New("至少需要一个规格")
+	}
+	product := &model.Product{
+		Status:    model.ProductStatusDraft,
+		CreatedBy: operatorID,
+	}
+	if err := s.applyProductRequest(ctx, operatorID, product, req.CategoryID, req.Name, req.Description, req.ImageFileIDs); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(req.SKUs))
+	seen := make(map[string]bool, len(req.SKUs))
+	for _, skuReq := range req.SKUs {
+		sku := &model.ProductSKU{Stock: skuReq.Stock, Status: 1}
+		if err := applySKURequest(sku, skuReq); err != nil {
+			return nil, err
+		}
+		if seen[sku.SKUCode] {
+			return nil, fmt.Errorf("规格编码重复: %s", sku.SKUCode)
+		}
+		if sku.Stock < 0 {
+			return nil, errors.New("库存不能为负数")
+		}
+		seen[sku.SKUCode] = true
+		codes = append(codes, sku.SKUCode)
+		product.SKUs = append(product.SKUs, sku)
+	}
+	exists, err := s.shopRepo.SKUCodeExists(ctx, codes, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("规格编码已存在")
+	}
+
+	if err := s.shopRepo.CreateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return s.GetProduct(ctx, product.ID, true)
+}
+
+// UpdateProduct 更新商品基本信息
+func (s *shopService) UpdateProduct(ctx context.Context, operatorID, id uint, req *model.UpdateProductRequest) (*model.Product, error) {
+	product, err := s.shopRepo.GetProduct(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "商品不存在")
+	}
+	if err := s.applyProductRequest(ctx, operatorID, product, req.CategoryID, req.Name, req.Description, req.ImageFileIDs); err != nil {
+		return nil, err
+	}
+	if err := s.shopRepo.UpdateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+	return s.GetProduct(ctx, id, true)
+}
+
+// applyProductRequest 校验并写入商品基本信息,已保存的图片不要求属于当前操作人
+func (s *shopService) applyProductRequest(ctx context.Context, operatorID uint, product *model.Product, categoryID uint, name, description string, imageFileIDs []uint) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("商品名称不能为空")
+	}
+	if _, err := s.shopRepo.GetCategory(ctx, categoryID); err != nil {
+		return checkNotFound(err, "商品分类不存在")
+	}
+
+	existing := make(map[uint]bool, len(product.ImageFileIDs))
+	for _, fileID := range product.ImageFileIDs {
+		existing[fileID] = true
+	}
+	images := uniqueIDs(imageFileIDs)
+	if len(images) > 9 {
+		return errors.New("商品图片最多9张")
+	}
+	for _, fileID := range images {
+		if existing[fileID] {
+			continue
+		}
+		if _, err := s.fileService.GetOwnedImage(ctx, operatorID, fileID); err != nil {
+			return err
+		}
+	}
+
+	product.CategoryID = categoryID
+	product.Name = name
+	product.Description = description
+	product.ImageFileIDs = images
+	return nil
+}
+
+// UpdateProductStatus 商品上下架,上架时至少需要一个在售规格
+func (s *shopService) UpdateProductStatus(ctx context.Context, id uint, status string) (*model.Product, error) {
+	switch status {
+	case model.ProductStatusDraft, model.ProductStatusOnSale, model.ProductStatusOffSale:
+	default:
+		return nil, errors.New("商品状态无效")
+	}
+	product, err := s.shopRepo.GetProduct(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "商品不存在")
+	}
+	if status == model.ProductStatusOnSale {
+		onSale := false
+		for _, sku := range product.SKUs {
+			if sku.Status == 1 {
+				onSale = true
+				break
+			}
+		}
+		if !onSale {
+			return nil, errors.New("商品没有在售规格,无法上架")
+		}
+	}
+	if err := s.shopRepo.UpdateProductStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+	return s.GetProduct(ctx, id, true)
+}
+
+// GetProduct 获取商品详情,用户端只能查看已上架商品的在售规格
+func (s *shopService) GetProduct(ctx context.Context, id uint, includeHidden bool) (*model.Product, error) {
+	product, err := s.shopRepo.GetProduct(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "商品不存在")
+	}
+	if !includeHidden {
+		if product.Status != model.ProductStatusOnSale {
+			return nil, notFound("商品不存在")
+		}
+		skus := make([]*model.ProductSKU, 0, len(product.SKUs))
+		for _, sku := range product.SKUs {
+			if sku.Status == 1 {
+				skus = append(skus, sku)
+			}
+		}
+		product.SKUs = skus
+	}
+	s.fillImages(product)
+	return product, nil
+}
+
+// ListProducts 商品列表,按一级分类筛选时包含其子分类
+func (s *shopService) ListProducts(ctx context.Context, req *model.ListProductRequest, includeHidden bool) ([]*model.Product, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	filter := &repository.ProductFilter{
+		Keyword:  strings.TrimSpace(req.Keyword),
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+		Sort:     req.Sort,
+		Offset:   offset,
+		Limit:    limit,
+	}
+	if includeHidden {
+		filter.Status = req.Status
+	} else {
+		filter.Status = model.ProductStatusOnSale
+	}
+	if req.CategoryID != 0 {
+		categories, err := s.shopRepo.ListCategories(ctx, !includeHidden)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.CategoryIDs = []uint{req.CategoryID}
+		for _, c := range categories {
+			if c.ParentID == req.CategoryID {
+				filter.CategoryIDs = append(filter.CategoryIDs, c.ID)
+			}
+		}
+	}
+
+	products, total, err := s.shopRepo.ListProducts(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, product := range products {
+		s.fillImages(product)
+	}
+	return products, total, nil
+}
+
+// AddSKU 为商品新增规格
+func (s *shopService) AddSKU(ctx context.Context, productID uint, req *model.SKURequest) (*model.ProductSKU, error) {
+	if _, err := s.shopRepo.GetProduct(ctx, productID); err != nil {
+		return nil, checkNotFound(err, "商品不存在")
+	}
+	if req.Stock < 0 {
+		return nil, errors.New("库存不能为负数")
+	}
+	sku := &model.ProductSKU{ProductID: productID, Stock: req.Stock, Status: 1}
+	if err := applySKURequest(sku, req); err != nil {
+		return nil, err
+	}
+	exists, err := s.shopRepo.SKUCodeExists(ctx, []string{sku.SKUCode}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("规格编码已存在")
+	}
+	if err := s.shopRepo.CreateSKU(ctx, sku); err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+// UpdateSKU 更新规格信息,库存需通过库存调整接口修改
+func (s *shopService) UpdateSKU(ctx context.Context, skuID uint, req *model.SKURequest) (*model.ProductSKU, error) {
+	sku, err := s.shopRepo.GetSKU(ctx, skuID)
+	if err != nil {
+		return nil, checkNotFound(err, "规格不存在")
+	}
+	if err := applySKURequest(sku, req); err != nil {
+		return nil, err
+	}
+	exists, err := s.shopRepo.SKUCodeExists(ctx, []string{sku.SKUCode}, sku.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("规格编码已存在")
+	}
+	if err := s.shopRepo.UpdateSKU(ctx, sku); err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+// applySKURequest 校验并写入规格字段
+func applySKURequest(sku *model.ProductSKU, req *model.SKURequest) error {
+	code := strings.TrimSpace(req.SKUCode)
+	name := strings.TrimSpace(req.Name)
+	if code == "" || name == "" {
+		return errors.New("规格编码和名称不能为空")
+	}
+	if req.Price <= 0 {
+		return errors.New("规格价格必须大于0")
+	}
+	if req.Status != nil {
+		if *req.Status != 0 && *req.Status != 1 {
+			return errors.New("规格状态无效")
+		}
+		sku.Status = *req.Status
+	}
+	sku.SKUCode = code
+	sku.Name = name
+	sku.Attributes = req.Attributes
+	sku.Price = req.Price
+	return nil
+}
+
+// AdjustStock 入库或出库,出库后库存不能为负
+func (s *shopService) AdjustStock(ctx context.Context, operatorID, skuID uint, req *model.AdjustStockRequest) (*model.ProductSKU, error) {
+	if req.Delta == 0 {
+		return nil, errors.New("调整数量不能为0")
+	}
+	if _, err := s.shopRepo.GetSKU(ctx, skuID); err != nil {
+		return nil, checkNotFound(err, "规格不存在")
+	}
+	if err := s.shopRepo.AdjustStock(ctx, skuID, req.Delta); err != nil {
+		return nil, err
+	}
+	logger.Info(ctx, "库存调整",
+		logger.Int("sku_id", int(skuID)),
+		logger.Int("delta", req.Delta),
+		logger.String("reason", req.Reason),
+		logger.Int("operator_id", int(operatorID)),
+	)
+	return s.shopRepo.GetSKU(ctx, skuID)
+}
+
+// GetCart 获取购物车,按最新商品信息计算价格和可购买状态
+func (s *shopService) GetCart(ctx context.Context, userID uint) (*model.Cart, error) {
+	quantities, err := s.loadCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(quantities))
+	for skuID := range quantities {
+		ids = append(ids, skuID)
+	}
+	skus, err := s.shopRepo.GetSKUsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	cart := &model.Cart{Items: make([]*model.CartItem, 0, len(skus))}
+	found := make(map[uint]bool, len(skus))
+	for _, sku := range skus {
+		found[sku.ID] = true
+		quantity := quantities[sku.ID]
+		item := &model.CartItem{
+			SKUID:      sku.ID,
+			Quantity:   quantity,
+			ProductID:  sku.ProductID,
+			SKUName:    sku.Name,
+			Attributes: sku.Attributes,
+			Price:      sku.Price,
+			Stock:      sku.Stock,
+			Available:  sku.Purchasable() && sku.Stock >= quantity,
+			Subtotal:   sku.Price * int64(quantity),
+		}
+		if sku.Product != nil {
+			item.ProductName = sku.Product.Name
+			if len(sku.Product.ImageFileIDs) > 0 {
+				item.Image = s.fileService.SignedURL(sku.Product.ImageFileIDs[0], model.FileVariantThumbnail)
+			}
+		}
+		cart.Items = append(cart.Items, item)
+		cart.TotalQuantity += quantity
+		if item.Available {
+			cart.TotalAmount += item.Subtotal
+		}
+	}
+
+	// 规格已被删除的条目从购物车中移除
+	var stale []string
+	for skuID := range quantities {
+		if !found[skuID] {
+			stale = append(stale, strconv.FormatUint(uint64(skuID), 10))
+		}
+	}
+	if len(stale) > 0 {
+		_ = redis.HDel(ctx, cartKey(userID), stale...)
+	}
+	return cart, nil
+}
+
+// SetCartItem 设置购物车条目数量,数量为0时移除
+func (s *shopService) SetCartItem(ctx context.Context, userID, skuID uint, quantity int) (*model.Cart, error) {
+	if quantity < 0 || quantity > 99 {
+		return nil, errors.New("商品数量需在0-99之间")
+	}
+	if quantity == 0 {
+		return s.RemoveCartItem(ctx, userID, skuID)
+	}
+
+	skus, err := s.shopRepo.GetSKUsByIDs(ctx, []uint{skuID})
+	if err != nil {
+		return nil, err
+	}
+	if len(skus) == 0 || !skus[0].Purchasable() {
+		return nil, notFound("商品不存在或已下架")
+	}
+	if skus[0].Stock < quantity {
+		return nil, fmt.Errorf("库存不足,当前库存%d", skus[0].Stock)
+	}
+
+	quantities, err := s.loadCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := quantities[skuID]; !ok && len(quantities) >= cartMaxItems {
+		return nil, fmt.Errorf("购物车最多%d种商品", cartMaxItems)
+	}
+
+	key := cartKey(userID)
+	if err := redis.HSet(ctx, key, strconv.FormatUint(uint64(skuID), 10), quantity); err != nil {
+		return nil, err
+	}
+	_ = redis.Expire(ctx, key, cartTTL)
+	return s.GetCart(ctx, userID)
+}
+
+// RemoveCartItem 移除购物车条目
+func (s *shopService) RemoveCartItem(ctx context.Context, userID, skuID uint) (*model.Cart, error) {
+	if err := redis.HDel(ctx, cartKey(userID), strconv.FormatUint(uint64(skuID), 10)); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
+}
+
+// ClearCart 清空购物车
+func (s *shopService) ClearCart(ctx context.Context, userID uint) error {
+	return redis.Del(ctx, cartKey(userID))
+}
+
+// Checkout 结算购物车并预占库存,预占成功后从购物车移除对应条目
+func (s *shopService) Checkout(ctx context.Context, userID uint, req *model.CheckoutRequest) (*model.StockReservation, error) {
+	quantities, err := s.loadCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := uniqueIDs(req.SKUIDs)
+	if len(selected) == 0 {
+		for skuID := range quantities {
+			selected = append(selected, skuID)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("购物车为空")
+	}
+	for _, skuID := range selected {
+		if _, ok := quantities[skuID]; !ok {
+			return nil, fmt.Errorf("商品规格%d不在购物车中", skuID)
+		}
+	}
+
+	skus, err := s.shopRepo.GetSKUsByIDs(ctx, selected)
+	if err != nil {
+		return nil, err
+	}
+	if len(skus) != len(selected) {
+		return nil, errors.New("部分商品已不存在,请刷新购物车")
+	}
+
+	reservation := &model.StockReservation{
+		UserID:    userID,
+		Status:    model.ReservationStatusReserved,
+		ExpiresAt: time.Now().Add(reservationTTL),
+	}
+	fields := make([]string, 0, len(skus))
+	for _, sku := range skus {
+		if !sku.Purchasable() {
+			return nil, fmt.Errorf("商品已下架: %s", sku.Name)
+		}
+		quantity := quantities[sku.ID]
+		reservation.Items = append(reservation.Items, &model.ReservationItem{
+			SKUID:       sku.ID,
+			ProductID:   sku.ProductID,
+			ProductName: sku.Product.Name,
+			SKUName:     sku.Name,
+			Price:       sku.Price,
+			Quantity:    quantity,
+		})
+		reservation.TotalAmount += sku.Price * int64(quantity)
+		fields = append(fields, strconv.FormatUint(uint64(sku.ID), 10))
+	}
+
+	if err := s.shopRepo.Reserve(ctx, reservation); err != nil {
+		return nil, err
+	}
+	_ = redis.HDel(ctx, cartKey(userID), fields...)
+	return reservation, nil
+}
+
+// GetReservation 获取本人的库存预占
+func (s *shopService) GetReservation(ctx context.Context, userID, id uint) (*model.StockReservation, error) {
+	reservation, err := s.shopRepo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, checkNotFound(err, "预占记录不存在")
+	}
+	if reservation.UserID != userID {
+		return nil, notFound("预占记录不存在")
+	}
+	return reservation, nil
+}
+
+// ReleaseReservation 用户放弃结算,释放预占的库存
+func (s *shopService) ReleaseReservation(ctx context.Context, userID, id uint) error {
+	if _, err := s.GetReservation(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.shopRepo.ReleaseReservation(ctx, id)
+}
+
+// RunReservationReaper 后台释放超时未下单的库存预占,ctx取消后退出
+func (s *shopService) RunReservationReaper(ctx context.Context) {
+	ticker := time.NewTicker(reservationReapInterval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "库存预占回收已启动")
+	for {
+		s.releaseExpired(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "库存预占回收已停止")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseExpired 释放一批过期预占,单条失败只记录日志
+func (s *shopService) releaseExpired(ctx context.Context, now time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(ctx, "库存预占回收异常", logger.Any("panic", r))
+		}
+	}()
+
+	ids, err := s.shopRepo.ListExpiredReservations(ctx, now, reservationReapBatch)
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		_ = s.shopRepo.ReleaseReservation(ctx, id)
+	}
+	if len(ids) > 0 {
+		logger.Info(ctx, "释放过期库存预占", logger.Int("count", len(ids)))
+	}
+}
+
+// loadCart 读取购物车中的规格ID与数量
+func (s *shopService) loadCart(ctx context.Context, userID uint) (map[uint]int, error) {
+	fields, err := redis.HGetAll(ctx, cartKey(userID))
+	if err != nil {
+		return nil, err
+	}
+	quantities := make(map[uint]int, len(fields))
+	for field, value := range fields {
+		skuID, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			continue
+		}
+		quantity, err := strconv.Atoi(value)
+		if err != nil || quantity <= 0 {
+			continue
+		}
+		quantities[uint(skuID)] = quantity
+	}
+	return quantities, nil
+}
+
+// fillImages 生成商品图片的签名链接
+func (s *shopService) fillImages(product *model.Product) {
+	for _, fileID := range product.ImageFileIDs {
+		product.Images = append(product.Images, s.fileService.SignedURL(fileID, model.FileVariantOriginal))
+	}
+}
+
+// cartKey 购物车缓存键
+func cartKey(userID uint) string {
+	return fmt.Sprintf("cart:%d", userID)
+}
@@ -0,0 +1,624 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"pet-service/biz/model"
+	"pet-service/biz/repository"
+	"pet-service/pkg/geo"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/notifier"
+)
+
+const (
+	// maxCalendarDays 单次设置或查询档期的最大天数
+	maxCalendarDays = 366
+	// defaultCalendarDays 未指定区间时默认查询的天数
+	defaultCalendarDays = 60
+	// maxBookingNights 单次预约的最大晚数
+	maxBookingNights = 60
+)
+
+// SitterService 寄养看护服务接口
+type SitterService interface {
+	UpsertProfile(ctx context.Context, userID uint, req *model.UpsertSitterProfileRequest) (*model.SitterProfile, error)
+	GetMyProfile(ctx context.Context, userID uint) (*model.SitterProfile, error)
+	GetProfile(ctx context.Context, sitterID uint) (*model.SitterProfile, error)
+	SearchSitters(ctx context.Context, req *model.ListSitterRequest) ([]*model.SitterProfile, int64, error)
+	SetCalendar(ctx context.Context, userID uint, req *model.SetCalendarRequest) ([]*model.CalendarDay, error)
+	GetCalendar(ctx context.Context, sitterID uint, req *model.CalendarRequest) ([]*model.CalendarDay, error)
+
+	CreateBooking(ctx context.Context, userID, sitterID uint, req *model.CreateBookingRequest) (*model.SitterBooking, error)
+	GetBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error)
+	ListMyBookings(ctx context.Context, userID uint, req *model.ListBookingRequest) ([]*model.SitterBooking, int64, error)
+	AcceptBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error)
+	DeclineBooking(ctx context.Context, userID, bookingID uint, reason string) (*model.SitterBooking, error)
+	CancelBooking(ctx context.Context, userID, bookingID uint, reason string) (*model.SitterBooking, error)
+	CompleteBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error)
+}
+
+// sitterService 寄养看护服务实现
+type sitterService struct {
+	sitterRepo repository.SitterRepository
+	petRepo    repository.PetRepository
+	petService PetService
+	notifier   notifier.Notifier
+}
+
+// NewSitterService 创建寄养看护服务
+func NewSitterService(sitterRepo repository.SitterRepository, petRepo repository.PetRepository, petService PetService, n notifier.Notifier) SitterService {
+	return &sitterService{
+		sitterRepo: sitterRepo,
+		petRepo:    petRepo,
+		petService: petService,
+		notifier:   n,
+	}
+}
+
+// UpsertProfile 创建或更新当前用户的看护人资料
+func (s *sitterService) UpsertProfile(ctx context.Context, userID uint, req *model.UpsertSitterProfileRequest) (*model.SitterProfile, error) {
+	if strings.TrimSpace(req.Headline) == "" || req.City == "" {
+		return nil, errors.New("请填写标题和所在城市")
+	}
+	if len(req.Services) == 0 {
+		return nil, errors.New("请至少选择一项服务")
+	}
+	for _, service := range req.Services {
+		if !model.SitterServices[service] {
+			return nil, fmt.Errorf("服务类型不支持: %s", service)
+		}
+	}
+	if len(req.AcceptedSpecies) == 0 {
+		return nil, errors.New("请至少选择一种接受的物种")
+	}
+	if req.PricePerNight < 0 {
+		return nil, errors.New("价格不能为负数")
+	}
+	hasPoint := req.Latitude != 0 || req.Longitude != 0
+	if hasPoint && !geo.ValidCoordinate(req.Latitude, req.Longitude) {
+		return nil, errors.New("经纬度不合法")
+	}
+	if req.ServiceRadiusKm < 0 || req.ServiceRadiusKm > 100 {
+		return nil, errors.New("服务半径需在0-100km之间")
+	}
+	if req.Status != nil && *req.Status != 0 && *req.Status != 1 {
+		return nil, errors.New("状态不合法")
+	}
+
+	profile, err := s.sitterRepo.GetProfileByUser(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		profile = &model.SitterProfile{UserID: userID, Status: 1}
+	}
+
+	profile.Headline = req.Headline
+	profile.Bio = req.Bio
+	profile.Services = req.Services
+	profile.AcceptedSpecies = req.AcceptedSpecies
+	profile.PricePerNight = req.PricePerNight
+	profile.MaxPets = req.MaxPets
+	if profile.MaxPets <= 0 {
+		profile.MaxPets = 1
+	}
+	profile.City = req.City
+	profile.ServiceArea = req.ServiceArea
+	profile.Latitude = req.Latitude
+	profile.Longitude = req.Longitude
+	profile.ServiceRadiusKm = req.ServiceRadiusKm
+	if req.Status != nil {
+		profile.Status = *req.Status
+	}
+
+	if err := s.sitterRepo.SaveProfile(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// GetMyProfile 获取当前用户的看护人资料
+func (s *sitterService) GetMyProfile(ctx context.Context, userID uint) (*model.SitterProfile, error) {
+	profile, err := s.sitterRepo.GetProfileByUser(ctx, userID)
+	if err != nil {
+		return nil, checkNotFound(err, "尚未创建看护人资料")
+	}
+	return profile, nil
+}
+
+// GetProfile 获取看护人公开资料
+func (s *sitterService) GetProfile(ctx context.Context, sitterID uint) (*model.SitterProfile, error) {
+	profile, err := s.sitterRepo.GetProfile(ctx, sitterID)
+	if err != nil {
+		return nil, checkNotFound(err, "看护人不存在")
+	}
+	return profile, nil
+}
+
+// SearchSitters 按城市、物种、服务、价格、档期和位置检索看护人
+func (s *sitterService) SearchSitters(ctx context.Context, req *model.ListSitterRequest) ([]*model.SitterProfile, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	filter := &repository.SitterFilter{
+		City:     req.City,
+		Species:  req.Species,
+		Service:  req.Service,
+		MaxPrice: req.MaxPrice,
+		Offset:   offset,
+		Limit:    limit,
+	}
+	if req.StartDate != "" && req.EndDate != "" {
+		from, to, nights, err := parseStayRange(req.StartDate, req.EndDate)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.StartDate, filter.EndDate, filter.Nights = &from, &to, nights
+	}
+	if req.Latitude != 0 || req.Longitude != 0 {
+		if !geo.ValidCoordinate(req.Latitude, req.Longitude) {
+			return nil, 0, errors.New("经纬度不合法")
+		}
+		filter.Latitude, filter.Longitude, filter.HasPoint = req.Latitude, req.Longitude, true
+	}
+
+	profiles, total, err := s.sitterRepo.SearchProfiles(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if filter.HasPoint {
+		for _, p := range profiles {
+			d := geo.Distance(req.Latitude, req.Longitude, p.Latitude, p.Longitude)
+			d = float64(int(d*100)) / 100
+			p.Distance = &d
+		}
+	}
+	return profiles, total, nil
+}
+
+// SetCalendar 设置[start_date, end_date]区间的档期,已被预约占用的日期不受影响
+func (s *sitterService) SetCalendar(ctx context.Context, userID uint, req *model.SetCalendarRequest) ([]*model.CalendarDay, error) {
+	profile, err := s.GetMyProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseDate(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	last, err := parseDate(req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if last.Before(from) {
+		return nil, errors.New("结束日期不能早于开始日期")
+	}
+	if from.Before(today()) {
+		return nil, errors.New("不能设置过去日期的档期")
+	}
+	to := last.AddDate(0, 0, 1)
+	if daysBetween(from, to) > maxCalendarDays {
+		return nil, errors.New("单次最多设置366天")
+	}
+
+	status := model.CalendarUnavailable
+	if req.Available {
+		status = model.CalendarAvailable
+	}
+	var dates []time.Time
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+	if err := s.sitterRepo.SetCalendar(ctx, profile.ID, dates, status); err != nil {
+		return nil, err
+	}
+	return s.calendar(ctx, profile.ID, from, to)
+}
+
+// GetCalendar 查询看护人档期,未设置的日期视为不可约
+func (s *sitterService) GetCalendar(ctx context.Context, sitterID uint, req *model.CalendarRequest) ([]*model.CalendarDay, error) {
+	if _, err := s.GetProfile(ctx, sitterID); err != nil {
+		return nil, err
+	}
+
+	from := today()
+	if req.StartDate != "" {
+		d, err := parseDate(req.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		from = d
+	}
+	to := from.AddDate(0, 0, defaultCalendarDays)
+	if req.EndDate != "" {
+		d, err := parseDate(req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		to = d.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		return nil, errors.New("结束日期不能早于开始日期")
+	}
+	if daysBetween(from, to) > maxCalendarDays {
+		return nil, errors.New("单次最多查询366天")
+	}
+	return s.calendar(ctx, sitterID, from, to)
+}
+
+// calendar 按天展开[from, to)区间的档期
+func (s *sitterService) calendar(ctx context.Context, sitterID uint, from, to time.Time) ([]*model.CalendarDay, error) {
+	days, err := s.sitterRepo.ListCalendar(ctx, sitterID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]string, len(days))
+	for _, d := range days {
+		statuses[d.Date.Format(model.DateLayout)] = d.Status
+	}
+
+	result := make([]*model.CalendarDay, 0, daysBetween(from, to))
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		key := d.Format(model.DateLayout)
+		status, ok := statuses[key]
+		if !ok {
+			status = model.CalendarUnavailable
+		}
+		result = append(result, &model.CalendarDay{Date: key, Status: status})
+	}
+	return result, nil
+}
+
+// CreateBooking 向看护人发起寄养预约,所选日期需全部可约
+func (s *sitterService) CreateBooking(ctx context.Context, userID, sitterID uint, req *model.CreateBookingRequest) (*model.SitterBooking, error) {
+	profile, err := s.GetProfile(ctx, sitterID)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Status != 1 {
+		return nil, errors.New("该看护人暂停接单")
+	}
+	if profile.UserID == userID {
+		return nil, errors.New("不能预约自己的寄养服务")
+	}
+	if !profile.OffersService(req.Service) {
+		return nil, errors.New("该看护人不提供此项服务")
+	}
+
+	petIDs := uniqueIDs(req.PetIDs)
+	if len(petIDs) == 0 {
+		return nil, errors.New("请选择需要寄养的宠物")
+	}
+	if len(petIDs) > profile.MaxPets {
+		return nil, fmt.Errorf("该看护人单次最多接待%d只宠物", profile.MaxPets)
+	}
+	for _, petID := range petIDs {
+		pet, err := s.petService.Authorize(ctx, userID, petID, model.PetRoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if !profile.AcceptsSpecies(pet.Species) {
+			return nil, fmt.Errorf("该看护人不接待%s", pet.Species)
+		}
+	}
+
+	from, to, nights, err := parseStayRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if from.Before(today()) {
+		return nil, errors.New("开始日期不能早于今天")
+	}
+	available, err := s.sitterRepo.CountAvailable(ctx, profile.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if available != int64(nights) {
+		return nil, errors.New("所选日期看护人不可约")
+	}
+	exists, err := s.sitterRepo.HasOverlappingBooking(ctx, profile.ID, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("该时段已有预约申请")
+	}
+
+	booking := &model.SitterBooking{
+		SitterID:     profile.ID,
+		SitterUserID: profile.UserID,
+		OwnerID:      userID,
+		PetIDs:       petIDs,
+		Service:      req.Service,
+		StartDate:    from,
+		EndDate:      to,
+		Nights:       nights,
+		TotalPrice:   profile.PricePerNight * int64(nights),
+		Message:      req.Message,
+		Status:       model.BookingStatusRequested,
+	}
+	if err := s.sitterRepo.CreateBooking(ctx, booking); err != nil {
+		return nil, err
+	}
+	s.notifyBooking(ctx, booking, booking.SitterUserID, "新的寄养预约",
+		fmt.Sprintf("收到%s至%s共%d晚的寄养申请", booking.StartDate.Format(model.DateLayout), booking.EndDate.Format(model.DateLayout), booking.Nights))
+	return booking, nil
+}
+
+// GetBooking 获取预约详情,仅主人和看护人可见
+func (s *sitterService) GetBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error) {
+	booking, err := s.getBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.OwnerID != userID && booking.SitterUserID != userID {
+		return nil, forbidden("无权查看该预约")
+	}
+	for _, petID := range booking.PetIDs {
+		pet, err := s.petRepo.GetByID(ctx, petID)
+		if err != nil {
+			continue
+		}
+		booking.Pets = append(booking.Pets, pet)
+	}
+	return booking, nil
+}
+
+// ListMyBookings 获取我发起的或我收到的预约
+func (s *sitterService) ListMyBookings(ctx context.Context, userID uint, req *model.ListBookingRequest) ([]*model.SitterBooking, int64, error) {
+	offset, limit := pageOffset(req.Page, req.PageSize)
+	if req.Role != "sitter" {
+		return s.sitterRepo.ListBookingsByOwner(ctx, userID, req.Status, offset, limit)
+	}
+	profile, err := s.GetMyProfile(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.sitterRepo.ListBookingsBySitter(ctx, profile.ID, req.Status, offset, limit)
+}
+
+// AcceptBooking 看护人接受预约,同时占用档期并拒绝冲突的其他申请
+func (s *sitterService) AcceptBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error) {
+	booking, err := s.getSitterBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != model.BookingStatusRequested {
+		return nil, errors.New("当前状态不允许该操作")
+	}
+	if booking.StartDate.Before(today()) {
+		return nil, errors.New("预约开始日期已过")
+	}
+
+	now := time.Now()
+	booking.RespondedAt = &now
+	if err := s.sitterRepo.AcceptBooking(ctx, booking); err != nil {
+		return nil, err
+	}
+	booking.Status = model.BookingStatusAccepted
+	s.notifyBooking(ctx, booking, booking.OwnerID, "寄养预约已接受",
+		fmt.Sprintf("%s开始的寄养预约已被看护人接受", booking.StartDate.Format(model.DateLayout)))
+	return booking, nil
+}
+
+// DeclineBooking 看护人拒绝预约
+func (s *sitterService) DeclineBooking(ctx context.Context, userID, bookingID uint, reason string) (*model.SitterBooking, error) {
+	booking, err := s.getSitterBooking(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.Status != model.BookingStatusRequested {
+		return nil, errors.New("当前状态不允许该操作")
+	}
+
+	now := time.Now()
+	booking.Status = model.BookingStatusDeclined
+	booking.DeclineReason = reason
+	booking.RespondedAt = &now
+	if err := s.sitterRepo.UpdateBookingStatus(ctx, booking, model.BookingStatusRequested); err != nil {
+		return nil, err
+	}
+	s.notifyBooking(ctx, booking, booking.OwnerID, "寄养预约被拒绝",
+		fmt.Sprintf("%s开始的寄养预约被看护人拒绝", booking.StartDate.Format(model.DateLayout)))
+	return booking, nil
+}
+
+// CancelBooking 主人撤回申请或在开始前取消预约,看护人可在开始前取消已接受的预约
+func (s *sitterService) CancelBooking(ctx context.Context, userID, bookingID uint, reason string) (*model.SitterBooking, error) {
+	booking, err := s.getBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	isOwner := booking.OwnerID == userID
+	if !isOwner && booking.SitterUserID != userID {
+		return nil, forbidden("无权操作该预约")
+	}
+
+	switch {
+	case booking.Status == model.BookingStatusRequested && isOwner:
+	case booking.Status == model.BookingStatusAccepted:
+		if !booking.StartDate.After(today()) {
+			return nil, errors.New("寄养已开始,无法取消")
+		}
+	default:
+		return nil, errors.New("当前状态不允许该操作")
+	}
+
+	fromStatus := booking.Status
+	booking.DeclineReason = reason
+	if err := s.sitterRepo.CancelBooking(ctx, booking, fromStatus); err != nil {
+		return nil, err
+	}
+	booking.Status = model.BookingStatusCancelled
+	logger.Info(ctx, "取消寄养预约", logger.Int("id", int(bookingID)), logger.Int("user_id", int(userID)))
+	recipient := booking.SitterUserID
+	if !isOwner {
+		recipient = booking.OwnerID
+	}
+	s.notifyBooking(ctx, booking, recipient, "寄养预约已取消",
+		fmt.Sprintf("%s开始的寄养预约已被对方取消", booking.StartDate.Format(model.DateLayout)))
+	return booking, nil
+}
+
+// CompleteBooking 寄养结束后由主人或看护人确认完成,完成后可评价
+func (s *sitterService) CompleteBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error) {
+	booking, err := s.getBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.OwnerID != userID && booking.SitterUserID != userID {
+		return nil, forbidden("无权操作该预约")
+	}
+	if booking.Status != model.BookingStatusAccepted {
+		return nil, errors.New("当前状态不允许该操作")
+	}
+	// 结束日期为离开当天,最后一晚结束后即可确认完成
+	if today().Before(booking.EndDate) {
+		return nil, errors.New("寄养尚未结束")
+	}
+
+	now := time.Now()
+	booking.CompletedAt = &now
+	if err := s.sitterRepo.CompleteBooking(ctx, booking); err != nil {
+		return nil, err
+	}
+	booking.Status = model.BookingStatusCompleted
+	recipient := booking.SitterUserID
+	if userID == booking.SitterUserID {
+		recipient = booking.OwnerID
+	}
+	s.notifyBooking(ctx, booking, recipient, "寄养已完成", "寄养已确认完成,欢迎评价本次服务")
+	return booking, nil
+}
+
+// notifyBooking 通知预约相关方,发送失败不影响预约操作
+func (s *sitterService) notifyBooking(ctx context.Context, booking *model.SitterBooking, userID uint, title, content string) {
+	err := s.notifier.Notify(ctx, &notifier.Message{
+		UserID:  userID,
+		Type:    model.NotifyTypeSitterBooking,
+		Title:   title,
+		Content: content,
+		Data: map[string]interface{}{
+			"booking_id": booking.ID,
+			"status":     booking.Status,
+		},
+	})
+	if err != nil {
+		logger.Error(ctx, "发送寄养预约通知失败", logger.Int("booking_id", int(booking.ID)), logger.Int("user_id", int(userID)), logger.ErrorField(err))
+	}
+}
+
+// getBooking 获取预约
+func (s *sitterService) getBooking(ctx context.Context, bookingID uint) (*model.SitterBooking, error) {
+	booking, err := s.sitterRepo.GetBooking(ctx, bookingID)
+	if err != nil {
+		return nil, checkNotFound(err, "预约不存在")
+	}
+	return booking, nil
+}
+
+// getSitterBooking 获取预约并校验当前用户是否为该预约的看护人
+func (s *sitterService) getSitterBooking(ctx context.Context, userID, bookingID uint) (*model.SitterBooking, error) {
+	booking, err := s.getBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.SitterUserID != userID {
+		logger.Warn(ctx, "非看护人操作预约", logger.Int("booking_id", int(bookingID)), logger.Int("user_id", int(userID)))
+		return nil, forbidden("仅看护人可执行该操作")
+	}
+	return booking, nil
+}
+
+// parseDate 解析YYYY-MM-DD格式的日期
+func parseDate(value string) (time.Time, error) {
+	d, err := time.ParseInLocation(model.DateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日期格式错误,应为YYYY-MM-DD: %s", value)
+	}
+	return d, nil
+}
+
+// parseStayRange 解析入住与离开日期,返回晚数
+func parseStayRange(start, end string) (time.Time, time.Time, int, error) {
+	from, err := parseDate(start)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, err
+	}
+	to, err := parseDate(end)
+	if err != nil {
+		return time.Time{}, time.Time{}, 0, err
+	}
+	nights := daysBetween(from, to)
+	if nights <= 0 {
+		return time.Time{}, time.Time{}, 0, errors.New("离开日期必须晚于开始日期")
+	}
+	if nights > maxBookingNights {
+		return time.Time{}, time.Time{}, 0, fmt.Errorf("单次预约最多%d晚", maxBookingNights)
+	}
+	return from, to, nights, nil
+}
+
+// today 返回本地时区的今天零点
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// daysBetween 计算两个日期相差的天数,按日历日计算以避免夏令时影响
+func daysBetween(from, to time.Time) int {
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// uniqueIDs 去除重复和无效的ID并保持顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// sitterReviewSource 寄养预约评价来源:主人可在寄养完成后评价看护人
+type sitterReviewSource struct {
+	sitterRepo repository.SitterRepository
+}
+
+// NewSitterReviewSource 创建寄养预约评价来源
+func NewSitterReviewSource(sitterRepo repository.SitterRepository) ReviewSource {
+	return &sitterReviewSource{sitterRepo: sitterRepo}
+}
+
+// ReviewTarget 校验预约属于该主人且已完成
+func (r *sitterReviewSource) ReviewTarget(ctx context.Context, userID, bookingID uint) (*ReviewTarget, error) {
+	booking, err := r.sitterRepo.GetBooking(ctx, bookingID)
+	if err != nil {
+		return nil, checkNotFound(err, "预约不存在")
+	}
+	if booking.OwnerID != userID {
+		return nil, forbidden("只能评价自己的寄养预约")
+	}
+	if !booking.Reviewable() {
+		return nil, errors.New("寄养完成后才能评价")
+	}
+	return &ReviewTarget{Type: model.ReviewTargetSitter, ID: booking.SitterID}, nil
+}
+
+// IsProvider 看护人本人可回复评价
+func (r *sitterReviewSource) IsProvider(ctx context.Context, target *ReviewTarget, userID uint) (bool, error) {
+	profile, err := r.sitterRepo.GetProfile(ctx, target.ID)
+	if err != nil {
+		return false, checkNotFound(err, "看护人不存在")
+	}
+	return profile.UserID == userID, nil
+}
@@ -11,6 +11,7 @@ import (
 	"pet-service/biz/repository"
 	"pet-service/pkg/jwt"
 	"pet-service/pkg/logger"
+	"pet-service/pkg/rbac"
 	"pet-service/pkg/redis"
 )
 
@@ -22,16 +23,21 @@ type UserService interface {
 	GetUser(ctx context.Context, id uint) (*model.User, error)
 	GetUserList(ctx context.Context, req *model.ListUserRequest) ([]*model.User, int64, error)
 	Login(ctx context.Context, req *model.LoginRequest, jwtManager *jwt.JWTManager) (*model.LoginResponse, error)
+	UpdateRole(ctx context.Context, operatorID, id uint, role string) (*model.User, error)
 }
 
 // userService 用户服务实现
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	fileService FileService
 }
 
 // NewUserService 创建用户服务
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, fileService FileService) UserService {
+	return &userService{
+		userRepo:    userRepo,
+		fileService: fileService,
+	}
 }
 
 // CreateUser 创建用户
@@ -99,6 +105,14 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req *model.Update
 	}
 	if req.Avatar != "" {
 		user.Avatar = req.Avatar
+		user.AvatarFileID = 0
+	}
+	if req.AvatarFileID != 0 {
+		// 头像文件必须是该用户自己上传的图片
+		if _, err := s.fileService.GetOwnedImage(ctx, id, req.AvatarFileID); err != nil {
+			return nil, err
+		}
+		user.AvatarFileID = req.AvatarFileID
 	}
 	if req.Status != nil {
 		user.Status = *req.Status
@@ -112,7 +126,7 @@ func (s *userService) UpdateUser(ctx context.Context, id uint, req *model.Update
 	_ = redis.Del(ctx, "users:all", fmt.Sprintf("user:%d", id))
 
 	logger.Info(ctx, "用户更新成功", logger.Int("id", int(id)))
-	return user, nil
+	return s.presentUser(user), nil
 }
 
 // DeleteUser 删除用户
@@ -152,7 +166,7 @@ func (s *userService) GetUser(ctx context.Context, id uint) (*model.User, error)
 	// 	redis.Set(ctx, cacheKey, data, 5*time.Minute)
 	// }
 
-	return user, nil
+	return s.presentUser(user), nil
 }
 
 // GetUserList 获取用户列表
@@ -170,6 +184,10 @@ func (s *userService) GetUserList(ctx context.Context, req *model.ListUserReques
 		logger.Int64("total", total),
 	)
 
+	for _, user := range users {
+		s.presentUser(user)
+	}
+
 	return users, total, nil
 }
 
@@ -195,7 +213,7 @@ func (s *userService) Login(ctx context.Context, req *model.LoginRequest, jwtMan
 	}
 
 	// 生成token
-	token, expiresIn, err := jwtManager.GenerateToken(user.ID, user.Username)
+	token, expiresIn, err := jwtManager.GenerateToken(user.ID, user.Username, user.Role)
 	if err != nil {
 		logger.Error(ctx, "生成token失败", logger.ErrorField(err))
 		return nil, errors.New("生成token失败")
@@ -207,22 +225,55 @@ func (s *userService) Login(ctx context.Context, req *model.LoginRequest, jwtMan
 
 	logger.Info(ctx, "用户登录成功", logger.String("username", req.Username))
 
+	s.presentUser(user)
+
 	response := &model.LoginResponse{
 		Token:     token,
 		TokenType: "Bearer",
 		ExpiresIn: expiresIn,
 		User: model.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			Phone:     user.Phone,
-			Nickname:  user.Nickname,
-			Avatar:    user.Avatar,
-			Status:    user.Status,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
+			ID:           user.ID,
+			Username:     user.Username,
+			Email:        user.Email,
+			Phone:        user.Phone,
+			Nickname:     user.Nickname,
+			Avatar:       user.Avatar,
+			AvatarFileID: user.AvatarFileID,
+			Status:       user.Status,
+			Role:         user.Role,
+			CreatedAt:    user.CreatedAt,
+			UpdatedAt:    user.UpdatedAt,
 		},
 	}
 
 	return response, nil
 }
+
+// UpdateRole 修改用户角色,新角色在用户重新登录后生效
+func (s *userService) UpdateRole(ctx context.Context, operatorID, id uint, role string) (*model.User, error) {
+	if !rbac.ValidRole(role) {
+		return nil, errors.New("角色不存在")
+	}
+	if operatorID == id {
+		return nil, errors.New("不能修改自己的角色")
+	}
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateRole(ctx, id, role); err != nil {
+		return nil, err
+	}
+	_ = redis.Del(ctx, "users:all", fmt.Sprintf("user:%d", id))
+	logger.Info(ctx, "修改用户角色", logger.Int("id", int(id)), logger.String("role", role), logger.Int("operator_id", int(operatorID)))
+	user.Role = role
+	return s.presentUser(user), nil
+}
+
+// presentUser 头像为上传文件时替换为带签名的缩略图链接,仅用于响应,不回写数据库
+func (s *userService) presentUser(user *model.User) *model.User {
+	if user != nil && user.AvatarFileID != 0 && s.fileService != nil {
+		user.Avatar = s.fileService.SignedURL(user.AvatarFileID, model.FileVariantThumbnail)
+	}
+	return user
+}
@@ -8,11 +8,61 @@ import (
 
 // Config 应用配置
 type Config struct {
-	Server   ServerConfig
-	Redis    RedisConfig
-	Database DatabaseConfig
-	Log      LogConfig
-	JWT      JWTConfig
+	Server      ServerConfig
+	Redis       RedisConfig
+	Database    DatabaseConfig
+	Log         LogConfig
+	JWT         JWTConfig
+	Storage     StorageConfig
+	Health      HealthConfig
+	Payment     PaymentConfig
+	Document    DocumentConfig
+	Appointment AppointmentConfig
+}
+
+// StorageConfig 文件存储配置
+type StorageConfig struct {
+	Driver        string // local 或 s3
+	LocalDir      string
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	MaxUploadSize int64
+	SignSecret    string
+	URLExpire     time.Duration
+	PublicBaseURL string
+}
+
+// HealthConfig 健康指标配置
+type HealthConfig struct {
+	WeightAlertPercent    int // 窗口期内体重变化超过该百分比时提醒
+	WeightAlertWindowDays int
+}
+
+// PaymentConfig 支付配置
+type PaymentConfig struct {
+	Driver       string // 支付网关,目前支持fake(本地模拟)
+	FakeSecret   string // fake网关回调签名密钥
+	OrderTimeout time.Duration
+	// AllowSimulate 是否开放模拟支付接口,任何登录用户都可借此免付款完成订单,仅限开发测试环境开启
+	AllowSimulate bool
+}
+
+// DocumentConfig PDF文档配置
+type DocumentConfig struct {
+	FontPath string // 包含中文字形的TTF字体路径,为空时使用内置英文字体与英文模板
+}
+
+// AppointmentConfig 门诊预约配置,营业时间按服务器本地时区
+type AppointmentConfig struct {
+	SlotMinutes    int // 可约开始时间的间隔
+	OpenHour       int // 每天最早可约时间(时)
+	CloseHour      int // 每天服务结束时间(时),预约须在此之前结束
+	Capacity       int // 同一诊所同一时段可同时接待的预约数
+	MinLeadMinutes int // 至少提前多少分钟预约
+	MaxDaysAhead   int // 最多提前多少天预约
 }
 
 // JWTConfig JWT配置
@@ -27,6 +77,7 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	PublicURL    string // 对外访问地址,用于生成公开主页链接和二维码
 }
 
 // RedisConfig Redis配置
@@ -63,6 +114,7 @@ func Load() *Config {
 			ReadTimeout:  60 * time.Second,
 			WriteTimeout: 60 * time.Second,
 			IdleTimeout:  120 * time.Second,
+			PublicURL:    getEnv("SERVER_PUBLIC_URL", "http://localhost:8888"),
 		},
 		Redis: RedisConfig{
 			Addr:     getEnv("REDIS_ADDR", "localhost:6379"),
@@ -88,6 +140,40 @@ func Load() *Config {
 			Secret:        getEnv("JWT_SECRET", "pet-service-secret-key-2024"),
 			TokenDuration: getEnvInt("JWT_TOKEN_DURATION", 24),
 		},
+		Storage: StorageConfig{
+			Driver:        getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:      getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			S3Endpoint:    getEnv("STORAGE_S3_ENDPOINT", "http://localhost:9000"),
+			S3Region:      getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("STORAGE_S3_BUCKET", "pet-service"),
+			S3AccessKey:   getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("STORAGE_S3_SECRET_KEY", ""),
+			MaxUploadSize: int64(getEnvInt("STORAGE_MAX_UPLOAD_MB", 10)) << 20,
+			SignSecret:    getEnv("STORAGE_SIGN_SECRET", "pet-service-storage-secret"),
+			URLExpire:     time.Duration(getEnvInt("STORAGE_URL_EXPIRE_MINUTES", 30)) * time.Minute,
+			PublicBaseURL: getEnv("STORAGE_PUBLIC_BASE_URL", ""),
+		},
+		Health: HealthConfig{
+			WeightAlertPercent:    getEnvInt("HEALTH_WEIGHT_ALERT_PERCENT", 10),
+			WeightAlertWindowDays: getEnvInt("HEALTH_WEIGHT_ALERT_WINDOW_DAYS", 30),
+		},
+		Payment: PaymentConfig{
+			Driver:        getEnv("PAYMENT_DRIVER", "fake"),
+			FakeSecret:    getEnv("PAYMENT_FAKE_SECRET", "pet-service-payment-secret"),
+			OrderTimeout:  time.Duration(getEnvInt("ORDER_PAY_TIMEOUT_MINUTES", 30)) * time.Minute,
+			AllowSimulate: getEnvBool("PAYMENT_ALLOW_SIMULATE", false),
+		},
+		Document: DocumentConfig{
+			FontPath: getEnv("PDF_FONT_PATH", ""),
+		},
+		Appointment: AppointmentConfig{
+			SlotMinutes:    getEnvInt("APPOINTMENT_SLOT_MINUTES", 15),
+			OpenHour:       getEnvInt("APPOINTMENT_OPEN_HOUR", 9),
+			CloseHour:      getEnvInt("APPOINTMENT_CLOSE_HOUR", 20),
+			Capacity:       getEnvInt("APPOINTMENT_CAPACITY", 2),
+			MinLeadMinutes: getEnvInt("APPOINTMENT_MIN_LEAD_MINUTES", 60),
+			MaxDaysAhead:   getEnvInt("APPOINTMENT_MAX_DAYS_AHEAD", 60),
+		},
 	}
 }
 
@@ -12,20 +12,56 @@ import (
 	"github.com/cloudwego/hertz/pkg/app/server"
 	"gorm.io/gorm"
 	"pet-service/biz/handler"
+	"pet-service/biz/model"
 	"pet-service/biz/repository"
 	"pet-service/biz/service"
 	"pet-service/config"
 	"pet-service/pkg/database"
 	"pet-service/pkg/logger"
 	"pet-service/pkg/middleware"
+	"pet-service/pkg/notifier"
+	"pet-service/pkg/payment"
+	"pet-service/pkg/pdf"
+	"pet-service/pkg/rbac"
 	"pet-service/pkg/recovery"
 	"pet-service/pkg/redis"
+	"pet-service/pkg/storage"
 )
 
 var (
-	db          *gorm.DB
-	cfg         *config.Config
-	userHandler *handler.UserHandler
+	db                   *gorm.DB
+	cfg                  *config.Config
+	userHandler          *handler.UserHandler
+	petHandler           *handler.PetHandler
+	adoptionHandler      *handler.AdoptionHandler
+	lostFoundHandler     *handler.LostFoundHandler
+	fileHandler          *handler.FileHandler
+	measurementHandler   *handler.MeasurementHandler
+	petShareHandler      *handler.PetShareHandler
+	microchipHandler     *handler.MicrochipHandler
+	petProfileHandler    *handler.PetProfileHandler
+	medicationHandler    *handler.MedicationHandler
+	sitterHandler        *handler.SitterHandler
+	reviewHandler        *handler.ReviewHandler
+	shopHandler          *handler.ShopHandler
+	orderHandler         *handler.OrderHandler
+	couponHandler        *handler.CouponHandler
+	postHandler          *handler.PostHandler
+	followHandler        *handler.FollowHandler
+	clinicHandler        *handler.ClinicHandler
+	chatHandler          *handler.ChatHandler
+	notificationHandler  *handler.NotificationHandler
+	eventHandler         *handler.EventHandler
+	medicalRecordHandler *handler.MedicalRecordHandler
+	insuranceHandler     *handler.InsuranceHandler
+	documentHandler      *handler.DocumentHandler
+	activityHandler      *handler.ActivityHandler
+	dietHandler          *handler.DietHandler
+	appointmentHandler   *handler.AppointmentHandler
+	donationHandler      *handler.DonationHandler
+	prescriptionHandler  *handler.PrescriptionHandler
+	// stopWorkers 停止后台任务
+	stopWorkers context.CancelFunc = func() {}
 )
 
 func main() {
@@ -63,10 +99,158 @@ func main() {
 	} else {
 		logger.Info(context.Background(), "数据库连接成功")
 
+		// 初始化文件存储
+		store, err := storage.New(&cfg.Storage)
+		if err != nil {
+			logger.Fatal(context.Background(), "文件存储初始化失败", logger.ErrorField(err))
+		}
+		fileRepo := repository.NewFileRepository(db)
+		fileService := service.NewFileService(fileRepo, store, &cfg.Storage)
+		fileHandler = handler.NewFileHandler(fileService)
+
 		// 初始化仓储和服务
 		userRepo := repository.NewUserRepository(db)
-		userService := service.NewUserService(userRepo)
+		userService := service.NewUserService(userRepo, fileService)
 		userHandler = handler.NewUserHandler(userService)
+
+		petRepo := repository.NewPetRepository(db)
+		petShareRepo := repository.NewPetShareRepository(db)
+		petService := service.NewPetService(petRepo, petShareRepo)
+		petHandler = handler.NewPetHandler(petService)
+		petShareService := service.NewPetShareService(petShareRepo, userRepo, petService)
+		petShareHandler = handler.NewPetShareHandler(petShareService)
+
+		microchipRepo := repository.NewMicrochipRepository(db)
+		microchipService := service.NewMicrochipService(microchipRepo, petRepo, userRepo, petService)
+		microchipHandler = handler.NewMicrochipHandler(microchipService)
+
+		petProfileRepo := repository.NewPetProfileRepository(db)
+		petProfileService := service.NewPetProfileService(petProfileRepo, petRepo, userRepo, petService, cfg.Server.PublicURL)
+		petProfileHandler = handler.NewPetProfileHandler(petProfileService)
+
+		measurementRepo := repository.NewMeasurementRepository(db)
+		breedRepo := repository.NewBreedRepository(db)
+		measurementService := service.NewMeasurementService(measurementRepo, breedRepo, petService, &cfg.Health)
+		measurementHandler = handler.NewMeasurementHandler(measurementService)
+
+		// 后台任务,服务退出时通过stopWorkers停止
+		var workerCtx context.Context
+		workerCtx, stopWorkers = context.WithCancel(context.Background())
+
+		// 业务通知按用户偏好写入站内信或交给外部推送渠道
+		notificationRepo := repository.NewNotificationRepository(db)
+		notificationService := service.NewNotificationService(notificationRepo, notifier.NewLogNotifier())
+		notificationHandler = handler.NewNotificationHandler(notificationService)
+		go notificationService.RunSubscriber(workerCtx)
+
+		medicationRepo := repository.NewMedicationRepository(db)
+		medicationService := service.NewMedicationService(medicationRepo, petRepo, petShareRepo, petService, notificationService)
+		medicationHandler = handler.NewMedicationHandler(medicationService)
+		go medicationService.RunScheduler(workerCtx)
+
+		shelterRepo := repository.NewShelterRepository(db)
+		adoptionRepo := repository.NewAdoptionRepository(db)
+		adoptionService := service.NewAdoptionService(shelterRepo, adoptionRepo)
+		adoptionHandler = handler.NewAdoptionHandler(adoptionService)
+
+		lostFoundRepo := repository.NewLostFoundRepository(db)
+		lostFoundService := service.NewLostFoundService(lostFoundRepo, petService)
+		lostFoundHandler = handler.NewLostFoundHandler(lostFoundService)
+		go lostFoundService.RunGeoIndexer(workerCtx)
+
+		sitterRepo := repository.NewSitterRepository(db)
+		sitterService := service.NewSitterService(sitterRepo, petRepo, petService, notificationService)
+		sitterHandler = handler.NewSitterHandler(sitterService)
+
+		clinicRepo := repository.NewClinicRepository(db)
+		appointmentRepo := repository.NewAppointmentRepository(db)
+		reviewRepo := repository.NewReviewRepository(db)
+		reviewService := service.NewReviewService(reviewRepo, fileService, map[string]service.ReviewSource{
+			model.ReviewSourceSitterBooking:  service.NewSitterReviewSource(sitterRepo),
+			model.ReviewSourceAppointment:    service.NewAppointmentReviewSource(appointmentRepo, clinicRepo),
+			model.ReviewSourceAppointmentVet: service.NewAppointmentVetReviewSource(appointmentRepo),
+		})
+		reviewHandler = handler.NewReviewHandler(reviewService)
+
+		shopRepo := repository.NewShopRepository(db)
+		shopService := service.NewShopService(shopRepo, fileService)
+		shopHandler = handler.NewShopHandler(shopService)
+		go shopService.RunReservationReaper(workerCtx)
+
+		gateway, err := payment.New(&cfg.Payment)
+		if err != nil {
+			logger.Fatal(context.Background(), "支付网关初始化失败", logger.ErrorField(err))
+		}
+		couponRepo := repository.NewCouponRepository(db)
+		couponService := service.NewCouponService(couponRepo)
+		pointsRepo := repository.NewPointsRepository(db)
+		pointsService := service.NewPointsService(pointsRepo)
+		couponHandler = handler.NewCouponHandler(couponService, pointsService)
+
+		orderRepo := repository.NewOrderRepository(db)
+		donationRepo := repository.NewDonationRepository(db)
+		orderService := service.NewOrderService(orderRepo, shopRepo, sitterRepo, donationRepo, pointsRepo, couponService, gateway, cfg.Payment.OrderTimeout, cfg.Server.PublicURL)
+		orderService.OnPaid(model.OrderTypeShop, pointsService.EarnForOrder)
+		orderService.OnPaid(model.OrderTypeSitterBooking, pointsService.EarnForOrder)
+		orderHandler = handler.NewOrderHandler(orderService)
+		go orderService.RunExpirationWorker(workerCtx)
+
+		donationService := service.NewDonationService(donationRepo, userRepo, adoptionService, orderService, notificationService)
+		orderService.OnPaid(model.OrderTypeDonation, donationService.HandlePaid)
+		donationHandler = handler.NewDonationHandler(donationService)
+
+		followRepo := repository.NewFollowRepository(db)
+		followService := service.NewFollowService(followRepo, userRepo, petRepo, notificationService)
+		followHandler = handler.NewFollowHandler(followService)
+
+		postRepo := repository.NewPostRepository(db)
+		postService := service.NewPostService(postRepo, followRepo, petService, fileService)
+		postHandler = handler.NewPostHandler(postService)
+		go postService.RunCounterFlusher(workerCtx)
+
+		clinicService := service.NewClinicService(clinicRepo, userRepo)
+		clinicHandler = handler.NewClinicHandler(clinicService)
+
+		appointmentService := service.NewAppointmentService(appointmentRepo, clinicService, petService, pointsService, notificationService, &cfg.Appointment)
+		appointmentHandler = handler.NewAppointmentHandler(appointmentService)
+
+		chatRepo := repository.NewChatRepository(db)
+		chatService := service.NewChatService(chatRepo, clinicService, petService, fileService)
+		chatHandler = handler.NewChatHandler(chatService)
+		go chatService.RunSubscriber(workerCtx)
+
+		eventRepo := repository.NewEventRepository(db)
+		eventService := service.NewEventService(eventRepo, petService, notificationService, cfg.Server.PublicURL)
+		eventHandler = handler.NewEventHandler(eventService)
+		go eventService.RunScheduler(workerCtx)
+
+		medicalRecordRepo := repository.NewMedicalRecordRepository(db)
+		medicalRecordService := service.NewMedicalRecordService(medicalRecordRepo, petService, fileService)
+		medicalRecordHandler = handler.NewMedicalRecordHandler(medicalRecordService)
+
+		insuranceRepo := repository.NewInsuranceRepository(db)
+		insuranceService := service.NewInsuranceService(insuranceRepo, petService, medicalRecordService, fileService)
+		insuranceHandler = handler.NewInsuranceHandler(insuranceService)
+
+		prescriptionRepo := repository.NewPrescriptionRepository(db)
+		prescriptionService := service.NewPrescriptionService(prescriptionRepo, userRepo, medicalRecordService, petService, clinicService, notificationService)
+		prescriptionHandler = handler.NewPrescriptionHandler(prescriptionService)
+
+		renderer, err := pdf.NewRenderer(cfg.Document.FontPath)
+		if err != nil {
+			logger.Fatal(context.Background(), "PDF渲染器初始化失败", logger.ErrorField(err))
+		}
+		documentRepo := repository.NewDocumentRepository(db)
+		documentService := service.NewDocumentService(documentRepo, medicalRecordRepo, petService, orderService, appointmentService, donationService, renderer, cfg.Server.PublicURL)
+		documentHandler = handler.NewDocumentHandler(documentService)
+
+		activityRepo := repository.NewActivityRepository(db)
+		activityService := service.NewActivityService(activityRepo, measurementRepo, petService)
+		activityHandler = handler.NewActivityHandler(activityService)
+
+		dietRepo := repository.NewDietRepository(db)
+		dietService := service.NewDietService(dietRepo, measurementRepo, petService, shopService)
+		dietHandler = handler.NewDietHandler(dietService)
 	}
 
 	h := server.Default(
@@ -74,6 +258,8 @@ func main() {
 		server.WithReadTimeout(cfg.Server.ReadTimeout),
 		server.WithWriteTimeout(cfg.Server.WriteTimeout),
 		server.WithIdleTimeout(cfg.Server.IdleTimeout),
+		// 预留multipart表单字段的空间
+		server.WithMaxRequestBodySize(int(cfg.Storage.MaxUploadSize)+1<<20),
 	)
 
 	// 注册中间件
@@ -118,6 +304,11 @@ func registerRoutes(h *server.Hertz) {
 		})
 	})
 
+	// 宠物公开主页(吊牌二维码指向的地址)
+	if petProfileHandler != nil {
+		h.GET("/p/:slug", middleware.RateLimitMiddleware("pet_profile", 60, time.Minute), petProfileHandler.GetPublic)
+	}
+
 	// API v1 路由组
 	v1 := h.Group("/api/v1")
 	{
@@ -126,6 +317,40 @@ func registerRoutes(h *server.Hertz) {
 			// 公开路由 - 不需要认证
 			v1.POST("/login", userHandler.Login)
 			v1.POST("/users", userHandler.CreateUser)
+			v1.GET("/adoptions", adoptionHandler.SearchListings)
+			v1.GET("/adoptions/:id", adoptionHandler.GetListing)
+			v1.GET("/shelters/:id", adoptionHandler.GetShelter)
+			v1.GET("/lost-found/nearby", lostFoundHandler.SearchNearby)
+			v1.GET("/lost-found/:id", lostFoundHandler.GetReport)
+			v1.GET("/lost-found/:id/matches", lostFoundHandler.SuggestMatches)
+			v1.GET("/files/:id/download", fileHandler.Download)
+			v1.GET("/breeds", measurementHandler.ListBreeds)
+			v1.GET("/microchips/:code", middleware.RateLimitMiddleware("microchip_lookup", 20, time.Minute), microchipHandler.Lookup)
+			v1.POST("/microchips/:code/messages", middleware.RateLimitMiddleware("microchip_message", 5, time.Minute), microchipHandler.SendMessage)
+			v1.GET("/documents/verify/:code", middleware.RateLimitMiddleware("document_verify", 30, time.Minute), documentHandler.Verify)
+			v1.GET("/sitters", sitterHandler.SearchSitters)
+			v1.GET("/sitters/:id", sitterHandler.GetProfile)
+			v1.GET("/sitters/:id/calendar", sitterHandler.GetCalendar)
+			v1.GET("/reviews", reviewHandler.ListReviews)
+			v1.GET("/reviews/summary", reviewHandler.GetSummary)
+			v1.GET("/reviews/:id", reviewHandler.GetReview)
+			v1.GET("/shop/categories", shopHandler.ListCategories)
+			v1.GET("/shop/products", shopHandler.ListProducts)
+			v1.GET("/shop/products/:id", shopHandler.GetProduct)
+			v1.POST("/payments/:gateway/callback", orderHandler.PaymentCallback)
+			v1.GET("/clinics", clinicHandler.ListClinics)
+			v1.GET("/clinics/:id", clinicHandler.GetClinic)
+			v1.GET("/clinics/:id/services", appointmentHandler.ListServices)
+			v1.GET("/events", eventHandler.ListEvents)
+			v1.GET("/events/:id", eventHandler.GetEvent)
+			v1.GET("/events/:id/ics", eventHandler.ExportICS)
+			v1.GET("/donation-campaigns", donationHandler.ListCampaigns)
+			v1.GET("/donation-campaigns/:id", donationHandler.GetCampaign)
+			v1.GET("/donation-campaigns/:id/donors", donationHandler.ListDonors)
+
+			// 实时连接,浏览器可通过token查询参数认证
+			v1.GET("/ws/chat", middleware.QueryTokenMiddleware(), middleware.JWTAuthMiddleware(), chatHandler.Connect)
+			v1.GET("/me/notifications/stream", middleware.QueryTokenMiddleware(), middleware.JWTAuthMiddleware(), notificationHandler.Stream)
 
 			// 需要认证的路由
 			authGroup := v1.Group("")
@@ -140,6 +365,306 @@ func registerRoutes(h *server.Hertz) {
 					userGroup.GET("/:id", userHandler.GetUser)
 					userGroup.GET("", userHandler.GetUserList)
 				}
+
+				// 宠物路由
+				petGroup := authGroup.Group("/pets")
+				{
+					petGroup.POST("", petHandler.CreatePet)
+					petGroup.GET("", petHandler.ListMyPets)
+					petGroup.GET("/:id", petHandler.GetPet)
+					petGroup.PUT("/:id", petHandler.UpdatePet)
+					petGroup.DELETE("/:id", petHandler.DeletePet)
+					petGroup.POST("/:id/measurements", measurementHandler.RecordMeasurements)
+					petGroup.GET("/:id/measurements", measurementHandler.QueryMeasurements)
+					petGroup.GET("/:id/measurements/alerts", measurementHandler.GetAlerts)
+					petGroup.DELETE("/:id/measurements/:mid", measurementHandler.DeleteMeasurement)
+					petGroup.POST("/:id/invitations", petShareHandler.Invite)
+					petGroup.GET("/:id/invitations", petShareHandler.ListPetInvitations)
+					petGroup.GET("/:id/members", petShareHandler.ListMembers)
+					petGroup.PUT("/:id/members/:uid", petShareHandler.UpdateMemberRole)
+					petGroup.DELETE("/:id/members/:uid", petShareHandler.RemoveMember)
+					petGroup.POST("/:id/transfers", petShareHandler.RequestTransfer)
+					petGroup.GET("/:id/microchip-lookups", microchipHandler.ListLookups)
+					petGroup.GET("/:id/found-messages", microchipHandler.ListMessages)
+					petGroup.GET("/:id/profile", petProfileHandler.GetSettings)
+					petGroup.PUT("/:id/profile", petProfileHandler.UpdateSettings)
+					petGroup.POST("/:id/profile/slug", petProfileHandler.RegenerateSlug)
+					petGroup.GET("/:id/profile/qrcode", petProfileHandler.QRCode)
+					petGroup.POST("/:id/medications", medicationHandler.CreatePlan)
+					petGroup.GET("/:id/medications", medicationHandler.ListPlans)
+					petGroup.GET("/:id/medications/adherence", medicationHandler.Adherence)
+					petGroup.GET("/:id/medication-doses", medicationHandler.ListDoses)
+				}
+
+				// 用药管理路由
+				authGroup.GET("/medications/:id", medicationHandler.GetPlan)
+				authGroup.PUT("/medications/:id", medicationHandler.UpdatePlan)
+				authGroup.PUT("/medications/:id/status", medicationHandler.UpdatePlanStatus)
+				authGroup.PUT("/medication-doses/:id", medicationHandler.RecordDose)
+
+				// 宠物共享路由
+				authGroup.GET("/me/pet-invitations", petShareHandler.ListMyInvitations)
+				authGroup.PUT("/pet-invitations/:id/accept", petShareHandler.AcceptInvitation)
+				authGroup.PUT("/pet-invitations/:id/decline", petShareHandler.DeclineInvitation)
+				authGroup.DELETE("/pet-invitations/:id", petShareHandler.CancelInvitation)
+				authGroup.GET("/me/pet-transfers", petShareHandler.ListMyTransfers)
+				authGroup.PUT("/pet-transfers/:id/confirm", petShareHandler.ConfirmTransfer)
+				authGroup.PUT("/pet-transfers/:id/decline", petShareHandler.DeclineTransfer)
+
+				// 领养路由
+				authGroup.POST("/shelters", adoptionHandler.CreateShelter)
+				authGroup.PUT("/shelters/:id", adoptionHandler.UpdateShelter)
+				authGroup.POST("/shelters/:id/listings", adoptionHandler.CreateListing)
+				authGroup.GET("/shelters/:id/applications", adoptionHandler.ListShelterApplications)
+				authGroup.PUT("/adoptions/:id", adoptionHandler.UpdateListing)
+				authGroup.POST("/adoptions/:id/applications", adoptionHandler.SubmitApplication)
+				authGroup.GET("/adoption-applications/:id", adoptionHandler.GetApplication)
+				authGroup.PUT("/adoption-applications/:id/status", adoptionHandler.ReviewApplication)
+				authGroup.GET("/me/adoption-applications", adoptionHandler.ListMyApplications)
+
+				// 走失招领路由
+				authGroup.POST("/lost-found", lostFoundHandler.CreateReport)
+				authGroup.PUT("/lost-found/:id/resolve", lostFoundHandler.ResolveReport)
+				authGroup.GET("/me/lost-found", lostFoundHandler.ListMyReports)
+
+				// 寄养路由
+				authGroup.GET("/me/sitter-profile", sitterHandler.GetMyProfile)
+				authGroup.PUT("/me/sitter-profile", sitterHandler.UpsertProfile)
+				authGroup.PUT("/me/sitter-profile/calendar", sitterHandler.SetCalendar)
+				authGroup.POST("/sitters/:id/bookings", sitterHandler.CreateBooking)
+				authGroup.GET("/me/sitter-bookings", sitterHandler.ListMyBookings)
+				authGroup.GET("/sitter-bookings/:id", sitterHandler.GetBooking)
+				authGroup.PUT("/sitter-bookings/:id/accept", sitterHandler.AcceptBooking)
+				authGroup.PUT("/sitter-bookings/:id/decline", sitterHandler.DeclineBooking)
+				authGroup.PUT("/sitter-bookings/:id/cancel", sitterHandler.CancelBooking)
+				authGroup.PUT("/sitter-bookings/:id/complete", sitterHandler.CompleteBooking)
+
+				// 评价路由
+				authGroup.POST("/reviews", reviewHandler.CreateReview)
+				authGroup.GET("/me/reviews", reviewHandler.ListMyReviews)
+				authGroup.PUT("/reviews/:id/reply", reviewHandler.ReplyReview)
+				authGroup.POST("/reviews/:id/flags", reviewHandler.FlagReview)
+				authGroup.DELETE("/reviews/:id", reviewHandler.DeleteReview)
+
+				// 商城路由
+				authGroup.GET("/me/cart", shopHandler.GetCart)
+				authGroup.DELETE("/me/cart", shopHandler.ClearCart)
+				authGroup.PUT("/me/cart/items/:sku_id", shopHandler.SetCartItem)
+				authGroup.DELETE("/me/cart/items/:sku_id", shopHandler.RemoveCartItem)
+				authGroup.POST("/shop/checkout", shopHandler.Checkout)
+				authGroup.GET("/shop/reservations/:id", shopHandler.GetReservation)
+				authGroup.DELETE("/shop/reservations/:id", shopHandler.ReleaseReservation)
+
+				// 订单路由
+				authGroup.POST("/orders", orderHandler.CreateOrder)
+				authGroup.GET("/me/orders", orderHandler.ListMyOrders)
+				authGroup.GET("/orders/:id", orderHandler.GetOrder)
+				authGroup.POST("/orders/:id/pay", orderHandler.PayOrder)
+				if cfg.Payment.AllowSimulate {
+					authGroup.POST("/orders/:id/simulate-payment", orderHandler.SimulatePayment)
+				}
+				authGroup.PUT("/orders/:id/cancel", orderHandler.CancelOrder)
+				authGroup.PUT("/orders/:id/confirm", orderHandler.ConfirmOrder)
+				authGroup.POST("/orders/:id/refund", orderHandler.RequestRefund)
+
+				// 优惠券与积分路由
+				authGroup.POST("/coupons/check", couponHandler.CheckCoupon)
+				authGroup.GET("/me/points", couponHandler.GetMyPoints)
+
+				// 社区动态路由
+				authGroup.POST("/posts", postHandler.CreatePost)
+				authGroup.GET("/posts", postHandler.ListPosts)
+				authGroup.GET("/me/timeline", postHandler.Timeline)
+				authGroup.GET("/posts/:id", postHandler.GetPost)
+				authGroup.DELETE("/posts/:id", postHandler.DeletePost)
+				authGroup.PUT("/posts/:id/like", postHandler.LikePost)
+				authGroup.DELETE("/posts/:id/like", postHandler.UnlikePost)
+				authGroup.POST("/posts/:id/comments", postHandler.CreateComment)
+				authGroup.GET("/posts/:id/comments", postHandler.ListComments)
+				authGroup.GET("/comments/:id/replies", postHandler.ListReplies)
+				authGroup.DELETE("/comments/:id", postHandler.DeleteComment)
+
+				// 关注与拉黑路由
+				authGroup.PUT("/users/:id/follow", followHandler.FollowUser)
+				authGroup.DELETE("/users/:id/follow", followHandler.UnfollowUser)
+				authGroup.GET("/users/:id/relation", followHandler.GetRelation)
+				authGroup.GET("/users/:id/followers", followHandler.ListFollowers)
+				authGroup.GET("/users/:id/following", followHandler.ListFollowing)
+				authGroup.PUT("/users/:id/block", followHandler.BlockUser)
+				authGroup.DELETE("/users/:id/block", followHandler.UnblockUser)
+				authGroup.GET("/me/mutuals", followHandler.ListMutuals)
+				authGroup.GET("/me/blocks", followHandler.ListBlocks)
+				authGroup.GET("/me/following-pets", followHandler.ListFollowedPets)
+				authGroup.PUT("/pets/:id/follow", followHandler.FollowPet)
+				authGroup.DELETE("/pets/:id/follow", followHandler.UnfollowPet)
+				authGroup.GET("/pets/:id/follow", followHandler.GetPetFollowStats)
+				authGroup.GET("/pets/:id/followers", followHandler.ListPetFollowers)
+
+				// 诊所路由
+				authGroup.GET("/me/clinics", clinicHandler.ListMyClinics)
+				authGroup.PUT("/clinics/:id", clinicHandler.UpdateClinic)
+				authGroup.GET("/clinics/:id/staff", clinicHandler.ListStaff)
+				authGroup.PUT("/clinics/:id/staff", clinicHandler.SaveStaff)
+				authGroup.DELETE("/clinics/:id/staff/:uid", clinicHandler.RemoveStaff)
+
+				// 诊所咨询路由
+				authGroup.POST("/conversations", chatHandler.StartConversation)
+				authGroup.GET("/me/conversations", chatHandler.ListMyConversations)
+				authGroup.GET("/me/conversations/unread", chatHandler.GetUnread)
+				authGroup.GET("/clinics/:id/conversations", chatHandler.ListClinicConversations)
+				authGroup.GET("/conversations/:id", chatHandler.GetConversation)
+				authGroup.GET("/conversations/:id/messages", chatHandler.ListMessages)
+				authGroup.POST("/conversations/:id/messages", chatHandler.SendMessage)
+				authGroup.PUT("/conversations/:id/read", chatHandler.MarkRead)
+
+				// 社区活动路由
+				authGroup.POST("/events", eventHandler.CreateEvent)
+				authGroup.PUT("/events/:id", eventHandler.UpdateEvent)
+				authGroup.PUT("/events/:id/cancel", eventHandler.CancelEvent)
+				authGroup.GET("/events/:id/rsvp", eventHandler.GetMyRSVP)
+				authGroup.PUT("/events/:id/rsvp", eventHandler.RSVP)
+				authGroup.DELETE("/events/:id/rsvp", eventHandler.CancelRSVP)
+				authGroup.GET("/events/:id/rsvps", eventHandler.ListRSVPs)
+				authGroup.GET("/me/events", eventHandler.ListOrganizedEvents)
+				authGroup.GET("/me/event-rsvps", eventHandler.ListMyRSVPs)
+
+				// 就诊记录路由
+				authGroup.POST("/pets/:id/medical-records", medicalRecordHandler.CreateRecord)
+				authGroup.GET("/pets/:id/medical-records", medicalRecordHandler.ListRecords)
+				authGroup.GET("/medical-records/:id", medicalRecordHandler.GetRecord)
+				authGroup.PUT("/medical-records/:id", medicalRecordHandler.UpdateRecord)
+				authGroup.DELETE("/medical-records/:id", medicalRecordHandler.DeleteRecord)
+
+				// 宠物保险路由
+				authGroup.POST("/pets/:id/insurance-policies", insuranceHandler.CreatePolicy)
+				authGroup.GET("/pets/:id/insurance-policies", insuranceHandler.ListPolicies)
+				authGroup.GET("/insurance-policies/:id", insuranceHandler.GetPolicy)
+				authGroup.PUT("/insurance-policies/:id", insuranceHandler.UpdatePolicy)
+				authGroup.DELETE("/insurance-policies/:id", insuranceHandler.DeletePolicy)
+				authGroup.POST("/insurance-policies/:id/claims", insuranceHandler.CreateClaim)
+				authGroup.GET("/pets/:id/insurance-claims", insuranceHandler.ListClaims)
+				authGroup.GET("/insurance-claims/:id", insuranceHandler.GetClaim)
+				authGroup.PUT("/insurance-claims/:id", insuranceHandler.UpdateClaim)
+				authGroup.DELETE("/insurance-claims/:id", insuranceHandler.DeleteClaim)
+				authGroup.PUT("/insurance-claims/:id/status", insuranceHandler.UpdateClaimStatus)
+				authGroup.GET("/pets/:id/insurance/summary", insuranceHandler.GetSummary)
+
+				// PDF文档路由
+				authGroup.GET("/pets/:id/vaccination-certificate", documentHandler.VaccinationCertificate)
+				authGroup.GET("/orders/:id/invoice", documentHandler.OrderInvoice)
+				authGroup.GET("/appointments/:id/invoice", documentHandler.AppointmentInvoice)
+				authGroup.GET("/donations/:id/receipt", documentHandler.DonationReceipt)
+
+				// 运动记录路由
+				authGroup.POST("/pets/:id/activities", activityHandler.CreateActivity)
+				authGroup.POST("/pets/:id/activities/import", activityHandler.ImportActivity)
+				authGroup.GET("/pets/:id/activities", activityHandler.ListActivities)
+				authGroup.GET("/pets/:id/activities/stats", activityHandler.Stats)
+				authGroup.GET("/activities/:id", activityHandler.GetActivity)
+				authGroup.PUT("/activities/:id", activityHandler.UpdateActivity)
+				authGroup.DELETE("/activities/:id", activityHandler.DeleteActivity)
+				authGroup.GET("/activities/:id/track", activityHandler.GetTrack)
+				authGroup.GET("/pets/:id/activity-goals", activityHandler.ListGoals)
+				authGroup.PUT("/pets/:id/activity-goals", activityHandler.SaveGoal)
+				authGroup.DELETE("/activity-goals/:id", activityHandler.DeleteGoal)
+
+				// 饮食计划路由
+				authGroup.POST("/pets/:id/diet-plans", dietHandler.CreatePlan)
+				authGroup.GET("/pets/:id/diet-plans", dietHandler.ListPlans)
+				authGroup.GET("/diet-plans/:id", dietHandler.GetPlan)
+				authGroup.PUT("/diet-plans/:id", dietHandler.UpdatePlan)
+				authGroup.DELETE("/diet-plans/:id", dietHandler.DeletePlan)
+				authGroup.POST("/pets/:id/feeding-logs", dietHandler.CreateLog)
+				authGroup.GET("/pets/:id/feeding-logs", dietHandler.ListLogs)
+				authGroup.DELETE("/feeding-logs/:id", dietHandler.DeleteLog)
+				authGroup.GET("/pets/:id/diet/summary", dietHandler.DailySummary)
+
+				// 诊所服务与门诊预约路由
+				authGroup.POST("/clinics/:id/services", appointmentHandler.CreateService)
+				authGroup.GET("/clinics/:id/services/all", appointmentHandler.ListAllServices)
+				authGroup.PUT("/clinic-services/:id", appointmentHandler.UpdateService)
+				authGroup.DELETE("/clinic-services/:id", appointmentHandler.DeleteService)
+				authGroup.POST("/clinics/:id/appointments/quote", appointmentHandler.Quote)
+				authGroup.GET("/clinics/:id/availability", appointmentHandler.Availability)
+				authGroup.POST("/clinics/:id/appointments", appointmentHandler.Book)
+				authGroup.GET("/clinics/:id/appointments", appointmentHandler.ListClinicAppointments)
+				authGroup.GET("/me/appointments", appointmentHandler.ListMyAppointments)
+				authGroup.GET("/appointments/:id", appointmentHandler.GetAppointment)
+				authGroup.PUT("/appointments/:id/cancel", appointmentHandler.CancelAppointment)
+				authGroup.PUT("/appointments/:id/status", appointmentHandler.UpdateAppointmentStatus)
+
+				// 募捐路由
+				authGroup.POST("/shelters/:id/campaigns", donationHandler.CreateCampaign)
+				authGroup.GET("/shelters/:id/donations/export", donationHandler.ExportDonations)
+				authGroup.PUT("/donation-campaigns/:id", donationHandler.UpdateCampaign)
+				authGroup.PUT("/donation-campaigns/:id/close", donationHandler.CloseCampaign)
+				authGroup.POST("/donation-campaigns/:id/donations", donationHandler.Donate)
+				authGroup.GET("/me/donations", donationHandler.ListMyDonations)
+				authGroup.GET("/donations/:id", donationHandler.GetDonation)
+
+				// 处方与配药路由
+				authGroup.POST("/medical-records/:id/prescriptions", prescriptionHandler.CreatePrescription)
+				authGroup.GET("/pets/:id/prescriptions", prescriptionHandler.ListPetPrescriptions)
+				authGroup.GET("/clinics/:id/prescriptions", prescriptionHandler.ListClinicPrescriptions)
+				authGroup.GET("/prescriptions/:id", prescriptionHandler.GetPrescription)
+				authGroup.PUT("/prescriptions/:id", prescriptionHandler.UpdatePrescription)
+				authGroup.DELETE("/prescriptions/:id", prescriptionHandler.DeletePrescription)
+				authGroup.PUT("/prescriptions/:id/sign", prescriptionHandler.SignPrescription)
+				authGroup.PUT("/prescriptions/:id/revoke", prescriptionHandler.RevokePrescription)
+				authGroup.POST("/prescriptions/:id/refills", prescriptionHandler.RequestRefill)
+				authGroup.GET("/prescriptions/:id/refills", prescriptionHandler.ListRefills)
+				authGroup.PUT("/prescription-refills/:id/cancel", prescriptionHandler.CancelRefill)
+				authGroup.GET("/clinics/:id/prescription-refills", prescriptionHandler.ListClinicRefills)
+				authGroup.PUT("/prescription-refills/:id/status", prescriptionHandler.HandleClinicRefill)
+
+				// 消息通知路由
+				authGroup.GET("/me/notifications", notificationHandler.ListNotifications)
+				authGroup.PUT("/me/notifications/read-all", notificationHandler.MarkAllRead)
+				authGroup.PUT("/notifications/:id/read", notificationHandler.MarkRead)
+				authGroup.GET("/me/notification-preferences", notificationHandler.GetPreferences)
+				authGroup.PUT("/me/notification-preferences", notificationHandler.UpdatePreferences)
+
+				// 管理路由,按角色权限校验
+				adminGroup := authGroup.Group("/admin")
+				{
+					adminGroup.PUT("/users/:id/role", middleware.RequirePermission(rbac.PermUserManage), userHandler.UpdateUserRole)
+
+					shopAdmin := adminGroup.Group("/shop", middleware.RequirePermission(rbac.PermShopManage))
+					shopAdmin.GET("/categories", shopHandler.AdminListCategories)
+					shopAdmin.POST("/categories", shopHandler.CreateCategory)
+					shopAdmin.PUT("/categories/:id", shopHandler.UpdateCategory)
+					shopAdmin.GET("/products", shopHandler.AdminListProducts)
+					shopAdmin.POST("/products", shopHandler.CreateProduct)
+					shopAdmin.GET("/products/:id", shopHandler.AdminGetProduct)
+					shopAdmin.PUT("/products/:id", shopHandler.UpdateProduct)
+					shopAdmin.PUT("/products/:id/status", shopHandler.UpdateProductStatus)
+					shopAdmin.POST("/products/:id/skus", shopHandler.AddSKU)
+					shopAdmin.PUT("/skus/:id", shopHandler.UpdateSKU)
+					shopAdmin.POST("/skus/:id/stock", shopHandler.AdjustStock)
+
+					orderAdmin := adminGroup.Group("/orders", middleware.RequirePermission(rbac.PermOrderManage))
+					orderAdmin.GET("", orderHandler.AdminListOrders)
+					orderAdmin.GET("/:id", orderHandler.AdminGetOrder)
+					orderAdmin.PUT("/:id/fulfill", orderHandler.FulfillOrder)
+					orderAdmin.POST("/:id/refunds", orderHandler.RefundOrder)
+
+					couponAdmin := adminGroup.Group("/coupons", middleware.RequirePermission(rbac.PermCouponManage))
+					couponAdmin.GET("", couponHandler.ListCoupons)
+					couponAdmin.POST("", couponHandler.CreateCoupon)
+					couponAdmin.GET("/:id", couponHandler.GetCoupon)
+					couponAdmin.PUT("/:id", couponHandler.UpdateCoupon)
+
+					adminGroup.POST("/clinics", middleware.RequirePermission(rbac.PermClinicManage), clinicHandler.CreateClinic)
+
+					refillAdmin := adminGroup.Group("/prescription-refills", middleware.RequirePermission(rbac.PermPrescriptionFill))
+					refillAdmin.GET("", prescriptionHandler.AdminListRefills)
+					refillAdmin.PUT("/:id/status", prescriptionHandler.AdminHandleRefill)
+				}
+
+				// 文件路由
+				authGroup.POST("/files", fileHandler.Upload)
+				authGroup.GET("/files/:id", fileHandler.GetFile)
+				authGroup.DELETE("/files/:id", fileHandler.DeleteFile)
 			}
 		}
 	}
@@ -168,6 +693,9 @@ func gracefulShutdown(h *server.Hertz) {
 func cleanup() {
 	logger.Info(context.Background(), "开始清理资源...")
 
+	// 停止后台任务
+	stopWorkers()
+
 	// 关闭Redis连接
 	if err := redis.Close(); err != nil {
 		logger.Error(context.Background(), "关闭Redis连接失败", logger.ErrorField(err))
@@ -0,0 +1,42 @@
+package geo
+
+import (
+	"math"
+)
+
+// earthRadiusKm 地球平均半径(km)
+const earthRadiusKm = 6371.0
+
+// maxIndexLatitude Redis GEO可索引的最大纬度(Web墨卡托投影范围),超出时GEOADD会报错
+const maxIndexLatitude = 85.05112878
+
+// Distance 使用Haversine公式计算两点间的球面距离(km)
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BoundingBox 计算以某点为中心、半径为radiusKm的外接矩形,用于数据库粗筛
+func BoundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	dLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	dLng := dLat / math.Max(math.Cos(toRadians(lat)), 1e-6)
+	return lat - dLat, lat + dLat, lng - dLng, lng + dLng
+}
+
+// ValidCoordinate 校验经纬度是否合法
+func ValidCoordinate(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+// ValidIndexCoordinate 校验经纬度能否写入Redis GEO索引
+func ValidIndexCoordinate(lat, lng float64) bool {
+	return lat >= -maxIndexLatitude && lat <= maxIndexLatitude && lng >= -180 && lng <= 180
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -0,0 +1,119 @@
+// Package ical 生成iCalendar(RFC 5545)日历文件,仅包含单个VEVENT所需的字段
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// utcLayout iCalendar的UTC时间格式
+const utcLayout = "20060102T150405Z"
+
+// maxLineOctets 内容行折叠前的最大字节数
+const maxLineOctets = 75
+
+// Event 日历事件
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Created     time.Time
+	Cancelled   bool
+	// Latitude/Longitude 都为0时不输出GEO
+	Latitude  float64
+	Longitude float64
+	// AlarmBefore 大于0时添加开始前的提醒
+	AlarmBefore time.Duration
+}
+
+// Calendar 生成只包含一个事件的日历文件内容
+func Calendar(prodID string, event *Event) []byte {
+	var buf bytes.Buffer
+	w := &writer{buf: &buf}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + escape(prodID))
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + escape(event.UID))
+	w.line("DTSTAMP:" + time.Now().UTC().Format(utcLayout))
+	if !event.Created.IsZero() {
+		w.line("CREATED:" + event.Created.UTC().Format(utcLayout))
+	}
+	w.line("DTSTART:" + event.Start.UTC().Format(utcLayout))
+	w.line("DTEND:" + event.End.UTC().Format(utcLayout))
+	w.line("SUMMARY:" + escape(event.Summary))
+	if event.Description != "" {
+		w.line("DESCRIPTION:" + escape(event.Description))
+	}
+	if event.Location != "" {
+		w.line("LOCATION:" + escape(event.Location))
+	}
+	if event.Latitude != 0 || event.Longitude != 0 {
+		w.line("GEO:" + formatFloat(event.Latitude) + ";" + formatFloat(event.Longitude))
+	}
+	if event.URL != "" {
+		w.line("URL:" + event.URL)
+	}
+	if event.Cancelled {
+		w.line("STATUS:CANCELLED")
+	} else {
+		w.line("STATUS:CONFIRMED")
+	}
+	if event.AlarmBefore > 0 {
+		w.line("BEGIN:VALARM")
+		w.line("ACTION:DISPLAY")
+		w.line("DESCRIPTION:" + escape(event.Summary))
+		w.line("TRIGGER:-PT" + strconv.Itoa(int(event.AlarmBefore/time.Minute)) + "M")
+		w.line("END:VALARM")
+	}
+	w.line("END:VEVENT")
+	w.line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writer 按CRLF结尾写入内容行,超长的行按规范折叠
+type writer struct {
+	buf *bytes.Buffer
+}
+
+func (w *writer) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		// 不在UTF-8多字节字符中间折叠
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.buf.WriteString(s[:cut])
+		w.buf.WriteString("\r\n ")
+		s = s[cut:]
+		// 续行以空格开头,空格计入行长
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(s)
+	w.buf.WriteString("\r\n")
+}
+
+// escape 转义TEXT类型值中的特殊字符
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
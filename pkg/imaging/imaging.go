@@ -0,0 +1,73 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册WebP解码器
+)
+
+// Thumbnail 缩略图
+type Thumbnail struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// DecodeConfig 读取图片尺寸
+func DecodeConfig(data []byte) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// MakeThumbnail 按比例缩放到长边不超过maxSide,PNG保留透明通道,其他格式输出JPEG
+func MakeThumbnail(data []byte, maxSide int) (*Thumbnail, error) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			h = h * maxSide / w
+			w = maxSide
+		} else {
+			w = w * maxSide / h
+			h = maxSide
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	thumb := &Thumbnail{Width: w, Height: h}
+	if format == "png" {
+		err = png.Encode(&buf, dst)
+		thumb.ContentType = "image/png"
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		thumb.ContentType = "image/jpeg"
+	}
+	if err != nil {
+		return nil, err
+	}
+	thumb.Data = buf.Bytes()
+	return thumb, nil
+}
@@ -11,6 +11,7 @@ import (
 type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -29,13 +30,14 @@ func NewJWTManager(secretKey string, tokenDuration time.Duration) *JWTManager {
 }
 
 // GenerateToken 生成JWT token
-func (m *JWTManager) GenerateToken(userID uint, username string) (string, int64, error) {
+func (m *JWTManager) GenerateToken(userID uint, username, role string) (string, int64, error) {
 	now := time.Now()
 	expiresAt := now.Add(m.tokenDuration)
 
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -88,5 +90,5 @@ func (m *JWTManager) RefreshToken(tokenString string) (string, int64, error) {
 		return "", 0, errors.New("token还未到刷新时间")
 	}
 
-	return m.GenerateToken(claims.UserID, claims.Username)
+	return m.GenerateToken(claims.UserID, claims.Username, claims.Role)
 }
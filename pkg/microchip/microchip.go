@@ -0,0 +1,40 @@
+package microchip
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidLength 芯片号不是15位数字
+	ErrInvalidLength = errors.New("芯片号必须为15位数字")
+	// ErrInvalidCode 国家/厂商代码不合法
+	ErrInvalidCode = errors.New("芯片号国家或厂商代码不合法")
+)
+
+// Normalize 去除扫描器输出中常见的空格和分隔符
+func Normalize(code string) string {
+	return strings.NewReplacer(" ", "", "-", "", ".", "").Replace(strings.TrimSpace(code))
+}
+
+// Validate 按ISO 11784/11785 FDX-B十进制表示校验芯片号:
+// 共15位数字,前3位为ISO 3166数字国家代码(001-899)或ICAR厂商代码(900-998),999为测试芯片
+func Validate(code string) error {
+	if len(code) != 15 {
+		return ErrInvalidLength
+	}
+	prefix := 0
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c < '0' || c > '9' {
+			return ErrInvalidLength
+		}
+		if i < 3 {
+			prefix = prefix*10 + int(c-'0')
+		}
+	}
+	if prefix == 0 || prefix == 999 {
+		return ErrInvalidCode
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
 	"pet-service/pkg/jwt"
 	"pet-service/pkg/logger"
+	"pet-service/pkg/rbac"
 )
 
 var jwtManager *jwt.JWTManager
@@ -67,11 +68,26 @@ func JWTAuthMiddleware() app.HandlerFunc {
 		// 将用户信息存入上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
 
 		c.Next(ctx)
 	}
 }
 
+// QueryTokenMiddleware 浏览器发起WebSocket握手时无法设置请求头,
+// 未携带Authorization时将token查询参数转为Bearer请求头,需在JWTAuthMiddleware之前使用
+// 请求日志会对token查询参数脱敏,见redactQuery
+func QueryTokenMiddleware() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if len(c.GetHeader("Authorization")) == 0 {
+			if token := c.Query("token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next(ctx)
+	}
+}
+
 // GetUserID 从上下文获取用户ID
 func GetUserID(c *app.RequestContext) uint {
 	userID, exists := c.Get("user_id")
@@ -89,3 +105,33 @@ func GetUsername(c *app.RequestContext) string {
 	}
 	return username.(string)
 }
+
+// GetRole 从上下文获取用户角色
+func GetRole(c *app.RequestContext) string {
+	role, exists := c.Get("role")
+	if !exists {
+		return ""
+	}
+	return role.(string)
+}
+
+// RequirePermission 权限校验中间件,需在JWTAuthMiddleware之后使用
+// 角色写入token,变更角色后需重新登录生效
+func RequirePermission(permission string) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if !rbac.Can(GetRole(c), permission) {
+			logger.Warn(ctx, "权限不足",
+				logger.Int("user_id", int(GetUserID(c))),
+				logger.String("role", GetRole(c)),
+				logger.String("permission", permission),
+			)
+			c.JSON(consts.StatusForbidden, map[string]interface{}{
+				"code":    403,
+				"message": "无权限操作",
+			})
+			c.Abort()
+			return
+		}
+		c.Next(ctx)
+	}
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/cloudwego/hertz/pkg/app"
@@ -66,7 +68,7 @@ func RequestLogMiddleware() app.HandlerFunc {
 		logger.Info(ctx, "收到请求",
 			logger.String("method", string(c.Request.Method())),
 			logger.String("path", string(c.Request.Path())),
-			logger.String("query", string(c.Request.QueryString())),
+			logger.String("query", redactQuery(string(c.Request.QueryString()))),
 			logger.String("client_ip", c.ClientIP()),
 		)
 
@@ -80,6 +82,31 @@ func RequestLogMiddleware() app.HandlerFunc {
 	}
 }
 
+// sensitiveQueryKeys 写入日志前需要脱敏的查询参数
+var sensitiveQueryKeys = map[string]bool{
+	"token":        true,
+	"access_token": true,
+}
+
+// redactQuery 将查询字符串中的敏感参数值替换为***,保持其余参数原样
+// WebSocket和SSE握手通过token查询参数携带JWT,不能原样写入日志
+func redactQuery(query string) string {
+	if query == "" {
+		return query
+	}
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			continue
+		}
+		if name, err := url.QueryUnescape(key); err == nil && sensitiveQueryKeys[strings.ToLower(name)] {
+			pairs[i] = key + "=***"
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
 // RecoveryMiddleware 错误恢复中间件
 func RecoveryMiddleware() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
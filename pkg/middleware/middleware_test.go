@@ -0,0 +1,27 @@
+package middleware
+
+import "testing"
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"空查询", "", ""},
+		{"无敏感参数", "page=1&page_size=20", "page=1&page_size=20"},
+		{"token参数", "token=eyJhbGciOi.abc.def", "token=***"},
+		{"token在中间", "room=1&token=abc&since=2", "room=1&token=***&since=2"},
+		{"大小写与编码", "TOKEN=abc&%74oken=def", "TOKEN=***&%74oken=***"},
+		{"access_token", "access_token=abc", "access_token=***"},
+		{"无值参数保持原样", "token&debug", "token&debug"},
+		{"前缀相同的参数不脱敏", "token_type=bearer", "token_type=bearer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactQuery(tt.query); got != tt.want {
+				t.Errorf("redactQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
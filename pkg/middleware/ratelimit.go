@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"pet-service/pkg/logger"
+	"pet-service/pkg/redis"
+)
+
+// RateLimitMiddleware 按客户端IP的固定窗口限流,name区分不同接口的计数
+// Redis不可用时放行,避免限流组件故障导致接口不可用
+func RateLimitMiddleware(name string, limit int, window time.Duration) app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		now := time.Now()
+		slot := now.UnixNano() / int64(window)
+		key := fmt.Sprintf("ratelimit:%s:%s:%d", name, c.ClientIP(), slot)
+
+		count, err := redis.IncrWithExpire(ctx, key, window)
+		if err != nil {
+			logger.Warn(ctx, "限流计数失败,放行请求", logger.String("name", name), logger.ErrorField(err))
+			c.Next(ctx)
+			return
+		}
+
+		if count > int64(limit) {
+			retryAfter := time.Duration((slot+1)*int64(window) - now.UnixNano())
+			logger.Warn(ctx, "请求触发限流", logger.String("name", name), logger.String("client_ip", c.ClientIP()))
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+			c.JSON(consts.StatusTooManyRequests, map[string]interface{}{
+				"code":    429,
+				"message": "请求过于频繁,请稍后再试",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next(ctx)
+	}
+}
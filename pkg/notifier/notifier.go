@@ -0,0 +1,40 @@
+package notifier
+
+import (
+	"context"
+
+	"pet-service/pkg/logger"
+)
+
+// Message 通知消息
+type Message struct {
+	UserID  uint
+	Type    string
+	Title   string
+	Content string
+	Data    map[string]interface{}
+}
+
+// Notifier 通知发送接口,业务模块通过该接口发送提醒,不关心具体投递渠道
+type Notifier interface {
+	Notify(ctx context.Context, msg *Message) error
+}
+
+// LogNotifier 仅记录日志的通知实现,未接入推送渠道时使用
+type LogNotifier struct{}
+
+// NewLogNotifier 创建日志通知
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify 记录通知日志
+func (n *LogNotifier) Notify(ctx context.Context, msg *Message) error {
+	logger.Info(ctx, "发送通知",
+		logger.Int("user_id", int(msg.UserID)),
+		logger.String("type", msg.Type),
+		logger.String("title", msg.Title),
+		logger.String("content", msg.Content),
+	)
+	return nil
+}
@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// FakeGateway 本地模拟支付网关,用于开发和测试
+// 发起支付不会真正扣款,通过BuildCallback生成签名回调模拟用户付款
+type FakeGateway struct {
+	secret []byte
+}
+
+// NewFakeGateway 创建模拟支付网关
+func NewFakeGateway(secret string) *FakeGateway {
+	return &FakeGateway{secret: []byte(secret)}
+}
+
+// Name 网关名称
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+// Prepay 创建模拟交易,交易号由订单号派生
+func (g *FakeGateway) Prepay(ctx context.Context, req *PrepayRequest) (*Prepay, error) {
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("支付金额无效: %d", req.Amount)
+	}
+	return &Prepay{
+		TradeNo: "FAKE" + req.OrderNo,
+		PayURL:  "fake://pay/" + req.OrderNo,
+	}, nil
+}
+
+// Refund 模拟退款,同步返回成功
+func (g *FakeGateway) Refund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	if req.Amount <= 0 || req.Amount > req.TotalAmount {
+		return nil, fmt.Errorf("退款金额无效: %d", req.Amount)
+	}
+	return &RefundResult{
+		GatewayRefundNo: "FAKER" + req.RefundNo,
+		Status:          RefundSucceeded,
+	}, nil
+}
+
+// ParseCallback 校验HMAC-SHA256签名并解析回调
+func (g *FakeGateway) ParseCallback(body []byte, signature string) (*CallbackEvent, error) {
+	if !hmac.Equal([]byte(g.sign(body)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+	var event CallbackEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("解析支付回调失败: %w", err)
+	}
+	return &event, nil
+}
+
+// BuildCallback 生成订单支付成功的签名回调,模拟网关通知
+func (g *FakeGateway) BuildCallback(orderNo, tradeNo string, amount int64) ([]byte, string, error) {
+	event := &CallbackEvent{
+		EventID: fmt.Sprintf("evt_%s_paid", orderNo),
+		Type:    EventPaymentSucceeded,
+		OrderNo: orderNo,
+		TradeNo: tradeNo,
+		Amount:  amount,
+		PaidAt:  time.Now(),
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, g.sign(body), nil
+}
+
+// sign 计算回调内容签名
+func (g *FakeGateway) sign(body []byte) string {
+	h := hmac.New(sha256.New, g.secret)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
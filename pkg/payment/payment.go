@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"pet-service/config"
+)
+
+// ErrInvalidSignature 回调签名校验失败
+var ErrInvalidSignature = errors.New("支付回调签名无效")
+
+// 回调事件类型
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventRefundSucceeded  = "refund.succeeded"
+)
+
+// 退款结果状态
+const (
+	RefundSucceeded = "succeeded"
+	RefundPending   = "pending" // 网关异步处理,结果通过回调通知
+)
+
+// PrepayRequest 发起支付请求,金额单位为分
+type PrepayRequest struct {
+	OrderNo   string
+	Amount    int64
+	Subject   string
+	NotifyURL string
+	ExpireAt  time.Time
+}
+
+// Prepay 支付参数,客户端据此拉起支付
+type Prepay struct {
+	TradeNo string `json:"trade_no"`
+	PayURL  string `json:"pay_url"`
+}
+
+// RefundRequest 退款请求
+type RefundRequest struct {
+	OrderNo     string
+	TradeNo     string
+	RefundNo    string
+	Amount      int64
+	TotalAmount int64
+	Reason      string
+}
+
+// RefundResult 退款结果
+type RefundResult struct {
+	GatewayRefundNo string
+	Status          string
+}
+
+// CallbackEvent 网关回调事件,EventID在同一网关内唯一,用于幂等处理
+type CallbackEvent struct {
+	EventID  string    `json:"event_id"`
+	Type     string    `json:"type"`
+	OrderNo  string    `json:"order_no"`
+	TradeNo  string    `json:"trade_no"`
+	RefundNo string    `json:"refund_no,omitempty"`
+	Amount   int64     `json:"amount"`
+	PaidAt   time.Time `json:"paid_at"`
+}
+
+// PaymentGateway 支付网关接口,接入新的支付渠道时实现该接口
+type PaymentGateway interface {
+	// Name 网关名称,用于回调路由和订单记录
+	Name() string
+	// Prepay 创建支付交易
+	Prepay(ctx context.Context, req *PrepayRequest) (*Prepay, error)
+	// Refund 发起退款
+	Refund(ctx context.Context, req *RefundRequest) (*RefundResult, error)
+	// ParseCallback 校验回调签名并解析事件,签名无效时返回ErrInvalidSignature
+	ParseCallback(body []byte, signature string) (*CallbackEvent, error)
+}
+
+// New 根据配置创建支付网关
+func New(cfg *config.PaymentConfig) (PaymentGateway, error) {
+	switch cfg.Driver {
+	case "", "fake":
+		return NewFakeGateway(cfg.FakeSecret), nil
+	default:
+		return nil, fmt.Errorf("不支持的支付网关: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,324 @@
+// Package pdf 按统一模板渲染证明、发票等PDF文档,文档底部附带验证码与验证二维码
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jung-kurt/gofpdf"
+	"pet-service/pkg/qrcode"
+)
+
+const (
+	fontFamily  = "doc"
+	coreFont    = "Helvetica"
+	pageMargin  = 15.0
+	lineHeight  = 7.0
+	labelWidth  = 42.0
+	qrSize      = 32.0
+	verifyBlock = qrSize + 8
+)
+
+// Field 键值字段,如"宠物名称: 豆豆"
+type Field struct {
+	Label string
+	Value string
+}
+
+// Column 表格列,Width单位为毫米,Align取值L/C/R
+type Column struct {
+	Title string
+	Width float64
+	Align string
+}
+
+// Table 明细表格
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	// Footer 表格下方右对齐的汇总行,如合计、优惠
+	Footer []Field
+}
+
+// Section 文档段落,依次渲染标题、字段和表格
+type Section struct {
+	Heading string
+	Fields  []Field
+	Table   *Table
+}
+
+// Document 文档模板
+type Document struct {
+	Title    string
+	Subtitle string
+	Sections []Section
+	Notes    []string
+	// VerifyCode 与VerifyURL用于生成底部验证区域,为空时不渲染
+	VerifyCode  string
+	VerifyURL   string
+	VerifyLabel string
+	IssuedAt    time.Time
+}
+
+// Renderer PDF渲染器
+// 配置了包含中文字形的TTF字体时使用UTF-8字体;未配置时退回内置Helvetica,非ASCII字符显示为"?"
+type Renderer struct {
+	font []byte
+}
+
+// NewRenderer 创建渲染器,fontPath为空时使用内置字体
+func NewRenderer(fontPath string) (*Renderer, error) {
+	if fontPath == "" {
+		return &Renderer{}, nil
+	}
+	font, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取PDF字体失败: %w", err)
+	}
+	// 预先加载一次以尽早发现不支持的字体文件
+	probe := gofpdf.New("P", "mm", "A4", "")
+	probe.AddUTF8FontFromBytes(fontFamily, "", font)
+	probe.AddPage()
+	probe.SetFont(fontFamily, "", 10)
+	probe.CellFormat(0, lineHeight, "probe", "", 1, "L", false, 0, "")
+	if err := probe.Output(io.Discard); err != nil {
+		return nil, fmt.Errorf("加载PDF字体失败: %w", err)
+	}
+	return &Renderer{font: font}, nil
+}
+
+// UTF8 是否支持中文等非ASCII字符
+func (r *Renderer) UTF8() bool {
+	return r.font != nil
+}
+
+// Label 按字体能力选择中文或英文文案
+func (r *Renderer) Label(zh, en string) string {
+	if r.UTF8() {
+		return zh
+	}
+	return en
+}
+
+// Render 渲染文档
+func (r *Renderer) Render(doc *Document) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	family := coreFont
+	if r.UTF8() {
+		family = fontFamily
+		pdf.AddUTF8FontFromBytes(fontFamily, "", r.font)
+		// 字体文件通常不含粗体,粗体样式复用同一字形
+		pdf.AddUTF8FontFromBytes(fontFamily, "B", r.font)
+	}
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetCreator("pet-service", true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin + 3)
+		pdf.SetFont(family, "", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 5, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	w := &writer{pdf: pdf, family: family, utf8: r.UTF8()}
+	w.header(doc)
+	for i := range doc.Sections {
+		w.section(&doc.Sections[i])
+	}
+	w.notes(doc.Notes)
+	if doc.VerifyCode != "" {
+		if err := w.verification(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writer 单次渲染的状态
+type writer struct {
+	pdf    *gofpdf.Fpdf
+	family string
+	utf8   bool
+}
+
+// text 内置字体只支持ASCII,其余字符替换为"?"
+func (w *writer) text(s string) string {
+	if w.utf8 {
+		return s
+	}
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		} else {
+			out = append(out, '?')
+		}
+	}
+	return string(out)
+}
+
+// fit 截断文本使其不超过指定宽度
+func (w *writer) fit(s string, width float64) string {
+	s = w.text(s)
+	if w.pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && w.pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func (w *writer) contentWidth() float64 {
+	pageWidth, _ := w.pdf.GetPageSize()
+	return pageWidth - 2*pageMargin
+}
+
+func (w *writer) header(doc *Document) {
+	pdf := w.pdf
+	pdf.SetTextColor(0, 0, 0)
+	pdf.SetFont(w.family, "B", 18)
+	pdf.CellFormat(0, 12, w.text(doc.Title), "", 1, "C", false, 0, "")
+	if doc.Subtitle != "" {
+		pdf.SetFont(w.family, "", 10)
+		pdf.SetTextColor(96, 96, 96)
+		pdf.CellFormat(0, 6, w.text(doc.Subtitle), "", 1, "C", false, 0, "")
+	}
+	pdf.SetDrawColor(200, 200, 200)
+	y := pdf.GetY() + 2
+	pdf.Line(pageMargin, y, pageMargin+w.contentWidth(), y)
+	pdf.Ln(6)
+}
+
+func (w *writer) section(s *Section) {
+	pdf := w.pdf
+	if s.Heading != "" {
+		pdf.SetFont(w.family, "B", 12)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(0, 8, w.text(s.Heading), "", 1, "L", false, 0, "")
+	}
+	for _, field := range s.Fields {
+		pdf.SetFont(w.family, "", 10)
+		pdf.SetTextColor(96, 96, 96)
+		pdf.CellFormat(labelWidth, lineHeight, w.text(field.Label), "", 0, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.MultiCell(0, lineHeight, w.text(field.Value), "", "L", false)
+	}
+	if s.Table != nil {
+		w.table(s.Table)
+	}
+	pdf.Ln(4)
+}
+
+func (w *writer) table(t *Table) {
+	pdf := w.pdf
+	pdf.SetFont(w.family, "B", 9)
+	pdf.SetFillColor(240, 240, 240)
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.SetTextColor(0, 0, 0)
+	for _, col := range t.Columns {
+		pdf.CellFormat(col.Width, lineHeight, w.fit(col.Title, col.Width-2), "1", 0, alignOf(col.Align), true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont(w.family, "", 9)
+	for _, row := range t.Rows {
+		for i, col := range t.Columns {
+			value := ""
+			if i < len(row) {
+				value = row[i]
+			}
+			pdf.CellFormat(col.Width, lineHeight, w.fit(value, col.Width-2), "1", 0, alignOf(col.Align), false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	if len(t.Footer) > 0 {
+		var total float64
+		for _, col := range t.Columns {
+			total += col.Width
+		}
+		pdf.Ln(1)
+		for _, field := range t.Footer {
+			pdf.CellFormat(total-40, lineHeight, w.text(field.Label), "", 0, "R", false, 0, "")
+			pdf.CellFormat(40, lineHeight, w.text(field.Value), "", 1, "R", false, 0, "")
+		}
+	}
+}
+
+func (w *writer) notes(notes []string) {
+	if len(notes) == 0 {
+		return
+	}
+	pdf := w.pdf
+	pdf.SetFont(w.family, "", 8)
+	pdf.SetTextColor(96, 96, 96)
+	for _, note := range notes {
+		pdf.MultiCell(0, 5, w.text(note), "", "L", false)
+	}
+	pdf.Ln(2)
+}
+
+// verification 在文档末尾渲染验证码和二维码,剩余空间不足时另起一页
+func (w *writer) verification(doc *Document) error {
+	pdf := w.pdf
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+verifyBlock > pageHeight-pageMargin {
+		pdf.AddPage()
+	}
+
+	png, err := qrcode.PNG(doc.VerifyURL, 256)
+	if err != nil {
+		return fmt.Errorf("生成验证二维码失败: %w", err)
+	}
+	opts := gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("verify-qr", opts, bytes.NewReader(png))
+
+	top := pdf.GetY() + 2
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.Line(pageMargin, top, pageMargin+w.contentWidth(), top)
+	qrX := pageMargin + w.contentWidth() - qrSize
+	pdf.ImageOptions("verify-qr", qrX, top+4, qrSize, qrSize, false, opts, 0, doc.VerifyURL)
+
+	textWidth := w.contentWidth() - qrSize - 4
+	pdf.SetXY(pageMargin, top+6)
+	pdf.SetFont(w.family, "B", 14)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.CellFormat(textWidth, 8, doc.VerifyCode, "", 2, "L", false, 0, "")
+	pdf.SetFont(w.family, "", 9)
+	pdf.SetTextColor(96, 96, 96)
+	if doc.VerifyLabel != "" {
+		pdf.MultiCell(textWidth, 5, w.text(doc.VerifyLabel), "", "L", false)
+	}
+	pdf.SetX(pageMargin)
+	pdf.CellFormat(textWidth, 5, w.fit(doc.VerifyURL, textWidth), "", 2, "L", false, 0, doc.VerifyURL)
+	if !doc.IssuedAt.IsZero() {
+		pdf.SetX(pageMargin)
+		pdf.CellFormat(textWidth, 5, doc.IssuedAt.Format("2006-01-02 15:04:05 MST"), "", 2, "L", false, 0, "")
+	}
+	pdf.SetY(top + verifyBlock)
+	return nil
+}
+
+func alignOf(align string) string {
+	if align == "" {
+		return "L"
+	}
+	return align
+}
@@ -0,0 +1,96 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// quietZone 二维码四周保留的空白模块数
+const quietZone = 4
+
+// 二维码尺寸(像素)
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+// checkSize 校验尺寸范围,避免超大尺寸占用过多内存
+func checkSize(size int) error {
+	if size < MinSize || size > MaxSize {
+		return fmt.Errorf("二维码尺寸须在%d-%d之间", MinSize, MaxSize)
+	}
+	return nil
+}
+
+// PNG 生成边长约为size像素的二维码PNG
+func PNG(content string, size int) ([]byte, error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+	// 按整数倍缩放,避免模块边缘模糊导致扫码失败
+	modules := code.Bounds().Dx() + 2*quietZone
+	scale := size / modules
+	if scale < 1 {
+		scale = 1
+	}
+	scaled, err := barcode.Scale(code, code.Bounds().Dx()*scale, code.Bounds().Dy()*scale)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, withQuietZone(scaled, quietZone*scale)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG 生成二维码SVG,size为显示尺寸,矢量图可任意缩放打印
+func SVG(content string, size int) ([]byte, error) {
+	if err := checkSize(size); err != nil {
+		return nil, err
+	}
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+	bounds := code.Bounds()
+	modules := bounds.Dx() + 2*quietZone
+
+	var path strings.Builder
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if r, _, _, _ := code.At(x, y).RGBA(); r == 0 {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x-bounds.Min.X+quietZone, y-bounds.Min.Y+quietZone)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="%s"/></svg>`, path.String())
+	return buf.Bytes(), nil
+}
+
+// withQuietZone 在图片四周添加白色边距
+func withQuietZone(src image.Image, margin int) image.Image {
+	b := src.Bounds()
+	dst := image.NewGray(image.Rect(0, 0, b.Dx()+2*margin, b.Dy()+2*margin))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(margin, margin, margin+b.Dx(), margin+b.Dy()), src, b.Min, draw.Src)
+	return dst
+}
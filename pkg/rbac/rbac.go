@@ -0,0 +1,46 @@
+package rbac
+
+// 角色
+const (
+	RoleUser        = "user"
+	RoleAdmin       = "admin"
+	RoleShopManager = "shop_manager"
+)
+
+// 权限
+const (
+	PermUserManage       = "user:manage"       // 管理用户角色
+	PermShopManage       = "shop:manage"       // 管理商品、分类和库存
+	PermOrderManage      = "order:manage"      // 查看全部订单、发货和退款
+	PermCouponManage     = "coupon:manage"     // 管理优惠券
+	PermClinicManage     = "clinic:manage"     // 入驻诊所
+	PermPrescriptionFill = "prescription:fill" // 商城药房处理处方配药申请
+)
+
+// rolePermissions 角色拥有的权限,admin拥有全部权限
+var rolePermissions = map[string]map[string]bool{
+	RoleUser: {},
+	RoleShopManager: {
+		PermShopManage:       true,
+		PermOrderManage:      true,
+		PermCouponManage:     true,
+		PermPrescriptionFill: true,
+	},
+}
+
+// ValidRole 判断角色是否存在
+func ValidRole(role string) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Can 判断角色是否拥有权限
+func Can(role, permission string) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	return rolePermissions[role][permission]
+}
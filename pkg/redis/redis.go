@@ -136,6 +136,156 @@ func HDel(ctx context.Context, key string, fields ...string) error {
 	return nil
 }
 
+// IncrWithExpire 计数器自增并刷新过期时间,用于按窗口分key的计数
+func IncrWithExpire(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	pipe := client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error(ctx, "Redis计数失败", logger.String("key", key), logger.ErrorField(err))
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// hincrByIfExistsScript 仅在哈希存在时自增字段,避免对未加载的计数产生不完整的值
+var hincrByIfExistsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HINCRBY", KEYS[1], ARGV[1], ARGV[2])
+end
+return nil
+`)
+
+// HIncrByIfExists 哈希存在时字段自增,返回自增是否生效
+func HIncrByIfExists(ctx context.Context, key, field string, incr int64) (bool, error) {
+	err := hincrByIfExistsScript.Run(ctx, client, []string{key}, field, incr).Err()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		logger.Error(ctx, "Redis HIncrBy失败", logger.String("key", key), logger.ErrorField(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// HSetAllWithExpire 批量设置哈希字段并设置过期时间
+func HSetAllWithExpire(ctx context.Context, key string, values map[string]interface{}, expiration time.Duration) error {
+	pipe := client.TxPipeline()
+	pipe.HSet(ctx, key, values)
+	pipe.Expire(ctx, key, expiration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error(ctx, "Redis批量设置哈希失败", logger.String("key", key), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// SAdd 添加集合成员
+func SAdd(ctx context.Context, key string, members ...interface{}) error {
+	err := client.SAdd(ctx, key, members...).Err()
+	if err != nil {
+		logger.Error(ctx, "Redis SAdd失败", logger.String("key", key), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// SPopN 随机弹出最多count个集合成员
+func SPopN(ctx context.Context, key string, count int64) ([]string, error) {
+	val, err := client.SPopN(ctx, key, count).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		logger.Error(ctx, "Redis SPop失败", logger.String("key", key), logger.ErrorField(err))
+		return nil, err
+	}
+	return val, nil
+}
+
+// Publish 发布频道消息
+func Publish(ctx context.Context, channel string, message interface{}) error {
+	err := client.Publish(ctx, channel, message).Err()
+	if err != nil {
+		logger.Error(ctx, "Redis发布消息失败", logger.String("channel", channel), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// Subscribe 订阅频道,使用方负责关闭返回的订阅
+func Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return client.Subscribe(ctx, channels...)
+}
+
+// GeoLocation 地理位置查询结果
+type GeoLocation struct {
+	Member    string
+	Longitude float64
+	Latitude  float64
+	Dist      float64 // 距查询中心的距离(km)
+}
+
+// GeoAdd 添加地理位置
+func GeoAdd(ctx context.Context, key, member string, longitude, latitude float64) error {
+	err := client.GeoAdd(ctx, key, &redis.GeoLocation{
+		Name:      member,
+		Longitude: longitude,
+		Latitude:  latitude,
+	}).Err()
+	if err != nil {
+		logger.Error(ctx, "Redis GeoAdd失败", logger.String("key", key), logger.String("member", member), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GeoRemove 删除地理位置
+func GeoRemove(ctx context.Context, key string, members ...string) error {
+	args := make([]interface{}, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	err := client.ZRem(ctx, key, args...).Err()
+	if err != nil {
+		logger.Error(ctx, "Redis GeoRemove失败", logger.String("key", key), logger.ErrorField(err))
+		return err
+	}
+	return nil
+}
+
+// GeoSearchRadius 按距离由近到远查询半径(km)内的地理位置,count为0时不限制数量
+func GeoSearchRadius(ctx context.Context, key string, longitude, latitude, radiusKm float64, count int) ([]GeoLocation, error) {
+	locations, err := client.GeoSearchLocation(ctx, key, &redis.GeoSearchLocationQuery{
+		GeoSearchQuery: redis.GeoSearchQuery{
+			Longitude:  longitude,
+			Latitude:   latitude,
+			Radius:     radiusKm,
+			RadiusUnit: "km",
+			Sort:       "ASC",
+			Count:      count,
+		},
+		WithCoord: true,
+		WithDist:  true,
+	}).Result()
+	if err != nil {
+		logger.Error(ctx, "Redis GeoSearch失败", logger.String("key", key), logger.ErrorField(err))
+		return nil, err
+	}
+
+	result := make([]GeoLocation, 0, len(locations))
+	for _, l := range locations {
+		result = append(result, GeoLocation{
+			Member:    l.Name,
+			Longitude: l.Longitude,
+			Latitude:  l.Latitude,
+			Dist:      l.Dist,
+		})
+	}
+	return result, nil
+}
+
 // Close 关闭Redis连接
 func Close() error {
 	if client != nil {
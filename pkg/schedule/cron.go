@@ -0,0 +1,141 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron 五段式cron表达式: 分 时 日 月 周
+// 支持 * 、数字、列表(1,3)、范围(1-5)和步长(*/2, 8-20/4),周日可写作0或7
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// field 单个字段的取值范围
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"分钟", 0, 59},
+	{"小时", 0, 23},
+	{"日", 1, 31},
+	{"月", 1, 12},
+	{"星期", 0, 7},
+}
+
+// ParseCron 解析cron表达式
+func ParseCron(expr string) (*Cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron表达式需要5个字段: %s", expr)
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+	// 周日统一为0
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &Cron{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+// Next 返回严格晚于t的下一个触发时间(精确到分钟),5年内无触发时返回零值
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 日与星期同时限定时满足其一即可,与标准cron语义一致
+func (c *Cron) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// parseField 将字段解析为位图
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			s, err := strconv.Atoi(item[i+1:])
+			if err != nil || s <= 0 {
+				return 0, fmt.Errorf("cron%s字段步长不合法: %s", f.name, item)
+			}
+			rangeExpr, step = item[:i], s
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("cron%s字段范围不合法: %s", f.name, item)
+			}
+		default:
+			v, err := strconv.Atoi(rangeExpr)
+			if err != nil {
+				return 0, fmt.Errorf("cron%s字段不合法: %s", f.name, item)
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("cron%s字段超出范围%d-%d: %s", f.name, f.min, f.max, item)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
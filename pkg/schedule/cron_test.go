@@ -0,0 +1,75 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"字段不足", "* * * *"},
+		{"字段过多", "* * * * * *"},
+		{"分钟越界", "60 * * * *"},
+		{"小时越界", "0 24 * * *"},
+		{"日为0", "0 0 0 * *"},
+		{"月越界", "0 0 1 13 *"},
+		{"星期越界", "0 0 * * 8"},
+		{"步长为0", "*/0 * * * *"},
+		{"步长非数字", "*/x * * * *"},
+		{"范围颠倒", "0 0 * * 5-1"},
+		{"范围不完整", "0 0 * * 1-"},
+		{"非数字", "a * * * *"},
+		{"列表含空项", "0 8, * * *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCron(tt.expr); err == nil {
+				t.Errorf("ParseCron(%q) err = nil, want error", tt.expr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"每15分钟", "*/15 * * * *", at(2026, 10, 16, 10, 7), at(2026, 10, 16, 10, 15)},
+		{"严格晚于当前时间", "0 8 * * *", at(2026, 10, 16, 8, 0), at(2026, 10, 17, 8, 0)},
+		{"忽略秒", "0 8 * * *", time.Date(2026, 10, 16, 7, 59, 59, 0, loc), at(2026, 10, 16, 8, 0)},
+		{"列表", "0 9,18 * * *", at(2026, 10, 16, 10, 0), at(2026, 10, 16, 18, 0)},
+		{"起点加步长", "5/20 * * * *", at(2026, 10, 16, 10, 6), at(2026, 10, 16, 10, 25)},
+		{"范围加步长", "0 8-20/4 * * *", at(2026, 10, 16, 13, 0), at(2026, 10, 16, 16, 0)},
+		{"工作日跳过周末", "30 9 * * 1-5", at(2026, 10, 16, 10, 0), at(2026, 10, 19, 9, 30)},
+		{"周日写作7", "0 10 * * 7", at(2026, 10, 16, 10, 0), at(2026, 10, 18, 10, 0)},
+		{"跨月", "0 0 1 * *", at(2026, 1, 31, 12, 0), at(2026, 2, 1, 0, 0)},
+		{"跨年", "0 0 * 1 *", at(2026, 2, 1, 0, 0), at(2027, 1, 1, 0, 0)},
+		{"闰日", "0 0 29 2 *", at(2026, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"日与星期满足其一", "0 12 1 * 0", at(2026, 10, 2, 0, 0), at(2026, 10, 4, 12, 0)},
+		{"日与星期满足其一取日", "0 12 1 * 0", at(2026, 10, 25, 13, 0), at(2026, 11, 1, 12, 0)},
+		{"星期为*时只看日", "0 12 5 * *", at(2026, 10, 2, 0, 0), at(2026, 10, 5, 12, 0)},
+		{"永不触发", "0 0 31 2 *", at(2026, 1, 1, 0, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			if got := c.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
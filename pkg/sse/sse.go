@@ -0,0 +1,80 @@
+// Package sse 基于Hertz分块响应的Server-Sent Events输出,
+// 每个事件写入后立即刷新,客户端断开后写入返回错误
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/network"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/cloudwego/hertz/pkg/protocol/http1/resp"
+)
+
+// Stream 单个SSE响应流,不支持并发写入
+type Stream struct {
+	w network.ExtWriter
+}
+
+// NewStream 设置事件流响应头并接管响应体写入,handler返回时由框架写入结束块
+func NewStream(c *app.RequestContext) *Stream {
+	c.SetStatusCode(consts.StatusOK)
+	c.Response.Header.Set("Content-Type", "text/event-stream; charset=utf-8")
+	c.Response.Header.Set("Cache-Control", "no-cache")
+	c.Response.Header.Set("Connection", "keep-alive")
+	// 关闭反向代理缓冲
+	c.Response.Header.Set("X-Accel-Buffering", "no")
+
+	w := resp.NewChunkedBodyWriter(&c.Response, c.GetWriter())
+	c.Response.HijackWriter(w)
+	return &Stream{w: w}
+}
+
+// Event 发送一个事件,id为空时不设置,data按行拆分为多个data字段
+func (s *Stream) Event(id, event, data string) error {
+	var buf bytes.Buffer
+	if id != "" {
+		buf.WriteString("id: " + id + "\n")
+	}
+	if event != "" {
+		buf.WriteString("event: " + event + "\n")
+	}
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: " + line + "\n")
+	}
+	buf.WriteByte('\n')
+	return s.write(buf.Bytes())
+}
+
+// JSON 以JSON编码数据发送事件
+func (s *Stream) JSON(id uint, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var eventID string
+	if id != 0 {
+		eventID = strconv.FormatUint(uint64(id), 10)
+	}
+	return s.Event(eventID, event, string(data))
+}
+
+// Retry 设置客户端断线重连间隔(毫秒)
+func (s *Stream) Retry(ms int) error {
+	return s.write([]byte("retry: " + strconv.Itoa(ms) + "\n\n"))
+}
+
+// Comment 发送注释行,客户端忽略,用于心跳保活
+func (s *Stream) Comment(text string) error {
+	return s.write([]byte(": " + text + "\n\n"))
+}
+
+func (s *Stream) write(p []byte) error {
+	if _, err := s.w.Write(p); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
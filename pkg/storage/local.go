@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage 本地文件系统存储
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage 创建本地文件系统存储
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// Put 写入对象,先写临时文件再重命名,避免读到不完整的文件
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get 读取对象
+func (s *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete 删除对象
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path 将对象Key转换为本地路径,拒绝越出根目录的Key
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if strings.Contains(key, "..") || cleaned == "/" {
+		return "", fmt.Errorf("非法的对象Key: %s", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
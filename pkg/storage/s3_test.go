@@ -0,0 +1,274 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKey = "AKIDTEST"
+	testSecretKey = "secret-key"
+	testRegion    = "us-east-1"
+	testBucket    = "pets"
+)
+
+// fakeS3 校验SigV4签名的内存对象存储,记录收到的请求
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	types    map[string]string
+	requests []string
+	// failStatus 非零时所有请求返回该状态码
+	failStatus int
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string][]byte), types: make(map[string]string)}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rawPath := strings.SplitN(r.RequestURI, "?", 2)[0]
+	f.requests = append(f.requests, r.Method+" "+rawPath)
+	if f.failStatus != 0 {
+		http.Error(w, "<Error><Code>InternalError</Code></Error>", f.failStatus)
+		return
+	}
+	if err := verifySignature(r, rawPath); err != nil {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + testBucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "<Error><Code>NoSuchBucket</Code></Error>", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if int64(len(body)) != r.ContentLength {
+			http.Error(w, "<Error><Code>IncompleteBody</Code></Error>", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// verifySignature 按服务端收到的请求独立计算SigV4签名并与Authorization比对
+func verifySignature(r *http.Request, rawPath string) error {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return errors.New("缺少签名")
+	}
+	fields := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		k, v, _ := strings.Cut(part, "=")
+		fields[k] = v
+	}
+	credential := strings.Split(fields["Credential"], "/")
+	if len(credential) != 5 || credential[0] != testAccessKey || credential[2] != testRegion ||
+		credential[3] != "s3" || credential[4] != "aws4_request" {
+		return errors.New("Credential不合法")
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if len(amzDate) != 16 || amzDate[:8] != credential[1] {
+		return errors.New("X-Amz-Date不合法")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		rawPath,
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	digest := sha256.Sum256([]byte(canonicalRequest))
+	scope := strings.Join(credential[1:], "/")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{credential[1], testRegion, "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	if !hmac.Equal([]byte(hex.EncodeToString(key)), []byte(fields["Signature"])) {
+		return errors.New("签名不匹配")
+	}
+	return nil
+}
+
+func newTestS3(t *testing.T, secretKey string) (*S3Storage, *fakeS3) {
+	t.Helper()
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	s, err := NewS3Storage(server.URL+"/", testRegion, testBucket, testAccessKey, secretKey)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	return s, fake
+}
+
+func TestS3StorageRoundTrip(t *testing.T) {
+	s, fake := newTestS3(t, testSecretKey)
+	ctx := context.Background()
+	data := []byte("png-bytes")
+
+	keys := []struct {
+		key     string
+		rawPath string
+	}{
+		{"avatars/1/a.png", "/pets/avatars/1/a.png"},
+		{"/pets/2/my photo+1.png", "/pets/pets/2/my%20photo%2B1.png"},
+		{"pets/3/头像.png", "/pets/pets/3/%E5%A4%B4%E5%83%8F.png"},
+	}
+	for _, tt := range keys {
+		t.Run(tt.key, func(t *testing.T) {
+			fake.mu.Lock()
+			fake.requests = nil
+			fake.mu.Unlock()
+			if err := s.Put(ctx, tt.key, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			rc, err := s.Get(ctx, tt.key)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			got, _ := io.ReadAll(rc)
+			rc.Close()
+			if !bytes.Equal(got, data) {
+				t.Errorf("Get = %q, want %q", got, data)
+			}
+			if err := s.Delete(ctx, tt.key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+
+			want := []string{"PUT " + tt.rawPath, "GET " + tt.rawPath, "DELETE " + tt.rawPath}
+			if strings.Join(fake.requests, ",") != strings.Join(want, ",") {
+				t.Errorf("requests = %v, want %v", fake.requests, want)
+			}
+		})
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("objects = %v, want empty", fake.objects)
+	}
+}
+
+func TestS3StoragePutContentType(t *testing.T) {
+	s, fake := newTestS3(t, testSecretKey)
+	if err := s.Put(context.Background(), "a.jpg", strings.NewReader("x"), 1, "image/jpeg"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := fake.types["a.jpg"]; got != "image/jpeg" {
+		t.Errorf("Content-Type = %q, want image/jpeg", got)
+	}
+}
+
+func TestS3StorageMissingObject(t *testing.T) {
+	s, _ := newTestS3(t, testSecretKey)
+	ctx := context.Background()
+	if _, err := s.Get(ctx, "missing.png"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("Get err = %v, want ErrObjectNotFound", err)
+	}
+	if err := s.Delete(ctx, "missing.png"); err != nil {
+		t.Errorf("Delete err = %v, want nil", err)
+	}
+}
+
+func TestS3StorageErrors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("签名错误", func(t *testing.T) {
+		s, _ := newTestS3(t, "wrong-secret")
+		err := s.Put(ctx, "a.png", strings.NewReader("x"), 1, "image/png")
+		if err == nil || !strings.Contains(err.Error(), "status=403") || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+			t.Errorf("Put err = %v, want 403 SignatureDoesNotMatch", err)
+		}
+	})
+
+	t.Run("服务端错误", func(t *testing.T) {
+		s, fake := newTestS3(t, testSecretKey)
+		fake.mu.Lock()
+		fake.failStatus = http.StatusInternalServerError
+		fake.mu.Unlock()
+		ops := map[string]func() error{
+			"Put": func() error { return s.Put(ctx, "a.png", strings.NewReader("x"), 1, "") },
+			"Get": func() error {
+				_, err := s.Get(ctx, "a.png")
+				return err
+			},
+			"Delete": func() error { return s.Delete(ctx, "a.png") },
+		}
+		for name, op := range ops {
+			if err := op(); err == nil || !strings.Contains(err.Error(), "status=500") {
+				t.Errorf("%s err = %v, want status=500", name, err)
+			}
+		}
+	})
+
+	t.Run("连接失败", func(t *testing.T) {
+		s, err := NewS3Storage("http://127.0.0.1:1", testRegion, testBucket, testAccessKey, testSecretKey)
+		if err != nil {
+			t.Fatalf("NewS3Storage: %v", err)
+		}
+		if err := s.Put(ctx, "a.png", strings.NewReader("x"), 1, ""); err == nil {
+			t.Error("Put err = nil, want connection error")
+		}
+	})
+}
+
+func TestNewS3StorageValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		bucket   string
+	}{
+		{"endpoint缺少host", "minio:9000", testBucket},
+		{"endpoint为空", "", testBucket},
+		{"bucket为空", "http://127.0.0.1:9000", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewS3Storage(tt.endpoint, testRegion, tt.bucket, testAccessKey, testSecretKey); err == nil {
+				t.Error("err = nil, want error")
+			}
+		})
+	}
+}
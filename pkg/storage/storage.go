@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"pet-service/config"
+)
+
+// ErrObjectNotFound 对象不存在
+var ErrObjectNotFound = errors.New("对象不存在")
+
+// Storage 文件存储接口
+type Storage interface {
+	// Put 写入对象
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象,调用方负责关闭返回的Reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象,对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
+
+// New 根据配置创建文件存储
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalDir)
+	case "s3":
+		return NewS3Storage(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	default:
+		return nil, fmt.Errorf("不支持的存储类型: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,433 @@
+// Package track 解析GPX/GeoJSON轨迹,计算距离与用时,并以gzip压缩的紧凑格式存储
+package track
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// 轨迹文件格式
+const (
+	FormatGPX     = "gpx"
+	FormatGeoJSON = "geojson"
+)
+
+// utf8BOM 部分工具导出的文件以BOM开头
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// earthRadius 地球平均半径(米)
+const earthRadius = 6371008.8
+
+var (
+	// ErrUnknownFormat 无法识别的轨迹文件
+	ErrUnknownFormat = errors.New("无法识别的轨迹格式,仅支持GPX和GeoJSON")
+	// ErrEmptyTrack 轨迹中没有有效的点
+	ErrEmptyTrack = errors.New("轨迹中没有有效的坐标点")
+	// ErrInvalidNumber 坐标或海拔为NaN或无穷大
+	ErrInvalidNumber = errors.New("轨迹包含非法数值(NaN或无穷大)")
+)
+
+// Point 轨迹点,Time可能为零值(GeoJSON通常不含时间)
+type Point struct {
+	Lat  float64
+	Lon  float64
+	Ele  float64
+	Time time.Time
+}
+
+// Track 轨迹,由一个或多个分段组成,分段之间的间隔不计入距离
+type Track struct {
+	Segments [][]Point
+}
+
+// Bounds 轨迹外接矩形
+type Bounds struct {
+	MinLat float64 `json:"min_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+// Parse 根据内容自动识别GPX或GeoJSON,返回轨迹和识别出的格式
+func Parse(data []byte) (*Track, string, error) {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	if len(trimmed) == 0 {
+		return nil, "", ErrUnknownFormat
+	}
+	switch trimmed[0] {
+	case '<':
+		t, err := ParseGPX(trimmed)
+		return t, FormatGPX, err
+	case '{':
+		t, err := ParseGeoJSON(trimmed)
+		return t, FormatGeoJSON, err
+	}
+	return nil, "", ErrUnknownFormat
+}
+
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+	Routes []struct {
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Ele  float64 `xml:"ele"`
+	Time string  `xml:"time"`
+}
+
+// ParseGPX 解析GPX 1.0/1.1的trk/trkseg/trkpt,没有航迹时退回使用rte/rtept
+func ParseGPX(data []byte) (*Track, error) {
+	var f gpxFile
+	if err := xml.Unmarshal(data, &f); err != nil {
+		return nil, ErrUnknownFormat
+	}
+	t := &Track{}
+	for _, trk := range f.Tracks {
+		for _, seg := range trk.Segments {
+			if err := t.addSegment(convertGPX(seg.Points)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(t.Segments) == 0 {
+		for _, rte := range f.Routes {
+			if err := t.addSegment(convertGPX(rte.Points)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(t.Segments) == 0 {
+		return nil, ErrEmptyTrack
+	}
+	return t, nil
+}
+
+func convertGPX(points []gpxPoint) []Point {
+	result := make([]Point, 0, len(points))
+	for _, p := range points {
+		point := Point{Lat: p.Lat, Lon: p.Lon, Ele: p.Ele}
+		if p.Time != "" {
+			if ts, err := time.Parse(time.RFC3339, p.Time); err == nil {
+				point.Time = ts
+			}
+		}
+		result = append(result, point)
+	}
+	return result
+}
+
+type geoJSONObject struct {
+	Type        string            `json:"type"`
+	Features    []*geoJSONObject  `json:"features"`
+	Geometry    *geoJSONObject    `json:"geometry"`
+	Geometries  []*geoJSONObject  `json:"geometries"`
+	Coordinates json.RawMessage   `json:"coordinates"`
+	Properties  geoJSONProperties `json:"properties"`
+}
+
+// geoJSONProperties 常见导出工具(如togeojson)把时间放在coordTimes或times中
+type geoJSONProperties struct {
+	CoordTimes json.RawMessage `json:"coordTimes"`
+	Times      json.RawMessage `json:"times"`
+}
+
+// ParseGeoJSON 解析FeatureCollection/Feature/GeometryCollection中的LineString和MultiLineString
+func ParseGeoJSON(data []byte) (*Track, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, ErrUnknownFormat
+	}
+	t := &Track{}
+	if err := t.addGeoJSON(&obj, nil); err != nil {
+		return nil, err
+	}
+	if len(t.Segments) == 0 {
+		return nil, ErrEmptyTrack
+	}
+	return t, nil
+}
+
+func (t *Track) addGeoJSON(obj *geoJSONObject, times json.RawMessage) error {
+	if obj == nil {
+		return nil
+	}
+	switch obj.Type {
+	case "FeatureCollection":
+		for _, f := range obj.Features {
+			if err := t.addGeoJSON(f, nil); err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		times := obj.Properties.CoordTimes
+		if len(times) == 0 {
+			times = obj.Properties.Times
+		}
+		return t.addGeoJSON(obj.Geometry, times)
+	case "GeometryCollection":
+		for _, g := range obj.Geometries {
+			if err := t.addGeoJSON(g, nil); err != nil {
+				return err
+			}
+		}
+	case "LineString":
+		var coords [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &coords); err != nil {
+			return ErrUnknownFormat
+		}
+		var ts []string
+		_ = json.Unmarshal(times, &ts)
+		return t.addSegment(convertCoords(coords, ts))
+	case "MultiLineString":
+		var lines [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &lines); err != nil {
+			return ErrUnknownFormat
+		}
+		var ts [][]string
+		_ = json.Unmarshal(times, &ts)
+		for i, coords := range lines {
+			var lineTimes []string
+			if i < len(ts) {
+				lineTimes = ts[i]
+			}
+			if err := t.addSegment(convertCoords(coords, lineTimes)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertCoords GeoJSON坐标顺序为[经度, 纬度, 海拔]
+func convertCoords(coords [][]float64, times []string) []Point {
+	result := make([]Point, 0, len(coords))
+	for i, c := range coords {
+		if len(c) < 2 {
+			continue
+		}
+		point := Point{Lon: c[0], Lat: c[1]}
+		if len(c) > 2 {
+			point.Ele = c[2]
+		}
+		if i < len(times) {
+			if ts, err := time.Parse(time.RFC3339, times[i]); err == nil {
+				point.Time = ts
+			}
+		}
+		result = append(result, point)
+	}
+	return result
+}
+
+// addSegment 丢弃越界坐标,少于2个点的分段无法计算距离,直接忽略
+// NaN和无穷大无法参与距离计算和JSON编码,视为文件内容非法(GPX的数值属性可以写成NaN/Inf)
+func (t *Track) addSegment(points []Point) error {
+	valid := points[:0]
+	for _, p := range points {
+		if !finite(p.Lat) || !finite(p.Lon) || !finite(p.Ele) {
+			return ErrInvalidNumber
+		}
+		if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+			continue
+		}
+		valid = append(valid, p)
+	}
+	if len(valid) >= 2 {
+		t.Segments = append(t.Segments, valid)
+	}
+	return nil
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// PointCount 轨迹点总数
+func (t *Track) PointCount() int {
+	n := 0
+	for _, seg := range t.Segments {
+		n += len(seg)
+	}
+	return n
+}
+
+// Distance 各分段相邻点的大圆距离之和(米)
+func (t *Track) Distance() float64 {
+	var total float64
+	for _, seg := range t.Segments {
+		for i := 1; i < len(seg); i++ {
+			total += Haversine(seg[i-1].Lat, seg[i-1].Lon, seg[i].Lat, seg[i].Lon)
+		}
+	}
+	return total
+}
+
+// TimeRange 第一个和最后一个带时间的点,轨迹不含时间时返回零值
+func (t *Track) TimeRange() (start, end time.Time) {
+	for _, seg := range t.Segments {
+		for _, p := range seg {
+			if p.Time.IsZero() {
+				continue
+			}
+			if start.IsZero() || p.Time.Before(start) {
+				start = p.Time
+			}
+			if p.Time.After(end) {
+				end = p.Time
+			}
+		}
+	}
+	return start, end
+}
+
+// Bounds 轨迹外接矩形
+func (t *Track) Bounds() Bounds {
+	b := Bounds{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, seg := range t.Segments {
+		for _, p := range seg {
+			b.MinLat = math.Min(b.MinLat, p.Lat)
+			b.MinLon = math.Min(b.MinLon, p.Lon)
+			b.MaxLat = math.Max(b.MaxLat, p.Lat)
+			b.MaxLon = math.Max(b.MaxLon, p.Lon)
+		}
+	}
+	return b
+}
+
+// Downsample 等间隔抽稀到约maxPoints个点,保留每个分段的首尾点
+func (t *Track) Downsample(maxPoints int) {
+	total := t.PointCount()
+	if maxPoints <= 0 || total <= maxPoints {
+		return
+	}
+	step := int(math.Ceil(float64(total) / float64(maxPoints)))
+	for i, seg := range t.Segments {
+		kept := make([]Point, 0, len(seg)/step+2)
+		for j, p := range seg {
+			if j%step == 0 || j == len(seg)-1 {
+				kept = append(kept, p)
+			}
+		}
+		t.Segments[i] = kept
+	}
+}
+
+// Haversine 两点间的大圆距离(米)
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// storedTrack 存储格式:每个点为[经度, 纬度, 海拔, Unix毫秒],时间未知时为0
+type storedTrack struct {
+	Segments [][][4]float64 `json:"s"`
+}
+
+// Encode 压缩编码轨迹,坐标保留6位小数(约0.1米)
+func Encode(t *Track) ([]byte, error) {
+	stored := storedTrack{Segments: make([][][4]float64, 0, len(t.Segments))}
+	for _, seg := range t.Segments {
+		points := make([][4]float64, 0, len(seg))
+		for _, p := range seg {
+			var ms float64
+			if !p.Time.IsZero() {
+				ms = float64(p.Time.UnixMilli())
+			}
+			points = append(points, [4]float64{round(p.Lon, 6), round(p.Lat, 6), round(p.Ele, 1), ms})
+		}
+		stored.Segments = append(stored.Segments, points)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(&stored); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode 解码Encode生成的数据
+func Decode(data []byte) (*Track, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	var stored storedTrack
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, err
+	}
+	t := &Track{Segments: make([][]Point, 0, len(stored.Segments))}
+	for _, seg := range stored.Segments {
+		points := make([]Point, 0, len(seg))
+		for _, c := range seg {
+			p := Point{Lon: c[0], Lat: c[1], Ele: c[2]}
+			if c[3] != 0 {
+				p.Time = time.UnixMilli(int64(c[3]))
+			}
+			points = append(points, p)
+		}
+		t.Segments = append(t.Segments, points)
+	}
+	return t, nil
+}
+
+// GeoJSON 输出为GeoJSON Feature,几何为MultiLineString,时间放在properties.coordTimes
+func GeoJSON(t *Track) map[string]interface{} {
+	lines := make([][][]float64, 0, len(t.Segments))
+	times := make([][]string, 0, len(t.Segments))
+	for _, seg := range t.Segments {
+		coords := make([][]float64, 0, len(seg))
+		segTimes := make([]string, 0, len(seg))
+		for _, p := range seg {
+			coords = append(coords, []float64{p.Lon, p.Lat, p.Ele})
+			ts := ""
+			if !p.Time.IsZero() {
+				ts = p.Time.UTC().Format(time.RFC3339)
+			}
+			segTimes = append(segTimes, ts)
+		}
+		lines = append(lines, coords)
+		times = append(times, segTimes)
+	}
+	return map[string]interface{}{
+		"type": "Feature",
+		"geometry": map[string]interface{}{
+			"type":        "MultiLineString",
+			"coordinates": lines,
+		},
+		"properties": map[string]interface{}{
+			"coordTimes": times,
+		},
+	}
+}
+
+func round(v float64, digits int) float64 {
+	p := math.Pow(10, float64(digits))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,204 @@
+package track
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+const gpxTrack = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test">
+  <trk><trkseg>
+    <trkpt lat="31.2300" lon="121.4700"><ele>5.0</ele><time>2026-10-18T08:00:00Z</time></trkpt>
+    <trkpt lat="31.2310" lon="121.4700"><ele>6.0</ele><time>2026-10-18T08:01:00Z</time></trkpt>
+    <trkpt lat="95" lon="121.4700"></trkpt>
+    <trkpt lat="31.2320" lon="121.4700"><time>2026-10-18T08:02:00Z</time></trkpt>
+  </trkseg><trkseg>
+    <trkpt lat="31.0" lon="121.0"></trkpt>
+  </trkseg></trk>
+</gpx>`
+
+const gpxRoute = `<gpx><rte>
+  <rtept lat="0" lon="0"></rtept>
+  <rtept lat="1" lon="0"></rtept>
+</rte></gpx>`
+
+const geoJSONCollection = `{"type":"FeatureCollection","features":[
+  {"type":"Feature","properties":{"coordTimes":["2026-10-18T08:00:00Z","2026-10-18T08:30:00Z"]},
+   "geometry":{"type":"LineString","coordinates":[[121.47,31.23,4],[121.48,31.23]]}},
+  {"type":"Feature","properties":{},
+   "geometry":{"type":"MultiLineString","coordinates":[[[0,0],[0,1]],[[10,10]],[[0,2],[0,3]]]}}
+]}`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantFormat   string
+		wantErr      error
+		wantSegments []int // 各分段点数
+	}{
+		{"GPX航迹,丢弃越界点和单点分段", gpxTrack, FormatGPX, nil, []int{3}},
+		{"GPX无航迹时使用航线", gpxRoute, FormatGPX, nil, []int{2}},
+		{"带BOM和前导空白", "\xEF\xBB\xBF \n" + gpxRoute, FormatGPX, nil, []int{2}},
+		{"GeoJSON要素集合", geoJSONCollection, FormatGeoJSON, nil, []int{2, 2, 2}},
+		{"GeoJSON单个几何", `{"type":"LineString","coordinates":[[0,0],[1,1]]}`, FormatGeoJSON, nil, []int{2}},
+		{"空内容", "  ", "", ErrUnknownFormat, nil},
+		{"非轨迹格式", "lat,lon\n1,2", "", ErrUnknownFormat, nil},
+		{"XML格式错误", "<gpx><trk>", FormatGPX, ErrUnknownFormat, nil},
+		{"JSON格式错误", `{"type":`, FormatGeoJSON, ErrUnknownFormat, nil},
+		{"坐标类型错误", `{"type":"LineString","coordinates":"x"}`, FormatGeoJSON, ErrUnknownFormat, nil},
+		{"没有有效点", `<gpx><trk><trkseg><trkpt lat="1" lon="1"/></trkseg></trk></gpx>`, FormatGPX, ErrEmptyTrack, nil},
+		{"GeoJSON没有线", `{"type":"Point","coordinates":[0,0]}`, FormatGeoJSON, ErrEmptyTrack, nil},
+		{"纬度为NaN", `<gpx><rte><rtept lat="NaN" lon="0"/><rtept lat="1" lon="0"/></rte></gpx>`, FormatGPX, ErrInvalidNumber, nil},
+		{"经度为无穷大", `<gpx><rte><rtept lat="0" lon="+Inf"/><rtept lat="1" lon="0"/></rte></gpx>`, FormatGPX, ErrInvalidNumber, nil},
+		{"海拔为无穷大", `<gpx><rte><rtept lat="0" lon="0"><ele>-Inf</ele></rtept><rtept lat="1" lon="0"/></rte></gpx>`, FormatGPX, ErrInvalidNumber, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, format, err := Parse([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", format, tt.wantFormat)
+			}
+			if err != nil {
+				return
+			}
+			if len(tr.Segments) != len(tt.wantSegments) {
+				t.Fatalf("segments = %d, want %d", len(tr.Segments), len(tt.wantSegments))
+			}
+			for i, n := range tt.wantSegments {
+				if len(tr.Segments[i]) != n {
+					t.Errorf("segment[%d] points = %d, want %d", i, len(tr.Segments[i]), n)
+				}
+			}
+		})
+	}
+}
+
+func TestGeoJSONCoordinateOrderAndTimes(t *testing.T) {
+	tr, _, err := Parse([]byte(geoJSONCollection))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	p := tr.Segments[0][0]
+	if p.Lon != 121.47 || p.Lat != 31.23 || p.Ele != 4 {
+		t.Errorf("point = %+v, want lon 121.47 lat 31.23 ele 4", p)
+	}
+	start, end := tr.TimeRange()
+	if !start.Equal(time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC)) {
+		t.Errorf("TimeRange = %s, %s", start, end)
+	}
+}
+
+func TestHaversine(t *testing.T) {
+	// 子午线上1度约为111.195公里
+	oneDegree := earthRadius * math.Pi / 180
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		want                   float64
+	}{
+		{"同一点", 31.23, 121.47, 31.23, 121.47, 0},
+		{"纬度1度", 0, 0, 1, 0, oneDegree},
+		{"赤道经度1度", 0, 0, 0, 1, oneDegree},
+		{"跨越180度经线", 0, 179.5, 0, -179.5, oneDegree},
+		{"南北极", 90, 0, -90, 0, oneDegree * 180},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Haversine(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("Haversine = %.3f, want %.3f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrackStats(t *testing.T) {
+	tr, _, err := Parse([]byte(geoJSONCollection))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	// 分段之间的间隔不计入距离
+	want := Haversine(31.23, 121.47, 31.23, 121.48) + 2*Haversine(0, 0, 1, 0)
+	if got := tr.Distance(); math.Abs(got-want) > 0.01 {
+		t.Errorf("Distance = %.3f, want %.3f", got, want)
+	}
+	if got := tr.PointCount(); got != 6 {
+		t.Errorf("PointCount = %d, want 6", got)
+	}
+	wantBounds := Bounds{MinLat: 0, MinLon: 0, MaxLat: 31.23, MaxLon: 121.48}
+	if got := tr.Bounds(); got != wantBounds {
+		t.Errorf("Bounds = %+v, want %+v", got, wantBounds)
+	}
+
+	noTime := &Track{Segments: [][]Point{{{Lat: 0, Lon: 0}, {Lat: 1, Lon: 0}}}}
+	if start, end := noTime.TimeRange(); !start.IsZero() || !end.IsZero() {
+		t.Errorf("TimeRange without times = %s, %s, want zero", start, end)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    int
+		maxPoints int
+		want      int
+	}{
+		{"未超过上限不抽稀", 10, 10, 10},
+		{"上限为0不抽稀", 10, 0, 10},
+		{"每2个取1个并保留终点", 10, 5, 6},
+		{"每4个取1个,终点额外保留", 100, 25, 26},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seg := make([]Point, tt.points)
+			for i := range seg {
+				seg[i] = Point{Lat: float64(i) / 1000}
+			}
+			tr := &Track{Segments: [][]Point{seg}}
+			tr.Downsample(tt.maxPoints)
+			got := tr.Segments[0]
+			if len(got) != tt.want {
+				t.Fatalf("points = %d, want %d", len(got), tt.want)
+			}
+			if got[0] != seg[0] || got[len(got)-1] != seg[len(seg)-1] {
+				t.Errorf("首尾点未保留: %+v ... %+v", got[0], got[len(got)-1])
+			}
+		})
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	ts := time.Date(2026, 10, 18, 8, 0, 0, 123e6, time.UTC)
+	tr := &Track{Segments: [][]Point{
+		{{Lat: 31.12345678, Lon: 121.98765432, Ele: 12.34, Time: ts}, {Lat: 31.2, Lon: 121.5}},
+		{{Lat: -10, Lon: -20, Ele: -1}, {Lat: -10.5, Lon: -20.5}},
+	}}
+	data, err := Encode(tr)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(got.Segments) != 2 || len(got.Segments[0]) != 2 || len(got.Segments[1]) != 2 {
+		t.Fatalf("segments = %+v", got.Segments)
+	}
+	p := got.Segments[0][0]
+	if p.Lat != 31.123457 || p.Lon != 121.987654 || p.Ele != 12.3 || !p.Time.Equal(ts) {
+		t.Errorf("point = %+v, want 6位小数坐标、1位小数海拔和毫秒时间", p)
+	}
+	if !got.Segments[0][1].Time.IsZero() {
+		t.Errorf("time = %s, want zero", got.Segments[0][1].Time)
+	}
+
+	if _, err := Decode([]byte("not gzip")); err == nil {
+		t.Error("Decode(invalid) err = nil, want error")
+	}
+}
@@ -0,0 +1,299 @@
+// Package websocket 基于Hertz连接劫持的最小WebSocket(RFC 6455)服务端实现,
+// 仅支持服务端角色,不支持扩展和子协议协商
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/network"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// 消息类型,与帧操作码一致
+const (
+	TextMessage   = 1
+	BinaryMessage = 2
+	CloseMessage  = 8
+	PingMessage   = 9
+	PongMessage   = 10
+)
+
+// 关闭状态码
+const (
+	CloseNormal          = 1000
+	CloseProtocolError   = 1002
+	CloseMessageTooBig   = 1009
+	closeNoStatusPayload = 1005
+)
+
+// acceptGUID 握手时与客户端key拼接计算Sec-WebSocket-Accept
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// defaultReadLimit 默认单条消息最大字节数
+const defaultReadLimit = 64 << 10
+
+// writeWait 单次写超时
+const writeWait = 10 * time.Second
+
+var (
+	// ErrBadHandshake 握手请求不合法
+	ErrBadHandshake = errors.New("websocket: 握手请求不合法")
+	// ErrClosed 连接已关闭
+	ErrClosed = errors.New("websocket: 连接已关闭")
+	// ErrReadLimit 消息超过大小限制
+	ErrReadLimit = errors.New("websocket: 消息超过大小限制")
+	// ErrProtocol 帧格式错误
+	ErrProtocol = errors.New("websocket: 帧格式错误")
+)
+
+// Upgrade 校验握手请求并写入101响应,响应发出后在劫持的连接上调用handler,handler返回后连接关闭
+func Upgrade(c *app.RequestContext, handler func(conn *Conn)) error {
+	if !c.IsGet() ||
+		!headerContains(string(c.GetHeader("Connection")), "upgrade") ||
+		!strings.EqualFold(string(c.GetHeader("Upgrade")), "websocket") ||
+		string(c.GetHeader("Sec-WebSocket-Version")) != "13" {
+		return ErrBadHandshake
+	}
+	key := string(c.GetHeader("Sec-WebSocket-Key"))
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return ErrBadHandshake
+	}
+
+	c.SetStatusCode(consts.StatusSwitchingProtocols)
+	c.Response.Header.Set("Upgrade", "websocket")
+	c.Response.Header.Set("Connection", "Upgrade")
+	c.Response.Header.Set("Sec-WebSocket-Accept", acceptKey(key))
+	c.Hijack(func(nc network.Conn) {
+		conn := newConn(nc)
+		defer conn.Close()
+		handler(conn)
+	})
+	return nil
+}
+
+// acceptKey 计算握手响应key
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContains 判断逗号分隔的请求头是否包含指定值
+func headerContains(header, value string) bool {
+	for _, v := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn WebSocket连接,读操作只能在单个goroutine中进行,写操作可并发调用
+// 超时通过network.Conn的SetReadTimeout/SetWriteTimeout设置,netpoll连接不支持net.Conn的Deadline方法
+type Conn struct {
+	conn      network.Conn
+	reader    *bufio.Reader
+	readLimit int64
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+func newConn(nc network.Conn) *Conn {
+	_ = nc.SetWriteTimeout(writeWait)
+	return &Conn{
+		conn:      nc,
+		reader:    bufio.NewReader(nc),
+		readLimit: defaultReadLimit,
+	}
+}
+
+// SetReadLimit 设置单条消息最大字节数
+func (c *Conn) SetReadLimit(limit int64) {
+	c.readLimit = limit
+}
+
+// SetIdleTimeout 设置读空闲超时,期间未收到任何数据(含pong)时读取返回超时错误,配合服务端定时ping检测断线
+func (c *Conn) SetIdleTimeout(timeout time.Duration) error {
+	return c.conn.SetReadTimeout(timeout)
+}
+
+// RemoteAddr 客户端地址
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// ReadMessage 读取一条完整的数据消息,自动合并分片、回复ping并处理关闭帧
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	var (
+		messageType int
+		message     []byte
+	)
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			if errors.Is(err, ErrReadLimit) {
+				c.writeClose(CloseMessageTooBig)
+			} else if errors.Is(err, ErrProtocol) {
+				c.writeClose(CloseProtocolError)
+			}
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case PingMessage:
+			if err := c.writeFrame(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			code := closeNoStatusPayload
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			if code == closeNoStatusPayload {
+				code = CloseNormal
+			}
+			c.writeClose(code)
+			return 0, nil, ErrClosed
+		case TextMessage, BinaryMessage:
+			if messageType != 0 {
+				c.writeClose(CloseProtocolError)
+				return 0, nil, ErrProtocol
+			}
+			messageType = opcode
+		case 0:
+			if messageType == 0 {
+				c.writeClose(CloseProtocolError)
+				return 0, nil, ErrProtocol
+			}
+		default:
+			c.writeClose(CloseProtocolError)
+			return 0, nil, ErrProtocol
+		}
+
+		if int64(len(message)+len(payload)) > c.readLimit {
+			c.writeClose(CloseMessageTooBig)
+			return 0, nil, ErrReadLimit
+		}
+		message = append(message, payload...)
+		if fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+// readFrame 读取单个帧,客户端发送的帧必须带掩码
+func (c *Conn) readFrame() (bool, int, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	opcode := int(header[0] & 0x0f)
+	if header[0]&0x70 != 0 || header[1]&0x80 == 0 {
+		return false, 0, nil, ErrProtocol
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	if opcode >= CloseMessage && (length > 125 || !fin) {
+		return false, 0, nil, ErrProtocol
+	}
+	if length < 0 || length > c.readLimit {
+		return false, 0, nil, ErrReadLimit
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage 发送一条消息,服务端帧不带掩码
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	return c.writeFrame(messageType, data)
+}
+
+// WriteJSON 以文本消息发送JSON
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(TextMessage, data)
+}
+
+func (c *Conn) writeFrame(opcode int, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|byte(opcode))
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	frame = append(frame, payload...)
+
+	_, err := c.conn.Write(frame)
+	if opcode == CloseMessage {
+		c.closed = true
+	}
+	return err
+}
+
+// writeClose 发送关闭帧,之后不再发送任何消息
+func (c *Conn) writeClose(code int) {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	_ = c.writeFrame(CloseMessage, payload)
+}
+
+// Close 发送正常关闭帧并关闭底层连接
+func (c *Conn) Close() error {
+	c.writeClose(CloseNormal)
+	return c.conn.Close()
+}
@@ -0,0 +1,283 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/network"
+)
+
+// pipeConn 将net.Pipe包装为network.Conn,Conn只使用net.Conn的读写方法
+type pipeConn struct {
+	net.Conn
+	network.Reader
+	network.Writer
+}
+
+func (pipeConn) SetReadTimeout(time.Duration) error  { return nil }
+func (pipeConn) SetWriteTimeout(time.Duration) error { return nil }
+
+// frame 测试用帧
+type frame struct {
+	opcode  int
+	payload []byte
+}
+
+// clientFrame 构造客户端帧,first为首字节(FIN、RSV与操作码),masked为false时不带掩码
+func clientFrame(first byte, payload []byte, masked bool) []byte {
+	buf := []byte{first}
+	var maskBit byte
+	if masked {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xffff:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if !masked {
+		return append(buf, payload...)
+	}
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	buf = append(buf, mask...)
+	for i, b := range payload {
+		buf = append(buf, b^mask[i%4])
+	}
+	return buf
+}
+
+// masked 带掩码的帧,fin为false时为非结束分片
+func masked(fin bool, opcode int, payload []byte) []byte {
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	return clientFrame(first, payload, true)
+}
+
+// parseServerFrames 解析服务端写出的不带掩码的帧
+func parseServerFrames(t *testing.T, data []byte) []frame {
+	t.Helper()
+	var frames []frame
+	for len(data) > 0 {
+		if len(data) < 2 || data[1]&0x80 != 0 {
+			t.Fatalf("服务端帧格式错误: % x", data)
+		}
+		opcode := int(data[0] & 0x0f)
+		length := int(data[1] & 0x7f)
+		data = data[2:]
+		switch length {
+		case 126:
+			length = int(binary.BigEndian.Uint16(data))
+			data = data[2:]
+		case 127:
+			length = int(binary.BigEndian.Uint64(data))
+			data = data[8:]
+		}
+		frames = append(frames, frame{opcode: opcode, payload: data[:length]})
+		data = data[length:]
+	}
+	return frames
+}
+
+func closePayload(code int) []byte {
+	return binary.BigEndian.AppendUint16(nil, uint16(code))
+}
+
+func TestReadMessage(t *testing.T) {
+	big := bytes.Repeat([]byte("a"), 200)
+	tests := []struct {
+		name      string
+		readLimit int64
+		input     [][]byte
+		eof       bool // 写完输入后关闭客户端
+		wantType  int
+		wantData  []byte
+		wantErr   error
+		wantReply []frame
+	}{
+		{
+			name:     "文本消息",
+			input:    [][]byte{masked(true, TextMessage, []byte("hello"))},
+			wantType: TextMessage,
+			wantData: []byte("hello"),
+		},
+		{
+			name:     "16位扩展长度",
+			input:    [][]byte{masked(true, BinaryMessage, big)},
+			wantType: BinaryMessage,
+			wantData: big,
+		},
+		{
+			name: "分片合并且中间插入ping",
+			input: [][]byte{
+				masked(false, TextMessage, []byte("he")),
+				masked(true, PingMessage, []byte("p")),
+				masked(false, 0, []byte("ll")),
+				masked(true, 0, []byte("o")),
+			},
+			wantType:  TextMessage,
+			wantData:  []byte("hello"),
+			wantReply: []frame{{PongMessage, []byte("p")}},
+		},
+		{
+			name:      "未带掩码",
+			input:     [][]byte{clientFrame(0x80|TextMessage, []byte("hi"), false)},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name:      "RSV位非零",
+			input:     [][]byte{clientFrame(0xc0|TextMessage, []byte("hi"), true)},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name:      "控制帧超过125字节",
+			input:     [][]byte{masked(true, PingMessage, big)},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name:      "控制帧分片",
+			input:     [][]byte{masked(false, PingMessage, []byte("p"))},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name:      "没有起始帧的续帧",
+			input:     [][]byte{masked(true, 0, []byte("x"))},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name: "分片未结束时开始新消息",
+			input: [][]byte{
+				masked(false, TextMessage, []byte("a")),
+				masked(true, TextMessage, []byte("b")),
+			},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name:      "未知操作码",
+			input:     [][]byte{masked(true, 3, []byte("x"))},
+			wantErr:   ErrProtocol,
+			wantReply: []frame{{CloseMessage, closePayload(CloseProtocolError)}},
+		},
+		{
+			name:      "单帧超过大小限制",
+			readLimit: 8,
+			input:     [][]byte{masked(true, TextMessage, []byte("0123456789"))},
+			wantErr:   ErrReadLimit,
+			wantReply: []frame{{CloseMessage, closePayload(CloseMessageTooBig)}},
+		},
+		{
+			name:      "分片累计超过大小限制",
+			readLimit: 8,
+			input: [][]byte{
+				masked(false, TextMessage, []byte("01234")),
+				masked(true, 0, []byte("56789")),
+			},
+			wantErr:   ErrReadLimit,
+			wantReply: []frame{{CloseMessage, closePayload(CloseMessageTooBig)}},
+		},
+		{
+			name:      "客户端关闭",
+			input:     [][]byte{masked(true, CloseMessage, closePayload(CloseNormal))},
+			wantErr:   ErrClosed,
+			wantReply: []frame{{CloseMessage, closePayload(CloseNormal)}},
+		},
+		{
+			name:      "客户端关闭不带状态码",
+			input:     [][]byte{masked(true, CloseMessage, nil)},
+			wantErr:   ErrClosed,
+			wantReply: []frame{{CloseMessage, closePayload(CloseNormal)}},
+		},
+		{
+			name:    "帧不完整",
+			input:   [][]byte{masked(true, TextMessage, []byte("hello"))[:4]},
+			eof:     true,
+			wantErr: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe()
+			conn := newConn(pipeConn{Conn: server})
+			if tt.readLimit > 0 {
+				conn.SetReadLimit(tt.readLimit)
+			}
+
+			// net.Pipe无缓冲,读写分别在独立goroutine中进行
+			go func() {
+				for _, b := range tt.input {
+					if _, err := client.Write(b); err != nil {
+						return
+					}
+				}
+				if tt.eof {
+					_ = client.Close()
+				}
+			}()
+			replyCh := make(chan []byte, 1)
+			go func() {
+				data, _ := io.ReadAll(client)
+				replyCh <- data
+			}()
+
+			messageType, data, err := conn.ReadMessage()
+			_ = server.Close()
+			reply := <-replyCh
+			_ = client.Close()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if messageType != tt.wantType || !bytes.Equal(data, tt.wantData) {
+				t.Errorf("message = (%d, %q), want (%d, %q)", messageType, data, tt.wantType, tt.wantData)
+			}
+			frames := parseServerFrames(t, reply)
+			if len(frames) != len(tt.wantReply) {
+				t.Fatalf("reply = %v, want %v", frames, tt.wantReply)
+			}
+			for i, f := range frames {
+				if f.opcode != tt.wantReply[i].opcode || !bytes.Equal(f.payload, tt.wantReply[i].payload) {
+					t.Errorf("reply[%d] = %v, want %v", i, f, tt.wantReply[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteMessageAfterClose(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	go func() { _, _ = io.Copy(io.Discard, client) }()
+
+	conn := newConn(pipeConn{Conn: server})
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := conn.WriteMessage(TextMessage, []byte("late")); !errors.Is(err, ErrClosed) {
+		t.Errorf("err = %v, want ErrClosed", err)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// RFC 6455 1.3节示例
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("acceptKey = %q", got)
+	}
+}
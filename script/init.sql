@@ -13,7 +13,9 @@ CREATE TABLE IF NOT EXISTS users (
     phone VARCHAR(20) UNIQUE COMMENT '手机号',
     nickname VARCHAR(50) COMMENT '昵称',
     avatar VARCHAR(255) COMMENT '头像',
+    avatar_file_id BIGINT UNSIGNED DEFAULT 0 COMMENT '头像文件ID',
     status TINYINT DEFAULT 1 COMMENT '状态:0禁用,1正常',
+    role VARCHAR(20) DEFAULT 'user' COMMENT '角色:user,admin,shop_manager',
     is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
     INDEX idx_username (username),
     INDEX idx_email (email),
@@ -21,7 +23,1151 @@ CREATE TABLE IF NOT EXISTS users (
     INDEX idx_status (status)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表';
 
+-- 宠物表
+CREATE TABLE IF NOT EXISTS pets (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '宠物ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    owner_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '主人用户ID,0表示由救助机构持有',
+    shelter_id BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所属救助机构ID',
+    name VARCHAR(50) NOT NULL COMMENT '名字',
+    species VARCHAR(20) NOT NULL COMMENT '物种:dog,cat等',
+    breed VARCHAR(50) COMMENT '品种',
+    gender VARCHAR(10) COMMENT '性别:male,female,unknown',
+    birth_date DATE COMMENT '出生日期',
+    color VARCHAR(30) COMMENT '毛色',
+    size VARCHAR(10) COMMENT '体型:small,medium,large',
+    avatar VARCHAR(255) COMMENT '照片',
+    description TEXT COMMENT '描述',
+    microchip VARCHAR(15) NULL COMMENT '芯片号(ISO 11784/11785)',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    UNIQUE KEY idx_microchip (microchip),
+    INDEX idx_owner_id (owner_id),
+    INDEX idx_shelter_id (shelter_id),
+    INDEX idx_species (species)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物表';
+
+-- 救助机构表
+CREATE TABLE IF NOT EXISTS shelters (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '机构ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    owner_id BIGINT UNSIGNED NOT NULL COMMENT '管理员用户ID',
+    name VARCHAR(100) NOT NULL COMMENT '机构名称',
+    city VARCHAR(50) COMMENT '城市',
+    address VARCHAR(255) COMMENT '地址',
+    phone VARCHAR(20) COMMENT '联系电话',
+    email VARCHAR(100) COMMENT '联系邮箱',
+    description TEXT COMMENT '机构介绍',
+    status TINYINT DEFAULT 1 COMMENT '状态:0禁用,1正常',
+    INDEX idx_owner_id (owner_id),
+    INDEX idx_city (city)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='救助机构表';
+
+-- 领养信息表
+CREATE TABLE IF NOT EXISTS adoption_listings (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '领养信息ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    shelter_id BIGINT UNSIGNED NOT NULL COMMENT '救助机构ID',
+    pet_id BIGINT UNSIGNED NOT NULL UNIQUE COMMENT '宠物ID',
+    title VARCHAR(100) NOT NULL COMMENT '标题',
+    description TEXT COMMENT '描述',
+    city VARCHAR(50) COMMENT '所在城市',
+    fee BIGINT DEFAULT 0 COMMENT '领养费用(分)',
+    status VARCHAR(20) DEFAULT 'open' COMMENT '状态:open,pending,adopted,closed',
+    INDEX idx_shelter_id (shelter_id),
+    INDEX idx_city (city),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='领养信息表';
+
+-- 领养申请表
+CREATE TABLE IF NOT EXISTS adoption_applications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '申请ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    listing_id BIGINT UNSIGNED NOT NULL COMMENT '领养信息ID',
+    shelter_id BIGINT UNSIGNED NOT NULL COMMENT '救助机构ID',
+    applicant_id BIGINT UNSIGNED NOT NULL COMMENT '申请人用户ID',
+    answers JSON COMMENT '问卷答案',
+    status VARCHAR(20) DEFAULT 'submitted' COMMENT '状态:submitted,screening,approved,rejected,completed',
+    reviewer_id BIGINT UNSIGNED DEFAULT 0 COMMENT '审核人用户ID',
+    review_note VARCHAR(500) COMMENT '审核备注',
+    reviewed_at DATETIME COMMENT '最近审核时间',
+    completed_at DATETIME COMMENT '完成时间',
+    INDEX idx_listing_id (listing_id),
+    INDEX idx_shelter_id (shelter_id),
+    INDEX idx_applicant_id (applicant_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='领养申请表';
+
+-- 走失/招领信息表
+CREATE TABLE IF NOT EXISTS lost_found_reports (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '信息ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '发布人用户ID',
+    pet_id BIGINT UNSIGNED DEFAULT 0 COMMENT '关联宠物ID,走失时可选',
+    type VARCHAR(10) NOT NULL COMMENT '类型:lost,found',
+    species VARCHAR(20) NOT NULL COMMENT '物种',
+    breed VARCHAR(50) COMMENT '品种',
+    color VARCHAR(30) COMMENT '毛色',
+    photos JSON COMMENT '照片',
+    latitude DECIMAL(10,7) NOT NULL COMMENT '最后出现位置纬度',
+    longitude DECIMAL(10,7) NOT NULL COMMENT '最后出现位置经度',
+    address VARCHAR(255) COMMENT '地址描述',
+    occurred_at DATETIME NOT NULL COMMENT '走失/发现时间',
+    description TEXT COMMENT '描述',
+    contact_phone VARCHAR(20) COMMENT '联系电话',
+    status VARCHAR(20) DEFAULT 'open' COMMENT '状态:open,resolved',
+    INDEX idx_user_id (user_id),
+    INDEX idx_type_status (type, status),
+    INDEX idx_species (species),
+    INDEX idx_location (latitude, longitude)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='走失/招领信息表';
+
+-- 上传文件表
+CREATE TABLE IF NOT EXISTS files (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '文件ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '上传用户ID',
+    purpose VARCHAR(20) NOT NULL COMMENT '用途:avatar,pet_photo,attachment',
+    storage_key VARCHAR(255) NOT NULL COMMENT '存储Key',
+    thumbnail_key VARCHAR(255) COMMENT '缩略图存储Key',
+    original_name VARCHAR(255) COMMENT '原始文件名',
+    content_type VARCHAR(100) COMMENT '文件类型',
+    size BIGINT DEFAULT 0 COMMENT '文件大小(字节)',
+    width INT DEFAULT 0 COMMENT '图片宽度',
+    height INT DEFAULT 0 COMMENT '图片高度',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='上传文件表';
+
+-- 宠物共享成员表
+CREATE TABLE IF NOT EXISTS pet_members (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '成员ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '成员用户ID',
+    role VARCHAR(10) NOT NULL COMMENT '角色:viewer,editor,owner',
+    invited_by BIGINT UNSIGNED COMMENT '邀请人用户ID',
+    UNIQUE KEY idx_pet_user (pet_id, user_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物共享成员表';
+
+-- 宠物共享邀请表
+CREATE TABLE IF NOT EXISTS pet_invitations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '邀请ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    inviter_id BIGINT UNSIGNED NOT NULL COMMENT '邀请人用户ID',
+    invitee_id BIGINT UNSIGNED NOT NULL COMMENT '被邀请人用户ID',
+    role VARCHAR(10) NOT NULL COMMENT '授予角色',
+    status VARCHAR(20) DEFAULT 'pending' COMMENT '状态:pending,accepted,declined,cancelled',
+    expires_at DATETIME COMMENT '过期时间',
+    responded_at DATETIME COMMENT '处理时间',
+    INDEX idx_pet_id (pet_id),
+    INDEX idx_invitee_id (invitee_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物共享邀请表';
+
+-- 宠物主人转让表
+CREATE TABLE IF NOT EXISTS pet_transfers (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '转让ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    from_user_id BIGINT UNSIGNED NOT NULL COMMENT '转出方(当前主人)',
+    to_user_id BIGINT UNSIGNED NOT NULL COMMENT '接收方',
+    from_confirmed TINYINT(1) DEFAULT 0 COMMENT '转出方已确认',
+    to_confirmed TINYINT(1) DEFAULT 0 COMMENT '接收方已确认',
+    status VARCHAR(20) DEFAULT 'pending' COMMENT '状态:pending,accepted,declined,cancelled',
+    completed_at DATETIME COMMENT '完成时间',
+    INDEX idx_pet_id (pet_id),
+    INDEX idx_from_user_id (from_user_id),
+    INDEX idx_to_user_id (to_user_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物主人转让表';
+
+-- 芯片查询审计表
+CREATE TABLE IF NOT EXISTS microchip_lookups (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '查询时间',
+    microchip VARCHAR(15) NOT NULL COMMENT '查询的芯片号',
+    pet_id BIGINT UNSIGNED DEFAULT 0 COMMENT '命中的宠物ID',
+    action VARCHAR(20) NOT NULL COMMENT '动作:lookup,message',
+    found TINYINT(1) DEFAULT 0 COMMENT '是否命中',
+    user_id BIGINT UNSIGNED DEFAULT 0 COMMENT '查询人用户ID,匿名为0',
+    client_ip VARCHAR(64) COMMENT '客户端IP',
+    user_agent VARCHAR(255) COMMENT '客户端UA',
+    INDEX idx_created_at (created_at),
+    INDEX idx_microchip (microchip),
+    INDEX idx_pet_id (pet_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='芯片查询审计表';
+
+-- 拾获留言表
+CREATE TABLE IF NOT EXISTS found_pet_messages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '留言ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    finder_name VARCHAR(50) COMMENT '拾获人称呼',
+    finder_contact VARCHAR(100) NOT NULL COMMENT '拾获人联系方式',
+    content VARCHAR(1000) NOT NULL COMMENT '留言内容',
+    location VARCHAR(255) COMMENT '发现地点',
+    is_read TINYINT(1) DEFAULT 0 COMMENT '是否已读',
+    INDEX idx_pet_id (pet_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='拾获留言表';
+
+-- 宠物公开主页表
+CREATE TABLE IF NOT EXISTS pet_profiles (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '主页ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL UNIQUE COMMENT '宠物ID',
+    slug VARCHAR(32) NOT NULL UNIQUE COMMENT '公开访问标识',
+    enabled TINYINT(1) DEFAULT 0 COMMENT '是否公开',
+    show_name TINYINT(1) DEFAULT 1 COMMENT '展示名字',
+    show_photo TINYINT(1) DEFAULT 1 COMMENT '展示照片',
+    show_medical_alert TINYINT(1) DEFAULT 0 COMMENT '展示医疗提示',
+    show_contact TINYINT(1) DEFAULT 0 COMMENT '展示主人联系方式',
+    medical_alert VARCHAR(500) COMMENT '医疗提示',
+    contact_note VARCHAR(255) COMMENT '联系说明'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物公开主页表';
+
+-- 用药计划表
+CREATE TABLE IF NOT EXISTS medication_plans (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '计划ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID',
+    drug VARCHAR(100) NOT NULL COMMENT '药品名称',
+    dose DECIMAL(10,3) NOT NULL COMMENT '单次剂量',
+    unit VARCHAR(20) NOT NULL COMMENT '剂量单位',
+    schedule_type VARCHAR(10) NOT NULL COMMENT '频率规则:interval,cron',
+    interval_hours INT DEFAULT 0 COMMENT '间隔小时数',
+    cron VARCHAR(100) COMMENT 'cron表达式(分 时 日 月 周)',
+    start_at DATETIME NOT NULL COMMENT '开始时间',
+    end_at DATETIME COMMENT '结束时间',
+    remind_before_min INT DEFAULT 0 COMMENT '提前提醒分钟数',
+    instructions VARCHAR(500) COMMENT '用药说明',
+    status VARCHAR(20) DEFAULT 'active' COMMENT '状态:active,paused,ended',
+    generated_until DATETIME NOT NULL COMMENT '已生成给药记录的截止时间',
+    INDEX idx_pet_id (pet_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用药计划表';
+
+-- 给药记录表
+CREATE TABLE IF NOT EXISTS medication_doses (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    plan_id BIGINT UNSIGNED NOT NULL COMMENT '用药计划ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    scheduled_at DATETIME NOT NULL COMMENT '计划给药时间',
+    status VARCHAR(20) DEFAULT 'scheduled' COMMENT '状态:scheduled,given,skipped,missed',
+    acted_at DATETIME COMMENT '实际给药/跳过时间',
+    acted_by BIGINT UNSIGNED DEFAULT 0 COMMENT '操作人用户ID',
+    note VARCHAR(255) COMMENT '备注',
+    reminded_at DATETIME COMMENT '提醒发送时间',
+    UNIQUE KEY idx_plan_time (plan_id, scheduled_at),
+    INDEX idx_pet_time (pet_id, scheduled_at),
+    INDEX idx_status_time (status, scheduled_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='给药记录表';
+
+-- 寄养看护人资料表
+CREATE TABLE IF NOT EXISTS sitter_profiles (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '看护人ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    user_id BIGINT UNSIGNED NOT NULL UNIQUE COMMENT '用户ID',
+    headline VARCHAR(100) NOT NULL COMMENT '标题',
+    bio TEXT COMMENT '个人介绍',
+    services JSON COMMENT '服务类型',
+    accepted_species JSON COMMENT '接受的物种',
+    price_per_night BIGINT NOT NULL DEFAULT 0 COMMENT '每晚价格(分)',
+    max_pets INT DEFAULT 1 COMMENT '单次最多接待宠物数',
+    city VARCHAR(50) COMMENT '城市',
+    service_area VARCHAR(255) COMMENT '服务区域说明',
+    latitude DECIMAL(10,7) COMMENT '纬度',
+    longitude DECIMAL(10,7) COMMENT '经度',
+    service_radius_km DECIMAL(6,2) DEFAULT 0 COMMENT '服务半径(km)',
+    status TINYINT DEFAULT 1 COMMENT '状态:0暂停接单,1接单中',
+    completed_stays INT DEFAULT 0 COMMENT '已完成寄养次数',
+    INDEX idx_city (city)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='寄养看护人资料表';
+
+-- 看护人档期表
+CREATE TABLE IF NOT EXISTS sitter_calendar_days (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    sitter_id BIGINT UNSIGNED NOT NULL COMMENT '看护人ID',
+    date DATE NOT NULL COMMENT '日期',
+    status VARCHAR(20) NOT NULL COMMENT '状态:available,unavailable,booked',
+    booking_id BIGINT UNSIGNED DEFAULT 0 COMMENT '占用的预约ID',
+    UNIQUE KEY idx_sitter_date (sitter_id, date),
+    INDEX idx_booking_id (booking_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='看护人档期表';
+
+-- 寄养预约表
+CREATE TABLE IF NOT EXISTS sitter_bookings (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '预约ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    sitter_id BIGINT UNSIGNED NOT NULL COMMENT '看护人ID',
+    sitter_user_id BIGINT UNSIGNED NOT NULL COMMENT '看护人用户ID',
+    owner_id BIGINT UNSIGNED NOT NULL COMMENT '宠物主人用户ID',
+    pet_ids JSON COMMENT '寄养宠物ID',
+    service VARCHAR(20) NOT NULL COMMENT '服务类型',
+    start_date DATE NOT NULL COMMENT '开始日期',
+    end_date DATE NOT NULL COMMENT '结束日期(不含)',
+    nights INT NOT NULL COMMENT '晚数',
+    total_price BIGINT NOT NULL COMMENT '总价(分)',
+    message VARCHAR(500) COMMENT '留言',
+    status VARCHAR(20) DEFAULT 'requested' COMMENT '状态:requested,accepted,declined,cancelled,completed',
+    decline_reason VARCHAR(255) COMMENT '拒绝/取消原因',
+    responded_at DATETIME COMMENT '看护人响应时间',
+    completed_at DATETIME COMMENT '完成时间',
+    INDEX idx_sitter_status (sitter_id, status),
+    INDEX idx_sitter_user_id (sitter_user_id),
+    INDEX idx_owner_id (owner_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='寄养预约表';
+
+-- 评价表
+CREATE TABLE IF NOT EXISTS reviews (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '评价ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    source_type VARCHAR(30) NOT NULL COMMENT '来源类型',
+    source_id BIGINT UNSIGNED NOT NULL COMMENT '来源ID(预约/寄养)',
+    target_type VARCHAR(20) NOT NULL COMMENT '被评价对象类型:sitter,clinic,vet',
+    target_id BIGINT UNSIGNED NOT NULL COMMENT '被评价对象ID',
+    reviewer_id BIGINT UNSIGNED NOT NULL COMMENT '评价人用户ID',
+    rating TINYINT NOT NULL COMMENT '评分1-5',
+    content TEXT COMMENT '评价内容',
+    photo_file_ids JSON COMMENT '照片文件ID',
+    reply VARCHAR(1000) COMMENT '服务方回复',
+    replied_by BIGINT UNSIGNED DEFAULT 0 COMMENT '回复人用户ID',
+    replied_at DATETIME COMMENT '回复时间',
+    flag_count INT DEFAULT 0 COMMENT '被举报次数',
+    status VARCHAR(20) DEFAULT 'visible' COMMENT '状态:visible,hidden',
+    UNIQUE KEY idx_source (source_type, source_id),
+    INDEX idx_target (target_type, target_id, status),
+    INDEX idx_reviewer_id (reviewer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价表';
+
+-- 评价举报表
+CREATE TABLE IF NOT EXISTS review_flags (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '举报ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    review_id BIGINT UNSIGNED NOT NULL COMMENT '评价ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '举报人用户ID',
+    reason VARCHAR(255) NOT NULL COMMENT '举报原因',
+    UNIQUE KEY idx_review_user (review_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评价举报表';
+
+-- 评分汇总表
+CREATE TABLE IF NOT EXISTS rating_summaries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    target_type VARCHAR(20) NOT NULL COMMENT '被评价对象类型',
+    target_id BIGINT UNSIGNED NOT NULL COMMENT '被评价对象ID',
+    `count` BIGINT DEFAULT 0 COMMENT '评价数',
+    `sum` BIGINT DEFAULT 0 COMMENT '评分总和',
+    star1 BIGINT DEFAULT 0 COMMENT '1星数',
+    star2 BIGINT DEFAULT 0 COMMENT '2星数',
+    star3 BIGINT DEFAULT 0 COMMENT '3星数',
+    star4 BIGINT DEFAULT 0 COMMENT '4星数',
+    star5 BIGINT DEFAULT 0 COMMENT '5星数',
+    UNIQUE KEY idx_target (target_type, target_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='评分汇总表';
+
+-- 商品分类表
+CREATE TABLE IF NOT EXISTS product_categories (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '分类ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    parent_id BIGINT UNSIGNED DEFAULT 0 COMMENT '上级分类ID,0为一级分类',
+    name VARCHAR(50) NOT NULL COMMENT '分类名称',
+    sort INT DEFAULT 0 COMMENT '排序,越小越靠前',
+    status TINYINT DEFAULT 1 COMMENT '状态:0停用,1启用',
+    INDEX idx_parent_id (parent_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='商品分类表';
+
+-- 商品表
+CREATE TABLE IF NOT EXISTS products (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '商品ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    category_id BIGINT UNSIGNED NOT NULL COMMENT '分类ID',
+    name VARCHAR(100) NOT NULL COMMENT '商品名称',
+    description TEXT COMMENT '商品描述',
+    image_file_ids JSON COMMENT '图片文件ID',
+    status VARCHAR(20) DEFAULT 'draft' COMMENT '状态:draft,on_sale,off_sale',
+    min_price BIGINT DEFAULT 0 COMMENT '在售规格最低价(分)',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID',
+    INDEX idx_category_id (category_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='商品表';
+
+-- 商品规格表
+CREATE TABLE IF NOT EXISTS product_skus (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '规格ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    product_id BIGINT UNSIGNED NOT NULL COMMENT '商品ID',
+    sku_code VARCHAR(64) NOT NULL UNIQUE COMMENT '规格编码',
+    name VARCHAR(100) NOT NULL COMMENT '规格名称',
+    attributes JSON COMMENT '规格属性',
+    price BIGINT NOT NULL COMMENT '售价(分)',
+    stock INT DEFAULT 0 COMMENT '可售库存',
+    status TINYINT DEFAULT 1 COMMENT '状态:0停售,1在售',
+    INDEX idx_product_id (product_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='商品规格表';
+
+-- 库存预占表
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '预占ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    items JSON COMMENT '预占明细',
+    total_amount BIGINT NOT NULL COMMENT '商品总额(分)',
+    status VARCHAR(20) DEFAULT 'reserved' COMMENT '状态:reserved,consumed,released',
+    expires_at DATETIME NOT NULL COMMENT '过期时间',
+    INDEX idx_user_id (user_id),
+    INDEX idx_status_expires (status, expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='库存预占表';
+
+-- 订单表
+CREATE TABLE IF NOT EXISTS orders (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '订单ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    order_no VARCHAR(32) NOT NULL UNIQUE COMMENT '订单号',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '下单用户ID',
+    type VARCHAR(30) NOT NULL COMMENT '订单类型:shop,sitter_booking,donation',
+    source_id BIGINT UNSIGNED NOT NULL COMMENT '来源ID(库存预占/寄养预约/捐款)',
+    subject VARCHAR(255) NOT NULL COMMENT '订单标题',
+    currency VARCHAR(3) DEFAULT 'CNY' COMMENT '币种',
+    total_amount BIGINT NOT NULL COMMENT '商品总额(分)',
+    discount_amount BIGINT DEFAULT 0 COMMENT '优惠金额(分),优惠券与积分抵扣之和',
+    coupon_id BIGINT UNSIGNED DEFAULT 0 COMMENT '使用的优惠券ID',
+    coupon_code VARCHAR(32) COMMENT '使用的券码',
+    coupon_discount BIGINT DEFAULT 0 COMMENT '优惠券抵扣(分)',
+    points_used BIGINT DEFAULT 0 COMMENT '使用积分',
+    points_discount BIGINT DEFAULT 0 COMMENT '积分抵扣(分)',
+    pay_amount BIGINT NOT NULL COMMENT '应付金额(分)',
+    refunded_amount BIGINT DEFAULT 0 COMMENT '已退款金额(分),含处理中的退款',
+    status VARCHAR(20) DEFAULT 'pending' COMMENT '状态:pending,paid,fulfilled,refunded,cancelled',
+    gateway VARCHAR(20) COMMENT '支付网关',
+    trade_no VARCHAR(64) COMMENT '网关交易号',
+    expires_at DATETIME NOT NULL COMMENT '支付截止时间',
+    paid_at DATETIME COMMENT '支付时间',
+    fulfilled_at DATETIME COMMENT '完成时间',
+    cancelled_at DATETIME COMMENT '取消时间',
+    cancel_reason VARCHAR(255) COMMENT '取消原因',
+    INDEX idx_user_status (user_id, status),
+    INDEX idx_source (type, source_id),
+    INDEX idx_status_expires (status, expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='订单表';
+
+-- 订单明细表
+CREATE TABLE IF NOT EXISTS order_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '明细ID',
+    order_id BIGINT UNSIGNED NOT NULL COMMENT '订单ID',
+    sku_id BIGINT UNSIGNED DEFAULT 0 COMMENT '商品规格ID',
+    product_id BIGINT UNSIGNED DEFAULT 0 COMMENT '商品ID',
+    name VARCHAR(255) NOT NULL COMMENT '名称',
+    sku_name VARCHAR(100) COMMENT '规格名称',
+    price BIGINT NOT NULL COMMENT '单价(分)',
+    quantity INT NOT NULL COMMENT '数量',
+    amount BIGINT NOT NULL COMMENT '小计(分)',
+    INDEX idx_order_id (order_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='订单明细表';
+
+-- 退款表
+CREATE TABLE IF NOT EXISTS refunds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '退款ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    refund_no VARCHAR(32) NOT NULL UNIQUE COMMENT '退款单号',
+    order_id BIGINT UNSIGNED NOT NULL COMMENT '订单ID',
+    amount BIGINT NOT NULL COMMENT '退款金额(分)',
+    reason VARCHAR(255) COMMENT '退款原因',
+    status VARCHAR(20) DEFAULT 'pending' COMMENT '状态:pending,succeeded,failed',
+    gateway_refund_no VARCHAR(64) COMMENT '网关退款单号',
+    fail_reason VARCHAR(255) COMMENT '失败原因',
+    operator_id BIGINT UNSIGNED DEFAULT 0 COMMENT '操作人用户ID,0为系统',
+    succeeded_at DATETIME COMMENT '退款成功时间',
+    INDEX idx_order_id (order_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='退款表';
+
+-- 支付回调记录表
+CREATE TABLE IF NOT EXISTS payment_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    gateway VARCHAR(20) NOT NULL COMMENT '支付网关',
+    event_id VARCHAR(64) NOT NULL COMMENT '网关事件ID',
+    type VARCHAR(30) NOT NULL COMMENT '事件类型',
+    order_no VARCHAR(32) COMMENT '订单号',
+    payload TEXT COMMENT '回调原文',
+    UNIQUE KEY idx_gateway_event (gateway, event_id),
+    INDEX idx_order_no (order_no)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='支付回调记录表';
+
+-- 优惠券表
+CREATE TABLE IF NOT EXISTS coupons (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '优惠券ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    code VARCHAR(32) NOT NULL UNIQUE COMMENT '券码',
+    name VARCHAR(100) NOT NULL COMMENT '名称',
+    type VARCHAR(20) NOT NULL COMMENT '类型:percent,fixed',
+    value BIGINT NOT NULL COMMENT '折扣百分比或立减金额(分)',
+    max_discount BIGINT DEFAULT 0 COMMENT '折扣券最高优惠(分),0为不限',
+    min_spend BIGINT DEFAULT 0 COMMENT '最低消费(分)',
+    order_types JSON COMMENT '适用订单类型,空为全部',
+    starts_at DATETIME NOT NULL COMMENT '生效时间',
+    ends_at DATETIME NOT NULL COMMENT '失效时间',
+    total_limit INT DEFAULT 0 COMMENT '总使用次数上限,0为不限',
+    per_user_limit INT DEFAULT 1 COMMENT '每人使用次数上限,0为不限',
+    used_count INT DEFAULT 0 COMMENT '已使用次数',
+    status VARCHAR(20) DEFAULT 'active' COMMENT '状态:active,disabled',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='优惠券表';
+
+-- 优惠券使用记录表
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    coupon_id BIGINT UNSIGNED NOT NULL COMMENT '优惠券ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    order_id BIGINT UNSIGNED NOT NULL UNIQUE COMMENT '订单ID',
+    discount BIGINT NOT NULL COMMENT '优惠金额(分)',
+    status VARCHAR(20) DEFAULT 'used' COMMENT '状态:used,released',
+    INDEX idx_coupon_user (coupon_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='优惠券使用记录表';
+
+-- 用户积分账户表
+CREATE TABLE IF NOT EXISTS user_points (
+    user_id BIGINT UNSIGNED PRIMARY KEY COMMENT '用户ID',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    balance BIGINT DEFAULT 0 COMMENT '可用积分',
+    total_earned BIGINT DEFAULT 0 COMMENT '累计获得积分'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户积分账户表';
+
+-- 积分流水表
+CREATE TABLE IF NOT EXISTS points_transactions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '流水ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    type VARCHAR(30) NOT NULL COMMENT '流水类型:earn_order,earn_appointment,redeem,return,revoke',
+    source_type VARCHAR(30) NOT NULL COMMENT '来源类型:order,appointment',
+    source_id BIGINT UNSIGNED NOT NULL COMMENT '来源ID',
+    delta BIGINT NOT NULL COMMENT '变动积分,正数增加负数减少',
+    balance_after BIGINT NOT NULL COMMENT '变动后余额',
+    remark VARCHAR(255) COMMENT '说明',
+    UNIQUE KEY idx_type_source (type, source_type, source_id),
+    INDEX idx_user_created (user_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='积分流水表';
+
+-- 动态表
+CREATE TABLE IF NOT EXISTS posts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '动态ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '发布人用户ID',
+    content TEXT COMMENT '正文',
+    photo_file_ids JSON COMMENT '照片文件ID',
+    like_count BIGINT DEFAULT 0 COMMENT '点赞数,由Redis计数定期回写',
+    comment_count BIGINT DEFAULT 0 COMMENT '评论数,由Redis计数定期回写',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_user_deleted (user_id, is_deleted)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='动态表';
+
+-- 动态标记宠物表
+CREATE TABLE IF NOT EXISTS post_pets (
+    post_id BIGINT UNSIGNED NOT NULL COMMENT '动态ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    PRIMARY KEY (post_id, pet_id),
+    INDEX idx_pet_id (pet_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='动态标记宠物表';
+
+-- 动态评论表
+CREATE TABLE IF NOT EXISTS post_comments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '评论ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    post_id BIGINT UNSIGNED NOT NULL COMMENT '动态ID',
+    parent_id BIGINT UNSIGNED DEFAULT 0 COMMENT '所属一级评论ID,0为一级评论',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '评论人用户ID',
+    reply_to_user_id BIGINT UNSIGNED DEFAULT 0 COMMENT '被回复的用户ID',
+    content VARCHAR(1000) NOT NULL COMMENT '内容',
+    reply_count BIGINT DEFAULT 0 COMMENT '回复数',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_post_parent (post_id, parent_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='动态评论表';
+
+-- 动态点赞表
+CREATE TABLE IF NOT EXISTS post_likes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '点赞ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    post_id BIGINT UNSIGNED NOT NULL COMMENT '动态ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '点赞用户ID',
+    UNIQUE KEY idx_post_user (post_id, user_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='动态点赞表';
+
+-- 用户关注表
+CREATE TABLE IF NOT EXISTS user_follows (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '关注ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    follower_id BIGINT UNSIGNED NOT NULL COMMENT '关注者用户ID',
+    followee_id BIGINT UNSIGNED NOT NULL COMMENT '被关注用户ID',
+    UNIQUE KEY idx_follower_followee (follower_id, followee_id),
+    INDEX idx_followee_id (followee_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户关注表';
+
+-- 宠物关注表
+CREATE TABLE IF NOT EXISTS pet_follows (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '关注ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '关注者用户ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '被关注宠物ID',
+    UNIQUE KEY idx_user_pet (user_id, pet_id),
+    INDEX idx_pet_id (pet_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物关注表';
+
+-- 用户拉黑表
+CREATE TABLE IF NOT EXISTS user_blocks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '拉黑ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    blocker_id BIGINT UNSIGNED NOT NULL COMMENT '拉黑者用户ID',
+    blocked_id BIGINT UNSIGNED NOT NULL COMMENT '被拉黑用户ID',
+    UNIQUE KEY idx_blocker_blocked (blocker_id, blocked_id),
+    INDEX idx_blocked_id (blocked_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户拉黑表';
+
+-- 诊所表
+CREATE TABLE IF NOT EXISTS clinics (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '诊所ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    name VARCHAR(100) NOT NULL COMMENT '诊所名称',
+    phone VARCHAR(20) COMMENT '联系电话',
+    city VARCHAR(50) COMMENT '城市',
+    address VARCHAR(255) COMMENT '地址',
+    latitude DECIMAL(10,7) COMMENT '纬度',
+    longitude DECIMAL(10,7) COMMENT '经度',
+    description TEXT COMMENT '简介',
+    opening_hours VARCHAR(255) COMMENT '营业时间说明',
+    status TINYINT DEFAULT 1 COMMENT '状态:0停业,1营业',
+    INDEX idx_city (city)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='诊所表';
+
+-- 诊所人员表
+CREATE TABLE IF NOT EXISTS clinic_staff (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '人员ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    clinic_id BIGINT UNSIGNED NOT NULL COMMENT '诊所ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    role VARCHAR(20) NOT NULL COMMENT '角色:manager,vet,staff',
+    title VARCHAR(50) COMMENT '职称',
+    UNIQUE KEY idx_clinic_user (clinic_id, user_id),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='诊所人员表';
+
+-- 诊所咨询会话表
+CREATE TABLE IF NOT EXISTS conversations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '会话ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    clinic_id BIGINT UNSIGNED NOT NULL COMMENT '诊所ID',
+    owner_id BIGINT UNSIGNED NOT NULL COMMENT '宠物主人用户ID',
+    pet_id BIGINT UNSIGNED DEFAULT 0 COMMENT '最近咨询的宠物ID',
+    last_message_id BIGINT UNSIGNED DEFAULT 0 COMMENT '最后一条消息ID',
+    last_message_at DATETIME COMMENT '最后消息时间',
+    last_message_preview VARCHAR(100) COMMENT '最后消息摘要',
+    owner_unread INT DEFAULT 0 COMMENT '主人未读数',
+    clinic_unread INT DEFAULT 0 COMMENT '诊所未读数',
+    owner_read_id BIGINT UNSIGNED DEFAULT 0 COMMENT '主人已读到的消息ID',
+    clinic_read_id BIGINT UNSIGNED DEFAULT 0 COMMENT '诊所已读到的消息ID',
+    UNIQUE KEY idx_clinic_owner (clinic_id, owner_id),
+    INDEX idx_owner_id (owner_id),
+    INDEX idx_last_message_at (last_message_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='诊所咨询会话表';
+
+-- 会话消息表
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '消息ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    conversation_id BIGINT UNSIGNED NOT NULL COMMENT '会话ID',
+    sender_id BIGINT UNSIGNED NOT NULL COMMENT '发送人用户ID',
+    sender_side VARCHAR(20) NOT NULL COMMENT '发送方:owner,clinic',
+    type VARCHAR(20) DEFAULT 'text' COMMENT '类型:text,image',
+    content TEXT COMMENT '文本内容',
+    file_id BIGINT UNSIGNED DEFAULT 0 COMMENT '图片文件ID',
+    INDEX idx_conversation_id (conversation_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='会话消息表';
+
+-- 站内通知表
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '通知ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '接收用户ID',
+    type VARCHAR(50) NOT NULL COMMENT '通知类型',
+    title VARCHAR(100) NOT NULL COMMENT '标题',
+    content VARCHAR(500) COMMENT '内容',
+    payload JSON COMMENT '业务数据,用于客户端跳转',
+    is_read TINYINT DEFAULT 0 COMMENT '是否已读',
+    read_at DATETIME COMMENT '阅读时间',
+    INDEX idx_user_read (user_id, is_read)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='站内通知表';
+
+-- 通知偏好表
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '偏好ID',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    type VARCHAR(50) NOT NULL COMMENT '通知类型',
+    channels JSON COMMENT '开启的渠道,为空表示不接收',
+    UNIQUE KEY idx_user_type (user_id, type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='通知偏好表';
+
+-- 社区活动表
+CREATE TABLE IF NOT EXISTS events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '活动ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    organizer_id BIGINT UNSIGNED NOT NULL COMMENT '组织者用户ID',
+    title VARCHAR(100) NOT NULL COMMENT '标题',
+    description TEXT COMMENT '活动说明',
+    city VARCHAR(50) COMMENT '城市',
+    location VARCHAR(255) NOT NULL COMMENT '活动地点',
+    latitude DECIMAL(10,7) COMMENT '纬度',
+    longitude DECIMAL(10,7) COMMENT '经度',
+    start_at DATETIME NOT NULL COMMENT '开始时间',
+    end_at DATETIME NOT NULL COMMENT '结束时间',
+    capacity INT NOT NULL COMMENT '名额',
+    allowed_species JSON COMMENT '允许携带的物种,为空表示不限',
+    remind_before_min INT DEFAULT 0 COMMENT '开始前多少分钟提醒,0表示不提醒',
+    status VARCHAR(20) DEFAULT 'published' COMMENT '状态:published,cancelled',
+    attendee_count INT DEFAULT 0 COMMENT '已报名人数',
+    waitlist_count INT DEFAULT 0 COMMENT '候补人数',
+    reminded_at DATETIME COMMENT '开始前提醒发送时间',
+    INDEX idx_organizer_id (organizer_id),
+    INDEX idx_city (city),
+    INDEX idx_start_at (start_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='社区活动表';
+
+-- 活动报名表
+CREATE TABLE IF NOT EXISTS event_rsvps (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '报名ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    event_id BIGINT UNSIGNED NOT NULL COMMENT '活动ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '用户ID',
+    status VARCHAR(20) NOT NULL COMMENT '状态:going,waitlisted,cancelled',
+    pet_ids JSON COMMENT '携带的宠物',
+    note VARCHAR(200) COMMENT '备注',
+    joined_at DATETIME COMMENT '报名或进入候补的时间,决定递补顺序',
+    UNIQUE KEY idx_event_user (event_id, user_id),
+    INDEX idx_event_status_joined (event_id, status, joined_at),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='活动报名表';
+
+-- 就诊记录表
+CREATE TABLE IF NOT EXISTS medical_records (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '就诊记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    recorded_by BIGINT UNSIGNED COMMENT '记录人用户ID',
+    type VARCHAR(20) NOT NULL COMMENT '类型:checkup,vaccination,illness,surgery,dental,other',
+    visit_date DATE NOT NULL COMMENT '就诊日期',
+    clinic_name VARCHAR(100) COMMENT '就诊机构',
+    vet_name VARCHAR(50) COMMENT '兽医',
+    diagnosis VARCHAR(500) COMMENT '诊断',
+    treatment TEXT COMMENT '治疗方案',
+    notes TEXT COMMENT '备注',
+    cost BIGINT DEFAULT 0 COMMENT '费用(分)',
+    invoice_no VARCHAR(64) COMMENT '发票号',
+    vaccine_name VARCHAR(100) COMMENT '疫苗名称,仅疫苗接种记录',
+    batch_no VARCHAR(50) COMMENT '疫苗批号',
+    valid_until DATE COMMENT '免疫有效期至',
+    attachment_file_ids JSON COMMENT '附件文件ID(病历、发票等)',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_pet_visit (pet_id, visit_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='就诊记录表';
+
+-- 宠物保单表
+CREATE TABLE IF NOT EXISTS insurance_policies (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '保单ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID',
+    provider VARCHAR(100) NOT NULL COMMENT '保险公司',
+    policy_number VARCHAR(64) NOT NULL COMMENT '保单号',
+    coverage VARCHAR(500) COMMENT '保障范围',
+    coverage_limit BIGINT DEFAULT 0 COMMENT '保额(分),0表示不限',
+    deductible BIGINT DEFAULT 0 COMMENT '免赔额(分)',
+    reimbursement_rate INT COMMENT '赔付比例(%)',
+    start_date DATE NOT NULL COMMENT '生效日期',
+    end_date DATE NOT NULL COMMENT '到期日期',
+    notes TEXT COMMENT '备注',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    UNIQUE KEY uk_pet_policy_no (pet_id, policy_number)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物保单表';
+
+-- 保险理赔表
+CREATE TABLE IF NOT EXISTS insurance_claims (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '理赔ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    policy_id BIGINT UNSIGNED NOT NULL COMMENT '保单ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID',
+    medical_record_id BIGINT UNSIGNED DEFAULT 0 COMMENT '关联就诊记录ID,0表示未关联',
+    invoice_no VARCHAR(64) COMMENT '发票号',
+    treatment_date DATE NOT NULL COMMENT '治疗日期',
+    description VARCHAR(500) COMMENT '理赔说明',
+    claimed_amount BIGINT NOT NULL COMMENT '申请金额(分)',
+    approved_amount BIGINT DEFAULT 0 COMMENT '核准金额(分)',
+    paid_amount BIGINT DEFAULT 0 COMMENT '实际赔付金额(分)',
+    status VARCHAR(20) DEFAULT 'draft' COMMENT '状态:draft,submitted,in_review,approved,denied,paid,withdrawn',
+    status_note VARCHAR(500) COMMENT '最近一次状态说明,如拒赔原因',
+    submitted_at DATETIME COMMENT '提交时间',
+    decided_at DATETIME COMMENT '核准或拒赔时间',
+    paid_at DATETIME COMMENT '赔付时间',
+    attachment_file_ids JSON COMMENT '附件文件ID(发票、病历等)',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_policy_id (policy_id),
+    INDEX idx_pet_id (pet_id),
+    INDEX idx_medical_record_id (medical_record_id),
+    INDEX idx_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='保险理赔表';
+
+-- 理赔状态历史表
+CREATE TABLE IF NOT EXISTS insurance_claim_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '历史ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    claim_id BIGINT UNSIGNED NOT NULL COMMENT '理赔ID',
+    user_id BIGINT UNSIGNED COMMENT '操作人用户ID',
+    from_status VARCHAR(20) COMMENT '原状态',
+    to_status VARCHAR(20) NOT NULL COMMENT '新状态',
+    amount BIGINT DEFAULT 0 COMMENT '核准或赔付金额(分)',
+    note VARCHAR(500) COMMENT '说明',
+    INDEX idx_claim_id (claim_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='理赔状态历史表';
+
+-- 签发文档表
+CREATE TABLE IF NOT EXISTS issued_documents (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '文档ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '签发时间',
+    code VARCHAR(16) NOT NULL COMMENT '验证码',
+    kind VARCHAR(30) NOT NULL COMMENT '文档类型:vaccination_certificate,order_invoice,appointment_invoice,donation_receipt',
+    subject_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID、订单ID、预约ID或捐款ID',
+    digest CHAR(64) NOT NULL COMMENT '内容摘要SHA-256',
+    issued_by BIGINT UNSIGNED COMMENT '签发时的下载用户ID',
+    title VARCHAR(100) NOT NULL COMMENT '文档标题',
+    summary JSON COMMENT '可公开核验的文档内容摘要',
+    UNIQUE KEY idx_code (code),
+    INDEX idx_subject_digest (kind, subject_id, digest)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='签发文档表';
+
+-- 运动记录表
+CREATE TABLE IF NOT EXISTS activities (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '活动ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    recorded_by BIGINT UNSIGNED COMMENT '记录人用户ID',
+    type VARCHAR(20) NOT NULL COMMENT '类型:walk,run,hike,play,swim,other',
+    title VARCHAR(100) COMMENT '标题',
+    started_at DATETIME NOT NULL COMMENT '开始时间',
+    duration_sec INT NOT NULL COMMENT '时长(秒)',
+    distance_m DECIMAL(10,1) DEFAULT 0 COMMENT '距离(米)',
+    pace_sec_per_km INT DEFAULT 0 COMMENT '配速(秒/公里),无距离时为0',
+    calories INT DEFAULT 0 COMMENT '估算消耗(千卡),缺少体重时为0',
+    source VARCHAR(10) DEFAULT 'manual' COMMENT '来源:manual,gpx,geojson',
+    has_track BOOLEAN DEFAULT FALSE COMMENT '是否有轨迹',
+    notes VARCHAR(500) COMMENT '备注',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_pet_started (pet_id, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='运动记录表';
+
+-- 活动轨迹表,轨迹数据较大,与运动记录分开存放
+CREATE TABLE IF NOT EXISTS activity_tracks (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '轨迹ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    activity_id BIGINT UNSIGNED NOT NULL COMMENT '活动ID',
+    point_count INT COMMENT '存储的轨迹点数',
+    min_lat DECIMAL(10,7) COMMENT '外接矩形',
+    min_lon DECIMAL(10,7),
+    max_lat DECIMAL(10,7),
+    max_lon DECIMAL(10,7),
+    data MEDIUMBLOB NOT NULL COMMENT 'gzip压缩的轨迹点',
+    UNIQUE KEY idx_activity_id (activity_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='活动轨迹表';
+
+-- 运动目标表
+CREATE TABLE IF NOT EXISTS activity_goals (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '目标ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    period VARCHAR(10) NOT NULL COMMENT '周期:week,month',
+    metric VARCHAR(20) NOT NULL COMMENT '指标:distance,duration,count',
+    target DECIMAL(12,1) NOT NULL COMMENT '目标值,距离为米,时长为秒',
+    UNIQUE KEY idx_pet_period_metric (pet_id, period, metric)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='运动目标表';
+
+-- 饮食计划表
+CREATE TABLE IF NOT EXISTS diet_plans (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '计划ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID',
+    product_id BIGINT UNSIGNED COMMENT '关联的商城商品ID',
+    food_name VARCHAR(100) NOT NULL COMMENT '食物名称',
+    kcal_per_kg INT NOT NULL COMMENT '食物代谢能(千卡/千克)',
+    portion_grams DECIMAL(8,1) NOT NULL COMMENT '每餐份量(克)',
+    meals_per_day INT NOT NULL COMMENT '每日餐数',
+    meal_times JSON COMMENT '喂食时间HH:MM',
+    activity_level VARCHAR(20) NOT NULL COMMENT '活动水平:weight_loss,low,normal,high,working',
+    neutered BOOLEAN DEFAULT FALSE COMMENT '是否绝育',
+    target_kcal INT DEFAULT 0 COMMENT '兽医指定的每日目标热量,0表示按MER计算',
+    start_date DATE NOT NULL COMMENT '开始日期',
+    end_date DATE COMMENT '结束日期,为空表示进行中',
+    notes VARCHAR(500) COMMENT '备注',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_pet_start (pet_id, start_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='饮食计划表';
+
+-- 喂食记录表
+CREATE TABLE IF NOT EXISTS feeding_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    plan_id BIGINT UNSIGNED COMMENT '所属饮食计划ID',
+    fed_by BIGINT UNSIGNED COMMENT '喂食人用户ID',
+    fed_at DATETIME NOT NULL COMMENT '喂食时间',
+    food_name VARCHAR(100) NOT NULL COMMENT '食物名称',
+    grams DECIMAL(8,1) NOT NULL COMMENT '份量(克)',
+    kcal INT NOT NULL COMMENT '热量(千卡)',
+    notes VARCHAR(255) COMMENT '备注',
+    INDEX idx_pet_fed (pet_id, fed_at),
+    INDEX idx_plan_id (plan_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='喂食记录表';
+
+-- 诊所服务表
+CREATE TABLE IF NOT EXISTS clinic_services (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '服务ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    clinic_id BIGINT UNSIGNED NOT NULL COMMENT '诊所ID',
+    category VARCHAR(20) NOT NULL COMMENT '类别:grooming,bath,nail_trim,dental,checkup,other',
+    name VARCHAR(100) NOT NULL COMMENT '服务名称',
+    description VARCHAR(500) COMMENT '服务说明',
+    status TINYINT NOT NULL COMMENT '状态:0下架,1上架',
+    is_deleted TINYINT DEFAULT 0 COMMENT '是否删除:0否,1是',
+    INDEX idx_clinic_id (clinic_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='诊所服务表';
+
+-- 诊所服务价格表,按宠物体型区分时长与价格
+CREATE TABLE IF NOT EXISTS clinic_service_prices (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '价格ID',
+    service_id BIGINT UNSIGNED NOT NULL COMMENT '服务ID',
+    size VARCHAR(10) NOT NULL COMMENT '体型:small,medium,large',
+    duration_min INT NOT NULL COMMENT '时长(分钟)',
+    price BIGINT NOT NULL COMMENT '价格(分)',
+    UNIQUE KEY idx_service_size (service_id, size)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='诊所服务价格表';
+
+-- 门诊预约表
+CREATE TABLE IF NOT EXISTS appointments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '预约ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    appointment_no VARCHAR(32) NOT NULL COMMENT '预约编号',
+    clinic_id BIGINT UNSIGNED NOT NULL COMMENT '诊所ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '预约人用户ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    pet_size VARCHAR(10) NOT NULL COMMENT '计价体型',
+    start_at DATETIME NOT NULL COMMENT '开始时间',
+    end_at DATETIME NOT NULL COMMENT '结束时间',
+    duration_min INT NOT NULL COMMENT '时长(分钟)',
+    total_price BIGINT NOT NULL COMMENT '总价(分)',
+    status VARCHAR(20) NOT NULL COMMENT '状态:requested,confirmed,declined,cancelled,completed,no_show',
+    notes VARCHAR(500) COMMENT '预约备注',
+    status_note VARCHAR(255) COMMENT '拒绝/取消原因',
+    confirmed_at DATETIME COMMENT '确认时间',
+    completed_at DATETIME COMMENT '完成时间',
+    vet_id BIGINT UNSIGNED DEFAULT 0 COMMENT '接诊兽医用户ID,完成时记录',
+    UNIQUE KEY idx_appointment_no (appointment_no),
+    INDEX idx_clinic_start (clinic_id, start_at),
+    INDEX idx_user_id (user_id),
+    INDEX idx_pet_id (pet_id),
+    INDEX idx_status (status),
+    INDEX idx_vet_id (vet_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='门诊预约表';
+
+-- 预约服务明细表,保存预约时的服务名称、时长和价格快照
+CREATE TABLE IF NOT EXISTS appointment_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '明细ID',
+    appointment_id BIGINT UNSIGNED NOT NULL COMMENT '预约ID',
+    service_id BIGINT UNSIGNED NOT NULL COMMENT '服务ID',
+    category VARCHAR(20) COMMENT '服务类别',
+    name VARCHAR(100) NOT NULL COMMENT '服务名称',
+    duration_min INT NOT NULL COMMENT '时长(分钟)',
+    price BIGINT NOT NULL COMMENT '价格(分)',
+    INDEX idx_appointment_id (appointment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='预约服务明细表';
+
+-- 募捐活动表,已筹金额和捐款笔数在捐款到账时增量更新
+CREATE TABLE IF NOT EXISTS donation_campaigns (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '活动ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    shelter_id BIGINT UNSIGNED NOT NULL COMMENT '救助机构ID',
+    created_by BIGINT UNSIGNED COMMENT '创建人用户ID',
+    title VARCHAR(100) NOT NULL COMMENT '活动标题',
+    description TEXT COMMENT '活动介绍与资金用途',
+    goal_amount BIGINT NOT NULL COMMENT '目标金额(分)',
+    raised_amount BIGINT DEFAULT 0 COMMENT '已筹金额(分)',
+    donation_count INT DEFAULT 0 COMMENT '到账捐款笔数',
+    deadline DATETIME NOT NULL COMMENT '截止时间',
+    status VARCHAR(20) NOT NULL COMMENT '状态:active,closed',
+    closed_at DATETIME COMMENT '提前结束时间',
+    INDEX idx_shelter_id (shelter_id),
+    INDEX idx_status_deadline (status, deadline)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='募捐活动表';
+
+-- 捐款表,通过订单支付,到账后计入活动总额
+CREATE TABLE IF NOT EXISTS donations (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '捐款ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    donation_no VARCHAR(32) NOT NULL COMMENT '捐款编号,即收据号',
+    campaign_id BIGINT UNSIGNED NOT NULL COMMENT '募捐活动ID',
+    shelter_id BIGINT UNSIGNED NOT NULL COMMENT '救助机构ID',
+    user_id BIGINT UNSIGNED NOT NULL COMMENT '捐款人用户ID',
+    donor_name VARCHAR(50) COMMENT '捐款人名称快照',
+    anonymous TINYINT(1) DEFAULT 0 COMMENT '是否匿名',
+    amount BIGINT NOT NULL COMMENT '捐款金额(分)',
+    currency VARCHAR(3) NOT NULL COMMENT '币种',
+    message VARCHAR(255) COMMENT '留言',
+    status VARCHAR(20) NOT NULL COMMENT '状态:pending,paid',
+    order_id BIGINT UNSIGNED DEFAULT 0 COMMENT '支付成功的订单ID',
+    paid_at DATETIME COMMENT '到账时间',
+    UNIQUE KEY idx_donation_no (donation_no),
+    INDEX idx_campaign_status (campaign_id, status),
+    INDEX idx_shelter_paid (shelter_id, paid_at),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='捐款表';
+
+-- 处方表,签发后内容不可修改,配药时扣减剩余续配次数
+CREATE TABLE IF NOT EXISTS prescriptions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '处方ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    prescription_no VARCHAR(32) NOT NULL COMMENT '处方编号',
+    clinic_id BIGINT UNSIGNED NOT NULL COMMENT '开方诊所ID',
+    vet_id BIGINT UNSIGNED NOT NULL COMMENT '开方兽医用户ID',
+    vet_name VARCHAR(50) COMMENT '开方兽医名称快照',
+    medical_record_id BIGINT UNSIGNED NOT NULL COMMENT '就诊记录ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    drug VARCHAR(100) NOT NULL COMMENT '药品名称',
+    strength VARCHAR(50) NOT NULL COMMENT '规格,如50mg',
+    quantity DECIMAL(10,2) NOT NULL COMMENT '每次配药数量',
+    unit VARCHAR(20) NOT NULL COMMENT '数量单位,如片、ml',
+    refills INT DEFAULT 0 COMMENT '首次配药之外可续配次数',
+    refills_remaining INT DEFAULT 0 COMMENT '剩余续配次数',
+    fill_count INT DEFAULT 0 COMMENT '已配药次数',
+    instructions VARCHAR(500) COMMENT '用法用量',
+    expires_at DATE NOT NULL COMMENT '有效期至(含当天)',
+    status VARCHAR(20) NOT NULL COMMENT '状态:draft,signed,revoked',
+    signed_at DATETIME COMMENT '签发时间',
+    digest CHAR(64) COMMENT '签发内容摘要SHA-256',
+    revoked_at DATETIME COMMENT '作废时间',
+    revoke_reason VARCHAR(255) COMMENT '作废原因',
+    UNIQUE KEY idx_prescription_no (prescription_no),
+    INDEX idx_clinic_status (clinic_id, status),
+    INDEX idx_vet_id (vet_id),
+    INDEX idx_medical_record_id (medical_record_id),
+    INDEX idx_pet_id (pet_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='处方表';
+
+-- 配药申请表,首次配药和续配都通过申请处理
+CREATE TABLE IF NOT EXISTS prescription_refills (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '申请ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '提交时间',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    prescription_id BIGINT UNSIGNED NOT NULL COMMENT '处方ID',
+    clinic_id BIGINT UNSIGNED NOT NULL COMMENT '开方诊所ID',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    requested_by BIGINT UNSIGNED NOT NULL COMMENT '申请人用户ID',
+    channel VARCHAR(10) NOT NULL COMMENT '配药渠道:clinic,shop',
+    note VARCHAR(255) COMMENT '申请备注',
+    status VARCHAR(20) NOT NULL COMMENT '状态:pending,fulfilled,declined,cancelled',
+    fill_no INT DEFAULT 0 COMMENT '第几次配药,1为首次',
+    handled_by BIGINT UNSIGNED DEFAULT 0 COMMENT '处理人用户ID',
+    handled_at DATETIME COMMENT '处理时间',
+    handle_note VARCHAR(255) COMMENT '处理说明或拒绝原因',
+    INDEX idx_prescription_id (prescription_id),
+    INDEX idx_channel_clinic_status (channel, clinic_id, status),
+    INDEX idx_requested_by (requested_by)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='配药申请表';
+
+-- 品种目录表
+CREATE TABLE IF NOT EXISTS breeds (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '品种ID',
+    species VARCHAR(20) NOT NULL COMMENT '物种',
+    name VARCHAR(50) NOT NULL COMMENT '品种名称',
+    size VARCHAR(10) COMMENT '体型:small,medium,large',
+    min_weight_kg DECIMAL(6,2) COMMENT '成年体重下限(kg)',
+    max_weight_kg DECIMAL(6,2) COMMENT '成年体重上限(kg)',
+    UNIQUE KEY idx_species_name (species, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='品种目录表';
+
+-- 宠物健康指标表
+CREATE TABLE IF NOT EXISTS pet_measurements (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY COMMENT '记录ID',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    pet_id BIGINT UNSIGNED NOT NULL COMMENT '宠物ID',
+    metric VARCHAR(30) NOT NULL COMMENT '指标类型',
+    value DECIMAL(10,3) NOT NULL COMMENT '测量值',
+    unit VARCHAR(20) COMMENT '单位',
+    measured_at DATETIME NOT NULL COMMENT '测量时间',
+    note VARCHAR(255) COMMENT '备注',
+    recorded_by BIGINT UNSIGNED COMMENT '记录人用户ID',
+    INDEX idx_pet_metric_time (pet_id, metric, measured_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='宠物健康指标表';
+
 -- 插入测试数据
-INSERT INTO users (username, password, email, phone, nickname, avatar, status) VALUES
-('admin', '123456', 'admin@example.com', '13800138000', '管理员', 'https://example.com/avatar/admin.png', 1),
-('testuser', '123456', 'test@example.com', '13800138001', '测试用户', 'https://example.com/avatar/test.png', 1);
+INSERT INTO users (username, password, email, phone, nickname, avatar, status, role) VALUES
+('admin', '123456', 'admin@example.com', '13800138000', '管理员', 'https://example.com/avatar/admin.png', 1, 'admin'),
+('testuser', '123456', 'test@example.com', '13800138001', '测试用户', 'https://example.com/avatar/test.png', 1, 'user');
+
+INSERT INTO breeds (species, name, size, min_weight_kg, max_weight_kg) VALUES
+('dog', '柯基', 'small', 10.00, 14.00),
+('dog', '柴犬', 'small', 8.00, 11.00),
+('dog', '泰迪', 'small', 2.00, 4.00),
+('dog', '比熊', 'small', 3.00, 6.00),
+('dog', '边境牧羊犬', 'medium', 14.00, 20.00),
+('dog', '金毛', 'large', 25.00, 34.00),
+('dog', '拉布拉多', 'large', 25.00, 36.00),
+('dog', '哈士奇', 'large', 16.00, 27.00),
+('cat', '中华田园猫', 'small', 3.00, 6.00),
+('cat', '英国短毛猫', 'medium', 4.00, 8.00),
+('cat', '美国短毛猫', 'medium', 3.50, 7.00),
+('cat', '布偶猫', 'large', 4.50, 9.00),
+('cat', '暹罗猫', 'small', 2.50, 5.50);